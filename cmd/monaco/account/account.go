@@ -0,0 +1,201 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/account"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+)
+
+type deployOptions struct {
+	manifestFromEnv bool
+	accounts        []string
+	projects        []string
+	dryRun          bool
+}
+
+type downloadOptions struct {
+	manifestFromEnv bool
+	accounts        []string
+	outputFolder    string
+}
+
+// deploy loads the resources of all (specified) account projects and deploys them to all (specified) accounts.
+func deploy(ctx context.Context, fs afero.Fs, manifestPath string, opts deployOptions) error {
+	m, absManifestPath, err := loadManifest(fs, manifestPath, opts.manifestFromEnv)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := selectAccounts(m.Accounts, opts.accounts)
+	if err != nil {
+		return err
+	}
+
+	resources, err := loadResources(fs, filepath.Dir(absManifestPath), m.AccountProjects, opts.projects)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		log.Info("Validation of %d policies, %d groups and %d users finished without errors", len(resources.Policies), len(resources.Groups), len(resources.Users))
+		return nil
+	}
+
+	var errs []error
+	for _, a := range accounts {
+		log.Info("Deploying to account %q", a.Name)
+		errs = append(errs, account.Deploy(ctx, newClient(ctx, a), resources)...)
+	}
+
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return errors.New("errors during account deployment")
+	}
+	log.Info("Account deployment finished without errors")
+	return nil
+}
+
+// download writes the resources of all (specified) accounts to '<output-folder>/<account>'.
+func download(ctx context.Context, fs afero.Fs, manifestPath string, opts downloadOptions) error {
+	m, _, err := loadManifest(fs, manifestPath, opts.manifestFromEnv)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := selectAccounts(m.Accounts, opts.accounts)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range accounts {
+		log.Info("Downloading account %q", a.Name)
+		resources, err := account.Download(ctx, newClient(ctx, a))
+		if err != nil {
+			return fmt.Errorf("failed to download account %q: %w", a.Name, err)
+		}
+
+		path := filepath.Join(opts.outputFolder, a.Name)
+		if err := account.WriteResources(fs, path, resources); err != nil {
+			return fmt.Errorf("failed to write account %q: %w", a.Name, err)
+		}
+		log.Info("Downloaded %d policies, %d groups and %d users of account %q to %q", len(resources.Policies), len(resources.Groups), len(resources.Users), a.Name, path)
+	}
+	return nil
+}
+
+func loadManifest(fs afero.Fs, manifestPath string, fromEnv bool) (manifest.Manifest, string, error) {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return manifest.Manifest{}, "", fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, errs := manifest.LoadManifest(&manifest.LoaderContext{
+		Fs:           fs,
+		ManifestPath: absManifestPath,
+		FromEnv:      fromEnv,
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return manifest.Manifest{}, "", errors.New("error while loading manifest")
+	}
+	return m, absManifestPath, nil
+}
+
+// selectAccounts returns the accounts of the given names, or all accounts if no names are given, sorted by name.
+func selectAccounts(accounts manifest.Accounts, names []string) ([]manifest.AccountDefinition, error) {
+	if len(accounts) == 0 {
+		return nil, errors.New("no accounts defined in manifest")
+	}
+
+	if len(names) == 0 {
+		names = maps.Keys(accounts)
+	}
+	sort.Strings(names)
+
+	result := make([]manifest.AccountDefinition, 0, len(names))
+	for _, name := range names {
+		a, found := accounts[name]
+		if !found {
+			return nil, fmt.Errorf("account %q is not defined in manifest", name)
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+// loadResources loads and merges the resources of the account projects of the given names, or of all account projects
+// if no names are given.
+func loadResources(fs afero.Fs, workingDir string, projects manifest.ProjectDefinitionByProjectID, names []string) (account.Resources, error) {
+	if len(projects) == 0 {
+		return account.Resources{}, errors.New("no account projects defined in manifest")
+	}
+
+	if len(names) == 0 {
+		names = maps.Keys(projects)
+	}
+	sort.Strings(names)
+
+	var result account.Resources
+	for _, name := range names {
+		p, found := projects[name]
+		if !found {
+			return account.Resources{}, fmt.Errorf("account project %q is not defined in manifest", name)
+		}
+
+		r, errs := account.LoadResources(fs, filepath.Join(workingDir, p.Path))
+		if len(errs) > 0 {
+			errutils.PrintErrors(errs)
+			return account.Resources{}, fmt.Errorf("error while loading account project %q", name)
+		}
+
+		result.Policies = append(result.Policies, r.Policies...)
+		result.Groups = append(result.Groups, r.Groups...)
+		result.Users = append(result.Users, r.Users...)
+	}
+
+	if errs := account.Validate(result); len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return account.Resources{}, errors.New("invalid account resources")
+	}
+	return result, nil
+}
+
+func newClient(ctx context.Context, a manifest.AccountDefinition) account.Client {
+	credentials := cmdutils.OAuthCredentials(a.OAuth)
+	if len(credentials.Scopes) == 0 {
+		credentials.Scopes = account.OAuthScopes
+	}
+	httpClient := client.NewOAuthClient(ctx, credentials)
+
+	apiURL := ""
+	if a.ApiURL != nil {
+		apiURL = a.ApiURL.Value
+	}
+	return account.NewClient(httpClient, apiURL, a.AccountUUID)
+}
@@ -0,0 +1,110 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+// GetAccountCommand returns the command group managing the users, groups and policies of Dynatrace accounts.
+func GetAccountCommand(fs afero.Fs) *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account",
+		Short: "Manage users, groups and policies of Dynatrace accounts",
+		Long: `Manage users, groups and policies of Dynatrace accounts
+
+  Accounts are defined in the 'accounts' section of the manifest, their resources in projects of type 'account'.`,
+	}
+
+	accountCmd.AddCommand(getDeployCommand(fs))
+	accountCmd.AddCommand(getDownloadCommand(fs))
+
+	return accountCmd
+}
+
+func getDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
+	var opts deployOptions
+	var timeout time.Duration
+
+	deployCmd = &cobra.Command{
+		Use:               "deploy [<manifest.yaml>]",
+		Short:             "Deploy the users, groups and policies of all account projects to the accounts",
+		Example:           "monaco account deploy manifest.yaml -a my-account",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, opts.manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return deploy(ctx, fs, manifestName, opts)
+		},
+	}
+
+	deployCmd.Flags().StringSliceVarP(&opts.accounts, "account", "a", []string{}, "Account(s) defined in the manifest to deploy to. If not set, all accounts are deployed to")
+	deployCmd.Flags().StringSliceVarP(&opts.projects, "project", "p", []string{}, "Account project(s) to deploy. If not set, all account projects are deployed")
+	deployCmd.Flags().BoolVarP(&opts.dryRun, "dry-run", "d", false, "Only validate the account resources instead of deploying them")
+	cmdutils.AddTimeoutFlag(deployCmd, &timeout)
+	cmdutils.AddManifestFromEnvFlag(deployCmd, &opts.manifestFromEnv)
+
+	return deployCmd
+}
+
+func getDownloadCommand(fs afero.Fs) (downloadCmd *cobra.Command) {
+	var opts downloadOptions
+	var timeout time.Duration
+
+	downloadCmd = &cobra.Command{
+		Use:   "download [<manifest.yaml>]",
+		Short: "Download the users, groups and policies of accounts",
+		Long: `Download the users, groups and policies of accounts
+
+  The resources of each account are written to '<output-folder>/<account>/account.yaml'.
+  To deploy them, add the directory as project of type 'account' to the manifest.`,
+		Example:           "monaco account download manifest.yaml -a my-account -o accounts",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, opts.manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return download(ctx, fs, manifestName, opts)
+		},
+	}
+
+	downloadCmd.Flags().StringSliceVarP(&opts.accounts, "account", "a", []string{}, "Account(s) defined in the manifest to download. If not set, all accounts are downloaded")
+	downloadCmd.Flags().StringVarP(&opts.outputFolder, "output-folder", "o", "accounts", "Folder to write the account resources to")
+	cmdutils.AddTimeoutFlag(downloadCmd, &timeout)
+	cmdutils.AddManifestFromEnvFlag(downloadCmd, &opts.manifestFromEnv)
+
+	return downloadCmd
+}
@@ -0,0 +1,130 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// writeArchive packs all files found below root in srcFs into a gzip compressed tar archive at archivePath in fs.
+// Paths inside the archive are relative to root and always use '/' as separator.
+func writeArchive(srcFs afero.Fs, root string, fs afero.Fs, archivePath string) (err error) {
+	if err := fs.MkdirAll(filepath.Dir(archivePath), 0777); err != nil {
+		return err
+	}
+
+	f, err := fs.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive %q: %w", archivePath, err)
+	}
+	defer func() {
+		err = errors.Join(err, f.Close())
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	walkErr := afero.Walk(srcFs, root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+
+		content, err := afero.ReadFile(srcFs, p)
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    filepath.ToSlash(rel),
+			Mode:    0664,
+			Size:    int64(len(content)),
+			ModTime: info.ModTime(),
+		}); err != nil {
+			return err
+		}
+		_, err = tw.Write(content)
+		return err
+	})
+	if walkErr != nil {
+		return fmt.Errorf("failed to write archive %q: %w", archivePath, walkErr)
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readArchive extracts a gzip compressed tar archive written by writeArchive into a new in-memory filesystem.
+func readArchive(fs afero.Fs, archivePath string) (afero.Fs, error) {
+	f, err := fs.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %q: %w", archivePath, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %q: %w", archivePath, err)
+	}
+
+	memFs := afero.NewMemMapFs()
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive %q: %w", archivePath, err)
+		}
+
+		name := path.Clean(h.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("archive %q contains invalid path %q", archivePath, h.Name)
+		}
+
+		target := filepath.FromSlash(name)
+		if err := memFs.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %q from archive %q: %w", h.Name, archivePath, err)
+		}
+		if err := afero.WriteFile(memFs, target, content, 0664); err != nil {
+			return nil, err
+		}
+	}
+
+	return memFs, nil
+}
@@ -0,0 +1,140 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/entities"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/settings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// configsProject is the name of the project holding classic configs and settings inside a backup archive
+	configsProject = "configs"
+	// entitiesProject is the name of the project holding entities inside a backup archive
+	entitiesProject = "entities"
+	// entitiesFolder is the folder entities are written to inside a backup archive, as they are not deployable
+	entitiesFolder = "entities"
+)
+
+type backupOptions struct {
+	manifestFile    string
+	environmentName string
+	outputFolder    string
+	withEntities    bool
+}
+
+func backup(ctx context.Context, fs afero.Fs, opts backupOptions) error {
+	m, err := cmdutils.LoadManifest(fs, opts.manifestFile, cmdutils.ManifestOptions{Environments: []string{opts.environmentName}})
+	if err != nil {
+		return err
+	}
+
+	env, found := m.Environments[opts.environmentName]
+	if !found {
+		return fmt.Errorf("environment %q was not available in manifest %q", opts.environmentName, opts.manifestFile)
+	}
+
+	if ok := cmdutils.VerifyEnvironmentGeneration(ctx, manifest.Environments{env.Name: env}, m.Offline); !ok {
+		return fmt.Errorf("unable to verify Dynatrace environment generation")
+	}
+
+	c, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	if err != nil {
+		return err
+	}
+	c = client.LimitClientParallelRequests(c, environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey))
+
+	serverVersion, err := cmdutils.ServerVersion(ctx, env.URL.Value, env.Auth)
+	if err != nil {
+		log.Warn("Unable to determine server version of environment %q: %v", env.Name, err)
+		serverVersion = version.UnknownVersion
+	}
+
+	memFs := afero.NewMemMapFs()
+
+	log.Info("Backing up configurations of environment %q", env.Name)
+	configs := downloadConfigs(ctx, c)
+	if err := writeProject(memFs, ".", env, serverVersion, configs, configsProject); err != nil {
+		return err
+	}
+
+	if opts.withEntities {
+		log.Info("Backing up entities of environment %q", env.Name)
+		if err := writeProject(memFs, entitiesFolder, env, serverVersion, entities.DownloadAll(ctx, c, entitiesProject), entitiesProject); err != nil {
+			return err
+		}
+	}
+
+	archivePath := filepath.Join(opts.outputFolder, archiveName(env.Name, time.Now()))
+	if err := writeArchive(memFs, ".", fs, archivePath); err != nil {
+		return err
+	}
+
+	log.Info("Backup of %d configurations written to %q", sumConfigs(configs), archivePath)
+	return nil
+}
+
+func downloadConfigs(ctx context.Context, c client.Client) project.ConfigsPerType {
+	apis := api.NewAPIs().Filter(func(a api.API) bool {
+		return a.SkipDownload || a.DeprecatedBy != ""
+	})
+
+	configs := classic.DownloadAllConfigs(ctx, apis, c, configsProject)
+	maps.Copy(configs, settings.DownloadAll(ctx, c, configsProject))
+
+	return download.ResolveDependencies(configs)
+}
+
+func writeProject(fs afero.Fs, outputFolder string, env manifest.EnvironmentDefinition, serverVersion version.Version, configs project.ConfigsPerType, projectName string) error {
+	return download.WriteToDisk(fs, download.WriterContext{
+		EnvironmentUrl:         env.URL.Value,
+		ProjectToWrite:         download.CreateProjectData(configs, projectName),
+		Auth:                   env.Auth,
+		OutputFolder:           outputFolder,
+		ForceOverwriteManifest: true,
+		WriteSnapshot:          true,
+		ServerVersion:          serverVersion,
+	})
+}
+
+func archiveName(environmentName string, t time.Time) string {
+	return fmt.Sprintf("backup_%s_%s.tar.gz", environmentName, t.Format("2006-01-02-150405"))
+}
+
+func sumConfigs(configs project.ConfigsPerType) int {
+	sum := 0
+	for _, c := range configs {
+		sum += len(c)
+	}
+	return sum
+}
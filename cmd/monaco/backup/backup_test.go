@@ -0,0 +1,173 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var testEnv = manifest.EnvironmentDefinition{
+	Name:  "target",
+	URL:   manifest.URLDefinition{Type: manifest.ValueURLType, Value: "https://target.dynatrace.com"},
+	Group: "default",
+	Auth:  manifest.Auth{Token: manifest.AuthSecret{Name: "TOKEN", Value: "secret"}},
+}
+
+func backedUpConfigs() project.ConfigsPerType {
+	return project.ConfigsPerType{
+		"alerting-profile": {
+			{
+				Type:       config.ClassicApiType{Api: "alerting-profile"},
+				Template:   template.NewDownloadTemplate("profile-id", "profile", `{"name": "{{.name}}"}`),
+				Coordinate: coordinate.Coordinate{Project: configsProject, Type: "alerting-profile", ConfigId: "profile-id"},
+				Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "profile"}},
+			},
+		},
+		"builtin:tags.auto-tagging": {
+			{
+				Type:       config.SettingsType{SchemaId: "builtin:tags.auto-tagging", SchemaVersion: "1.0"},
+				Template:   template.NewDownloadTemplate("tag-id", "tag-id", `{"name": "tag"}`),
+				Coordinate: coordinate.Coordinate{Project: configsProject, Type: "builtin:tags.auto-tagging", ConfigId: "tag-id"},
+				Parameters: config.Parameters{
+					config.NameParameter:  &value.ValueParameter{Value: "tag-id"},
+					config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+				},
+				OriginObjectId: "tag-object-id",
+			},
+		},
+	}
+}
+
+func writeTestBackup(t *testing.T, fs afero.Fs, archivePath string) {
+	memFs := afero.NewMemMapFs()
+	err := writeProject(memFs, ".", testEnv, version.Version{Major: 1, Minor: 270}, backedUpConfigs(), configsProject)
+	assert.NoError(t, err)
+
+	err = writeArchive(memFs, ".", fs, archivePath)
+	assert.NoError(t, err)
+}
+
+func TestBackupArchiveCanBeRestored(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	archivePath := "backups/" + archiveName("source", time.Date(2023, 5, 4, 10, 15, 0, 0, time.UTC))
+	assert.Equal(t, "backups/backup_source_2023-05-04-101500.tar.gz", archivePath)
+
+	writeTestBackup(t, fs, archivePath)
+
+	archiveFs, err := readArchive(fs, archivePath)
+	assert.NoError(t, err)
+
+	exists, err := afero.Exists(archiveFs, "configs/"+download.SnapshotFileName)
+	assert.NoError(t, err)
+	assert.True(t, exists, "expected backup to contain a snapshot")
+
+	configs, err := loadBackup(archiveFs, testEnv)
+	assert.NoError(t, err)
+	assert.Len(t, configs, 2)
+	for _, c := range configs {
+		assert.Equal(t, testEnv.Name, c.Environment)
+		assert.Equal(t, configsProject, c.Coordinate.Project)
+	}
+
+	assert.Len(t, filterConfigs(configs, []string{"alerting-profile"}, nil), 1)
+	assert.Len(t, filterConfigs(configs, nil, []string{"builtin:tags.auto-tagging"}), 1)
+	assert.Len(t, filterConfigs(configs, []string{"dashboard"}, nil), 0)
+	assert.Len(t, filterConfigs(configs, nil, nil), 2)
+}
+
+func TestWithDependencies(t *testing.T) {
+	tag := coordinate.Coordinate{Project: configsProject, Type: "builtin:tags.auto-tagging", ConfigId: "tag"}
+	zone := coordinate.Coordinate{Project: configsProject, Type: "management-zone", ConfigId: "zone"}
+	profile := coordinate.Coordinate{Project: configsProject, Type: "alerting-profile", ConfigId: "profile"}
+	unrelated := coordinate.Coordinate{Project: configsProject, Type: "management-zone", ConfigId: "unrelated"}
+
+	configs := []config.Config{
+		{Coordinate: tag, Type: config.SettingsType{SchemaId: "builtin:tags.auto-tagging"}},
+		{Coordinate: zone, Type: config.ClassicApiType{Api: "management-zone"}, Parameters: config.Parameters{"tag": reference.NewWithCoordinate(tag, "name")}},
+		{Coordinate: unrelated, Type: config.ClassicApiType{Api: "management-zone"}},
+		{Coordinate: profile, Type: config.ClassicApiType{Api: "alerting-profile"}, Parameters: config.Parameters{"zone": reference.NewWithCoordinate(zone, "id")}},
+	}
+
+	selected := filterConfigs(configs, []string{"alerting-profile"}, nil)
+	assert.Len(t, selected, 1)
+
+	var restored []coordinate.Coordinate
+	for _, c := range withDependencies(configs, selected) {
+		restored = append(restored, c.Coordinate)
+	}
+	assert.Equal(t, []coordinate.Coordinate{tag, zone, profile}, restored, "transitive dependencies are restored in deployment order")
+}
+
+func TestReadArchiveFailsForMissingArchive(t *testing.T) {
+	_, err := readArchive(afero.NewMemMapFs(), "does-not-exist.tar.gz")
+	assert.Error(t, err)
+}
+
+func TestFindConfigsCreatedAfterBackup(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()["alerting-profile"]).Return([]client.Value{
+		{Id: "1", Name: "profile"},
+		{Id: "2", Name: "new profile"},
+		{Id: "3", Name: "another new profile"},
+	}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:tags.auto-tagging", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		var result []client.DownloadSettingsObject
+		for _, o := range []client.DownloadSettingsObject{
+			{SchemaId: "builtin:tags.auto-tagging", ObjectId: "tag-object-id"},
+			{SchemaId: "builtin:tags.auto-tagging", ObjectId: "restored-object-id", ExternalId: idutils.GenerateExternalID("builtin:tags.auto-tagging", "tag-id")},
+			{SchemaId: "builtin:tags.auto-tagging", ObjectId: "new-object-id", ExternalId: "created-elsewhere"},
+		} {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+
+	var restored []config.Config
+	for _, cfgs := range backedUpConfigs() {
+		restored = append(restored, cfgs...)
+	}
+
+	entries, err := findConfigsCreatedAfterBackup(context.TODO(), c, restored)
+	assert.NoError(t, err)
+	assert.Equal(t, []delete.DeletePointer{
+		{Type: "alerting-profile", ConfigId: "another new profile"},
+		{Type: "alerting-profile", ConfigId: "new profile"},
+		{Type: "builtin:tags.auto-tagging", ConfigId: "new-object-id"},
+	}, entries)
+}
@@ -0,0 +1,131 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+func GetBackupCommand(fs afero.Fs) (backupCmd *cobra.Command) {
+	var opts backupOptions
+	var timeout time.Duration
+
+	backupCmd = &cobra.Command{
+		Use:   "backup <manifest.yaml>",
+		Short: "Back up all configurations of an environment into a timestamped archive",
+		Long: `Back up all configurations of an environment into a timestamped archive
+
+  Downloads all classic configurations and settings of the given environment and stores them as 'backup_<environment>_<timestamp>.tar.gz'.
+  Backups can be deployed again using 'monaco restore'.`,
+		Example:           "monaco backup manifest.yaml -e dev-environment -o backups",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.manifestFile = args[0]
+
+			if !files.IsYamlFileExtension(opts.manifestFile) {
+				return fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", opts.manifestFile)
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return backup(ctx, fs, opts)
+		},
+	}
+
+	backupCmd.Flags().StringVarP(&opts.environmentName, "environment", "e", "", "The environment defined in the manifest to back up")
+	backupCmd.Flags().StringVarP(&opts.outputFolder, "output-folder", "o", "backups", "Folder to write the backup archive to")
+	if featureflags.Entities().Enabled() {
+		backupCmd.Flags().BoolVar(&opts.withEntities, "entities", false, "Additionally back up all entities. Entities are informational only and are not restored")
+	}
+	cmdutils.AddTimeoutFlag(backupCmd, &timeout)
+
+	if err := backupCmd.MarkFlagRequired("environment"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := backupCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return backupCmd
+}
+
+func GetRestoreCommand(fs afero.Fs) (restoreCmd *cobra.Command) {
+	var opts restoreOptions
+	var timeout time.Duration
+
+	restoreCmd = &cobra.Command{
+		Use:   "restore <manifest.yaml> <backup.tar.gz>",
+		Short: "Restore configurations of a backup archive to an environment",
+		Long: `Restore configurations of a backup archive to an environment
+
+  Deploys all (or a selected subset of) configurations of a backup created by 'monaco backup' to an environment defined in the manifest.
+  This may be the environment the backup was taken from, or any other one.
+  Optionally, a delete file listing all configurations created after the backup is written, which can be applied using 'monaco delete'.`,
+		Example:           "monaco restore manifest.yaml backups/backup_dev_2023-05-04-101500.tar.gz -e dev-environment --api alerting-profile --delete-file restore-delete.yaml",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.manifestFile = args[0]
+			opts.archiveFile = args[1]
+
+			if !files.IsYamlFileExtension(opts.manifestFile) {
+				return fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", opts.manifestFile)
+			}
+
+			if opts.deleteFile != "" && !files.IsYamlFileExtension(opts.deleteFile) {
+				return fmt.Errorf("wrong format for delete file! expected a .yaml file, but got %s", opts.deleteFile)
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return restore(ctx, fs, opts)
+		},
+	}
+
+	restoreCmd.Flags().StringVarP(&opts.environmentName, "environment", "e", "", "The environment defined in the manifest to restore to")
+	restoreCmd.Flags().StringSliceVarP(&opts.specificAPIs, "api", "a", nil, "One or more APIs to restore (flag can be repeated or value defined as comma-separated list)")
+	restoreCmd.Flags().StringSliceVarP(&opts.specificSchemas, "settings-schema", "s", nil, "One or more settings 2.0 schemas to restore (flag can be repeated or value defined as comma-separated list)")
+	restoreCmd.Flags().StringVar(&opts.deleteFile, "delete-file", "", "Write a delete file listing all configurations of the restored APIs and settings schemas which were created after the backup")
+	restoreCmd.Flags().BoolVarP(&opts.dryRun, "dry-run", "d", false, "Switches to just validation instead of actual restore")
+	restoreCmd.Flags().BoolVarP(&opts.continueOnErr, "continue-on-error", "c", false, "Proceed restore even if config upload fails")
+	cmdutils.AddTimeoutFlag(restoreCmd, &timeout)
+
+	if err := restoreCmd.MarkFlagRequired("environment"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := restoreCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := restoreCmd.RegisterFlagCompletionFunc("api", completion.AllAvailableApis); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return restoreCmd
+}
@@ -0,0 +1,314 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/settings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+	"github.com/spf13/afero"
+	"sort"
+)
+
+type restoreOptions struct {
+	manifestFile    string
+	archiveFile     string
+	environmentName string
+	specificAPIs    []string
+	specificSchemas []string
+	deleteFile      string
+	continueOnErr   bool
+	dryRun          bool
+}
+
+func restore(ctx context.Context, fs afero.Fs, opts restoreOptions) error {
+	m, err := cmdutils.LoadManifest(fs, opts.manifestFile, cmdutils.ManifestOptions{Environments: []string{opts.environmentName}})
+	if err != nil {
+		return err
+	}
+
+	env, found := m.Environments[opts.environmentName]
+	if !found {
+		return fmt.Errorf("environment %q was not available in manifest %q", opts.environmentName, opts.manifestFile)
+	}
+
+	archiveFs, err := readArchive(fs, opts.archiveFile)
+	if err != nil {
+		return err
+	}
+
+	configs, err := loadBackup(archiveFs, env)
+	if err != nil {
+		return err
+	}
+
+	selected := filterConfigs(configs, opts.specificAPIs, opts.specificSchemas)
+	if len(selected) == 0 {
+		log.Warn("Backup %q contains no configurations matching the given filters", opts.archiveFile)
+		return nil
+	}
+	configs = withDependencies(configs, selected)
+	if len(configs) > len(selected) {
+		log.Info("Restoring %d configurations the selected configurations depend on as well", len(configs)-len(selected))
+	}
+
+	if ok := cmdutils.VerifyEnvironmentGeneration(ctx, manifest.Environments{env.Name: env}, m.Offline); !ok {
+		return fmt.Errorf("unable to verify Dynatrace environment generation")
+	}
+
+	deployClient, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, opts.dryRun, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	if err == nil && !opts.dryRun {
+		deployClient, err = cmdutils.AuditClient(fs, deployClient, env.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info("Restoring %d configurations from %q to environment %q", len(configs), opts.archiveFile, env.Name)
+	deployErrs := deploy.DeployConfigs(log.WithFields(ctx, log.EnvironmentField(env.Name)), deployClient, api.NewAPIs(), configs, deploy.DeployConfigsOptions{
+		ContinueOnErr: opts.continueOnErr,
+		DryRun:        opts.dryRun,
+	})
+	if len(deployErrs) > 0 {
+		errutils.PrintErrors(deployErrs)
+		return fmt.Errorf("failed to restore %d configurations", len(deployErrs))
+	}
+
+	if opts.deleteFile != "" {
+		// listing is read-only, thus even a dry-run uses a real client to find configs created after the backup
+		c, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+		if err != nil {
+			return err
+		}
+		if err := writeDeleteEntries(ctx, fs, c, selected, opts.deleteFile); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Restore finished without errors")
+	return nil
+}
+
+// loadBackup loads the configs project of a backup archive for the given target environment and returns its configs
+// sorted for deployment.
+func loadBackup(archiveFs afero.Fs, env manifest.EnvironmentDefinition) ([]config.Config, error) {
+	projects, errs := project.LoadProjects(archiveFs, project.ProjectLoaderContext{
+		KnownApis:  api.NewAPIs().GetApiNameLookup(),
+		WorkingDir: ".",
+		Manifest: manifest.Manifest{
+			Projects: manifest.ProjectDefinitionByProjectID{
+				configsProject: {Name: configsProject, Path: configsProject},
+			},
+			Environments: manifest.Environments{env.Name: env},
+		},
+		ParametersSerde: config.ParameterParsers(),
+	})
+	if errs != nil {
+		errutils.PrintErrors(errs)
+		return nil, errors.New("error while loading backup")
+	}
+
+	sorted, errs := topologysort.GetSortedConfigsForEnvironments(projects, []string{env.Name})
+	if errs != nil {
+		errutils.PrintErrors(errs)
+		return nil, errors.New("error during sorting of backed up configurations")
+	}
+
+	return sorted[env.Name], nil
+}
+
+// filterConfigs retains only the configs of the given APIs and settings schemas. If neither are given, all configs are retained.
+func filterConfigs(configs []config.Config, specificAPIs, specificSchemas []string) []config.Config {
+	if len(specificAPIs) == 0 && len(specificSchemas) == 0 {
+		return configs
+	}
+
+	var result []config.Config
+	for _, c := range configs {
+		switch t := c.Type.(type) {
+		case config.ClassicApiType:
+			if slices.Contains(specificAPIs, t.Api) {
+				result = append(result, c)
+			}
+		case config.SettingsType:
+			if slices.Contains(specificSchemas, t.SchemaId) {
+				result = append(result, c)
+			}
+		}
+	}
+	return result
+}
+
+// withDependencies returns the selected configs together with all configs they transitively depend on, in the order of
+// the given configs.
+func withDependencies(configs []config.Config, selected []config.Config) []config.Config {
+	byCoordinate := make(map[coordinate.Coordinate]config.Config, len(configs))
+	for _, c := range configs {
+		byCoordinate[c.Coordinate] = c
+	}
+
+	included := make(map[coordinate.Coordinate]bool, len(configs))
+	var include func(c config.Config)
+	include = func(c config.Config) {
+		if included[c.Coordinate] {
+			return
+		}
+		included[c.Coordinate] = true
+		for _, ref := range c.References() {
+			if dependency, found := byCoordinate[ref]; found {
+				include(dependency)
+			}
+		}
+	}
+	for _, c := range selected {
+		include(c)
+	}
+
+	result := make([]config.Config, 0, len(included))
+	for _, c := range configs {
+		if included[c.Coordinate] {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// writeDeleteEntries writes a delete file containing all configs and settings objects of the restored APIs and schemas
+// which currently exist on the environment, but are not part of the backup - i.e. those created after the backup was
+// taken.
+func writeDeleteEntries(ctx context.Context, fs afero.Fs, c client.Client, restored []config.Config, deleteFile string) error {
+	entries, err := findConfigsCreatedAfterBackup(ctx, c, restored)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		log.Info("No configurations were created after the backup")
+		return nil
+	}
+
+	if err := delete.WriteDeleteFile(fs, deleteFile, entries); err != nil {
+		return err
+	}
+	log.Info("%d configurations were created after the backup. Review and apply %q using 'monaco delete' to remove them", len(entries), deleteFile)
+	return nil
+}
+
+func findConfigsCreatedAfterBackup(ctx context.Context, c client.Client, restored []config.Config) ([]delete.DeletePointer, error) {
+	backedUpNames := make(map[string]map[string]struct{})
+	backedUpSettings := make(map[string][]config.Config)
+	for _, cfg := range restored {
+		switch t := cfg.Type.(type) {
+		case config.ClassicApiType:
+			if _, exists := backedUpNames[t.Api]; !exists {
+				backedUpNames[t.Api] = make(map[string]struct{})
+			}
+			if name, ok := cfg.Parameters[config.NameParameter].(*valueParam.ValueParameter); ok {
+				backedUpNames[t.Api][fmt.Sprint(name.Value)] = struct{}{}
+			}
+		case config.SettingsType:
+			backedUpSettings[t.SchemaId] = append(backedUpSettings[t.SchemaId], cfg)
+		}
+	}
+
+	apis := api.NewAPIs()
+	var entries []delete.DeletePointer
+	for apiID, names := range backedUpNames {
+		// configs of parent-scoped APIs are removed together with their parent objects
+		if apis[apiID].HasParent() {
+			continue
+		}
+
+		values, err := c.ListConfigs(ctx, apis[apiID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to list configs of type %q: %w", apiID, err)
+		}
+
+		for _, v := range values {
+			if classic.SkippedPreDownload(apiID, v) {
+				continue
+			}
+			if _, backedUp := names[v.Name]; !backedUp {
+				entries = append(entries, delete.DeletePointer{Type: apiID, ConfigId: v.Name})
+			}
+		}
+	}
+
+	for schemaId, configs := range backedUpSettings {
+		settingsEntries, err := findSettingsCreatedAfterBackup(ctx, c, schemaId, configs)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, settingsEntries...)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].ConfigId < entries[j].ConfigId
+	})
+	return entries, nil
+}
+
+// findSettingsCreatedAfterBackup returns delete entries for all settings objects of the schema which neither have the
+// externalId nor the objectId of one of the backed up configs. Entries refer to the settings objects by their objectId.
+func findSettingsCreatedAfterBackup(ctx context.Context, c client.SettingsClient, schemaId string, backedUp []config.Config) ([]delete.DeletePointer, error) {
+	externalIds := make(map[string]struct{}, len(backedUp))
+	objectIds := make(map[string]struct{}, len(backedUp))
+	for _, cfg := range backedUp {
+		externalIds[idutils.GenerateExternalID(schemaId, cfg.SettingsObjectId())] = struct{}{}
+		if cfg.OriginObjectId != "" {
+			objectIds[cfg.OriginObjectId] = struct{}{}
+		}
+	}
+
+	objects, err := c.ListSettings(ctx, schemaId, client.ListSettingsOptions{Filter: func(o client.DownloadSettingsObject) bool {
+		if settings.Discarded(o) {
+			return false
+		}
+		_, backedUpByExternalId := externalIds[o.ExternalId]
+		_, backedUpByObjectId := objectIds[o.ObjectId]
+		return !backedUpByExternalId && !backedUpByObjectId
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings of schema %q: %w", schemaId, err)
+	}
+
+	entries := make([]delete.DeletePointer, len(objects))
+	for i, o := range objects {
+		entries[i] = delete.DeletePointer{Type: schemaId, ConfigId: o.ObjectId}
+	}
+	return entries, nil
+}
@@ -0,0 +1,174 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Profile composes the projects of a manifest to a sequence of steps, which are deployed one after the other to
+// initialize an environment.
+type Profile struct {
+	// Manifest is the path of the manifest defining the environments and projects, relative to the profile
+	Manifest string `yaml:"manifest"`
+	// Variables are made available to the configurations as environment variables, unless they are already set
+	Variables map[string]string `yaml:"variables,omitempty"`
+	// Steps are deployed in the given order
+	Steps []Step `yaml:"steps"`
+}
+
+// Step is a set of projects deployed together.
+type Step struct {
+	// Name optionally names the step for logging. It defaults to the projects of the step.
+	Name string `yaml:"name,omitempty"`
+	// Projects to deploy in this step, including their dependencies
+	Projects []string `yaml:"projects"`
+}
+
+func (s Step) String() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprint(s.Projects)
+}
+
+// Options defines to which environments a profile is bootstrapped by Bootstrap, and how.
+type Options struct {
+	// EnvironmentGroups restricts the bootstrap to the given environment groups
+	EnvironmentGroups []string
+	// Environments restricts the bootstrap to the given environments
+	Environments []string
+	// DryRun states that the steps are only validated instead of deployed
+	DryRun bool
+}
+
+// deployFunc deploys the projects of a manifest, see [deploy.Deploy].
+type deployFunc func(ctx context.Context, fs afero.Fs, manifestPath string, opts deploy.Options) error
+
+// Bootstrap loads the given profile and deploys its steps in order. Before anything is deployed, all steps are
+// validated, so that an environment is not left half-initialized due to an invalid configuration of a later step.
+func Bootstrap(ctx context.Context, fs afero.Fs, profilePath string, opts Options) error {
+	return bootstrap(ctx, fs, profilePath, opts, deploy.Deploy)
+}
+
+func bootstrap(ctx context.Context, fs afero.Fs, profilePath string, opts Options, deployFn deployFunc) error {
+	profile, err := LoadProfile(fs, profilePath)
+	if err != nil {
+		return err
+	}
+
+	manifestPath := profile.Manifest
+	if !filepath.IsAbs(manifestPath) {
+		manifestPath = filepath.Join(filepath.Dir(profilePath), manifestPath)
+	}
+
+	if err := setVariables(profile.Variables); err != nil {
+		return err
+	}
+
+	deployStep := func(step Step, dryRun bool) error {
+		return deployFn(ctx, fs, manifestPath, deploy.Options{
+			EnvironmentGroups: opts.EnvironmentGroups,
+			Environments:      opts.Environments,
+			Projects:          step.Projects,
+			DryRun:            dryRun,
+		})
+	}
+
+	for i, step := range profile.Steps {
+		log.Info("Validating step %d/%d: %s", i+1, len(profile.Steps), step)
+		if err := deployStep(step, true); err != nil {
+			return fmt.Errorf("validation of step %d (%s) failed: %w", i+1, step, err)
+		}
+	}
+
+	if opts.DryRun {
+		log.Info("Validated all %d steps of profile %q", len(profile.Steps), profilePath)
+		return nil
+	}
+
+	for i, step := range profile.Steps {
+		log.Info("Deploying step %d/%d: %s", i+1, len(profile.Steps), step)
+		if err := deployStep(step, false); err != nil {
+			return fmt.Errorf("deployment of step %d (%s) failed, later steps were not deployed: %w", i+1, step, err)
+		}
+	}
+
+	log.Info("Bootstrapped all %d steps of profile %q", len(profile.Steps), profilePath)
+	return nil
+}
+
+// LoadProfile reads and validates the profile at the given path.
+func LoadProfile(fs afero.Fs, profilePath string) (Profile, error) {
+	data, err := afero.ReadFile(fs, profilePath)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to read profile %q: %w", profilePath, err)
+	}
+
+	var profile Profile
+	if err := yaml.UnmarshalStrict(data, &profile); err != nil {
+		return Profile{}, fmt.Errorf("failed to parse profile %q: %w", profilePath, err)
+	}
+
+	var errs []error
+	if profile.Manifest == "" {
+		errs = append(errs, errors.New("'manifest' is required"))
+	}
+	if len(profile.Steps) == 0 {
+		errs = append(errs, errors.New("at least one step is required"))
+	}
+	for i, step := range profile.Steps {
+		if len(step.Projects) == 0 {
+			errs = append(errs, fmt.Errorf("step %d (%s) does not define any projects", i+1, step))
+		}
+	}
+	if len(errs) > 0 {
+		return Profile{}, fmt.Errorf("invalid profile %q: %w", profilePath, errors.Join(errs...))
+	}
+
+	return profile, nil
+}
+
+// setVariables sets the given variables as environment variables. Variables which are already set are kept, so that
+// they can be overridden per environment, e.g. in a CI pipeline.
+func setVariables(variables map[string]string) error {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, isSet := os.LookupEnv(name); isSet {
+			log.Debug("Variable %q is already set and not overridden by the profile", name)
+			continue
+		}
+		if err := os.Setenv(name, variables[name]); err != nil {
+			return fmt.Errorf("failed to set variable %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/deploy"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+const profileYaml = `manifest: manifest.yaml
+variables:
+  BOOTSTRAP_TEST_OWNER: team-a
+  BOOTSTRAP_TEST_PRESET: profile
+steps:
+- name: tagging
+  projects: [tagging]
+- projects: [alerting, management-zones]
+`
+
+type deployCall struct {
+	manifestPath string
+	opts         deploy.Options
+}
+
+// newRecordingDeploy returns a deployFunc recording all calls, which fails for the step starting with the given
+// project, either in dry-run mode or when actually deploying.
+func newRecordingDeploy(failingProject string, failDryRun bool) (deployFunc, *[]deployCall) {
+	var calls []deployCall
+	return func(_ context.Context, _ afero.Fs, manifestPath string, opts deploy.Options) error {
+		calls = append(calls, deployCall{manifestPath: manifestPath, opts: opts})
+		if opts.Projects[0] == failingProject && opts.DryRun == failDryRun {
+			return errors.New("deployment failed")
+		}
+		return nil
+	}, &calls
+}
+
+func newProfileFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "bootstrap/profile.yaml", []byte(profileYaml), 0644))
+
+	t.Setenv("BOOTSTRAP_TEST_PRESET", "environment")
+	t.Cleanup(func() { _ = os.Unsetenv("BOOTSTRAP_TEST_OWNER") })
+	return fs
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Run("all steps are validated before they are deployed in order", func(t *testing.T) {
+		deployFn, calls := newRecordingDeploy("", false)
+
+		err := bootstrap(context.TODO(), newProfileFs(t), "bootstrap/profile.yaml", Options{Environments: []string{"dev"}}, deployFn)
+		assert.NoError(t, err)
+
+		assert.Len(t, *calls, 4)
+		assert.Equal(t, []bool{true, true, false, false}, []bool{(*calls)[0].opts.DryRun, (*calls)[1].opts.DryRun, (*calls)[2].opts.DryRun, (*calls)[3].opts.DryRun})
+		assert.Equal(t, []string{"tagging"}, (*calls)[2].opts.Projects)
+		assert.Equal(t, []string{"alerting", "management-zones"}, (*calls)[3].opts.Projects)
+		assert.Equal(t, "bootstrap/manifest.yaml", (*calls)[2].manifestPath)
+		assert.Equal(t, []string{"dev"}, (*calls)[2].opts.Environments)
+	})
+
+	t.Run("profile variables are set unless already defined", func(t *testing.T) {
+		deployFn, _ := newRecordingDeploy("", false)
+
+		err := bootstrap(context.TODO(), newProfileFs(t), "bootstrap/profile.yaml", Options{DryRun: true}, deployFn)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "team-a", os.Getenv("BOOTSTRAP_TEST_OWNER"))
+		assert.Equal(t, "environment", os.Getenv("BOOTSTRAP_TEST_PRESET"))
+	})
+
+	t.Run("dry-run only validates", func(t *testing.T) {
+		deployFn, calls := newRecordingDeploy("", false)
+
+		err := bootstrap(context.TODO(), newProfileFs(t), "bootstrap/profile.yaml", Options{DryRun: true}, deployFn)
+		assert.NoError(t, err)
+		assert.Len(t, *calls, 2)
+	})
+
+	t.Run("nothing is deployed if a later step is invalid", func(t *testing.T) {
+		deployFn, calls := newRecordingDeploy("alerting", true)
+
+		err := bootstrap(context.TODO(), newProfileFs(t), "bootstrap/profile.yaml", Options{}, deployFn)
+		assert.ErrorContains(t, err, "validation of step 2")
+		assert.Len(t, *calls, 2)
+	})
+
+	t.Run("later steps are not deployed after a failing step", func(t *testing.T) {
+		deployFn, calls := newRecordingDeploy("tagging", false)
+
+		err := bootstrap(context.TODO(), newProfileFs(t), "bootstrap/profile.yaml", Options{}, deployFn)
+		assert.ErrorContains(t, err, "deployment of step 1 (tagging) failed")
+		assert.Len(t, *calls, 3)
+	})
+}
+
+func TestLoadProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		wantErr string
+	}{
+		{"manifest is required", "steps:\n- projects: [a]", "'manifest' is required"},
+		{"steps are required", "manifest: manifest.yaml", "at least one step is required"},
+		{"steps need projects", "manifest: manifest.yaml\nsteps:\n- name: empty", "step 1 (empty) does not define any projects"},
+		{"unknown fields are reported", "manifest: manifest.yaml\nstep:\n- projects: [a]", "failed to parse profile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			assert.NoError(t, afero.WriteFile(fs, "profile.yaml", []byte(tt.profile), 0644))
+
+			_, err := LoadProfile(fs, "profile.yaml")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
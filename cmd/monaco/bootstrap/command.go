@@ -0,0 +1,69 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bootstrap
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+func GetBootstrapCommand(fs afero.Fs) (bootstrapCmd *cobra.Command) {
+	var opts Options
+	var profilePath string
+	var timeout time.Duration
+
+	bootstrapCmd = &cobra.Command{
+		Use:   "bootstrap --profile <profile.yaml>",
+		Short: "Initialize Dynatrace environments by deploying the projects of a profile step by step",
+		Long: `Initialize Dynatrace environments by deploying the projects of a profile step by step
+
+  A profile references a manifest and composes its projects to steps, e.g. baseline tagging, alerting and management
+  zones. All steps are validated before the first one is deployed. Afterwards, the steps are deployed in order,
+  stopping at the first failing step. Variables defined by the profile are available as environment variables.`,
+		Example: "monaco bootstrap --profile profile.yaml -e dev-environment",
+		Args:    cobra.NoArgs,
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return Bootstrap(ctx, fs, profilePath, opts)
+		},
+	}
+
+	bootstrapCmd.Flags().StringVar(&profilePath, "profile", "", "Path of the profile defining the manifest and the steps to deploy")
+	bootstrapCmd.Flags().StringSliceVarP(&opts.Environments, "environment", "e", []string{},
+		"Specify one (or multiple) environment(s) to bootstrap. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'.")
+	bootstrapCmd.Flags().StringSliceVarP(&opts.EnvironmentGroups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) to bootstrap. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'")
+	bootstrapCmd.Flags().BoolVarP(&opts.DryRun, "dry-run", "d", false, "Only validate all steps of the profile instead of deploying them")
+	cmdutils.AddTimeoutFlag(bootstrapCmd, &timeout)
+
+	if err := bootstrapCmd.MarkFlagRequired("profile"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	bootstrapCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return bootstrapCmd
+}
@@ -20,12 +20,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/audit"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/lint"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // SilenceUsageCommand gives back a command that is just configured to skip printing of usage info.
@@ -37,37 +54,367 @@ func SilenceUsageCommand() func(cmd *cobra.Command, args []string) {
 	}
 }
 
+// AddTimeoutFlag registers the `--timeout` flag limiting the overall run time of the given command.
+func AddTimeoutFlag(cmd *cobra.Command, timeout *time.Duration) {
+	cmd.Flags().DurationVar(timeout, "timeout", 0, "Maximum duration of the whole command run, e.g. '30m'. If the timeout is exceeded, all running requests are cancelled. By default no timeout is set")
+}
+
+// WithTimeout returns a context that is cancelled after the given timeout. A timeout of 0 means that no timeout is applied.
+// The returned cancel function must always be called.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// EnvManifestPath is the environment variable the manifest path is read from, if it is not passed as argument.
+const EnvManifestPath = "MONACO_MANIFEST"
+
+// envManifestName is used as manifest path if the manifest is created from environment variables. Its only purpose is
+// to make the current directory the working directory of the manifest's projects.
+const envManifestName = "manifest-from-env.yaml"
+
+// AddManifestFromEnvFlag registers the `--manifest-from-env` flag, creating the manifest from environment variables instead of a file.
+func AddManifestFromEnvFlag(cmd *cobra.Command, fromEnv *bool) {
+	cmd.Flags().BoolVar(fromEnv, "manifest-from-env", false, fmt.Sprintf("Create the manifest from environment variables instead of a file. "+
+		"Projects are read from %s, environments from %s and each environment is configured by variables prefixed with 'MONACO_ENVIRONMENT_<NAME>_'. "+
+		"Project paths are resolved relative to the current directory", manifest.EnvManifestProjects, manifest.EnvManifestEnvironments))
+}
+
+// ResolveManifestPath returns the path of the manifest to load. It is either the single positional argument, or - if
+// no argument is given - the value of the environment variable EnvManifestPath. If fromEnv is set, no manifest file
+// is used at all and a path within the current directory is returned.
+func ResolveManifestPath(args []string, fromEnv bool) (string, error) {
+	if fromEnv {
+		if len(args) > 0 {
+			return "", fmt.Errorf("manifest %q can not be used together with '--manifest-from-env'", args[0])
+		}
+		return envManifestName, nil
+	}
+
+	var manifestPath string
+	if len(args) > 0 {
+		manifestPath = args[0]
+	} else if manifestPath = os.Getenv(EnvManifestPath); manifestPath == "" {
+		return "", fmt.Errorf("no manifest given! either pass it as argument, set the environment variable %s, or use '--manifest-from-env'", EnvManifestPath)
+	}
+
+	if !files.IsYamlFileExtension(manifestPath) {
+		return "", fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestPath)
+	}
+	return manifestPath, nil
+}
+
+// AddWorkspaceFlag registers the `--workspace` flag, operating on all manifests of a workspace file instead of a single manifest.
+func AddWorkspaceFlag(cmd *cobra.Command, workspacePath *string) {
+	cmd.Flags().StringVar(workspacePath, "workspace", "", "Operate on all manifests of the given workspace file instead of a single manifest. "+
+		"Environments, groups and projects are either scoped by the name of their manifest ('<manifest>/<name>'), or apply to all manifests")
+	cmd.MarkFlagsMutuallyExclusive("workspace", "manifest-from-env")
+}
+
+// AddSafeguardFlags registers the `--max-deletes` and `--confirm` flags, aborting deletions which would delete more objects than expected.
+func AddSafeguardFlags(cmd *cobra.Command, safeguard *delete.Safeguard) {
+	cmd.Flags().Var(&safeguard.MaxDeletes, "max-deletes", "Abort without deleting anything if more objects would be deleted from any environment. "+
+		"Either an absolute number (e.g. '50'), or a percentage of the existing objects of the affected types (e.g. '20%')")
+	cmd.Flags().BoolVar(&safeguard.Confirmed, "confirm", false, "Delete objects even if more than allowed by '--max-deletes' would be deleted")
+}
+
+// AddDeploymentEventFlag registers the `--deployment-event` flag, defining the event sent to environments after a successful deployment.
+func AddDeploymentEventFlag(cmd *cobra.Command, eventType *string) {
+	cmd.Flags().StringVar(eventType, "deployment-event", "", fmt.Sprintf("Send an event listing all deployed configurations to each environment after a successful deployment. "+
+		"One of %s. Sending events requires the token scope 'events.ingest', or 'bizevents.ingest' for %s", client.EventTypes, client.BizEvent))
+}
+
+// ParseEventType validates the value of the `--deployment-event` flag. An empty value is valid and means that no event is sent.
+func ParseEventType(eventType string) (client.EventType, error) {
+	if eventType == "" {
+		return "", nil
+	}
+	for _, t := range client.EventTypes {
+		if strings.EqualFold(string(t), eventType) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown deployment event %q! expected one of %s", eventType, client.EventTypes)
+}
+
+// WithHTTPSettings returns a client option that applies the HTTP settings defined in a manifest. To include the
+// headers, query parameters and client certificate of a single environment, pass [manifest.HTTPSettings.ForEnvironment].
+func WithHTTPSettings(s manifest.HTTPSettings) func(*client.DynatraceClient) {
+	timeouts := client.WithHTTPTimeouts(client.HTTPTimeouts{
+		Connect: s.ConnectTimeout,
+		Request: s.RequestTimeout,
+	})
+
+	additions := make([]rest.RequestAddition, len(s.RequestAdditions))
+	for i, a := range s.RequestAdditions {
+		additions[i] = rest.RequestAddition{
+			PathPrefix:      a.PathPrefix,
+			Headers:         a.Headers,
+			QueryParameters: a.QueryParameters,
+		}
+	}
+
+	return func(d *client.DynatraceClient) {
+		// timeouts replace the base transports, hence they need to be applied first
+		timeouts(d)
+		if s.ClientCertificate != nil {
+			client.WithClientCertificate(*s.ClientCertificate)(d)
+		}
+		client.WithRequestAdditions(additions)(d)
+	}
+}
+
 // CreateDTClient is driven by data given through a manifest.EnvironmentDefinition to create an appropriate client.Client.
+// The given options are applied to the created client.
 //
 // In case when flag dryRun is true this factory returns the client.DummyClient.
-func CreateDTClient(url string, a manifest.Auth, dryRun bool) (client.Client, error) {
+func CreateDTClient(ctx context.Context, url string, a manifest.Auth, dryRun bool, opts ...func(*client.DynatraceClient)) (client.Client, error) {
 	switch {
 	case dryRun:
 		return client.NewDummyClient(), nil
 	case a.OAuth == nil:
-		return client.NewClassicClient(url, a.Token.Value)
+		return client.NewClassicClient(url, a.Token.Value, opts...)
 	case a.OAuth != nil:
-		oauthCredentials := client.OauthCredentials{
-			ClientID:     a.OAuth.ClientID.Value,
-			ClientSecret: a.OAuth.ClientSecret.Value,
-			TokenURL:     a.OAuth.GetTokenEndpointValue(),
-		}
-		return client.NewPlatformClient(url, a.Token.Value, oauthCredentials)
+		return client.NewPlatformClient(ctx, url, a.Token.Value, OAuthCredentials(*a.OAuth), opts...)
 	default:
 		return nil, fmt.Errorf("unable to create authorizing HTTP Client for environment %s - no oauth credentials given", url)
 	}
 }
 
+// EnvHTTPCache is the environment variable defining the directory responses of downloads are cached in. If not set,
+// responses are not cached.
+const EnvHTTPCache = "MONACO_HTTP_CACHE"
+
+// EnvHTTPCacheTTL is the environment variable defining how long cached responses are used without checking for
+// changes, e.g. '30m'. Defaults to defaultHTTPCacheTTL.
+const EnvHTTPCacheTTL = "MONACO_HTTP_CACHE_TTL"
+
+const defaultHTTPCacheTTL = 10 * time.Minute
+
+// WithHTTPCache returns an option caching responses of the client in the directory defined by EnvHTTPCache, or nil if
+// the variable is not set. It must only be used by commands that do not change configs, and must be the last option.
+func WithHTTPCache(fs afero.Fs) func(*client.DynatraceClient) {
+	dir := os.Getenv(EnvHTTPCache)
+	if dir == "" {
+		return nil
+	}
+
+	ttl := defaultHTTPCacheTTL
+	if v := os.Getenv(EnvHTTPCacheTTL); v != "" {
+		if d, err := time.ParseDuration(v); err != nil || d < 0 {
+			log.Warn("Invalid value %q of environment variable %s, using default of %v", v, EnvHTTPCacheTTL, defaultHTTPCacheTTL)
+		} else {
+			ttl = d
+		}
+	}
+
+	log.Info("Caching responses in %q for %v", dir, ttl)
+	return client.WithHTTPCache(fs, dir, ttl)
+}
+
+// EnvAuditLog is the environment variable defining the file all changes made to environments are appended to.
+const EnvAuditLog = "MONACO_AUDIT_LOG"
+
+// AuditClient decorates the given client to record all changes made to the given environment. Changes are appended to
+// the file defined by EnvAuditLog, and sent as log records to the environment if featureflags.AuditLogForwarding is
+// enabled. If neither is configured, the client is returned as is.
+func AuditClient(fs afero.Fs, c client.Client, environment string) (client.Client, error) {
+	var sinks []audit.Sink
+
+	if path := os.Getenv(EnvAuditLog); path != "" {
+		s, err := audit.NewFileSink(fs, path)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+	if featureflags.AuditLogForwarding().Enabled() {
+		sinks = append(sinks, audit.NewTenantSink(c))
+	}
+
+	if len(sinks) == 0 {
+		return c, nil
+	}
+	return audit.Client(c, environment, sinks...), nil
+}
+
+// CreateEnvironmentClient loads the manifest and returns a client for the single given environment defined in it.
+// Changes made via the client are audited.
+func CreateEnvironmentClient(ctx context.Context, fs afero.Fs, manifestPath string, manifestFromEnv bool, environment string) (client.Client, error) {
+	m, err := LoadManifest(fs, manifestPath, ManifestOptions{Environments: []string{environment}, FromEnv: manifestFromEnv})
+	if err != nil {
+		return nil, err
+	}
+
+	env, found := m.Environments[environment]
+	if !found {
+		return nil, fmt.Errorf("environment %q was not available in manifest %q", environment, manifestPath)
+	}
+
+	c, err := CreateDTClient(ctx, env.URL.Value, env.Auth, false, WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	if err == nil {
+		c, err = AuditClient(fs, c, env.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a client for environment %q: %w", env.Name, err)
+	}
+	return c, nil
+}
+
+// OAuthCredentials returns the client credentials defined by the OAuth settings of an environment.
+func OAuthCredentials(o manifest.OAuth) client.OauthCredentials {
+	return client.OauthCredentials{
+		ClientID:     o.ClientID.Value,
+		ClientSecret: o.ClientSecret.Value,
+		TokenURL:     o.GetTokenEndpointValue(),
+		Scopes:       o.Scopes,
+		Audience:     o.Audience,
+	}
+}
+
+// ManifestOptions restrict what is loaded of a manifest, see manifest.LoaderContext.
+type ManifestOptions struct {
+	Groups       []string
+	Environments []string
+	FromEnv      bool
+	// StrictProjects states that unknown keys in config files fail loading projects, see project.ProjectLoaderContext
+	StrictProjects bool
+}
+
+// LoadManifest loads the manifest at the given path. Load errors are printed.
+func LoadManifest(fs afero.Fs, manifestPath string, opts ManifestOptions) (manifest.Manifest, error) {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return manifest.Manifest{}, fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, errs := manifest.LoadManifest(&manifest.LoaderContext{
+		Fs:           fs,
+		ManifestPath: absManifestPath,
+		Groups:       opts.Groups,
+		Environments: opts.Environments,
+		FromEnv:      opts.FromEnv,
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return manifest.Manifest{}, errors.New("error while loading manifest")
+	}
+	return m, nil
+}
+
+// LoadManifestAndProjects loads the manifest at the given path and all projects defined in it. Load errors are printed.
+func LoadManifestAndProjects(fs afero.Fs, manifestPath string, opts ManifestOptions) (manifest.Manifest, []project.Project, error) {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return manifest.Manifest{}, nil, fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, err := LoadManifest(fs, absManifestPath, opts)
+	if err != nil {
+		return manifest.Manifest{}, nil, err
+	}
+
+	context := newProjectLoaderContext(absManifestPath, m)
+	context.Strict = opts.StrictProjects
+	projects, errs := project.LoadProjects(fs, context)
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return manifest.Manifest{}, nil, errors.New("error while loading projects")
+	}
+	return m, projects, nil
+}
+
+// LoadIgnoredRemoteObjects loads all projects defined in the given manifest which can be loaded and returns, per environment, the remote
+// objects of all configs for which isIgnored returns true. Manifests without projects result in an empty map.
+func LoadIgnoredRemoteObjects(fs afero.Fs, manifestPath string, m manifest.Manifest, isIgnored func(c config.Config) bool) map[string]config.RemoteObjects {
+	return project.RemoteObjectsPerEnvironment(LoadManifestProjectsPartially(fs, manifestPath, m), isIgnored)
+}
+
+// LoadManifestProjectsPartially loads all projects defined in the given manifest which can be loaded. Projects failing
+// to load are skipped with a warning, see project.LoadProjectsPartially.
+func LoadManifestProjectsPartially(fs afero.Fs, manifestPath string, m manifest.Manifest) []project.Project {
+	if len(m.Projects) == 0 {
+		return nil
+	}
+
+	projects, errs := project.LoadProjectsPartially(fs, newProjectLoaderContext(manifestPath, m))
+	for _, err := range errs {
+		log.Warn("Skipping project configuration which failed to load: %v", err)
+	}
+	return projects
+}
+
+// LoadManifestProjects loads all projects defined in the given manifest. Load errors are printed. Manifests without
+// projects result in no projects.
+func LoadManifestProjects(fs afero.Fs, manifestPath string, m manifest.Manifest) ([]project.Project, error) {
+	if len(m.Projects) == 0 {
+		return nil, nil
+	}
+
+	projects, errs := project.LoadProjects(fs, newProjectLoaderContext(manifestPath, m))
+	if errs != nil {
+		errutils.PrintErrors(errs)
+		return nil, errors.New("error while loading projects of manifest")
+	}
+	return projects, nil
+}
+
+func newProjectLoaderContext(manifestPath string, m manifest.Manifest) project.ProjectLoaderContext {
+	return project.ProjectLoaderContext{
+		KnownApis:       api.NewAPIs().GetApiNameLookup(),
+		WorkingDir:      filepath.Dir(manifestPath),
+		Manifest:        m,
+		ParametersSerde: config.ParameterParsers(),
+	}
+}
+
+// LoadKnownObjects returns the objects deployed by the given projects of a manifest, as far as their IDs are known:
+// the ones recorded by the configs themselves, and the ones recorded in download snapshots of the project folders.
+// Snapshot entries of configs which no longer exist are ignored.
+func LoadKnownObjects(fs afero.Fs, manifestPath string, m manifest.Manifest, projects []project.Project) (lint.KnownObjects, error) {
+	known := lint.CollectKnownObjects(projects)
+
+	existing := make(map[coordinate.Coordinate]bool)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			existing[c.Coordinate] = true
+		})
+	}
+
+	for _, p := range m.Projects {
+		snapshot, err := download.ReadSnapshot(fs, filepath.Join(filepath.Dir(manifestPath), p.Path))
+		if err != nil {
+			return nil, err
+		}
+		if snapshot == nil {
+			continue
+		}
+		for id, c := range snapshot.OriginObjectIds() {
+			if existing[c] {
+				known.Add(id, c)
+			}
+		}
+	}
+	return known, nil
+}
+
 // VerifyEnvironmentGeneration takes a manifestEnvironments map and tries to verify that each environment can be reached
-// using the configured credentials
-func VerifyEnvironmentGeneration(envs manifest.Environments) bool {
+// using the configured credentials. In offline mode, environments are not verified.
+func VerifyEnvironmentGeneration(ctx context.Context, envs manifest.Environments, offline manifest.OfflineSettings) bool {
+	if offline.Enabled {
+		log.Debug("Offline mode is enabled, skipping verification of environments")
+		return true
+	}
+
 	if featureflags.VerifyEnvironmentType().Enabled() {
 		for _, env := range envs {
 			switch {
 			case env.Auth.OAuth == nil:
-				return isClassicEnvironment(env)
+				return isClassicEnvironment(ctx, env)
 			case env.Auth.OAuth != nil:
-				return isPlatformEnvironment(env)
+				return isPlatformEnvironment(ctx, env)
 			default:
 				log.Error("Could not authorize against the environment with name %q (%s). Unknown environment type.", env.Name, env.URL.Value)
 				return false
@@ -77,8 +424,17 @@ func VerifyEnvironmentGeneration(envs manifest.Environments) bool {
 	return true
 }
 
-func isClassicEnvironment(env manifest.EnvironmentDefinition) bool {
-	if _, err := client.GetDynatraceVersion(client.NewTokenAuthClient(env.Auth.Token.Value), env.URL.Value); err != nil {
+// ServerVersion returns the version of the Dynatrace server of the environment at the given URL.
+func ServerVersion(ctx context.Context, url string, a manifest.Auth) (version.Version, error) {
+	httpClient := client.NewTokenAuthClient(a.Token.Value)
+	if a.OAuth != nil {
+		httpClient = client.NewOAuthClient(ctx, OAuthCredentials(*a.OAuth))
+	}
+	return client.GetDynatraceVersion(ctx, httpClient, url)
+}
+
+func isClassicEnvironment(ctx context.Context, env manifest.EnvironmentDefinition) bool {
+	if _, err := client.GetDynatraceVersion(ctx, client.NewTokenAuthClient(env.Auth.Token.Value), env.URL.Value); err != nil {
 		var respErr client.RespError
 		if errors.As(err, &respErr) {
 			log.Error("Could not authorize against the environment with name %q (%s) using token authorization.", env.Name, env.URL.Value)
@@ -95,13 +451,8 @@ func isClassicEnvironment(env manifest.EnvironmentDefinition) bool {
 	return true
 }
 
-func isPlatformEnvironment(env manifest.EnvironmentDefinition) bool {
-	oauthCredentials := client.OauthCredentials{
-		ClientID:     env.Auth.OAuth.ClientID.Value,
-		ClientSecret: env.Auth.OAuth.ClientSecret.Value,
-		TokenURL:     env.Auth.OAuth.GetTokenEndpointValue(),
-	}
-	if _, err := client.GetDynatraceClassicURL(client.NewOAuthClient(context.TODO(), oauthCredentials), env.URL.Value); err != nil {
+func isPlatformEnvironment(ctx context.Context, env manifest.EnvironmentDefinition) bool {
+	if _, err := client.GetDynatraceClassicURL(ctx, client.NewOAuthClient(ctx, OAuthCredentials(*env.Auth.OAuth)), env.URL.Value); err != nil {
 		var respErr client.RespError
 		if errors.As(err, &respErr) {
 			log.Error("Could not authorize against the environment with name %q (%s) using oAuth authorization.", env.Name, env.URL.Value)
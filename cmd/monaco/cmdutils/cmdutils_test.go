@@ -19,12 +19,15 @@
 package cmdutils
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -58,7 +61,7 @@ func TestVerifyClusterGen(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if ok := VerifyEnvironmentGeneration(tt.args.envs); ok == tt.wantErr {
+			if ok := VerifyEnvironmentGeneration(context.TODO(), tt.args.envs, manifest.OfflineSettings{}); ok == tt.wantErr {
 				t.Errorf("VerifyEnvironmentGeneration() error = %v, wantErr %v", ok, tt.wantErr)
 			}
 		})
@@ -71,7 +74,7 @@ func TestVerifyClusterGen(t *testing.T) {
 		}))
 		defer server.Close()
 
-		ok := VerifyEnvironmentGeneration(manifest.Environments{
+		ok := VerifyEnvironmentGeneration(context.TODO(), manifest.Environments{
 			"env": manifest.EnvironmentDefinition{
 				Name: "env",
 				URL: manifest.URLDefinition{
@@ -80,7 +83,20 @@ func TestVerifyClusterGen(t *testing.T) {
 					Value: server.URL,
 				},
 			},
-		})
+		}, manifest.OfflineSettings{})
+		assert.True(t, ok)
+	})
+
+	t.Run("Offline mode skips verification", func(t *testing.T) {
+		ok := VerifyEnvironmentGeneration(context.TODO(), manifest.Environments{
+			"env": manifest.EnvironmentDefinition{
+				Name: "env",
+				URL: manifest.URLDefinition{
+					Type:  manifest.ValueURLType,
+					Value: "http://unreachable.invalid",
+				},
+			},
+		}, manifest.OfflineSettings{Enabled: true})
 		assert.True(t, ok)
 	})
 
@@ -103,7 +119,7 @@ func TestVerifyClusterGen(t *testing.T) {
 		}))
 		defer server.Close()
 
-		ok := VerifyEnvironmentGeneration(manifest.Environments{
+		ok := VerifyEnvironmentGeneration(context.TODO(), manifest.Environments{
 			"env": manifest.EnvironmentDefinition{
 				Name: "env",
 				URL: manifest.URLDefinition{
@@ -119,7 +135,7 @@ func TestVerifyClusterGen(t *testing.T) {
 					},
 				},
 			},
-		})
+		}, manifest.OfflineSettings{})
 		assert.True(t, ok)
 	})
 
@@ -142,7 +158,7 @@ func TestVerifyClusterGen(t *testing.T) {
 		}))
 		defer server.Close()
 
-		ok := VerifyEnvironmentGeneration(manifest.Environments{
+		ok := VerifyEnvironmentGeneration(context.TODO(), manifest.Environments{
 			"env1": manifest.EnvironmentDefinition{
 				Name: "env1",
 				URL: manifest.URLDefinition{
@@ -151,10 +167,10 @@ func TestVerifyClusterGen(t *testing.T) {
 					Value: server.URL + "/WRONG_URL",
 				},
 			},
-		})
+		}, manifest.OfflineSettings{})
 		assert.False(t, ok)
 
-		ok = VerifyEnvironmentGeneration(manifest.Environments{
+		ok = VerifyEnvironmentGeneration(context.TODO(), manifest.Environments{
 			"env2": manifest.EnvironmentDefinition{
 				Name: "env2",
 				URL: manifest.URLDefinition{
@@ -170,7 +186,80 @@ func TestVerifyClusterGen(t *testing.T) {
 					},
 				},
 			},
-		})
+		}, manifest.OfflineSettings{})
 		assert.False(t, ok)
 	})
 }
+
+func TestResolveManifestPath(t *testing.T) {
+	t.Setenv(EnvManifestPath, "")
+
+	p, err := ResolveManifestPath([]string{"manifest.yaml"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "manifest.yaml", p)
+
+	_, err = ResolveManifestPath([]string{"manifest.json"}, false)
+	assert.ErrorContains(t, err, "expected a .yaml file")
+
+	_, err = ResolveManifestPath(nil, false)
+	assert.ErrorContains(t, err, EnvManifestPath)
+
+	t.Setenv(EnvManifestPath, "from-env/manifest.yaml")
+	p, err = ResolveManifestPath(nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env/manifest.yaml", p)
+
+	p, err = ResolveManifestPath(nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, ".", filepath.Dir(p))
+
+	_, err = ResolveManifestPath([]string{"manifest.yaml"}, true)
+	assert.Error(t, err)
+}
+
+func TestLoadManifestAndProjects(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://abcde.dev.dynatracelabs.com
+    auth:
+      token:
+        name: ENV_TOKEN
+`
+	configYaml := `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+`
+
+	newFs := func() afero.Fs {
+		fs := afero.NewMemMapFs()
+		folder, _ := filepath.Abs(".")
+		_ = afero.WriteFile(fs, filepath.Join(folder, "manifest.yaml"), []byte(manifestYaml), 0644)
+		_ = afero.WriteFile(fs, filepath.Join(folder, "project/alerting-profile/profile.yaml"), []byte(configYaml), 0644)
+		_ = afero.WriteFile(fs, filepath.Join(folder, "project/alerting-profile/profile.json"), []byte("{}"), 0644)
+		return fs
+	}
+
+	t.Run("manifest and projects are loaded", func(t *testing.T) {
+		m, projects, err := LoadManifestAndProjects(newFs(), "manifest.yaml", ManifestOptions{})
+		assert.NoError(t, err)
+		assert.Contains(t, m.Environments, "env")
+		assert.Len(t, projects, 1)
+	})
+
+	t.Run("unknown environment", func(t *testing.T) {
+		_, _, err := LoadManifestAndProjects(newFs(), "manifest.yaml", ManifestOptions{Environments: []string{"unknown"}})
+		assert.EqualError(t, err, "error while loading manifest")
+	})
+}
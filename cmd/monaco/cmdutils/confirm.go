@@ -0,0 +1,141 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdutils
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// AddYesFlag registers the `--yes` flag, skipping the interactive confirmation of destructive commands.
+func AddYesFlag(cmd *cobra.Command, yes *bool) {
+	cmd.Flags().BoolVarP(yes, "yes", "y", false, "Skip the interactive confirmation, e.g. when running in automation")
+}
+
+// DeletionConfirmation asks users to confirm planned deletions before anything is deleted.
+type DeletionConfirmation struct {
+	In  io.Reader
+	Out io.Writer
+	// Skip disables the confirmation, e.g. if `--yes` is set
+	Skip bool
+}
+
+// NewDeletionConfirmation creates a DeletionConfirmation reading from and writing to the in- and output of the given command.
+func NewDeletionConfirmation(cmd *cobra.Command, skip bool) DeletionConfirmation {
+	return DeletionConfirmation{In: cmd.InOrStdin(), Out: cmd.OutOrStdout(), Skip: skip}
+}
+
+// Confirm prints a summary of the given plans, keyed by environment name, and requires users to type the name of each
+// environment objects are deleted from. An error is returned if any environment is not confirmed, or if confirmation is
+// required but the input is not an interactive terminal.
+func (c DeletionConfirmation) Confirm(plans map[string]delete.Plan) error {
+	if c.Skip {
+		return nil
+	}
+
+	var envs []string
+	total := 0
+	types := make(map[string]struct{})
+	for env, p := range plans {
+		if p.Count() == 0 {
+			continue
+		}
+		envs = append(envs, env)
+		total += p.Count()
+		for _, t := range p.Types() {
+			types[t] = struct{}{}
+		}
+	}
+	sort.Strings(envs)
+
+	if total == 0 {
+		return nil
+	}
+
+	if !isInteractive(c.In) {
+		return errors.New("deletion needs to be confirmed, but the input is not interactive. Use '--yes' to delete without confirmation")
+	}
+
+	fmt.Fprintf(c.Out, "About to delete %d configs across %d APIs on environments %s:\n", total, len(types), strings.Join(envs, ", "))
+	for _, env := range envs {
+		p := plans[env]
+		fmt.Fprintf(c.Out, "  - %s: %d configs of %s\n", env, p.Count(), strings.Join(p.Types(), ", "))
+	}
+
+	scanner := bufio.NewScanner(c.In)
+	for _, env := range envs {
+		fmt.Fprintf(c.Out, "Type the name of environment %q to confirm: ", env)
+		if !scanner.Scan() {
+			return fmt.Errorf("deletion from environment %q was not confirmed", env)
+		}
+		if strings.TrimSpace(scanner.Text()) != env {
+			return fmt.Errorf("deletion from environment %q was not confirmed: got %q", env, strings.TrimSpace(scanner.Text()))
+		}
+	}
+
+	return nil
+}
+
+// isInteractive returns whether the given input is a terminal. Inputs other than files - e.g. those set by tests - are
+// considered interactive.
+func isInteractive(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	if !ok {
+		return true
+	}
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// StageApproval asks users to approve the next stage of a rollout before it is deployed.
+type StageApproval struct {
+	In  io.Reader
+	Out io.Writer
+}
+
+// NewStageApproval creates a StageApproval reading from and writing to the in- and output of the given command.
+func NewStageApproval(cmd *cobra.Command) StageApproval {
+	return StageApproval{In: cmd.InOrStdin(), Out: cmd.OutOrStdout()}
+}
+
+// Approve asks users to approve the given stage. An error is returned if the stage is not approved, or if the input is
+// not an interactive terminal.
+func (a StageApproval) Approve(_ context.Context, stage string) error {
+	if a.In == nil || !isInteractive(a.In) {
+		return fmt.Errorf("%s needs to be approved, but the input is not interactive", stage)
+	}
+
+	fmt.Fprintf(a.Out, "Deploy %s? [y/N]: ", stage)
+	scanner := bufio.NewScanner(a.In)
+	if !scanner.Scan() {
+		return fmt.Errorf("%s was not approved", stage)
+	}
+	switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+	case "y", "yes":
+		return nil
+	default:
+		return fmt.Errorf("%s was not approved", stage)
+	}
+}
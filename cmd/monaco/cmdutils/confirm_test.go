@@ -0,0 +1,113 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdutils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDeletionConfirmation_Confirm(t *testing.T) {
+	plans := map[string]delete.Plan{
+		"prod":    planOf(t, 2),
+		"staging": planOf(t, 1),
+		"empty":   {},
+	}
+
+	t.Run("all environments confirmed", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		c := DeletionConfirmation{In: strings.NewReader("prod\nstaging\n"), Out: out}
+
+		assert.NoError(t, c.Confirm(plans))
+		assert.Contains(t, out.String(), "About to delete 3 configs across 1 APIs on environments prod, staging")
+		assert.NotContains(t, out.String(), "empty", "environments without deletions do not need to be confirmed")
+	})
+
+	t.Run("wrong environment name", func(t *testing.T) {
+		c := DeletionConfirmation{In: strings.NewReader("prod\nprod\n"), Out: &bytes.Buffer{}}
+		assert.ErrorContains(t, c.Confirm(plans), `deletion from environment "staging" was not confirmed`)
+	})
+
+	t.Run("input ends before all environments are confirmed", func(t *testing.T) {
+		c := DeletionConfirmation{In: strings.NewReader("prod\n"), Out: &bytes.Buffer{}}
+		assert.Error(t, c.Confirm(plans))
+	})
+
+	t.Run("skipped confirmation", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		c := DeletionConfirmation{In: strings.NewReader(""), Out: out, Skip: true}
+		assert.NoError(t, c.Confirm(plans))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("nothing to delete", func(t *testing.T) {
+		c := DeletionConfirmation{In: strings.NewReader(""), Out: &bytes.Buffer{}}
+		assert.NoError(t, c.Confirm(map[string]delete.Plan{"prod": {}}))
+	})
+}
+
+// planOf plans the deletion of n configs of a single API
+func planOf(t *testing.T, n int) delete.Plan {
+	a := api.API{ID: "alerting-profile"}
+
+	var values []client.Value
+	var entries []delete.DeletePointer
+	for i := 0; i < n; i++ {
+		values = append(values, client.Value{Id: fmt.Sprintf("id-%d", i), Name: fmt.Sprintf("name-%d", i)})
+		entries = append(entries, delete.DeletePointer{Type: a.ID, ConfigId: fmt.Sprintf("name-%d", i)})
+	}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), a).Return(values, nil)
+
+	plan, errs := delete.PlanDeletion(context.TODO(), c, api.APIs{a.ID: a}, map[string][]delete.DeletePointer{a.ID: entries})
+	assert.Empty(t, errs)
+	return plan
+}
+
+func TestStageApproval_Approve(t *testing.T) {
+	t.Run("approved", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		a := StageApproval{In: strings.NewReader("yes\n"), Out: out}
+		assert.NoError(t, a.Approve(context.TODO(), "stage 2 (prod)"))
+		assert.Contains(t, out.String(), "Deploy stage 2 (prod)? [y/N]")
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		a := StageApproval{In: strings.NewReader("n\n"), Out: &bytes.Buffer{}}
+		assert.ErrorContains(t, a.Approve(context.TODO(), "stage 2 (prod)"), "stage 2 (prod) was not approved")
+	})
+
+	t.Run("input ends", func(t *testing.T) {
+		a := StageApproval{In: strings.NewReader(""), Out: &bytes.Buffer{}}
+		assert.ErrorContains(t, a.Approve(context.TODO(), "stage 2 (prod)"), "was not approved")
+	})
+
+	t.Run("no input", func(t *testing.T) {
+		assert.ErrorContains(t, StageApproval{}.Approve(context.TODO(), "stage 2 (prod)"), "input is not interactive")
+	})
+}
@@ -0,0 +1,65 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdutils
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"time"
+)
+
+// ReportRateLimits logs how often requests were rate limited since the statistics were last reset, if they were at all.
+func ReportRateLimits() {
+	stats := rest.RateLimitStatistics()
+	if stats.RateLimited == 0 {
+		return
+	}
+	log.Info("Requests were rate limited %d times and suspended for %s in total", stats.RateLimited, stats.Waited.Round(time.Second))
+}
+
+// ReportWarnings logs a report of all warnings logged since they were last reset, counting repeated warnings once. If
+// failOnWarning is set, an error is returned if there were any warnings.
+func ReportWarnings(failOnWarning bool) error {
+	warnings := log.Warnings()
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	var distinct []string
+	counts := make(map[string]int, len(warnings))
+	for _, w := range warnings {
+		if counts[w] == 0 {
+			distinct = append(distinct, w)
+		}
+		counts[w]++
+	}
+
+	log.Info("=== Warnings (%d) ===", len(warnings))
+	for _, w := range distinct {
+		if counts[w] > 1 {
+			log.Info("%s (%d times)", w, counts[w])
+		} else {
+			log.Info(w)
+		}
+	}
+
+	if failOnWarning {
+		return fmt.Errorf("%d warnings were logged and '--fail-on-warning' is set", len(warnings))
+	}
+	return nil
+}
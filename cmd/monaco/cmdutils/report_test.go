@@ -0,0 +1,39 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdutils
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReportWarnings(t *testing.T) {
+	log.ResetWarnings()
+	t.Cleanup(log.ResetWarnings)
+
+	assert.NoError(t, ReportWarnings(true), "no warnings were logged")
+
+	log.Warn("first")
+	log.Warn("second")
+	log.Warn("first")
+
+	assert.NoError(t, ReportWarnings(false))
+	assert.EqualError(t, ReportWarnings(true), "3 warnings were logged and '--fail-on-warning' is set")
+}
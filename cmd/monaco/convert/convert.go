@@ -57,7 +57,7 @@ func convert(fs afero.Fs, workingDir string, environmentsFile string, outputFold
 		SourceManifestPath: manifestPath,
 		OutputDir:          outputFolder,
 		ManifestName:       manifestName,
-		ParametersSerde:    configv2.DefaultParameterParsers,
+		ParametersSerde:    configv2.ParameterParsers(),
 	}, man, projs)
 
 	if len(errs) > 0 {
@@ -22,15 +22,20 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"time"
 )
 
 func GetDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
 
 	var environments, groups []string
 	var manifestName string
+	var timeout time.Duration
 	var deleteFile string
+	var safeguard delete.Safeguard
+	var yes bool
 
 	deleteCmd = &cobra.Command{
 		Use:     "delete --manifest <manifest.yaml> --file <delete.yaml>",
@@ -50,7 +55,10 @@ func GetDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
 				return err
 			}
 
-			return Delete(fs, manifestName, deleteFile, environments, groups)
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return Delete(ctx, fs, manifestName, deleteFile, environments, groups, safeguard, cmdutils.NewDeletionConfirmation(cmd, yes))
 		},
 		ValidArgsFunction: completion.DeleteCompletion,
 	}
@@ -70,6 +78,9 @@ func GetDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
 			"This flag is mutually exclusive with '--group'. "+
 			"If this flag is specified, configuration will be deleted from all specified environments. "+
 			"If neither --groups nor --environment is present, all environments will be used for deletion")
+	cmdutils.AddTimeoutFlag(deleteCmd, &timeout)
+	cmdutils.AddSafeguardFlags(deleteCmd, &safeguard)
+	cmdutils.AddYesFlag(deleteCmd, &yes)
 
 	if err := deleteCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
 		log.Fatal("failed to setup CLI %v", err)
@@ -15,6 +15,7 @@
 package delete
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
@@ -25,11 +26,12 @@ import (
 	"path/filepath"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	"github.com/spf13/afero"
 )
 
-func Delete(fs afero.Fs, deploymentManifestPath string, deleteFile string, environmentNames []string, environmentGroups []string) error {
+func Delete(ctx context.Context, fs afero.Fs, deploymentManifestPath string, deleteFile string, environmentNames []string, environmentGroups []string, safeguard delete.Safeguard, confirmation cmdutils.DeletionConfirmation) error {
 
 	deploymentManifestPath = filepath.Clean(deploymentManifestPath)
 	deploymentManifestPath, manifestErr := filepath.Abs(deploymentManifestPath)
@@ -57,7 +59,7 @@ func Delete(fs afero.Fs, deploymentManifestPath string, deleteFile string, envir
 		return fmt.Errorf("encountered errors while parsing delete.yaml: %s", errs)
 	}
 
-	deleteErrors := deleteConfigs(maps.Values(manifest.Environments), apis, entriesToDelete)
+	deleteErrors := deleteConfigs(ctx, fs, maps.Values(manifest.Environments), manifest.HTTP, apis, entriesToDelete, safeguard, confirmation)
 
 	for _, e := range deleteErrors {
 		log.Error("Deletion error: %s", e)
@@ -68,29 +70,42 @@ func Delete(fs afero.Fs, deploymentManifestPath string, deleteFile string, envir
 	return nil
 }
 
-func deleteConfigs(environments []manifest.EnvironmentDefinition, apis api.APIs, entriesToDelete map[string][]delete.DeletePointer) (errors []error) {
+func deleteConfigs(ctx context.Context, fs afero.Fs, environments []manifest.EnvironmentDefinition, httpSettings manifest.HTTPSettings, apis api.APIs, entriesToDelete map[string][]delete.DeletePointer, safeguard delete.Safeguard, confirmation cmdutils.DeletionConfirmation) (errors []error) {
 
-	for _, env := range environments {
-		deleteErrors := deleteConfigForEnvironment(env, apis, entriesToDelete)
+	clients := make(map[string]client.Client, len(environments))
+	plans := make(map[string]delete.Plan, len(environments))
 
-		if deleteErrors != nil {
-			errors = append(errors, deleteErrors...)
+	for _, env := range environments {
+		dynatraceClient, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(httpSettings.ForEnvironment(env)))
+		if err == nil {
+			dynatraceClient, err = cmdutils.AuditClient(fs, dynatraceClient, env.Name)
+		}
+		if err != nil {
+			errors = append(errors, fmt.Errorf("It was not possible to create a client for env `%s` due to the following error: %w", env.Name, err))
+			continue
 		}
-	}
 
-	return errors
-}
+		log.Info("Collecting configs to delete for environment `%s`", env.Name)
 
-func deleteConfigForEnvironment(env manifest.EnvironmentDefinition, apis api.APIs, entriesToDelete map[string][]delete.DeletePointer) []error {
-	dynatraceClient, err := cmdutils.CreateDTClient(env.URL.Value, env.Auth, false)
+		plan, planErrors := delete.PlanDeletion(ctx, dynatraceClient, apis, entriesToDelete)
+		errors = append(errors, planErrors...)
 
-	if err != nil {
-		return []error{
-			fmt.Errorf("It was not possible to create a client for env `%s` due to the following error: %w", env.Name, err),
-		}
+		clients[env.Name] = dynatraceClient
+		plans[env.Name] = plan
 	}
 
-	log.Info("Deleting configs for environment `%s`", env.Name)
+	if err := safeguard.Check(plans); err != nil {
+		return append(errors, fmt.Errorf("aborted deletion without deleting anything. Use '--confirm' to delete anyway: %w", err))
+	}
+
+	if err := confirmation.Confirm(plans); err != nil {
+		return append(errors, err)
+	}
 
-	return delete.DeleteConfigs(dynatraceClient, apis, entriesToDelete)
+	for env, plan := range plans {
+		log.Info("Deleting %d configs for environment `%s`", plan.Count(), env)
+		errors = append(errors, plan.Execute(ctx, clients[env])...)
+	}
+
+	return errors
 }
@@ -0,0 +1,204 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Approver approves rollout stages requiring manual approval. Approve blocks until the given stage is approved, and
+// returns an error if it is rejected or the context is done.
+type Approver interface {
+	Approve(ctx context.Context, stage string) error
+}
+
+// Kinds of approvers, given as '<kind>' or '<kind>:<argument>' to ParseApprover
+const (
+	TerminalApproval = "terminal"
+	FileApproval     = "file"
+	HTTPApproval     = "http"
+)
+
+// approvalPollInterval is the interval the file of file approvals is checked in
+const approvalPollInterval = 5 * time.Second
+
+// EnvApprovalToken is the environment variable holding the token HTTP approval requests have to present as
+// 'Authorization: Bearer <token>'
+const EnvApprovalToken = "MONACO_APPROVAL_TOKEN"
+
+// ParseApprover returns the approver defined by the given value:
+//   - 'terminal' asks for confirmation on the terminal
+//   - 'file:<path>' waits for the given file to be created, and deletes it once the stage is approved. A file containing
+//     'reject' rejects the stage.
+//   - 'http:<address>' listens on the given address, e.g. ':8080', until the stage is approved by a POST request to
+//     '/approve', or rejected by a POST request to '/reject'. Addresses without a host are bound to the loopback
+//     interface. Requests need to be authorized with the token set in EnvApprovalToken.
+func ParseApprover(fs afero.Fs, value string, terminal cmdutils.StageApproval) (Approver, error) {
+	kind, arg, _ := strings.Cut(value, ":")
+	switch kind {
+	case TerminalApproval:
+		if arg != "" {
+			break
+		}
+		return terminal, nil
+	case FileApproval:
+		if arg == "" {
+			break
+		}
+		return fileApprover{fs: fs, path: arg, interval: approvalPollInterval}, nil
+	case HTTPApproval:
+		if arg == "" {
+			break
+		}
+		token := os.Getenv(EnvApprovalToken)
+		if token == "" {
+			return nil, fmt.Errorf("approval %q requires a token set in the environment variable %q", value, EnvApprovalToken)
+		}
+		return httpApprover{address: loopbackAddress(arg), token: token}, nil
+	}
+	return nil, fmt.Errorf("invalid approval %q! expected one of '%s', '%s:<path>' or '%s:<address>'", value, TerminalApproval, FileApproval, HTTPApproval)
+}
+
+// rejectingApprover rejects all stages. It is used if no approver is configured, e.g. by 'monaco serve' which deploys
+// unattended, so rollouts halt before stages requiring approval instead of deploying them.
+type rejectingApprover struct{}
+
+func (rejectingApprover) Approve(_ context.Context, stage string) error {
+	return fmt.Errorf("%s requires approval, but no approval is configured", stage)
+}
+
+// loopbackAddress binds addresses without a host, e.g. ':8080', to the loopback interface. To accept approvals from
+// other hosts, the interface has to be given explicitly, e.g. '0.0.0.0:8080'.
+func loopbackAddress(address string) string {
+	if host, port, err := net.SplitHostPort(address); err == nil && host == "" {
+		return net.JoinHostPort("127.0.0.1", port)
+	}
+	return address
+}
+
+// fileApprover approves stages once a file is created, e.g. by a separate pipeline step requiring sign-off.
+type fileApprover struct {
+	fs       afero.Fs
+	path     string
+	interval time.Duration
+}
+
+func (a fileApprover) Approve(ctx context.Context, stage string) error {
+	log.Info("Waiting for %s to be approved by creating file %q", stage, a.path)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		data, err := afero.ReadFile(a.fs, a.path)
+		if err == nil {
+			// the file is removed, so the next stage requiring approval waits for it to be created again
+			if err := a.fs.Remove(a.path); err != nil {
+				return fmt.Errorf("failed to remove approval file %q: %w", a.path, err)
+			}
+			if strings.TrimSpace(string(data)) == "reject" {
+				return fmt.Errorf("%s was rejected", stage)
+			}
+			log.Info("%s was approved", stage)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s was not approved: %w", stage, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// httpApprover approves stages once a request to approve them is received.
+type httpApprover struct {
+	address string
+	token   string
+}
+
+func (a httpApprover) Approve(ctx context.Context, stage string) error {
+	listener, err := net.Listen("tcp", a.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen for approval of %s: %w", stage, err)
+	}
+
+	decisions := make(chan bool, 1)
+	server := &http.Server{Handler: approvalHandler(stage, a.token, decisions), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Failed to serve approval requests: %s", err)
+		}
+	}()
+	defer server.Close()
+
+	log.Info("Waiting for %s to be approved by a POST request to 'http://%s/approve'", stage, listener.Addr())
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%s was not approved: %w", stage, ctx.Err())
+	case approved := <-decisions:
+		if !approved {
+			return fmt.Errorf("%s was rejected", stage)
+		}
+		log.Info("%s was approved", stage)
+		return nil
+	}
+}
+
+// approvalHandler answers the requests approving or rejecting the given stage, sending the decision to the given
+// channel. GET requests return the stage waiting for approval. Requests not authorized with the given token are
+// refused.
+func approvalHandler(stage string, token string, decisions chan<- bool) http.Handler {
+	decide := func(approved bool, decision string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			select {
+			case decisions <- approved:
+				_, _ = fmt.Fprintf(w, "%s %s\n", stage, decision)
+			default:
+				w.WriteHeader(http.StatusConflict)
+				_, _ = fmt.Fprintf(w, "%s was already decided\n", stage)
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s is waiting for approval\n", stage)
+	})
+	mux.Handle("/approve", decide(true, "approved"))
+	mux.Handle("/reject", decide(false, "rejected"))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
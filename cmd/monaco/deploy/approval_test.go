@@ -0,0 +1,145 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseApprover(t *testing.T) {
+	t.Setenv(EnvApprovalToken, "secret")
+	fs := afero.NewMemMapFs()
+	terminal := cmdutils.StageApproval{}
+
+	tests := []struct {
+		value   string
+		want    Approver
+		wantErr bool
+	}{
+		{value: "terminal", want: terminal},
+		{value: "file:approvals/prod", want: fileApprover{fs: fs, path: "approvals/prod", interval: approvalPollInterval}},
+		{value: "http::8080", want: httpApprover{address: "127.0.0.1:8080", token: "secret"}},
+		{value: "http:0.0.0.0:8080", want: httpApprover{address: "0.0.0.0:8080", token: "secret"}},
+		{value: "file", wantErr: true},
+		{value: "http:", wantErr: true},
+		{value: "terminal:now", wantErr: true},
+		{value: "slack:channel", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseApprover(fs, tt.value, terminal)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("http approval requires a token", func(t *testing.T) {
+		t.Setenv(EnvApprovalToken, "")
+		_, err := ParseApprover(fs, "http::8080", terminal)
+		assert.ErrorContains(t, err, EnvApprovalToken)
+	})
+}
+
+func TestFileApprover_Approve(t *testing.T) {
+	t.Run("approved once the file exists", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		a := fileApprover{fs: fs, path: "approved", interval: time.Millisecond}
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			_ = afero.WriteFile(fs, "approved", nil, 0644)
+		}()
+
+		assert.NoError(t, a.Approve(context.TODO(), "stage 2 (prod)"))
+		exists, _ := afero.Exists(fs, "approved")
+		assert.False(t, exists, "the file is removed after the approval")
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "approved", []byte("reject\n"), 0644))
+		a := fileApprover{fs: fs, path: "approved", interval: time.Millisecond}
+
+		assert.ErrorContains(t, a.Approve(context.TODO(), "stage 2 (prod)"), "stage 2 (prod) was rejected")
+	})
+
+	t.Run("context done before approval", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		a := fileApprover{fs: afero.NewMemMapFs(), path: "approved", interval: time.Millisecond}
+
+		assert.ErrorContains(t, a.Approve(ctx, "stage 2 (prod)"), "stage 2 (prod) was not approved")
+	})
+}
+
+func TestRejectingApprover_Approve(t *testing.T) {
+	err := rejectingApprover{}.Approve(context.TODO(), "stage 2 (prod)")
+	assert.ErrorContains(t, err, "stage 2 (prod) requires approval, but no approval is configured")
+}
+
+func Test_approvalHandler(t *testing.T) {
+	decisions := make(chan bool, 1)
+	handler := approvalHandler("stage 2 (prod)", "secret", decisions)
+	request := func(method, target string, token string) *http.Request {
+		r := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		return r
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodPost, "/approve", ""))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodPost, "/approve", "guessed"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, decisions, "unauthorized requests don't decide")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodGet, "/", "secret"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "stage 2 (prod) is waiting for approval")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodGet, "/approve", "secret"))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodPost, "/reject", "secret"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, <-decisions)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodPost, "/approve", "secret"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, request(http.MethodPost, "/approve", "secret"))
+	assert.Equal(t, http.StatusConflict, rec.Code, "only the first decision counts")
+	assert.True(t, <-decisions)
+}
@@ -17,52 +17,100 @@
 package deploy
 
 import (
+	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
-	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"time"
 )
 
 func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
-	var dryRun, continueOnError bool
-	var manifestName string
-	var environment, project, groups []string
+	var opts Options
+	var timeout time.Duration
+	var deploymentEvent, packagePath, verifyKey, approval string
 
 	deployCmd = &cobra.Command{
-		Use:               "deploy <manifest.yaml>",
+		Use:               "deploy [<manifest.yaml>]",
 		Short:             "Deploy configurations to Dynatrace environments",
 		Example:           "monaco deploy manifest.yaml -v -e dev-environment",
-		Args:              cobra.ExactArgs(1),
+		Args:              cobra.RangeArgs(0, 1),
 		ValidArgsFunction: completion.DeployCompletion,
 		PreRun:            cmdutils.SilenceUsageCommand(),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if opts.DeploymentEvent, err = cmdutils.ParseEventType(deploymentEvent); err != nil {
+				return err
+			}
 
-			manifestName = args[0]
+			if opts.CheckIdempotency && !opts.DryRun {
+				return errors.New("'--check-idempotency' can only be used together with '--dry-run'")
+			}
 
-			if !files.IsYamlFileExtension(manifestName) {
-				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+			if opts.Approver, err = ParseApprover(fs, approval, cmdutils.NewStageApproval(cmd)); err != nil {
 				return err
 			}
 
-			return deployConfigs(fs, manifestName, groups, environment, project, continueOnError, dryRun)
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			if packagePath != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("manifest %q can not be used together with '--package'", args[0])
+				}
+				return DeployPackage(ctx, fs, packagePath, verifyKey, opts)
+			}
+			if verifyKey != "" {
+				return errors.New("'--verify-key' can only be used together with '--package'")
+			}
+
+			manifestName, err := cmdutils.ResolveManifestPath(args, opts.ManifestFromEnv)
+			if err != nil {
+				return err
+			}
+			return Deploy(ctx, fs, manifestName, opts)
 		},
 	}
 
-	deployCmd.Flags().StringSliceVarP(&environment, "environment", "e", []string{},
+	deployCmd.Flags().StringSliceVarP(&opts.Environments, "environment", "e", []string{},
 		"Specify one (or multiple) environment(s) to deploy to. "+
 			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
 			"This flag is mutually exclusive with '--group'.")
-	deployCmd.Flags().StringSliceVarP(&groups, "group", "g", []string{},
+	deployCmd.Flags().StringSliceVarP(&opts.EnvironmentGroups, "group", "g", []string{},
 		"Specify one (or multiple) environmentGroup(s) to deploy to. "+
 			"To set multiple groups either repeat this flag, or seprate them using a comma (,). "+
 			"If this flag is specified, all environments within this group will be used for deployment. "+
 			"This flag is mutually exclusive with '--environment'")
-	deployCmd.Flags().StringSliceVarP(&project, "project", "p", make([]string, 0), "Project configuration to deploy (also deploys any dependent configurations)")
-	deployCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Switches to just validation instead of actual deployment")
-	deployCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "c", false, "Proceed deployment even if config upload fails")
+	deployCmd.Flags().StringSliceVarP(&opts.Projects, "project", "p", make([]string, 0), "Project configuration to deploy (also deploys any dependent configurations)")
+	deployCmd.Flags().BoolVarP(&opts.DryRun, "dry-run", "d", false, "Switches to just validation instead of actual deployment")
+	deployCmd.Flags().StringSliceVar(&opts.DisabledLintRules, "disable-rule", []string{}, "In dry-run mode, id(s) of lint rules not to apply, e.g. 'hardcoded-value'. "+
+		"To disable multiple rules either repeat this flag, or separate them using a comma (,)")
+	deployCmd.Flags().BoolVar(&opts.ValidateRemote, "validate-remote", false, "In dry-run mode, additionally validate configs against the configurations existing in the environments, e.g. to detect duplicate names. Requires valid credentials, but never writes to the environments")
+	deployCmd.Flags().BoolVar(&opts.ValidateScopes, "validate-scopes", false, "Verify that the entities settings are scoped to (e.g. HOST-1234567890ABCDEF) exist in the environments before deploying them. In dry-run mode, this requires valid credentials, but never writes to the environments")
+	deployCmd.Flags().BoolVarP(&opts.ContinueOnErr, "continue-on-error", "c", false, "Proceed deployment even if config upload fails")
+	deployCmd.Flags().Var(&opts.MaxFailures, "max-failures", "Proceed deployment if config uploads fail, but abort the deployment to an environment once more configs failed than this number (e.g. '5') or percentage of its configs (e.g. '10%')")
+	deployCmd.Flags().BoolVar(&opts.CheckIdempotency, "check-idempotency", false, "In dry-run mode, render each config twice and report configs whose renders differ, e.g. due to random values")
+	deployCmd.Flags().BoolVar(&opts.ResolveSkippedReferences, "resolve-skipped-references", false, "Look up skipped configs referenced by deployed configs in the environments by their externalId or name, instead of failing the configs referencing them. This allows skipping configs deployed in earlier runs, e.g. optional baseline projects")
+	deployCmd.Flags().BoolVar(&opts.CheckSchemaVersions, "check-schema-versions", false, "Compare the schema versions settings configs were downloaded with to the versions available in the environments, and warn about configs created with a different major version")
+	deployCmd.Flags().StringVar(&opts.SchemaMigrationsFile, "schema-migrations", "", "File defining the fields renamed between major versions of settings schemas. Settings configs created with an older major version are migrated before they are deployed. Implies '--check-schema-versions'")
+	deployCmd.Flags().IntVar(&opts.SettingsBatchSize, "settings-batch-size", 50, "Maximum number of settings objects of the same schema upserted in a single request. Objects a batch fails for are retried one by one. Set to 1 to upsert all objects one by one")
+	deployCmd.Flags().BoolVar(&opts.MarkOwnership, "mark-ownership", false, "Append a marker like '[managed by monaco – project:type:config]' to the description of deployed config API objects. Together with the externalIds of settings 2.0 objects, this allows downloading only objects managed or not managed by monaco via 'monaco download --ownership'")
+	deployCmd.Flags().BoolVar(&opts.Lock, "lock", false, "Acquire a deployment lock on each environment before deploying and release it afterwards, so concurrent deployments using '--lock' fail instead of interleaving. Locks not released within their lease are taken over")
+	deployCmd.Flags().DurationVar(&opts.LockLease, "lock-lease", deploy.DefaultLockLease, "Time after which a deployment lock that was not renewed is considered stale. Locks are renewed after a third of the lease while the deployment runs")
+	deployCmd.Flags().StringVar(&opts.LockHolder, "lock-holder", deploy.DefaultLockHolder(), "Identifies this deployment in the lock, e.g. the ID of a CI job")
+	deployCmd.Flags().StringVar(&approval, "approval", TerminalApproval, "How rollout stages requiring approval are approved: 'terminal' asks for confirmation, "+
+		"'file:<path>' waits for the file to be created (a file containing 'reject' rejects the stage), "+
+		"'http:<address>' listens on the address (e.g. ':8080', bound to loopback unless a host is given) for a POST request to '/approve' or '/reject', "+
+		"authorized by 'Authorization: Bearer <token>' with the token set in '"+EnvApprovalToken+"'")
+	deployCmd.Flags().StringVar(&packagePath, "package", "", "Deploy the manifest and projects of a package built by 'monaco package' instead of a manifest file")
+	deployCmd.Flags().StringVar(&verifyKey, "verify-key", "", "Ed25519 public key in PEM format. The package is only deployed if its signature ('<package>.sig') was created with the matching private key")
+	deployCmd.Flags().BoolVar(&opts.Strict, "strict", false, "Fail on any unknown key in config files. By default, unknown keys in leniently parsed sections like the 'type' of a config are only warned about")
+	cmdutils.AddTimeoutFlag(deployCmd, &timeout)
+	cmdutils.AddManifestFromEnvFlag(deployCmd, &opts.ManifestFromEnv)
+	cmdutils.AddDeploymentEventFlag(deployCmd, &deploymentEvent)
 
 	err := deployCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag)
 	if err != nil {
@@ -75,6 +123,8 @@ func GetDeployCommand(fs afero.Fs) (deployCmd *cobra.Command) {
 	}
 
 	deployCmd.MarkFlagsMutuallyExclusive("environment", "group")
+	deployCmd.MarkFlagsMutuallyExclusive("package", "manifest-from-env")
+	deployCmd.MarkFlagsMutuallyExclusive("continue-on-error", "max-failures")
 
 	return deployCmd
 }
@@ -15,117 +15,384 @@
 package deploy
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/lint"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	configError "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/packaging"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemamigration"
 	"github.com/spf13/afero"
 )
 
-func deployConfigs(fs afero.Fs, manifestPath string, environmentGroups []string, specificEnvironments []string, specificProjects []string, continueOnErr bool, dryRun bool) error {
+// Options defines which projects are deployed to which environments by Deploy, and how.
+type Options struct {
+	// ManifestFromEnv defines that the manifest is created from environment variables instead of being read from a file
+	ManifestFromEnv bool
+	// EnvironmentGroups restricts the deployment to the given environment groups
+	EnvironmentGroups []string
+	// Environments restricts the deployment to the given environments
+	Environments []string
+	// Projects restricts the deployment to the given projects and their dependencies
+	Projects []string
+	// ContinueOnErr states that the deployment continues even if deploying a config fails
+	ContinueOnErr bool
+	// MaxFailures optionally defines how many configs may fail per environment before its deployment is aborted
+	MaxFailures deploy.FailureBudget
+	// DryRun states that configs are only validated instead of deployed
+	DryRun bool
+	// ValidateRemote states that in dry-run mode, configs are additionally validated against the objects existing in
+	// the environments. The environments are only read, never written.
+	ValidateRemote bool
+	// ValidateScopes states that the entities settings objects are scoped to are verified to exist before deploying them.
+	// In dry-run mode, the environments are read for this.
+	ValidateScopes bool
+	// DeploymentEvent optionally defines the type of event sent to each environment after a successful deployment
+	DeploymentEvent client.EventType
+	// Strict states that unknown keys in config files fail loading, instead of only being warned about if they are
+	// part of leniently decoded sections
+	Strict bool
+	// CheckIdempotency states that in dry-run mode, each config is rendered twice, reporting configs whose renders
+	// differ, e.g. due to random values
+	CheckIdempotency bool
+	// ResolveSkippedReferences states that skipped configs referenced by deployed configs are looked up in the
+	// environments, instead of failing the configs referencing them
+	ResolveSkippedReferences bool
+	// CheckSchemaVersions states that the schema versions of settings configs are compared with the versions
+	// available on the environments, warning about configs created with a different major version
+	CheckSchemaVersions bool
+	// SchemaMigrationsFile optionally defines the file of migrations applied to settings configs created with a
+	// different major version of their schema. It implies CheckSchemaVersions.
+	SchemaMigrationsFile string
+	// SchemaVersionLock defines the schema versions settings configs without explicit schema version are deployed with
+	SchemaVersionLock packaging.Lock
+	// SettingsBatchSize is the maximum number of settings objects upserted in a single request
+	SettingsBatchSize int
+	// MarkOwnership states that the descriptions of deployed config API objects are marked with the coordinate of
+	// their config, so they can be told apart from manually created objects
+	MarkOwnership bool
+	// Lock states that a deployment lock is acquired on each environment before deploying to it
+	Lock bool
+	// LockLease is the time after which a lock that was not released is considered stale
+	LockLease time.Duration
+	// LockHolder identifies this deployment in locks
+	LockHolder string
+	// Approver approves rollout stages requiring manual approval. If nil, these stages are rejected
+	Approver Approver
+	// DisabledLintRules holds the IDs of lint rules not applied to the configs validated by a dry-run
+	DisabledLintRules []string
+	// SkipRolloutWaits states that the stages of a rollout are deployed without waiting the time defined for them,
+	// e.g. when re-applying configs which were already rolled out
+	SkipRolloutWaits bool
+}
+
+// Deploy loads the given manifest and deploys all configurations of the (specified) projects to the (specified) environments.
+func Deploy(ctx context.Context, fs afero.Fs, manifestPath string, opts Options) error {
+	d, err := loadDeployment(ctx, fs, manifestPath, opts)
+	if err != nil {
+		return err
+	}
+
+	logProjectsInfo(d.projects)
+	logEnvironmentsInfo(d.manifest.Environments)
+	logCriticalPaths(d.configs)
+	if opts.DryRun {
+		if err := logLintFindings(fs, d.manifestPath, *d.manifest, d.projects, opts.DisabledLintRules); err != nil {
+			log.Warn("Failed to check configurations for likely mistakes: %v", err)
+		}
+	}
+
+	if d.manifest.Rollout != nil {
+		return doRollout(ctx, fs, d.configs, d.manifest.Environments, d.manifest.HTTP, *d.manifest.Rollout, opts)
+	}
+
+	if err = doDeploy(ctx, fs, d.configs, d.manifest.Environments, d.manifest.HTTP, opts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// deployment holds the manifest and the sorted configs of the (specified) projects to deploy.
+type deployment struct {
+	// manifestPath is the absolute path of the manifest
+	manifestPath string
+	manifest     *manifest.Manifest
+	projects     []project.Project
+	configs      project.ConfigsPerEnvironment
+}
+
+// loadDeployment loads the given manifest and the configs of the (specified) projects to deploy to the (specified)
+// environments, sorted in deployment order.
+func loadDeployment(ctx context.Context, fs afero.Fs, manifestPath string, opts Options) (deployment, error) {
 	absManifestPath, err := absPath(manifestPath)
 	if err != nil {
-		return fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+		return deployment{}, fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
 	}
-	loadedManifest, err := loadManifest(fs, absManifestPath, environmentGroups, specificEnvironments)
+	loadedManifest, err := loadManifest(ctx, fs, absManifestPath, opts.ManifestFromEnv, opts.EnvironmentGroups, opts.Environments)
 	if err != nil {
-		return err
+		return deployment{}, err
 	}
 
-	ok := verifyEnvironmentGen(loadedManifest.Environments, dryRun)
+	ok := verifyEnvironmentGen(ctx, loadedManifest.Environments, loadedManifest.Offline, opts.DryRun)
 	if !ok {
-		return fmt.Errorf("unable to verify Dynatrace environment generation")
+		return deployment{}, fmt.Errorf("unable to verify Dynatrace environment generation")
 	}
 
-	loadedProjects, err := loadProjects(fs, absManifestPath, loadedManifest)
+	loadedProjects, err := loadProjects(fs, absManifestPath, loadedManifest, opts.Strict)
 	if err != nil {
-		return err
+		return deployment{}, err
 	}
 
-	filteredProjects, err := filterProjects(loadedProjects, specificProjects, loadedManifest.Environments.Names())
+	applySchemaVersionLock(loadedProjects, opts.SchemaVersionLock)
+
+	filteredProjects, err := filterProjects(loadedProjects, opts.Projects, loadedManifest.Environments.Names())
 	if err != nil {
-		return fmt.Errorf("error while loading relevant projects to deploy: %w", err)
+		return deployment{}, fmt.Errorf("error while loading relevant projects to deploy: %w", err)
 	}
 
 	sortedConfigs, err := sortConfigs(filteredProjects, loadedManifest.Environments.Names())
 	if err != nil {
-		return fmt.Errorf("error during configuration sort: %w", err)
+		return deployment{}, fmt.Errorf("error during configuration sort: %w", err)
 	}
 
-	logProjectsInfo(filteredProjects)
-	logEnvironmentsInfo(loadedManifest.Environments)
+	return deployment{manifestPath: absManifestPath, manifest: loadedManifest, projects: filteredProjects, configs: sortedConfigs}, nil
+}
 
-	if err = doDeploy(sortedConfigs, loadedManifest.Environments, continueOnErr, dryRun); err != nil {
+// DeployPackage opens the package built by 'monaco package' and deploys its manifest like Deploy. Settings configs
+// without explicit schema version are deployed with the version locked by the package. If verifyKeyPath is set, the
+// package is only deployed if it was signed with the private key of the public key read from it.
+func DeployPackage(ctx context.Context, fs afero.Fs, packagePath string, verifyKeyPath string, opts Options) error {
+	p, err := openPackage(fs, packagePath, verifyKeyPath)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	log.Info("Deploying package %q (%s)", packagePath, p.Digest)
+	opts.SchemaVersionLock = p.Lock
+	return Deploy(ctx, p.Fs, p.ManifestPath, opts)
 }
 
-func doDeploy(configs project.ConfigsPerEnvironment, environments manifest.Environments, continueOnErr bool, dryRun bool) error {
-	var deployErrs []error
-	for envName, configs := range configs {
-		logDeploymentInfo(dryRun, envName)
-		env, found := environments[envName]
+func openPackage(fs afero.Fs, packagePath string, verifyKeyPath string) (packaging.Package, error) {
+	if verifyKeyPath == "" {
+		return packaging.Open(fs, packagePath)
+	}
 
-		if !found {
-			if continueOnErr {
-				deployErrs = append(deployErrs, fmt.Errorf("cannot find environment `%s`", envName))
-				continue
-			} else {
-				return fmt.Errorf("cannot find environment `%s`", envName)
+	key, err := packaging.LoadPublicKey(fs, verifyKeyPath)
+	if err != nil {
+		return packaging.Package{}, err
+	}
+	p, err := packaging.OpenVerified(fs, packagePath, key)
+	if err != nil {
+		return packaging.Package{}, err
+	}
+	log.Info("Verified signature of package %q", packagePath)
+	return p, nil
+}
+
+// applySchemaVersionLock sets the locked schema version of all settings configs without explicit schema version.
+func applySchemaVersionLock(projects []project.Project, lock packaging.Lock) {
+	if len(lock) == 0 {
+		return
+	}
+
+	for _, p := range projects {
+		for _, configsPerType := range p.Configs {
+			for _, configs := range configsPerType {
+				for i := range configs {
+					t, ok := configs[i].Type.(config.SettingsType)
+					if !ok || t.SchemaVersion != "" {
+						continue
+					}
+					if v, found := lock[t.SchemaId]; found {
+						t.SchemaVersion = v
+						configs[i].Type = t
+					}
+				}
 			}
 		}
+	}
+}
+
+func doDeploy(ctx context.Context, fs afero.Fs, configs project.ConfigsPerEnvironment, environments manifest.Environments, httpSettings manifest.HTTPSettings, opts Options) error {
+	var migrations schemamigration.Table
+	if opts.SchemaMigrationsFile != "" {
+		var err error
+		if migrations, err = schemamigration.Load(fs, opts.SchemaMigrationsFile); err != nil {
+			return err
+		}
+	}
 
-		dtClient, err := cmdutils.CreateDTClient(env.URL.Value, env.Auth, dryRun)
+	clients, deployErrs, err := createEnvironmentClients(ctx, fs, configs, environments, httpSettings, opts)
+	if err != nil {
+		return err
+	}
 
+	if opts.Lock && !opts.DryRun {
+		release, err := acquireLocks(ctx, clients, opts)
 		if err != nil {
-			if continueOnErr {
-				deployErrs = append(deployErrs, err)
-				continue
-			} else {
-				return err
-			}
+			return err
 		}
+		defer release()
+	}
 
-		errs := deploy.DeployConfigs(dtClient, api.NewAPIs(), configs, deploy.DeployConfigsOptions{
-			ContinueOnErr: continueOnErr,
-			DryRun:        dryRun,
-		})
-		deployErrs = append(deployErrs, errs...)
+	// environments without a client already reported an error and are left out
+	deployableConfigs := make(project.ConfigsPerEnvironment, len(clients))
+	for envName := range clients {
+		deployableConfigs[envName] = configs[envName]
 	}
 
+	deployErrs = append(deployErrs, deploy.DeployConfigsForEnvironments(ctx, deployableConfigs, clients, api.NewAPIs(), deploy.DeployConfigsOptions{
+		ContinueOnErr:            opts.ContinueOnErr,
+		MaxFailures:              opts.MaxFailures,
+		DryRun:                   opts.DryRun,
+		DeploymentEvent:          opts.DeploymentEvent,
+		CheckIdempotency:         opts.CheckIdempotency,
+		ResolveSkippedReferences: opts.ResolveSkippedReferences,
+		CheckSchemaVersions:      opts.CheckSchemaVersions || opts.SchemaMigrationsFile != "",
+		SchemaMigrations:         migrations,
+		SettingsBatchSize:        opts.SettingsBatchSize,
+	})...)
+
 	if deployErrs != nil {
 		printErrorReport(deployErrs)
-		return fmt.Errorf("errors during %s", getOperationNounForLogging(dryRun))
+		return fmt.Errorf("errors during %s", getOperationNounForLogging(opts.DryRun))
 	}
-	log.Info("%s finished without errors", getOperationNounForLogging(dryRun))
+	log.Info("%s finished without errors", getOperationNounForLogging(opts.DryRun))
 	return nil
 }
 
+// acquireLocks acquires the deployment lock of all environments. If any lock can not be acquired, the already acquired
+// ones are released. The returned function releases all locks.
+func acquireLocks(ctx context.Context, clients deploy.EnvironmentClients, opts Options) (func(), error) {
+	var locks []*deploy.Lock
+	var lockCtxs []context.Context
+	release := func() {
+		for i, l := range locks {
+			if err := l.Release(lockCtxs[i]); err != nil {
+				log.WithCtxFields(lockCtxs[i]).Error("Failed to release deployment lock: %s", err)
+			}
+		}
+	}
+
+	envNames := maps.Keys(clients)
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		envCtx := log.WithFields(ctx, log.EnvironmentField(envName))
+		l, err := deploy.AcquireLock(envCtx, clients[envName], opts.LockHolder, opts.LockLease)
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("failed to lock environment %q: %w", envName, err)
+		}
+		locks = append(locks, l)
+		lockCtxs = append(lockCtxs, envCtx)
+	}
+	return release, nil
+}
+
+// createEnvironmentClients creates a client for each environment configs are deployed to. If ContinueOnErr or
+// MaxFailures is set, errors are collected and the environment is left out, otherwise the first error is returned
+// directly.
+func createEnvironmentClients(ctx context.Context, fs afero.Fs, configs project.ConfigsPerEnvironment, environments manifest.Environments, httpSettings manifest.HTTPSettings, opts Options) (deploy.EnvironmentClients, []error, error) {
+	clients := make(deploy.EnvironmentClients, len(configs))
+	var errs []error
+	// like deploy.DeployConfigsForEnvironments, the deployment to other environments proceeds if failures are tolerated
+	tolerateErrs := opts.ContinueOnErr || opts.MaxFailures.IsSet()
+
+	for envName := range configs {
+		env, found := environments[envName]
+		if !found {
+			err := fmt.Errorf("cannot find environment `%s`", envName)
+			if !tolerateErrs {
+				return nil, nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		// the client is only used for this deployment, thus the configs listed to look up existing configs by name are
+		// cached instead of being listed for every config
+		dtClient, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, opts.DryRun, cmdutils.WithHTTPSettings(httpSettings.ForEnvironment(env)), client.WithNameLookupCache())
+		if err == nil && !opts.DryRun {
+			dtClient, err = cmdutils.AuditClient(fs, dtClient, envName)
+		}
+		if err == nil {
+			dtClient, err = withValidations(ctx, dtClient, env, httpSettings, opts)
+		}
+		if err == nil && opts.MarkOwnership {
+			dtClient = deploy.WithOwnershipMarkers(dtClient)
+		}
+		if err != nil {
+			if !tolerateErrs {
+				return nil, nil, err
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		clients[envName] = dtClient
+	}
+
+	return clients, errs, nil
+}
+
+// withValidations decorates the given client with the validations enabled in opts. In dry-run mode, the environment is
+// read using an additional client.
+func withValidations(ctx context.Context, c client.Client, env manifest.EnvironmentDefinition, httpSettings manifest.HTTPSettings, opts Options) (client.Client, error) {
+	validateRemote := opts.DryRun && opts.ValidateRemote
+	if !validateRemote && !opts.ValidateScopes {
+		return c, nil
+	}
+
+	remote := c
+	if opts.DryRun {
+		var err error
+		if remote, err = cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(httpSettings.ForEnvironment(env))); err != nil {
+			return nil, err
+		}
+	}
+
+	if validateRemote {
+		c = deploy.WithRemoteNameValidation(c, remote)
+	}
+	if opts.ValidateScopes {
+		c = deploy.WithScopeValidation(c, remote)
+	}
+	return c, nil
+}
+
 func absPath(manifestPath string) (string, error) {
 	manifestPath = filepath.Clean(manifestPath)
 	return filepath.Abs(manifestPath)
 }
 
-func loadManifest(fs afero.Fs, manifestPath string, groups []string, environments []string) (*manifest.Manifest, error) {
+// loadManifest loads the manifest, discovering the environments of groups defining a discovery.
+func loadManifest(ctx context.Context, fs afero.Fs, manifestPath string, fromEnv bool, groups []string, environments []string) (*manifest.Manifest, error) {
 	m, errs := manifest.LoadManifest(&manifest.LoaderContext{
 		Fs:           fs,
 		ManifestPath: manifestPath,
 		Groups:       groups,
 		Environments: environments,
+		FromEnv:      fromEnv,
+		Discover:     true,
+		Context:      ctx,
 	})
 
 	if len(errs) > 0 {
@@ -136,20 +403,21 @@ func loadManifest(fs afero.Fs, manifestPath string, groups []string, environment
 	return &m, nil
 }
 
-func verifyEnvironmentGen(environments manifest.Environments, dryRun bool) bool {
+func verifyEnvironmentGen(ctx context.Context, environments manifest.Environments, offline manifest.OfflineSettings, dryRun bool) bool {
 	if !dryRun {
-		return cmdutils.VerifyEnvironmentGeneration(environments)
+		return cmdutils.VerifyEnvironmentGeneration(ctx, environments, offline)
 
 	}
 	return true
 }
 
-func loadProjects(fs afero.Fs, manifestPath string, man *manifest.Manifest) ([]project.Project, error) {
+func loadProjects(fs afero.Fs, manifestPath string, man *manifest.Manifest, strict bool) ([]project.Project, error) {
 	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
 		KnownApis:       api.NewAPIs().GetApiNameLookup(),
 		WorkingDir:      filepath.Dir(manifestPath),
 		Manifest:        *man,
-		ParametersSerde: config.DefaultParameterParsers,
+		ParametersSerde: config.ParameterParsers(),
+		Strict:          strict,
 	})
 
 	if errs != nil {
@@ -197,20 +465,51 @@ func logProjectsInfo(projects []project.Project) {
 	}
 }
 
+// logCriticalPaths logs the longest dependency chain of each environment, as it limits the speed of the deployment.
+func logCriticalPaths(configs project.ConfigsPerEnvironment) {
+	envNames := maps.Keys(configs)
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		path := topologysort.GetCriticalPath(configs[envName])
+		if len(path) < 2 {
+			continue
+		}
+
+		coordinates := make([]string, len(path))
+		for i, c := range path {
+			coordinates[i] = c.String()
+		}
+		log.Info("Longest dependency chain of environment `%s` (%d configs): %s", envName, len(path), strings.Join(coordinates, " -> "))
+	}
+}
+
+// logLintFindings logs likely mistakes in the configs validated by a dry-run, except for the ones of the given disabled
+// rules. Findings are logged as information, so they don't fail deployments using '--fail-on-warning'. Use the lint
+// command to check configs in detail.
+func logLintFindings(fs afero.Fs, manifestPath string, m manifest.Manifest, projects []project.Project, disabledRules []string) error {
+	known, err := cmdutils.LoadKnownObjects(fs, manifestPath, m, projects)
+	if err != nil {
+		return err
+	}
+
+	rules := append([]lint.Rule{}, lint.DefaultRules...)
+	rules = append(rules, lint.ReferenceSuggestionRule(known))
+	if rules, err = lint.WithoutRules(rules, disabledRules); err != nil {
+		return err
+	}
+	for _, f := range lint.Lint(projects, rules) {
+		log.Info("Possible mistake: %s", f)
+	}
+	return nil
+}
+
 func logEnvironmentsInfo(environments manifest.Environments) {
 	log.Info("Environments to deploy to:")
 	for _, name := range environments.Names() {
 		log.Info("  - %s", name)
 	}
 }
-func logDeploymentInfo(dryRun bool, envName string) {
-	if dryRun {
-		log.Info("Validating configurations for environment `%s`...", envName)
-	} else {
-		log.Info("Deploying configurations to environment `%s`...", envName)
-	}
-}
-
 func getOperationNounForLogging(dryRun bool) string {
 	if dryRun {
 		return "Validation"
@@ -238,6 +537,9 @@ func printErrorReport(deploymentErrors []error) {
 		}
 	}
 
+	clusters, configErrors := clusterByRootCause(configErrors)
+	printRootCauseClusters(clusters)
+
 	groupedConfigErrors := groupConfigErrors(configErrors)
 
 	for project, apiErrors := range groupedConfigErrors {
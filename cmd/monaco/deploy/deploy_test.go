@@ -17,9 +17,15 @@
 package deploy
 
 import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	p "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -270,7 +276,7 @@ func Test_DoDeploy_InvalidManifest(t *testing.T) {
 	manifestPath, _ := filepath.Abs("manifest.yaml")
 	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
 
-	err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, true, true)
+	err := Deploy(context.TODO(), testFs, manifestPath, Options{ContinueOnErr: true, DryRun: true})
 	assert.Error(t, err)
 }
 
@@ -311,27 +317,112 @@ environmentGroups:
 	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
 
 	t.Run("Wrong environment group", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"NOT_EXISTING_GROUP"}, []string{}, []string{}, true, true)
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{EnvironmentGroups: []string{"NOT_EXISTING_GROUP"}, ContinueOnErr: true, DryRun: true})
 		assert.Error(t, err)
 	})
 	t.Run("Wrong environment name", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"NOT_EXISTING_ENV"}, []string{}, true, true)
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{EnvironmentGroups: []string{"default"}, Environments: []string{"NOT_EXISTING_ENV"}, ContinueOnErr: true, DryRun: true})
 		assert.Error(t, err)
 	})
 
 	t.Run("Wrong project name", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"NON_EXISTING_PROJECT"}, true, true)
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{EnvironmentGroups: []string{"default"}, Environments: []string{"project"}, Projects: []string{"NON_EXISTING_PROJECT"}, ContinueOnErr: true, DryRun: true})
 		assert.Error(t, err)
 	})
 
 	t.Run("no parameters", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{}, []string{}, []string{}, true, true)
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{ContinueOnErr: true, DryRun: true})
 		assert.NoError(t, err)
 	})
 
 	t.Run("correct parameters", func(t *testing.T) {
-		err := deployConfigs(testFs, manifestPath, []string{"default"}, []string{"project"}, []string{"project"}, true, true)
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{EnvironmentGroups: []string{"default"}, Environments: []string{"project"}, Projects: []string{"project"}, ContinueOnErr: true, DryRun: true})
 		assert.NoError(t, err)
 	})
 
 }
+
+func Test_createEnvironmentClients_UnknownEnvironment(t *testing.T) {
+	configs := p.ConfigsPerEnvironment{"unknown": nil}
+
+	t.Run("fails without tolerated failures", func(t *testing.T) {
+		_, _, err := createEnvironmentClients(context.TODO(), afero.NewMemMapFs(), configs, manifest.Environments{}, manifest.HTTPSettings{}, Options{})
+		assert.EqualError(t, err, "cannot find environment `unknown`")
+	})
+
+	t.Run("environment is left out with max failures", func(t *testing.T) {
+		budget, err := deploy.ParseFailureBudget("1")
+		assert.NoError(t, err)
+
+		clients, errs, err := createEnvironmentClients(context.TODO(), afero.NewMemMapFs(), configs, manifest.Environments{}, manifest.HTTPSettings{}, Options{MaxFailures: budget})
+		assert.NoError(t, err)
+		assert.Empty(t, clients)
+		assert.Len(t, errs, 1)
+	})
+}
+
+func Test_DoDeploy_HardcodedValuesDontFail(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		switch req.Method {
+		case http.MethodGet:
+			_, _ = rw.Write([]byte(`{"values": [], "version": "1.270.0.20230101-000000"}`))
+		default:
+			_, _ = rw.Write([]byte(`{"id": "a4ff7a3b-0b4c-4e5a-8d36-0a3e3bd1a2b8", "name": "alerting-profile"}`))
+		}
+	}))
+	defer server.Close()
+
+	manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: ` + server.URL + `
+    auth:
+      token:
+        name: ENV_TOKEN
+`
+	configYaml := `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    api: alerting-profile
+`
+	testFs := afero.NewMemMapFs()
+	configPath, _ := filepath.Abs("project/alerting-profile/profile.yaml")
+	_ = afero.WriteFile(testFs, configPath, []byte(configYaml), 0644)
+	templatePath, _ := filepath.Abs("project/alerting-profile/profile.json")
+	_ = afero.WriteFile(testFs, templatePath, []byte(`{"name": "{{ .name }}", "recipient": "ops@example.com"}`), 0644)
+	manifestPath, _ := filepath.Abs("manifest.yaml")
+	_ = afero.WriteFile(testFs, manifestPath, []byte(manifestYaml), 0644)
+
+	// lint findings must not fail deployments using '--fail-on-warning'
+	assertNoLintWarnings := func(t *testing.T) {
+		for _, w := range log.Warnings() {
+			assert.NotContains(t, w, "ops@example.com")
+		}
+	}
+	t.Cleanup(log.ResetWarnings)
+
+	t.Run("deployment succeeds", func(t *testing.T) {
+		log.ResetWarnings()
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{})
+		assert.NoError(t, err)
+		assertNoLintWarnings(t)
+	})
+
+	t.Run("dry-run succeeds", func(t *testing.T) {
+		log.ResetWarnings()
+		err := Deploy(context.TODO(), testFs, manifestPath, Options{DryRun: true, DisabledLintRules: []string{"unused-parameter"}})
+		assert.NoError(t, err)
+		assertNoLintWarnings(t)
+	})
+}
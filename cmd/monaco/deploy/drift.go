@@ -0,0 +1,74 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	"github.com/spf13/afero"
+	"sort"
+)
+
+// ReportDrift loads the given manifest like Deploy, and reports the configs of the (specified) projects whose objects
+// in the (specified) environments are missing or differ from them, without changing the environments. It returns the
+// number of drifted configs. See deploy.DetectDrift for which configs are compared.
+func ReportDrift(ctx context.Context, fs afero.Fs, manifestPath string, opts Options) (int, error) {
+	opts.DryRun = false
+	d, err := loadDeployment(ctx, fs, manifestPath, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	apis := api.NewAPIs()
+	drifted := 0
+	var errs []error
+
+	envNames := maps.Keys(d.configs)
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		envCtx := log.WithFields(ctx, log.EnvironmentField(envName))
+
+		env, found := d.manifest.Environments[envName]
+		if !found {
+			errs = append(errs, fmt.Errorf("cannot find environment `%s`", envName))
+			continue
+		}
+		c, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(d.manifest.HTTP.ForEnvironment(env)))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		log.WithCtxFields(envCtx).Info("Detecting drift of configurations in environment `%s`...", envName)
+		drifts, detectErrs := deploy.DetectDrift(envCtx, c, apis, d.configs[envName])
+		for _, drift := range drifts {
+			log.WithCtxFields(envCtx).Warn("Config %s drifted in environment `%s`: %s", drift.Coordinate, envName, drift.Reason)
+		}
+		drifted += len(drifts)
+		errs = append(errs, detectErrs...)
+	}
+
+	if len(errs) > 0 {
+		printErrorReport(errs)
+		return drifted, fmt.Errorf("errors during drift detection")
+	}
+	log.Info("Drift detection finished: %d config(s) drifted", drifted)
+	return drifted, nil
+}
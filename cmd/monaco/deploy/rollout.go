@@ -0,0 +1,125 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rolloutStage holds the environments and configs deployed by a stage of a rollout.
+type rolloutStage struct {
+	manifest.RolloutStage
+	name         string
+	environments manifest.Environments
+	configs      project.ConfigsPerEnvironment
+}
+
+// doRollout deploys the given configs stage by stage as defined by the rollout policy. Stages without environments to
+// deploy to are skipped. The rollout halts at the first stage failing, or not being approved. Without an approver in the
+// options, stages requiring approval are rejected.
+func doRollout(ctx context.Context, fs afero.Fs, configs project.ConfigsPerEnvironment, environments manifest.Environments, httpSettings manifest.HTTPSettings, policy manifest.RolloutPolicy, opts Options) error {
+	stages := planRollout(configs, environments, policy)
+
+	approver := opts.Approver
+	if approver == nil {
+		approver = rejectingApprover{}
+	}
+
+	for i, stage := range stages {
+		envNames := stage.environments.Names()
+		sort.Strings(envNames)
+		log.Info("Rollout %s: deploying to environments %s", stage.name, strings.Join(envNames, ", "))
+
+		if !opts.DryRun {
+			// earlier stages surfacing problems is what waiting is for, thus there is nothing to wait for if no stage
+			// was deployed before. Approvals are required regardless, e.g. if only the environments of a later stage
+			// are deployed.
+			if i > 0 && !opts.SkipRolloutWaits {
+				if err := waitForStage(ctx, stage); err != nil {
+					return err
+				}
+			}
+			if stage.Approval {
+				if err := approver.Approve(ctx, stage.name); err != nil {
+					return fmt.Errorf("rollout halted before %s: %w", stage.name, err)
+				}
+			}
+		}
+
+		if err := doDeploy(ctx, fs, stage.configs, stage.environments, httpSettings, opts); err != nil {
+			if i < len(stages)-1 {
+				return fmt.Errorf("rollout halted at %s: %w", stage.name, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// planRollout splits the given configs and environments into the stages of the rollout policy, leaving out stages
+// without environments.
+func planRollout(configs project.ConfigsPerEnvironment, environments manifest.Environments, policy manifest.RolloutPolicy) []rolloutStage {
+	var stages []rolloutStage
+	for i, s := range policy.Stages {
+		stage := rolloutStage{
+			RolloutStage: s,
+			name:         fmt.Sprintf("stage %d (%s)", i+1, strings.Join(s.Groups, ", ")),
+			environments: make(manifest.Environments),
+			configs:      make(project.ConfigsPerEnvironment),
+		}
+
+		for name, env := range environments {
+			if !containsName(s.Groups, env.Group) {
+				continue
+			}
+			stage.environments[name] = env
+			if c, found := configs[name]; found {
+				stage.configs[name] = c
+			}
+		}
+
+		if len(stage.environments) == 0 {
+			log.Debug("Rollout %s has no environments to deploy to", stage.name)
+			continue
+		}
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
+// waitForStage waits the time defined for the given stage, or until the context is done.
+func waitForStage(ctx context.Context, stage rolloutStage) error {
+	if stage.Wait <= 0 {
+		return nil
+	}
+
+	log.Info("Rollout %s: waiting %s before deploying", stage.name, stage.Wait)
+	timer := time.NewTimer(stage.Wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("rollout halted while waiting for %s: %w", stage.name, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
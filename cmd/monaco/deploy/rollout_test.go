@@ -0,0 +1,77 @@
+//go:build unit
+
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func Test_planRollout(t *testing.T) {
+	environments := manifest.Environments{
+		"dev1":  {Name: "dev1", Group: "dev"},
+		"dev2":  {Name: "dev2", Group: "dev"},
+		"prod1": {Name: "prod1", Group: "prod"},
+	}
+	configs := project.ConfigsPerEnvironment{
+		"dev1":  nil,
+		"dev2":  nil,
+		"prod1": nil,
+	}
+	policy := manifest.RolloutPolicy{Stages: []manifest.RolloutStage{
+		{Groups: []string{"dev"}},
+		{Groups: []string{"staging"}},
+		{Groups: []string{"prod"}, Approval: true},
+	}}
+
+	stages := planRollout(configs, environments, policy)
+
+	assert.Len(t, stages, 2, "stages without environments are left out")
+	assert.Equal(t, "stage 1 (dev)", stages[0].name)
+	assert.ElementsMatch(t, []string{"dev1", "dev2"}, stages[0].environments.Names())
+	assert.Len(t, stages[0].configs, 2)
+	assert.Equal(t, "stage 3 (prod)", stages[1].name)
+	assert.ElementsMatch(t, []string{"prod1"}, stages[1].environments.Names())
+	assert.True(t, stages[1].Approval)
+}
+
+func Test_doRollout_ApprovesStagesOfSelectedGroups(t *testing.T) {
+	environments := manifest.Environments{
+		"prod1": {Name: "prod1", Group: "prod"},
+	}
+	policy := manifest.RolloutPolicy{Stages: []manifest.RolloutStage{
+		{Groups: []string{"dev"}},
+		{Groups: []string{"prod"}, Approval: true},
+	}}
+
+	// deploying only the 'prod' group still requires its approval, without an approver the stage is rejected
+	err := doRollout(context.TODO(), afero.NewMemMapFs(), project.ConfigsPerEnvironment{"prod1": nil}, environments, manifest.HTTPSettings{}, policy, Options{EnvironmentGroups: []string{"prod"}})
+	assert.ErrorContains(t, err, "rollout halted before stage 2 (prod)")
+}
+
+func Test_waitForStage_StopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := waitForStage(ctx, rolloutStage{RolloutStage: manifest.RolloutStage{Wait: time.Hour}, name: "stage 2 (prod)"})
+	assert.ErrorContains(t, err, "rollout halted while waiting for stage 2 (prod)")
+}
@@ -0,0 +1,125 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	configError "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+)
+
+// maxListedConfigs is the maximum number of configs listed per root cause
+const maxListedConfigs = 10
+
+// rootCause identifies errors which most likely have the same cause: they share the HTTP status code, if any, and
+// their messages only differ in identifiers and numbers. Other details, e.g. the quoted response of the environment,
+// need to match, as they tell different causes apart.
+type rootCause struct {
+	statusCode string
+	message    string
+}
+
+type rootCauseCluster struct {
+	cause  rootCause
+	errors []configError.ConfigError
+}
+
+var (
+	statusCodePattern = regexp.MustCompile(`HTTP:? \(?(\d{3})\)?`)
+	// identifierPattern matches UUIDs, entity IDs, external IDs generated by monaco, settings object IDs and numbers
+	identifierPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[A-Z_]+-[0-9A-F]{8,}|monaco:[A-Za-z0-9+/]+=*|\b[A-Za-z0-9_-]{24,}\b|\d+`)
+)
+
+func rootCauseOf(err error) rootCause {
+	msg := errutils.ErrorString(err)
+
+	var statusCode string
+	if m := statusCodePattern.FindStringSubmatch(msg); m != nil {
+		statusCode = m[1]
+	}
+
+	msg = identifierPattern.ReplaceAllString(msg, "<id>")
+	return rootCause{statusCode: statusCode, message: msg}
+}
+
+// clusterByRootCause returns all root causes shared by several errors, in descending order of their number of errors.
+// Errors not sharing their root cause with another error are returned separately.
+func clusterByRootCause(errs []configError.ConfigError) ([]rootCauseCluster, []configError.ConfigError) {
+	var order []rootCause
+	byCause := make(map[rootCause][]configError.ConfigError)
+	for _, err := range errs {
+		c := rootCauseOf(err)
+		if _, found := byCause[c]; !found {
+			order = append(order, c)
+		}
+		byCause[c] = append(byCause[c], err)
+	}
+
+	var clusters []rootCauseCluster
+	var unique []configError.ConfigError
+	for _, c := range order {
+		if len(byCause[c]) > 1 {
+			clusters = append(clusters, rootCauseCluster{cause: c, errors: byCause[c]})
+		} else {
+			unique = append(unique, byCause[c]...)
+		}
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].errors) > len(clusters[j].errors)
+	})
+	return clusters, unique
+}
+
+// printRootCauseClusters logs each cluster once, with one representative error and the configs failing with it.
+func printRootCauseClusters(clusters []rootCauseCluster) {
+	if len(clusters) == 0 {
+		return
+	}
+
+	log.Error("=== Errors grouped by root cause ===")
+	for _, c := range clusters {
+		status := ""
+		if c.cause.statusCode != "" {
+			status = fmt.Sprintf(" with HTTP %s", c.cause.statusCode)
+		}
+		log.Error("%d configs failed%s. Example: %s", len(c.errors), status, errutils.ErrorString(c.errors[0]))
+		log.Error("\tAffected configs: %s", affectedConfigs(c.errors))
+	}
+}
+
+func affectedConfigs(errs []configError.ConfigError) string {
+	var configs []string
+	for i, err := range errs {
+		if i == maxListedConfigs {
+			configs = append(configs, fmt.Sprintf("and %d more", len(errs)-maxListedConfigs))
+			break
+		}
+
+		s := err.Coordinates().String()
+		if d, ok := err.(configError.DetailedConfigError); ok {
+			s = fmt.Sprintf("%s(%s) %s", d.LocationDetails().Environment, d.LocationDetails().Group, s)
+		}
+		configs = append(configs, s)
+	}
+	return strings.Join(configs, ", ")
+}
@@ -0,0 +1,84 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	configError "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testConfigError struct {
+	coordinate coordinate.Coordinate
+	reason     string
+}
+
+func (e testConfigError) Coordinates() coordinate.Coordinate { return e.coordinate }
+func (e testConfigError) Error() string                      { return e.reason }
+
+func newTestConfigError(configId, reason string) configError.ConfigError {
+	return testConfigError{coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: configId}, reason: reason}
+}
+
+func TestClusterByRootCause(t *testing.T) {
+	schemaErr1 := newTestConfigError("a", `failed to upsert settings object with externalId "monaco:YQ==" (HTTP 400): Given property 'rules' with value: '[]' violates the following constraints`)
+	schemaErr2 := newTestConfigError("b", `failed to upsert settings object with externalId "monaco:Yg==" (HTTP 400): Given property 'rules' with value: '[]' violates the following constraints`)
+	schemaErr3 := newTestConfigError("c", `failed to upsert settings object with externalId "monaco:Yw==" (HTTP 400): Given property 'rules' with value: '[]' violates the following constraints`)
+	serverErr := newTestConfigError("d", `failed to upsert settings object with externalId "monaco:ZA==" (HTTP 500): Given property 'rules' with value: '[]' violates the following constraints`)
+	otherSchemaErr := newTestConfigError("g", `failed to upsert settings object with externalId "monaco:Zw==" (HTTP 400): Given property 'name' with value: 'g' violates the following constraints`)
+	timeoutErr1 := newTestConfigError("e", "request for config 1234 timed out")
+	timeoutErr2 := newTestConfigError("f", "request for config 5678 timed out")
+
+	clusters, unique := clusterByRootCause([]configError.ConfigError{timeoutErr1, schemaErr1, serverErr, schemaErr2, otherSchemaErr, timeoutErr2, schemaErr3})
+
+	assert.Equal(t, []rootCauseCluster{
+		{cause: rootCauseOf(schemaErr1), errors: []configError.ConfigError{schemaErr1, schemaErr2, schemaErr3}},
+		{cause: rootCauseOf(timeoutErr1), errors: []configError.ConfigError{timeoutErr1, timeoutErr2}},
+	}, clusters)
+	assert.Equal(t, []configError.ConfigError{serverErr, otherSchemaErr}, unique)
+	assert.Equal(t, "400", clusters[0].cause.statusCode)
+}
+
+func TestRootCauseOf(t *testing.T) {
+	for _, tc := range []struct{ a, b string }{
+		{"object 0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d not found", "object 1a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d not found"},
+		{"entity HOST-0123456789ABCDEF not found", "entity SERVICE-FEDCBA9876543210 not found"},
+		{`object "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU" not found`, `object "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGF" not found`},
+		{"retried 3 times", "retried 5 times"},
+	} {
+		assert.Equal(t, rootCauseOf(newTestConfigError("a", tc.a)), rootCauseOf(newTestConfigError("b", tc.b)))
+	}
+
+	assert.NotEqual(t,
+		rootCauseOf(newTestConfigError("a", `(HTTP 400): {"message": "name must not be empty"}`)),
+		rootCauseOf(newTestConfigError("b", `(HTTP 400): {"message": "scope is invalid"}`)),
+		"quoted details are kept")
+}
+
+func TestAffectedConfigs(t *testing.T) {
+	var errs []configError.ConfigError
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		errs = append(errs, newTestConfigError(id, "failed"))
+	}
+
+	assert.Equal(t, "p:builtin:alerting.profile:a, p:builtin:alerting.profile:b, p:builtin:alerting.profile:c, "+
+		"p:builtin:alerting.profile:d, p:builtin:alerting.profile:e, p:builtin:alerting.profile:f, p:builtin:alerting.profile:g, "+
+		"p:builtin:alerting.profile:h, p:builtin:alerting.profile:i, p:builtin:alerting.profile:j, and 2 more", affectedConfigs(errs))
+}
@@ -0,0 +1,84 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docs
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/docs"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// GetDocsCommand returns the command group to generate documentation of projects.
+func GetDocsCommand(fs afero.Fs) *cobra.Command {
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate documentation of projects",
+	}
+
+	docsCmd.AddCommand(getGenerateCommand(fs))
+
+	return docsCmd
+}
+
+func getGenerateCommand(fs afero.Fs) (generateCmd *cobra.Command) {
+	var opts Options
+
+	generateCmd = &cobra.Command{
+		Use:   "generate [<manifest.yaml>]",
+		Short: "Generate an inventory of all configs of the projects of a manifest",
+		Long: `Generate an inventory of all configs of the projects of a manifest
+
+  The inventory lists the configs of each project per type, their parameters and how parameters are overridden per
+  environment, the environments configs are skipped in, and a graph of the dependencies between configs.
+  Markdown output renders the graph as Mermaid diagram, which is displayed as image by common Git hosting platforms.`,
+		Example: `monaco docs generate manifest.yaml
+monaco docs generate manifest.yaml --format html -o public`,
+		Args:   cobra.MaximumNArgs(1),
+		PreRun: cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, opts.ManifestFromEnv)
+			if err != nil {
+				return err
+			}
+			return Generate(fs, manifestName, opts)
+		},
+	}
+
+	generateCmd.Flags().StringVar(&opts.Format, "format", docs.FormatMarkdown, "Format of the documentation, either 'markdown' or 'html'")
+	generateCmd.Flags().StringVarP(&opts.OutputFolder, "output-folder", "o", "docs", "Folder to write the documentation to")
+	generateCmd.Flags().StringSliceVarP(&opts.Environments, "environment", "e", []string{},
+		"Specify one (or multiple) environment(s) to document the configurations of. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'. "+
+			"If neither --group nor --environment is present, the configurations of all environments are documented.")
+	generateCmd.Flags().StringSliceVarP(&opts.EnvironmentGroups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) to document the configurations of. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'")
+	cmdutils.AddManifestFromEnvFlag(generateCmd, &opts.ManifestFromEnv)
+
+	if err := generateCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	generateCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return generateCmd
+}
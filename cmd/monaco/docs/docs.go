@@ -0,0 +1,77 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docs
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/docs"
+	"github.com/spf13/afero"
+	"path/filepath"
+)
+
+// Options defines which configurations are documented by Generate and how.
+type Options struct {
+	// ManifestFromEnv defines that the manifest is created from environment variables instead of being read from a file
+	ManifestFromEnv bool
+	// EnvironmentGroups restricts the documentation to the configurations of the given environment groups
+	EnvironmentGroups []string
+	// Environments restricts the documentation to the configurations of the given environments
+	Environments []string
+	// Format is either docs.FormatMarkdown or docs.FormatHTML
+	Format string
+	// OutputFolder is the folder the documentation is written to
+	OutputFolder string
+}
+
+// Generate loads the given manifest and writes the documentation of all its projects to the output folder.
+func Generate(fs afero.Fs, manifestPath string, opts Options) error {
+	m, projects, err := cmdutils.LoadManifestAndProjects(fs, manifestPath, cmdutils.ManifestOptions{
+		Groups:       opts.EnvironmentGroups,
+		Environments: opts.Environments,
+		FromEnv:      opts.ManifestFromEnv,
+	})
+	if err != nil {
+		return err
+	}
+
+	environments := make([]string, 0, len(m.Environments))
+	for name := range m.Environments {
+		environments = append(environments, name)
+	}
+
+	inv := docs.New(projects, environments)
+	content, err := docs.Render(inv, opts.Format)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.MkdirAll(opts.OutputFolder, 0777); err != nil {
+		return fmt.Errorf("failed to create output folder %q: %w", opts.OutputFolder, err)
+	}
+	file := filepath.Join(opts.OutputFolder, "index.md")
+	if opts.Format == docs.FormatHTML {
+		file = filepath.Join(opts.OutputFolder, "index.html")
+	}
+	if err := afero.WriteFile(fs, file, content, 0644); err != nil {
+		return fmt.Errorf("failed to write documentation: %w", err)
+	}
+
+	log.Info("Documented %d configs of %d projects in %q", inv.Count(), len(inv.Projects), file)
+	return nil
+}
@@ -15,14 +15,18 @@
 package download
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	"net/url"
 	"path"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
 	"github.com/spf13/afero"
@@ -34,10 +38,10 @@ import (
 //
 // The actual implementations are in the [DefaultCommand] struct.
 type Command interface {
-	DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions downloadCmdOptions) error
-	DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptions) error
-	DownloadEntitiesBasedOnManifest(fs afero.Fs, cmdOptions entitiesManifestDownloadOptions) error
-	DownloadEntities(fs afero.Fs, cmdOptions entitiesDirectDownloadOptions) error
+	DownloadConfigsBasedOnManifest(ctx context.Context, fs afero.Fs, cmdOptions downloadCmdOptions) error
+	DownloadConfigs(ctx context.Context, fs afero.Fs, cmdOptions downloadCmdOptions) error
+	DownloadEntitiesBasedOnManifest(ctx context.Context, fs afero.Fs, cmdOptions entitiesManifestDownloadOptions) error
+	DownloadEntities(ctx context.Context, fs afero.Fs, cmdOptions entitiesDirectDownloadOptions) error
 }
 
 // DefaultCommand is used to implement the [Command] interface.
@@ -52,6 +56,7 @@ type sharedDownloadCmdOptions struct {
 	projectName    string
 	outputFolder   string
 	forceOverwrite bool
+	timeout        time.Duration
 }
 
 type downloadOptionsShared struct {
@@ -61,17 +66,31 @@ type downloadOptionsShared struct {
 	projectName             string
 	forceOverwriteManifest  bool
 	concurrentDownloadLimit int
+	snapshot                bool
+	// serverVersion is the version of the Dynatrace server downloaded from, recorded in snapshots. It may be unknown.
+	serverVersion version.Version
+	// report, if set, collects the objects skipped during the download and is written into the output folder
+	report *report.Report
+	// split, if enabled, splits the downloaded configs into one project per management zone or tag value
+	split download.Split
 }
 
 func writeConfigs(downloadedConfigs project.ConfigsPerType, opts downloadOptionsShared, fs afero.Fs) error {
-	proj := download.CreateProjectData(downloadedConfigs, opts.projectName)
+	projects := []project.Project{download.CreateProjectData(downloadedConfigs, opts.projectName)}
+	if opts.split.Enabled() {
+		projects = download.SplitProjects(downloadedConfigs, opts.projectName, opts.split)
+	}
 
 	downloadWriterContext := download.WriterContext{
 		EnvironmentUrl:         opts.environmentURL,
-		ProjectToWrite:         proj,
+		ProjectToWrite:         projects[0],
+		SplitProjects:          projects[1:],
 		Auth:                   opts.auth,
 		OutputFolder:           opts.outputFolder,
 		ForceOverwriteManifest: opts.forceOverwriteManifest,
+		WriteSnapshot:          opts.snapshot,
+		ServerVersion:          opts.serverVersion,
+		Report:                 opts.report,
 	}
 	err := download.WriteToDisk(fs, downloadWriterContext)
 	if err != nil {
@@ -79,7 +98,7 @@ func writeConfigs(downloadedConfigs project.ConfigsPerType, opts downloadOptions
 	}
 
 	log.Info("Searching for circular dependencies")
-	if depErr := reportForCircularDependencies(proj); depErr != nil {
+	if depErr := reportForCircularDependencies(projects); depErr != nil {
 		log.Warn("Download finished with problems: %s", depErr)
 	} else {
 		log.Info("No circular dependencies found")
@@ -89,8 +108,8 @@ func writeConfigs(downloadedConfigs project.ConfigsPerType, opts downloadOptions
 	return nil
 }
 
-func reportForCircularDependencies(p project.Project) error {
-	_, errs := topologysort.GetSortedConfigsForEnvironments([]project.Project{p}, []string{p.Id})
+func reportForCircularDependencies(projects []project.Project) error {
+	_, errs := topologysort.GetSortedConfigsForEnvironments(projects, []string{projects[0].Id})
 	if len(errs) != 0 {
 		errutils.PrintWarnings(errs)
 		return fmt.Errorf("there are circular dependencies between %d configurations that need to be resolved manually", len(errs))
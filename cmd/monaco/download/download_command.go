@@ -18,14 +18,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
-	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
-	"net/http"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
 )
@@ -54,13 +54,13 @@ func GetDownloadCommand(fs afero.Fs, command Command) (cmd *cobra.Command) {
 
 			if f.environmentURL != "" {
 				f.manifestFile = ""
-				return command.DownloadConfigs(fs, f)
+				return command.DownloadConfigs(cmd.Context(), fs, f)
 			}
-			return command.DownloadConfigsBasedOnManifest(fs, f)
+			return command.DownloadConfigsBasedOnManifest(cmd.Context(), fs, f)
 		},
 	}
 
-	setupSharedFlags(cmd, &f.projectName, &f.outputFolder, &f.forceOverwrite)
+	setupSharedFlags(cmd, &f.projectName, &f.outputFolder, &f.forceOverwrite, &f.timeout)
 
 	// download via manifest
 	cmd.Flags().StringVarP(&f.manifestFile, "manifest", "m", "manifest.yaml", "Name (and the path) to the manifest file. If not provided \"manifest.yaml\" value will be used.")
@@ -77,6 +77,17 @@ func GetDownloadCommand(fs afero.Fs, command Command) (cmd *cobra.Command) {
 
 	cmd.Flags().BoolVar(&f.onlyAPIs, "only-apis", false, "Only download config APIs, skip downloading settings 2.0 objects")
 	cmd.Flags().BoolVar(&f.onlySettings, "only-settings", false, "Only download settings 2.0 objects, skip downloading config APIs")
+	cmd.Flags().IntVar(&f.downloadWorkers, "download-workers", 0, "Number of configs downloaded concurrently. If not set, the number of concurrent requests defined by MONACO_CONCURRENT_REQUESTS is used")
+	cmd.Flags().Float64Var(&f.qpsPerAPI, "qps-per-api", 0, "Maximum number of requests per second sent for each config API. If not set, the rate is not limited. Regardless of this limit, requests of an API slow down when the environment responds with 'Too Many Requests'")
+	cmd.Flags().StringVar(&f.modifiedSince, "modified-since", "", "Only download settings 2.0 objects modified after the given date (e.g. 2023-01-01) or RFC 3339 timestamp. Config APIs do not provide modification times and are always downloaded completely")
+	cmd.Flags().BoolVar(&f.snapshot, "snapshot", false, "Additionally write a 'snapshot.json' into the downloaded project, containing SHA-256 hashes of all downloaded objects and environment metadata for later integrity verification")
+	cmd.Flags().StringVar(&f.idsFile, "ids", "", "File listing the objects to download, one '<API or settings schema>:<ID>' per line (e.g. 'alerting-profile:<config ID>' or 'builtin:alerting.profile:<object ID>'). Lines starting with '#' are ignored. Only the listed objects are fetched, and the download fails if any of them is not found")
+	cmd.Flags().StringVar(&f.splitBy, "split-by", "", "Split the download into one project per team: 'management-zone' moves configs referencing a single management zone into a project named after it, "+
+		"'tag:<key>' moves configs tagged '<key>:<value>' into a project named after the value. Other configs stay in the downloaded project, references between the projects are kept")
+	cmd.Flags().StringVar(&f.ownership, "ownership", "", "Only download objects 'managed' by monaco or 'unmanaged' ones, e.g. created manually. Settings 2.0 objects are recognized by their externalId, config API objects by the marker added to their description by 'monaco deploy --mark-ownership'. If not set, all objects are downloaded")
+	for _, f := range []string{"api", "settings-schema", "only-apis", "only-settings"} {
+		cmd.MarkFlagsMutuallyExclusive("ids", f)
+	}
 	cmd.MarkFlagsMutuallyExclusive("settings-schema", "only-apis", "only-settings")
 	cmd.MarkFlagsMutuallyExclusive("api", "only-apis", "only-settings")
 	cmd.MarkFlagsMutuallyExclusive("only-apis", "only-settings")
@@ -123,7 +134,9 @@ func preRunChecks(f downloadCmdOptions) error {
 func getDownloadEntitiesCommand(fs afero.Fs, command Command, downloadCmd *cobra.Command) {
 	var project, outputFolder string
 	var forceOverwrite bool
+	var timeout time.Duration
 	var specificEntitiesTypes []string
+	var deltaFrom string
 
 	downloadEntitiesCmd := &cobra.Command{
 		Use:   "entities",
@@ -161,11 +174,13 @@ Either downloading based on an existing manifest, or by defining environment URL
 						projectName:    project,
 						outputFolder:   outputFolder,
 						forceOverwrite: forceOverwrite,
+						timeout:        timeout,
 					},
 					specificEntitiesTypes: specificEntitiesTypes,
+					deltaFrom:             deltaFrom,
 				},
 			}
-			return command.DownloadEntitiesBasedOnManifest(fs, options)
+			return command.DownloadEntitiesBasedOnManifest(cmd.Context(), fs, options)
 		},
 	}
 
@@ -192,17 +207,19 @@ Either downloading based on an existing manifest, or by defining environment URL
 						projectName:    project,
 						outputFolder:   outputFolder,
 						forceOverwrite: forceOverwrite,
+						timeout:        timeout,
 					},
 					specificEntitiesTypes: specificEntitiesTypes,
+					deltaFrom:             deltaFrom,
 				},
 			}
-			return command.DownloadEntities(fs, options)
+			return command.DownloadEntities(cmd.Context(), fs, options)
 
 		},
 	}
 
-	setupSharedEntitiesFlags(manifestDownloadCmd, &project, &outputFolder, &forceOverwrite, &specificEntitiesTypes)
-	setupSharedEntitiesFlags(directDownloadCmd, &project, &outputFolder, &forceOverwrite, &specificEntitiesTypes)
+	setupSharedEntitiesFlags(manifestDownloadCmd, &project, &outputFolder, &forceOverwrite, &timeout, &specificEntitiesTypes, &deltaFrom)
+	setupSharedEntitiesFlags(directDownloadCmd, &project, &outputFolder, &forceOverwrite, &timeout, &specificEntitiesTypes, &deltaFrom)
 
 	downloadEntitiesCmd.AddCommand(manifestDownloadCmd)
 	downloadEntitiesCmd.AddCommand(directDownloadCmd)
@@ -210,16 +227,21 @@ Either downloading based on an existing manifest, or by defining environment URL
 	downloadCmd.AddCommand(downloadEntitiesCmd)
 }
 
-func setupSharedEntitiesFlags(cmd *cobra.Command, project, outputFolder *string, forceOverwrite *bool, specificEntitiesTypes *[]string) {
-	setupSharedFlags(cmd, project, outputFolder, forceOverwrite)
+func setupSharedEntitiesFlags(cmd *cobra.Command, project, outputFolder *string, forceOverwrite *bool, timeout *time.Duration, specificEntitiesTypes *[]string, deltaFrom *string) {
+	setupSharedFlags(cmd, project, outputFolder, forceOverwrite, timeout)
 	cmd.Flags().StringSliceVarP(specificEntitiesTypes, "specific-types", "s", make([]string, 0), "List of entity type IDs specifying which entity types to download")
+	cmd.Flags().StringVar(deltaFrom, "delta-from", "", "Project folder of a previous entities download. Only entities seen since that download are fetched and merged with it, instead of downloading all entities again")
+	if err := cmd.MarkFlagDirname("delta-from"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
 
 }
-func setupSharedFlags(cmd *cobra.Command, project, outputFolder *string, forceOverwrite *bool) {
+func setupSharedFlags(cmd *cobra.Command, project, outputFolder *string, forceOverwrite *bool, timeout *time.Duration) {
 	// flags always available
 	cmd.Flags().StringVarP(project, "project", "p", "project", "Project to create within the output-folder")
 	cmd.Flags().StringVarP(outputFolder, "output-folder", "o", "", "Folder to write downloaded configs to")
 	cmd.Flags().BoolVarP(forceOverwrite, "force", "f", false, "Force overwrite any existing manifest.yaml, rather than creating an additional manifest_{timestamp}.yaml. Manifest download: additionally never append source environment name to project folder name")
+	cmdutils.AddTimeoutFlag(cmd, timeout)
 
 	err := cmd.MarkFlagDirname("output-folder")
 	if err != nil {
@@ -227,31 +249,22 @@ func setupSharedFlags(cmd *cobra.Command, project, outputFolder *string, forceOv
 	}
 }
 
-// printUploadToSameEnvironmentWarning function may display a warning message on the console,
-// notifying the user that downloaded objects cannot be uploaded to the same environment.
-// It verifies the version of the tenant and, depending on the result, it may or may not display the warning.
-func printUploadToSameEnvironmentWarning(env manifest.EnvironmentDefinition) {
-	var serverVersion version.Version
-	var err error
-
-	var httpClient *http.Client
-	if env.Auth.OAuth == nil {
-		httpClient = client.NewTokenAuthClient(env.Auth.Token.Value)
-	} else {
-		credentials := client.OauthCredentials{
-			ClientID:     env.Auth.OAuth.ClientID.Value,
-			ClientSecret: env.Auth.OAuth.ClientSecret.Value,
-			TokenURL:     env.Auth.OAuth.GetTokenEndpointValue(),
-		}
-		httpClient = client.NewOAuthClient(context.TODO(), credentials)
-	}
-
-	serverVersion, err = client.GetDynatraceVersion(httpClient, env.URL.Value)
+// determineServerVersion returns the version of the Dynatrace server of the environment at the given URL, or
+// version.UnknownVersion if it can't be determined.
+func determineServerVersion(ctx context.Context, url string, auth manifest.Auth) version.Version {
+	serverVersion, err := cmdutils.ServerVersion(ctx, url, auth)
 	if err != nil {
-		log.Warn("Unable to determine server version %q: %w", env.URL.Value, err)
-		return
+		log.Warn("Unable to determine server version %q: %v", url, err)
+		return version.UnknownVersion
 	}
-	if serverVersion.SmallerThan(version.Version{Major: 1, Minor: 262}) {
+	return serverVersion
+}
+
+// printUploadToSameEnvironmentWarning function may display a warning message on the console,
+// notifying the user that downloaded objects cannot be uploaded to the same environment.
+// Depending on the given version of the tenant, it may or may not display the warning.
+func printUploadToSameEnvironmentWarning(serverVersion version.Version) {
+	if !serverVersion.Invalid() && serverVersion.SmallerThan(version.Version{Major: 1, Minor: 262}) {
 		logUploadToSameEnvironmentWarning()
 	}
 }
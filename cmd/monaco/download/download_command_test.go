@@ -39,7 +39,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			specificEnvironmentName:  "my-environment1",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
 		}
-		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--manifest path/to/my-manifest.yaml --environment my-environment1")
 
@@ -54,7 +54,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			specificEnvironmentName:  "my-environment",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
 		}
-		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--environment my-environment")
 
@@ -74,7 +74,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			auth:                     auth{token: "TOKEN"},
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
 		}
-		m.EXPECT().DownloadConfigs(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigs(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--url http://some.url --token TOKEN")
 
@@ -93,7 +93,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			},
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
 		}
-		m.EXPECT().DownloadConfigs(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigs(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--url http://some.url --token TOKEN --oauth-client-id CLIENT_ID --oauth-client-secret CLIENT_SECRET")
 		assert.NoError(t, err)
@@ -126,7 +126,7 @@ func TestGetDownloadCommand(t *testing.T) {
 				forceOverwrite: true,
 			},
 		}
-		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--manifest path/my-manifest.yaml --environment my-environment --project my-project --output-folder path/to/my-folder --force true")
 
@@ -141,7 +141,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			specificEnvironmentName:  "my_environment",
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
 		}
-		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--environment my_environment")
 		assert.NoError(t, err)
@@ -156,7 +156,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			sharedDownloadCmdOptions: sharedDownloadCmdOptions{projectName: "project"},
 			specificAPIs:             []string{"test", "test2", "test3", "test4"},
 		}
-		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--environment myEnvironment --api test --api test2 --api test3,test4")
 		assert.NoError(t, err)
@@ -171,7 +171,7 @@ func TestGetDownloadCommand(t *testing.T) {
 		}
 
 		m := newMonaco(t)
-		m.EXPECT().DownloadConfigs(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigs(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--url test.url --token token --only-apis")
 		assert.NoError(t, err)
@@ -199,7 +199,7 @@ func TestGetDownloadCommand(t *testing.T) {
 			specificSchemas:          []string{"settings:schema:1", "settings:schema:2", "settings:schema:3", "settings:schema:4"},
 		}
 		m := newMonaco(t)
-		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigsBasedOnManifest(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--environment myEnvironment --settings-schema settings:schema:1 --settings-schema settings:schema:2 --settings-schema settings:schema:3,settings:schema:4")
 		assert.NoError(t, err)
@@ -214,7 +214,7 @@ func TestGetDownloadCommand(t *testing.T) {
 		}
 
 		m := newMonaco(t)
-		m.EXPECT().DownloadConfigs(gomock.Any(), expected).Return(nil)
+		m.EXPECT().DownloadConfigs(gomock.Any(), gomock.Any(), expected).Return(nil)
 
 		err := m.download("--url test.url --token token --only-settings")
 		assert.NoError(t, err)
@@ -340,7 +340,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "direct", "test.url", "token", "--project", "test"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntities(gomock.Any(), entitiesDirectDownloadOptions{
+				cmd.EXPECT().DownloadEntities(gomock.Any(), gomock.Any(), entitiesDirectDownloadOptions{
 					environmentURL: "test.url",
 					envVarName:     "token",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -359,7 +359,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "direct", "test.url", "token"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntities(gomock.Any(), entitiesDirectDownloadOptions{
+				cmd.EXPECT().DownloadEntities(gomock.Any(), gomock.Any(), entitiesDirectDownloadOptions{
 					environmentURL: "test.url",
 					envVarName:     "token",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -378,7 +378,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "direct", "test.url", "token", "--output-folder", "myDownloads"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntities(gomock.Any(), entitiesDirectDownloadOptions{
+				cmd.EXPECT().DownloadEntities(gomock.Any(), gomock.Any(), entitiesDirectDownloadOptions{
 					environmentURL: "test.url",
 					envVarName:     "token",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -397,7 +397,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "direct", "test.url", "token", "--output-folder", "myDownloads", "--force"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntities(gomock.Any(), entitiesDirectDownloadOptions{
+				cmd.EXPECT().DownloadEntities(gomock.Any(), gomock.Any(), entitiesDirectDownloadOptions{
 					environmentURL: "test.url",
 					envVarName:     "token",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -416,7 +416,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "manifest", "test.yaml", "test_env"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), entitiesManifestDownloadOptions{
+				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), gomock.Any(), entitiesManifestDownloadOptions{
 					manifestFile:            "test.yaml",
 					specificEnvironmentName: "test_env",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -435,7 +435,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "manifest", "test.yaml", "test_env", "--project", "testproject"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), entitiesManifestDownloadOptions{
+				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), gomock.Any(), entitiesManifestDownloadOptions{
 					manifestFile:            "test.yaml",
 					specificEnvironmentName: "test_env",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -454,7 +454,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "manifest", "test.yaml", "test_env", "--output-folder", "myDownloads"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), entitiesManifestDownloadOptions{
+				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), gomock.Any(), entitiesManifestDownloadOptions{
 					manifestFile:            "test.yaml",
 					specificEnvironmentName: "test_env",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -473,7 +473,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "manifest", "test.yaml", "test_env", "--output-folder", "myDownloads", "--force"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), entitiesManifestDownloadOptions{
+				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), gomock.Any(), entitiesManifestDownloadOptions{
 					manifestFile:            "test.yaml",
 					specificEnvironmentName: "test_env",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -492,7 +492,7 @@ func TestValidCommands(t *testing.T) {
 			[]string{"entities", "manifest", "test.yaml", "test_env", "--specific-types", "HOST,SERVICE"},
 			[]string{},
 			func(cmd *MockCommand) {
-				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), entitiesManifestDownloadOptions{
+				cmd.EXPECT().DownloadEntitiesBasedOnManifest(gomock.Any(), gomock.Any(), entitiesManifestDownloadOptions{
 					manifestFile:            "test.yaml",
 					specificEnvironmentName: "test_env",
 					entitiesDownloadCommandOptions: entitiesDownloadCommandOptions{
@@ -15,21 +15,27 @@
 package download
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/classic"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/settings"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/ownership"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/spf13/afero"
 	"os"
 	"strings"
+	"time"
 )
 
 type downloadCmdOptions struct {
@@ -42,6 +48,13 @@ type downloadCmdOptions struct {
 	specificSchemas         []string
 	onlyAPIs                bool
 	onlySettings            bool
+	snapshot                bool
+	downloadWorkers         int
+	qpsPerAPI               float64
+	modifiedSince           string
+	idsFile                 string
+	splitBy                 string
+	ownership               string
 }
 
 type auth struct {
@@ -84,7 +97,9 @@ func readEnvVariable(envVar string) (manifest.AuthSecret, error) {
 	return manifest.AuthSecret{Name: envVar, Value: content}, nil
 }
 
-func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions downloadCmdOptions) error {
+func (d DefaultCommand) DownloadConfigsBasedOnManifest(ctx context.Context, fs afero.Fs, cmdOptions downloadCmdOptions) error {
+	ctx, cancel := cmdutils.WithTimeout(ctx, cmdOptions.timeout)
+	defer cancel()
 
 	m, errs := manifest.LoadManifest(&manifest.LoaderContext{
 		Fs:           fs,
@@ -101,18 +116,38 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 		return fmt.Errorf("environment %q was not available in manifest %q", cmdOptions.specificEnvironmentName, cmdOptions.manifestFile)
 	}
 
-	ok := cmdutils.VerifyEnvironmentGeneration(manifest.Environments{env.Name: env})
+	ok := cmdutils.VerifyEnvironmentGeneration(ctx, manifest.Environments{env.Name: env}, m.Offline)
 	if !ok {
 		return fmt.Errorf("unable to verify Dynatrace environment generation")
 	}
 
-	printUploadToSameEnvironmentWarning(env)
+	var serverVersion version.Version
+	if !m.Offline.Enabled {
+		serverVersion = determineServerVersion(ctx, env.URL.Value, env.Auth)
+		printUploadToSameEnvironmentWarning(serverVersion)
+	}
 
 	if !cmdOptions.forceOverwrite {
 		cmdOptions.projectName = fmt.Sprintf("%s_%s", cmdOptions.projectName, cmdOptions.specificEnvironmentName)
 	}
 
-	concurrentDownloadLimit := environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey)
+	concurrentDownloadLimit := getConcurrentDownloadLimit(cmdOptions)
+	modifiedSince, err := parseModifiedSince(cmdOptions.modifiedSince)
+	if err != nil {
+		return err
+	}
+	ids, err := readIds(fs, cmdOptions.idsFile)
+	if err != nil {
+		return err
+	}
+	split, err := download.ParseSplit(cmdOptions.splitBy)
+	if err != nil {
+		return err
+	}
+	ownershipFilter, err := ownership.ParseFilter(cmdOptions.ownership)
+	if err != nil {
+		return err
+	}
 
 	options := downloadConfigsOptions{
 		downloadOptionsShared: downloadOptionsShared{
@@ -122,30 +157,64 @@ func (d DefaultCommand) DownloadConfigsBasedOnManifest(fs afero.Fs, cmdOptions d
 			projectName:             cmdOptions.projectName,
 			forceOverwriteManifest:  cmdOptions.forceOverwrite,
 			concurrentDownloadLimit: concurrentDownloadLimit,
+			snapshot:                cmdOptions.snapshot,
+			serverVersion:           serverVersion,
+			split:                   split,
 		},
 		specificAPIs:    cmdOptions.specificAPIs,
 		specificSchemas: cmdOptions.specificSchemas,
 		onlyAPIs:        cmdOptions.onlyAPIs,
 		onlySettings:    cmdOptions.onlySettings,
+		qpsPerAPI:       cmdOptions.qpsPerAPI,
+		modifiedSince:   modifiedSince,
+		ids:             ids,
+		ownership:       ownershipFilter,
 	}
 
-	dtClient, err := cmdutils.CreateDTClient(options.environmentURL, options.auth, false)
+	ignored := cmdutils.LoadIgnoredRemoteObjects(fs, cmdOptions.manifestFile, m, func(c config.Config) bool { return c.IgnoreOnDownload })
+	options.ignored = ignored[env.Name]
+
+	dtClient, err := cmdutils.CreateDTClient(ctx, options.environmentURL, options.auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)), cmdutils.WithHTTPCache(fs))
 	if err != nil {
 		return err
 	}
 
-	return doDownloadConfigs(fs, dtClient, api.NewAPIs(), options)
+	return doDownloadConfigs(ctx, fs, dtClient, api.NewAPIs(), options)
 }
 
-func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptions) error {
-	concurrentDownloadLimit := environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey)
+func (d DefaultCommand) DownloadConfigs(ctx context.Context, fs afero.Fs, cmdOptions downloadCmdOptions) error {
+	ctx, cancel := cmdutils.WithTimeout(ctx, cmdOptions.timeout)
+	defer cancel()
+
+	concurrentDownloadLimit := getConcurrentDownloadLimit(cmdOptions)
 	a, errors := cmdOptions.auth.mapToAuth()
 	errors = append(errors, validateParameters(cmdOptions.environmentURL, cmdOptions.projectName)...)
+	modifiedSince, err := parseModifiedSince(cmdOptions.modifiedSince)
+	if err != nil {
+		errors = append(errors, err)
+	}
+	ids, err := readIds(fs, cmdOptions.idsFile)
+	if err != nil {
+		errors = append(errors, err)
+	}
+	split, err := download.ParseSplit(cmdOptions.splitBy)
+	if err != nil {
+		errors = append(errors, err)
+	}
+	ownershipFilter, err := ownership.ParseFilter(cmdOptions.ownership)
+	if err != nil {
+		errors = append(errors, err)
+	}
 
 	if len(errors) > 0 {
 		return printAndFormatErrors(errors, "not all necessary information is present to start downloading configurations")
 	}
 
+	var serverVersion version.Version
+	if cmdOptions.snapshot {
+		serverVersion = determineServerVersion(ctx, cmdOptions.environmentURL, *a)
+	}
+
 	options := downloadConfigsOptions{
 		downloadOptionsShared: downloadOptionsShared{
 			environmentURL:          cmdOptions.environmentURL,
@@ -154,19 +223,59 @@ func (d DefaultCommand) DownloadConfigs(fs afero.Fs, cmdOptions downloadCmdOptio
 			projectName:             cmdOptions.projectName,
 			forceOverwriteManifest:  cmdOptions.forceOverwrite,
 			concurrentDownloadLimit: concurrentDownloadLimit,
+			snapshot:                cmdOptions.snapshot,
+			serverVersion:           serverVersion,
+			split:                   split,
 		},
 		specificAPIs:    cmdOptions.specificAPIs,
 		specificSchemas: cmdOptions.specificSchemas,
 		onlyAPIs:        cmdOptions.onlyAPIs,
 		onlySettings:    cmdOptions.onlySettings,
+		qpsPerAPI:       cmdOptions.qpsPerAPI,
+		modifiedSince:   modifiedSince,
+		ids:             ids,
+		ownership:       ownershipFilter,
 	}
 
-	dtClient, err := cmdutils.CreateDTClient(options.environmentURL, options.auth, false)
+	dtClient, err := cmdutils.CreateDTClient(ctx, options.environmentURL, options.auth, false, cmdutils.WithHTTPCache(fs))
 	if err != nil {
 		return err
 	}
 
-	return doDownloadConfigs(fs, dtClient, api.NewAPIs(), options)
+	return doDownloadConfigs(ctx, fs, dtClient, api.NewAPIs(), options)
+}
+
+// getConcurrentDownloadLimit returns the number of download workers set by flag, or the number of concurrent requests
+// defined by environment variable otherwise.
+func getConcurrentDownloadLimit(cmdOptions downloadCmdOptions) int {
+	if cmdOptions.downloadWorkers > 0 {
+		return cmdOptions.downloadWorkers
+	}
+	return environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey)
+}
+
+// readIds reads the IDs file given by '--ids'. If no file is given, nil is returned.
+func readIds(fs afero.Fs, path string) ([]objectId, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return readIdsFile(fs, path)
+}
+
+// parseModifiedSince parses the value of the '--modified-since' flag, which is either a date (e.g. 2023-01-01) or a
+// RFC 3339 timestamp. An empty value results in the zero time.
+func parseModifiedSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value %q for \"modified-since\": expected a date (e.g. 2023-01-01) or a RFC 3339 timestamp", s)
+	}
+	return t, nil
 }
 
 type downloadConfigsOptions struct {
@@ -175,15 +284,30 @@ type downloadConfigsOptions struct {
 	specificSchemas []string
 	onlyAPIs        bool
 	onlySettings    bool
+	// qpsPerAPI limits the requests per second sent for each config API. If 0, the rate is not limited.
+	qpsPerAPI float64
+	// modifiedSince restricts the download to objects modified after the given time. If zero, all objects are downloaded.
+	modifiedSince time.Time
+	// ids, if set, restricts the download to the listed objects
+	ids []objectId
+	// ignored holds the remote objects of configs marked with 'ignoreOnDownload', which are left out of the download
+	ignored config.RemoteObjects
+	// ownership restricts the download to objects managed by monaco or not. By default, all objects are downloaded.
+	ownership ownership.Filter
 }
 
-func doDownloadConfigs(fs afero.Fs, c client.Client, apis api.APIs, opts downloadConfigsOptions) error {
+func doDownloadConfigs(ctx context.Context, fs afero.Fs, c client.Client, apis api.APIs, opts downloadConfigsOptions) error {
 	err := preDownloadValidations(fs, opts.downloadOptionsShared)
 	if err != nil {
 		return err
 	}
 
 	c = client.LimitClientParallelRequests(c, opts.concurrentDownloadLimit)
+	ctx = log.WithFields(ctx, log.EnvironmentField(opts.environmentURL))
+
+	if opts.ids != nil {
+		opts.specificAPIs, opts.specificSchemas = typesOfIds(apis, opts.ids)
+	}
 
 	if ok, unknownApis := validateSpecificAPIs(apis, opts.specificAPIs); !ok {
 		err := fmt.Errorf("requested APIs '%v' are not known", strings.Join(unknownApis, ","))
@@ -191,22 +315,38 @@ func doDownloadConfigs(fs afero.Fs, c client.Client, apis api.APIs, opts downloa
 		return err
 	}
 
-	if ok, unknownSchemas := validateSpecificSchemas(c, opts.specificSchemas); !ok {
+	if ok, unknownSchemas := validateSpecificSchemas(ctx, c, opts.specificSchemas); !ok {
 		err := fmt.Errorf("requested settings-schema(s) '%v' are not known", strings.Join(unknownSchemas, ","))
 		log.Error("%v. Please consult the documentation for available schemas and verify they are available in your environment.", err)
 		return err
 	}
 
 	log.Info("Downloading from environment '%v' into project '%v'", opts.environmentURL, opts.projectName)
-	downloadedConfigs, err := downloadConfigs(c, apis, opts)
+	opts.report = report.New()
+	downloadedConfigs, err := downloadConfigs(ctx, c, apis, opts)
 	if err != nil {
 		return err
 	}
 
+	downloadedConfigs = removeIgnoredConfigs(downloadedConfigs, opts.ignored, opts.report)
+	var missing []objectId
+	if opts.ids != nil {
+		missing = reportMissingIds(downloadedConfigs, opts.ids, opts.report)
+	}
+	for t, configs := range downloadedConfigs {
+		opts.report.Downloaded(t, len(configs))
+	}
+
 	log.Info("Resolving dependencies between configurations")
 	downloadedConfigs = download.ResolveDependencies(downloadedConfigs)
 
-	return writeConfigs(downloadedConfigs, opts.downloadOptionsShared, fs)
+	if err := writeConfigs(downloadedConfigs, opts.downloadOptionsShared, fs); err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%d object(s) listed in IDs file were not found", len(missing))
+	}
+	return nil
 }
 
 func validateSpecificAPIs(a api.APIs, apiNames []string) (valid bool, unknownAPIs []string) {
@@ -218,12 +358,12 @@ func validateSpecificAPIs(a api.APIs, apiNames []string) (valid bool, unknownAPI
 	return len(unknownAPIs) == 0, unknownAPIs
 }
 
-func validateSpecificSchemas(c client.SettingsClient, schemas []string) (valid bool, unknownSchemas []string) {
+func validateSpecificSchemas(ctx context.Context, c client.SettingsClient, schemas []string) (valid bool, unknownSchemas []string) {
 	if len(schemas) == 0 {
 		return true, nil
 	}
 
-	schemaList, err := c.ListSchemas()
+	schemaList, err := c.ListSchemas(ctx)
 	if err != nil {
 		log.Error("failed to query available Settings Schemas: %v", err)
 		return false, schemas
@@ -241,11 +381,26 @@ func validateSpecificSchemas(c client.SettingsClient, schemas []string) (valid b
 	return len(unknownSchemas) == 0, unknownSchemas
 }
 
-func downloadConfigs(c client.Client, apis api.APIs, opts downloadConfigsOptions) (project.ConfigsPerType, error) {
+func downloadConfigs(ctx context.Context, c client.Client, apis api.APIs, opts downloadConfigsOptions) (project.ConfigsPerType, error) {
 	configObjects := make(project.ConfigsPerType)
 
+	classicOpts := []func(*classic.Downloader){classic.WithWorkers(opts.concurrentDownloadLimit), classic.WithQPSPerAPI(opts.qpsPerAPI), classic.WithReport(opts.report), classic.WithOwnershipFilter(opts.ownership)}
+	settingsOpts := []func(*settings.Downloader){settings.WithModifiedSince(opts.modifiedSince), settings.WithReport(opts.report), settings.WithOwnershipFilter(opts.ownership), settings.WithIgnoredObjects(opts.ignored)}
+	if opts.ids != nil {
+		configIds, objectIds := groupIds(apis, opts.ids)
+		var allObjectIds []string
+		for _, ids := range objectIds {
+			allObjectIds = append(allObjectIds, ids...)
+		}
+		classicOpts = append(classicOpts, classic.WithConfigIds(configIds))
+		settingsOpts = append(settingsOpts, settings.WithObjectIds(allObjectIds))
+	}
+
 	if shouldDownloadClassicConfigs(opts) {
-		classicCfgs, err := downloadClassicConfigs(c, apis, opts.specificAPIs, opts.projectName)
+		if !opts.modifiedSince.IsZero() {
+			log.Info("Config APIs do not provide modification times, all of their configurations are downloaded regardless of \"modified-since\"")
+		}
+		classicCfgs, err := downloadClassicConfigs(ctx, c, apis, opts.specificAPIs, opts.projectName, classicOpts...)
 		if err != nil {
 			return nil, err
 		}
@@ -253,13 +408,37 @@ func downloadConfigs(c client.Client, apis api.APIs, opts downloadConfigsOptions
 	}
 
 	if shouldDownloadSettings(opts) {
-		settingsObjects := downloadSettings(c, opts.specificSchemas, opts.projectName)
+		settingsObjects := downloadSettings(ctx, c, opts.specificSchemas, opts.projectName, settingsOpts...)
 		maps.Copy(configObjects, settingsObjects)
 	}
 
 	return configObjects, nil
 }
 
+// removeIgnoredConfigs drops all downloaded configs whose remote object belongs to a config marked with 'ignoreOnDownload'
+func removeIgnoredConfigs(configs project.ConfigsPerType, ignored config.RemoteObjects, r *report.Report) project.ConfigsPerType {
+	if ignored.IsEmpty() {
+		return configs
+	}
+
+	result := make(project.ConfigsPerType, len(configs))
+	for t, cfgs := range configs {
+		var kept []config.Config
+		for _, c := range cfgs {
+			if ignored.ContainsConfig(c) {
+				log.Info("Skipping download of %s as it is marked with 'ignoreOnDownload'", c.Coordinate)
+				r.Filter(t, downloadedId(c), "", "marked with 'ignoreOnDownload'")
+				continue
+			}
+			kept = append(kept, c)
+		}
+		if len(kept) > 0 {
+			result[t] = kept
+		}
+	}
+	return result
+}
+
 // shouldDownloadClassicConfigs returns true unless onlySettings or specificSchemas but no specificAPIs are defined
 func shouldDownloadClassicConfigs(opts downloadConfigsOptions) bool {
 	return !opts.onlySettings && (len(opts.specificSchemas) == 0 || len(opts.specificAPIs) > 0)
@@ -270,7 +449,7 @@ func shouldDownloadSettings(opts downloadConfigsOptions) bool {
 	return !opts.onlyAPIs && (len(opts.specificAPIs) == 0 || len(opts.specificSchemas) > 0)
 }
 
-func downloadClassicConfigs(c client.Client, apis api.APIs, specificAPIs []string, projectName string) (project.ConfigsPerType, error) {
+func downloadClassicConfigs(ctx context.Context, c client.Client, apis api.APIs, specificAPIs []string, projectName string, opts ...func(*classic.Downloader)) (project.ConfigsPerType, error) {
 	apisToDownload := getApisToDownload(apis, specificAPIs)
 	if len(apisToDownload) == 0 {
 		return nil, fmt.Errorf("no APIs to download")
@@ -278,23 +457,24 @@ func downloadClassicConfigs(c client.Client, apis api.APIs, specificAPIs []strin
 
 	if len(specificAPIs) > 0 {
 		log.Debug("APIs to download: \n - %v", strings.Join(maps.Keys(apisToDownload), "\n - "))
-		cfgs := classic.DownloadAllConfigs(apisToDownload, c, projectName)
+		cfgs := classic.DownloadAllConfigs(ctx, apisToDownload, c, projectName, opts...)
 		return cfgs, nil
 	}
 
 	log.Debug("APIs to download: \n - %v", strings.Join(maps.Keys(apisToDownload), "\n - "))
-	cfgs := classic.DownloadAllConfigs(apisToDownload, c, projectName)
+	cfgs := classic.DownloadAllConfigs(ctx, apisToDownload, c, projectName, opts...)
 	return cfgs, nil
 }
 
-func downloadSettings(c client.Client, specificSchemas []string, projectName string) project.ConfigsPerType {
+func downloadSettings(ctx context.Context, c client.Client, specificSchemas []string, projectName string, opts ...func(*settings.Downloader)) project.ConfigsPerType {
+	downloader := settings.NewSettingsDownloader(c, opts...)
 	if len(specificSchemas) > 0 {
 		log.Debug("Settings to download: \n - %v", strings.Join(specificSchemas, "\n - "))
-		s := settings.Download(c, specificSchemas, projectName)
+		s := downloader.Download(ctx, specificSchemas, projectName)
 		return s
 	}
 
-	s := settings.DownloadAll(c, projectName)
+	s := downloader.DownloadAll(ctx, projectName)
 	return s
 }
 
@@ -19,6 +19,7 @@
 package download
 
 import (
+	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
@@ -27,6 +28,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestGetApisToDownload(t *testing.T) {
@@ -108,10 +110,10 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 				onlySettings:    false,
 			},
 			expectedBehaviour: func(c *client.MockClient) {
-				c.EXPECT().ListConfigs(gomock.Any()).AnyTimes().Return([]client.Value{}, nil)
-				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).AnyTimes().Return([]byte("{}"), nil) // singleton configs are always attempted
-				c.EXPECT().ListSchemas().Return(client.SchemaList{}, nil)
-				c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
+				c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]client.Value{}, nil)
+				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return([]byte("{}"), nil) // singleton configs are always attempted
+				c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{}, nil)
+				c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
 			},
 		},
 		{
@@ -123,9 +125,9 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 				onlySettings:    false,
 			},
 			expectedBehaviour: func(c *client.MockClient) {
-				c.EXPECT().ListConfigs(gomock.Any()).Times(0)
-				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Times(0)
-				c.EXPECT().ListSettings("builtin:magic.secret", gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
+				c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().ListSettings(gomock.Any(), "builtin:magic.secret", gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
 			},
 		},
 		{
@@ -137,10 +139,10 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 				onlySettings:    false,
 			},
 			expectedBehaviour: func(c *client.MockClient) {
-				c.EXPECT().ListConfigs(api.NewAPIs()["alerting-profile"]).Return([]client.Value{{Id: "42", Name: "profile"}}, nil)
-				c.EXPECT().ReadConfigById(gomock.Any(), "42").AnyTimes().Return([]byte("{}"), nil)
-				c.EXPECT().ListSchemas().Times(0)
-				c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()["alerting-profile"]).Return([]client.Value{{Id: "42", Name: "profile"}}, nil)
+				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), "42").AnyTimes().Return([]byte("{}"), nil)
+				c.EXPECT().ListSchemas(gomock.Any()).Times(0)
+				c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -152,9 +154,9 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 				onlySettings:    false,
 			},
 			expectedBehaviour: func(c *client.MockClient) {
-				c.EXPECT().ListConfigs(api.NewAPIs()["alerting-profile"]).Return([]client.Value{{Id: "42", Name: "profile"}}, nil)
-				c.EXPECT().ReadConfigById(gomock.Any(), "42").AnyTimes().Return([]byte("{}"), nil)
-				c.EXPECT().ListSettings("builtin:magic.secret", gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
+				c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()["alerting-profile"]).Return([]client.Value{{Id: "42", Name: "profile"}}, nil)
+				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), "42").AnyTimes().Return([]byte("{}"), nil)
+				c.EXPECT().ListSettings(gomock.Any(), "builtin:magic.secret", gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
 
 			},
 		},
@@ -167,10 +169,10 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 				onlySettings:    false,
 			},
 			expectedBehaviour: func(c *client.MockClient) {
-				c.EXPECT().ListConfigs(gomock.Any()).AnyTimes().Return([]client.Value{}, nil)
-				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).AnyTimes().Return([]byte("{}"), nil) // singleton configs are always attempted
-				c.EXPECT().ListSchemas().Times(0)
-				c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).AnyTimes().Return([]client.Value{}, nil)
+				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return([]byte("{}"), nil) // singleton configs are always attempted
+				c.EXPECT().ListSchemas(gomock.Any()).Times(0)
+				c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 		},
 		{
@@ -182,10 +184,10 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 				onlySettings:    true,
 			},
 			expectedBehaviour: func(c *client.MockClient) {
-				c.EXPECT().ListConfigs(gomock.Any()).Times(0)
-				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Times(0)
-				c.EXPECT().ListSchemas().Return(client.SchemaList{}, nil)
-				c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
+				c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{}, nil)
+				c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return([]client.DownloadSettingsObject{}, nil)
 			},
 		},
 	}
@@ -209,7 +211,7 @@ func TestDownloadConfigsBehaviour(t *testing.T) {
 
 			tt.expectedBehaviour(c)
 
-			_, err := downloadConfigs(c, api.NewAPIs(), tt.givenOpts)
+			_, err := downloadConfigs(context.TODO(), c, api.NewAPIs(), tt.givenOpts)
 			assert.NoError(t, err)
 		})
 	}
@@ -389,7 +391,7 @@ func Test_validateSpecificSettings(t *testing.T) {
 		{
 			"valid if setting is found",
 			given{
-				settingsOnEnvironment:     client.SchemaList{{"builtin:magic.setting"}},
+				settingsOnEnvironment:     client.SchemaList{{SchemaId: "builtin:magic.setting"}},
 				specificSettingsRequested: []string{"builtin:magic.setting"},
 			},
 			true,
@@ -398,7 +400,7 @@ func Test_validateSpecificSettings(t *testing.T) {
 		{
 			"not valid if setting not found",
 			given{
-				settingsOnEnvironment:     client.SchemaList{{"builtin:magic.setting"}},
+				settingsOnEnvironment:     client.SchemaList{{SchemaId: "builtin:magic.setting"}},
 				specificSettingsRequested: []string{"builtin:unknown"},
 			},
 			false,
@@ -407,7 +409,7 @@ func Test_validateSpecificSettings(t *testing.T) {
 		{
 			"not valid if one setting not found",
 			given{
-				settingsOnEnvironment:     client.SchemaList{{"builtin:magic.setting"}},
+				settingsOnEnvironment:     client.SchemaList{{SchemaId: "builtin:magic.setting"}},
 				specificSettingsRequested: []string{"builtin:magic.setting", "builtin:unknown"},
 			},
 			false,
@@ -416,7 +418,7 @@ func Test_validateSpecificSettings(t *testing.T) {
 		{
 			"valid if no specific schemas requested (empty)",
 			given{
-				settingsOnEnvironment:     client.SchemaList{{"builtin:magic.setting"}},
+				settingsOnEnvironment:     client.SchemaList{{SchemaId: "builtin:magic.setting"}},
 				specificSettingsRequested: []string{},
 			},
 			true,
@@ -425,7 +427,7 @@ func Test_validateSpecificSettings(t *testing.T) {
 		{
 			"valid if no specific schemas requested (nil)",
 			given{
-				settingsOnEnvironment:     client.SchemaList{{"builtin:magic.setting"}},
+				settingsOnEnvironment:     client.SchemaList{{SchemaId: "builtin:magic.setting"}},
 				specificSettingsRequested: nil,
 			},
 			true,
@@ -462,9 +464,9 @@ func Test_validateSpecificSettings(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := client.NewMockClient(gomock.NewController(t))
-			c.EXPECT().ListSchemas().AnyTimes().Return(tt.given.settingsOnEnvironment, nil)
+			c.EXPECT().ListSchemas(gomock.Any()).AnyTimes().Return(tt.given.settingsOnEnvironment, nil)
 
-			gotValid, gotUnknownSchemas := validateSpecificSchemas(c, tt.given.specificSettingsRequested)
+			gotValid, gotUnknownSchemas := validateSpecificSchemas(context.TODO(), c, tt.given.specificSettingsRequested)
 			assert.Equalf(t, tt.wantValid, gotValid, "validateSpecificSchemas(%v) for available settings %v", tt.given.specificSettingsRequested, tt.given.specificSettingsRequested)
 			assert.Equalf(t, tt.wantUnknownSchemas, gotUnknownSchemas, "validateSpecificSchemas(%v) for available settings %v", tt.given.specificSettingsRequested, tt.given.specificSettingsRequested)
 		})
@@ -495,7 +497,7 @@ func TestDownloadConfigsExitsEarlyForUnknownAPI(t *testing.T) {
 	}
 
 	givenDefaultAPIs := api.NewAPIs()
-	err := doDownloadConfigs(afero.NewMemMapFs(), c, givenDefaultAPIs, givenOpts)
+	err := doDownloadConfigs(context.TODO(), afero.NewMemMapFs(), c, givenDefaultAPIs, givenOpts)
 	assert.ErrorContains(t, err, "not known", "expected download to fail for unkown API")
 }
 
@@ -522,12 +524,12 @@ func TestDownloadConfigsExitsEarlyForUnknownSettingsSchema(t *testing.T) {
 		},
 	}
 
-	c.EXPECT().ListSchemas().Return(client.SchemaList{{"builtin:some.schema"}}, nil)
+	c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: "builtin:some.schema"}}, nil)
 
 	givenDefaultAPIs := api.NewAPIs()
-	err := doDownloadConfigs(afero.NewMemMapFs(), c, givenDefaultAPIs, givenOpts)
+	err := doDownloadConfigs(context.TODO(), afero.NewMemMapFs(), c, givenDefaultAPIs, givenOpts)
 	assert.ErrorContains(t, err, "not known", "expected download to fail for unkown Settings Schema")
-	c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Times(0) // no downloads should even be attempted for unknown schema
+	c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(0) // no downloads should even be attempted for unknown schema
 }
 
 func TestMapToAuth(t *testing.T) {
@@ -585,3 +587,25 @@ func TestMapToAuth(t *testing.T) {
 		assert.Contains(t, errs, errors.New("the content of the environment variable \"CLIENT_SECRET\" is not set"))
 	})
 }
+
+func TestParseModifiedSince(t *testing.T) {
+	t.Run("empty value results in zero time", func(t *testing.T) {
+		actual, err := parseModifiedSince("")
+		assert.NoError(t, err)
+		assert.True(t, actual.IsZero())
+	})
+	t.Run("date", func(t *testing.T) {
+		actual, err := parseModifiedSince("2023-01-01")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), actual)
+	})
+	t.Run("RFC 3339 timestamp", func(t *testing.T) {
+		actual, err := parseModifiedSince("2023-01-01T12:30:00+02:00")
+		assert.NoError(t, err)
+		assert.True(t, time.Date(2023, 1, 1, 10, 30, 0, 0, time.UTC).Equal(actual))
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := parseModifiedSince("yesterday")
+		assert.ErrorContains(t, err, "modified-since")
+	})
+}
@@ -15,6 +15,7 @@
 package download
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/environment"
@@ -31,6 +32,8 @@ import (
 type entitiesDownloadCommandOptions struct {
 	sharedDownloadCmdOptions
 	specificEntitiesTypes []string
+	// deltaFrom is the project folder of a previous download to only download entities seen since then
+	deltaFrom string
 }
 
 type entitiesManifestDownloadOptions struct {
@@ -47,9 +50,12 @@ type entitiesDirectDownloadOptions struct {
 type downloadEntitiesOptions struct {
 	downloadOptionsShared
 	specificEntitiesTypes []string
+	deltaFrom             string
 }
 
-func (d DefaultCommand) DownloadEntitiesBasedOnManifest(fs afero.Fs, cmdOptions entitiesManifestDownloadOptions) error {
+func (d DefaultCommand) DownloadEntitiesBasedOnManifest(ctx context.Context, fs afero.Fs, cmdOptions entitiesManifestDownloadOptions) error {
+	ctx, cancel := cmdutils.WithTimeout(ctx, cmdOptions.timeout)
+	defer cancel()
 
 	m, errs := manifest.LoadManifest(&manifest.LoaderContext{
 		Fs:           fs,
@@ -82,16 +88,20 @@ func (d DefaultCommand) DownloadEntitiesBasedOnManifest(fs afero.Fs, cmdOptions
 			concurrentDownloadLimit: concurrentDownloadLimit,
 		},
 		specificEntitiesTypes: cmdOptions.specificEntitiesTypes,
+		deltaFrom:             cmdOptions.deltaFrom,
 	}
 
-	dtClient, err := cmdutils.CreateDTClient(env.URL.Value, env.Auth, false)
+	dtClient, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)), cmdutils.WithHTTPCache(fs))
 	if err != nil {
 		return err
 	}
-	return doDownloadEntities(fs, dtClient, options)
+	return doDownloadEntities(ctx, fs, dtClient, options)
 }
 
-func (d DefaultCommand) DownloadEntities(fs afero.Fs, cmdOptions entitiesDirectDownloadOptions) error {
+func (d DefaultCommand) DownloadEntities(ctx context.Context, fs afero.Fs, cmdOptions entitiesDirectDownloadOptions) error {
+	ctx, cancel := cmdutils.WithTimeout(ctx, cmdOptions.timeout)
+	defer cancel()
+
 	token := os.Getenv(cmdOptions.envVarName)
 	concurrentDownloadLimit := environment.GetEnvValueIntLog(environment.ConcurrentRequestsEnvKey)
 	errors := validateParameters(cmdOptions.environmentURL, cmdOptions.projectName)
@@ -115,40 +125,52 @@ func (d DefaultCommand) DownloadEntities(fs afero.Fs, cmdOptions entitiesDirectD
 			concurrentDownloadLimit: concurrentDownloadLimit,
 		},
 		specificEntitiesTypes: cmdOptions.specificEntitiesTypes,
+		deltaFrom:             cmdOptions.deltaFrom,
 	}
 
-	dtClient, err := client.NewClassicClient(cmdOptions.environmentURL, token)
+	dtClient, err := client.NewClassicClient(cmdOptions.environmentURL, token, cmdutils.WithHTTPCache(fs))
 	if err != nil {
 		return err
 	}
 
-	return doDownloadEntities(fs, dtClient, options)
+	return doDownloadEntities(ctx, fs, dtClient, options)
 }
 
-func doDownloadEntities(fs afero.Fs, dtClient client.Client, opts downloadEntitiesOptions) error {
+func doDownloadEntities(ctx context.Context, fs afero.Fs, dtClient client.Client, opts downloadEntitiesOptions) error {
 	err := preDownloadValidations(fs, opts.downloadOptionsShared)
 	if err != nil {
 		return err
 	}
 
+	var downloaderOpts []func(*entities.Downloader)
+	if opts.deltaFrom != "" {
+		previous, err := entities.LoadPreviousDump(fs, opts.deltaFrom)
+		if err != nil {
+			return err
+		}
+		log.Info("Only downloading entities seen since the previous download %q", opts.deltaFrom)
+		downloaderOpts = append(downloaderOpts, entities.WithPreviousDump(previous))
+	}
+
 	log.Info("Downloading from environment '%v' into project '%v'", opts.environmentURL, opts.projectName)
 
-	downloadedConfigs := downloadEntities(dtClient, opts)
+	downloadedConfigs := downloadEntities(ctx, dtClient, opts, downloaderOpts...)
 
 	return writeConfigs(downloadedConfigs, opts.downloadOptionsShared, fs)
 }
 
-func downloadEntities(dtClient client.Client, opts downloadEntitiesOptions) project.ConfigsPerType {
+func downloadEntities(ctx context.Context, dtClient client.Client, opts downloadEntitiesOptions, downloaderOpts ...func(*entities.Downloader)) project.ConfigsPerType {
 	dtClient = client.LimitClientParallelRequests(dtClient, opts.downloadOptionsShared.concurrentDownloadLimit)
+	downloader := entities.NewEntitiesDownloader(dtClient, downloaderOpts...)
 
 	var entitiesObjects project.ConfigsPerType
 
 	// download specific entity types only
 	if len(opts.specificEntitiesTypes) > 0 {
 		log.Debug("Entity Types to download: \n - %v", strings.Join(opts.specificEntitiesTypes, "\n - "))
-		entitiesObjects = entities.Download(dtClient, opts.specificEntitiesTypes, opts.projectName)
+		entitiesObjects = downloader.Download(ctx, opts.specificEntitiesTypes, opts.projectName)
 	} else {
-		entitiesObjects = entities.DownloadAll(dtClient, opts.downloadOptionsShared.projectName)
+		entitiesObjects = downloader.DownloadAll(ctx, opts.downloadOptionsShared.projectName)
 	}
 
 	if numEntities := sumConfigs(entitiesObjects); numEntities > 0 {
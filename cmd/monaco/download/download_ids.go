@@ -0,0 +1,135 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"sort"
+	"strings"
+)
+
+// objectId identifies a single object to download, as listed in the file given by '--ids'
+type objectId struct {
+	// Type is the ID of a config API or a settings 2.0 schema
+	Type string
+	// Id is the ID of the config, or the object ID of the settings 2.0 object
+	Id string
+}
+
+func (o objectId) String() string {
+	return o.Type + ":" + o.Id
+}
+
+// readIdsFile reads the objects to download from the given file. Each line holds one object as '<API or schema>:<ID>',
+// empty lines and lines starting with '#' are ignored. As schema IDs contain colons themselves, the ID is taken from
+// the last colon onwards.
+func readIdsFile(fs afero.Fs, path string) ([]objectId, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IDs file %q: %w", path, err)
+	}
+
+	var ids []objectId
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.LastIndex(line, ":")
+		if i <= 0 || i == len(line)-1 {
+			return nil, fmt.Errorf("invalid entry %q in line %d of IDs file %q: expected '<API or schema>:<ID>'", line, lineNumber, path)
+		}
+		ids = append(ids, objectId{Type: line[:i], Id: line[i+1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read IDs file %q: %w", path, err)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("IDs file %q does not contain any IDs", path)
+	}
+	return ids, nil
+}
+
+// typesOfIds returns the config APIs and settings 2.0 schemas of the given IDs. All types that are not a known API are
+// treated as schemas.
+func typesOfIds(apis api.APIs, ids []objectId) (apiIds []string, schemaIds []string) {
+	configIds, objectIds := groupIds(apis, ids)
+	for a := range configIds {
+		apiIds = append(apiIds, a)
+	}
+	for s := range objectIds {
+		schemaIds = append(schemaIds, s)
+	}
+	sort.Strings(apiIds)
+	sort.Strings(schemaIds)
+	return apiIds, schemaIds
+}
+
+// groupIds groups the given IDs by config API and settings 2.0 schema
+func groupIds(apis api.APIs, ids []objectId) (configIds map[string][]string, objectIds map[string][]string) {
+	configIds = make(map[string][]string)
+	objectIds = make(map[string][]string)
+	for _, o := range ids {
+		if apis.Contains(o.Type) {
+			configIds[o.Type] = append(configIds[o.Type], o.Id)
+		} else {
+			objectIds[o.Type] = append(objectIds[o.Type], o.Id)
+		}
+	}
+	return configIds, objectIds
+}
+
+// reportMissingIds logs a warning for each of the given IDs no config was downloaded for, records them as failed in the
+// given report and returns them.
+func reportMissingIds(configs project.ConfigsPerType, ids []objectId, r *report.Report) []objectId {
+	downloaded := make(map[objectId]struct{})
+	for t, cfgs := range configs {
+		for _, c := range cfgs {
+			downloaded[objectId{Type: t, Id: downloadedId(c)}] = struct{}{}
+		}
+	}
+
+	var missing []objectId
+	for _, o := range ids {
+		if _, found := downloaded[o]; !found {
+			log.Warn("Object %q listed in IDs file was not found and has not been downloaded", o)
+			r.Fail(o.Type, o.Id, "", errors.New("listed in IDs file, but not found"))
+			missing = append(missing, o)
+		}
+	}
+	return missing
+}
+
+// downloadedId returns the ID the remote object of a downloaded config has
+func downloadedId(c config.Config) string {
+	if c.OriginObjectId != "" {
+		return c.OriginObjectId
+	}
+	return c.Coordinate.ConfigId
+}
@@ -0,0 +1,196 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadIdsFile(t *testing.T) {
+	t.Run("entries are split at the last colon", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		_ = afero.WriteFile(fs, "ids.txt", []byte(`
+# alerting profiles
+alerting-profile:1234
+builtin:alerting.profile:vu9U3hXa3q0AAAAB
+
+`), 0644)
+
+		ids, err := readIdsFile(fs, "ids.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, []objectId{
+			{Type: "alerting-profile", Id: "1234"},
+			{Type: "builtin:alerting.profile", Id: "vu9U3hXa3q0AAAAB"},
+		}, ids)
+	})
+
+	t.Run("invalid entries fail", func(t *testing.T) {
+		for _, line := range []string{"no-colon", ":id", "api:"} {
+			fs := afero.NewMemMapFs()
+			_ = afero.WriteFile(fs, "ids.txt", []byte(line), 0644)
+
+			_, err := readIdsFile(fs, "ids.txt")
+			assert.ErrorContains(t, err, "line 1", line)
+		}
+	})
+
+	t.Run("files without IDs fail", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		_ = afero.WriteFile(fs, "ids.txt", []byte("# nothing\n"), 0644)
+
+		_, err := readIdsFile(fs, "ids.txt")
+		assert.ErrorContains(t, err, "does not contain any IDs")
+	})
+
+	t.Run("missing files fail", func(t *testing.T) {
+		_, err := readIdsFile(afero.NewMemMapFs(), "ids.txt")
+		assert.Error(t, err)
+	})
+}
+
+func TestTypesOfIds(t *testing.T) {
+	apis := api.APIs{"alerting-profile": api.API{ID: "alerting-profile"}}
+	ids := []objectId{
+		{Type: "builtin:alerting.profile", Id: "b"},
+		{Type: "alerting-profile", Id: "1"},
+		{Type: "alerting-profile", Id: "2"},
+		{Type: "builtin:alerting.profile", Id: "a"},
+	}
+
+	apiIds, schemaIds := typesOfIds(apis, ids)
+	assert.Equal(t, []string{"alerting-profile"}, apiIds)
+	assert.Equal(t, []string{"builtin:alerting.profile"}, schemaIds)
+
+	configIds, objectIds := groupIds(apis, ids)
+	assert.Equal(t, map[string][]string{"alerting-profile": {"1", "2"}}, configIds)
+	assert.Equal(t, map[string][]string{"builtin:alerting.profile": {"b", "a"}}, objectIds)
+}
+
+func TestReportMissingIds(t *testing.T) {
+	configs := project.ConfigsPerType{
+		"alerting-profile": {
+			{Coordinate: coordinate.Coordinate{Type: "alerting-profile", ConfigId: "1"}},
+		},
+		"builtin:alerting.profile": {
+			{Coordinate: coordinate.Coordinate{Type: "builtin:alerting.profile", ConfigId: "generated"}, OriginObjectId: "a"},
+		},
+	}
+	ids := []objectId{
+		{Type: "alerting-profile", Id: "1"},
+		{Type: "alerting-profile", Id: "2"},
+		{Type: "builtin:alerting.profile", Id: "a"},
+	}
+
+	r := report.New()
+	missing := reportMissingIds(configs, ids, r)
+	assert.Equal(t, []objectId{{Type: "alerting-profile", Id: "2"}}, missing)
+	assert.Equal(t, []report.Object{{Id: "2", Error: "listed in IDs file, but not found"}}, r.Types["alerting-profile"].Failed)
+}
+
+func TestDownloadConfigsOfIds(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: "builtin:alerting.profile"}}, nil)
+	c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()["alerting-profile"]).Return([]client.Value{{Id: "1", Name: "one"}, {Id: "2", Name: "two"}}, nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), "1").Return([]byte(`{"name": "one"}`), nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		var result []client.DownloadSettingsObject
+		for _, o := range []client.DownloadSettingsObject{
+			{ObjectId: "a", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: []byte("{}")},
+			{ObjectId: "b", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: []byte("{}")},
+		} {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+
+	fs := afero.NewMemMapFs()
+	opts := downloadConfigsOptions{
+		downloadOptionsShared: downloadOptionsShared{
+			environmentURL:          "testurl.com",
+			auth:                    manifest.Auth{Token: manifest.AuthSecret{Name: "TEST_TOKEN_VAR", Value: "test.token"}},
+			outputFolder:            "folder",
+			projectName:             "project",
+			concurrentDownloadLimit: 1,
+		},
+		ids: []objectId{
+			{Type: "alerting-profile", Id: "1"},
+			{Type: "builtin:alerting.profile", Id: "a"},
+		},
+	}
+
+	err := doDownloadConfigs(context.TODO(), fs, c, api.NewAPIs(), opts)
+	assert.NoError(t, err)
+
+	exists, _ := afero.Exists(fs, "folder/project/alerting-profile/config.yaml")
+	assert.True(t, exists)
+	exists, _ = afero.Exists(fs, "folder/project/builtinalerting.profile/config.yaml")
+	assert.True(t, exists)
+}
+
+func TestDownloadConfigsOfIds_MissingIdsFailTheDownload(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: "builtin:alerting.profile"}}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		var result []client.DownloadSettingsObject
+		for _, o := range []client.DownloadSettingsObject{
+			{ObjectId: "a", SchemaId: "builtin:alerting.profile", Scope: "environment", Value: []byte("{}")},
+		} {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+
+	fs := afero.NewMemMapFs()
+	opts := downloadConfigsOptions{
+		downloadOptionsShared: downloadOptionsShared{
+			environmentURL:          "testurl.com",
+			auth:                    manifest.Auth{Token: manifest.AuthSecret{Name: "TEST_TOKEN_VAR", Value: "test.token"}},
+			outputFolder:            "folder",
+			projectName:             "project",
+			concurrentDownloadLimit: 1,
+		},
+		ids: []objectId{
+			{Type: "builtin:alerting.profile", Id: "a"},
+			{Type: "builtin:alerting.profile", Id: "missing"},
+		},
+	}
+
+	err := doDownloadConfigs(context.TODO(), fs, c, api.NewAPIs(), opts)
+	assert.EqualError(t, err, "1 object(s) listed in IDs file were not found")
+
+	exists, _ := afero.Exists(fs, "folder/project/builtinalerting.profile/config.yaml")
+	assert.True(t, exists, "objects found are downloaded nevertheless")
+	r, err := afero.ReadFile(fs, "folder/"+report.FileName)
+	assert.NoError(t, err)
+	assert.Contains(t, string(r), `"id": "missing"`)
+}
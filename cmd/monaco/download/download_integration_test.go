@@ -17,6 +17,7 @@
 package download
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
@@ -102,7 +103,7 @@ func TestDownloadIntegrationSimple(t *testing.T) {
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -168,7 +169,7 @@ func TestDownloadIntegrationWithReference(t *testing.T) {
 
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -254,7 +255,7 @@ func TestDownloadIntegrationWithMultipleApisAndReferences(t *testing.T) {
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -368,7 +369,7 @@ func TestDownloadIntegrationSingletonConfig(t *testing.T) {
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -432,7 +433,7 @@ func TestDownloadIntegrationSyntheticLocations(t *testing.T) {
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -488,6 +489,9 @@ func TestDownloadIntegrationDashboards(t *testing.T) {
 		"/dashboard/id-1": "dashboard/id-1.json",
 		"/dashboard/id-2": "dashboard/id-2.json", //"/dashboard/id-3": "dashboard/id-3.json", // MUST NEVER BE ACCESSED, pre-download filter remove the need to download it
 		"/dashboard/id-4": "dashboard/id-4.json",
+		"/api/config/v1/dashboards/id-1/shareSettings": "dashboard/id-1-share-settings.json",
+		"/api/config/v1/dashboards/id-2/shareSettings": "dashboard/id-2-share-settings.json",
+		"/api/config/v1/dashboards/id-4/shareSettings": "dashboard/id-4-share-settings.json",
 	}
 
 	// Server
@@ -497,7 +501,7 @@ func TestDownloadIntegrationDashboards(t *testing.T) {
 
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -529,7 +533,7 @@ func TestDownloadIntegrationDashboards(t *testing.T) {
 				},
 				Group:       "default",
 				Environment: projectName,
-				Template:    contentOnlyTemplate{`{"dashboardMetadata": {"name": "{{.name}}", "owner": "Q"}, "tiles": []}`},
+				Template:    contentOnlyTemplate{`{"dashboardMetadata": {"name": "{{.name}}", "owner": "Q"}, "tiles": [], "shareSettings": {"enabled": true, "permissions": [{"type": "ALL", "permission": "VIEW"}]}}`},
 				Type:        config.ClassicApiType{Api: "dashboard"},
 			},
 			{
@@ -540,7 +544,7 @@ func TestDownloadIntegrationDashboards(t *testing.T) {
 				},
 				Group:       "default",
 				Environment: projectName,
-				Template:    contentOnlyTemplate{`{"dashboardMetadata": {"name": "{{.name}}", "owner": "Admiral Jean-Luc Picard"}, "tiles": []}`},
+				Template:    contentOnlyTemplate{`{"dashboardMetadata": {"name": "{{.name}}", "owner": "Admiral Jean-Luc Picard"}, "tiles": [], "shareSettings": {"enabled": false, "permissions": []}}`},
 				Type:        config.ClassicApiType{Api: "dashboard"},
 			},
 		},
@@ -573,7 +577,7 @@ func TestDownloadIntegrationAnomalyDetectionMetrics(t *testing.T) {
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
 	// WHEN we download everything
-	err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, projectName))
 
 	assert.NilError(t, err)
 
@@ -709,7 +713,7 @@ func TestDownloadIntegrationHostAutoUpdate(t *testing.T) {
 
 			dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 			// WHEN we download everything
-			err := doDownloadConfigs(fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, testcase.projectName))
+			err := doDownloadConfigs(context.TODO(), fs, dtClient, apiMap, setupTestingDownloadOptions(t, server, testcase.projectName))
 
 			assert.NilError(t, err)
 
@@ -778,7 +782,7 @@ func TestDownloadIntegrationOverwritesFolderAndManifestIfForced(t *testing.T) {
 
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, dtClient, apis, options)
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apis, options)
 
 	assert.NilError(t, err)
 
@@ -866,7 +870,7 @@ func TestDownloadIntegrationDownloadsAPIsAndSettings(t *testing.T) {
 
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, dtClient, apis, opts)
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apis, opts)
 
 	assert.NilError(t, err)
 
@@ -926,7 +930,7 @@ func TestDownloadIntegrationDownloadsOnlyAPIsIfConfigured(t *testing.T) {
 	opts.onlyAPIs = true
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, dtClient, apis, opts)
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apis, opts)
 
 	assert.NilError(t, err)
 
@@ -983,7 +987,7 @@ func TestDownloadIntegrationDownloadsOnlySettingsIfConfigured(t *testing.T) {
 	opts.onlyAPIs = false
 	dtClient, _ := client.NewDynatraceClientForTesting(server.URL, server.Client())
 
-	err := doDownloadConfigs(fs, dtClient, apis, opts)
+	err := doDownloadConfigs(context.TODO(), fs, dtClient, apis, opts)
 
 	assert.NilError(t, err)
 
@@ -171,7 +171,7 @@ func Test_checkForCircularDependencies(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := reportForCircularDependencies(tt.args.proj)
+			err := reportForCircularDependencies([]project.Project{tt.args.proj})
 			if tt.wantErr {
 				assert.ErrorContains(t, err, "there are circular dependencies")
 			} else {
@@ -0,0 +1,82 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package findreferences
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func GetFindReferencesCommand(fs afero.Fs) (findReferencesCmd *cobra.Command) {
+	var opts Options
+	var workspacePath string
+
+	findReferencesCmd = &cobra.Command{
+		Use:   "find-references <project:type:id | object-id> [<manifest.yaml>]",
+		Short: "List all configs referring to a config or to a Dynatrace object",
+		Long: `List all configs referring to a config or to a Dynatrace object
+
+  If a config coordinate in the form 'project:type:id' is given, all configs referencing it in reference parameters
+  or in 'dependsOn' are listed.
+  Otherwise, the argument is treated as raw ID of a Dynatrace object, like an entity ID, and all configs containing it
+  in their templates or value parameters are listed.`,
+		Example: `monaco find-references infrastructure:management-zone:mz-hosts manifest.yaml
+monaco find-references HOST-1234567890ABCDEF manifest.yaml -e dev-environment
+monaco find-references payment/infrastructure:management-zone:mz-hosts --workspace workspace.yaml`,
+		Args:   cobra.RangeArgs(1, 2),
+		PreRun: cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if workspacePath != "" {
+				if len(args) > 1 {
+					return fmt.Errorf("manifest %q can not be used together with '--workspace'", args[1])
+				}
+				return FindReferencesInWorkspace(fs, workspacePath, args[0], opts)
+			}
+
+			manifestName, err := cmdutils.ResolveManifestPath(args[1:], opts.ManifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			return FindReferences(fs, manifestName, args[0], opts)
+		},
+	}
+
+	findReferencesCmd.Flags().StringSliceVarP(&opts.Environments, "environment", "e", []string{},
+		"Specify one (or multiple) environment(s) to search the configurations of. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'. "+
+			"If neither --group nor --environment is present, the configurations of all environments are searched.")
+	findReferencesCmd.Flags().StringSliceVarP(&opts.EnvironmentGroups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) to search the configurations of. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'")
+	cmdutils.AddManifestFromEnvFlag(findReferencesCmd, &opts.ManifestFromEnv)
+	cmdutils.AddWorkspaceFlag(findReferencesCmd, &workspacePath)
+
+	if err := findReferencesCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	findReferencesCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return findReferencesCmd
+}
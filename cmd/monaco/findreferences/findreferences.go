@@ -0,0 +1,116 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package findreferences
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/references"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/workspace"
+	"github.com/spf13/afero"
+)
+
+// Options defines which configurations are searched by FindReferences.
+type Options struct {
+	// ManifestFromEnv defines that the manifest is created from environment variables instead of being read from a file
+	ManifestFromEnv bool
+	// EnvironmentGroups restricts the search to the configurations of the given environment groups
+	EnvironmentGroups []string
+	// Environments restricts the search to the configurations of the given environments
+	Environments []string
+}
+
+// FindReferences loads the given manifest and logs all configs referring to the target. The target is either a config
+// coordinate ('project:type:id'), or the raw ID of a Dynatrace object.
+func FindReferences(fs afero.Fs, manifestPath string, target string, opts Options) error {
+	found, err := findInManifest(fs, manifestPath, target, opts)
+	if err != nil {
+		return err
+	}
+
+	messages := make([]string, len(found))
+	for i, r := range found {
+		messages[i] = r.String()
+	}
+	report(target, messages)
+	return nil
+}
+
+// FindReferencesInWorkspace searches the configs of all manifests of the given workspace file like FindReferences. A config
+// coordinate scoped by the name of its manifest ('<manifest>/project:type:id') is only searched within its manifest,
+// as references never cross manifests. References are reported with the name of their manifest.
+func FindReferencesInWorkspace(fs afero.Fs, workspacePath string, target string, opts Options) error {
+	w, err := workspace.Load(fs, workspacePath)
+	if err != nil {
+		return err
+	}
+	for _, names := range [][]string{{target}, opts.Environments, opts.EnvironmentGroups} {
+		if err := w.CheckScopes(names); err != nil {
+			return err
+		}
+	}
+
+	var messages []string
+	for _, m := range w.Manifests {
+		targets, targetSelected := m.Select([]string{target})
+		environments, envSelected := m.Select(opts.Environments)
+		groups, groupSelected := m.Select(opts.EnvironmentGroups)
+		if !targetSelected || !envSelected || !groupSelected {
+			continue
+		}
+
+		found, err := findInManifest(fs, m.Path, targets[0], Options{EnvironmentGroups: groups, Environments: environments})
+		if err != nil {
+			return fmt.Errorf("failed to search manifest %q: %w", m.Name, err)
+		}
+		for _, r := range found {
+			messages = append(messages, m.Scope(r.String()))
+		}
+	}
+	report(target, messages)
+	return nil
+}
+
+func findInManifest(fs afero.Fs, manifestPath string, target string, opts Options) ([]references.Reference, error) {
+	_, projects, err := cmdutils.LoadManifestAndProjects(fs, manifestPath, cmdutils.ManifestOptions{
+		Groups:       opts.EnvironmentGroups,
+		Environments: opts.Environments,
+		FromEnv:      opts.ManifestFromEnv,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if c, err := coordinate.Parse(target); err == nil {
+		return references.ToConfig(projects, c), nil
+	}
+	return references.ToId(projects, target), nil
+}
+
+func report(target string, messages []string) {
+	if len(messages) == 0 {
+		log.Info("No references to %q found", target)
+		return
+	}
+
+	log.Info("Found %d reference(s) to %q:", len(messages), target)
+	for _, msg := range messages {
+		log.Info("\t%s", msg)
+	}
+}
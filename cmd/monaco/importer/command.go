@@ -0,0 +1,60 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package importer
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// GetImportCommand returns the command converting Settings 2.0 objects given as JSON into configs.
+func GetImportCommand(fs afero.Fs) (importCmd *cobra.Command) {
+	var opts Options
+
+	importCmd = &cobra.Command{
+		Use:   "import <file.json> --project <project>",
+		Short: "Convert Settings 2.0 objects given as JSON into configs of a project",
+		Long: `Convert Settings 2.0 objects given as JSON into configs of a project
+
+  The file may contain the value of a single object as copied from the Dynatrace UI, a list of them, or objects
+  returned by the settings API, containing 'schemaId', 'scope' and 'value'. A config and template is created for each
+  object. The name of the config is taken from properties like 'name' or 'displayName', which are replaced by a
+  reference to the name parameter.
+
+  Configs are added to the config file of their schema in the project, which is created if it does not exist yet.`,
+		Example: "monaco import profile.json --schema builtin:alerting.profile --project alerting",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.File = args[0]
+			return Import(fs, opts)
+		},
+	}
+
+	importCmd.Flags().StringVarP(&opts.Schema, "schema", "s", "", "Settings 2.0 schema of the objects, e.g. 'builtin:alerting.profile'. Required unless the file contains the schema of each object")
+	importCmd.Flags().StringVar(&opts.Scope, "scope", "", "Scope of the objects. If not set, the scope contained in the file or 'environment' is used")
+	importCmd.Flags().StringVarP(&opts.Project, "project", "p", "", "Project to add the configs to")
+	importCmd.Flags().StringVarP(&opts.OutputFolder, "output-folder", "o", ".", "Folder containing the project folder")
+
+	if err := importCmd.MarkFlagRequired("project"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return importCmd
+}
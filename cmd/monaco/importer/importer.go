@@ -0,0 +1,330 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package importer converts Settings 2.0 objects copied from the Dynatrace UI or exported via the API into configs.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// nameFields are the properties a name is taken from, in order of preference. Nested properties are separated by dots.
+var nameFields = []string{"name", "displayName", "title", "summary", "label", "generalProperties.name", "metadata.name"}
+
+// Options defines the configs created by Import.
+type Options struct {
+	// File is the JSON file to import
+	File string
+	// Schema is the Settings 2.0 schema of the imported objects. It may be omitted if the file contains the schema
+	// of each object, like exports of the settings API do.
+	Schema string
+	// Scope is the scope of the imported objects. It defaults to the scope contained in the file, or 'environment'.
+	Scope string
+	// Project is the project the configs are added to
+	Project string
+	// OutputFolder is the folder containing the project folder
+	OutputFolder string
+}
+
+// object is a Settings 2.0 object read from the imported file
+type object struct {
+	schemaId string
+	scope    string
+	value    map[string]interface{}
+}
+
+// Import reads the Settings 2.0 objects of the given file and adds a config for each of them to the project. The file
+// may contain a single object value as copied from the UI, a list of them, or objects as returned by the settings
+// API, with 'schemaId', 'scope' and 'value', optionally wrapped in 'items'.
+//
+// The name of each config is taken heuristically from properties like 'name', which are replaced by a reference to
+// the name parameter in the template.
+func Import(fs afero.Fs, opts Options) error {
+	if opts.Project == "" {
+		return errors.New("project name must not be empty")
+	}
+
+	data, err := afero.ReadFile(fs, opts.File)
+	if err != nil {
+		return fmt.Errorf("failed to read %q: %w", opts.File, err)
+	}
+
+	objects, err := parseObjects(data, opts)
+	if err != nil {
+		return fmt.Errorf("failed to import %q: %w", opts.File, err)
+	}
+
+	ids := make(map[string]int)
+	configs := make([]config.Config, 0, len(objects))
+	for _, o := range objects {
+		c, err := newConfig(o, opts, ids)
+		if err != nil {
+			return fmt.Errorf("failed to import %q: %w", opts.File, err)
+		}
+		configs = append(configs, c)
+	}
+
+	if err := writeConfigs(fs, opts.OutputFolder, opts.Project, configs); err != nil {
+		return err
+	}
+
+	for _, c := range configs {
+		log.Info("Imported config %s", c.Coordinate)
+	}
+	log.Info("Imported %d config(s) into project %q", len(configs), opts.Project)
+	return nil
+}
+
+// parseObjects parses the objects of the imported file, applying the schema and scope of the options.
+func parseObjects(data []byte, opts Options) ([]object, error) {
+	var content interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if m, ok := content.(map[string]interface{}); ok {
+		if items, ok := m["items"].([]interface{}); ok {
+			content = items
+		}
+	}
+
+	var list []interface{}
+	if l, ok := content.([]interface{}); ok {
+		list = l
+	} else {
+		list = []interface{}{content}
+	}
+	if len(list) == 0 {
+		return nil, errors.New("no object found")
+	}
+
+	objects := make([]object, 0, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("entry %d is not a JSON object", i)
+		}
+
+		// objects of schemas with a 'value' property are only unwrapped if they are exports, which name their schema
+		o := object{value: m}
+		v, hasValue := m["value"].(map[string]interface{})
+		schemaId, hasSchemaId := m["schemaId"].(string)
+		if hasValue && hasSchemaId {
+			o.value = v
+			o.schemaId = schemaId
+			o.scope, _ = m["scope"].(string)
+		}
+
+		if opts.Schema != "" {
+			if o.schemaId != "" && o.schemaId != opts.Schema {
+				return nil, fmt.Errorf("entry %d is an object of schema %q, not %q", i, o.schemaId, opts.Schema)
+			}
+			o.schemaId = opts.Schema
+		}
+		if o.schemaId == "" {
+			return nil, fmt.Errorf("the schema of entry %d is unknown, please provide it", i)
+		}
+
+		if opts.Scope != "" {
+			o.scope = opts.Scope
+		} else if o.scope == "" {
+			o.scope = "environment"
+		}
+
+		objects = append(objects, o)
+	}
+	return objects, nil
+}
+
+// newConfig returns the config of the given object. Its ID is derived from its name, and made unique among the
+// imported configs using ids.
+func newConfig(o object, opts Options, ids map[string]int) (config.Config, error) {
+	name, found := extractName(o.value)
+	if !found {
+		name = strings.TrimSuffix(filepath.Base(opts.File), filepath.Ext(opts.File))
+		log.Warn("No name found in object of schema %q, using %q", o.schemaId, name)
+	}
+
+	id := configId(name)
+	if ids[id]++; ids[id] > 1 {
+		id = fmt.Sprintf("%s-%d", id, ids[id])
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(o.value); err != nil {
+		return config.Config{}, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return config.Config{
+		Template: template.NewDownloadTemplate(id, name, buf.String()),
+		Coordinate: coordinate.Coordinate{
+			Project:  opts.Project,
+			Type:     o.schemaId,
+			ConfigId: id,
+		},
+		Type: config.SettingsType{SchemaId: o.schemaId},
+		Parameters: map[string]parameter.Parameter{
+			config.NameParameter:  &value.ValueParameter{Value: name},
+			config.ScopeParameter: &value.ValueParameter{Value: o.scope},
+		},
+	}, nil
+}
+
+// extractName returns the first non-empty string of the nameFields, replacing it by a reference to the name parameter.
+func extractName(v map[string]interface{}) (string, bool) {
+	for _, f := range nameFields {
+		path := strings.Split(f, ".")
+		parent := v
+		for _, key := range path[:len(path)-1] {
+			parent, _ = parent[key].(map[string]interface{})
+		}
+
+		key := path[len(path)-1]
+		if name, ok := parent[key].(string); ok && strings.TrimSpace(name) != "" {
+			parent[key] = "{{.name}}"
+			return name, true
+		}
+	}
+	return "", false
+}
+
+var invalidIdChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+func configId(name string) string {
+	id := strings.Trim(invalidIdChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if id == "" {
+		return "config"
+	}
+	return id
+}
+
+// writeConfigs writes the configs into the project folder. If the config file of their type already exists, the
+// configs are appended to it, keeping its content and comments. Existing configs and templates are never overwritten.
+func writeConfigs(fs afero.Fs, outputFolder, project string, configs []config.Config) error {
+	generated := afero.NewMemMapFs()
+	errs := config.WriteConfigs(&config.WriterContext{
+		Fs:              generated,
+		OutputFolder:    ".",
+		ProjectFolder:   project,
+		ParametersSerde: config.ParameterParsers(),
+	}, configs)
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to write configs: %w", errors.Join(errs...))
+	}
+
+	files := make(map[string][]byte)
+	err := afero.Walk(generated, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		data, err := afero.ReadFile(generated, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(outputFolder, path)
+		exists, err := afero.Exists(fs, target)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !exists:
+			files[target] = data
+		case filepath.Base(path) == "config.yaml":
+			if files[target], err = appendConfigs(fs, target, data); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("file %q already exists", target)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for path, data := range files {
+		if err := fs.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return err
+		}
+		if err := afero.WriteFile(fs, path, data, 0664); err != nil {
+			return fmt.Errorf("failed to write %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// appendConfigs returns the config file at the given path with the configs of the generated file appended.
+func appendConfigs(fs afero.Fs, path string, generated []byte) ([]byte, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing, added yaml.Node
+	if err := yaml.Unmarshal(data, &existing); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	if err := yaml.Unmarshal(generated, &added); err != nil {
+		return nil, err
+	}
+
+	configs := yamlnode.Get(existing.Content[0], "configs")
+	if configs == nil || configs.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%q does not define configs", path)
+	}
+
+	for _, entry := range yamlnode.Items(yamlnode.Get(added.Content[0], "configs")) {
+		id := yamlnode.ScalarValue(entry, "id")
+		for _, e := range configs.Content {
+			if yamlnode.ScalarValue(e, "id") == id {
+				return nil, fmt.Errorf("config %q already exists in %q", id, path)
+			}
+		}
+		configs.Content = append(configs.Content, entry)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&existing); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,98 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package importer
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	t.Run("value copied from the UI", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "profile.json", []byte(`{"name": "Team A", "severityRules": []}`), 0644))
+
+		err := Import(fs, Options{File: "profile.json", Schema: "builtin:alerting.profile", Project: "alerting", OutputFolder: "out"})
+		assert.NoError(t, err)
+
+		configYaml, err := afero.ReadFile(fs, "out/alerting/builtinalerting.profile/config.yaml")
+		assert.NoError(t, err)
+		assert.Contains(t, string(configYaml), "id: team-a")
+		assert.Contains(t, string(configYaml), "name: Team A")
+		assert.Contains(t, string(configYaml), "schema: builtin:alerting.profile")
+		assert.Contains(t, string(configYaml), "scope: environment")
+
+		tmpl, err := afero.ReadFile(fs, "out/alerting/builtinalerting.profile/team-a.json")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name": "{{.name}}", "severityRules": []}`, string(tmpl))
+	})
+
+	t.Run("UI copies with a value property are not unwrapped", func(t *testing.T) {
+		objects, err := parseObjects([]byte(`{"name": "threshold", "value": {"amount": 5}}`), Options{Schema: "builtin:custom.schema"})
+		assert.NoError(t, err)
+		assert.Equal(t, []object{{
+			schemaId: "builtin:custom.schema",
+			scope:    "environment",
+			value:    map[string]interface{}{"name": "threshold", "value": map[string]interface{}{"amount": float64(5)}},
+		}}, objects)
+	})
+
+	t.Run("objects exported from the settings API are appended to existing configs", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		existing := "configs:\n# keep me\n- id: existing\n  type:\n    settings:\n      schema: builtin:alerting.maintenance-window\n      scope: environment\n  config:\n    template: existing.json\n"
+		assert.NoError(t, afero.WriteFile(fs, "alerting/builtinalerting.maintenance-window/config.yaml", []byte(existing), 0644))
+		assert.NoError(t, afero.WriteFile(fs, "export.json", []byte(`{"items": [
+			{"objectId": "a", "schemaId": "builtin:alerting.maintenance-window", "scope": "HOST-1", "value": {"generalProperties": {"name": "Backup"}}},
+			{"objectId": "b", "schemaId": "builtin:alerting.maintenance-window", "scope": "environment", "value": {"generalProperties": {"name": "Backup"}}}
+		]}`), 0644))
+
+		err := Import(fs, Options{File: "export.json", Project: "alerting", OutputFolder: "."})
+		assert.NoError(t, err)
+
+		configYaml, err := afero.ReadFile(fs, "alerting/builtinalerting.maintenance-window/config.yaml")
+		assert.NoError(t, err)
+		assert.Contains(t, string(configYaml), "# keep me")
+		assert.Contains(t, string(configYaml), "id: existing")
+		assert.Contains(t, string(configYaml), "id: backup\n")
+		assert.Contains(t, string(configYaml), "id: backup-2\n")
+		assert.Contains(t, string(configYaml), "scope: HOST-1")
+
+		tmpl, err := afero.ReadFile(fs, "alerting/builtinalerting.maintenance-window/backup.json")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"generalProperties": {"name": "{{.name}}"}}`, string(tmpl))
+	})
+
+	t.Run("existing configs are not overwritten", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "profile.json", []byte(`{"name": "Team A"}`), 0644))
+		opts := Options{File: "profile.json", Schema: "builtin:alerting.profile", Project: "alerting", OutputFolder: "."}
+
+		assert.NoError(t, Import(fs, opts))
+		assert.ErrorContains(t, Import(fs, opts), "already exists")
+	})
+
+	t.Run("schema is required", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "profile.json", []byte(`[{"name": "Team A"}]`), 0644))
+
+		err := Import(fs, Options{File: "profile.json", Project: "alerting"})
+		assert.ErrorContains(t, err, "schema of entry 0 is unknown")
+	})
+}
@@ -19,6 +19,7 @@
 package integrationtest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -136,7 +137,7 @@ func assertConfig(t *testing.T, client client.ConfigClient, theApi api.API, envi
 	var exists bool
 
 	if config.Skip {
-		exists, _, _ = client.ConfigExistsByName(theApi, name)
+		exists, _, _ = client.ConfigExistsByName(context.TODO(), theApi, name)
 		assert.Check(t, !exists, "Object should NOT be available, but was. environment.Environment: '%s', failed for '%s' (%s)", environment.Name, name, configType)
 		return
 	}
@@ -145,7 +146,7 @@ func assertConfig(t *testing.T, client client.ConfigClient, theApi api.API, envi
 
 	// To deal with delays of configs becoming available try for max 120 polling cycles (4min - at 2sec cycles) for expected state to be reached
 	err := wait(description, 120, func() bool {
-		exists, _, _ = client.ConfigExistsByName(theApi, name)
+		exists, _, _ = client.ConfigExistsByName(context.TODO(), theApi, name)
 		return (shouldBeAvailable && exists) || (!shouldBeAvailable && !exists)
 	})
 	assert.NilError(t, err)
@@ -159,7 +160,7 @@ func assertConfig(t *testing.T, client client.ConfigClient, theApi api.API, envi
 
 func assertSetting(t *testing.T, c client.SettingsClient, typ config.SettingsType, environment manifest.EnvironmentDefinition, shouldBeAvailable bool, config config.Config) {
 	expectedExtId := idutils.GenerateExternalID(typ.SchemaId, config.Coordinate.ConfigId)
-	objects, err := c.ListSettings(typ.SchemaId, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId == expectedExtId }})
+	objects, err := c.ListSettings(context.TODO(), typ.SchemaId, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId == expectedExtId }})
 	assert.NilError(t, err)
 
 	if len(objects) > 1 {
@@ -19,6 +19,7 @@
 package integrationtest
 
 import (
+	"context"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"strings"
 	"testing"
@@ -54,8 +55,12 @@ func cleanupConfigs(t *testing.T, apis api.APIs, c client.ConfigClient, suffix s
 			t.Logf("Skipping cleanup of legacy log monitoring API")
 			continue
 		}
+		if api.HasParent() {
+			t.Logf("Skipping cleanup of parent-scoped API %q, its configs are removed with their parents", api.ID)
+			continue
+		}
 
-		values, err := c.ListConfigs(api)
+		values, err := c.ListConfigs(context.TODO(), api)
 		if err != nil {
 			t.Logf("Failed to cleanup any test configs of type %q: %v", api.ID, err)
 		}
@@ -63,7 +68,7 @@ func cleanupConfigs(t *testing.T, apis api.APIs, c client.ConfigClient, suffix s
 		for _, value := range values {
 			// For the calculated-metrics-log API, the suffix is part of the ID, not name
 			if strings.HasSuffix(value.Name, suffix) || strings.HasSuffix(value.Id, suffix) {
-				err := c.DeleteConfigById(api, value.Id)
+				err := c.DeleteConfigById(context.TODO(), api, value.Id)
 				if err != nil {
 					t.Logf("Failed to cleanup test config: %s (%s): %v", value.Name, api.ID, err)
 				} else {
@@ -93,7 +98,7 @@ func cleanupSettings(t *testing.T, fs afero.Fs, manifestPath string, loadedManif
 }
 
 func deleteSettingsObjects(t *testing.T, schema, externalID string, c client.SettingsClient) {
-	objects, err := c.ListSettings(schema, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId == externalID }})
+	objects, err := c.ListSettings(context.TODO(), schema, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId == externalID }})
 	if err != nil {
 		t.Logf("Failed to cleanup test config: could not fetch settings 2.0 objects with schema ID %s: %v", schema, err)
 		return
@@ -105,7 +110,7 @@ func deleteSettingsObjects(t *testing.T, schema, externalID string, c client.Set
 	}
 
 	for _, obj := range objects {
-		err := c.DeleteSettings(obj.ObjectId)
+		err := c.DeleteSettings(context.TODO(), obj.ObjectId)
 		if err != nil {
 			t.Logf("Failed to cleanup test config: could not delete settings 2.0 object with object ID %s: %v", obj.ObjectId, err)
 		} else {
@@ -19,6 +19,7 @@
 package integrationtest
 
 import (
+	"context"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/testutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
@@ -34,7 +35,7 @@ import (
 
 func CreateDynatraceClient(t *testing.T, environment manifest.EnvironmentDefinition) client.Client {
 
-	c, err := cmdutils.CreateDTClient(environment.URL.Value, environment.Auth, false)
+	c, err := cmdutils.CreateDTClient(context.TODO(), environment.URL.Value, environment.Auth, false)
 	assert.NilError(t, err, "failed to create test client")
 
 	return c
@@ -20,6 +20,7 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/integrationtest"
@@ -107,7 +108,7 @@ func assertConfigAvailable(t *testing.T, client client.ConfigClient, env manifes
 	assert.Assert(t, found, "Config %s should have a known api, but does not. Api %s does not exist", config.Coordinate, typ.Api)
 
 	if config.Skip {
-		exists, _, err := client.ConfigExistsByName(a, fmt.Sprint(name))
+		exists, _, err := client.ConfigExistsByName(context.TODO(), a, fmt.Sprint(name))
 		assert.NilError(t, err)
 		assert.Check(t, !exists, "Config '%s' should NOT be available on env '%s', but was. environment.", env.Name, config.Coordinate)
 
@@ -119,7 +120,7 @@ func assertConfigAvailable(t *testing.T, client client.ConfigClient, env manifes
 	exists := false
 	// To deal with delays of configs becoming available try for max 120 polling cycles (4min - at 2sec cycles) for expected state to be reached
 	err = wait(description, 120, func() bool {
-		exists, _, err = client.ConfigExistsByName(a, fmt.Sprint(name))
+		exists, _, err = client.ConfigExistsByName(context.TODO(), a, fmt.Sprint(name))
 		return (shouldBeAvailable && exists) || (!shouldBeAvailable && !exists)
 	})
 	assert.NilError(t, err)
@@ -19,6 +19,7 @@
 package v2
 
 import (
+	"context"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/integrationtest"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/testutils"
@@ -70,12 +71,12 @@ func cleanupTestConfigs(t *testing.T, apis api.APIs, client client.ConfigClient,
 			continue
 		}
 
-		values, err := client.ListConfigs(api)
+		values, err := client.ListConfigs(context.TODO(), api)
 		assert.NilError(t, err)
 
 		for _, value := range values {
 			if testSuffixRegex.MatchString(value.Name) || testSuffixRegex.MatchString(value.Id) {
-				err := client.DeleteConfigById(api, value.Id)
+				err := client.DeleteConfigById(context.TODO(), api, value.Id)
 				if err != nil {
 					t.Errorf("failed to delete %s (%s): %v", value.Name, api.ID, err)
 				} else {
@@ -91,12 +92,12 @@ func cleanupTestConfigs(t *testing.T, apis api.APIs, client client.ConfigClient,
 func cleanupTestSettings(t *testing.T, c client.SettingsClient) int {
 	deletedSettings := 0
 
-	schemas, err := c.ListSchemas()
+	schemas, err := c.ListSchemas(context.TODO())
 	assert.NilError(t, err)
 
 	for _, s := range schemas {
 		schemaId := s.SchemaId
-		objects, err := c.ListSettings(schemaId, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId != "" }})
+		objects, err := c.ListSettings(context.TODO(), schemaId, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId != "" }})
 		if err != nil {
 			t.Errorf("could not fetch settings 2.0 objects with schema %s: %v", schemaId, err)
 		}
@@ -106,7 +107,7 @@ func cleanupTestSettings(t *testing.T, c client.SettingsClient) int {
 		}
 
 		for _, obj := range objects {
-			err := c.DeleteSettings(obj.ObjectId)
+			err := c.DeleteSettings(context.TODO(), obj.ObjectId)
 			if err != nil {
 				t.Errorf("failed to delete %q object: %s (extId: %s): %v", obj.ObjectId, obj.ExternalId, schemaId, err)
 			} else {
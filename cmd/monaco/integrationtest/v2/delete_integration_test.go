@@ -109,7 +109,7 @@ func TestDelete(t *testing.T) {
 
 			// DELETE Config
 			cmd = runner.BuildCli(fs)
-			baseCmd := []string{"delete", "--verbose"}
+			baseCmd := []string{"delete", "--verbose", "--yes"}
 			cmd.SetArgs(append(baseCmd, tt.cmdFlags...))
 			err = cmd.Execute()
 			assert.NilError(t, err)
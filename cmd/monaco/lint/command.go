@@ -0,0 +1,85 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func GetLintCommand(fs afero.Fs) (lintCmd *cobra.Command) {
+	var opts Options
+	var workspacePath string
+
+	lintCmd = &cobra.Command{
+		Use:   "lint [<manifest.yaml>]",
+		Short: "Check configurations for likely mistakes, like unused or undefined parameters",
+		Example: `monaco lint manifest.yaml -e dev-environment
+monaco lint --workspace workspace.yaml -p payment/infrastructure`,
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if workspacePath != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("manifest %q can not be used together with '--workspace'", args[0])
+				}
+				return LintWorkspace(fs, workspacePath, opts)
+			}
+
+			manifestName, err := cmdutils.ResolveManifestPath(args, opts.ManifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			return Lint(fs, manifestName, opts)
+		},
+	}
+
+	lintCmd.Flags().StringSliceVarP(&opts.Environments, "environment", "e", []string{},
+		"Specify one (or multiple) environment(s) to check the configurations of. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'. "+
+			"If neither --group nor --environment is present, the configurations of all environments are checked.")
+	lintCmd.Flags().StringSliceVarP(&opts.EnvironmentGroups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) to check the configurations of. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'")
+	lintCmd.Flags().StringSliceVarP(&opts.Projects, "project", "p", []string{}, "Project(s) to check. If not set, all projects are checked")
+	lintCmd.Flags().BoolVar(&opts.Fix, "fix", false, "Replace IDs of objects deployed by other configs, which are contained as is in templates, with reference parameters. "+
+		"Objects are identified by the IDs configs were downloaded from, including the ones recorded in download snapshots")
+	lintCmd.Flags().StringSliceVar(&opts.DisabledRules, "disable-rule", []string{}, "Id(s) of rules not to apply, e.g. 'hardcoded-value'. "+
+		"To disable multiple rules either repeat this flag, or separate them using a comma (,)")
+	cmdutils.AddManifestFromEnvFlag(lintCmd, &opts.ManifestFromEnv)
+	cmdutils.AddWorkspaceFlag(lintCmd, &workspacePath)
+
+	if err := lintCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	if err := lintCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	lintCmd.MarkFlagsMutuallyExclusive("environment", "group")
+
+	return lintCmd
+}
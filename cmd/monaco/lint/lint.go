@@ -0,0 +1,320 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/lint"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/refactor"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/workspace"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+)
+
+// Options defines which configurations are checked by Lint.
+type Options struct {
+	// ManifestFromEnv defines that the manifest is created from environment variables instead of being read from a file
+	ManifestFromEnv bool
+	// EnvironmentGroups restricts the check to the configurations of the given environment groups
+	EnvironmentGroups []string
+	// Environments restricts the check to the configurations of the given environments
+	Environments []string
+	// Projects restricts the check to the given projects
+	Projects []string
+	// Fix states that raw IDs of objects deployed by other configs are replaced with reference parameters before the
+	// configurations are checked
+	Fix bool
+	// DisabledRules holds the ids of rules which are not applied
+	DisabledRules []string
+}
+
+// Lint loads the given manifest and checks all configurations of the (specified) projects for the (specified)
+// environments using the default lint rules. All findings are logged, and an error is returned if there are any.
+func Lint(fs afero.Fs, manifestPath string, opts Options) error {
+	findings, err := lintManifest(fs, manifestPath, opts)
+	if err != nil {
+		return err
+	}
+
+	var r findingsReport
+	r.add(findings, func(msg string) string { return msg })
+	return r.report()
+}
+
+// LintWorkspace checks the configurations of all manifests of the given workspace file like Lint. Findings are
+// reported with the name of their manifest. Environments, groups and projects to check are either scoped by the name
+// of their manifest ('<manifest>/<name>'), or apply to all manifests.
+func LintWorkspace(fs afero.Fs, workspacePath string, opts Options) error {
+	w, err := workspace.Load(fs, workspacePath)
+	if err != nil {
+		return err
+	}
+	for _, names := range [][]string{opts.Environments, opts.EnvironmentGroups, opts.Projects} {
+		if err := w.CheckScopes(names); err != nil {
+			return err
+		}
+	}
+
+	var r findingsReport
+	for _, m := range w.Manifests {
+		manifestOpts, selected := selectForManifest(m, opts)
+		if !selected {
+			log.Debug("Skipping manifest %q, as no selected environment, group or project applies to it", m.Name)
+			continue
+		}
+
+		log.Info("Checking manifest %q", m.Name)
+		findings, err := lintManifest(fs, m.Path, manifestOpts)
+		if err != nil {
+			return fmt.Errorf("failed to check manifest %q: %w", m.Name, err)
+		}
+		r.add(findings, m.Scope)
+	}
+	return r.report()
+}
+
+// selectForManifest returns the options applying to the given manifest of a workspace. False is returned if the
+// options restrict the check to other manifests.
+func selectForManifest(m workspace.Manifest, opts Options) (Options, bool) {
+	environments, envSelected := m.Select(opts.Environments)
+	groups, groupSelected := m.Select(opts.EnvironmentGroups)
+	projects, projectSelected := m.Select(opts.Projects)
+
+	return Options{
+		EnvironmentGroups: groups,
+		Environments:      environments,
+		Projects:          projects,
+		Fix:               opts.Fix,
+		DisabledRules:     opts.DisabledRules,
+	}, envSelected && groupSelected && projectSelected
+}
+
+func lintManifest(fs afero.Fs, manifestPath string, opts Options) ([]lint.Finding, error) {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, projects, err := cmdutils.LoadManifestAndProjects(fs, absManifestPath, cmdutils.ManifestOptions{
+		Groups:         opts.EnvironmentGroups,
+		Environments:   opts.Environments,
+		FromEnv:        opts.ManifestFromEnv,
+		StrictProjects: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Projects) > 0 {
+		projects, err = filterProjects(projects, opts.Projects)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	known, err := cmdutils.LoadKnownObjects(fs, absManifestPath, m, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Fix {
+		fixed, err := fixRawReferences(fs, absManifestPath, m, projects, known)
+		if err != nil {
+			return nil, err
+		}
+		if fixed > 0 {
+			// the changed configs are loaded and checked again
+			opts.Fix = false
+			return lintManifest(fs, manifestPath, opts)
+		}
+	}
+
+	rules := append([]lint.Rule{}, lint.DefaultRules...)
+	rules = append(rules, lint.ReferenceSuggestionRule(known))
+	rules, err = lint.WithoutRules(rules, opts.DisabledRules)
+	if err != nil {
+		return nil, err
+	}
+	return lint.Lint(projects, rules), nil
+}
+
+// fixRawReferences replaces the raw IDs of known objects in the templates of the given projects with reference
+// parameters. It returns the number of replaced IDs.
+func fixRawReferences(fs afero.Fs, manifestPath string, m manifest.Manifest, projects []project.Project, known lint.KnownObjects) (int, error) {
+	var refs []refactor.RawReference
+	seen := make(map[refactor.RawReference]bool)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			for _, r := range lint.FindRawReferences(c, known) {
+				ref := refactor.RawReference{Config: c.Coordinate, Id: r.Id, Target: r.Target}
+				if !seen[ref] {
+					seen[ref] = true
+					refs = append(refs, ref)
+				}
+			}
+		})
+	}
+	refs = applicableReferences(projects, refs)
+	if len(refs) == 0 {
+		return 0, nil
+	}
+
+	fixed, err := refactor.UseReferences(afero.NewBasePathFs(fs, filepath.Dir(manifestPath)), m.Projects, refs)
+	if err != nil {
+		return fixed, fmt.Errorf("failed to replace raw IDs with references: %w", err)
+	}
+	log.Info("Replaced %d raw ID(s) with reference parameters", fixed)
+	return fixed, nil
+}
+
+// applicableReferences returns the raw references which can be replaced by reference parameters. References are not
+// applicable if the referenced config is not deployed to all environments the referencing config is deployed to, or
+// if the reference would introduce a circular dependency.
+func applicableReferences(projects []project.Project, refs []refactor.RawReference) []refactor.RawReference {
+	deployed := make(map[string]map[coordinate.Coordinate]bool)
+	dependencies := make(map[coordinate.Coordinate][]coordinate.Coordinate)
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			if deployed[env] == nil {
+				deployed[env] = make(map[coordinate.Coordinate]bool)
+			}
+			for _, configs := range configsPerType {
+				for _, c := range configs {
+					deployed[env][c.Coordinate] = !c.Skip
+					dependencies[c.Coordinate] = append(dependencies[c.Coordinate], c.References()...)
+				}
+			}
+		}
+	}
+
+	// dependsOn returns whether the config depends on the other one, directly or transitively
+	dependsOn := func(c, other coordinate.Coordinate) bool {
+		visited := make(map[coordinate.Coordinate]bool)
+		queue := []coordinate.Coordinate{c}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			if current == other {
+				return true
+			}
+			if !visited[current] {
+				visited[current] = true
+				queue = append(queue, dependencies[current]...)
+			}
+		}
+		return false
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Config != refs[j].Config {
+			return refs[i].Config.String() < refs[j].Config.String()
+		}
+		return refs[i].Id < refs[j].Id
+	})
+
+	var applicable []refactor.RawReference
+	for _, r := range refs {
+		if env, skipped := skippedIn(deployed, r.Config, r.Target); skipped {
+			log.Warn("Not replacing ID %q in config %s, as config %s is not deployed to environment %q", r.Id, r.Config, r.Target, env)
+			continue
+		}
+		if dependsOn(r.Target, r.Config) {
+			log.Warn("Not replacing ID %q in config %s, as a reference to config %s would introduce a circular dependency", r.Id, r.Config, r.Target)
+			continue
+		}
+		dependencies[r.Config] = append(dependencies[r.Config], r.Target)
+		applicable = append(applicable, r)
+	}
+	return applicable
+}
+
+// skippedIn returns an environment the config is deployed to, but the target is not, if any.
+func skippedIn(deployed map[string]map[coordinate.Coordinate]bool, c, target coordinate.Coordinate) (string, bool) {
+	envs := make([]string, 0, len(deployed))
+	for env := range deployed {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+
+	for _, env := range envs {
+		if deployed[env][c] && !deployed[env][target] {
+			return env, true
+		}
+	}
+	return "", false
+}
+
+// findingsReport collects the messages of findings, separated into problems and warnings.
+type findingsReport struct {
+	problems, warnings []string
+}
+
+func (r *findingsReport) add(findings []lint.Finding, scope func(string) string) {
+	for _, f := range findings {
+		if f.Warning {
+			r.warnings = append(r.warnings, scope(f.String()))
+		} else {
+			r.problems = append(r.problems, scope(f.String()))
+		}
+	}
+}
+
+// report logs all collected messages. Only problems fail the check, warnings are logged only.
+func (r *findingsReport) report() error {
+	for _, msg := range r.warnings {
+		log.Warn(msg)
+	}
+	for _, msg := range r.problems {
+		log.Error(msg)
+	}
+
+	if len(r.problems) > 0 {
+		return fmt.Errorf("found %d problems", len(r.problems))
+	}
+	if len(r.warnings) > 0 {
+		log.Info("No problems found, but %d warnings", len(r.warnings))
+		return nil
+	}
+	log.Info("No problems found")
+	return nil
+}
+
+func filterProjects(projects []project.Project, ids []string) ([]project.Project, error) {
+	var filtered []project.Project
+	found := make(map[string]struct{}, len(ids))
+	for _, p := range projects {
+		if slices.Contains(ids, p.Id) {
+			filtered = append(filtered, p)
+			found[p.Id] = struct{}{}
+		}
+	}
+
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return nil, fmt.Errorf("unknown project %q", id)
+		}
+	}
+	return filtered, nil
+}
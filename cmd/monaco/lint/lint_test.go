@@ -0,0 +1,189 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/refactor"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://abcde.dev.dynatracelabs.com
+    auth:
+      token:
+        type: environment
+        name: ENV_TOKEN
+`
+	configYaml := `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+    parameters:
+      used: value
+      unused: value
+  type:
+    api: alerting-profile
+`
+
+	writeManifest := func(fs afero.Fs, folder string, template string) string {
+		configPath, _ := filepath.Abs(filepath.Join(folder, "project/alerting-profile/profile.yaml"))
+		_ = afero.WriteFile(fs, configPath, []byte(configYaml), 0644)
+		templatePath, _ := filepath.Abs(filepath.Join(folder, "project/alerting-profile/profile.json"))
+		_ = afero.WriteFile(fs, templatePath, []byte(template), 0644)
+		manifestPath, _ := filepath.Abs(filepath.Join(folder, "manifest.yaml"))
+		_ = afero.WriteFile(fs, manifestPath, []byte(manifestYaml), 0644)
+		return manifestPath
+	}
+	newFs := func(template string) (afero.Fs, string) {
+		fs := afero.NewMemMapFs()
+		return fs, writeManifest(fs, ".", template)
+	}
+
+	t.Run("findings are reported", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"used": "{{ .used }}", "undefined": "{{ .undefined }}"}`)
+		err := Lint(fs, manifestPath, Options{})
+		assert.EqualError(t, err, "found 2 problems")
+	})
+
+	t.Run("no findings", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"used": "{{ .used }}", "unused": "{{ .unused }}"}`)
+		err := Lint(fs, manifestPath, Options{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("hard-coded values are warnings only", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"used": "{{ .used }}", "unused": "{{ .unused }}", "recipient": "ops@example.com"}`)
+		err := Lint(fs, manifestPath, Options{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled rules are not applied", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"used": "{{ .used }}"}`)
+		assert.EqualError(t, Lint(fs, manifestPath, Options{}), "found 1 problems")
+		assert.NoError(t, Lint(fs, manifestPath, Options{DisabledRules: []string{"unused-parameter"}}))
+		assert.EqualError(t, Lint(fs, manifestPath, Options{DisabledRules: []string{"unknown"}}), "unknown rule(s) unknown")
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		fs, manifestPath := newFs(`{}`)
+		err := Lint(fs, manifestPath, Options{Projects: []string{"unknown"}})
+		assert.EqualError(t, err, `unknown project "unknown"`)
+	})
+
+	t.Run("raw references are reported and fixed", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"used": "{{ .used }}", "unused": "{{ .unused }}", "profile": "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU"}`)
+		dir := filepath.Dir(manifestPath)
+		_ = afero.WriteFile(fs, filepath.Join(dir, "project/settings/profile.yaml"), []byte(`configs:
+- id: settings-profile
+  config:
+    name: profile
+    originObjectId: vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU
+    template: profile.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+`), 0644)
+		_ = afero.WriteFile(fs, filepath.Join(dir, "project/settings/profile.json"), []byte(`{}`), 0644)
+
+		assert.EqualError(t, Lint(fs, manifestPath, Options{}), "found 1 problems")
+		assert.NoError(t, Lint(fs, manifestPath, Options{Fix: true}))
+
+		template, _ := afero.ReadFile(fs, filepath.Join(dir, "project/alerting-profile/profile.json"))
+		assert.Contains(t, string(template), `"profile": "{{ .settings_profileId }}"`)
+		config, _ := afero.ReadFile(fs, filepath.Join(dir, "project/alerting-profile/profile.yaml"))
+		assert.Contains(t, string(config), "configType: builtin:alerting.profile")
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		writeManifest(fs, "payment", `{"used": "{{ .used }}", "undefined": "{{ .undefined }}"}`)
+		writeManifest(fs, "shipping", `{"used": "{{ .used }}", "unused": "{{ .unused }}"}`)
+		workspacePath, _ := filepath.Abs("workspace.yaml")
+		_ = afero.WriteFile(fs, workspacePath, []byte("manifests:\n- path: payment/manifest.yaml\n- path: shipping/manifest.yaml\n"), 0644)
+
+		assert.EqualError(t, LintWorkspace(fs, workspacePath, Options{}), "found 2 problems")
+		assert.EqualError(t, LintWorkspace(fs, workspacePath, Options{Projects: []string{"payment/project"}}), "found 2 problems")
+		assert.NoError(t, LintWorkspace(fs, workspacePath, Options{Projects: []string{"shipping/project"}}))
+		assert.NoError(t, LintWorkspace(fs, workspacePath, Options{Environments: []string{"shipping/env"}}))
+		assert.EqualError(t, LintWorkspace(fs, workspacePath, Options{Projects: []string{"billing/project"}}), `"billing/project" is scoped by unknown manifest "billing"`)
+	})
+}
+
+func Test_applicableReferences(t *testing.T) {
+	dashboard := coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard"}
+	profile := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	notification := coordinate.Coordinate{Project: "project", Type: "builtin:problem.notifications", ConfigId: "notification"}
+	zone := coordinate.Coordinate{Project: "project", Type: "builtin:management-zones", ConfigId: "zone"}
+
+	newConfig := func(env string, c coordinate.Coordinate, skip bool, refs ...coordinate.Coordinate) config.Config {
+		params := config.Parameters{}
+		for _, r := range refs {
+			params[r.ConfigId] = reference.NewWithCoordinate(r, "id")
+		}
+		return config.Config{Coordinate: c, Environment: env, Skip: skip, Parameters: params}
+	}
+	p := project.Project{
+		Id: "project",
+		Configs: project.ConfigsPerTypePerEnvironments{
+			"dev": {
+				"dashboard":                     {newConfig("dev", dashboard, false)},
+				"builtin:alerting.profile":      {newConfig("dev", profile, false)},
+				"builtin:problem.notifications": {newConfig("dev", notification, false, profile)},
+				"builtin:management-zones":      {newConfig("dev", zone, false)},
+			},
+			"prod": {
+				"dashboard":                     {newConfig("prod", dashboard, false)},
+				"builtin:alerting.profile":      {newConfig("prod", profile, false)},
+				"builtin:problem.notifications": {newConfig("prod", notification, false, profile)},
+				"builtin:management-zones":      {newConfig("prod", zone, true)},
+			},
+		},
+	}
+
+	got := applicableReferences([]project.Project{p}, []refactor.RawReference{
+		{Config: profile, Id: "notification-id", Target: notification},
+		{Config: dashboard, Id: "zone-id", Target: zone},
+		{Config: dashboard, Id: "profile-id", Target: profile},
+		{Config: profile, Id: "dashboard-id", Target: dashboard},
+	})
+
+	// references are applied in order of their config, thus the reference of the profile to the dashboard wins
+	assert.Equal(t, []refactor.RawReference{
+		{Config: profile, Id: "dashboard-id", Target: dashboard},
+	}, got)
+}
@@ -0,0 +1,176 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maintenance
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"strings"
+	"time"
+)
+
+// GetMaintenanceCommand returns the command group to create and delete ad hoc maintenance windows.
+func GetMaintenanceCommand(fs afero.Fs) *cobra.Command {
+	maintenanceCmd := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Create and delete ad hoc maintenance windows",
+		Long: `Create and delete ad hoc maintenance windows
+
+  Maintenance windows for one-off events like change freezes can be created without defining them in a project.
+  They are created as 'builtin:alerting.maintenance-window' settings objects identified by their name, so they can be
+  updated by creating them again, and deleted afterwards.`,
+	}
+
+	maintenanceCmd.AddCommand(getCreateCommand(fs))
+	maintenanceCmd.AddCommand(getDeleteCommand(fs))
+
+	return maintenanceCmd
+}
+
+func getCreateCommand(fs afero.Fs) (createCmd *cobra.Command) {
+	var environment, from, to, name, description, suppression string
+	var scopes []string
+	var dryRun, manifestFromEnv bool
+	var timeout time.Duration
+
+	createCmd = &cobra.Command{
+		Use:     "create [<manifest.yaml>]",
+		Short:   "Create a maintenance window taking place once",
+		Example: "monaco maintenance create manifest.yaml -e prod --name change-freeze --from 2023-12-22T18:00:00Z --to 2024-01-02T08:00:00Z --scope tag:owner:team-a",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			w := Window{Name: name, Description: description, Suppression: suppression}
+			if w.From, err = parseTime(from, "from"); err != nil {
+				return err
+			}
+			if w.To, err = parseTime(to, "to"); err != nil {
+				return err
+			}
+			for _, s := range scopes {
+				scope, err := ParseScope(s)
+				if err != nil {
+					return err
+				}
+				w.Scopes = append(w.Scopes, scope)
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			if dryRun {
+				return Create(ctx, nil, w, true)
+			}
+
+			c, err := cmdutils.CreateEnvironmentClient(ctx, fs, manifestName, manifestFromEnv, environment)
+			if err != nil {
+				return err
+			}
+			return Create(ctx, c, w, false)
+		},
+	}
+
+	createCmd.Flags().StringVarP(&environment, "environment", "e", "", "The environment defined in the manifest to create the maintenance window in")
+	createCmd.Flags().StringVar(&name, "name", "", "Name of the maintenance window. Creating a window with the name of an existing one updates it")
+	createCmd.Flags().StringVar(&description, "description", "", "Description of the maintenance window")
+	createCmd.Flags().StringVar(&from, "from", "now", "Start of the maintenance window in RFC3339 format, e.g. '2023-12-22T18:00:00+01:00', or 'now'")
+	createCmd.Flags().StringVar(&to, "to", "", "End of the maintenance window in RFC3339 format, e.g. '2024-01-02T08:00:00+01:00'")
+	createCmd.Flags().StringSliceVar(&scopes, "scope", nil, "Restrict the maintenance window to entities matching 'tag:<key>[:<value>]', 'entity:<id>', 'type:<entity type>' or 'mz:<management zone id>'. "+
+		"Repeat the flag to add several scopes. If not set, the whole environment is affected")
+	createCmd.Flags().StringVar(&suppression, "suppression", DontDetectProblems, fmt.Sprintf("How problems are handled during the maintenance window, one of %s", strings.Join(suppressions, ", ")))
+	createCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Print the maintenance window instead of creating it")
+	cmdutils.AddManifestFromEnvFlag(createCmd, &manifestFromEnv)
+	cmdutils.AddTimeoutFlag(createCmd, &timeout)
+
+	for _, f := range []string{"environment", "name", "to"} {
+		if err := createCmd.MarkFlagRequired(f); err != nil {
+			log.Fatal("failed to setup CLI %v", err)
+		}
+	}
+	if err := createCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return createCmd
+}
+
+func getDeleteCommand(fs afero.Fs) (deleteCmd *cobra.Command) {
+	var environment, name string
+	var dryRun, manifestFromEnv bool
+	var timeout time.Duration
+
+	deleteCmd = &cobra.Command{
+		Use:     "delete [<manifest.yaml>]",
+		Short:   "Delete a maintenance window created by 'monaco maintenance create'",
+		Example: "monaco maintenance delete manifest.yaml -e prod --name change-freeze",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			c, err := cmdutils.CreateEnvironmentClient(ctx, fs, manifestName, manifestFromEnv, environment)
+			if err != nil {
+				return err
+			}
+			return Delete(ctx, c, name, dryRun)
+		},
+	}
+
+	deleteCmd.Flags().StringVarP(&environment, "environment", "e", "", "The environment defined in the manifest to delete the maintenance window from")
+	deleteCmd.Flags().StringVar(&name, "name", "", "Name of the maintenance window")
+	deleteCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Only look up the maintenance window instead of deleting it")
+	cmdutils.AddManifestFromEnvFlag(deleteCmd, &manifestFromEnv)
+	cmdutils.AddTimeoutFlag(deleteCmd, &timeout)
+
+	for _, f := range []string{"environment", "name"} {
+		if err := deleteCmd.MarkFlagRequired(f); err != nil {
+			log.Fatal("failed to setup CLI %v", err)
+		}
+	}
+	if err := deleteCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return deleteCmd
+}
+
+// parseTime parses the value of the time flag of the given name.
+func parseTime(value, flag string) (time.Time, error) {
+	if value == "now" {
+		return time.Now(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid value %q of '--%s'! expected a time in RFC3339 format, e.g. '2023-12-22T18:00:00+01:00'", value, flag)
+	}
+	return t, nil
+}
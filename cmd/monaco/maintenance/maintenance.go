@@ -0,0 +1,194 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"strings"
+	"time"
+)
+
+// schemaId is the settings schema of maintenance windows
+const schemaId = "builtin:alerting.maintenance-window"
+
+// idPrefix prefixes the names of ad hoc maintenance windows to build the ID their externalId is generated from. This
+// keeps them apart from maintenance windows deployed by projects.
+const idPrefix = "monaco-maintenance-"
+
+// Suppression modes of maintenance windows
+const (
+	DetectProblemsAndAlert  = "DETECT_PROBLEMS_AND_ALERT"
+	DetectProblemsDontAlert = "DETECT_PROBLEMS_DONT_ALERT"
+	DontDetectProblems      = "DONT_DETECT_PROBLEMS"
+)
+
+var suppressions = []string{DetectProblemsAndAlert, DetectProblemsDontAlert, DontDetectProblems}
+
+// Window defines an ad hoc maintenance window taking place once.
+type Window struct {
+	Name        string
+	Description string
+	From        time.Time
+	To          time.Time
+	// Suppression is one of the suppression modes, e.g. DontDetectProblems
+	Suppression string
+	// Scopes restrict the maintenance window to matching entities. Without scopes, the whole environment is affected.
+	Scopes []Scope
+}
+
+// Scope is a filter selecting the entities affected by a maintenance window.
+type Scope struct {
+	EntityType      string   `json:"entityType,omitempty"`
+	EntityId        string   `json:"entityId,omitempty"`
+	EntityTags      []string `json:"entityTags"`
+	ManagementZones []string `json:"managementZones"`
+}
+
+// ParseScope parses scopes given as 'tag:<key>[:<value>]', 'entity:<id>', 'type:<entity type>' or 'mz:<management zone id>'.
+func ParseScope(s string) (Scope, error) {
+	kind, value, found := strings.Cut(s, ":")
+	if !found || value == "" {
+		return Scope{}, fmt.Errorf("invalid scope %q! expected one of 'tag:<key>[:<value>]', 'entity:<id>', 'type:<entity type>' or 'mz:<management zone id>'", s)
+	}
+
+	scope := Scope{EntityTags: []string{}, ManagementZones: []string{}}
+	switch kind {
+	case "tag":
+		scope.EntityTags = []string{value}
+	case "entity":
+		scope.EntityId = value
+	case "type":
+		scope.EntityType = value
+	case "mz":
+		scope.ManagementZones = []string{value}
+	default:
+		return Scope{}, fmt.Errorf("unknown kind %q of scope %q! expected one of 'tag', 'entity', 'type' or 'mz'", kind, s)
+	}
+	return scope, nil
+}
+
+// validate returns an error if the window can not be created.
+func (w Window) validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("maintenance windows require a name")
+	}
+	if !w.To.After(w.From) {
+		return fmt.Errorf("the end of the maintenance window (%s) must be after its start (%s)", w.To.Format(time.RFC3339), w.From.Format(time.RFC3339))
+	}
+	for _, s := range suppressions {
+		if w.Suppression == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown suppression %q! expected one of %s", w.Suppression, strings.Join(suppressions, ", "))
+}
+
+// payload returns the settings object value of the window.
+func (w Window) payload() ([]byte, error) {
+	// start and end are local times without offset, of the time zone given in the schedule
+	const layout = "2006-01-02T15:04:05"
+
+	scopes := w.Scopes
+	if scopes == nil {
+		scopes = []Scope{}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"enabled": true,
+		"generalProperties": map[string]interface{}{
+			"name":                             w.Name,
+			"description":                      w.Description,
+			"maintenanceType":                  "PLANNED",
+			"suppression":                      w.Suppression,
+			"disableSyntheticMonitorExecution": false,
+		},
+		"schedule": map[string]interface{}{
+			"scheduleType": "ONCE",
+			"onceRecurrence": map[string]interface{}{
+				"startTime": w.From.UTC().Format(layout),
+				"endTime":   w.To.UTC().Format(layout),
+				"timeZone":  "UTC",
+			},
+		},
+		"filters": scopes,
+	}, "", "  ")
+}
+
+// Create creates the given maintenance window, or updates the existing one of the same name. In dry-run mode, the
+// window is only printed.
+func Create(ctx context.Context, c client.SettingsClient, w Window, dryRun bool) error {
+	if err := w.validate(); err != nil {
+		return err
+	}
+
+	payload, err := w.payload()
+	if err != nil {
+		return fmt.Errorf("failed to build maintenance window: %w", err)
+	}
+
+	if dryRun {
+		log.Info("Dry-run: would create maintenance window %q:\n%s", w.Name, payload)
+		return nil
+	}
+
+	entity, err := c.UpsertSettings(ctx, client.SettingsObject{
+		Id:       idPrefix + w.Name,
+		SchemaId: schemaId,
+		Scope:    "environment",
+		Content:  payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window %q: %w", w.Name, err)
+	}
+
+	log.Info("Created maintenance window %q (%s) from %s to %s", w.Name, entity.Id, w.From.Format(time.RFC3339), w.To.Format(time.RFC3339))
+	return nil
+}
+
+// Delete deletes the maintenance window of the given name created by Create. In dry-run mode, the window is only
+// looked up.
+func Delete(ctx context.Context, c client.SettingsClient, name string, dryRun bool) error {
+	externalId := idutils.GenerateExternalID(schemaId, idPrefix+name)
+
+	objects, err := c.ListSettings(ctx, schemaId, client.ListSettingsOptions{
+		DiscardValue: true,
+		Filter:       func(o client.DownloadSettingsObject) bool { return o.ExternalId == externalId },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	if len(objects) == 0 {
+		return fmt.Errorf("no maintenance window %q created by monaco exists", name)
+	}
+
+	for _, o := range objects {
+		if dryRun {
+			log.Info("Dry-run: would delete maintenance window %q (%s)", name, o.ObjectId)
+			continue
+		}
+		if err := c.DeleteSettings(ctx, o.ObjectId); err != nil {
+			return fmt.Errorf("failed to delete maintenance window %q (%s): %w", name, o.ObjectId, err)
+		}
+		log.Info("Deleted maintenance window %q (%s)", name, o.ObjectId)
+	}
+	return nil
+}
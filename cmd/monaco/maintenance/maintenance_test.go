@@ -0,0 +1,150 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+	"time"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		scope   string
+		want    Scope
+		wantErr string
+	}{
+		{scope: "tag:owner:team-a", want: Scope{EntityTags: []string{"owner:team-a"}, ManagementZones: []string{}}},
+		{scope: "entity:HOST-1234567890ABCDEF", want: Scope{EntityId: "HOST-1234567890ABCDEF", EntityTags: []string{}, ManagementZones: []string{}}},
+		{scope: "type:HOST", want: Scope{EntityType: "HOST", EntityTags: []string{}, ManagementZones: []string{}}},
+		{scope: "mz:-123", want: Scope{EntityTags: []string{}, ManagementZones: []string{"-123"}}},
+		{scope: "owner", wantErr: "invalid scope"},
+		{scope: "tag:", wantErr: "invalid scope"},
+		{scope: "host:HOST-1234567890ABCDEF", wantErr: `unknown kind "host"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			got, err := ParseScope(tt.scope)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	from := time.Date(2023, 12, 22, 18, 0, 0, 0, time.FixedZone("CET", 3600))
+	w := Window{
+		Name:        "freeze",
+		From:        from,
+		To:          from.Add(24 * time.Hour),
+		Suppression: DontDetectProblems,
+		Scopes:      []Scope{{EntityTags: []string{"owner:team-a"}, ManagementZones: []string{}}},
+	}
+
+	t.Run("window is upserted by name", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, o client.SettingsObject) (client.DynatraceEntity, error) {
+			assert.Equal(t, o.Id, "monaco-maintenance-freeze")
+			assert.Equal(t, o.SchemaId, schemaId)
+			assert.Equal(t, o.Scope, "environment")
+
+			var payload map[string]interface{}
+			assert.NilError(t, json.Unmarshal(o.Content, &payload))
+			assert.DeepEqual(t, payload["schedule"], map[string]interface{}{
+				"scheduleType": "ONCE",
+				"onceRecurrence": map[string]interface{}{
+					"startTime": "2023-12-22T17:00:00",
+					"endTime":   "2023-12-23T17:00:00",
+					"timeZone":  "UTC",
+				},
+			})
+			assert.DeepEqual(t, payload["filters"], []interface{}{
+				map[string]interface{}{"entityTags": []interface{}{"owner:team-a"}, "managementZones": []interface{}{}},
+			})
+			return client.DynatraceEntity{Id: "object-id"}, nil
+		})
+
+		assert.NilError(t, Create(context.TODO(), c, w, false))
+	})
+
+	t.Run("nothing is created in dry-run", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		assert.NilError(t, Create(context.TODO(), c, w, true))
+	})
+
+	t.Run("windows ending before they start are rejected", func(t *testing.T) {
+		invalid := w
+		invalid.To = w.From.Add(-time.Hour)
+		assert.ErrorContains(t, Create(context.TODO(), nil, invalid, true), "must be after its start")
+	})
+
+	t.Run("unknown suppressions are rejected", func(t *testing.T) {
+		invalid := w
+		invalid.Suppression = "NONE"
+		assert.ErrorContains(t, Create(context.TODO(), nil, invalid, true), `unknown suppression "NONE"`)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	objects := []client.DownloadSettingsObject{
+		{ObjectId: "other", ExternalId: idutils.GenerateExternalID(schemaId, "other")},
+		{ObjectId: "object-id", ExternalId: idutils.GenerateExternalID(schemaId, "monaco-maintenance-freeze")},
+	}
+	listFiltered := func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		var result []client.DownloadSettingsObject
+		for _, o := range objects {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	}
+
+	t.Run("window is deleted by name", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), schemaId, gomock.Any()).DoAndReturn(listFiltered)
+		c.EXPECT().DeleteSettings(gomock.Any(), "object-id").Return(nil)
+
+		assert.NilError(t, Delete(context.TODO(), c, "freeze", false))
+	})
+
+	t.Run("nothing is deleted in dry-run", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), schemaId, gomock.Any()).DoAndReturn(listFiltered)
+
+		assert.NilError(t, Delete(context.TODO(), c, "freeze", true))
+	})
+
+	t.Run("unknown windows are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), schemaId, gomock.Any()).DoAndReturn(listFiltered)
+
+		assert.ErrorContains(t, Delete(context.TODO(), c, "unknown", false), `no maintenance window "unknown" created by monaco exists`)
+	})
+}
@@ -0,0 +1,71 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package open
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+// GetOpenCommand returns the command printing the link to the object of a config in the Dynatrace UI.
+func GetOpenCommand(fs afero.Fs) (openCmd *cobra.Command) {
+	var environment string
+	var opts Options
+	var timeout time.Duration
+
+	openCmd = &cobra.Command{
+		Use:   "open [<manifest.yaml>] <project:type:id>",
+		Short: "Print the link to the object of a config in the Dynatrace UI",
+		Long: `Print the link to the object of a config in the Dynatrace UI
+
+  The object deployed for the config is looked up in the given environment - settings objects by the externalId
+  monaco assigned them, classic configs by their name. Configs of APIs without a known UI page are linked to the
+  object in the API instead.`,
+		Example: "monaco open manifest.yaml my-project:dashboard:overview -e prod --browser",
+		Args:    cobra.RangeArgs(1, 2),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args[:len(args)-1], opts.ManifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return Open(ctx, fs, manifestName, environment, args[len(args)-1], opts)
+		},
+	}
+
+	openCmd.Flags().StringVarP(&environment, "environment", "e", "", "The environment defined in the manifest to look up the object in")
+	openCmd.Flags().BoolVar(&opts.Browser, "browser", false, "Additionally open the link in the default browser")
+	cmdutils.AddManifestFromEnvFlag(openCmd, &opts.ManifestFromEnv)
+	cmdutils.AddTimeoutFlag(openCmd, &timeout)
+
+	if err := openCmd.MarkFlagRequired("environment"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := openCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return openCmd
+}
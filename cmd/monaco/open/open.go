@@ -0,0 +1,152 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package open
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// uiPages holds the pages of the Dynatrace UI showing the objects of classic config APIs. The object ID is inserted
+// into the page.
+var uiPages = map[string]string{
+	"dashboard":          "#dashboard;id=%s",
+	"synthetic-monitor":  "#webcheckdetailV3;webcheckId=%s",
+	"application-web":    "#uemapplications/uemappmetrics;uemapplicationId=%s",
+	"application-mobile": "#mobileappoverview;appId=%s",
+}
+
+// Options define how the object of a config is opened.
+type Options struct {
+	// ManifestFromEnv defines that the manifest is created from environment variables instead of being read from a file
+	ManifestFromEnv bool
+	// Browser opens the link in the default browser, in addition to printing it
+	Browser bool
+}
+
+// Open looks up the object deployed for the config of the given coordinate ('project:type:id') in the given
+// environment and prints the link to the object in the Dynatrace UI.
+func Open(ctx context.Context, fs afero.Fs, manifestPath string, environment string, target string, opts Options) error {
+	c, err := coordinate.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid coordinate %q: %w", target, err)
+	}
+
+	m, projects, err := cmdutils.LoadManifestAndProjects(fs, manifestPath, cmdutils.ManifestOptions{
+		Environments: []string{environment},
+		FromEnv:      opts.ManifestFromEnv,
+	})
+	if err != nil {
+		return err
+	}
+
+	env, found := m.Environments[environment]
+	if !found {
+		return fmt.Errorf("environment %q was not available in manifest %q", environment, manifestPath)
+	}
+
+	conf, found := findConfig(projects, environment, c)
+	if !found {
+		return fmt.Errorf("config %s is not defined for environment %q", c, environment)
+	}
+
+	dtClient, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	if err != nil {
+		return fmt.Errorf("failed to create a client for environment %q: %w", env.Name, err)
+	}
+
+	link, err := Link(ctx, dtClient, api.NewAPIs(), env.URL.Value, conf)
+	if err != nil {
+		return err
+	}
+
+	log.Info("%s", link)
+	if opts.Browser {
+		if err := openInBrowser(link); err != nil {
+			return fmt.Errorf("failed to open %q in the browser: %w", link, err)
+		}
+	}
+	return nil
+}
+
+// Link looks up the object deployed for the given config and returns the link to the object in the Dynatrace UI.
+// For objects of config APIs without a known UI page, the link to the object in the API is returned instead.
+func Link(ctx context.Context, c client.Client, apis api.APIs, environmentURL string, conf *config.Config) (string, error) {
+	objectId, err := deploy.FindObjectId(ctx, c, apis, conf)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the object of config %s: %w", conf.Coordinate, err)
+	}
+
+	environmentURL = strings.TrimSuffix(environmentURL, "/")
+	switch t := conf.Type.(type) {
+	case config.SettingsType:
+		return fmt.Sprintf("%s/ui/settings/%s?objectId=%s", environmentURL, t.SchemaId, url.QueryEscape(objectId)), nil
+	case config.ClassicApiType:
+		if page, found := uiPages[t.Api]; found {
+			return environmentURL + "/" + fmt.Sprintf(page, url.PathEscape(objectId)), nil
+		}
+		log.Warn("No page of the Dynatrace UI is known for configs of API %q, linking the object in the API instead", t.Api)
+		a := apis[t.Api]
+		if a.SingleConfiguration {
+			return a.CreateURL(environmentURL), nil
+		}
+		return a.CreateURL(environmentURL) + "/" + url.PathEscape(objectId), nil
+	default:
+		return "", fmt.Errorf("configs of type %q can not be linked", conf.Type.ID())
+	}
+}
+
+// findConfig returns the config of the given coordinate defined for the given environment.
+func findConfig(projects []project.Project, environment string, c coordinate.Coordinate) (*config.Config, bool) {
+	for _, p := range projects {
+		if p.Id != c.Project {
+			continue
+		}
+		for _, conf := range p.Configs[environment][c.Type] {
+			if conf.Coordinate == c {
+				conf := conf
+				return &conf, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// openInBrowser opens the link with the default handler of the operating system.
+func openInBrowser(link string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", link).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", link).Start()
+	default:
+		return exec.Command("xdg-open", link).Start()
+	}
+}
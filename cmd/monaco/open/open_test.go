@@ -0,0 +1,119 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package open
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLink(t *testing.T) {
+	apis := api.NewAPIs()
+
+	t.Run("settings objects are linked in the settings UI", func(t *testing.T) {
+		conf := &config.Config{
+			Coordinate: coordinate.Coordinate{Project: "p", Type: "builtin:alerting.profile", ConfigId: "profile"},
+			Type:       config.SettingsType{SchemaId: "builtin:alerting.profile"},
+		}
+		externalId := idutils.GenerateExternalID("builtin:alerting.profile", "profile")
+
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+			o := client.DownloadSettingsObject{ObjectId: "vu9U3hXa3q0AAAAB", ExternalId: externalId}
+			assert.True(t, opts.Filter(o))
+			return []client.DownloadSettingsObject{o}, nil
+		})
+
+		link, err := Link(context.TODO(), c, apis, "https://env.live.dynatrace.com/", conf)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://env.live.dynatrace.com/ui/settings/builtin:alerting.profile?objectId=vu9U3hXa3q0AAAAB", link)
+	})
+
+	t.Run("dashboards are linked by their name", func(t *testing.T) {
+		conf := &config.Config{
+			Coordinate: coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "overview"},
+			Type:       config.ClassicApiType{Api: "dashboard"},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "Overview"}},
+		}
+
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), apis["dashboard"]).Return([]client.Value{{Id: "other", Name: "Other"}, {Id: "dashboard-id", Name: "Overview"}}, nil)
+
+		link, err := Link(context.TODO(), c, apis, "https://env.live.dynatrace.com", conf)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://env.live.dynatrace.com/#dashboard;id=dashboard-id", link)
+	})
+
+	t.Run("configs of APIs without UI page are linked in the API", func(t *testing.T) {
+		conf := &config.Config{
+			Coordinate: coordinate.Coordinate{Project: "p", Type: "slo", ConfigId: "slo"},
+			Type:       config.ClassicApiType{Api: "slo"},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "SLO"}},
+		}
+
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), apis["slo"]).Return([]client.Value{{Id: "slo-id", Name: "SLO"}}, nil)
+
+		link, err := Link(context.TODO(), c, apis, "https://env.live.dynatrace.com", conf)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://env.live.dynatrace.com/api/v2/slo/slo-id", link)
+	})
+
+	t.Run("objects not found fail", func(t *testing.T) {
+		conf := &config.Config{
+			Coordinate: coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "overview"},
+			Type:       config.ClassicApiType{Api: "dashboard"},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "Overview"}},
+		}
+
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), apis["dashboard"]).Return(nil, nil)
+
+		_, err := Link(context.TODO(), c, apis, "https://env.live.dynatrace.com", conf)
+		assert.ErrorContains(t, err, "p:dashboard:overview")
+	})
+}
+
+func TestFindConfig(t *testing.T) {
+	c := coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "overview"}
+	projects := []project.Project{
+		{
+			Id: "p",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"prod": {"dashboard": {{Coordinate: c, Environment: "prod"}}},
+			},
+		},
+	}
+
+	conf, found := findConfig(projects, "prod", c)
+	assert.True(t, found)
+	assert.Equal(t, "prod", conf.Environment)
+
+	_, found = findConfig(projects, "dev", c)
+	assert.False(t, found)
+}
@@ -0,0 +1,72 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+// GetPackageCommand returns the command to build a package of a manifest and its projects.
+func GetPackageCommand(fs afero.Fs) (packageCmd *cobra.Command) {
+	var outputFolder, lockEnvironment, signingKey string
+	var timeout time.Duration
+
+	packageCmd = &cobra.Command{
+		Use:   "package [<manifest.yaml>]",
+		Short: "Build a deployable package of a manifest and its projects",
+		Long: `Build a deployable package of a manifest and its projects
+
+  The package is a zip archive containing the manifest, all files of its projects and a lock file of the Settings 2.0
+  schema versions used by the projects. It is named after its content digest, so the same content always results in
+  the same package. Deploy it using 'monaco deploy --package <file>' to promote the very same artifact through
+  environments.
+
+  Packages can be signed with an Ed25519 private key in PEM format, e.g. generated by
+  'openssl genpkey -algorithm ed25519 -out signing-key.pem'. The signature is written next to the package with the
+  extension '.sig'. Deployments can enforce valid signatures using 'monaco deploy --package <file> --verify-key <key>'.`,
+		Example: "monaco package manifest.yaml -o dist --lock-environment dev",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, false)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return Package(ctx, fs, manifestName, outputFolder, lockEnvironment, signingKey)
+		},
+	}
+
+	packageCmd.Flags().StringVarP(&outputFolder, "output-folder", "o", ".", "Folder to write the package to")
+	packageCmd.Flags().StringVar(&lockEnvironment, "lock-environment", "", "Environment to lock the versions of schemas used without explicit version to. If not set, only explicit versions are locked")
+	packageCmd.Flags().StringVar(&signingKey, "signing-key", "", "Ed25519 private key in PEM format to sign the package with")
+	cmdutils.AddTimeoutFlag(packageCmd, &timeout)
+
+	if err := packageCmd.RegisterFlagCompletionFunc("lock-environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return packageCmd
+}
@@ -0,0 +1,149 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/packaging"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"path/filepath"
+)
+
+// Package builds a package of the manifest and its projects in outputFolder. The schema versions pinned by settings
+// configs are locked in the package. If lockEnvironment is set, the versions of schemas used without explicit version
+// are locked to the versions available on that environment. If signingKeyPath is set, the package is signed with the
+// private key read from it.
+func Package(ctx context.Context, fs afero.Fs, manifestPath string, outputFolder string, lockEnvironment string, signingKeyPath string) error {
+	var signingKey ed25519.PrivateKey
+	if signingKeyPath != "" {
+		var err error
+		if signingKey, err = packaging.LoadPrivateKey(fs, signingKeyPath); err != nil {
+			return err
+		}
+	}
+
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, projects, err := cmdutils.LoadManifestAndProjects(fs, absManifestPath, cmdutils.ManifestOptions{})
+	if err != nil {
+		return err
+	}
+
+	lock := pinnedSchemaVersions(projects)
+	if lockEnvironment != "" {
+		if err := lockAvailableSchemaVersions(ctx, m, lockEnvironment, lock); err != nil {
+			return err
+		}
+	}
+	for schemaId, v := range lock {
+		if v == "" {
+			delete(lock, schemaId)
+		}
+	}
+
+	archive, err := packaging.Build(fs, absManifestPath, m, lock)
+	if err != nil {
+		return fmt.Errorf("failed to build package: %w", err)
+	}
+	digest := packaging.Digest(archive)
+
+	if err := fs.MkdirAll(outputFolder, 0777); err != nil {
+		return err
+	}
+	archivePath := filepath.Join(outputFolder, packaging.FileName(digest))
+	if err := afero.WriteFile(fs, archivePath, archive, 0664); err != nil {
+		return fmt.Errorf("failed to write package %q: %w", archivePath, err)
+	}
+
+	if signingKey != nil {
+		signaturePath := archivePath + packaging.SignatureExtension
+		if err := afero.WriteFile(fs, signaturePath, packaging.Sign(archive, signingKey), 0664); err != nil {
+			return fmt.Errorf("failed to write signature %q: %w", signaturePath, err)
+		}
+		log.Info("Signed package %q", archivePath)
+	}
+
+	// projects referencing files outside their folder fail to load from the package
+	p, err := packaging.Open(fs, archivePath)
+	if err != nil {
+		return err
+	}
+	if _, _, err := cmdutils.LoadManifestAndProjects(p.Fs, p.ManifestPath, cmdutils.ManifestOptions{}); err != nil {
+		return fmt.Errorf("package %q is not deployable, projects may reference files outside of their folder: %w", archivePath, err)
+	}
+
+	log.Info("Packaged %d project(s) and %d locked schema version(s) to %q (%s)", len(m.Projects), len(lock), archivePath, digest)
+	return nil
+}
+
+// pinnedSchemaVersions returns the schema versions pinned by settings configs of the projects. Schemas used without
+// explicit version have an empty version.
+func pinnedSchemaVersions(projects []project.Project) packaging.Lock {
+	lock := packaging.Lock{}
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			t, ok := c.Type.(config.SettingsType)
+			if !ok {
+				return
+			}
+			if lock[t.SchemaId] == "" {
+				lock[t.SchemaId] = t.SchemaVersion
+			}
+		})
+	}
+	return lock
+}
+
+// lockAvailableSchemaVersions sets the version of all schemas of the lock without version to the latest version
+// available on the given environment.
+func lockAvailableSchemaVersions(ctx context.Context, m manifest.Manifest, environment string, lock packaging.Lock) error {
+	env, found := m.Environments[environment]
+	if !found {
+		return fmt.Errorf("environment %q was not available in manifest", environment)
+	}
+
+	c, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	if err != nil {
+		return fmt.Errorf("failed to create a client for environment %q: %w", environment, err)
+	}
+	schemas, err := c.ListSchemas(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list schemas of environment %q: %w", environment, err)
+	}
+
+	for _, s := range schemas {
+		if v, used := lock[s.SchemaId]; used && v == "" {
+			lock[s.SchemaId] = s.LatestSchemaVersion
+		}
+	}
+	for schemaId, v := range lock {
+		if v == "" {
+			log.Warn("Schema %q is not available on environment %q, its version is not locked", schemaId, environment)
+		}
+	}
+	return nil
+}
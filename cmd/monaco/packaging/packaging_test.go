@@ -0,0 +1,152 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/packaging"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+// writeKeyPair writes a new Ed25519 key pair in PEM format to key.pem and key.pub.pem
+func writeKeyPair(t *testing.T, fs afero.Fs) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	privDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	pubDer, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+
+	_ = afero.WriteFile(fs, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer}), 0600)
+	_ = afero.WriteFile(fs, "key.pub.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0644)
+}
+
+func TestPackage(t *testing.T) {
+	t.Setenv("ENV_TOKEN", "mock env token")
+
+	manifestYaml := `manifestVersion: "1.0"
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://abcde.dev.dynatracelabs.com
+    auth:
+      token:
+        type: environment
+        name: ENV_TOKEN
+`
+	configYaml := `configs:
+- id: profile
+  config:
+    name: alerting-profile
+    template: profile.json
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      schemaVersion: "8.0"
+      scope: environment
+- id: unpinned
+  config:
+    name: other
+    template: profile.json
+  type:
+    settings:
+      schema: builtin:other
+      scope: environment
+`
+
+	newFs := func(template string) (afero.Fs, string) {
+		fs := afero.NewMemMapFs()
+		manifestPath, _ := filepath.Abs("manifest.yaml")
+		_ = afero.WriteFile(fs, manifestPath, []byte(manifestYaml), 0644)
+		_ = afero.WriteFile(fs, filepath.Join(filepath.Dir(manifestPath), "project", "profile", "profile.yaml"), []byte(configYaml), 0644)
+		_ = afero.WriteFile(fs, filepath.Join(filepath.Dir(manifestPath), "project", "profile", "profile.json"), []byte(template), 0644)
+		return fs, manifestPath
+	}
+
+	t.Run("packages can be deployed", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"name": "{{ .name }}"}`)
+
+		err := Package(context.TODO(), fs, manifestPath, "dist", "", "")
+		assert.NoError(t, err)
+
+		files, err := afero.ReadDir(fs, "dist")
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+		packagePath := filepath.Join("dist", files[0].Name())
+
+		p, err := packaging.Open(fs, packagePath)
+		assert.NoError(t, err)
+		assert.Equal(t, packaging.Lock{"builtin:alerting.profile": "8.0"}, p.Lock)
+		assert.Equal(t, packaging.FileName(p.Digest), files[0].Name())
+
+		err = deploy.DeployPackage(context.TODO(), fs, packagePath, "", deploy.Options{DryRun: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("signed packages can be verified on deploy", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"name": "{{ .name }}"}`)
+		writeKeyPair(t, fs)
+
+		err := Package(context.TODO(), fs, manifestPath, "dist", "", "key.pem")
+		assert.NoError(t, err)
+
+		files, _ := afero.ReadDir(fs, "dist")
+		assert.Len(t, files, 2)
+		packagePath := filepath.Join("dist", files[0].Name())
+
+		err = deploy.DeployPackage(context.TODO(), fs, packagePath, "key.pub.pem", deploy.Options{DryRun: true})
+		assert.NoError(t, err)
+
+		_ = fs.Remove(packagePath + packaging.SignatureExtension)
+		err = deploy.DeployPackage(context.TODO(), fs, packagePath, "key.pub.pem", deploy.Options{DryRun: true})
+		assert.ErrorContains(t, err, "failed to read signature")
+	})
+
+	t.Run("packaging the same content twice results in the same package", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"name": "{{ .name }}"}`)
+
+		assert.NoError(t, Package(context.TODO(), fs, manifestPath, "first", "", ""))
+		assert.NoError(t, Package(context.TODO(), fs, manifestPath, "second", "", ""))
+
+		first, _ := afero.ReadDir(fs, "first")
+		second, _ := afero.ReadDir(fs, "second")
+		assert.Equal(t, first[0].Name(), second[0].Name())
+	})
+
+	t.Run("invalid projects are not packaged", func(t *testing.T) {
+		fs, manifestPath := newFs(`{"name": "{{ .name }}"}`)
+		_ = afero.WriteFile(fs, filepath.Join(filepath.Dir(manifestPath), "project", "profile", "invalid.yaml"), []byte("configs: [invalid"), 0644)
+
+		err := Package(context.TODO(), fs, manifestPath, "dist", "", "")
+		assert.Error(t, err)
+	})
+}
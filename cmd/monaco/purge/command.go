@@ -22,15 +22,21 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"time"
 )
 
 func GetPurgeCommand(fs afero.Fs) (purgeCmd *cobra.Command) {
 
 	var environment []string
 	var manifestName string
+	var timeout time.Duration
 	var specificApis []string
+	var safeguard delete.Safeguard
+	var yes bool
+	var execOpts executeOptions
 
 	purgeCmd = &cobra.Command{
 		Use:     "purge <manifest.yaml>",
@@ -48,13 +54,20 @@ func GetPurgeCommand(fs afero.Fs) (purgeCmd *cobra.Command) {
 				return err
 			}
 
-			return purge(fs, manifestName, environment, specificApis)
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return purge(ctx, fs, manifestName, environment, specificApis, safeguard, cmdutils.NewDeletionConfirmation(cmd, yes), execOpts)
 		},
 		ValidArgsFunction: completion.PurgeCompletion,
 	}
 
 	purgeCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Deletes configuration only for specified envs. If not set, delete will be executed on all environments defined in manifest.")
 	purgeCmd.Flags().StringSliceVarP(&specificApis, "api", "a", make([]string, 0), "One or more specific APIs to delete from (flag can be repeated or value defined as comma-separated list)")
+	cmdutils.AddTimeoutFlag(purgeCmd, &timeout)
+	cmdutils.AddSafeguardFlags(purgeCmd, &safeguard)
+	cmdutils.AddYesFlag(purgeCmd, &yes)
+	addExecuteFlags(purgeCmd, &execOpts)
 
 	if err := purgeCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
 		log.Fatal("failed to setup CLI %v", err)
@@ -65,3 +78,77 @@ func GetPurgeCommand(fs afero.Fs) (purgeCmd *cobra.Command) {
 
 	return purgeCmd
 }
+
+func GetPurgeTypesCommand(fs afero.Fs) (purgeTypesCmd *cobra.Command) {
+
+	var environment []string
+	var timeout time.Duration
+	var specificApis []string
+	var specificSchemas []string
+	var monacoManagedOnly bool
+	var dryRun bool
+	var safeguard delete.Safeguard
+	var yes bool
+	var execOpts executeOptions
+
+	purgeTypesCmd = &cobra.Command{
+		Use:   "purge-types <manifest.yaml>",
+		Short: "Delete all configurations of the given APIs and settings schemas from the environments defined in the manifest",
+		Long: `Delete all configurations of the given APIs and settings schemas from the environments defined in the manifest
+
+Unlike 'purge', only the selected types are deleted, e.g. to clean up after a failed mass import.
+Configurations marked with 'ignoreOnPurge' are kept.
+
+With '--monaco-managed-only', only settings objects deployed by the settings configs of the manifest's projects are
+deleted - of all schemas, unless specific schemas are given. Objects created by monaco for other purposes, like
+deployment locks, are kept.`,
+		Example: "monaco purge-types manifest.yaml -e dev-environment -s builtin:alerting.profile -a alerting-profile --dry-run",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			manifestName := args[0]
+
+			if !files.IsYamlFileExtension(manifestName) {
+				err := fmt.Errorf("wrong format for manifest file! expected a .yaml file, but got %s", manifestName)
+				return err
+			}
+
+			if len(specificApis) == 0 && len(specificSchemas) == 0 && !monacoManagedOnly {
+				return fmt.Errorf("at least one API or settings schema to purge must be given via '--api' or '--settings-schema', or '--monaco-managed-only' must be set")
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return purgeTypes(ctx, fs, manifestName, environment, specificApis, specificSchemas, monacoManagedOnly, dryRun, safeguard, cmdutils.NewDeletionConfirmation(cmd, yes), execOpts)
+		},
+		ValidArgsFunction: completion.PurgeCompletion,
+	}
+
+	purgeTypesCmd.Flags().StringSliceVarP(&environment, "environment", "e", make([]string, 0), "Deletes configuration only for specified envs. If not set, delete will be executed on all environments defined in manifest.")
+	purgeTypesCmd.Flags().StringSliceVarP(&specificApis, "api", "a", make([]string, 0), "One or more APIs to delete all configs of (flag can be repeated or value defined as comma-separated list)")
+	purgeTypesCmd.Flags().StringSliceVarP(&specificSchemas, "settings-schema", "s", make([]string, 0), "One or more settings 2.0 schemas to delete all objects of (flag can be repeated or value defined as comma-separated list)")
+	purgeTypesCmd.Flags().BoolVar(&monacoManagedOnly, "monaco-managed-only", false, "Only delete settings objects deployed by the settings configs of the manifest's projects, identified by their externalId")
+	purgeTypesCmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Only report what would be deleted, without deleting anything")
+	cmdutils.AddTimeoutFlag(purgeTypesCmd, &timeout)
+	cmdutils.AddSafeguardFlags(purgeTypesCmd, &safeguard)
+	cmdutils.AddYesFlag(purgeTypesCmd, &yes)
+	addExecuteFlags(purgeTypesCmd, &execOpts)
+
+	if err := purgeTypesCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := purgeTypesCmd.RegisterFlagCompletionFunc("api", completion.AllAvailableApis); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return purgeTypesCmd
+}
+
+func addExecuteFlags(cmd *cobra.Command, opts *executeOptions) {
+	cmd.Flags().IntVar(&opts.batchSize, "batch-size", 100, "Number of configs deleted before the progress is reported and written to the resume file")
+	cmd.Flags().DurationVar(&opts.batchPause, "batch-pause", 0, "Time waited after each batch of deletions, limiting the rate of delete requests, e.g. '2s'")
+	cmd.Flags().BoolVar(&opts.failFast, "fail-fast", false, "Stop deleting as soon as a config fails to be deleted. By default, deleting continues, and failed configs are reported at the end and recorded in the resume file")
+	cmd.Flags().StringVar(&opts.resumeFile, "resume-file", "", "File recording which configs were deleted. If the purge does not complete, running it again with the same file skips the configs already deleted. The file is removed once the purge completes without errors")
+}
@@ -17,6 +17,8 @@
 package purge
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
@@ -24,13 +26,80 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/spf13/afero"
+	"os"
 	"path/filepath"
+	"sort"
+	"time"
 )
 
-func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string, apiNames []string) error {
+// executeOptions define how the deletions of a purge are executed.
+type executeOptions struct {
+	batchSize  int
+	batchPause time.Duration
+	// failFast stops the purge at the first config failing to be deleted, instead of continuing with the remaining ones
+	failFast bool
+	// resumeFile optionally records the progress of the purge per environment, so a partially completed purge can be
+	// continued by running it again with the same file
+	resumeFile string
+}
+
+// resumeState is the content of a resume file, the progress of the purge per environment
+type resumeState map[string]*delete.Progress
+
+// environmentObjects are the remote objects of the configs of the loaded projects in a single environment
+type environmentObjects struct {
+	// keep holds the objects of configs marked with 'ignoreOnPurge'
+	keep config.RemoteObjects
+	// managed holds the objects of all settings configs, identifying the settings objects deployed by the projects
+	managed config.RemoteObjects
+}
+
+// planFunc plans the deletions of a purge for a single environment
+type planFunc func(ctx context.Context, c client.Client, objects environmentObjects) (delete.Plan, []error)
+
+func purge(ctx context.Context, fs afero.Fs, deploymentManifestPath string, environmentNames []string, apiNames []string, safeguard delete.Safeguard, confirmation cmdutils.DeletionConfirmation, execOpts executeOptions) error {
+	apis := api.NewAPIs().Filter(api.RetainByName(apiNames))
+	plan := func(ctx context.Context, c client.Client, objects environmentObjects) (delete.Plan, []error) {
+		return delete.PlanPurge(ctx, c, apis, objects.keep)
+	}
+	return purgeWithPlan(ctx, fs, deploymentManifestPath, environmentNames, plan, false, safeguard, confirmation, execOpts)
+}
+
+// purgeTypes deletes all configs of the given APIs and all settings objects of the given schemas. If monacoManagedOnly
+// is set, only settings objects deployed by the settings configs of the manifest's projects are deleted - of all schemas
+// if none are given.
+func purgeTypes(ctx context.Context, fs afero.Fs, deploymentManifestPath string, environmentNames []string, apiNames []string, schemaIds []string, monacoManagedOnly bool, dryRun bool, safeguard delete.Safeguard, confirmation cmdutils.DeletionConfirmation, execOpts executeOptions) error {
+	if monacoManagedOnly {
+		if len(apiNames) > 0 {
+			return errors.New("configs of APIs are not identifiable as created by monaco and can not be purged with '--monaco-managed-only'")
+		}
+		plan := func(ctx context.Context, c client.Client, objects environmentObjects) (delete.Plan, []error) {
+			return delete.PlanPurgeByExternalIds(ctx, c, schemaIds, objects.managed, objects.keep)
+		}
+		return purgeWithPlan(ctx, fs, deploymentManifestPath, environmentNames, plan, dryRun, safeguard, confirmation, execOpts)
+	}
+
+	knownApis := api.NewAPIs()
+	for _, a := range apiNames {
+		if !knownApis.Contains(a) {
+			return fmt.Errorf("unknown API %q", a)
+		}
+	}
+
+	apis := knownApis.Filter(api.RetainByName(apiNames))
+	plan := func(ctx context.Context, c client.Client, objects environmentObjects) (delete.Plan, []error) {
+		return delete.PlanPurgeTypes(ctx, c, apis, schemaIds, objects.keep)
+	}
+	return purgeWithPlan(ctx, fs, deploymentManifestPath, environmentNames, plan, dryRun, safeguard, confirmation, execOpts)
+}
+
+func purgeWithPlan(ctx context.Context, fs afero.Fs, deploymentManifestPath string, environmentNames []string, plan planFunc, dryRun bool, safeguard delete.Safeguard, confirmation cmdutils.DeletionConfirmation, execOpts executeOptions) error {
 
 	deploymentManifestPath = filepath.Clean(deploymentManifestPath)
 	deploymentManifestPath, manifestErr := filepath.Abs(deploymentManifestPath)
@@ -39,8 +108,6 @@ func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string
 		return fmt.Errorf("error while finding absolute path for `%s`: %w", deploymentManifestPath, manifestErr)
 	}
 
-	apis := api.NewAPIs().Filter(api.RetainByName(apiNames))
-
 	mani, manifestLoadError := manifest.LoadManifest(&manifest.LoaderContext{
 		Fs:           fs,
 		ManifestPath: deploymentManifestPath,
@@ -52,7 +119,19 @@ func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string
 		return errors.New("error while loading manifest")
 	}
 
-	deleteErrors := purgeConfigs(maps.Values(mani.Environments), apis)
+	// projects failing to load are skipped, their configs marked with 'ignoreOnPurge' are not known and thus not kept
+	projects := cmdutils.LoadManifestProjectsPartially(fs, deploymentManifestPath, mani)
+	keep := project.RemoteObjectsPerEnvironment(projects, func(c config.Config) bool { return c.IgnoreOnPurge })
+	managed := project.RemoteObjectsPerEnvironment(projects, func(c config.Config) bool {
+		_, isSettings := c.Type.(config.SettingsType)
+		return isSettings
+	})
+	objects := make(map[string]environmentObjects, len(mani.Environments))
+	for env := range mani.Environments {
+		objects[env] = environmentObjects{keep: keep[env], managed: managed[env]}
+	}
+
+	deleteErrors := purgeConfigs(ctx, fs, maps.Values(mani.Environments), mani.HTTP, plan, dryRun, objects, safeguard, confirmation, execOpts)
 
 	for _, e := range deleteErrors {
 		log.Error("Deletion error: %s", e)
@@ -63,32 +142,123 @@ func purge(fs afero.Fs, deploymentManifestPath string, environmentNames []string
 	return nil
 }
 
-func purgeConfigs(environments []manifest.EnvironmentDefinition, apis api.APIs) (errors []error) {
+func purgeConfigs(ctx context.Context, fs afero.Fs, environments []manifest.EnvironmentDefinition, httpSettings manifest.HTTPSettings, planPurge planFunc, dryRun bool, objects map[string]environmentObjects, safeguard delete.Safeguard, confirmation cmdutils.DeletionConfirmation, execOpts executeOptions) (errors []error) {
+
+	clients := make(map[string]client.Client, len(environments))
+	plans := make(map[string]delete.Plan, len(environments))
 
 	for _, env := range environments {
-		deleteErrors := purgeForEnvironment(env, apis)
+		dynatraceClient, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(httpSettings.ForEnvironment(env)))
+		if err == nil {
+			dynatraceClient, err = cmdutils.AuditClient(fs, dynatraceClient, env.Name)
+		}
+		if err != nil {
+			errors = append(errors, fmt.Errorf("failed to create a client for env `%s` due to the following error: %w", env.Name, err))
+			continue
+		}
+
+		log.Info("Collecting configs to delete for environment `%s`", env.Name)
 
-		if deleteErrors != nil {
-			errors = append(errors, deleteErrors...)
+		plan, planErrors := planPurge(ctx, dynatraceClient, objects[env.Name])
+		errors = append(errors, planErrors...)
+
+		clients[env.Name] = dynatraceClient
+		plans[env.Name] = plan
+	}
+
+	if dryRun {
+		for env, plan := range plans {
+			log.Info("Dry-run: would delete %d configs of %v from environment `%s`", plan.Count(), plan.Types(), env)
 		}
+		return errors
 	}
 
-	return errors
-}
+	if err := safeguard.Check(plans); err != nil {
+		return append(errors, fmt.Errorf("aborted purge without deleting anything. Use '--confirm' to delete anyway: %w", err))
+	}
 
-func purgeForEnvironment(env manifest.EnvironmentDefinition, apis api.APIs) []error {
-	dynatraceClient, err := cmdutils.CreateDTClient(env.URL.Value, env.Auth, false)
+	if err := confirmation.Confirm(plans); err != nil {
+		return append(errors, err)
+	}
 
+	state, err := loadResumeState(fs, execOpts.resumeFile)
 	if err != nil {
-		return []error{
-			fmt.Errorf("failed to create a client for env `%s` due to the following error: %w", env.Name, err),
+		return append(errors, err)
+	}
+
+	envNames := maps.Keys(plans)
+	sort.Strings(envNames)
+	for _, env := range envNames {
+		plan := plans[env]
+		if state[env] == nil {
+			state[env] = &delete.Progress{}
+		} else {
+			log.Info("Resuming purge of environment `%s`, skipping %d already deleted configs", env, len(state[env].Deleted))
+		}
+
+		log.Info("Deleting %d configs for environment `%s`", plan.Count(), env)
+		execErrs := plan.ExecuteWithOptions(ctx, clients[env], delete.ExecuteOptions{
+			BatchSize:       execOpts.batchSize,
+			BatchPause:      execOpts.batchPause,
+			ContinueOnError: !execOpts.failFast,
+			Progress:        state[env],
+			OnProgress: func(done, total int) {
+				log.Info("Processed %d/%d configs of environment `%s` (%d failed)", done, total, env, len(state[env].Failed))
+				if err := saveResumeState(fs, execOpts.resumeFile, state); err != nil {
+					log.Warn("Failed to write resume file: %s", err)
+				}
+			},
+		})
+		errors = append(errors, execErrs...)
+
+		if len(execErrs) > 0 && execOpts.failFast {
+			break
 		}
 	}
 
-	log.Info("Deleting configs for environment `%s`", env.Name)
+	for env, progress := range state {
+		for key, reason := range progress.Failed {
+			log.Error("Failed to delete %s of environment `%s`: %s", key, env, reason)
+		}
+	}
+	if len(errors) == 0 && execOpts.resumeFile != "" {
+		if err := fs.Remove(execOpts.resumeFile); err != nil && !os.IsNotExist(err) {
+			log.Warn("Failed to remove resume file %q of completed purge: %s", execOpts.resumeFile, err)
+		}
+	} else if execOpts.resumeFile != "" {
+		log.Info("Progress was written to %q. Run the purge again with the same '--resume-file' to continue it", execOpts.resumeFile)
+	}
+
+	return errors
+}
 
-	errs := delete.DeleteAllConfigs(dynatraceClient, apis)
-	errs = append(errs, delete.DeleteAllSettingsObjects(dynatraceClient)...)
+// loadResumeState reads the given resume file. If no file is given or it does not exist, an empty state is returned.
+func loadResumeState(fs afero.Fs, file string) (resumeState, error) {
+	state := make(resumeState)
+	if file == "" {
+		return state, nil
+	}
+
+	data, err := afero.ReadFile(fs, file)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resume file %q: %w", file, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse resume file %q: %w", file, err)
+	}
+	return state, nil
+}
 
-	return errs
+func saveResumeState(fs afero.Fs, file string, state resumeState) error {
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, file, data, 0644)
 }
@@ -0,0 +1,114 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/refactor"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// GetRefactorCommand returns the command group to change configs across a whole monaco repository.
+func GetRefactorCommand(fs afero.Fs) *cobra.Command {
+	refactorCmd := &cobra.Command{
+		Use:   "refactor",
+		Short: "Change configs across all projects of a manifest",
+	}
+
+	refactorCmd.AddCommand(getMoveCommand(fs))
+
+	return refactorCmd
+}
+
+func getMoveCommand(fs afero.Fs) (moveCmd *cobra.Command) {
+	var manifestName string
+
+	moveCmd = &cobra.Command{
+		Use:   "move <project:type:id> <project:type:id>",
+		Short: "Move or rename a config and rewrite all references to it",
+		Long: `Move or rename a config and rewrite all references to it
+
+  The config definition is moved to the config file at the same relative path in the target project, and its template
+  is moved along. All references and 'dependsOn' entries in all projects of the manifest are rewritten.
+
+  The original coordinate is stored as 'movedFrom' of the config, so that already deployed objects are updated
+  instead of being created again.`,
+		Example: "monaco refactor move old-project:dashboard:overview new-project:dashboard:overview",
+		Args:    cobra.ExactArgs(2),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Move(fs, manifestName, args[0], args[1])
+		},
+	}
+
+	moveCmd.Flags().StringVarP(&manifestName, "manifest", "m", "manifest.yaml", "Path to the manifest defining the projects")
+	err := moveCmd.MarkFlagFilename("manifest", files.YamlExtensions...)
+	if err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return moveCmd
+}
+
+// GetMigrateAPICommand returns the command migrating configs of a deprecated config API.
+func GetMigrateAPICommand(fs afero.Fs) (migrateCmd *cobra.Command) {
+	var manifestName, from, to string
+
+	migrateCmd = &cobra.Command{
+		Use:   "migrate-api",
+		Short: "Migrate all configs of a deprecated config API to the API or settings schema replacing it",
+		Long: `Migrate all configs of a deprecated config API to the API or settings schema replacing it
+
+  The types of all configs of the API are rewritten, and their templates are converted to the payload of the target.
+  All references to the migrated configs in all projects of the manifest are rewritten.
+
+  Templates need to be valid JSON to be converted, i.e. placeholders must be within strings. Objects deployed via
+  the deprecated API are not removed, and need to be deleted after the migrated configs are deployed.
+
+  APIs of monaco v1 replaced by their successor are resolved to it, e.g. 'dashboard-v2' to 'dashboard', whose handling
+  of non-unique names is the default by now.
+
+  Supported migrations:
+    ` + strings.Join(refactor.SupportedAPIMigrations(), "\n    "),
+		Example: "monaco migrate-api --from alerting-profile --to builtin:alerting.profile",
+		Args:    cobra.NoArgs,
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return MigrateAPI(fs, manifestName, from, to)
+		},
+	}
+
+	migrateCmd.Flags().StringVarP(&manifestName, "manifest", "m", "manifest.yaml", "Path to the manifest defining the projects")
+	migrateCmd.Flags().StringVar(&from, "from", "", "The deprecated config API whose configs are migrated, e.g. 'alerting-profile'")
+	migrateCmd.Flags().StringVar(&to, "to", "", "The config API or settings schema the configs are migrated to. If not set, the one deprecating the API is used")
+	if err := migrateCmd.MarkFlagRequired("from"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := migrateCmd.MarkFlagFilename("manifest", files.YamlExtensions...); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := migrateCmd.RegisterFlagCompletionFunc("from", completion.AllAvailableApis); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return migrateCmd
+}
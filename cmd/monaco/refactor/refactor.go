@@ -0,0 +1,83 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/refactor"
+	"github.com/spf13/afero"
+	"path/filepath"
+)
+
+// Move moves the config at the coordinate 'from' to the coordinate 'to' within the projects of the given manifest.
+// Both coordinates are given in the form 'project:type:id'.
+func Move(fs afero.Fs, manifestPath string, from, to string) error {
+	fromCoordinate, err := coordinate.Parse(from)
+	if err != nil {
+		return err
+	}
+	toCoordinate, err := coordinate.Parse(to)
+	if err != nil {
+		return err
+	}
+
+	repoFs, projects, err := loadProjects(fs, manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if err := refactor.Move(repoFs, projects, fromCoordinate, toCoordinate); err != nil {
+		return fmt.Errorf("failed to move config %s to %s: %w", fromCoordinate, toCoordinate, err)
+	}
+
+	log.Info("Moved config %s to %s", fromCoordinate, toCoordinate)
+	return nil
+}
+
+// MigrateAPI migrates all configs of the deprecated config API 'from' within the projects of the given manifest to the
+// config API or settings schema 'to', or to the one deprecating 'from' if 'to' is empty.
+func MigrateAPI(fs afero.Fs, manifestPath string, from, to string) error {
+	repoFs, projects, err := loadProjects(fs, manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if err := refactor.MigrateAPI(repoFs, projects, from, to); err != nil {
+		return fmt.Errorf("failed to migrate configs of API %q: %w", from, err)
+	}
+	return nil
+}
+
+// loadProjects returns the projects of the given manifest, and a file system rooted at the folder of the manifest
+// which project paths are relative to.
+func loadProjects(fs afero.Fs, manifestPath string) (afero.Fs, manifest.ProjectDefinitionByProjectID, error) {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, err := cmdutils.LoadManifest(fs, absManifestPath, cmdutils.ManifestOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return afero.NewBasePathFs(fs, filepath.Dir(absManifestPath)), m.Projects, nil
+}
@@ -15,11 +15,33 @@
 package runner
 
 import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/account"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/lint"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/backup"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/bootstrap"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/convert"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/delete"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/docs"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/download"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/findreferences"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/importer"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/maintenance"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/open"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/packaging"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/purge"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/refactor"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/scaffold"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/schema"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/serve"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/tags"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/templates"
 	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
@@ -36,7 +58,11 @@ var optionalAddedLogger *builtinLog.Logger
 func Run() int {
 	rootCmd := BuildCli(afero.NewOsFs())
 
-	if err := rootCmd.Execute(); err != nil {
+	// cancel all running requests on interrupt, so that monaco can shut down cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		return 1
 	}
 	return 0
@@ -51,6 +77,7 @@ func BuildCliWithCapturedLog(fs afero.Fs, logOutput io.Writer) *cobra.Command {
 
 func BuildCli(fs afero.Fs) *cobra.Command {
 	var verbose bool
+	var failOnWarning bool
 
 	var rootCmd = &cobra.Command{
 		Use:   "monaco <command>",
@@ -71,12 +98,32 @@ Examples:
 
 	// global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable debug logging")
+	rootCmd.PersistentFlags().BoolVar(&failOnWarning, "fail-on-warning", false, "Fail the command if any warnings were logged, e.g. for strict pipelines")
 
 	// commands
 	rootCmd.AddCommand(download.GetDownloadCommand(fs, &download.DefaultCommand{}))
 	rootCmd.AddCommand(convert.GetConvertCommand(fs))
 	rootCmd.AddCommand(deploy.GetDeployCommand(fs))
+	rootCmd.AddCommand(bootstrap.GetBootstrapCommand(fs))
+	rootCmd.AddCommand(scaffold.GetNewCommand(fs))
+	rootCmd.AddCommand(importer.GetImportCommand(fs))
+	rootCmd.AddCommand(templates.GetTemplatesCommand(fs))
 	rootCmd.AddCommand(delete.GetDeleteCommand(fs))
+	rootCmd.AddCommand(purge.GetPurgeTypesCommand(fs))
+	rootCmd.AddCommand(backup.GetBackupCommand(fs))
+	rootCmd.AddCommand(backup.GetRestoreCommand(fs))
+	rootCmd.AddCommand(serve.GetServeCommand(fs))
+	rootCmd.AddCommand(lint.GetLintCommand(fs))
+	rootCmd.AddCommand(schema.GetSchemaCommand(fs))
+	rootCmd.AddCommand(refactor.GetRefactorCommand(fs))
+	rootCmd.AddCommand(refactor.GetMigrateAPICommand(fs))
+	rootCmd.AddCommand(findreferences.GetFindReferencesCommand(fs))
+	rootCmd.AddCommand(docs.GetDocsCommand(fs))
+	rootCmd.AddCommand(account.GetAccountCommand(fs))
+	rootCmd.AddCommand(maintenance.GetMaintenanceCommand(fs))
+	rootCmd.AddCommand(packaging.GetPackageCommand(fs))
+	rootCmd.AddCommand(tags.GetTagsCommand(fs))
+	rootCmd.AddCommand(open.GetOpenCommand(fs))
 	rootCmd.AddCommand(version.GetVersionCommand())
 
 	if featureflags.DangerousCommands().Enabled() {
@@ -86,6 +133,8 @@ Examples:
 		rootCmd.AddCommand(purge.GetPurgeCommand(fs))
 	}
 
+	reportOnFinish(rootCmd, &failOnWarning)
+
 	return rootCmd
 }
 
@@ -94,6 +143,27 @@ func configureDebugLogging(fs afero.Fs, verbose *bool) func(cmd *cobra.Command,
 		if *verbose {
 			log.Default().SetLevel(log.LevelDebug)
 		}
+		// only warnings of the command itself are reported once it finished
+		log.ResetWarnings()
 		log.SetupLogging(fs, optionalAddedLogger)
 	}
 }
+
+// reportOnFinish wraps the RunE of the given command and all its sub-commands, so that rate limits and warnings are
+// reported once a command finished. Unlike post-run hooks, this also happens if the command failed.
+func reportOnFinish(cmd *cobra.Command, failOnWarning *bool) {
+	if runE := cmd.RunE; runE != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) (err error) {
+			defer func() {
+				cmdutils.ReportRateLimits()
+				if warnErr := cmdutils.ReportWarnings(*failOnWarning); err == nil {
+					err = warnErr
+				}
+			}()
+			return runE(cmd, args)
+		}
+	}
+	for _, c := range cmd.Commands() {
+		reportOnFinish(c, failOnWarning)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scaffold
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+func GetNewCommand(fs afero.Fs) (newCmd *cobra.Command) {
+	newCmd = &cobra.Command{
+		Use:   "new",
+		Short: "Create new monaco files",
+	}
+
+	newCmd.AddCommand(getNewProjectCommand(fs))
+
+	return newCmd
+}
+
+func getNewProjectCommand(fs afero.Fs) (projectCmd *cobra.Command) {
+	var opts Options
+
+	projectCmd = &cobra.Command{
+		Use:   "project <name> --type <type>",
+		Short: "Create a new project containing a config of the given type",
+		Long: `Create a new project containing a config of the given type
+
+  The type is either the name of a classic API, e.g. 'alerting-profile', or a Settings 2.0 schema ID prefixed by
+  'settings:', e.g. 'settings:builtin:alerting.profile'. For Settings 2.0 configs, the template is filled with the
+  defaults of the schema if a schema cache pulled by 'monaco schema pull' is given.`,
+		Example: "monaco new project alerting --type settings:builtin:alerting.profile --schema-cache .schemas/dev-environment",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Name = args[0]
+			return NewProject(fs, opts)
+		},
+	}
+
+	projectCmd.Flags().StringVarP(&opts.Type, "type", "t", "", "Classic API or Settings 2.0 schema (prefixed by 'settings:') of the config to create")
+	projectCmd.Flags().StringVarP(&opts.OutputFolder, "output-folder", "o", ".", "Folder to create the project in")
+	projectCmd.Flags().StringVar(&opts.SchemaCache, "schema-cache", "", "Schema cache of an environment, e.g. '.schemas/<environment>', to take the defaults of Settings 2.0 schemas from")
+
+	if err := projectCmd.MarkFlagRequired("type"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return projectCmd
+}
@@ -0,0 +1,206 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemacache"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strings"
+)
+
+// settingsTypePrefix marks a config type as Settings 2.0 schema, e.g. 'settings:builtin:alerting.profile'
+const settingsTypePrefix = "settings:"
+
+// Options defines the project created by NewProject.
+type Options struct {
+	// Name of the project, used as folder name and config ID
+	Name string
+	// Type is either the name of a classic API, or a Settings 2.0 schema ID prefixed by 'settings:'
+	Type string
+	// OutputFolder is the folder the project folder is created in
+	OutputFolder string
+	// SchemaCache is the optional folder of a schema cache pulled by 'monaco schema pull'. If set, the defaults of
+	// the schema are used for the template of a Settings 2.0 config.
+	SchemaCache string
+}
+
+// NewProject creates a project folder containing a single config of the given type. The template of a Settings 2.0
+// config is filled with the defaults of its schema, if the schema is cached.
+func NewProject(fs afero.Fs, opts Options) error {
+	if opts.Name == "" {
+		return errors.New("project name must not be empty")
+	}
+
+	projectFolder := filepath.Join(opts.OutputFolder, opts.Name)
+	if exists, err := afero.Exists(fs, projectFolder); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("folder %q already exists", projectFolder)
+	}
+
+	c, err := newConfig(fs, opts)
+	if err != nil {
+		return err
+	}
+
+	errs := config.WriteConfigs(&config.WriterContext{
+		Fs:              fs,
+		OutputFolder:    opts.OutputFolder,
+		ProjectFolder:   opts.Name,
+		ParametersSerde: config.ParameterParsers(),
+	}, []config.Config{c})
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to write project %q: %w", opts.Name, errors.Join(errs...))
+	}
+
+	log.Info("Created project %q in %q. Add it to the 'projects' of your manifest to deploy it.", opts.Name, projectFolder)
+	return nil
+}
+
+func newConfig(fs afero.Fs, opts Options) (config.Config, error) {
+	if schemaId, isSettings := strings.CutPrefix(opts.Type, settingsTypePrefix); isSettings {
+		return newSettingsConfig(fs, opts, schemaId)
+	}
+
+	if _, found := api.NewAPIs()[opts.Type]; !found {
+		return config.Config{}, fmt.Errorf("unknown type %q: must be a classic API or a Settings 2.0 schema prefixed by %q", opts.Type, settingsTypePrefix)
+	}
+
+	return config.Config{
+		Template: template.NewDownloadTemplate(opts.Name, opts.Name, "{\n  \"name\": \"{{.name}}\"\n}"),
+		Coordinate: coordinate.Coordinate{
+			Project:  opts.Name,
+			Type:     opts.Type,
+			ConfigId: opts.Name,
+		},
+		Type: config.ClassicApiType{Api: opts.Type},
+		Parameters: map[string]parameter.Parameter{
+			config.NameParameter: &value.ValueParameter{Value: opts.Name},
+		},
+	}, nil
+}
+
+func newSettingsConfig(fs afero.Fs, opts Options, schemaId string) (config.Config, error) {
+	if schemaId == "" {
+		return config.Config{}, fmt.Errorf("type %q does not define a schema ID", opts.Type)
+	}
+
+	s := schema{AllowedScopes: []string{"environment"}}
+	if opts.SchemaCache != "" {
+		var err error
+		if s, err = loadSchema(fs, opts.SchemaCache, schemaId); err != nil {
+			return config.Config{}, err
+		}
+	} else {
+		log.Info("No schema cache given, the template of %q is empty. Use 'monaco schema pull' and '--schema-cache' to start with the defaults of the schema.", schemaId)
+	}
+
+	content, err := s.template()
+	if err != nil {
+		return config.Config{}, err
+	}
+
+	scope := "environment"
+	if !s.allowsScope(scope) && len(s.AllowedScopes) > 0 {
+		scope = s.AllowedScopes[0]
+		log.Warn("Schema %q can not be used on environment scope, set the 'scope' parameter to the ID of a %s", schemaId, scope)
+	}
+
+	return config.Config{
+		Template: template.NewDownloadTemplate(opts.Name, opts.Name, content),
+		Coordinate: coordinate.Coordinate{
+			Project:  opts.Name,
+			Type:     schemaId,
+			ConfigId: opts.Name,
+		},
+		Type: config.SettingsType{
+			SchemaId:      schemaId,
+			SchemaVersion: s.Version,
+		},
+		Parameters: map[string]parameter.Parameter{
+			config.NameParameter:  &value.ValueParameter{Value: opts.Name},
+			config.ScopeParameter: &value.ValueParameter{Value: scope},
+		},
+	}, nil
+}
+
+// schema holds the parts of a Settings 2.0 schema definition needed to scaffold a config.
+type schema struct {
+	Version       string                    `json:"version"`
+	AllowedScopes []string                  `json:"allowedScopes"`
+	Properties    map[string]schemaProperty `json:"properties"`
+}
+
+type schemaProperty struct {
+	Default any `json:"default"`
+}
+
+func loadSchema(fs afero.Fs, cacheFolder string, schemaId string) (schema, error) {
+	version, data, err := schemacache.Load(fs, cacheFolder, schemaId)
+	if err != nil {
+		return schema{}, fmt.Errorf("failed to load schema %q from cache %q: %w", schemaId, cacheFolder, err)
+	}
+
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return schema{}, fmt.Errorf("failed to parse schema %q: %w", schemaId, err)
+	}
+	if s.Version == "" {
+		s.Version = version
+	}
+	return s, nil
+}
+
+func (s schema) allowsScope(scope string) bool {
+	for _, a := range s.AllowedScopes {
+		if a == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// template returns the JSON template holding the default of each property. A 'name' property references the name
+// parameter of the config instead.
+func (s schema) template() (string, error) {
+	content := make(map[string]any, len(s.Properties))
+	for name, p := range s.Properties {
+		if p.Default != nil {
+			content[name] = p.Default
+		}
+	}
+	if _, found := s.Properties["name"]; found {
+		content["name"] = "{{.name}}"
+	}
+
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to create template: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,87 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scaffold
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const alertingProfileSchema = `{
+  "schemaId": "builtin:alerting.profile",
+  "version": "8.2",
+  "allowedScopes": ["environment"],
+  "properties": {
+    "name": {"type": "text"},
+    "severityRules": {"type": "list", "default": []},
+    "eventFilters": {"type": "list"},
+    "managementZone": {"type": "text", "default": ""}
+  }
+}`
+
+func TestNewProject(t *testing.T) {
+	t.Run("settings config with schema defaults", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "cache/index.json", []byte(`{"builtin:alerting.profile": "8.2"}`), 0644))
+		assert.NoError(t, afero.WriteFile(fs, "cache/builtin_alerting.profile/8.2.json", []byte(alertingProfileSchema), 0644))
+
+		err := NewProject(fs, Options{Name: "alerting", Type: "settings:builtin:alerting.profile", OutputFolder: "out", SchemaCache: "cache"})
+		assert.NoError(t, err)
+
+		configYaml, err := afero.ReadFile(fs, "out/alerting/builtinalerting.profile/config.yaml")
+		assert.NoError(t, err)
+		assert.Contains(t, string(configYaml), "schema: builtin:alerting.profile")
+		assert.Contains(t, string(configYaml), "schemaVersion: \"8.2\"")
+		assert.Contains(t, string(configYaml), "scope: environment")
+
+		tmpl, err := afero.ReadFile(fs, "out/alerting/builtinalerting.profile/alerting.json")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name": "{{.name}}", "severityRules": [], "managementZone": ""}`, string(tmpl))
+	})
+
+	t.Run("classic API config", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+
+		err := NewProject(fs, Options{Name: "profiles", Type: "alerting-profile", OutputFolder: "."})
+		assert.NoError(t, err)
+
+		configYaml, err := afero.ReadFile(fs, "profiles/alerting-profile/config.yaml")
+		assert.NoError(t, err)
+		assert.Contains(t, string(configYaml), "api: alerting-profile")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		err := NewProject(afero.NewMemMapFs(), Options{Name: "p", Type: "unknown"})
+		assert.ErrorContains(t, err, "unknown type")
+	})
+
+	t.Run("schema not cached", func(t *testing.T) {
+		err := NewProject(afero.NewMemMapFs(), Options{Name: "p", Type: "settings:builtin:unknown", SchemaCache: "cache"})
+		assert.ErrorContains(t, err, "not cached")
+	})
+
+	t.Run("existing project folder", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, fs.MkdirAll("p", 0777))
+
+		err := NewProject(fs, Options{Name: "p", Type: "alerting-profile", OutputFolder: "."})
+		assert.ErrorContains(t, err, "already exists")
+	})
+}
@@ -0,0 +1,179 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"strings"
+	"time"
+)
+
+// GetSchemaCommand returns the command group to publish the JSON Schemas of monaco's YAML files and to validate files
+// against them.
+func GetSchemaCommand(fs afero.Fs) *cobra.Command {
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Generate JSON Schemas of monaco's YAML files and validate files against them",
+		Long: `Generate JSON Schemas of monaco's YAML files and validate files against them
+
+  The schemas are generated from the same definitions monaco uses to load the files. They can be used by editors for
+  validation and autocompletion, e.g. by adding '# yaml-language-server: $schema=<path>/config.schema.json' to a file.
+  Additionally, the Settings 2.0 schemas of environments can be pulled to a local cache and compared between
+  environments.`,
+	}
+
+	schemaCmd.AddCommand(getGenerateCommand(fs))
+	schemaCmd.AddCommand(getValidateCommand(fs))
+	schemaCmd.AddCommand(getPullCommand(fs))
+	schemaCmd.AddCommand(getCompareCommand(fs))
+
+	return schemaCmd
+}
+
+func getGenerateCommand(fs afero.Fs) (generateCmd *cobra.Command) {
+	var outputFolder string
+
+	generateCmd = &cobra.Command{
+		Use:     "generate",
+		Short:   "Write the JSON Schemas of all monaco YAML files",
+		Example: "monaco schema generate -o schemas",
+		Args:    cobra.NoArgs,
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Generate(fs, outputFolder)
+		},
+	}
+
+	generateCmd.Flags().StringVarP(&outputFolder, "output-folder", "o", "schemas", "Folder to write the schemas to")
+
+	return generateCmd
+}
+
+func getValidateCommand(fs afero.Fs) (validateCmd *cobra.Command) {
+	var kind string
+
+	validateCmd = &cobra.Command{
+		Use:     "validate <file.yaml>...",
+		Short:   "Validate YAML files against their JSON Schema",
+		Example: "monaco schema validate manifest.yaml project/dashboards/config.yaml",
+		Args:    cobra.MinimumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Validate(fs, args, kind)
+		},
+	}
+
+	validateCmd.Flags().StringVarP(&kind, "kind", "k", "",
+		fmt.Sprintf("Kind of the files, one of %s. If not set, it is detected from the content of each file", strings.Join(Kinds(), ", ")))
+
+	return validateCmd
+}
+
+func getPullCommand(fs afero.Fs) (pullCmd *cobra.Command) {
+	var environments []string
+	var outputFolder string
+	var force, manifestFromEnv bool
+	var timeout time.Duration
+
+	pullCmd = &cobra.Command{
+		Use:   "pull [<manifest.yaml>]",
+		Short: "Download the Settings 2.0 schemas of environments to a local cache",
+		Long: `Download the Settings 2.0 schemas of environments to a local cache
+
+  The schemas of each environment are written to '<output-folder>/<environment>', one file per schema and version.
+  Schemas already cached in their latest version are not downloaded again, outdated versions are replaced.`,
+		Example: "monaco schema pull manifest.yaml -e dev-environment -o .schemas",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return Pull(ctx, fs, manifestName, manifestFromEnv, environments, outputFolder, force)
+		},
+	}
+
+	pullCmd.Flags().StringSliceVarP(&environments, "environment", "e", []string{},
+		"Environment(s) to download the schemas of. If not set, the schemas of all environments are downloaded.")
+	pullCmd.Flags().StringVarP(&outputFolder, "output-folder", "o", ".schemas", "Folder to cache the schemas in")
+	pullCmd.Flags().BoolVarP(&force, "force", "f", false, "Download all schemas, even if they are already cached in their latest version")
+	cmdutils.AddManifestFromEnvFlag(pullCmd, &manifestFromEnv)
+	cmdutils.AddTimeoutFlag(pullCmd, &timeout)
+
+	if err := pullCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByManifestFlag); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return pullCmd
+}
+
+func getCompareCommand(fs afero.Fs) (compareCmd *cobra.Command) {
+	var source, target string
+	var manifestFromEnv bool
+	var timeout time.Duration
+
+	compareCmd = &cobra.Command{
+		Use:   "compare [<manifest.yaml>]",
+		Short: "Compare the Settings 2.0 schemas available on two environments",
+		Long: `Compare the Settings 2.0 schemas available on two environments
+
+  Lists all schemas that are only available on one of the environments or in different versions. Schemas used by
+  settings configs of the manifest's projects, which are not available on the target environment in the version the
+  configs require, are highlighted and fail the command. Use it before promoting projects, e.g. to older Managed
+  clusters, to find configs that would fail to deploy.`,
+		Example: "monaco schema compare manifest.yaml --source dev --target prod",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			return Compare(ctx, fs, manifestName, manifestFromEnv, source, target)
+		},
+	}
+
+	compareCmd.Flags().StringVar(&source, "source", "", "Environment to compare the schemas of, e.g. the environment projects are developed on")
+	compareCmd.Flags().StringVar(&target, "target", "", "Environment to compare the schemas with, e.g. the environment projects are promoted to")
+	cmdutils.AddManifestFromEnvFlag(compareCmd, &manifestFromEnv)
+	cmdutils.AddTimeoutFlag(compareCmd, &timeout)
+
+	for _, f := range []string{"source", "target"} {
+		if err := compareCmd.MarkFlagRequired(f); err != nil {
+			log.Fatal("failed to setup CLI %v", err)
+		}
+		if err := compareCmd.RegisterFlagCompletionFunc(f, completion.EnvironmentByArg0); err != nil {
+			log.Fatal("failed to setup CLI %v", err)
+		}
+	}
+
+	return compareCmd
+}
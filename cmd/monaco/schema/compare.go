@@ -0,0 +1,209 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"sort"
+)
+
+// SchemaDifference describes how the availability of a Settings 2.0 schema differs between two environments.
+type SchemaDifference struct {
+	SchemaId string
+	// SourceVersion and TargetVersion are the latest versions of the schema on each environment. They are empty if the
+	// schema is not available on the environment.
+	SourceVersion, TargetVersion string
+	// UsedByProject is set if configs of the projects deployed to the target environment use the schema
+	UsedByProject bool
+	// RequiredVersion is the schema version pinned by configs of the projects, if any
+	RequiredVersion string
+}
+
+// MissingOnTarget returns true if configs of the projects use the schema, but it is not available on the target
+// environment in the version they require.
+func (d SchemaDifference) MissingOnTarget() bool {
+	if !d.UsedByProject {
+		return false
+	}
+	if d.TargetVersion == "" {
+		return true
+	}
+	if d.RequiredVersion == "" {
+		return false
+	}
+
+	required, err := version.ParseVersion(d.RequiredVersion)
+	if err != nil {
+		return d.RequiredVersion != d.TargetVersion
+	}
+	available, err := version.ParseVersion(d.TargetVersion)
+	if err != nil {
+		return d.RequiredVersion != d.TargetVersion
+	}
+	return available.SmallerThan(required)
+}
+
+// CompareSchemas returns all schemas that are not available on both environments in the same version, as well as all
+// schemas used by projects that are missing on the target, sorted by schema ID. usedSchemas maps the schemas used by
+// the projects to the version they require, or an empty string if they don't pin a version.
+func CompareSchemas(source, target client.SchemaList, usedSchemas map[string]string) []SchemaDifference {
+	differences := map[string]*SchemaDifference{}
+	get := func(schemaId string) *SchemaDifference {
+		if d, found := differences[schemaId]; found {
+			return d
+		}
+		d := &SchemaDifference{SchemaId: schemaId}
+		differences[schemaId] = d
+		return d
+	}
+
+	for _, s := range source {
+		get(s.SchemaId).SourceVersion = s.LatestSchemaVersion
+	}
+	for _, s := range target {
+		get(s.SchemaId).TargetVersion = s.LatestSchemaVersion
+	}
+	for schemaId, requiredVersion := range usedSchemas {
+		d := get(schemaId)
+		d.UsedByProject = true
+		d.RequiredVersion = requiredVersion
+	}
+
+	result := make([]SchemaDifference, 0, len(differences))
+	for _, d := range differences {
+		if d.SourceVersion != d.TargetVersion || d.MissingOnTarget() {
+			result = append(result, *d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SchemaId < result[j].SchemaId
+	})
+	return result
+}
+
+// Compare compares the Settings 2.0 schemas available on the source and target environment of the manifest. Schemas
+// used by configs of the manifest's projects but missing on the target are highlighted and fail the comparison, as
+// deploying them to the target would fail.
+func Compare(ctx context.Context, fs afero.Fs, manifestPath string, manifestFromEnv bool, sourceEnv, targetEnv string) error {
+	m, projects, err := cmdutils.LoadManifestAndProjects(fs, manifestPath, cmdutils.ManifestOptions{
+		Environments: []string{sourceEnv, targetEnv},
+		FromEnv:      manifestFromEnv,
+	})
+	if err != nil {
+		return err
+	}
+
+	usedSchemas := schemasUsedBy(projects, targetEnv)
+	if err != nil {
+		return err
+	}
+
+	source, err := listSchemas(ctx, m, sourceEnv)
+	if err != nil {
+		return err
+	}
+	target, err := listSchemas(ctx, m, targetEnv)
+	if err != nil {
+		return err
+	}
+
+	return reportDifferences(sourceEnv, targetEnv, CompareSchemas(source, target, usedSchemas))
+}
+
+func listSchemas(ctx context.Context, m manifest.Manifest, environment string) (client.SchemaList, error) {
+	env, found := m.Environments[environment]
+	if !found {
+		return nil, fmt.Errorf("environment %q was not available in manifest", environment)
+	}
+
+	c, err := cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a client for environment %q: %w", environment, err)
+	}
+
+	schemas, err := c.ListSchemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas of environment %q: %w", environment, err)
+	}
+	return schemas, nil
+}
+
+// schemasUsedBy returns the schemas used by settings configs of the given projects for the given environment, mapped
+// to the schema version they pin.
+func schemasUsedBy(projects []project.Project, environment string) map[string]string {
+	result := map[string]string{}
+	for _, p := range projects {
+		for _, configs := range p.Configs[environment] {
+			for _, c := range configs {
+				t, ok := c.Type.(config.SettingsType)
+				if !ok || c.Skip {
+					continue
+				}
+				if result[t.SchemaId] == "" {
+					result[t.SchemaId] = t.SchemaVersion
+				}
+			}
+		}
+	}
+	return result
+}
+
+func reportDifferences(sourceEnv, targetEnv string, differences []SchemaDifference) error {
+	if len(differences) == 0 {
+		log.Info("Environments %q and %q provide the same schemas", sourceEnv, targetEnv)
+		return nil
+	}
+
+	log.Info("Schemas differing between environments %q and %q (schema: %s -> %s):", sourceEnv, targetEnv, sourceEnv, targetEnv)
+	missing := 0
+	for _, d := range differences {
+		if d.MissingOnTarget() {
+			missing++
+			log.Error("\t%s: %s -> %s (used by project%s, missing on target)", d.SchemaId, versionOrMissing(d.SourceVersion), versionOrMissing(d.TargetVersion), requiredSuffix(d.RequiredVersion))
+			continue
+		}
+		log.Info("\t%s: %s -> %s", d.SchemaId, versionOrMissing(d.SourceVersion), versionOrMissing(d.TargetVersion))
+	}
+
+	if missing > 0 {
+		return fmt.Errorf("%d schema(s) used by projects are not available on environment %q", missing, targetEnv)
+	}
+	return nil
+}
+
+func versionOrMissing(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return v
+}
+
+func requiredSuffix(requiredVersion string) string {
+	if requiredVersion == "" {
+		return ""
+	}
+	return " in version " + requiredVersion
+}
@@ -0,0 +1,95 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	source := client.SchemaList{
+		{SchemaId: "builtin:alerting.profile", LatestSchemaVersion: "8.2"},
+		{SchemaId: "builtin:equal", LatestSchemaVersion: "1.0"},
+		{SchemaId: "builtin:new", LatestSchemaVersion: "1.0"},
+		{SchemaId: "builtin:pinned", LatestSchemaVersion: "2.1"},
+	}
+	target := client.SchemaList{
+		{SchemaId: "builtin:alerting.profile", LatestSchemaVersion: "8.0"},
+		{SchemaId: "builtin:equal", LatestSchemaVersion: "1.0"},
+		{SchemaId: "builtin:old", LatestSchemaVersion: "0.1"},
+		{SchemaId: "builtin:pinned", LatestSchemaVersion: "2.0"},
+	}
+
+	t.Run("differences are reported", func(t *testing.T) {
+		got := CompareSchemas(source, target, nil)
+
+		assert.Equal(t, []SchemaDifference{
+			{SchemaId: "builtin:alerting.profile", SourceVersion: "8.2", TargetVersion: "8.0"},
+			{SchemaId: "builtin:new", SourceVersion: "1.0"},
+			{SchemaId: "builtin:old", TargetVersion: "0.1"},
+			{SchemaId: "builtin:pinned", SourceVersion: "2.1", TargetVersion: "2.0"},
+		}, got)
+		for _, d := range got {
+			assert.False(t, d.MissingOnTarget(), d.SchemaId)
+		}
+	})
+
+	t.Run("schemas used by projects are highlighted if missing on target", func(t *testing.T) {
+		got := CompareSchemas(source, target, map[string]string{
+			"builtin:alerting.profile": "",
+			"builtin:new":              "",
+			"builtin:pinned":           "2.1",
+			"builtin:unknown":          "",
+		})
+
+		missing := map[string]bool{}
+		for _, d := range got {
+			missing[d.SchemaId] = d.MissingOnTarget()
+		}
+		assert.Equal(t, map[string]bool{
+			"builtin:alerting.profile": false,
+			"builtin:new":              true,
+			"builtin:old":              false,
+			"builtin:pinned":           true,
+			"builtin:unknown":          true,
+		}, missing)
+	})
+}
+
+func TestSchemaDifference_MissingOnTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		diff SchemaDifference
+		want bool
+	}{
+		{"unused schema", SchemaDifference{SourceVersion: "1.0"}, false},
+		{"not available", SchemaDifference{UsedByProject: true, SourceVersion: "1.0"}, true},
+		{"available without pinned version", SchemaDifference{UsedByProject: true, TargetVersion: "0.1"}, false},
+		{"newer version available", SchemaDifference{UsedByProject: true, TargetVersion: "1.2.0", RequiredVersion: "1.1.3"}, false},
+		{"older version available", SchemaDifference{UsedByProject: true, TargetVersion: "1.0.9", RequiredVersion: "1.1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.diff.MissingOnTarget())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemacache"
+	"github.com/spf13/afero"
+	"path/filepath"
+)
+
+// Pull downloads the Settings 2.0 schemas of the given environments of the manifest into the schema cache
+// '<outputFolder>/<environment>'. If no environments are given, the schemas of all environments are downloaded.
+func Pull(ctx context.Context, fs afero.Fs, manifestPath string, manifestFromEnv bool, environments []string, outputFolder string, force bool) error {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, errs := manifest.LoadManifest(&manifest.LoaderContext{
+		Fs:           fs,
+		ManifestPath: absManifestPath,
+		Environments: environments,
+		FromEnv:      manifestFromEnv,
+	})
+	if len(errs) > 0 {
+		errutils.PrintErrors(errs)
+		return errors.New("error while loading manifest")
+	}
+
+	return pullEnvironments(ctx, fs, m, outputFolder, force, func(env manifest.EnvironmentDefinition) (client.SettingsClient, error) {
+		return cmdutils.CreateDTClient(ctx, env.URL.Value, env.Auth, false, cmdutils.WithHTTPSettings(m.HTTP.ForEnvironment(env)))
+	})
+}
+
+func pullEnvironments(ctx context.Context, fs afero.Fs, m manifest.Manifest, outputFolder string, force bool, createClient func(manifest.EnvironmentDefinition) (client.SettingsClient, error)) error {
+	failed := 0
+	for _, env := range m.Environments {
+		c, err := createClient(env)
+		if err != nil {
+			log.Error("Failed to create a client for environment %q: %s", env.Name, err)
+			failed++
+			continue
+		}
+
+		folder := filepath.Join(outputFolder, env.Name)
+		summary, err := schemacache.Pull(ctx, fs, c, folder, force)
+		if err != nil {
+			log.Error("Failed to pull schemas of environment %q: %s", env.Name, err)
+			failed++
+			continue
+		}
+		log.Info("Pulled schemas of environment %q to %q: %d downloaded, %d up to date, %d removed", env.Name, folder, summary.Downloaded, summary.UpToDate, summary.Removed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to pull schemas of %d environment(s)", failed)
+	}
+	return nil
+}
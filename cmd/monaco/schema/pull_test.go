@@ -0,0 +1,54 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemacache"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPullEnvironments(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	m := manifest.Manifest{Environments: manifest.Environments{
+		"dev":  {Name: "dev"},
+		"prod": {Name: "prod"},
+	}}
+
+	err := pullEnvironments(context.TODO(), fs, m, "schemas", false, func(env manifest.EnvironmentDefinition) (client.SettingsClient, error) {
+		if env.Name == "prod" {
+			return nil, errors.New("no access")
+		}
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: "builtin:a", LatestSchemaVersion: "1.0"}}, nil)
+		c.EXPECT().GetSchema(gomock.Any(), "builtin:a").Return([]byte(`{}`), nil)
+		return c, nil
+	})
+	assert.EqualError(t, err, "failed to pull schemas of 1 environment(s)")
+
+	version, _, err := schemacache.Load(fs, "schemas/dev", "builtin:a")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", version)
+}
@@ -0,0 +1,134 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/jsonschema"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/delete"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/workspace"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kinds of YAML files with a schema
+const (
+	KindManifest  = "manifest"
+	KindConfig    = "config"
+	KindDelete    = "delete"
+	KindWorkspace = "workspace"
+)
+
+// schemas holds the functions returning the schema of each kind of file
+var schemas = map[string]func() *jsonschema.Schema{
+	KindManifest:  manifest.JSONSchema,
+	KindConfig:    config.JSONSchema,
+	KindDelete:    delete.JSONSchema,
+	KindWorkspace: workspace.JSONSchema,
+}
+
+// Kinds returns the sorted kinds of files schemas are available for.
+func Kinds() []string {
+	kinds := make([]string, 0, len(schemas))
+	for k := range schemas {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Generate writes the schemas of all kinds of files into the given folder, named '<kind>.schema.json'.
+func Generate(fs afero.Fs, outputFolder string) error {
+	if err := fs.MkdirAll(outputFolder, 0777); err != nil {
+		return fmt.Errorf("failed to create output folder %q: %w", outputFolder, err)
+	}
+
+	for _, kind := range Kinds() {
+		data, err := json.MarshalIndent(schemas[kind](), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to serialize %s schema: %w", kind, err)
+		}
+
+		path := filepath.Join(outputFolder, kind+".schema.json")
+		if err := afero.WriteFile(fs, path, append(data, '\n'), 0664); err != nil {
+			return fmt.Errorf("failed to write %s schema: %w", kind, err)
+		}
+		log.Info("Written %s schema to %q", kind, path)
+	}
+
+	return nil
+}
+
+// Validate validates the given files against the schema of the given kind. If kind is empty, the kind of each file is
+// detected by its top-level keys. All violations are logged, and an error is returned if there are any.
+func Validate(fs afero.Fs, files []string, kind string) error {
+	if kind != "" {
+		if _, found := schemas[kind]; !found {
+			return fmt.Errorf("unknown kind %q, must be one of %s", kind, strings.Join(Kinds(), ", "))
+		}
+	}
+
+	violations := 0
+	for _, file := range files {
+		data, err := afero.ReadFile(fs, file)
+		if err != nil {
+			return fmt.Errorf("failed to read %q: %w", file, err)
+		}
+
+		fileKind := kind
+		if fileKind == "" {
+			fileKind = detectKind(data)
+		}
+
+		errs := jsonschema.Validate(data, schemas[fileKind]())
+		for _, e := range errs {
+			log.Error("%s: %s", file, e)
+		}
+		if len(errs) == 0 {
+			log.Info("%s: valid %s file", file, fileKind)
+		}
+		violations += len(errs)
+	}
+
+	if violations > 0 {
+		return fmt.Errorf("found %d schema violations", violations)
+	}
+	return nil
+}
+
+// detectKind returns the kind of the given YAML file content, based on the top-level keys specific to manifest, delete
+// and workspace files. Any other file is considered to be a config file.
+func detectKind(data []byte) string {
+	root := yamlnode.Parse(data)
+	switch {
+	case yamlnode.Get(root, "manifestVersion") != nil:
+		return KindManifest
+	case yamlnode.Get(root, "delete") != nil:
+		return KindDelete
+	case yamlnode.Get(root, "manifests") != nil:
+		return KindWorkspace
+	default:
+		return KindConfig
+	}
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"encoding/json"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	err := Generate(fs, "schemas")
+	assert.NoError(t, err)
+
+	for _, kind := range []string{KindConfig, KindDelete, KindManifest, KindWorkspace} {
+		data, err := afero.ReadFile(fs, filepath.Join("schemas", kind+".schema.json"))
+		assert.NoError(t, err)
+
+		var schema map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &schema), "%s schema is no valid JSON", kind)
+		assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+	}
+}
+
+func TestValidate(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "manifest.yaml", []byte(`manifestVersion: 1.0
+projects:
+- name: project
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://example.com
+    auth:
+      token:
+        name: TOKEN
+`), 0644)
+	_ = afero.WriteFile(fs, "config.yaml", []byte(`configs:
+- id: profile
+  config:
+    name: profile
+    template: profile.json
+  type: alerting-profile
+`), 0644)
+	_ = afero.WriteFile(fs, "delete.yaml", []byte("delete:\n- alerting-profile/profile\n"), 0644)
+	_ = afero.WriteFile(fs, "invalid.yaml", []byte("configs:\n- id: profile\n  confg: {}\n"), 0644)
+
+	t.Run("valid files of all kinds", func(t *testing.T) {
+		assert.NoError(t, Validate(fs, []string{"manifest.yaml", "config.yaml", "delete.yaml"}, ""))
+	})
+
+	t.Run("violations are reported", func(t *testing.T) {
+		assert.EqualError(t, Validate(fs, []string{"config.yaml", "invalid.yaml"}, ""), "found 3 schema violations")
+	})
+
+	t.Run("kind is enforced", func(t *testing.T) {
+		assert.Error(t, Validate(fs, []string{"delete.yaml"}, KindConfig))
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		assert.ErrorContains(t, Validate(fs, []string{"config.yaml"}, "dashboard"), "unknown kind")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		assert.Error(t, Validate(fs, []string{"missing.yaml"}, ""))
+	})
+}
+
+// TestValidate_TestResources ensures that the schemas match the files monaco is tested with.
+func TestValidate_TestResources(t *testing.T) {
+	fs := afero.NewReadOnlyFs(afero.NewOsFs())
+	var files []string
+
+	err := afero.Walk(fs, "../integrationtest/v2/test-resources", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.Contains(path, "invalid") || filepath.Ext(path) != ".yaml" {
+			return err
+		}
+		files = append(files, path)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	assert.NoError(t, Validate(fs, files, ""))
+}
+
+func TestDetectKind(t *testing.T) {
+	assert.Equal(t, KindManifest, detectKind([]byte("manifestVersion: 1.0\n")))
+	assert.Equal(t, KindDelete, detectKind([]byte("delete: []\n")))
+	assert.Equal(t, KindWorkspace, detectKind([]byte("manifests: []\n")))
+	assert.Equal(t, KindConfig, detectKind([]byte("configs: []\n")))
+	assert.Equal(t, KindConfig, detectKind([]byte("not: [valid")))
+}
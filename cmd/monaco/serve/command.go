@@ -0,0 +1,141 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/deploy"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"path/filepath"
+	"time"
+)
+
+func GetServeCommand(fs afero.Fs) (serveCmd *cobra.Command) {
+	var deployOpts deploy.Options
+	var timeout time.Duration
+	var deploymentEvent string
+	var opts serveOptions
+
+	serveCmd = &cobra.Command{
+		Use:   "serve [<manifest.yaml>]",
+		Short: "Periodically deploy configurations to Dynatrace environments",
+		Long: `Periodically deploy configurations to Dynatrace environments
+
+  Re-applies the manifest once per interval, reverting any drift of the deployed configurations. With '--report-drift',
+  configurations whose objects are missing or differ from them are only reported instead.
+  Manifest and projects are re-loaded from disk on every run, so they can be kept up to date e.g. by a git sync side-car.
+  The waits between rollout stages of the manifest are only applied to runs after files of the manifest folder changed,
+  and '--timeout' limits the duration of each run.
+  Health and metrics of the runs are served via HTTP on '/healthz' and '/metrics'.`,
+		Example:           "monaco serve manifest.yaml --interval 1h --listen :8080 -e prod-environment",
+		Args:              cobra.RangeArgs(0, 1),
+		ValidArgsFunction: completion.DeployCompletion,
+		PreRun:            cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, deployOpts.ManifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			if deployOpts.DeploymentEvent, err = cmdutils.ParseEventType(deploymentEvent); err != nil {
+				return err
+			}
+
+			folder, err := filepath.Abs(filepath.Dir(manifestName))
+			if err != nil {
+				return err
+			}
+
+			// rolledOut is the fingerprint of the files last deployed successfully. Rollout waits are meant for changes
+			// surfacing problems, thus they are skipped when re-applying the same files to revert drift.
+			var rolledOut string
+			// the root command's '--fail-on-warning' flag is not registered if the command is used on its own
+			failOnWarning, _ := cmd.Flags().GetBool("fail-on-warning")
+			return serve(cmd.Context(), opts, func(ctx context.Context) (drifted int, err error) {
+				ctx, cancel := cmdutils.WithTimeout(ctx, timeout)
+				defer cancel()
+
+				// serve does not finish like other commands, thus the rate limits and warnings of each run are reported
+				// and discarded
+				defer func() {
+					cmdutils.ReportRateLimits()
+					rest.ResetRateLimitStatistics()
+					if warnErr := cmdutils.ReportWarnings(failOnWarning); err == nil {
+						err = warnErr
+					}
+					log.ResetWarnings()
+				}()
+
+				if opts.reportDrift {
+					return deploy.ReportDrift(ctx, fs, manifestName, deployOpts)
+				}
+
+				fingerprint, err := filesFingerprint(fs, folder)
+				if err != nil {
+					log.Warn("Failed to check %q for changes, rollout waits are applied: %v", folder, err)
+				}
+				runOpts := deployOpts
+				runOpts.SkipRolloutWaits = fingerprint != "" && fingerprint == rolledOut
+
+				if err := deploy.Deploy(ctx, fs, manifestName, runOpts); err != nil {
+					return 0, err
+				}
+				rolledOut = fingerprint
+				return 0, nil
+			})
+		},
+	}
+
+	serveCmd.Flags().DurationVar(&opts.interval, "interval", time.Hour, "Time between two runs, e.g. '30m'")
+	serveCmd.Flags().StringVar(&opts.listenAddress, "listen", ":8080", "Address to serve the health ('/healthz') and metrics ('/metrics') endpoints on")
+	serveCmd.Flags().StringSliceVarP(&deployOpts.Environments, "environment", "e", []string{},
+		"Specify one (or multiple) environment(s) to deploy to. "+
+			"To set multiple environments either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--group'.")
+	serveCmd.Flags().StringSliceVarP(&deployOpts.EnvironmentGroups, "group", "g", []string{},
+		"Specify one (or multiple) environmentGroup(s) to deploy to. "+
+			"To set multiple groups either repeat this flag, or separate them using a comma (,). "+
+			"This flag is mutually exclusive with '--environment'")
+	serveCmd.Flags().StringSliceVarP(&deployOpts.Projects, "project", "p", make([]string, 0), "Project configuration to deploy (also deploys any dependent configurations)")
+	serveCmd.Flags().BoolVarP(&deployOpts.DryRun, "dry-run", "d", false, "Only validate the configurations on every run instead of deploying them")
+	serveCmd.Flags().BoolVar(&deployOpts.ValidateRemote, "validate-remote", false, "In dry-run mode, additionally validate configs against the configurations existing in the environments, e.g. to detect duplicate names. Requires valid credentials, but never writes to the environments")
+	serveCmd.Flags().BoolVar(&deployOpts.ValidateScopes, "validate-scopes", false, "Verify that the entities settings are scoped to (e.g. HOST-1234567890ABCDEF) exist in the environments before deploying them. In dry-run mode, this requires valid credentials, but never writes to the environments")
+	serveCmd.Flags().BoolVarP(&deployOpts.ContinueOnErr, "continue-on-error", "c", false, "Proceed a run even if config upload fails")
+	serveCmd.Flags().Var(&deployOpts.MaxFailures, "max-failures", "Proceed a run if config uploads fail, but abort the deployment to an environment once more configs failed than this number (e.g. '5') or percentage of its configs (e.g. '10%')")
+	serveCmd.Flags().BoolVar(&opts.reportDrift, "report-drift", false, "Only report configurations whose objects are missing or differ from them on every run, instead of deploying them. Requires valid credentials, but never writes to the environments")
+	cmdutils.AddTimeoutFlag(serveCmd, &timeout)
+	cmdutils.AddManifestFromEnvFlag(serveCmd, &deployOpts.ManifestFromEnv)
+	cmdutils.AddDeploymentEventFlag(serveCmd, &deploymentEvent)
+
+	if err := serveCmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := serveCmd.RegisterFlagCompletionFunc("project", completion.ProjectsFromManifest); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	serveCmd.MarkFlagsMutuallyExclusive("environment", "group")
+	serveCmd.MarkFlagsMutuallyExclusive("continue-on-error", "max-failures")
+	serveCmd.MarkFlagsMutuallyExclusive("report-drift", "dry-run")
+
+	return serveCmd
+}
@@ -0,0 +1,256 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runFunc executes a single reconciliation run. It returns the number of drifted configs if drift is reported.
+type runFunc func(ctx context.Context) (int, error)
+
+type serveOptions struct {
+	interval      time.Duration
+	listenAddress string
+	// reportDrift states that runs report drift instead of deploying
+	reportDrift bool
+}
+
+// status keeps track of all reconciliation runs. It is safe for concurrent use.
+type status struct {
+	mutex         sync.RWMutex
+	reportDrift   bool
+	runs          int
+	failures      int
+	lastRunStart  time.Time
+	lastRunEnd    time.Time
+	lastRunErr    error
+	lastSuccessAt time.Time
+	// drifted is the number of configs the last successful run found drifted
+	drifted int
+}
+
+func (s *status) record(start, end time.Time, drifted int, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.runs++
+	s.lastRunStart = start
+	s.lastRunEnd = end
+	s.lastRunErr = err
+	if err != nil {
+		s.failures++
+	} else {
+		s.lastSuccessAt = end
+		s.drifted = drifted
+	}
+}
+
+type healthResponse struct {
+	Status        string     `json:"status"`
+	Runs          int        `json:"runs"`
+	Failures      int        `json:"failures"`
+	LastRun       *time.Time `json:"lastRun,omitempty"`
+	LastSuccess   *time.Time `json:"lastSuccess,omitempty"`
+	LastRunError  string     `json:"lastRunError,omitempty"`
+	LastRunMillis int64      `json:"lastRunDurationMillis"`
+	Drifted       *int       `json:"driftedConfigs,omitempty"`
+}
+
+// healthHandler reports the state of the last run as JSON. It always answers with 200 as long as the process is able to
+// serve requests, as a failing run is not resolved by restarting monaco.
+func (s *status) healthHandler(w http.ResponseWriter, _ *http.Request) {
+	s.mutex.RLock()
+	resp := healthResponse{
+		Status:        "ok",
+		Runs:          s.runs,
+		Failures:      s.failures,
+		LastRunMillis: s.lastRunEnd.Sub(s.lastRunStart).Milliseconds(),
+	}
+	if s.runs > 0 {
+		end := s.lastRunEnd
+		resp.LastRun = &end
+	}
+	if !s.lastSuccessAt.IsZero() {
+		success := s.lastSuccessAt
+		resp.LastSuccess = &success
+	}
+	if s.lastRunErr != nil {
+		resp.Status = "failing"
+		resp.LastRunError = s.lastRunErr.Error()
+	}
+	if s.reportDrift && !s.lastSuccessAt.IsZero() {
+		drifted := s.drifted
+		resp.Drifted = &drifted
+		if drifted > 0 && resp.Status == "ok" {
+			resp.Status = "drifted"
+		}
+	}
+	s.mutex.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("Failed to write health response: %v", err)
+	}
+}
+
+// metricsHandler exposes the run statistics in the Prometheus text format.
+func (s *status) metricsHandler(w http.ResponseWriter, _ *http.Request) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	lastSuccess := 0
+	if s.runs > 0 && s.lastRunErr == nil {
+		lastSuccess = 1
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeMetric(w, "monaco_runs_total", "counter", "Number of finished reconciliation runs.", s.runs)
+	writeMetric(w, "monaco_run_failures_total", "counter", "Number of failed reconciliation runs.", s.failures)
+	writeMetric(w, "monaco_last_run_success", "gauge", "Whether the last reconciliation run succeeded (1) or not (0).", lastSuccess)
+	writeMetric(w, "monaco_last_run_duration_seconds", "gauge", "Duration of the last reconciliation run.", s.lastRunEnd.Sub(s.lastRunStart).Seconds())
+	writeMetric(w, "monaco_last_run_timestamp_seconds", "gauge", "Unix time the last reconciliation run finished.", unixSeconds(s.lastRunEnd))
+	writeMetric(w, "monaco_last_success_timestamp_seconds", "gauge", "Unix time of the last successful reconciliation run.", unixSeconds(s.lastSuccessAt))
+	if s.reportDrift {
+		writeMetric(w, "monaco_drifted_configs", "gauge", "Number of configs whose objects differ from them, as of the last successful run.", s.drifted)
+	}
+}
+
+func writeMetric(w http.ResponseWriter, name, metricType, help string, value any) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+}
+
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func newServer(address string, s *status) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthHandler)
+	mux.HandleFunc("/metrics", s.metricsHandler)
+
+	return &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// serve executes run right away and then once per interval until ctx is done. Meanwhile, the health and metrics
+// endpoints are served on the configured address.
+func serve(ctx context.Context, opts serveOptions, run runFunc) error {
+	if opts.interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0, but was %v", opts.interval)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := &status{reportDrift: opts.reportDrift}
+	server := newServer(opts.listenAddress, s)
+
+	var serverErr error
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		log.Info("Serving health and metrics endpoints on %q", opts.listenAddress)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr = err
+			cancel()
+		}
+	}()
+
+	reconcileLoop(ctx, opts.interval, s, run)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
+
+	<-serverDone
+	if serverErr != nil {
+		return fmt.Errorf("failed to serve health and metrics endpoints: %w", serverErr)
+	}
+	return nil
+}
+
+func reconcileLoop(ctx context.Context, interval time.Duration, s *status, run runFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		log.Info("Starting reconciliation run")
+		drifted, err := run(ctx)
+		s.record(start, time.Now(), drifted, err)
+
+		if err != nil {
+			log.Error("Reconciliation run failed: %v", err)
+		} else {
+			log.Info("Reconciliation run finished in %v", time.Since(start).Truncate(time.Millisecond))
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Stopping reconciliation")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// filesFingerprint returns a hash of the paths, sizes and modification times of all files in the given folder, except
+// for hidden ones like '.git'. It changes whenever files are added, removed or modified, e.g. by a git sync side-car.
+func filesFingerprint(fs afero.Fs, folder string) (string, error) {
+	hash := sha256.New()
+	err := afero.Walk(fs, folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != folder && strings.HasPrefix(info.Name(), ".") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			_, _ = fmt.Fprintf(hash, "%s\x00%d\x00%d\n", path, info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,148 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReconcileLoop_RunsUntilContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &status{}
+
+	runs := 0
+	reconcileLoop(ctx, time.Millisecond, s, func(context.Context) (int, error) {
+		runs++
+		if runs == 3 {
+			cancel()
+			return 0, errors.New("failed")
+		}
+		return 0, nil
+	})
+
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, 3, s.runs)
+	assert.Equal(t, 1, s.failures)
+	assert.EqualError(t, s.lastRunErr, "failed")
+	assert.False(t, s.lastSuccessAt.IsZero())
+}
+
+func TestServe_RejectsInvalidInterval(t *testing.T) {
+	err := serve(context.TODO(), serveOptions{interval: 0}, func(context.Context) (int, error) { return 0, nil })
+	assert.Error(t, err)
+}
+
+func TestServe_ReturnsErrorIfAddressIsInvalid(t *testing.T) {
+	err := serve(context.TODO(), serveOptions{interval: time.Hour, listenAddress: "invalid:address:99999"}, func(context.Context) (int, error) { return 0, nil })
+	assert.ErrorContains(t, err, "failed to serve health and metrics endpoints")
+}
+
+func TestHealthHandler(t *testing.T) {
+	s := &status{}
+
+	rec := httptest.NewRecorder()
+	s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp healthResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "ok", resp.Status)
+	assert.Nil(t, resp.LastRun)
+
+	now := time.Now()
+	s.record(now.Add(-time.Second), now, 0, errors.New("deployment failed"))
+
+	rec = httptest.NewRecorder()
+	s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "failing", resp.Status)
+	assert.Equal(t, "deployment failed", resp.LastRunError)
+	assert.Equal(t, 1, resp.Runs)
+	assert.Equal(t, 1, resp.Failures)
+	assert.Equal(t, int64(1000), resp.LastRunMillis)
+	assert.Nil(t, resp.LastSuccess)
+}
+
+func TestMetricsHandler(t *testing.T) {
+	s := &status{}
+	now := time.Unix(1680000000, 0)
+	s.record(now.Add(-2*time.Second), now, 0, nil)
+
+	rec := httptest.NewRecorder()
+	s.metricsHandler(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE monaco_runs_total counter\nmonaco_runs_total 1\n")
+	assert.Contains(t, body, "monaco_run_failures_total 0\n")
+	assert.Contains(t, body, "monaco_last_run_success 1\n")
+	assert.Contains(t, body, "monaco_last_run_duration_seconds 2\n")
+	assert.Contains(t, body, "monaco_last_run_timestamp_seconds 1680000000\n")
+	assert.Contains(t, body, "monaco_last_success_timestamp_seconds 1680000000\n")
+	assert.NotContains(t, body, "monaco_drifted_configs")
+}
+
+func TestStatus_ReportsDrift(t *testing.T) {
+	s := &status{reportDrift: true}
+	now := time.Now()
+	s.record(now.Add(-time.Second), now, 2, nil)
+
+	rec := httptest.NewRecorder()
+	s.metricsHandler(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), "# TYPE monaco_drifted_configs gauge\nmonaco_drifted_configs 2\n")
+
+	rec = httptest.NewRecorder()
+	s.healthHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var resp healthResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "drifted", resp.Status)
+	assert.Equal(t, 2, *resp.Drifted)
+
+	// failed runs keep the drift of the last successful run
+	s.record(now, now.Add(time.Second), 0, errors.New("failed"))
+	assert.Equal(t, 2, s.drifted)
+}
+
+func TestFilesFingerprint(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "repo/manifest.yaml", []byte("manifestVersion: 1.0"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "repo/project/config.yaml", []byte("configs: []"), 0644))
+
+	fingerprint, err := filesFingerprint(fs, "repo")
+	assert.NoError(t, err)
+
+	// hidden folders like '.git' are ignored
+	assert.NoError(t, afero.WriteFile(fs, "repo/.git/HEAD", []byte("ref: refs/heads/main"), 0644))
+	unchanged, err := filesFingerprint(fs, "repo")
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint, unchanged)
+
+	assert.NoError(t, afero.WriteFile(fs, "repo/project/config.yaml", []byte("configs: [] # changed"), 0644))
+	changed, err := filesFingerprint(fs, "repo")
+	assert.NoError(t, err)
+	assert.NotEqual(t, fingerprint, changed)
+}
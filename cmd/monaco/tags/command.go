@@ -0,0 +1,104 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tags
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/runner/completion"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+// GetTagsCommand returns the command group to add and remove custom tags of monitored entities.
+func GetTagsCommand(fs afero.Fs) *cobra.Command {
+	tagsCmd := &cobra.Command{
+		Use:   "tags",
+		Short: "Add and remove custom tags of monitored entities",
+		Long: `Add and remove custom tags of monitored entities
+
+  Tags are applied to all entities matching an entity selector, e.g. to mark the owners of hosts or services that
+  alerting profiles and management zones defined in projects rely on.`,
+	}
+
+	tagsCmd.AddCommand(getTagsSubCommand(fs, "apply", "Add tags to all entities matching an entity selector", "add tags to", Apply))
+	tagsCmd.AddCommand(getTagsSubCommand(fs, "remove", "Remove tags from all entities matching an entity selector", "remove tags from", Remove))
+
+	return tagsCmd
+}
+
+type tagsAction func(ctx context.Context, c client.Client, entitySelector string, tags []client.Tag, dryRun bool) error
+
+func getTagsSubCommand(fs afero.Fs, use string, short string, verb string, action tagsAction) (cmd *cobra.Command) {
+	var environment, selector string
+	var rawTags []string
+	var dryRun, manifestFromEnv bool
+	var timeout time.Duration
+
+	cmd = &cobra.Command{
+		Use:     use + " [<manifest.yaml>]",
+		Short:   short,
+		Example: "monaco tags " + use + " manifest.yaml -e prod --selector 'type(HOST),tag(env:prod)' --tag owner:team-a",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifestName, err := cmdutils.ResolveManifestPath(args, manifestFromEnv)
+			if err != nil {
+				return err
+			}
+
+			tags := make([]client.Tag, 0, len(rawTags))
+			for _, t := range rawTags {
+				tag, err := ParseTag(t)
+				if err != nil {
+					return err
+				}
+				tags = append(tags, tag)
+			}
+
+			ctx, cancel := cmdutils.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			c, err := cmdutils.CreateEnvironmentClient(ctx, fs, manifestName, manifestFromEnv, environment)
+			if err != nil {
+				return err
+			}
+			return action(ctx, c, selector, tags, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&environment, "environment", "e", "", "The environment defined in the manifest to "+verb+" entities in")
+	cmd.Flags().StringVar(&selector, "selector", "", "Entity selector matching the entities to "+verb+", e.g. 'type(HOST),tag(env:prod)'")
+	cmd.Flags().StringArrayVar(&rawTags, "tag", nil, "Tag given as '<key>' or '<key>:<value>'. Repeat the flag for several tags")
+	cmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Only list the entities matching the selector instead of changing their tags")
+	cmdutils.AddManifestFromEnvFlag(cmd, &manifestFromEnv)
+	cmdutils.AddTimeoutFlag(cmd, &timeout)
+
+	for _, f := range []string{"environment", "selector", "tag"} {
+		if err := cmd.MarkFlagRequired(f); err != nil {
+			log.Fatal("failed to setup CLI %v", err)
+		}
+	}
+	if err := cmd.RegisterFlagCompletionFunc("environment", completion.EnvironmentByArg0); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return cmd
+}
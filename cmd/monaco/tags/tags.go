@@ -0,0 +1,88 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tags
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"strings"
+)
+
+// ParseTag parses tags given as '<key>' or '<key>:<value>'.
+func ParseTag(s string) (client.Tag, error) {
+	key, value, _ := strings.Cut(s, ":")
+	if key == "" {
+		return client.Tag{}, fmt.Errorf("invalid tag %q! expected '<key>' or '<key>:<value>'", s)
+	}
+	return client.Tag{Key: key, Value: value}, nil
+}
+
+// Apply adds the given tags to all entities matching the entity selector. In dry-run mode, the matching entities are
+// only listed.
+func Apply(ctx context.Context, c client.Client, entitySelector string, tags []client.Tag, dryRun bool) error {
+	if dryRun {
+		return listAffected(ctx, c, entitySelector, "add", tags)
+	}
+
+	matched, err := c.AddTags(ctx, entitySelector, tags)
+	if err != nil {
+		return err
+	}
+	log.Info("Added tags %s to %d entities matching %q", tagList(tags), matched, entitySelector)
+	return nil
+}
+
+// Remove removes the given tags from all entities matching the entity selector. In dry-run mode, the matching
+// entities are only listed.
+func Remove(ctx context.Context, c client.Client, entitySelector string, tags []client.Tag, dryRun bool) error {
+	if dryRun {
+		return listAffected(ctx, c, entitySelector, "remove", tags)
+	}
+
+	for _, t := range tags {
+		matched, err := c.DeleteTag(ctx, entitySelector, t)
+		if err != nil {
+			return err
+		}
+		log.Info("Removed tag %q from %d entities matching %q", t, matched, entitySelector)
+	}
+	return nil
+}
+
+// listAffected logs the entities matching the entity selector, which would be changed by the given action.
+func listAffected(ctx context.Context, c client.EntitiesClient, entitySelector string, action string, tags []client.Tag) error {
+	ids, err := c.ListEntityIds(ctx, entitySelector)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Dry-run: would %s tags %s on %d entities matching %q", action, tagList(tags), len(ids), entitySelector)
+	for _, id := range ids {
+		log.Info("\t%s", id)
+	}
+	return nil
+}
+
+func tagList(tags []client.Tag) string {
+	s := make([]string, len(tags))
+	for i, t := range tags {
+		s[i] = fmt.Sprintf("%q", t)
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,96 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tags
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    client.Tag
+		wantErr bool
+	}{
+		{tag: "owner:team-a", want: client.Tag{Key: "owner", Value: "team-a"}},
+		{tag: "critical", want: client.Tag{Key: "critical"}},
+		{tag: "url:https://example.com", want: client.Tag{Key: "url", Value: "https://example.com"}},
+		{tag: ":team-a", wantErr: true},
+		{tag: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := ParseTag(tt.tag)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "invalid tag")
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	selector := `type("HOST"),tag("env:prod")`
+	tags := []client.Tag{{Key: "owner", Value: "team-a"}, {Key: "critical"}}
+
+	t.Run("tags are added", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().AddTags(gomock.Any(), selector, tags).Return(2, nil)
+
+		err := Apply(context.TODO(), c, selector, tags, false)
+		assert.NilError(t, err)
+	})
+
+	t.Run("dry-run only lists affected entities", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntityIds(gomock.Any(), selector).Return([]string{"HOST-1", "HOST-2"}, nil)
+
+		err := Apply(context.TODO(), c, selector, tags, true)
+		assert.NilError(t, err)
+	})
+}
+
+func TestRemove(t *testing.T) {
+	selector := `type("HOST")`
+	tags := []client.Tag{{Key: "owner", Value: "team-a"}, {Key: "critical"}}
+
+	t.Run("each tag is removed", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().DeleteTag(gomock.Any(), selector, tags[0]).Return(2, nil)
+		c.EXPECT().DeleteTag(gomock.Any(), selector, tags[1]).Return(2, nil)
+
+		err := Remove(context.TODO(), c, selector, tags, false)
+		assert.NilError(t, err)
+	})
+
+	t.Run("dry-run only lists affected entities", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntityIds(gomock.Any(), selector).Return([]string{"HOST-1"}, nil)
+
+		err := Remove(context.TODO(), c, selector, tags, true)
+		assert.NilError(t, err)
+	})
+}
@@ -0,0 +1,168 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/cmd/monaco/cmdutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func GetTemplatesCommand(fs afero.Fs) (templatesCmd *cobra.Command) {
+	var registry string
+
+	templatesCmd = &cobra.Command{
+		Use:   "templates",
+		Short: "Search and add sample projects of a template registry",
+		Long: `Search and add sample projects of a template registry
+
+  A template registry is a git repository or a local folder, holding one folder per template. The registry is given
+  by '--registry', or the environment variable '` + RegistryEnvKey + `'. Git repositories are given by their URL,
+  optionally followed by '#<branch or tag>', and are fetched using git.`,
+	}
+
+	templatesCmd.PersistentFlags().StringVar(&registry, "registry", "", "URL of the git repository or folder of the template registry. Defaults to the environment variable '"+RegistryEnvKey+"'")
+
+	templatesCmd.AddCommand(getSearchCommand(fs, &registry))
+	templatesCmd.AddCommand(getAddCommand(fs, &registry))
+
+	return templatesCmd
+}
+
+func getSearchCommand(fs afero.Fs, registry *string) *cobra.Command {
+	return &cobra.Command{
+		Use:     "search [<term>]",
+		Short:   "List the templates whose name, description or tags contain the given term",
+		Example: "monaco templates search dashboard --registry https://github.com/example/monaco-templates.git",
+		Args:    cobra.MaximumNArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := resolveRegistry(*registry)
+			if err != nil {
+				return err
+			}
+
+			term := ""
+			if len(args) == 1 {
+				term = args[0]
+			}
+
+			folder, done, err := Open(fs, r)
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			found, err := Search(fs, folder, term)
+			if err != nil {
+				return err
+			}
+			if len(found) == 0 {
+				log.Info("No template found")
+				return nil
+			}
+			for _, t := range found {
+				tags := ""
+				if len(t.Tags) > 0 {
+					tags = fmt.Sprintf(" [%s]", strings.Join(t.Tags, ", "))
+				}
+				log.Info("%s: %s%s", t.Name, t.Description, tags)
+			}
+			return nil
+		},
+	}
+}
+
+func getAddCommand(fs afero.Fs, registry *string) *cobra.Command {
+	var manifestName, project string
+
+	addCmd := &cobra.Command{
+		Use:   "add <template> --project <project>",
+		Short: "Copy a template into a project of a manifest",
+		Long: `Copy a template into a project of a manifest
+
+  The template is copied into the folder '<project>/<template>' and becomes part of the project. If the project is a
+  grouping project, the template becomes the project '<project>.<template>' of the group. References of the template
+  to its own configs, and its name in config names and values, are adjusted to the project.`,
+		Example: "monaco templates add host-dashboard --project infrastructure --registry https://github.com/example/monaco-templates.git",
+		Args:    cobra.ExactArgs(1),
+		PreRun:  cmdutils.SilenceUsageCommand(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := resolveRegistry(*registry)
+			if err != nil {
+				return err
+			}
+
+			target, projectName, err := resolveDestination(fs, manifestName, project, args[0])
+			if err != nil {
+				return err
+			}
+
+			folder, done, err := Open(fs, r)
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			return Add(fs, folder, args[0], target, projectName)
+		},
+	}
+
+	addCmd.Flags().StringVarP(&manifestName, "manifest", "m", "manifest.yaml", "Path to the manifest defining the projects")
+	addCmd.Flags().StringVarP(&project, "project", "p", "", "Name of the project of the manifest to add the template to")
+	if err := addCmd.MarkFlagFilename("manifest", files.YamlExtensions...); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+	if err := addCmd.MarkFlagRequired("project"); err != nil {
+		log.Fatal("failed to setup CLI %v", err)
+	}
+
+	return addCmd
+}
+
+// resolveDestination loads the given manifest and returns the folder to add the template to, and the name of the
+// project it becomes part of.
+func resolveDestination(fs afero.Fs, manifestPath, project, template string) (string, string, error) {
+	absManifestPath, err := filepath.Abs(manifestPath)
+	if err != nil {
+		return "", "", fmt.Errorf("error while finding absolute path for `%s`: %w", manifestPath, err)
+	}
+
+	m, err := cmdutils.LoadManifest(fs, absManifestPath, cmdutils.ManifestOptions{})
+	if err != nil {
+		return "", "", err
+	}
+
+	return Destination(m, filepath.Dir(absManifestPath), project, template)
+}
+
+func resolveRegistry(registry string) (string, error) {
+	if registry != "" {
+		return registry, nil
+	}
+	if r, found := os.LookupEnv(RegistryEnvKey); found && r != "" {
+		return r, nil
+	}
+	return "", errors.New("no template registry given, use '--registry' or the environment variable " + RegistryEnvKey)
+}
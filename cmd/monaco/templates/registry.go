@@ -0,0 +1,84 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+	"os/exec"
+	"strings"
+)
+
+// gitURLPrefixes are the prefixes of registries given by the URL of a git repository
+var gitURLPrefixes = []string{"https://", "http://", "ssh://", "git://", "file://", "git@"}
+
+// isGitRepository returns whether the given registry is the URL of a git repository instead of a local folder.
+func isGitRepository(registry string) bool {
+	for _, p := range gitURLPrefixes {
+		if strings.HasPrefix(registry, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneRepository clones the given git repository into the given folder. The URL may be followed by '#<ref>' to clone
+// a branch or tag other than the default branch.
+var cloneRepository = func(url, folder string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git must be installed to fetch template registries from git repositories")
+	}
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if u, ref, found := strings.Cut(url, "#"); found {
+		url = u
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, url, folder)
+
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Open returns the folder holding the given registry. Registries given by the URL of a git repository are cloned into
+// a temporary folder, which is removed by calling the returned function. Other registries are local folders.
+func Open(fs afero.Fs, registry string) (string, func(), error) {
+	if !isGitRepository(registry) {
+		return registry, func() {}, nil
+	}
+
+	folder, err := afero.TempDir(fs, "", "monaco-templates-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create folder for template registry: %w", err)
+	}
+	remove := func() {
+		if err := fs.RemoveAll(folder); err != nil {
+			log.Warn("Failed to remove temporary folder %q: %v", folder, err)
+		}
+	}
+
+	log.Info("Fetching template registry %q", registry)
+	if err := cloneRepository(registry, folder); err != nil {
+		remove()
+		return "", nil, fmt.Errorf("failed to fetch template registry %q: %w", registry, err)
+	}
+	return folder, remove, nil
+}
@@ -0,0 +1,199 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package templates provides access to a registry of sample projects, e.g. curated dashboards or alerting baselines.
+//
+// A registry is a git repository or a local folder. Each folder of the registry containing a 'template.yaml' file is a
+// template. All other files of the folder are a project, which references its own configs by the name of the folder.
+// The name of the folder may also be used in config names and values, e.g. to prefix names by the project.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/refactor"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RegistryEnvKey is the environment variable defining the registry if none is given explicitly
+const RegistryEnvKey = "MONACO_TEMPLATE_REGISTRY"
+
+// descriptorFile is the file marking a folder of the registry as template
+const descriptorFile = "template.yaml"
+
+// Template is a sample project of a registry.
+type Template struct {
+	// Name of the template, which is the name of its folder
+	Name string `yaml:"-"`
+	// Description of the template
+	Description string `yaml:"description"`
+	// Tags optionally categorize the template, e.g. 'dashboard' or 'alerting'
+	Tags []string `yaml:"tags,omitempty"`
+
+	path string
+}
+
+// matches returns whether the name, description or one of the tags of the template contains the given term,
+// ignoring case.
+func (t Template) matches(term string) bool {
+	term = strings.ToLower(term)
+	if strings.Contains(strings.ToLower(t.Name), term) || strings.Contains(strings.ToLower(t.Description), term) {
+		return true
+	}
+	for _, tag := range t.Tags {
+		if strings.Contains(strings.ToLower(tag), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns all templates of the given registry, sorted by name.
+func List(fs afero.Fs, registry string) ([]Template, error) {
+	entries, err := afero.ReadDir(fs, registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template registry %q: %w", registry, err)
+	}
+
+	var result []Template
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(registry, e.Name())
+		data, err := afero.ReadFile(fs, filepath.Join(path, descriptorFile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		t := Template{}
+		if err := yaml.UnmarshalStrict(data, &t); err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", filepath.Join(path, descriptorFile), err)
+		}
+		t.Name = e.Name()
+		t.path = path
+		result = append(result, t)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// Search returns the templates of the given registry matching the given term. All templates are returned for an
+// empty term.
+func Search(fs afero.Fs, registry string, term string) ([]Template, error) {
+	all, err := List(fs, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Template
+	for _, t := range all {
+		if t.matches(term) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// Destination returns the folder to add the template of the given name to, and the name of the project the template
+// becomes part of. Templates added to a project of the manifest are copied into the folder '<project>/<name>' and
+// become part of the project. Templates added to a grouping project are copied into the folder '<group>/<name>' and
+// become the project '<group>.<name>'.
+func Destination(m manifest.Manifest, manifestDir, project, name string) (string, string, error) {
+	if p, found := m.Projects[project]; found {
+		return filepath.Join(manifestDir, p.Path, name), p.Name, nil
+	}
+	for _, p := range m.Projects {
+		if p.Group == project {
+			return filepath.Join(manifestDir, filepath.Dir(p.Path), name), project + "." + name, nil
+		}
+	}
+	return "", "", fmt.Errorf("project %q is not defined in the manifest", project)
+}
+
+// Add copies the template of the given name into the given folder. References of the template to its own configs and
+// its name in config names and values are adjusted to the given project, see Destination.
+func Add(fs afero.Fs, registry string, name string, target string, project string) error {
+	all, err := List(fs, registry)
+	if err != nil {
+		return err
+	}
+
+	var template *Template
+	for i := range all {
+		if all[i].Name == name {
+			template = &all[i]
+			break
+		}
+	}
+	if template == nil {
+		return fmt.Errorf("template %q does not exist in registry %q", name, registry)
+	}
+
+	if exists, err := afero.Exists(fs, target); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("folder %q already exists", target)
+	}
+
+	if err := copyTemplate(fs, template.path, target); err != nil {
+		return fmt.Errorf("failed to copy template %q: %w", name, err)
+	}
+
+	if err := refactor.RenameProject(fs, target, name, project); err != nil {
+		return fmt.Errorf("failed to adjust template %q to project %q: %w", name, project, err)
+	}
+
+	log.Info("Added template %q to %q", name, target)
+	return nil
+}
+
+// copyTemplate copies all files of the template, except for its descriptor.
+func copyTemplate(fs afero.Fs, from, to string) error {
+	return afero.Walk(fs, from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fs.MkdirAll(filepath.Join(to, rel), 0777)
+		}
+		if rel == descriptorFile {
+			return nil
+		}
+
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+		return afero.WriteFile(fs, filepath.Join(to, rel), data, 0664)
+	})
+}
@@ -0,0 +1,155 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package templates
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+const hostDashboardConfig = `configs:
+- id: hosts
+  type: dashboard
+  config:
+    name: host-dashboard hosts
+    template: hosts.json
+    parameters:
+      zone: [host-dashboard, management-zone, hosts, id]
+`
+
+func setupRegistry(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	files := map[string]string{
+		"registry/host-dashboard/template.yaml":    "description: Overview of all hosts\ntags: [dashboard, infrastructure]\n",
+		"registry/host-dashboard/config.yaml":      hostDashboardConfig,
+		"registry/host-dashboard/hosts.json":       "{}",
+		"registry/alerting-baseline/template.yaml": "description: Default alerting profiles\ntags: [alerting]\n",
+		"registry/alerting-baseline/config.yaml":   "configs: []\n",
+		"registry/no-template/config.yaml":         "configs: []\n",
+		"registry/.git/config":                     "",
+	}
+	for path, content := range files {
+		assert.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+	}
+	return fs
+}
+
+func TestSearch(t *testing.T) {
+	fs := setupRegistry(t)
+
+	all, err := Search(fs, "registry", "")
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+	assert.Equal(t, "alerting-baseline", all[0].Name)
+	assert.Equal(t, "host-dashboard", all[1].Name)
+
+	found, err := Search(fs, "registry", "DASHBOARD")
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "Overview of all hosts", found[0].Description)
+
+	found, err = Search(fs, "registry", "alerting")
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+	assert.Equal(t, "alerting-baseline", found[0].Name)
+}
+
+func TestAdd(t *testing.T) {
+	fs := setupRegistry(t)
+
+	err := Add(fs, "registry", "host-dashboard", "projects/infrastructure/host-dashboard", "infrastructure")
+	assert.NoError(t, err)
+
+	exists, err := afero.Exists(fs, "projects/infrastructure/host-dashboard/template.yaml")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = afero.Exists(fs, "projects/infrastructure/host-dashboard/hosts.json")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	config, err := afero.ReadFile(fs, "projects/infrastructure/host-dashboard/config.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(config), "zone: [management-zone, hosts, id]")
+	assert.Contains(t, string(config), "name: infrastructure hosts")
+
+	err = Add(fs, "registry", "host-dashboard", "projects/infrastructure/host-dashboard", "infrastructure")
+	assert.ErrorContains(t, err, "already exists")
+
+	err = Add(fs, "registry", "unknown", "projects/infrastructure/unknown", "infrastructure")
+	assert.ErrorContains(t, err, "does not exist")
+}
+
+func TestDestination(t *testing.T) {
+	m := manifest.Manifest{
+		Projects: manifest.ProjectDefinitionByProjectID{
+			"infrastructure": {Name: "infrastructure", Path: "projects/infra"},
+			"teams.a":        {Name: "teams.a", Group: "teams", Path: filepath.Join("grouped", "a")},
+		},
+	}
+
+	folder, project, err := Destination(m, "/repo", "infrastructure", "host-dashboard")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("/repo", "projects", "infra", "host-dashboard"), folder)
+	assert.Equal(t, "infrastructure", project)
+
+	folder, project, err = Destination(m, "/repo", "teams", "host-dashboard")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("/repo", "grouped", "host-dashboard"), folder)
+	assert.Equal(t, "teams.host-dashboard", project)
+
+	_, _, err = Destination(m, "/repo", "unknown", "host-dashboard")
+	assert.ErrorContains(t, err, "not defined")
+}
+
+func TestOpen(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	clone := cloneRepository
+	defer func() { cloneRepository = clone }()
+
+	var cloned string
+	cloneRepository = func(url, folder string) error {
+		cloned = url
+		return afero.WriteFile(fs, filepath.Join(folder, "host-dashboard", "template.yaml"), []byte("description: Hosts\n"), 0644)
+	}
+
+	folder, done, err := Open(fs, "https://example.com/templates.git#v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/templates.git#v1", cloned)
+	found, err := Search(fs, folder, "")
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	done()
+	exists, err := afero.DirExists(fs, folder)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	cloneRepository = func(string, string) error { return errors.New("repository not found") }
+	_, _, err = Open(fs, "git@example.com:templates.git")
+	assert.ErrorContains(t, err, "repository not found")
+
+	folder, _, err = Open(fs, "./registry")
+	assert.NoError(t, err)
+	assert.Equal(t, "./registry", folder)
+}
@@ -25,6 +25,8 @@ import (
 
 const (
 	ConcurrentRequestsEnvKey = "MONACO_CONCURRENT_REQUESTS"
+	SettingsPageSizeEnvKey   = "MONACO_SETTINGS_PAGE_SIZE"
+	EntitiesPageSizeEnvKey   = "MONACO_ENTITIES_PAGE_SIZE"
 	defaultValueKey          = "DEFAULT"
 )
 
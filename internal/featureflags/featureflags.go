@@ -93,3 +93,29 @@ func VerifyEnvironmentType() FeatureFlag {
 		defaultEnabled: true,
 	}
 }
+
+// PaginationPrefetch returns the feature flag that tells whether the next page of paginated lists is requested while
+// the current page is processed
+func PaginationPrefetch() FeatureFlag {
+	return FeatureFlag{
+		envName:        "MONACO_FEAT_PAGINATION_PREFETCH",
+		defaultEnabled: true,
+	}
+}
+
+// RequestCompression returns the feature flag that tells whether large payloads are sent gzip compressed
+func RequestCompression() FeatureFlag {
+	return FeatureFlag{
+		envName:        "MONACO_FEAT_REQUEST_COMPRESSION",
+		defaultEnabled: false,
+	}
+}
+
+// AuditLogForwarding returns the feature flag that tells whether changes made to environments are recorded as log
+// records in the environments themselves
+func AuditLogForwarding() FeatureFlag {
+	return FeatureFlag{
+		envName:        "MONACO_AUDIT_LOG_FORWARD",
+		defaultEnabled: false,
+	}
+}
@@ -21,23 +21,26 @@ import (
 	"fmt"
 )
 
+// ExternalIDPrefix is the prefix of all externalIDs generated by GenerateExternalID. It identifies settings 2.0 objects
+// that were created by monaco.
+const ExternalIDPrefix = "monaco:"
+
 // GenerateExternalID generates the externalID for settings 2.0 objects based on the schema, and ID.
 // The result of the function is pure.
 // Max length for the external ID is 500
 func GenerateExternalID(schema, id string) string {
-	const prefix = "monaco:"
 	const format = "%s$%s"
 	const externalIDMaxLength = 500
 
 	formattedID := fmt.Sprintf(format, schema, id)
 	encodedID := base64.StdEncoding.EncodeToString([]byte(formattedID))
 
-	encodedIDMaxLength := externalIDMaxLength - len(prefix)
+	encodedIDMaxLength := externalIDMaxLength - len(ExternalIDPrefix)
 	if len(encodedID) > encodedIDMaxLength {
 		encodedID = encodedID[encodedIDMaxLength:]
 	}
 
-	externalID := fmt.Sprintf("monaco:%s", encodedID)
+	externalID := ExternalIDPrefix + encodedID
 
 	return externalID
 }
@@ -0,0 +1,71 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idutils
+
+import "strings"
+
+// ContainsId returns whether the given content contains the ID as a whole, not being part of a longer ID.
+func ContainsId(content, id string) bool {
+	found := false
+	forEachId(content, id, func(int) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// ReplaceId replaces all occurrences of the ID which are not part of a longer ID, and returns the number of
+// replacements.
+func ReplaceId(content, id, replacement string) (string, int) {
+	var b strings.Builder
+	count := 0
+	written := 0
+	forEachId(content, id, func(start int) bool {
+		b.WriteString(content[written:start])
+		b.WriteString(replacement)
+		written = start + len(id)
+		count++
+		return true
+	})
+	b.WriteString(content[written:])
+	return b.String(), count
+}
+
+// forEachId calls f with the start index of every occurrence of the ID in the content which is not part of a longer
+// ID, as long as f returns true.
+func forEachId(content, id string, f func(start int) bool) {
+	for offset := 0; ; {
+		i := strings.Index(content[offset:], id)
+		if i < 0 {
+			return
+		}
+		start := offset + i
+		end := start + len(id)
+		if (start == 0 || !isIdChar(content[start-1])) && (end == len(content) || !isIdChar(content[end])) {
+			if !f(start) {
+				return
+			}
+			offset = end
+			continue
+		}
+		offset = start + 1
+	}
+}
+
+func isIdChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '-' || b == '_'
+}
@@ -0,0 +1,36 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package idutils
+
+import (
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestContainsId(t *testing.T) {
+	assert.Assert(t, ContainsId(`{"id": "ID-1234"}`, "ID-1234"))
+	assert.Assert(t, ContainsId(`xID-1234, ID-1234`, "ID-1234"))
+	assert.Assert(t, !ContainsId(`{"id": "xID-1234", "other": "ID-1234-x"}`, "ID-1234"))
+}
+
+func TestReplaceId(t *testing.T) {
+	got, n := ReplaceId(`["ID-1234", "ID-1234", "xID-1234", ID-1234,ID-1234]`, "ID-1234", "{{ .id }}")
+	assert.Equal(t, `["{{ .id }}", "{{ .id }}", "xID-1234", {{ .id }},{{ .id }}]`, got)
+	assert.Equal(t, 4, n)
+}
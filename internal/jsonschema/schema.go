@@ -0,0 +1,154 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package jsonschema generates JSON Schemas from the Go structs monaco's YAML files are decoded into, and validates
+// YAML documents against them. Generating the schemas from the structs guarantees that they always match the code.
+//
+// Fields are named after their 'yaml' struct tag. Additionally, the 'jsonschema' struct tag defines further
+// constraints as comma-separated options:
+//   - required: the field must be defined
+//   - enum=a|b: the value of the field must be one of the given values
+package jsonschema
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Draft is the JSON Schema version of the generated schemas.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is the subset of JSON Schema used to describe monaco's YAML files.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type string        `json:"type,omitempty"`
+	Enum []interface{} `json:"enum,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is either a *Schema for the values of a map, or false for structs
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+
+	OneOf []*Schema `json:"oneOf,omitempty"`
+}
+
+// Customizer can be implemented by types which are decoded differently than their fields suggest, e.g. by a custom
+// unmarshal function, to replace the schema generated for them.
+type Customizer interface {
+	JSONSchema(generated *Schema) *Schema
+}
+
+// Generate returns the schema of the given type, titled with the given title.
+func Generate(t reflect.Type, title string) *Schema {
+	s := generate(t)
+	s.Schema = Draft
+	s.Title = title
+	return s
+}
+
+var customizerType = reflect.TypeOf((*Customizer)(nil)).Elem()
+
+func generate(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var s *Schema
+	switch t.Kind() {
+	case reflect.Struct:
+		s = generateStruct(t)
+	case reflect.Map:
+		s = &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = generate(t.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		s = &Schema{Type: "array", Items: generate(t.Elem())}
+	case reflect.String:
+		s = &Schema{Type: "string"}
+	case reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		s = &Schema{Type: "number"}
+	default:
+		// interfaces accept any value
+		s = &Schema{}
+	}
+
+	if t.Implements(customizerType) {
+		return reflect.Zero(t).Interface().(Customizer).JSONSchema(s)
+	}
+	return s
+}
+
+func generateStruct(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if strings.Contains(options, "inline") {
+			inlined := generate(f.Type)
+			for n, p := range inlined.Properties {
+				s.Properties[n] = p
+			}
+			s.Required = append(s.Required, inlined.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		property := generate(f.Type)
+		for _, option := range strings.Split(f.Tag.Get("jsonschema"), ",") {
+			switch {
+			case option == "required":
+				s.Required = append(s.Required, name)
+			case strings.HasPrefix(option, "enum="):
+				for _, v := range strings.Split(strings.TrimPrefix(option, "enum="), "|") {
+					property.Enum = append(property.Enum, v)
+				}
+			}
+		}
+		s.Properties[name] = property
+	}
+
+	sort.Strings(s.Required)
+	return s
+}
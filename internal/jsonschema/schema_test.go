@@ -0,0 +1,94 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type testFlexible struct {
+	Name string `yaml:"name"`
+}
+
+func (testFlexible) JSONSchema(generated *Schema) *Schema {
+	return &Schema{OneOf: []*Schema{{Type: "string"}, generated}}
+}
+
+type testEmbedded struct {
+	Inlined string `yaml:"inlined" jsonschema:"required"`
+}
+
+type testDocument struct {
+	testEmbedded `yaml:",inline"`
+	Id           string                 `yaml:"id" jsonschema:"required"`
+	Kind         string                 `yaml:"kind,omitempty" jsonschema:"enum=a|b"`
+	Count        int                    `yaml:"count"`
+	Ratio        float64                `yaml:"ratio"`
+	Enabled      *bool                  `yaml:"enabled,omitempty"`
+	Labels       map[string]string      `yaml:"labels"`
+	Values       map[string]interface{} `yaml:"values"`
+	Items        []testFlexible         `yaml:"items"`
+	Ignored      string                 `yaml:"-"`
+	Untagged     string
+	unexported   string
+}
+
+func TestGenerate(t *testing.T) {
+	got := Generate(reflect.TypeOf(testDocument{}), "test")
+
+	assert.Equal(t, &Schema{
+		Schema:               Draft,
+		Title:                "test",
+		Type:                 "object",
+		Required:             []string{"id", "inlined"},
+		AdditionalProperties: false,
+		Properties: map[string]*Schema{
+			"inlined": {Type: "string"},
+			"id":      {Type: "string"},
+			"kind":    {Type: "string", Enum: []interface{}{"a", "b"}},
+			"count":   {Type: "integer"},
+			"ratio":   {Type: "number"},
+			"enabled": {Type: "boolean"},
+			"labels":  {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+			"values":  {Type: "object"},
+			"items": {Type: "array", Items: &Schema{OneOf: []*Schema{
+				{Type: "string"},
+				{Type: "object", Properties: map[string]*Schema{"name": {Type: "string"}}, AdditionalProperties: false},
+			}}},
+			"untagged": {Type: "string"},
+		},
+	}, got)
+}
+
+func TestGenerate_SerializesClosedObjects(t *testing.T) {
+	data, err := json.Marshal(Generate(reflect.TypeOf(testEmbedded{}), "test"))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title": "test",
+		"type": "object",
+		"properties": {"inlined": {"type": "string"}},
+		"required": ["inlined"],
+		"additionalProperties": false
+	}`, string(data))
+}
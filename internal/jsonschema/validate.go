@@ -0,0 +1,204 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"fmt"
+	monacoStrings "github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// ValidationError is a violation of a schema by an element of a YAML document.
+type ValidationError struct {
+	// Path is the dot-separated path of the element violating the schema, empty for the document root
+	Path string
+	// Position is the location of the element in the document
+	Position yamlnode.Position
+	// Reason describes the violation
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "<root>"
+	}
+	return fmt.Sprintf("%s (line %d, column %d): %s", path, e.Position.Line, e.Position.Column, e.Reason)
+}
+
+// Validate validates the given YAML document against the given schema and returns all violations. As YAML decoding
+// turns any scalar into a string if a string is expected, all scalars are accepted for string types. Null values are
+// accepted for any type, as they are decoded into the type's zero value.
+func Validate(data []byte, s *Schema) []ValidationError {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []ValidationError{{Reason: err.Error()}}
+	}
+
+	if len(doc.Content) == 0 {
+		return []ValidationError{{Position: yamlnode.Position{Line: 1, Column: 1}, Reason: "document is empty"}}
+	}
+
+	return validate(doc.Content[0], s, "")
+}
+
+func validate(n *yaml.Node, s *Schema, path string) []ValidationError {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+
+	if isNull(n) {
+		return nil
+	}
+
+	if len(s.OneOf) > 0 {
+		var types []string
+		for _, option := range s.OneOf {
+			if matchesType(n, option.Type) {
+				return validate(n, option, path)
+			}
+			types = append(types, option.Type)
+		}
+		return []ValidationError{newValidationError(n, path, "must be of one of the types %s", strings.Join(types, ", "))}
+	}
+
+	if !matchesType(n, s.Type) {
+		return []ValidationError{newValidationError(n, path, "must be of type %s", s.Type)}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(n.Value, s.Enum) {
+		return []ValidationError{newValidationError(n, path, "must be one of %v, but is %q", s.Enum, n.Value)}
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		return validateMapping(n, s, path)
+	case yaml.SequenceNode:
+		if s.Items == nil {
+			return nil
+		}
+		var errs []ValidationError
+		for i, item := range n.Content {
+			errs = append(errs, validate(item, s.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	}
+
+	return nil
+}
+
+func validateMapping(n *yaml.Node, s *Schema, path string) []ValidationError {
+	var errs []ValidationError
+
+	defined := make(map[string]struct{}, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		defined[key.Value] = struct{}{}
+
+		if key.Value == "<<" {
+			continue
+		}
+
+		if p, found := s.Properties[key.Value]; found {
+			errs = append(errs, validate(value, p, join(path, key.Value))...)
+			continue
+		}
+
+		switch additional := s.AdditionalProperties.(type) {
+		case *Schema:
+			errs = append(errs, validate(value, additional, join(path, key.Value))...)
+		case bool:
+			if additional {
+				continue
+			}
+			reason := fmt.Sprintf("unknown property %q", key.Value)
+			if suggestion, found := monacoStrings.ClosestMatch(key.Value, propertyNames(s)); found {
+				reason += fmt.Sprintf(" - did you mean %q?", suggestion)
+			}
+			errs = append(errs, newValidationError(key, path, "%s", reason))
+		}
+	}
+
+	for _, r := range s.Required {
+		if _, found := defined[r]; !found {
+			errs = append(errs, newValidationError(n, path, "missing required property %q", r))
+		}
+	}
+
+	return errs
+}
+
+func matchesType(n *yaml.Node, t string) bool {
+	switch t {
+	case "":
+		return true
+	case "object":
+		return n.Kind == yaml.MappingNode
+	case "array":
+		return n.Kind == yaml.SequenceNode
+	case "string":
+		return n.Kind == yaml.ScalarNode
+	case "boolean":
+		return n.Kind == yaml.ScalarNode && n.Tag == "!!bool"
+	case "integer":
+		return n.Kind == yaml.ScalarNode && n.Tag == "!!int"
+	case "number":
+		return n.Kind == yaml.ScalarNode && (n.Tag == "!!int" || n.Tag == "!!float")
+	default:
+		return false
+	}
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+func inEnum(v string, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == v {
+			return true
+		}
+	}
+	return false
+}
+
+func propertyNames(s *Schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newValidationError(n *yaml.Node, path string, format string, args ...interface{}) ValidationError {
+	return ValidationError{
+		Path:     path,
+		Position: yamlnode.PositionOf(n),
+		Reason:   fmt.Sprintf(format, args...),
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
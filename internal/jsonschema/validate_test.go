@@ -0,0 +1,114 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonschema
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	schema := Generate(reflect.TypeOf(testDocument{}), "test")
+
+	tests := []struct {
+		name     string
+		document string
+		want     []ValidationError
+	}{
+		{
+			name: "valid document",
+			document: `id: a
+inlined: b
+kind: a
+count: 1
+ratio: 0.5
+enabled: true
+labels: {x: y, z: 1}
+values: {anything: [1, {2: 3}]}
+items:
+- just a name
+- name: x
+`,
+		},
+		{
+			name:     "scalars are accepted as strings and nulls for all types",
+			document: "id: 1\ninlined: true\ncount:\n",
+		},
+		{
+			name:     "missing required properties",
+			document: "kind: a\n",
+			want: []ValidationError{
+				{Position: yamlnode.Position{Line: 1, Column: 1}, Reason: `missing required property "id"`},
+				{Position: yamlnode.Position{Line: 1, Column: 1}, Reason: `missing required property "inlined"`},
+			},
+		},
+		{
+			name:     "unknown properties with suggestion",
+			document: "id: a\ninlined: b\ncont: 1\nitems: [{nmae: x}]\n",
+			want: []ValidationError{
+				{Position: yamlnode.Position{Line: 3, Column: 1}, Reason: `unknown property "cont" - did you mean "count"?`},
+				{Path: "items[0]", Position: yamlnode.Position{Line: 4, Column: 10}, Reason: `unknown property "nmae" - did you mean "name"?`},
+			},
+		},
+		{
+			name:     "type mismatches",
+			document: "id: a\ninlined: b\ncount: many\nratio: [1]\nlabels: {a: [b]}\nitems: [[]]\n",
+			want: []ValidationError{
+				{Path: "count", Position: yamlnode.Position{Line: 3, Column: 8}, Reason: "must be of type integer"},
+				{Path: "ratio", Position: yamlnode.Position{Line: 4, Column: 8}, Reason: "must be of type number"},
+				{Path: "labels.a", Position: yamlnode.Position{Line: 5, Column: 13}, Reason: "must be of type string"},
+				{Path: "items[0]", Position: yamlnode.Position{Line: 6, Column: 9}, Reason: "must be of one of the types string, object"},
+			},
+		},
+		{
+			name:     "enum violation",
+			document: "id: a\ninlined: b\nkind: c\n",
+			want: []ValidationError{
+				{Path: "kind", Position: yamlnode.Position{Line: 3, Column: 7}, Reason: `must be one of [a b], but is "c"`},
+			},
+		},
+		{
+			name:     "empty document",
+			document: "",
+			want:     []ValidationError{{Position: yamlnode.Position{Line: 1, Column: 1}, Reason: "document is empty"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Validate([]byte(tt.document), schema))
+		})
+	}
+}
+
+func TestValidate_InvalidYAML(t *testing.T) {
+	errs := Validate([]byte("a: ["), Generate(reflect.TypeOf(testDocument{}), "test"))
+
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "yaml:")
+}
+
+func TestValidationError_Error(t *testing.T) {
+	assert.Equal(t, "items[0].name (line 3, column 5): must be of type string",
+		ValidationError{Path: "items[0].name", Position: yamlnode.Position{Line: 3, Column: 5}, Reason: "must be of type string"}.Error())
+	assert.Equal(t, "<root> (line 1, column 1): missing required property \"id\"",
+		ValidationError{Position: yamlnode.Position{Line: 1, Column: 1}, Reason: "missing required property \"id\""}.Error())
+}
@@ -0,0 +1,111 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Field is contextual information attached to log messages, e.g. the coordinate of the config being processed.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// CoordinateField returns a Field holding the coordinate of the config being processed
+func CoordinateField(coordinate fmt.Stringer) Field {
+	return Field{Key: "coordinate", Value: coordinate.String()}
+}
+
+// EnvironmentField returns a Field holding the environment being processed
+func EnvironmentField(environment string) Field {
+	return Field{Key: "environment", Value: environment}
+}
+
+// TypeField returns a Field holding the type - e.g. API or settings schema - of the configs being processed
+func TypeField(typ string) Field {
+	return Field{Key: "type", Value: typ}
+}
+
+type fieldsKey struct{}
+
+// WithFields returns a context carrying the given fields in addition to those already attached to ctx. A field
+// replaces an already attached field of the same key. Messages logged via WithCtxFields for the returned context are
+// prefixed with all attached fields.
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	existing, _ := ctx.Value(fieldsKey{}).([]Field)
+	combined := make([]Field, 0, len(existing)+len(fields))
+	combined = append(combined, existing...)
+
+	for _, f := range fields {
+		replaced := false
+		for i := range combined {
+			if combined[i].Key == f.Key {
+				combined[i] = f
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			combined = append(combined, f)
+		}
+	}
+	return context.WithValue(ctx, fieldsKey{}, combined)
+}
+
+// ContextLogger logs messages to the default logger, prefixed with the fields attached to a context.
+type ContextLogger struct {
+	prefix string
+}
+
+// WithCtxFields returns a logger prefixing all messages with the fields attached to the given context via WithFields,
+// e.g. "[environment=dev coordinate=project:api:id] message". This keeps messages traceable when configs are processed
+// concurrently.
+func WithCtxFields(ctx context.Context) ContextLogger {
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+	if len(fields) == 0 {
+		return ContextLogger{}
+	}
+
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Key + "=" + f.Value
+	}
+	return ContextLogger{prefix: "[" + strings.Join(parts, " ") + "] "}
+}
+
+// Error logs the message with the prefix ERROR.
+func (l ContextLogger) Error(msg string, a ...interface{}) {
+	doLog(defaultLogger, LevelError, "%s%s", l.prefix, fmt.Sprintf(msg, a...))
+}
+
+// Warn logs the message with the prefix WARN.
+func (l ContextLogger) Warn(msg string, a ...interface{}) {
+	doLog(defaultLogger, LevelWarn, "%s%s", l.prefix, fmt.Sprintf(msg, a...))
+}
+
+// Info logs the message with the prefix INFO.
+func (l ContextLogger) Info(msg string, a ...interface{}) {
+	doLog(defaultLogger, LevelInfo, "%s%s", l.prefix, fmt.Sprintf(msg, a...))
+}
+
+// Debug logs the message with the prefix DEBUG.
+func (l ContextLogger) Debug(msg string, a ...interface{}) {
+	doLog(defaultLogger, LevelDebug, "%s%s", l.prefix, fmt.Sprintf(msg, a...))
+}
@@ -0,0 +1,69 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCoordinate string
+
+func (c testCoordinate) String() string { return string(c) }
+
+func captureConsole(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	previous := defaultLogger.consoleLogger
+	defaultLogger.consoleLogger = log.New(buf, "", 0)
+	t.Cleanup(func() { defaultLogger.consoleLogger = previous })
+	return buf
+}
+
+func TestWithCtxFields(t *testing.T) {
+	buf := captureConsole(t)
+
+	ctx := WithFields(context.TODO(), EnvironmentField("dev"))
+	ctx = WithFields(ctx, CoordinateField(testCoordinate("project:api:id")))
+	WithCtxFields(ctx).Info("deploying %s", "config")
+
+	assert.Equal(t, "INFO  [environment=dev coordinate=project:api:id] deploying config\n", buf.String())
+}
+
+func TestWithCtxFields_withoutFields(t *testing.T) {
+	buf := captureConsole(t)
+
+	WithCtxFields(context.TODO()).Error("something %d%%", 100)
+
+	assert.Equal(t, "ERROR something 100%\n", buf.String())
+}
+
+func TestWithFields_replacesFieldsOfSameKey(t *testing.T) {
+	buf := captureConsole(t)
+
+	ctx := WithFields(context.TODO(), EnvironmentField("dev"), TypeField("a"))
+	WithFields(ctx, TypeField("other")) // must not change ctx
+	ctx = WithFields(ctx, TypeField("b"))
+	WithCtxFields(ctx).Info("msg")
+
+	assert.Equal(t, "INFO  [environment=dev type=b] msg\n", buf.String())
+}
@@ -22,6 +22,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -66,6 +67,10 @@ type extendedLogger struct {
 	fileLogger       *log.Logger
 	additionalLogger *log.Logger
 	level            logLevel
+
+	// warnings holds the messages of all warnings logged since the last ResetWarnings
+	warnings      []string
+	warningsMutex sync.Mutex
 }
 
 // New creates a new extendedLogger, which contains two
@@ -199,8 +204,30 @@ func Debug(msg string, a ...interface{}) {
 	defaultLogger.Debug(msg, a...)
 }
 
+// Warnings returns the messages of all warnings logged to the default logger since the last call of ResetWarnings, in
+// the order they were logged.
+func Warnings() []string {
+	defaultLogger.warningsMutex.Lock()
+	defer defaultLogger.warningsMutex.Unlock()
+	return append([]string(nil), defaultLogger.warnings...)
+}
+
+// ResetWarnings discards all warnings collected by the default logger.
+func ResetWarnings() {
+	defaultLogger.warningsMutex.Lock()
+	defer defaultLogger.warningsMutex.Unlock()
+	defaultLogger.warnings = nil
+}
+
 func doLog(logger *extendedLogger, level logLevel, msg string, a ...interface{}) {
-	msg = fmt.Sprintf(level.prefix()+msg, a...)
+	msg = fmt.Sprintf(msg, a...)
+	if level == LevelWarn {
+		logger.warningsMutex.Lock()
+		logger.warnings = append(logger.warnings, msg)
+		logger.warningsMutex.Unlock()
+	}
+
+	msg = level.prefix() + msg
 	if logger.level >= level && logger.consoleLogger != nil {
 		logger.consoleLogger.Println(msg)
 	}
@@ -194,3 +194,18 @@ func touch(t *testing.T, fs afero.Fs, path string, perm os.FileMode) {
 
 	chmod(t, fs, path, perm)
 }
+
+func TestWarnings_collectsOnlyWarnings(t *testing.T) {
+	ResetWarnings()
+	defer ResetWarnings()
+
+	Info("some info")
+	Warn("first %s", "warning")
+	Error("some error")
+	Warn("second warning")
+
+	assert.Equal(t, []string{"first warning", "second warning"}, Warnings())
+
+	ResetWarnings()
+	assert.Empty(t, Warnings())
+}
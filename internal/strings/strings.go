@@ -21,3 +21,50 @@ import "fmt"
 func ToString(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
+
+// ClosestMatch returns the candidate with the smallest edit distance to s. Candidates requiring more edits than half of
+// the length of s are not considered similar, in which case false is returned.
+func ClosestMatch(s string, candidates []string) (string, bool) {
+	best, bestDistance := "", len(s)/2+1
+	for _, c := range candidates {
+		if d := editDistance(s, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance returns the Levenshtein distance of a and b, i.e. the number of single character insertions, deletions
+// or substitutions needed to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+
+	return previous[len(rb)]
+}
+
+func min(first int, others ...int) int {
+	m := first
+	for _, o := range others {
+		if o < m {
+			m = o
+		}
+	}
+	return m
+}
@@ -0,0 +1,56 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package strings
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"environmentOverrides", "groupOverrides", "config", "type"}
+
+	tests := []struct {
+		given     string
+		wantMatch string
+		wantFound bool
+	}{
+		{"enviromentOverrides", "environmentOverrides", true},
+		{"groupOverride", "groupOverrides", true},
+		{"typ", "type", true},
+		{"configs", "config", true},
+		{"something", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			match, found := ClosestMatch(tt.given, candidates)
+			assert.Equal(t, tt.wantMatch, match)
+			assert.Equal(t, tt.wantFound, found)
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("abc", "abc"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 1, editDistance("abc", "abd"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
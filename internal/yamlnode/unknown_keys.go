@@ -0,0 +1,156 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package yamlnode
+
+import (
+	"fmt"
+	monacoStrings "github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownKey is a key of a YAML mapping, which does not match any field of the struct the mapping is decoded into.
+type UnknownKey struct {
+	// Key is the unrecognized key
+	Key string
+	// Path is the dot-separated path of the mapping containing the key, empty for the top-level mapping
+	Path string
+	// Position is the location of the key in the document
+	Position Position
+	// Suggestion is the most similar valid key of the mapping, if there is any
+	Suggestion string
+}
+
+func (k UnknownKey) String() string {
+	key := k.Key
+	if k.Path != "" {
+		key = k.Path + "." + k.Key
+	}
+
+	s := fmt.Sprintf("unknown key %q (line %d, column %d)", key, k.Position.Line, k.Position.Column)
+	if k.Suggestion != "" {
+		s += fmt.Sprintf(" - did you mean %q?", k.Suggestion)
+	}
+	return s
+}
+
+// UnknownKeys returns all keys of mappings in the given node tree which would not be decoded into a field of the given
+// type, following the struct's 'yaml' tags. Maps, slices and pointers are descended into, while the values of
+// interface-typed fields are not checked.
+func UnknownKeys(n *yaml.Node, t reflect.Type) []UnknownKey {
+	return unknownKeys(n, t, "")
+}
+
+func unknownKeys(n *yaml.Node, t reflect.Type, path string) []UnknownKey {
+	if n == nil {
+		return nil
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var result []UnknownKey
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				result = append(result, unknownKeys(value, t, path)...)
+				continue
+			}
+
+			if f, found := fields[key.Value]; found {
+				result = append(result, unknownKeys(value, f, join(path, key.Value))...)
+				continue
+			}
+
+			suggestion, _ := monacoStrings.ClosestMatch(key.Value, sortedKeys(fields))
+			result = append(result, UnknownKey{Key: key.Value, Path: path, Position: PositionOf(key), Suggestion: suggestion})
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i, item := range Items(n) {
+			result = append(result, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			result = append(result, unknownKeys(n.Content[i+1], t.Elem(), join(path, n.Content[i].Value))...)
+		}
+	}
+
+	return result
+}
+
+// yamlFields returns the types of the fields of the given struct type, keyed by the name they are decoded from.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if strings.Contains(options, "inline") {
+			for n, inlined := range yamlFields(f.Type) {
+				fields[n] = inlined
+			}
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func sortedKeys(m map[string]reflect.Type) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,80 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package yamlnode
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+type testOverride struct {
+	Environment string     `yaml:"environment"`
+	Override    testConfig `yaml:"override"`
+}
+
+type testConfig struct {
+	Name       string                 `yaml:"name"`
+	Parameters map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+type testDefinition struct {
+	Id                   string         `yaml:"id"`
+	Config               testConfig     `yaml:"config"`
+	EnvironmentOverrides []testOverride `yaml:"environmentOverrides"`
+	Ignored              string         `yaml:"-"`
+	Untagged             string
+}
+
+func TestUnknownKeys(t *testing.T) {
+	root := Parse([]byte(`id: a
+config:
+  name: b
+  parameters:
+    anything: {goes: here}
+enviromentOverrides: []
+environmentOverrides:
+- environment: e
+  override:
+    nmae: c
+untagged: d
+completelyDifferent: true
+`))
+
+	got := UnknownKeys(root, reflect.TypeOf(testDefinition{}))
+
+	assert.Equal(t, []UnknownKey{
+		{Key: "enviromentOverrides", Position: Position{Line: 6, Column: 1}, Suggestion: "environmentOverrides"},
+		{Key: "nmae", Path: "environmentOverrides[0].override", Position: Position{Line: 10, Column: 5}, Suggestion: "name"},
+		{Key: "completelyDifferent", Position: Position{Line: 12, Column: 1}},
+	}, got)
+}
+
+func TestUnknownKeys_IgnoresMismatchingNodes(t *testing.T) {
+	root := Parse([]byte("id: a\nconfig: just a string\nenvironmentOverrides: {not: a list}\n"))
+
+	assert.Empty(t, UnknownKeys(root, reflect.TypeOf(&testDefinition{})))
+}
+
+func TestUnknownKey_String(t *testing.T) {
+	assert.Equal(t, `unknown key "config.nmae" (line 3, column 5) - did you mean "name"?`,
+		UnknownKey{Key: "nmae", Path: "config", Position: Position{Line: 3, Column: 5}, Suggestion: "name"}.String())
+	assert.Equal(t, `unknown key "other" (line 1, column 1)`,
+		UnknownKey{Key: "other", Position: Position{Line: 1, Column: 1}}.String())
+}
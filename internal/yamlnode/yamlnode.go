@@ -0,0 +1,165 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package yamlnode provides helpers to look up the positions of elements in YAML documents. While the actual decoding
+// of monaco's YAML files is done into structs, the node tree of a document preserves the line and column of each
+// element, which allows to point users to the exact location of an error.
+package yamlnode
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+)
+
+// Node is a node of a parsed YAML document.
+type Node = yaml.Node
+
+// Position is the location of an element in a YAML document. Line and column start at 1 - the zero value denotes an
+// unknown position.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// Known returns whether the position points to an actual location in a document.
+func (p Position) Known() bool {
+	return p.Line > 0
+}
+
+// String formats the position as 'line:column'.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Format returns the given file path, suffixed with the position if it is known.
+func (p Position) Format(path string) string {
+	if !p.Known() {
+		return path
+	}
+	return fmt.Sprintf("%s:%s", path, p)
+}
+
+// Parse parses the given data into a node tree and returns its root node. If the data can not be parsed, nil is
+// returned, as reporting syntax errors is left to the actual decoding of the data.
+func Parse(data []byte) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+// PositionOf returns the position of the given node. For nil nodes, an unknown position is returned.
+func PositionOf(n *yaml.Node) Position {
+	if n == nil {
+		return Position{}
+	}
+	return Position{Line: n.Line, Column: n.Column}
+}
+
+// Get returns the node found by following the given keys through nested mappings, or nil if any of them does not
+// exist.
+func Get(n *yaml.Node, keys ...string) *yaml.Node {
+	for _, key := range keys {
+		n = value(n, key)
+	}
+	return n
+}
+
+// Items returns the items of the given node, if it is a sequence.
+func Items(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// Keys returns the key nodes of the given node, if it is a mapping.
+func Keys(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	keys := make([]*yaml.Node, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keys = append(keys, n.Content[i])
+	}
+	return keys
+}
+
+// ScalarValue returns the value of the given key of a mapping node, if it is a scalar. Otherwise, an empty string is
+// returned.
+func ScalarValue(n *yaml.Node, key string) string {
+	v := value(n, key)
+	if v == nil || v.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return v.Value
+}
+
+func value(n *yaml.Node, key string) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Set sets the value of the given key of a mapping node, appending the key if it is not defined yet.
+func Set(n *yaml.Node, key string, v *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content[i+1] = v
+			return
+		}
+	}
+	n.Content = append(n.Content, Scalar(key), v)
+}
+
+// Delete removes the given key from a mapping node, if it is defined.
+func Delete(n *yaml.Node, key string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// Scalar returns a new node holding the given string.
+func Scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// Sequence returns a new sequence node holding the given strings in flow style, e.g. '[a, b]'.
+func Sequence(values ...string) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	for _, v := range values {
+		n.Content = append(n.Content, Scalar(v))
+	}
+	return n
+}
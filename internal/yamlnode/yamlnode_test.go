@@ -0,0 +1,86 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package yamlnode
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testDocument = `configs:
+- id: first
+  config:
+    name: a
+- id: second
+  config:
+    parameters:
+      threshold: 5
+`
+
+func TestParse_InvalidYAMLReturnsNil(t *testing.T) {
+	assert.Nil(t, Parse([]byte("a: [")))
+	assert.Nil(t, Parse([]byte("")))
+}
+
+func TestPositions(t *testing.T) {
+	root := Parse([]byte(testDocument))
+
+	items := Items(Get(root, "configs"))
+	assert.Len(t, items, 2)
+
+	assert.Equal(t, "first", ScalarValue(items[0], "id"))
+	assert.Equal(t, Position{Line: 2, Column: 3}, PositionOf(items[0]))
+	assert.Equal(t, "second", ScalarValue(items[1], "id"))
+	assert.Equal(t, Position{Line: 5, Column: 3}, PositionOf(items[1]))
+
+	keys := Keys(Get(items[1], "config", "parameters"))
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "threshold", keys[0].Value)
+	assert.Equal(t, Position{Line: 8, Column: 7}, PositionOf(keys[0]))
+}
+
+func TestMissingElements(t *testing.T) {
+	root := Parse([]byte(testDocument))
+
+	assert.Nil(t, Get(root, "configs", "id"))
+	assert.Nil(t, Get(nil, "configs"))
+	assert.Empty(t, Items(Get(root, "unknown")))
+	assert.Empty(t, Keys(Get(root, "configs")))
+	assert.Equal(t, "", ScalarValue(root, "configs"))
+	assert.False(t, PositionOf(nil).Known())
+}
+
+func TestPosition_Format(t *testing.T) {
+	assert.Equal(t, "config.yaml", Position{}.Format("config.yaml"))
+	assert.Equal(t, "config.yaml:12:5", Position{Line: 12, Column: 5}.Format("config.yaml"))
+}
+
+func TestSetAndDelete(t *testing.T) {
+	root := Parse([]byte("a: 1\nb: 2\n"))
+
+	Set(root, "a", Scalar("x"))
+	Set(root, "c", Sequence("y", "z"))
+	Delete(root, "b")
+	Delete(root, "unknown")
+
+	assert.Equal(t, "x", ScalarValue(root, "a"))
+	assert.Nil(t, Get(root, "b"))
+	assert.Len(t, Items(Get(root, "c")), 2)
+	assert.Len(t, Keys(root), 2)
+}
@@ -0,0 +1,80 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package account manages the users, groups and policies of Dynatrace accounts via the account management API.
+//
+// Account resources are defined in YAML files of account projects, referencing each other by name:
+//
+//	policies:
+//	- name: read-settings
+//	  description: Allows to read settings
+//	  statement: ALLOW settings:objects:read;
+//	groups:
+//	- name: settings-readers
+//	  permissions:
+//	  - name: tenant-viewer
+//	    scope: abc12345
+//	    scopeType: tenant
+//	  policies: [read-settings, Standard User]
+//	users:
+//	- email: jane.doe@example.com
+//	  groups: [settings-readers]
+//
+// Policies are defined on account level. Groups may reference policies of the account, as well as global policies
+// provided by Dynatrace (e.g. 'Standard User').
+package account
+
+// Resources holds all users, groups and policies of an account.
+type Resources struct {
+	Policies []Policy `yaml:"policies,omitempty"`
+	Groups   []Group  `yaml:"groups,omitempty"`
+	Users    []User   `yaml:"users,omitempty"`
+}
+
+// Policy is an account level policy, identified by its name.
+type Policy struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	// Statement is the policy statement, e.g. 'ALLOW settings:objects:read;'
+	Statement string `yaml:"statement"`
+}
+
+// Group is a user group, identified by its name.
+type Group struct {
+	Name                     string       `yaml:"name"`
+	Description              string       `yaml:"description,omitempty"`
+	FederatedAttributeValues []string     `yaml:"federatedAttributeValues,omitempty"`
+	Permissions              []Permission `yaml:"permissions,omitempty"`
+	// Policies holds the names of all account or global policies bound to the group on account level
+	Policies []string `yaml:"policies,omitempty"`
+}
+
+// Permission is a permission granted to a group.
+type Permission struct {
+	// Name of the permission, e.g. 'tenant-viewer'
+	Name string `yaml:"name"`
+	// Scope the permission is granted for, e.g. an environment id
+	Scope string `yaml:"scope"`
+	// ScopeType of the scope, e.g. 'account', 'tenant' or 'management-zone'
+	ScopeType string `yaml:"scopeType"`
+}
+
+// User is a user of an account, identified by their email address.
+type User struct {
+	Email string `yaml:"email"`
+	// Groups holds the names of all groups the user is a member of
+	Groups []string `yaml:"groups,omitempty"`
+}
@@ -0,0 +1,271 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+// fakeClient is an in-memory account, keyed by UUID.
+type fakeClient struct {
+	globalPolicies map[string]RemotePolicy
+	policies       map[string]RemotePolicy
+	groups         map[string]RemoteGroup
+	bindings       map[string][]string
+	userGroups     map[string][]string
+	failingGroups  map[string]bool
+	nextId         int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		globalPolicies: map[string]RemotePolicy{"global-1": {UUID: "global-1", Policy: Policy{Name: "Standard User"}}},
+		policies:       map[string]RemotePolicy{},
+		groups:         map[string]RemoteGroup{},
+		bindings:       map[string][]string{},
+		userGroups:     map[string][]string{},
+		failingGroups:  map[string]bool{},
+	}
+}
+
+func (f *fakeClient) newId() string {
+	f.nextId++
+	return fmt.Sprintf("uuid-%d", f.nextId)
+}
+
+func (f *fakeClient) ListPolicies(context.Context) ([]RemotePolicy, error) {
+	var result []RemotePolicy
+	for _, p := range f.policies {
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func (f *fakeClient) ListGlobalPolicies(context.Context) ([]RemotePolicy, error) {
+	var result []RemotePolicy
+	for _, p := range f.globalPolicies {
+		result = append(result, RemotePolicy{UUID: p.UUID, Policy: Policy{Name: p.Name}})
+	}
+	return result, nil
+}
+
+func (f *fakeClient) CreatePolicy(_ context.Context, p Policy) (string, error) {
+	id := f.newId()
+	f.policies[id] = RemotePolicy{UUID: id, Policy: p}
+	return id, nil
+}
+
+func (f *fakeClient) UpdatePolicy(_ context.Context, uuid string, p Policy) error {
+	f.policies[uuid] = RemotePolicy{UUID: uuid, Policy: p}
+	return nil
+}
+
+func (f *fakeClient) ListGroups(context.Context) ([]RemoteGroup, error) {
+	var result []RemoteGroup
+	for _, g := range f.groups {
+		result = append(result, RemoteGroup{UUID: g.UUID, Owner: g.Owner, Group: Group{Name: g.Name, Description: g.Description, FederatedAttributeValues: g.FederatedAttributeValues}})
+	}
+	return result, nil
+}
+
+func (f *fakeClient) CreateGroup(_ context.Context, g Group) (string, error) {
+	if f.failingGroups[g.Name] {
+		return "", fmt.Errorf("group creation failed")
+	}
+	id := f.newId()
+	f.groups[id] = RemoteGroup{UUID: id, Owner: "LOCAL", Group: Group{Name: g.Name, Description: g.Description, FederatedAttributeValues: g.FederatedAttributeValues}}
+	return id, nil
+}
+
+func (f *fakeClient) UpdateGroup(_ context.Context, uuid string, g Group) error {
+	existing := f.groups[uuid]
+	existing.Name, existing.Description, existing.FederatedAttributeValues = g.Name, g.Description, g.FederatedAttributeValues
+	f.groups[uuid] = existing
+	return nil
+}
+
+func (f *fakeClient) GetGroupPermissions(_ context.Context, uuid string) ([]Permission, error) {
+	return f.groups[uuid].Permissions, nil
+}
+
+func (f *fakeClient) UpdateGroupPermissions(_ context.Context, uuid string, permissions []Permission) error {
+	g := f.groups[uuid]
+	g.Permissions = permissions
+	f.groups[uuid] = g
+	return nil
+}
+
+func (f *fakeClient) GetPolicyBindings(context.Context) (map[string][]string, error) {
+	return f.bindings, nil
+}
+
+func (f *fakeClient) UpdateGroupPolicyBindings(_ context.Context, groupUUID string, policyUUIDs []string) error {
+	f.bindings[groupUUID] = policyUUIDs
+	return nil
+}
+
+func (f *fakeClient) ListUsers(context.Context) ([]string, error) {
+	var result []string
+	for email := range f.userGroups {
+		result = append(result, email)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (f *fakeClient) GetUserGroups(_ context.Context, email string) ([]string, error) {
+	return f.userGroups[email], nil
+}
+
+func (f *fakeClient) CreateUser(_ context.Context, email string) error {
+	f.userGroups[email] = nil
+	return nil
+}
+
+func (f *fakeClient) UpdateUserGroups(_ context.Context, email string, groupUUIDs []string) error {
+	f.userGroups[email] = groupUUIDs
+	return nil
+}
+
+func (f *fakeClient) ListEnvironments(context.Context) ([]Environment, error) {
+	return nil, nil
+}
+
+var testResources = Resources{
+	Policies: []Policy{
+		{Name: "read-settings", Description: "reads settings", Statement: "ALLOW settings:objects:read;"},
+	},
+	Groups: []Group{
+		{
+			Name:        "readers",
+			Permissions: []Permission{{Name: "tenant-viewer", Scope: "abc12345", ScopeType: "tenant"}},
+			Policies:    []string{"Standard User", "read-settings"},
+		},
+		{Name: "writers"},
+	},
+	Users: []User{
+		{Email: "jane.doe@example.com", Groups: []string{"readers"}},
+	},
+}
+
+func TestDeployAndDownload(t *testing.T) {
+	c := newFakeClient()
+
+	errs := Deploy(context.TODO(), c, testResources)
+	assert.Empty(t, errs)
+
+	downloaded, err := Download(context.TODO(), c)
+	assert.NoError(t, err)
+	assert.Equal(t, testResources, downloaded)
+
+	// deploying again updates the existing resources instead of creating new ones
+	errs = Deploy(context.TODO(), c, testResources)
+	assert.Empty(t, errs)
+	assert.Len(t, c.policies, 1)
+	assert.Len(t, c.groups, 2)
+	assert.Len(t, c.userGroups, 1)
+}
+
+func TestDeploy_ReportsUnknownPoliciesAndFailedGroups(t *testing.T) {
+	c := newFakeClient()
+	c.failingGroups["failing"] = true
+
+	errs := Deploy(context.TODO(), c, Resources{
+		Groups: []Group{
+			{Name: "unknown-policy", Policies: []string{"unknown"}},
+			{Name: "failing"},
+			{Name: "ok"},
+		},
+		Users: []User{
+			{Email: "a@example.com", Groups: []string{"failing"}},
+			{Email: "b@example.com", Groups: []string{"ok"}},
+		},
+	})
+
+	assert.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], `failed to deploy group "unknown-policy": policy "unknown" is neither a global policy`)
+	assert.ErrorContains(t, errs[1], `failed to deploy group "failing": group creation failed`)
+	assert.ErrorContains(t, errs[2], `failed to deploy user "a@example.com": group "failing" was not deployed successfully`)
+	assert.Contains(t, c.userGroups, "b@example.com")
+}
+
+func TestLoadResources(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "project/policies.yaml", []byte(`
+policies:
+- name: read-settings
+  description: reads settings
+  statement: "ALLOW settings:objects:read;"
+`), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "project/sub/groups.yml", []byte(`
+groups:
+- name: readers
+  permissions:
+  - {name: tenant-viewer, scope: abc12345, scopeType: tenant}
+  policies: [Standard User, read-settings]
+- name: writers
+users:
+- email: jane.doe@example.com
+  groups: [readers]
+`), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "project/ignored.json", []byte(`{}`), 0644))
+
+	got, errs := LoadResources(fs, "project")
+	assert.Empty(t, errs)
+	assert.Equal(t, testResources, got)
+}
+
+func TestLoadResources_RejectsUnknownFields(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "project/a.yaml", []byte("groups:\n- name: a\n  unknown: b\n"), 0644))
+
+	_, errs := LoadResources(fs, "project")
+	assert.Len(t, errs, 1)
+}
+
+func TestWriteResources(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	assert.NoError(t, WriteResources(fs, "out/account", testResources))
+
+	got, errs := LoadResources(fs, "out/account")
+	assert.Empty(t, errs)
+	assert.Equal(t, testResources, got)
+}
+
+func TestValidate(t *testing.T) {
+	errs := Validate(Resources{
+		Policies: []Policy{{Name: "p"}, {Name: "dup", Statement: "s"}, {Name: "dup", Statement: "s"}},
+		Groups:   []Group{{Name: "g"}, {Name: "g"}},
+		Users:    []User{{Email: "u", Groups: []string{"g", "unknown"}}, {}},
+	})
+
+	assert.Equal(t, []error{
+		fmt.Errorf(`policy "p" is missing a statement`),
+		fmt.Errorf(`policy "dup" is defined more than once`),
+		fmt.Errorf(`group "g" is defined more than once`),
+		fmt.Errorf(`user "u" is a member of undefined group "unknown"`),
+		fmt.Errorf(`user is missing an email`),
+	}, errs)
+}
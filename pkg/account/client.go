@@ -0,0 +1,389 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultApiURL is the URL of the account management API used if an account does not define one.
+const DefaultApiURL = "https://api.dynatrace.com"
+
+// OAuthScopes holds the OAuth scopes required to manage account resources.
+var OAuthScopes = []string{"account-idm-read", "account-idm-write", "iam-policies-management"}
+
+// EnvironmentOAuthScopes holds the OAuth scopes required to list the environments of an account.
+var EnvironmentOAuthScopes = []string{"account-env-read"}
+
+// Client accesses the users, groups and policies of a single account.
+type Client interface {
+	// ListPolicies returns all policies of the account, including their statements.
+	ListPolicies(ctx context.Context) ([]RemotePolicy, error)
+	// ListGlobalPolicies returns the global policies provided by Dynatrace. Their statements are not returned.
+	ListGlobalPolicies(ctx context.Context) ([]RemotePolicy, error)
+	// CreatePolicy creates an account policy and returns its UUID.
+	CreatePolicy(ctx context.Context, p Policy) (string, error)
+	// UpdatePolicy updates the account policy of the given UUID.
+	UpdatePolicy(ctx context.Context, uuid string, p Policy) error
+
+	// ListGroups returns all groups of the account, without their permissions and policies.
+	ListGroups(ctx context.Context) ([]RemoteGroup, error)
+	// CreateGroup creates a group and returns its UUID.
+	CreateGroup(ctx context.Context, g Group) (string, error)
+	// UpdateGroup updates the name, description and federated attribute values of the group of the given UUID.
+	UpdateGroup(ctx context.Context, uuid string, g Group) error
+	// GetGroupPermissions returns all permissions of the group of the given UUID.
+	GetGroupPermissions(ctx context.Context, uuid string) ([]Permission, error)
+	// UpdateGroupPermissions replaces all permissions of the group of the given UUID.
+	UpdateGroupPermissions(ctx context.Context, uuid string, permissions []Permission) error
+	// GetPolicyBindings returns the UUIDs of all policies bound on account level per group UUID.
+	GetPolicyBindings(ctx context.Context) (map[string][]string, error)
+	// UpdateGroupPolicyBindings replaces all policies bound on account level to the group of the given UUID.
+	UpdateGroupPolicyBindings(ctx context.Context, groupUUID string, policyUUIDs []string) error
+
+	// ListUsers returns the emails of all users of the account.
+	ListUsers(ctx context.Context) ([]string, error)
+	// GetUserGroups returns the UUIDs of all groups the user is a member of.
+	GetUserGroups(ctx context.Context, email string) ([]string, error)
+	// CreateUser invites a user to the account.
+	CreateUser(ctx context.Context, email string) error
+	// UpdateUserGroups replaces all group memberships of a user.
+	UpdateUserGroups(ctx context.Context, email string, groupUUIDs []string) error
+
+	// ListEnvironments returns all environments of the account.
+	ListEnvironments(ctx context.Context) ([]Environment, error)
+}
+
+// RemotePolicy is a policy existing in Dynatrace.
+type RemotePolicy struct {
+	UUID string
+	Policy
+}
+
+// RemoteGroup is a group existing in Dynatrace.
+type RemoteGroup struct {
+	UUID string
+	// Owner states how the group is managed, e.g. 'LOCAL' or 'SCIM'
+	Owner string
+	Group
+}
+
+// Environment is an environment (tenant) of an account.
+type Environment struct {
+	// ID is the environment ID, e.g. 'abc12345'
+	ID   string
+	Name string
+	// Tags are the tags of the environment, as 'key' or 'key:value'
+	Tags []string
+}
+
+type httpClient struct {
+	client      *http.Client
+	apiURL      string
+	accountUUID string
+}
+
+// NewClient returns a client for the account of the given UUID. The given HTTP client needs to authorize all requests
+// using OAuth credentials with the [OAuthScopes]. If apiURL is empty, the [DefaultApiURL] is used.
+func NewClient(client *http.Client, apiURL string, accountUUID string) Client {
+	if apiURL == "" {
+		apiURL = DefaultApiURL
+	}
+	return &httpClient{
+		client:      client,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		accountUUID: accountUUID,
+	}
+}
+
+func (c *httpClient) accountPath(path string) string {
+	return fmt.Sprintf("%s/iam/v1/accounts/%s%s", c.apiURL, url.PathEscape(c.accountUUID), path)
+}
+
+func (c *httpClient) repoPath(levelType, levelId, path string) string {
+	return fmt.Sprintf("%s/iam/v1/repo/%s/%s%s", c.apiURL, levelType, url.PathEscape(levelId), path)
+}
+
+type policyDTO struct {
+	UUID           string   `json:"uuid,omitempty"`
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	StatementQuery string   `json:"statementQuery,omitempty"`
+	Tags           []string `json:"tags"`
+}
+
+func (c *httpClient) ListPolicies(ctx context.Context) ([]RemotePolicy, error) {
+	policies, err := c.listPolicies(ctx, "account", c.accountUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the list does not contain the statements
+	for i := range policies {
+		var dto policyDTO
+		if err := c.get(ctx, c.repoPath("account", c.accountUUID, "/policies/"+url.PathEscape(policies[i].UUID)), &dto); err != nil {
+			return nil, err
+		}
+		policies[i].Statement = dto.StatementQuery
+	}
+	return policies, nil
+}
+
+func (c *httpClient) ListGlobalPolicies(ctx context.Context) ([]RemotePolicy, error) {
+	return c.listPolicies(ctx, "global", "global")
+}
+
+func (c *httpClient) listPolicies(ctx context.Context, levelType, levelId string) ([]RemotePolicy, error) {
+	var resp struct {
+		Policies []policyDTO `json:"policies"`
+	}
+	if err := c.get(ctx, c.repoPath(levelType, levelId, "/policies"), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]RemotePolicy, len(resp.Policies))
+	for i, p := range resp.Policies {
+		result[i] = RemotePolicy{UUID: p.UUID, Policy: Policy{Name: p.Name, Description: p.Description}}
+	}
+	return result, nil
+}
+
+func (c *httpClient) CreatePolicy(ctx context.Context, p Policy) (string, error) {
+	var created policyDTO
+	if err := c.send(ctx, rest.Post, c.repoPath("account", c.accountUUID, "/policies"), toPolicyDTO(p), &created); err != nil {
+		return "", err
+	}
+	return created.UUID, nil
+}
+
+func (c *httpClient) UpdatePolicy(ctx context.Context, uuid string, p Policy) error {
+	return c.send(ctx, rest.Put, c.repoPath("account", c.accountUUID, "/policies/"+url.PathEscape(uuid)), toPolicyDTO(p), nil)
+}
+
+func toPolicyDTO(p Policy) policyDTO {
+	return policyDTO{Name: p.Name, Description: p.Description, StatementQuery: p.Statement, Tags: []string{}}
+}
+
+type groupDTO struct {
+	UUID                     string   `json:"uuid,omitempty"`
+	Name                     string   `json:"name"`
+	Description              string   `json:"description"`
+	FederatedAttributeValues []string `json:"federatedAttributeValues"`
+	Owner                    string   `json:"owner,omitempty"`
+}
+
+func (c *httpClient) ListGroups(ctx context.Context) ([]RemoteGroup, error) {
+	var resp struct {
+		Items []groupDTO `json:"items"`
+	}
+	if err := c.get(ctx, c.accountPath("/groups"), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]RemoteGroup, len(resp.Items))
+	for i, g := range resp.Items {
+		result[i] = RemoteGroup{
+			UUID:  g.UUID,
+			Owner: g.Owner,
+			Group: Group{Name: g.Name, Description: g.Description, FederatedAttributeValues: g.FederatedAttributeValues},
+		}
+	}
+	return result, nil
+}
+
+func (c *httpClient) CreateGroup(ctx context.Context, g Group) (string, error) {
+	var created []groupDTO
+	if err := c.send(ctx, rest.Post, c.accountPath("/groups"), []groupDTO{toGroupDTO(g)}, &created); err != nil {
+		return "", err
+	}
+	if len(created) != 1 {
+		return "", fmt.Errorf("failed to create group %q: expected one created group, but got %d", g.Name, len(created))
+	}
+	return created[0].UUID, nil
+}
+
+func (c *httpClient) UpdateGroup(ctx context.Context, uuid string, g Group) error {
+	return c.send(ctx, rest.Put, c.accountPath("/groups/"+url.PathEscape(uuid)), toGroupDTO(g), nil)
+}
+
+func toGroupDTO(g Group) groupDTO {
+	federatedAttributeValues := g.FederatedAttributeValues
+	if federatedAttributeValues == nil {
+		federatedAttributeValues = []string{}
+	}
+	return groupDTO{Name: g.Name, Description: g.Description, FederatedAttributeValues: federatedAttributeValues}
+}
+
+type permissionDTO struct {
+	PermissionName string `json:"permissionName"`
+	Scope          string `json:"scope"`
+	ScopeType      string `json:"scopeType"`
+}
+
+func (c *httpClient) GetGroupPermissions(ctx context.Context, uuid string) ([]Permission, error) {
+	var resp struct {
+		Permissions []permissionDTO `json:"permissions"`
+	}
+	if err := c.get(ctx, c.accountPath("/groups/"+url.PathEscape(uuid)+"/permissions"), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]Permission, len(resp.Permissions))
+	for i, p := range resp.Permissions {
+		result[i] = Permission{Name: p.PermissionName, Scope: p.Scope, ScopeType: p.ScopeType}
+	}
+	return result, nil
+}
+
+func (c *httpClient) UpdateGroupPermissions(ctx context.Context, uuid string, permissions []Permission) error {
+	dtos := make([]permissionDTO, len(permissions))
+	for i, p := range permissions {
+		dtos[i] = permissionDTO{PermissionName: p.Name, Scope: p.Scope, ScopeType: p.ScopeType}
+	}
+	return c.send(ctx, rest.Put, c.accountPath("/groups/"+url.PathEscape(uuid)+"/permissions"), dtos, nil)
+}
+
+func (c *httpClient) GetPolicyBindings(ctx context.Context) (map[string][]string, error) {
+	var resp struct {
+		PolicyBindings []struct {
+			PolicyUUID string   `json:"policyUuid"`
+			Groups     []string `json:"groups"`
+		} `json:"policyBindings"`
+	}
+	if err := c.get(ctx, c.repoPath("account", c.accountUUID, "/bindings"), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string)
+	for _, b := range resp.PolicyBindings {
+		for _, g := range b.Groups {
+			result[g] = append(result[g], b.PolicyUUID)
+		}
+	}
+	return result, nil
+}
+
+func (c *httpClient) UpdateGroupPolicyBindings(ctx context.Context, groupUUID string, policyUUIDs []string) error {
+	body := struct {
+		PolicyUUIDs []string `json:"policyUuids"`
+	}{PolicyUUIDs: policyUUIDs}
+	return c.send(ctx, rest.Put, c.repoPath("account", c.accountUUID, "/bindings/groups/"+url.PathEscape(groupUUID)), body, nil)
+}
+
+func (c *httpClient) ListUsers(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Items []struct {
+			Email string `json:"email"`
+		} `json:"items"`
+	}
+	if err := c.get(ctx, c.accountPath("/users"), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(resp.Items))
+	for i, u := range resp.Items {
+		result[i] = u.Email
+	}
+	return result, nil
+}
+
+func (c *httpClient) GetUserGroups(ctx context.Context, email string) ([]string, error) {
+	var resp struct {
+		Groups []struct {
+			GroupUUID string `json:"groupUuid"`
+		} `json:"groups"`
+	}
+	if err := c.get(ctx, c.accountPath("/users/"+url.PathEscape(email)), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(resp.Groups))
+	for i, g := range resp.Groups {
+		result[i] = g.GroupUUID
+	}
+	return result, nil
+}
+
+func (c *httpClient) CreateUser(ctx context.Context, email string) error {
+	body := struct {
+		Email string `json:"email"`
+	}{Email: email}
+	return c.send(ctx, rest.Post, c.accountPath("/users"), body, nil)
+}
+
+func (c *httpClient) UpdateUserGroups(ctx context.Context, email string, groupUUIDs []string) error {
+	return c.send(ctx, rest.Put, c.accountPath("/users/"+url.PathEscape(email)+"/groups"), groupUUIDs, nil)
+}
+
+func (c *httpClient) ListEnvironments(ctx context.Context) ([]Environment, error) {
+	var resp struct {
+		TenantResources []struct {
+			Name string   `json:"name"`
+			ID   string   `json:"id"`
+			Tags []string `json:"tags"`
+		} `json:"tenantResources"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("%s/env/v2/accounts/%s/environments", c.apiURL, url.PathEscape(c.accountUUID)), &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]Environment, len(resp.TenantResources))
+	for i, e := range resp.TenantResources {
+		result[i] = Environment{ID: e.ID, Name: e.Name, Tags: e.Tags}
+	}
+	return result, nil
+}
+
+func (c *httpClient) get(ctx context.Context, url string, result any) error {
+	resp, err := rest.Get(ctx, c.client, url)
+	if err != nil {
+		return fmt.Errorf("failed to GET %s: %w", url, err)
+	}
+	return unmarshalResponse(http.MethodGet, url, resp, result)
+}
+
+func (c *httpClient) send(ctx context.Context, call rest.SendingRequest, url string, body any, result any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to serialize request body: %w", err)
+	}
+
+	resp, err := call(ctx, c.client, url, data)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", url, err)
+	}
+	return unmarshalResponse("request to", url, resp, result)
+}
+
+func unmarshalResponse(method, url string, resp rest.Response, result any) error {
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed %s %s (HTTP %d)!\n\tResponse was: %s", method, url, resp.StatusCode, string(resp.Body))
+	}
+	if result == nil || len(resp.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body, result); err != nil {
+		return fmt.Errorf("failed to parse response of %s %s: %w", method, url, err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+)
+
+// Deploy creates or updates all given resources in the account accessed by the client, in the order policies, groups
+// and users. Resources are matched with the existing ones by name, or email for users. Permissions, policy bindings
+// and group memberships are replaced by the defined ones. Existing resources which are not defined are left untouched.
+//
+// Deploying continues if a resource fails, and all errors are returned. Resources referencing a failed one fail as well.
+func Deploy(ctx context.Context, c Client, r Resources) []error {
+	policies, errs := deployPolicies(ctx, c, r.Policies)
+
+	groups, groupErrs := deployGroups(ctx, c, r.Groups, policies)
+	errs = append(errs, groupErrs...)
+
+	errs = append(errs, deployUsers(ctx, c, r.Users, groups)...)
+	return errs
+}
+
+// deployPolicies deploys the given policies and returns the UUIDs of all policies which can be bound to groups by name.
+func deployPolicies(ctx context.Context, c Client, policies []Policy) (map[string]string, []error) {
+	global, err := c.ListGlobalPolicies(ctx)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list global policies: %w", err)}
+	}
+	remote, err := c.ListPolicies(ctx)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list policies: %w", err)}
+	}
+
+	uuids := make(map[string]string, len(global)+len(remote))
+	for _, p := range global {
+		uuids[p.Name] = p.UUID
+	}
+	existing := make(map[string]RemotePolicy, len(remote))
+	for _, p := range remote {
+		uuids[p.Name] = p.UUID
+		existing[p.Name] = p
+	}
+
+	var errs []error
+	for _, p := range policies {
+		e, found := existing[p.Name]
+		switch {
+		case !found:
+			log.Info("Creating policy %q", p.Name)
+			uuid, err := c.CreatePolicy(ctx, p)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to create policy %q: %w", p.Name, err))
+				delete(uuids, p.Name)
+				continue
+			}
+			uuids[p.Name] = uuid
+		case e.Policy != p:
+			log.Info("Updating policy %q", p.Name)
+			if err := c.UpdatePolicy(ctx, e.UUID, p); err != nil {
+				errs = append(errs, fmt.Errorf("failed to update policy %q: %w", p.Name, err))
+				delete(uuids, p.Name)
+			}
+		default:
+			log.Debug("Policy %q is up to date", p.Name)
+		}
+	}
+	return uuids, errs
+}
+
+// deployGroups deploys the given groups, including their permissions and policy bindings, and returns the UUIDs of all
+// groups by name.
+func deployGroups(ctx context.Context, c Client, groups []Group, policies map[string]string) (map[string]string, []error) {
+	remote, err := c.ListGroups(ctx)
+	if err != nil {
+		return nil, []error{fmt.Errorf("failed to list groups: %w", err)}
+	}
+
+	uuids := make(map[string]string, len(remote))
+	for _, g := range remote {
+		uuids[g.Name] = g.UUID
+	}
+
+	var errs []error
+	for _, g := range groups {
+		if err := deployGroup(ctx, c, g, uuids, policies); err != nil {
+			errs = append(errs, fmt.Errorf("failed to deploy group %q: %w", g.Name, err))
+			delete(uuids, g.Name)
+		}
+	}
+	return uuids, errs
+}
+
+func deployGroup(ctx context.Context, c Client, g Group, uuids map[string]string, policies map[string]string) error {
+	policyUUIDs := make([]string, len(g.Policies))
+	for i, name := range g.Policies {
+		uuid, found := policies[name]
+		if !found {
+			return fmt.Errorf("policy %q is neither a global policy, nor a (successfully deployed) policy of the account", name)
+		}
+		policyUUIDs[i] = uuid
+	}
+
+	uuid, found := uuids[g.Name]
+	if found {
+		log.Info("Updating group %q", g.Name)
+		if err := c.UpdateGroup(ctx, uuid, g); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Creating group %q", g.Name)
+		var err error
+		if uuid, err = c.CreateGroup(ctx, g); err != nil {
+			return err
+		}
+		uuids[g.Name] = uuid
+	}
+
+	if err := c.UpdateGroupPermissions(ctx, uuid, g.Permissions); err != nil {
+		return fmt.Errorf("failed to update permissions: %w", err)
+	}
+	if err := c.UpdateGroupPolicyBindings(ctx, uuid, policyUUIDs); err != nil {
+		return fmt.Errorf("failed to update policy bindings: %w", err)
+	}
+	return nil
+}
+
+func deployUsers(ctx context.Context, c Client, users []User, groups map[string]string) []error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	remote, err := c.ListUsers(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("failed to list users: %w", err)}
+	}
+	existing := make(map[string]struct{}, len(remote))
+	for _, email := range remote {
+		existing[email] = struct{}{}
+	}
+
+	var errs []error
+	for _, u := range users {
+		if err := deployUser(ctx, c, u, existing, groups); err != nil {
+			errs = append(errs, fmt.Errorf("failed to deploy user %q: %w", u.Email, err))
+		}
+	}
+	return errs
+}
+
+func deployUser(ctx context.Context, c Client, u User, existing map[string]struct{}, groups map[string]string) error {
+	groupUUIDs := make([]string, len(u.Groups))
+	for i, name := range u.Groups {
+		uuid, found := groups[name]
+		if !found {
+			return fmt.Errorf("group %q was not deployed successfully", name)
+		}
+		groupUUIDs[i] = uuid
+	}
+
+	if _, found := existing[u.Email]; !found {
+		log.Info("Inviting user %q", u.Email)
+		if err := c.CreateUser(ctx, u.Email); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Updating group memberships of user %q", u.Email)
+	return c.UpdateUserGroups(ctx, u.Email, groupUUIDs)
+}
@@ -0,0 +1,133 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Download returns all users, groups and policies of the account accessed by the client. Policy bindings are only
+// downloaded on account level, and may reference global policies. All resources are sorted by name, or email for users.
+func Download(ctx context.Context, c Client) (Resources, error) {
+	policies, err := c.ListPolicies(ctx)
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to list policies: %w", err)
+	}
+	global, err := c.ListGlobalPolicies(ctx)
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to list global policies: %w", err)
+	}
+
+	policyNames := make(map[string]string, len(policies)+len(global))
+	for _, p := range append(global, policies...) {
+		policyNames[p.UUID] = p.Name
+	}
+
+	groups, groupNames, err := downloadGroups(ctx, c, policyNames)
+	if err != nil {
+		return Resources{}, err
+	}
+
+	users, err := downloadUsers(ctx, c, groupNames)
+	if err != nil {
+		return Resources{}, err
+	}
+
+	r := Resources{
+		Policies: make([]Policy, len(policies)),
+		Groups:   groups,
+		Users:    users,
+	}
+	for i, p := range policies {
+		r.Policies[i] = p.Policy
+	}
+	sort.Slice(r.Policies, func(i, j int) bool { return r.Policies[i].Name < r.Policies[j].Name })
+
+	return r, nil
+}
+
+func downloadGroups(ctx context.Context, c Client, policyNames map[string]string) ([]Group, map[string]string, error) {
+	remote, err := c.ListGroups(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	bindings, err := c.GetPolicyBindings(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get policy bindings: %w", err)
+	}
+
+	groups := make([]Group, len(remote))
+	groupNames := make(map[string]string, len(remote))
+	for i, g := range remote {
+		groupNames[g.UUID] = g.Name
+
+		permissions, err := c.GetGroupPermissions(ctx, g.UUID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get permissions of group %q: %w", g.Name, err)
+		}
+		sort.Slice(permissions, func(i, j int) bool {
+			if permissions[i].Name != permissions[j].Name {
+				return permissions[i].Name < permissions[j].Name
+			}
+			return permissions[i].Scope < permissions[j].Scope
+		})
+
+		var policies []string
+		for _, uuid := range bindings[g.UUID] {
+			if name, found := policyNames[uuid]; found {
+				policies = append(policies, name)
+			}
+		}
+		sort.Strings(policies)
+
+		groups[i] = g.Group
+		groups[i].Permissions = permissions
+		groups[i].Policies = policies
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	return groups, groupNames, nil
+}
+
+func downloadUsers(ctx context.Context, c Client, groupNames map[string]string) ([]User, error) {
+	emails, err := c.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	sort.Strings(emails)
+
+	users := make([]User, len(emails))
+	for i, email := range emails {
+		groupUUIDs, err := c.GetUserGroups(ctx, email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get groups of user %q: %w", email, err)
+		}
+
+		var groups []string
+		for _, uuid := range groupUUIDs {
+			if name, found := groupNames[uuid]; found {
+				groups = append(groups, name)
+			}
+		}
+		sort.Strings(groups)
+
+		users[i] = User{Email: email, Groups: groups}
+	}
+	return users, nil
+}
@@ -0,0 +1,147 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package account
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ResourcesFileName is the name of the file account resources are written to.
+const ResourcesFileName = "account.yaml"
+
+// LoadResources loads the account resources defined in all YAML files within the given directory and its
+// subdirectories, and validates them.
+func LoadResources(fs afero.Fs, path string) (Resources, []error) {
+	var paths []string
+	err := afero.Walk(fs, path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && files.IsYamlFileExtension(p) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return Resources{}, []error{fmt.Errorf("failed to read account project %q: %w", path, err)}
+	}
+	sort.Strings(paths)
+
+	var result Resources
+	var errs []error
+	for _, p := range paths {
+		r, err := loadResourcesFile(fs, p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result.Policies = append(result.Policies, r.Policies...)
+		result.Groups = append(result.Groups, r.Groups...)
+		result.Users = append(result.Users, r.Users...)
+	}
+	if errs != nil {
+		return Resources{}, errs
+	}
+
+	if errs := Validate(result); errs != nil {
+		return Resources{}, errs
+	}
+	return result, nil
+}
+
+func loadResourcesFile(fs afero.Fs, path string) (Resources, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	var r Resources
+	if err := yaml.UnmarshalStrict(data, &r); err != nil {
+		return Resources{}, fmt.Errorf("failed to parse account resources of %q: %w", path, err)
+	}
+	return r, nil
+}
+
+// Validate checks that all resources are complete and uniquely named, and that users only reference defined groups.
+// As groups may reference global policies, which are not defined in projects, policy references are validated on deployment.
+func Validate(r Resources) []error {
+	var errs []error
+
+	policies := make(map[string]struct{}, len(r.Policies))
+	for _, p := range r.Policies {
+		switch {
+		case p.Name == "":
+			errs = append(errs, fmt.Errorf("policy is missing a name"))
+		case p.Statement == "":
+			errs = append(errs, fmt.Errorf("policy %q is missing a statement", p.Name))
+		}
+		if _, found := policies[p.Name]; found {
+			errs = append(errs, fmt.Errorf("policy %q is defined more than once", p.Name))
+		}
+		policies[p.Name] = struct{}{}
+	}
+
+	groups := make(map[string]struct{}, len(r.Groups))
+	for _, g := range r.Groups {
+		if g.Name == "" {
+			errs = append(errs, fmt.Errorf("group is missing a name"))
+		}
+		if _, found := groups[g.Name]; found {
+			errs = append(errs, fmt.Errorf("group %q is defined more than once", g.Name))
+		}
+		groups[g.Name] = struct{}{}
+	}
+
+	users := make(map[string]struct{}, len(r.Users))
+	for _, u := range r.Users {
+		if u.Email == "" {
+			errs = append(errs, fmt.Errorf("user is missing an email"))
+		}
+		if _, found := users[u.Email]; found {
+			errs = append(errs, fmt.Errorf("user %q is defined more than once", u.Email))
+		}
+		users[u.Email] = struct{}{}
+
+		for _, g := range u.Groups {
+			if _, found := groups[g]; !found {
+				errs = append(errs, fmt.Errorf("user %q is a member of undefined group %q", u.Email, g))
+			}
+		}
+	}
+
+	return errs
+}
+
+// WriteResources writes the given resources to the file [ResourcesFileName] within the given directory.
+func WriteResources(fs afero.Fs, path string, r Resources) error {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to serialize account resources: %w", err)
+	}
+
+	if err := fs.MkdirAll(path, 0777); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", path, err)
+	}
+
+	return afero.WriteFile(fs, filepath.Join(path, ResourcesFileName), data, 0664)
+}
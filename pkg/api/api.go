@@ -16,6 +16,18 @@
 
 package api
 
+import (
+	"net/url"
+	"strings"
+)
+
+// DashboardShareSettingsProperty is the property of a dashboard payload holding its share settings. They are not part
+// of the dashboard itself, but managed via the share settings endpoint of the dashboard.
+const DashboardShareSettingsProperty = "shareSettings"
+
+// ParentObjectIdPlaceholder is the placeholder of the ID of the parent object in the URLPath of parent-scoped APIs.
+const ParentObjectIdPlaceholder = "{parentId}"
+
 // API structure present definition of config endpoints
 type API struct {
 	ID                           string
@@ -36,6 +48,27 @@ type API struct {
 	//
 	// Those configs include all configs handling credentials, as well as the extension-API.
 	SkipDownload bool
+	// Ordered APIs are those APIs whose configs are applied in a defined order, e.g. request naming rules.
+	// Their order is changed via the '<URLPath>/order' endpoint.
+	Ordered bool
+	// Parent is the ID of the API of the parent objects of parent-scoped APIs, e.g. the web applications of their key
+	// user actions. The URLPath of such APIs contains the ParentObjectIdPlaceholder, and their configs define the ID of
+	// their parent object as scope.
+	Parent string
+	// NameSelector is the query parameter of APIs filtering the listed configs by a selector, e.g. `sloSelector`. If
+	// set, existing configs are looked up by their name on server side, instead of listing all configs of the API.
+	NameSelector string
+}
+
+// HasParent returns whether the API is parent-scoped, i.e. its configs belong to a parent object.
+func (a API) HasParent() bool {
+	return a.Parent != ""
+}
+
+// ApplyParentObjectID returns a copy of the API whose URLPath points to the configs of the given parent object.
+func (a API) ApplyParentObjectID(parentObjectID string) API {
+	a.URLPath = strings.ReplaceAll(a.URLPath, ParentObjectIdPlaceholder, url.PathEscape(parentObjectID))
+	return a
 }
 
 // CreateURL creates final URL for given environmentUrl/domain
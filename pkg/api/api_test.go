@@ -28,6 +28,16 @@ func TestGetUrl(t *testing.T) {
 	assert.Equal(t, "https://url/to/dev/environment/api/config/v1/managementZones", (API{URLPath: "/api/config/v1/managementZones"}).CreateURL(testDevEnvironment.GetEnvironmentUrl()))
 }
 
+func TestApplyParentObjectID(t *testing.T) {
+	a := API{ID: "key-user-actions-web", URLPath: "/api/config/v1/applications/web/" + ParentObjectIdPlaceholder + "/keyUserActions", Parent: "application-web"}
+
+	applied := a.ApplyParentObjectID("APPLICATION-1234")
+
+	assert.Equal(t, "/api/config/v1/applications/web/APPLICATION-1234/keyUserActions", applied.URLPath)
+	assert.Equal(t, a.ID, applied.ID)
+	assert.Contains(t, a.URLPath, ParentObjectIdPlaceholder, "original API must not be changed")
+}
+
 func Test_configEndpoints(t *testing.T) {
 	for _, v := range configEndpoints {
 		v.testConfiguredApi(t)
@@ -48,4 +58,10 @@ func (a API) testConfiguredApi(t *testing.T) {
 	} else {
 		assert.NotZerof(t, a.PropertyNameOfGetAllResponse, "endpoint %v doesnt have populated field \"PropertyNameOfGetAllResponse\"! (actual values: %+v)", a.ID, a)
 	}
+	if a.HasParent() {
+		assert.Containsf(t, a.URLPath, ParentObjectIdPlaceholder, "endpoint %q has a parent, but its URL path does not contain the ID of the parent object", a.ID)
+		assert.Containsf(t, NewAPIs(), a.Parent, "parent %q of endpoint %q is not a known API", a.Parent, a.ID)
+	} else {
+		assert.NotContainsf(t, a.URLPath, ParentObjectIdPlaceholder, "endpoint %q has no parent, but its URL path contains the ID of a parent object", a.ID)
+	}
 }
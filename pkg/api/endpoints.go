@@ -121,11 +121,30 @@ var configEndpoints = []API{
 		URLPath:                      "/api/config/v1/applications/mobile",
 		PropertyNameOfGetAllResponse: StandardApiPropertyNameOfGetAllResponse,
 	},
+	{
+		ID:                           "key-user-actions-web",
+		URLPath:                      "/api/config/v1/applications/web/" + ParentObjectIdPlaceholder + "/keyUserActions",
+		PropertyNameOfGetAllResponse: "keyUserActionList",
+		Parent:                       "application-web",
+	},
+	{
+		ID:                           "key-user-actions-mobile",
+		URLPath:                      "/api/config/v1/applications/mobile/" + ParentObjectIdPlaceholder + "/keyUserActions",
+		PropertyNameOfGetAllResponse: "keyUserActions",
+		Parent:                       "application-mobile",
+	},
+	{
+		ID:                           "user-action-and-session-properties-mobile",
+		URLPath:                      "/api/config/v1/applications/mobile/" + ParentObjectIdPlaceholder + "/userActionAndSessionProperties",
+		PropertyNameOfGetAllResponse: StandardApiPropertyNameOfGetAllResponse,
+		Parent:                       "application-mobile",
+	},
 	{
 		ID:                           "app-detection-rule",
 		URLPath:                      "/api/config/v1/applicationDetectionRules",
 		PropertyNameOfGetAllResponse: StandardApiPropertyNameOfGetAllResponse,
 		DeprecatedBy:                 "builtin:rum.web.app-detection",
+		Ordered:                      true,
 	},
 	{
 		ID:                           "aws-credentials",
@@ -206,12 +225,14 @@ var configEndpoints = []API{
 		URLPath:                      "/api/config/v1/service/requestNaming",
 		PropertyNameOfGetAllResponse: StandardApiPropertyNameOfGetAllResponse,
 		NonUniqueName:                true,
+		Ordered:                      true,
 	},
 	// Environment API not Config API
 	{
 		ID:                           "slo",
 		URLPath:                      "/api/v2/slo",
 		PropertyNameOfGetAllResponse: "slo",
+		NameSelector:                 "sloSelector",
 	},
 	{
 		ID:                           "credential-vault",
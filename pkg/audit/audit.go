@@ -0,0 +1,182 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audit records all changes monaco performs on Dynatrace environments - creating, updating and deleting
+// objects - to satisfy change-management requirements. Records are written to Sinks, e.g. an append-only local file
+// or the logs of the environment itself.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/spf13/afero"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// Action is the kind of change a Record describes
+type Action string
+
+const (
+	// Upsert creates an object, or updates it if it already exists
+	Upsert Action = "upsert"
+	// Update updates an existing object
+	Update Action = "update"
+	// Delete deletes an object
+	Delete Action = "delete"
+)
+
+// Record describes a single change performed on an environment.
+type Record struct {
+	Time time.Time `json:"time"`
+	// User is the user monaco was run by
+	User        string `json:"user"`
+	Environment string `json:"environment"`
+	Action      Action `json:"action"`
+	// Type is the API, settings schema or extension the changed object belongs to
+	Type string `json:"type"`
+	// Coordinate is the config the change was made for, if known
+	Coordinate string `json:"coordinate,omitempty"`
+	// ObjectId is the ID of the changed object in the environment, if known
+	ObjectId string `json:"objectId,omitempty"`
+	// Name is the name of the changed object, if known
+	Name string `json:"name,omitempty"`
+	// PayloadHash is the hex-encoded SHA-256 hash of the payload sent to the environment, if any
+	PayloadHash string `json:"payloadHash,omitempty"`
+	// Error is set if the change failed
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives audit records.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+}
+
+type coordinateKey struct{}
+
+// WithCoordinate returns a context attributing all changes made using it to the given config.
+func WithCoordinate(ctx context.Context, c coordinate.Coordinate) context.Context {
+	return context.WithValue(ctx, coordinateKey{}, c)
+}
+
+// CoordinateFrom returns the config changes made using the given context are attributed to, if any.
+func CoordinateFrom(ctx context.Context) (coordinate.Coordinate, bool) {
+	c, ok := ctx.Value(coordinateKey{}).(coordinate.Coordinate)
+	return c, ok
+}
+
+func coordinateFrom(ctx context.Context) string {
+	if c, ok := CoordinateFrom(ctx); ok {
+		return c.String()
+	}
+	return ""
+}
+
+// CurrentUser returns the name of the user running monaco, or "unknown" if it can not be determined.
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return "unknown"
+}
+
+func hashPayload(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(payload))
+}
+
+// FileSink appends records as JSON lines to a file. It is safe for concurrent use.
+type FileSink struct {
+	mutex sync.Mutex
+	file  afero.File
+}
+
+// NewFileSink opens the given file for appending, creating it if it does not exist yet.
+func NewFileSink(fs afero.Fs, path string) (*FileSink, error) {
+	f, err := fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %q: %w", path, err)
+	}
+	return &FileSink{file: f}, nil
+}
+
+func (s *FileSink) Write(_ context.Context, r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// TenantSink forwards records as log records to the environment they describe a change of.
+type TenantSink struct {
+	client client.EventsClient
+}
+
+// NewTenantSink creates a TenantSink sending logs using the given client.
+func NewTenantSink(c client.EventsClient) TenantSink {
+	return TenantSink{client: c}
+}
+
+func (s TenantSink) Write(ctx context.Context, r Record) error {
+	content := fmt.Sprintf("monaco %s of %s %s", r.Action, r.Type, r.ObjectId)
+	if r.Coordinate != "" {
+		content += fmt.Sprintf(" (%s)", r.Coordinate)
+	}
+
+	attributes := map[string]string{
+		"monaco.user":        r.User,
+		"monaco.environment": r.Environment,
+		"monaco.action":      string(r.Action),
+		"monaco.type":        r.Type,
+	}
+	for k, v := range map[string]string{
+		"monaco.coordinate":  r.Coordinate,
+		"monaco.objectId":    r.ObjectId,
+		"monaco.name":        r.Name,
+		"monaco.payloadHash": r.PayloadHash,
+		"monaco.error":       r.Error,
+	} {
+		if v != "" {
+			attributes[k] = v
+		}
+	}
+	if r.Error != "" {
+		attributes["loglevel"] = "ERROR"
+	}
+
+	return s.client.SendLog(ctx, content, attributes)
+}
@@ -0,0 +1,134 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	records []Record
+}
+
+func (s *recordingSink) Write(_ context.Context, r Record) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+func TestClient_RecordsChanges(t *testing.T) {
+	a := api.API{ID: "alerting-profile"}
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := coordinate.Coordinate{Project: "p", Type: a.ID, ConfigId: "profile"}
+
+	mock := client.NewMockClient(gomock.NewController(t))
+	mock.EXPECT().UpsertConfigByName(gomock.Any(), a, "Profile", []byte("{}")).Return(client.DynatraceEntity{Id: "id-1", Name: "Profile"}, nil)
+	mock.EXPECT().DeleteSettings(gomock.Any(), "object-1").Return(errors.New("not found"))
+	mock.EXPECT().ListConfigs(gomock.Any(), a).Return(nil, nil)
+
+	sink := &recordingSink{}
+	audited := Client(mock, "prod", sink)
+	audited.(*auditingClient).now = func() time.Time { return now }
+	audited.(*auditingClient).user = "jane"
+
+	_, err := audited.UpsertConfigByName(WithCoordinate(context.TODO(), c), a, "Profile", []byte("{}"))
+	assert.NoError(t, err)
+	err = audited.DeleteSettings(context.TODO(), "object-1")
+	assert.Error(t, err)
+	_, err = audited.ListConfigs(context.TODO(), a)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Record{
+		{
+			Time:        now,
+			User:        "jane",
+			Environment: "prod",
+			Action:      Upsert,
+			Type:        a.ID,
+			Coordinate:  c.String(),
+			ObjectId:    "id-1",
+			Name:        "Profile",
+			PayloadHash: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+		},
+		{
+			Time:        now,
+			User:        "jane",
+			Environment: "prod",
+			Action:      Delete,
+			Type:        "settings",
+			ObjectId:    "object-1",
+			Error:       "not found",
+		},
+	}, sink.records, "reading calls must not be recorded")
+}
+
+func TestFileSink_AppendsRecords(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "audit.log", []byte(`{"action":"upsert"}`+"\n"), 0644))
+
+	sink, err := NewFileSink(fs, "audit.log")
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Write(context.TODO(), Record{Action: Delete, ObjectId: "id-1"}))
+	assert.NoError(t, sink.Close())
+
+	f, err := fs.Open("audit.log")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	var actions []Action
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		actions = append(actions, r.Action)
+	}
+	assert.Equal(t, []Action{Upsert, Delete}, actions, "existing records must be kept")
+}
+
+func TestTenantSink_SendsLog(t *testing.T) {
+	mock := client.NewMockClient(gomock.NewController(t))
+	mock.EXPECT().SendLog(gomock.Any(), "monaco delete of alerting-profile id-1 (p:alerting-profile:profile)", map[string]string{
+		"monaco.user":        "jane",
+		"monaco.environment": "prod",
+		"monaco.action":      "delete",
+		"monaco.type":        "alerting-profile",
+		"monaco.coordinate":  "p:alerting-profile:profile",
+		"monaco.objectId":    "id-1",
+	}).Return(nil)
+
+	err := NewTenantSink(mock).Write(context.TODO(), Record{
+		User:        "jane",
+		Environment: "prod",
+		Action:      Delete,
+		Type:        "alerting-profile",
+		Coordinate:  "p:alerting-profile:profile",
+		ObjectId:    "id-1",
+	})
+	assert.NoError(t, err)
+}
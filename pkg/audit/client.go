@@ -0,0 +1,147 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"time"
+)
+
+// auditingClient decorates a client.Client, recording every change it performs. Reading methods are passed through
+// to the decorated client.
+type auditingClient struct {
+	client.Client
+	environment string
+	user        string
+	sinks       []Sink
+	now         func() time.Time
+}
+
+var _ client.Client = (*auditingClient)(nil)
+
+// Client utilizes the decorator pattern to record all changes made using the given client on the given environment
+// to the given sinks. Failing to write a record is logged, but does not fail the change itself.
+func Client(c client.Client, environment string, sinks ...Sink) client.Client {
+	return &auditingClient{
+		Client:      c,
+		environment: environment,
+		user:        CurrentUser(),
+		sinks:       sinks,
+		now:         time.Now,
+	}
+}
+
+func (a *auditingClient) record(ctx context.Context, r Record, err error) {
+	r.Time = a.now()
+	r.User = a.user
+	r.Environment = a.environment
+	r.Coordinate = coordinateFrom(ctx)
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	for _, s := range a.sinks {
+		if err := s.Write(ctx, r); err != nil {
+			log.Warn("Failed to write audit record for %s of %s %s: %v", r.Action, r.Type, r.ObjectId, err)
+		}
+	}
+}
+
+func (a *auditingClient) UpsertConfigByName(ctx context.Context, theApi api.API, name string, payload []byte) (client.DynatraceEntity, error) {
+	entity, err := a.Client.UpsertConfigByName(ctx, theApi, name, payload)
+	a.record(ctx, Record{Action: Upsert, Type: theApi.ID, ObjectId: entity.Id, Name: name, PayloadHash: hashPayload(payload)}, err)
+	return entity, err
+}
+
+func (a *auditingClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, theApi api.API, entityId string, name string, payload []byte) (client.DynatraceEntity, error) {
+	entity, err := a.Client.UpsertConfigByNonUniqueNameAndId(ctx, theApi, entityId, name, payload)
+	a.record(ctx, Record{Action: Upsert, Type: theApi.ID, ObjectId: entity.Id, Name: name, PayloadHash: hashPayload(payload)}, err)
+	return entity, err
+}
+
+func (a *auditingClient) DeleteConfigById(ctx context.Context, theApi api.API, id string) error {
+	err := a.Client.DeleteConfigById(ctx, theApi, id)
+	a.record(ctx, Record{Action: Delete, Type: theApi.ID, ObjectId: id}, err)
+	return err
+}
+
+func (a *auditingClient) ReorderConfigs(ctx context.Context, theApi api.API, ids []string) error {
+	err := a.Client.ReorderConfigs(ctx, theApi, ids)
+	a.record(ctx, Record{Action: Update, Type: theApi.ID, Name: "order"}, err)
+	return err
+}
+
+func (a *auditingClient) UpsertSettings(ctx context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+	entity, err := a.Client.UpsertSettings(ctx, obj)
+	a.record(ctx, Record{Action: Upsert, Type: obj.SchemaId, ObjectId: entity.Id, PayloadHash: hashPayload(obj.Content)}, err)
+	return entity, err
+}
+
+func (a *auditingClient) UpsertSettingsBatch(ctx context.Context, objs []client.SettingsObject) []client.SettingsBatchResult {
+	results := a.Client.UpsertSettingsBatch(ctx, objs)
+	for i, r := range results {
+		a.record(ctx, Record{Action: Upsert, Type: objs[i].SchemaId, ObjectId: r.Entity.Id, PayloadHash: hashPayload(objs[i].Content)}, r.Err)
+	}
+	return results
+}
+
+func (a *auditingClient) DeleteSettings(ctx context.Context, objectId string) error {
+	err := a.Client.DeleteSettings(ctx, objectId)
+	a.record(ctx, Record{Action: Delete, Type: "settings", ObjectId: objectId}, err)
+	return err
+}
+
+func (a *auditingClient) UploadExtension(ctx context.Context, artifact []byte) (client.ExtensionVersion, error) {
+	v, err := a.Client.UploadExtension(ctx, artifact)
+	a.record(ctx, Record{Action: Upsert, Type: "extension", Name: v.Name, ObjectId: v.Version, PayloadHash: hashPayload(artifact)}, err)
+	return v, err
+}
+
+func (a *auditingClient) UpdateExtensionEnvironmentConfiguration(ctx context.Context, extensionName string, payload []byte) error {
+	err := a.Client.UpdateExtensionEnvironmentConfiguration(ctx, extensionName, payload)
+	a.record(ctx, Record{Action: Update, Type: "extension", Name: extensionName, PayloadHash: hashPayload(payload)}, err)
+	return err
+}
+
+func (a *auditingClient) UpsertExtensionMonitoringConfiguration(ctx context.Context, extensionName string, c client.ExtensionMonitoringConfiguration) (client.DynatraceEntity, error) {
+	entity, err := a.Client.UpsertExtensionMonitoringConfiguration(ctx, extensionName, c)
+	a.record(ctx, Record{Action: Upsert, Type: extensionName, ObjectId: entity.Id, PayloadHash: hashPayload(c.Value)}, err)
+	return entity, err
+}
+
+func (a *auditingClient) UpdateDashboardShareSettings(ctx context.Context, dashboardId string, shareSettings []byte) error {
+	err := a.Client.UpdateDashboardShareSettings(ctx, dashboardId, shareSettings)
+	a.record(ctx, Record{Action: Update, Type: "dashboard-share-settings", ObjectId: dashboardId, PayloadHash: hashPayload(shareSettings)}, err)
+	return err
+}
+
+func (a *auditingClient) AddTags(ctx context.Context, entitySelector string, tags []client.Tag) (int, error) {
+	matched, err := a.Client.AddTags(ctx, entitySelector, tags)
+	for _, t := range tags {
+		a.record(ctx, Record{Action: Update, Type: "tags", ObjectId: entitySelector, Name: t.String()}, err)
+	}
+	return matched, err
+}
+
+func (a *auditingClient) DeleteTag(ctx context.Context, entitySelector string, tag client.Tag) (int, error) {
+	matched, err := a.Client.DeleteTag(ctx, entitySelector, tag)
+	a.record(ctx, Record{Action: Delete, Type: "tags", ObjectId: entitySelector, Name: tag.String()}, err)
+	return matched, err
+}
@@ -18,9 +18,12 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/throttle"
@@ -29,8 +32,10 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/oauth2/endpoints"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
 	version2 "github.com/dynatrace/dynatrace-configuration-as-code/pkg/version"
+	"github.com/spf13/afero"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"net"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -46,19 +51,19 @@ type ConfigClient interface {
 	// It calls the underlying GET endpoint of the API. E.g. for alerting profiles this would be:
 	//    GET <environment-url>/api/config/v1/alertingProfiles
 	// The result is expressed using a list of Value (id and name tuples).
-	ListConfigs(a api.API) (values []Value, err error)
+	ListConfigs(ctx context.Context, a api.API) (values []Value, err error)
 
 	// ReadConfigById reads a Dynatrace config identified by id from the given API.
 	// It calls the underlying GET endpoint for the API. E.g. for alerting profiles this would be:
 	//    GET <environment-url>/api/config/v1/alertingProfiles/<id> ... to get the alerting profile
-	ReadConfigById(a api.API, id string) (json []byte, err error)
+	ReadConfigById(ctx context.Context, a api.API, id string) (json []byte, err error)
 
 	// UpsertConfigByName creates a given Dynatrace config if it doesn't exist and updates it otherwise using its name.
 	// It calls the underlying GET, POST, and PUT endpoints for the API. E.g. for alerting profiles this would be:
 	//    GET <environment-url>/api/config/v1/alertingProfiles ... to check if the config is already available
 	//    POST <environment-url>/api/config/v1/alertingProfiles ... afterwards, if the config is not yet available
 	//    PUT <environment-url>/api/config/v1/alertingProfiles/<id> ... instead of POST, if the config is already available
-	UpsertConfigByName(a api.API, name string, payload []byte) (entity DynatraceEntity, err error)
+	UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (entity DynatraceEntity, err error)
 
 	// UpsertConfigByNonUniqueNameAndId creates a given Dynatrace config if it doesn't exist and updates it based on specific rules if it does not
 	// - if only one config with the name exist, behave like any other type and just update this entity
@@ -67,17 +72,23 @@ type ConfigClient interface {
 	// It calls the underlying GET and PUT endpoints for the API. E.g. for alerting profiles this would be:
 	//	 GET <environment-url>/api/config/v1/alertingProfiles ... to check if the config is already available
 	//	 PUT <environment-url>/api/config/v1/alertingProfiles/<id> ... with the given (or found by unique name) entity ID
-	UpsertConfigByNonUniqueNameAndId(a api.API, entityID string, name string, payload []byte) (entity DynatraceEntity, err error)
+	UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityID string, name string, payload []byte) (entity DynatraceEntity, err error)
 
 	// DeleteConfigById removes a given config for a given API using its id.
 	// It calls the DELETE endpoint for the API. E.g. for alerting profiles this would be:
 	//    DELETE <environment-url>/api/config/v1/alertingProfiles/<id> ... to delete the config
-	DeleteConfigById(a api.API, id string) error
+	DeleteConfigById(ctx context.Context, a api.API, id string) error
 
 	// ConfigExistsByName checks if a config with the given name exists for the given API.
 	// It calls the underlying GET endpoint for the API. E.g. for alerting profiles this would be:
 	//    GET <environment-url>/api/config/v1/alertingProfiles
-	ConfigExistsByName(a api.API, name string) (exists bool, id string, err error)
+	ConfigExistsByName(ctx context.Context, a api.API, name string) (exists bool, id string, err error)
+
+	// ReorderConfigs applies the given order of config ids to an [api.API.Ordered] API. Configs not part of the
+	// given ids keep their position relative to each other.
+	// It calls the order endpoint of the API. E.g. for request naming rules this would be:
+	//    PUT <environment-url>/api/config/v1/service/requestNaming/order
+	ReorderConfigs(ctx context.Context, a api.API, ids []string) error
 }
 
 // DownloadSettingsObject is the response type for the ListSettings operation
@@ -88,6 +99,22 @@ type DownloadSettingsObject struct {
 	ObjectId      string          `json:"objectId"`
 	Scope         string          `json:"scope"`
 	Value         json.RawMessage `json:"value"`
+	// ModificationInfo holds when and by whom the object was created and last modified. It is nil if the environment
+	// does not provide it.
+	ModificationInfo *SettingsModificationInfo `json:"modificationInfo,omitempty"`
+}
+
+// SettingsModificationInfo holds the modification metadata of a settings 2.0 object
+type SettingsModificationInfo struct {
+	CreatedBy        string `json:"createdBy"`
+	CreatedTime      int64  `json:"createdTime"`
+	LastModifiedBy   string `json:"lastModifiedBy"`
+	LastModifiedTime int64  `json:"lastModifiedTime"`
+}
+
+// LastModified returns the time of the last modification
+func (m SettingsModificationInfo) LastModified() time.Time {
+	return time.UnixMilli(m.LastModifiedTime)
 }
 
 // ErrSettingNotFound is returned when no settings 2.0 object could be found
@@ -108,23 +135,31 @@ type SettingsClient interface {
 	// UpsertSettings either creates the supplied object, or updates an existing one.
 	// First, we try to find the external-id of the object. If we can't find it, we create the object, if we find it, we
 	// update the object.
-	UpsertSettings(SettingsObject) (DynatraceEntity, error)
+	UpsertSettings(ctx context.Context, obj SettingsObject) (DynatraceEntity, error)
+
+	// UpsertSettingsBatch creates or updates the supplied objects, posting them in a single request where possible.
+	// The objects must not depend on each other. One result is returned per object, in the order of the objects.
+	// Objects the batched request failed for are upserted one by one, so their results are the same as UpsertSettings.
+	UpsertSettingsBatch(ctx context.Context, objs []SettingsObject) []SettingsBatchResult
 
 	// ListSchemas returns all schemas that the Dynatrace environment reports
-	ListSchemas() (SchemaList, error)
+	ListSchemas(ctx context.Context) (SchemaList, error)
+
+	// GetSchema returns the JSON definition of the latest version of the given schema
+	GetSchema(ctx context.Context, schemaId string) ([]byte, error)
 
 	// ListSettings returns all settings objects for a given schema.
-	ListSettings(string, ListSettingsOptions) ([]DownloadSettingsObject, error)
+	ListSettings(ctx context.Context, schemaId string, opts ListSettingsOptions) ([]DownloadSettingsObject, error)
 
 	// GetSettingById returns the setting with the given object ID
-	GetSettingById(string) (*DownloadSettingsObject, error)
+	GetSettingById(ctx context.Context, objectId string) (*DownloadSettingsObject, error)
 
 	// DeleteSettings deletes a settings object giving its object ID
-	DeleteSettings(string) error
+	DeleteSettings(ctx context.Context, objectId string) error
 }
 
 // defaultListSettingsFields  are the fields we are interested in when getting setting objects
-const defaultListSettingsFields = "objectId,value,externalId,schemaVersion,schemaId,scope"
+const defaultListSettingsFields = "objectId,value,externalId,schemaVersion,schemaId,scope,modificationInfo"
 
 // reducedListSettingsFields are the fields we are interested in when getting settings objects but don't care about the
 // actual value payload
@@ -151,6 +186,14 @@ type ListSettingsOptions struct {
 // ListSettingsFilter can be used to filter fetched settings objects with custom criteria, e.g. o.ExternalId == ""
 type ListSettingsFilter func(DownloadSettingsObject) bool
 
+// ListEntitiesOptions are additional options for the ListEntities method
+// of the Entities client
+type ListEntitiesOptions struct {
+	// From restricts the result to entities seen after the given time. If not set, all entities seen
+	// within the last five weeks are returned.
+	From time.Time
+}
+
 // EntitiesClient is the abstraction layer for read-only operations on the Dynatrace Entities v2 API.
 // Its design is intentionally not dependent on Monaco objects.
 //
@@ -162,10 +205,104 @@ type ListSettingsFilter func(DownloadSettingsObject) bool
 type EntitiesClient interface {
 
 	// ListEntitiesTypes returns all entities types
-	ListEntitiesTypes() ([]EntitiesType, error)
+	ListEntitiesTypes(ctx context.Context) ([]EntitiesType, error)
 
 	// ListEntities returns all entities objects for a given type.
-	ListEntities(EntitiesType) ([]string, error)
+	ListEntities(ctx context.Context, entitiesType EntitiesType, opts ListEntitiesOptions) ([]string, error)
+
+	// EntityExists checks whether an entity with the given ID exists.
+	EntityExists(ctx context.Context, entityId string) (bool, error)
+
+	// ListEntityIds returns the IDs of all entities matching the given [entity selector].
+	//
+	// [entity selector]: https://www.dynatrace.com/support/help/dynatrace-api/environment-api/entity-v2/entity-selector
+	ListEntityIds(ctx context.Context, entitySelector string) ([]string, error)
+}
+
+// EventsClient is the abstraction layer for sending events to Dynatrace.
+//
+// This interface exclusively accesses the [events api] and [business events api] of Dynatrace.
+//
+// [events api]: https://www.dynatrace.com/support/help/dynatrace-api/environment-api/events-v2
+// [business events api]: https://www.dynatrace.com/support/help/dynatrace-api/environment-api/business-events
+type EventsClient interface {
+
+	// SendEvent sends the given event to the environment.
+	SendEvent(ctx context.Context, event Event) error
+
+	// SendLog ingests a log record with the given content and attributes into the environment.
+	SendLog(ctx context.Context, content string, attributes map[string]string) error
+}
+
+// ExtensionsClient is the abstraction layer for the lifecycle of Extensions 2.0: uploading extension artifacts,
+// activating extension versions and managing their monitoring configurations.
+//
+// This interface exclusively accesses the [extensions 2.0 api] of Dynatrace.
+//
+// [extensions 2.0 api]: https://www.dynatrace.com/support/help/dynatrace-api/environment-api/extensions-20
+type ExtensionsClient interface {
+
+	// ListExtensionVersions returns all versions of the given extension available in the environment.
+	ListExtensionVersions(ctx context.Context, extensionName string) ([]string, error)
+
+	// UploadExtension uploads the given zip artifact of an extension and returns the uploaded extension version.
+	UploadExtension(ctx context.Context, artifact []byte) (ExtensionVersion, error)
+
+	// UpdateExtensionEnvironmentConfiguration activates an extension version in the environment. The payload
+	// defines the version to activate, e.g. {"version": "1.2.3"}.
+	UpdateExtensionEnvironmentConfiguration(ctx context.Context, extensionName string, payload []byte) error
+
+	// ListExtensionMonitoringConfigurations returns all monitoring configurations of the given extension.
+	ListExtensionMonitoringConfigurations(ctx context.Context, extensionName string) ([]ExtensionMonitoringConfiguration, error)
+
+	// UpsertExtensionMonitoringConfiguration creates the given monitoring configuration of an extension, or updates
+	// it if its ObjectId is set.
+	UpsertExtensionMonitoringConfiguration(ctx context.Context, extensionName string, c ExtensionMonitoringConfiguration) (DynatraceEntity, error)
+}
+
+// DashboardSharingClient is the abstraction layer for the share settings of classic dashboards, which are not part
+// of the dashboard itself.
+//
+// This interface exclusively accesses the share settings endpoint of the [dashboards api] of Dynatrace.
+//
+// [dashboards api]: https://www.dynatrace.com/support/help/dynatrace-api/configuration-api/dashboards-api
+type DashboardSharingClient interface {
+
+	// GetDashboardShareSettings returns the share settings of the given dashboard.
+	GetDashboardShareSettings(ctx context.Context, dashboardId string) ([]byte, error)
+
+	// UpdateDashboardShareSettings replaces the share settings of the given dashboard.
+	UpdateDashboardShareSettings(ctx context.Context, dashboardId string, shareSettings []byte) error
+}
+
+// Tag is a custom tag of a monitored entity. Tags without value only have a Key.
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// String returns the tag in the notation of entity selectors, e.g. 'owner:team-a'.
+func (t Tag) String() string {
+	if t.Value == "" {
+		return t.Key
+	}
+	return t.Key + ":" + t.Value
+}
+
+// TagsClient is the abstraction layer for managing custom tags of monitored entities.
+//
+// This interface exclusively accesses the [custom tags api] of Dynatrace.
+//
+// [custom tags api]: https://www.dynatrace.com/support/help/dynatrace-api/environment-api/custom-tags
+type TagsClient interface {
+
+	// AddTags adds the given tags to all entities matching the given entity selector and returns the number of
+	// matched entities.
+	AddTags(ctx context.Context, entitySelector string, tags []Tag) (int, error)
+
+	// DeleteTag removes the given tag from all entities matching the given entity selector and returns the number of
+	// matched entities. Tags are only removed if key and value match.
+	DeleteTag(ctx context.Context, entitySelector string, tag Tag) (int, error)
 }
 
 //go:generate mockgen -source=client.go -destination=client_mock.go -package=client DynatraceClient
@@ -182,6 +319,10 @@ type Client interface {
 	ConfigClient
 	SettingsClient
 	EntitiesClient
+	EventsClient
+	ExtensionsClient
+	DashboardSharingClient
+	TagsClient
 }
 
 // DynatraceClient is the default implementation of the HTTP
@@ -212,6 +353,12 @@ type DynatraceClient struct {
 	settingsObjectAPIPath string
 
 	retrySettings rest.RetrySettings
+
+	// pagination defines how paginated lists of settings objects and entities are requested
+	pagination Pagination
+
+	// nameLookupCache, if set, caches the configs listed to look up existing configs by name
+	nameLookupCache *nameLookupCache
 }
 
 // OauthCredentials holds information for authenticating to Dynatrace
@@ -221,12 +368,16 @@ type OauthCredentials struct {
 	ClientSecret string
 	TokenURL     string
 	Scopes       []string
+	// Audience is optionally sent as 'audience' parameter of token requests
+	Audience string
 }
 
 var (
 	_ EntitiesClient = (*DynatraceClient)(nil)
+	_ EventsClient   = (*DynatraceClient)(nil)
 	_ SettingsClient = (*DynatraceClient)(nil)
 	_ ConfigClient   = (*DynatraceClient)(nil)
+	_ TagsClient     = (*DynatraceClient)(nil)
 	_ Client         = (*DynatraceClient)(nil)
 )
 
@@ -245,8 +396,9 @@ func WithServerVersion(serverVersion version.Version) func(client *DynatraceClie
 }
 
 // WithAutoServerVersion can be used to let the client automatically determine the Dynatrace server version
-// during creation using newDynatraceClient. If the server version is already known WithServerVersion should be used
-func WithAutoServerVersion() func(client *DynatraceClient) {
+// during creation using newDynatraceClient, requesting it with the given context. If the server version is already
+// known WithServerVersion should be used
+func WithAutoServerVersion(ctx context.Context) func(client *DynatraceClient) {
 	return func(d *DynatraceClient) {
 		var serverVersion version.Version
 		var err error
@@ -255,7 +407,7 @@ func WithAutoServerVersion() func(client *DynatraceClient) {
 			// so this call would need to be "redirected" to the second gen URL, which do not currently resolve
 			d.serverVersion = version.UnknownVersion
 		} else {
-			serverVersion, err = GetDynatraceVersion(d.clientClassic, d.environmentURLClassic)
+			serverVersion, err = GetDynatraceVersion(ctx, d.clientClassic, d.environmentURLClassic)
 		}
 		if err != nil {
 			log.Warn("Unable to determine Dynatrace server version: %v", err)
@@ -266,6 +418,199 @@ func WithAutoServerVersion() func(client *DynatraceClient) {
 	}
 }
 
+// WithNameLookupCache makes the DynatraceClient list the configs of each API only once to look up existing configs by
+// name when upserting configs, instead of listing them for every upsert. As configs created or deleted by others are
+// not noticed afterward, it must only be used for the duration of a deployment.
+func WithNameLookupCache() func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		d.nameLookupCache = newNameLookupCache()
+	}
+}
+
+// Pagination defines how paginated lists of settings objects and entities are requested.
+type Pagination struct {
+	// SettingsPageSize is the number of settings objects requested per page. If 0, the default page size is used.
+	SettingsPageSize int
+	// EntitiesPageSize is the number of entities requested per page. If 0, the default page size is used.
+	EntitiesPageSize int
+	// Prefetch defines whether the next page is already requested while the current page is processed
+	Prefetch bool
+}
+
+// PaginationFromEnvironment returns the Pagination defined by the MONACO_SETTINGS_PAGE_SIZE and
+// MONACO_ENTITIES_PAGE_SIZE environment variables and the featureflags.PaginationPrefetch feature flag.
+func PaginationFromEnvironment() Pagination {
+	return Pagination{
+		SettingsPageSize: environment.GetEnvValueInt(environment.SettingsPageSizeEnvKey),
+		EntitiesPageSize: environment.GetEnvValueInt(environment.EntitiesPageSizeEnvKey),
+		Prefetch:         featureflags.PaginationPrefetch().Enabled(),
+	}
+}
+
+// WithPagination sets how the DynatraceClient requests paginated lists of settings objects and entities
+func WithPagination(pagination Pagination) func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		d.pagination = pagination
+	}
+}
+
+func (p Pagination) settingsPageSize() string {
+	if p.SettingsPageSize > 0 {
+		return strconv.Itoa(p.SettingsPageSize)
+	}
+	return defaultPageSize
+}
+
+func (p Pagination) entitiesPageSize() string {
+	if p.EntitiesPageSize > 0 {
+		return strconv.Itoa(p.EntitiesPageSize)
+	}
+	return defaultPageSizeEntities
+}
+
+// HTTPTimeouts defines timeouts applied to the HTTP calls of a DynatraceClient. A zero value means no timeout.
+type HTTPTimeouts struct {
+	// Connect is the maximum time to wait for a connection to be established
+	Connect time.Duration
+	// Request is the maximum time a single request, including reading the response body, may take
+	Request time.Duration
+}
+
+// WithHTTPTimeouts sets the given timeouts on all HTTP clients used by the DynatraceClient
+func WithHTTPTimeouts(timeouts HTTPTimeouts) func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		for _, c := range []*http.Client{d.client, d.clientClassic} {
+			if c != nil {
+				ApplyHTTPTimeouts(c, timeouts)
+			}
+		}
+	}
+}
+
+// ApplyHTTPTimeouts sets the given timeouts on an HTTP client created by NewOAuthClient or using a TokenAuthTransport
+func ApplyHTTPTimeouts(c *http.Client, timeouts HTTPTimeouts) {
+	if timeouts.Request > 0 {
+		c.Timeout = timeouts.Request
+	}
+	if timeouts.Connect > 0 {
+		setConnectTimeout(c, timeouts.Connect)
+	}
+}
+
+// WithClientCertificate presents the given certificate when establishing TLS connections, as required by gateways
+// terminating mutual TLS in front of an environment. Options replacing the transports of the client must be applied before.
+func WithClientCertificate(cert tls.Certificate) func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		done := make(map[*http.Client]struct{}, 2)
+		for _, c := range []*http.Client{d.client, d.clientClassic} {
+			if _, ok := done[c]; c == nil || ok {
+				continue
+			}
+			done[c] = struct{}{}
+
+			switch t := c.Transport.(type) {
+			case *TokenAuthTransport:
+				t.RoundTripper = withClientCertificate(t.RoundTripper, cert)
+			case *oauth2.Transport:
+				t.Base = withClientCertificate(t.Base, cert)
+			default:
+				log.Warn("Unable to apply client certificate to HTTP transport of type %T", c.Transport)
+			}
+		}
+	}
+}
+
+// withClientCertificate returns a copy of the given base transport presenting the given certificate. A nil base is
+// treated as http.DefaultTransport, which must not be modified.
+func withClientCertificate(base http.RoundTripper, cert tls.Certificate) http.RoundTripper {
+	var t *http.Transport
+	switch b := base.(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = b.Clone()
+	default:
+		log.Warn("Unable to apply client certificate to HTTP transport of type %T", base)
+		return base
+	}
+
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	t.TLSClientConfig.Certificates = append(t.TLSClientConfig.Certificates, cert)
+	return t
+}
+
+// WithRequestAdditions adds the headers and query parameters of the given additions to all requests of the
+// DynatraceClient. See rest.RequestAdditionsTransport for details. Options replacing the transports of the client must
+// be applied before.
+func WithRequestAdditions(additions []rest.RequestAddition) func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		if len(additions) == 0 {
+			return
+		}
+
+		wrapped := make(map[*http.Client]struct{}, 2)
+		for _, c := range []*http.Client{d.client, d.clientClassic} {
+			if _, done := wrapped[c]; c == nil || done {
+				continue
+			}
+			wrapped[c] = struct{}{}
+
+			switch t := c.Transport.(type) {
+			case *TokenAuthTransport:
+				t.RoundTripper = rest.NewRequestAdditionsTransport(t.RoundTripper, additions)
+			case *oauth2.Transport:
+				t.Base = rest.NewRequestAdditionsTransport(t.Base, additions)
+			default:
+				log.Warn("Unable to add headers and query parameters to HTTP transport of type %T", c.Transport)
+			}
+		}
+	}
+}
+
+// WithHTTPCache caches successful GET responses of the DynatraceClient in the given directory for the given time.
+// See rest.CachingTransport for details. Options replacing the transports of the client must be applied before.
+func WithHTTPCache(fs afero.Fs, dir string, ttl time.Duration) func(client *DynatraceClient) {
+	return func(d *DynatraceClient) {
+		cached := make(map[*http.Client]struct{}, 2)
+		for _, c := range []*http.Client{d.client, d.clientClassic} {
+			if _, done := cached[c]; c == nil || done {
+				continue
+			}
+			cached[c] = struct{}{}
+
+			switch t := c.Transport.(type) {
+			case *TokenAuthTransport:
+				t.RoundTripper = rest.NewCachingTransport(t.RoundTripper, fs, dir, ttl)
+			case *oauth2.Transport:
+				t.Base = rest.NewCachingTransport(t.Base, fs, dir, ttl)
+			default:
+				log.Warn("Unable to cache responses of HTTP transport of type %T", c.Transport)
+			}
+		}
+	}
+}
+
+// setConnectTimeout replaces the base transport of the given client with one that uses the given timeout for
+// establishing connections. Only the transports created by this package are supported.
+func setConnectTimeout(c *http.Client, timeout time.Duration) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+
+	switch t := c.Transport.(type) {
+	case *TokenAuthTransport:
+		t.RoundTripper = base
+	case *oauth2.Transport:
+		t.Base = base
+	default:
+		log.Warn("Unable to apply connect timeout to HTTP transport of type %T", c.Transport)
+	}
+}
+
 // TokenAuthTransport should be used to enable a client
 // to use dynatrace token authorization
 type TokenAuthTransport struct {
@@ -311,7 +656,8 @@ func NewTokenAuthClient(token string) *http.Client {
 	return &http.Client{Transport: NewTokenAuthTransport(nil, token)}
 }
 
-// NewOAuthClient creates a new HTTP client that supports OAuth2 client credentials based authorization
+// NewOAuthClient creates a new HTTP client that supports OAuth2 client credentials based authorization. Access tokens
+// are requested lazily and requested again once they expire, so that long-running commands keep working.
 func NewOAuthClient(ctx context.Context, oauthConfig OauthCredentials) *http.Client {
 
 	tokenUrl := oauthConfig.TokenURL
@@ -326,6 +672,9 @@ func NewOAuthClient(ctx context.Context, oauthConfig OauthCredentials) *http.Cli
 		TokenURL:     tokenUrl,
 		Scopes:       oauthConfig.Scopes,
 	}
+	if oauthConfig.Audience != "" {
+		config.EndpointParams = url.Values{"audience": {oauthConfig.Audience}}
+	}
 
 	return config.Client(ctx)
 }
@@ -337,31 +686,26 @@ const (
 	settingsObjectAPIPathPlatform = "/platform/classic/environment-api/v2/settings/objects"
 )
 
-// NewPlatformClient creates a new dynatrace client to be used for platform enabled environments
-func NewPlatformClient(dtURL string, token string, oauthCredentials OauthCredentials, opts ...func(dynatraceClient *DynatraceClient)) (*DynatraceClient, error) {
+// NewPlatformClient creates a new dynatrace client to be used for platform enabled environments. The given context is
+// used to request the classic environment URL and to fetch OAuth tokens.
+func NewPlatformClient(ctx context.Context, dtURL string, token string, oauthCredentials OauthCredentials, opts ...func(dynatraceClient *DynatraceClient)) (*DynatraceClient, error) {
 	dtURL = strings.TrimSuffix(dtURL, "/")
 	if err := validateURL(dtURL); err != nil {
 		return nil, err
 	}
 
 	tokenClient := NewTokenAuthClient(token)
-	oauthClient := NewOAuthClient(context.TODO(), oauthCredentials)
-
-	classicURL, err := GetDynatraceClassicURL(oauthClient, dtURL)
-	if err != nil {
-		log.Error("Unable to determine Dynatrace classic environment URL: %v", err)
-		return nil, err
-	}
+	oauthClient := NewOAuthClient(ctx, oauthCredentials)
 
 	d := &DynatraceClient{
 		serverVersion:         version.Version{},
 		environmentURL:        dtURL,
-		environmentURLClassic: classicURL,
 		client:                oauthClient,
 		clientClassic:         tokenClient,
 		retrySettings:         rest.DefaultRetrySettings,
 		settingsSchemaAPIPath: settingsSchemaAPIPathPlatform,
 		settingsObjectAPIPath: settingsObjectAPIPathPlatform,
+		pagination:            PaginationFromEnvironment(),
 	}
 
 	for _, o := range opts {
@@ -369,6 +713,15 @@ func NewPlatformClient(dtURL string, token string, oauthCredentials OauthCredent
 			o(d)
 		}
 	}
+
+	// the options are applied first, so that the classic URL is requested with e.g. the headers and client certificate
+	// they add
+	classicURL, err := GetDynatraceClassicURL(ctx, d.client, dtURL)
+	if err != nil {
+		log.Error("Unable to determine Dynatrace classic environment URL: %v", err)
+		return nil, err
+	}
+	d.environmentURLClassic = classicURL
 	return d, nil
 }
 
@@ -390,6 +743,7 @@ func NewClassicClient(dtURL string, token string, opts ...func(dynatraceClient *
 		retrySettings:         rest.DefaultRetrySettings,
 		settingsSchemaAPIPath: settingsSchemaAPIPathClassic,
 		settingsObjectAPIPath: settingsObjectAPIPathClassic,
+		pagination:            PaginationFromEnvironment(),
 	}
 
 	for _, o := range opts {
@@ -420,7 +774,7 @@ func isNewDynatraceTokenFormat(token string) bool {
 	return strings.HasPrefix(token, "dt0c01.") && strings.Count(token, ".") == 2
 }
 
-func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, error) {
+func (d *DynatraceClient) UpsertSettings(ctx context.Context, obj SettingsObject) (DynatraceEntity, error) {
 
 	// special handling for updating settings 2.0 objects on tenants with version pre 1.262.0
 	// Tenants with versions < 1.262 are not able to handle updates of existing
@@ -428,12 +782,12 @@ func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, e
 	// So we check if the object with originObjectID already exists, if yes and the tenant is older than 1.262
 	// then we cannot perform the upsert operation
 	if !d.serverVersion.Invalid() && d.serverVersion.SmallerThan(version.Version{Major: 1, Minor: 262, Patch: 0}) {
-		fetchedSettingObj, err := d.GetSettingById(obj.OriginObjectId)
+		fetchedSettingObj, err := d.GetSettingById(ctx, obj.OriginObjectId)
 		if err != nil && !errors.Is(err, ErrSettingNotFound) {
 			return DynatraceEntity{}, fmt.Errorf("unable to fetch settings object with object id %q: %w", obj.OriginObjectId, err)
 		}
 		if fetchedSettingObj != nil {
-			log.Warn("Unable to update Settings 2.0 object of schema %q and object id %q on Dynatrace environment with a version < 1.262.0", obj.SchemaId, obj.OriginObjectId)
+			log.WithCtxFields(ctx).Warn("Unable to update Settings 2.0 object of schema %q and object id %q on Dynatrace environment with a version < 1.262.0", obj.SchemaId, obj.OriginObjectId)
 			return DynatraceEntity{
 				Id:   fetchedSettingObj.ObjectId,
 				Name: fetchedSettingObj.ObjectId,
@@ -456,7 +810,15 @@ func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, e
 
 	requestUrl := d.environmentURL + d.settingsObjectAPIPath
 
-	resp, err := rest.SendWithRetryWithInitialTry(d.client, rest.Post, obj.Id, requestUrl, payload, d.retrySettings.Normal)
+	resp, err := rest.SendWithRetryWithInitialTry(ctx, d.client, rest.Post, obj.Id, requestUrl, payload, d.retrySettings.Normal)
+	if violations, found := parseSettingsConstraintViolations(resp.Body); found && !success(resp) {
+		return DynatraceEntity{}, SettingsConstraintViolationError{
+			ExternalId: externalId,
+			StatusCode: resp.StatusCode,
+			Message:    violations.Message,
+			Violations: violations.ConstraintViolationList,
+		}
+	}
 	if err != nil {
 		return DynatraceEntity{}, fmt.Errorf("failed to upsert dynatrace obj: %w", err)
 	}
@@ -470,18 +832,85 @@ func (d *DynatraceClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, e
 		return DynatraceEntity{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	log.Debug("\tCreated/Updated object %s (%s) with externalId %s", obj.Id, obj.SchemaId, externalId)
+	log.WithCtxFields(ctx).Debug("\tCreated/Updated object %s (%s) with externalId %s", obj.Id, obj.SchemaId, externalId)
 	return entity, nil
 }
 
-func (d *DynatraceClient) ListConfigs(api api.API) (values []Value, err error) {
+func (d *DynatraceClient) UpsertSettingsBatch(ctx context.Context, objs []SettingsObject) []SettingsBatchResult {
+	results := make([]SettingsBatchResult, len(objs))
+
+	var batched []int
+	var requests []settingsRequest
+	for i, obj := range objs {
+		// objects requiring special handling by UpsertSettings are not batched
+		if obj.SchemaId == "builtin:oneagent.features" || (!d.serverVersion.Invalid() && d.serverVersion.SmallerThan(version.Version{Major: 1, Minor: 262, Patch: 0})) {
+			results[i].Entity, results[i].Err = d.UpsertSettings(ctx, obj)
+			continue
+		}
+
+		req, err := newSettingsRequest(obj, idutils.GenerateExternalID(obj.SchemaId, obj.Id))
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to build settings object for upsert: %w", err)
+			continue
+		}
+		batched = append(batched, i)
+		requests = append(requests, req)
+	}
+
+	if len(batched) == 0 {
+		return results
+	}
+
+	parsed, err := d.postSettingsBatch(ctx, requests)
+	if err != nil {
+		log.WithCtxFields(ctx).Debug("Failed to upsert batch of %d settings objects, upserting them one by one: %v", len(batched), err)
+	}
+
+	for j, i := range batched {
+		if parsed != nil && parsed[j].Code >= 200 && parsed[j].Code <= 299 {
+			results[i].Entity = DynatraceEntity{Id: parsed[j].ObjectId, Name: parsed[j].ObjectId}
+			log.WithCtxFields(ctx).Debug("\tCreated/Updated object %s (%s) with externalId %s", objs[i].Id, objs[i].SchemaId, requests[j].ExternalId)
+			continue
+		}
+		// retrying the object on its own reports its error, or upserts it if it only failed transiently
+		results[i].Entity, results[i].Err = d.UpsertSettings(ctx, objs[i])
+	}
+	return results
+}
+
+// postSettingsBatch posts the given objects in a single request, returning the result of each object. No error is
+// returned if only some objects failed.
+func (d *DynatraceClient) postSettingsBatch(ctx context.Context, requests []settingsRequest) ([]batchPostResponse, error) {
+	payload, err := marshalSettingsRequests(requests)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := rest.Post(ctx, d.client, d.environmentURL+d.settingsObjectAPIPath, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// the settings api responds with results per object for successful, partially successful and invalid requests
+	parsed, err := parseBatchPostResponse(resp.Body, len(requests))
+	if err != nil {
+		return nil, fmt.Errorf("HTTP %d: %w", resp.StatusCode, err)
+	}
+	return parsed, nil
+}
+
+func (d *DynatraceClient) ListConfigs(ctx context.Context, api api.API) (values []Value, err error) {
 
 	fullUrl := api.CreateURL(d.environmentURLClassic)
-	values, err = getExistingValuesFromEndpoint(d.clientClassic, api, fullUrl, d.retrySettings)
+	values, err = getExistingValuesFromEndpoint(ctx, d.clientClassic, api, fullUrl, d.retrySettings)
 	return values, err
 }
 
-func (d *DynatraceClient) ReadConfigById(api api.API, id string) (json []byte, err error) {
+func (d *DynatraceClient) ReadConfigById(ctx context.Context, api api.API, id string) (json []byte, err error) {
+	if isKeyUserActionApi(api) {
+		return readKeyUserActionById(ctx, d.clientClassic, api, api.CreateURL(d.environmentURLClassic), id)
+	}
+
 	var dtUrl string
 	isSingleConfigurationApi := api.SingleConfiguration
 
@@ -491,7 +920,7 @@ func (d *DynatraceClient) ReadConfigById(api api.API, id string) (json []byte, e
 		dtUrl = api.CreateURL(d.environmentURLClassic) + "/" + url.PathEscape(id)
 	}
 
-	response, err := rest.Get(d.clientClassic, dtUrl)
+	response, err := rest.Get(ctx, d.clientClassic, dtUrl)
 
 	if err != nil {
 		return nil, err
@@ -504,28 +933,54 @@ func (d *DynatraceClient) ReadConfigById(api api.API, id string) (json []byte, e
 	return response.Body, nil
 }
 
-func (d *DynatraceClient) DeleteConfigById(api api.API, id string) error {
+func (d *DynatraceClient) DeleteConfigById(ctx context.Context, api api.API, id string) error {
 
-	return rest.DeleteConfig(d.clientClassic, api.CreateURL(d.environmentURLClassic), id)
+	return rest.DeleteConfig(ctx, d.clientClassic, api.CreateURL(d.environmentURLClassic), id)
 }
 
-func (d *DynatraceClient) ConfigExistsByName(api api.API, name string) (exists bool, id string, err error) {
+func (d *DynatraceClient) ConfigExistsByName(ctx context.Context, api api.API, name string) (exists bool, id string, err error) {
 	apiURL := api.CreateURL(d.environmentURLClassic)
-	existingObjectId, err := getObjectIdIfAlreadyExists(d.clientClassic, api, apiURL, name, d.retrySettings)
+	existingObjectId, err := getObjectIdIfAlreadyExists(ctx, d.clientClassic, api, apiURL, name, d.retrySettings, nil)
 	return existingObjectId != "", existingObjectId, err
 }
 
-func (d *DynatraceClient) UpsertConfigByName(api api.API, name string, payload []byte) (entity DynatraceEntity, err error) {
+func (d *DynatraceClient) UpsertConfigByName(ctx context.Context, api api.API, name string, payload []byte) (entity DynatraceEntity, err error) {
 
 	if api.ID == "extension" {
 		fullUrl := api.CreateURL(d.environmentURLClassic)
-		return uploadExtension(d.clientClassic, fullUrl, name, payload)
+		return uploadExtension(ctx, d.clientClassic, fullUrl, name, payload)
 	}
-	return upsertDynatraceObject(d.clientClassic, d.environmentURLClassic, name, api, payload, d.retrySettings)
+	return upsertDynatraceObject(ctx, d.clientClassic, d.environmentURLClassic, name, api, payload, d.retrySettings, d.nameLookupCache)
 }
 
-func (d *DynatraceClient) UpsertConfigByNonUniqueNameAndId(api api.API, entityId string, name string, payload []byte) (entity DynatraceEntity, err error) {
-	return upsertDynatraceEntityByNonUniqueNameAndId(d.clientClassic, d.environmentURLClassic, entityId, name, api, payload, d.retrySettings)
+func (d *DynatraceClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, api api.API, entityId string, name string, payload []byte) (entity DynatraceEntity, err error) {
+	return upsertDynatraceEntityByNonUniqueNameAndId(ctx, d.clientClassic, d.environmentURLClassic, entityId, name, api, payload, d.retrySettings, d.nameLookupCache)
+}
+
+func (d *DynatraceClient) ReorderConfigs(ctx context.Context, api api.API, ids []string) error {
+	type idValue struct {
+		Id string `json:"id"`
+	}
+	body := struct {
+		Values []idValue `json:"values"`
+	}{Values: make([]idValue, len(ids))}
+	for i, id := range ids {
+		body.Values[i] = idValue{Id: id}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := rest.Put(ctx, d.clientClassic, api.CreateURL(d.environmentURLClassic)+"/order", payload)
+	if err != nil {
+		return fmt.Errorf("failed to reorder configs of api %v: %w", api.ID, err)
+	}
+	if !success(resp) {
+		return fmt.Errorf("failed to reorder configs of api %v (HTTP %v)!\n    Response was: %v", api.ID, resp.StatusCode, string(resp.Body))
+	}
+	return nil
 }
 
 // SchemaListResponse is the response type returned by the ListSchemas operation
@@ -534,17 +989,18 @@ type SchemaListResponse struct {
 	TotalCount int        `json:"totalCount"`
 }
 type SchemaList []struct {
-	SchemaId string `json:"schemaId"`
+	SchemaId            string `json:"schemaId"`
+	LatestSchemaVersion string `json:"latestSchemaVersion"`
 }
 
-func (d *DynatraceClient) ListSchemas() (SchemaList, error) {
+func (d *DynatraceClient) ListSchemas(ctx context.Context) (SchemaList, error) {
 	u, err := url.Parse(d.environmentURL + d.settingsSchemaAPIPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse url: %w", err)
 	}
 
 	// getting all schemas does not have pagination
-	resp, err := rest.Get(d.client, u.String())
+	resp, err := rest.Get(ctx, d.client, u.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to GET schemas: %w", err)
 	}
@@ -566,13 +1022,31 @@ func (d *DynatraceClient) ListSchemas() (SchemaList, error) {
 	return result.Items, nil
 }
 
-func (d *DynatraceClient) GetSettingById(objectId string) (*DownloadSettingsObject, error) {
+func (d *DynatraceClient) GetSchema(ctx context.Context, schemaId string) ([]byte, error) {
+	u, err := url.Parse(d.environmentURL + d.settingsSchemaAPIPath + "/" + url.PathEscape(schemaId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	resp, err := rest.Get(ctx, d.client, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to GET schema %q: %w", schemaId, err)
+	}
+
+	if !success(resp) {
+		return nil, fmt.Errorf("request failed with HTTP (%d).\n\tResponse content: %s", resp.StatusCode, string(resp.Body))
+	}
+
+	return resp.Body, nil
+}
+
+func (d *DynatraceClient) GetSettingById(ctx context.Context, objectId string) (*DownloadSettingsObject, error) {
 	u, err := url.Parse(d.environmentURL + d.settingsObjectAPIPath + "/" + objectId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL '%s': %w", d.environmentURL+d.settingsObjectAPIPath, err)
 	}
 
-	resp, err := rest.Get(d.client, u.String())
+	resp, err := rest.Get(ctx, d.client, u.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to GET settings object with object id %q: %w", objectId, err)
 	}
@@ -594,7 +1068,7 @@ func (d *DynatraceClient) GetSettingById(objectId string) (*DownloadSettingsObje
 	return &result, nil
 }
 
-func (d *DynatraceClient) ListSettings(schemaId string, opts ListSettingsOptions) ([]DownloadSettingsObject, error) {
+func (d *DynatraceClient) ListSettings(ctx context.Context, schemaId string, opts ListSettingsOptions) ([]DownloadSettingsObject, error) {
 	log.Debug("Downloading all settings for schema %s", schemaId)
 
 	listSettingsFields := defaultListSettingsFields
@@ -603,7 +1077,7 @@ func (d *DynatraceClient) ListSettings(schemaId string, opts ListSettingsOptions
 	}
 	params := url.Values{
 		"schemaIds": []string{schemaId},
-		"pageSize":  []string{defaultPageSize},
+		"pageSize":  []string{d.pagination.settingsPageSize()},
 		"fields":    []string{listSettingsFields},
 	}
 
@@ -631,7 +1105,7 @@ func (d *DynatraceClient) ListSettings(schemaId string, opts ListSettingsOptions
 		return len(parsed.Items), len(result), nil
 	}
 
-	_, err := d.listPaginated(d.settingsObjectAPIPath, params, schemaId, addToResult)
+	_, err := d.listPaginated(ctx, d.settingsObjectAPIPath, params, schemaId, addToResult)
 
 	if err != nil {
 		return nil, err
@@ -654,7 +1128,7 @@ func (e EntitiesType) String() string {
 	return e.EntitiesTypeId
 }
 
-func (d *DynatraceClient) ListEntitiesTypes() ([]EntitiesType, error) {
+func (d *DynatraceClient) ListEntitiesTypes(ctx context.Context) ([]EntitiesType, error) {
 
 	params := url.Values{
 		"pageSize": []string{defaultPageSize},
@@ -674,7 +1148,7 @@ func (d *DynatraceClient) ListEntitiesTypes() ([]EntitiesType, error) {
 		return len(parsed.Types), len(result), nil
 	}
 
-	_, err := d.listPaginated(pathEntitiesTypes, params, "EntityTypeList", addToResult)
+	_, err := d.listPaginated(ctx, pathEntitiesTypes, params, "EntityTypeList", addToResult)
 
 	if err != nil {
 		return nil, err
@@ -691,7 +1165,7 @@ func genTimeframeUnixMilliString(duration time.Duration) string {
 	return strconv.FormatInt(time.Now().Add(duration).UnixMilli(), 10)
 }
 
-func (d *DynatraceClient) ListEntities(entitiesType EntitiesType) ([]string, error) {
+func (d *DynatraceClient) ListEntities(ctx context.Context, entitiesType EntitiesType, opts ListEntitiesOptions) ([]string, error) {
 
 	entityType := entitiesType.EntitiesTypeId
 	log.Debug("Downloading all entities for entities Type %s", entityType)
@@ -720,8 +1194,8 @@ func (d *DynatraceClient) ListEntities(entitiesType EntitiesType) ([]string, err
 	var ignoreProperties []string
 
 	for runExtraction {
-		params := genListEntitiesParams(entityType, entitiesType, ignoreProperties)
-		resp, err := d.listPaginated(pathEntitiesObjects, params, entityType, addToResult)
+		params := genListEntitiesParams(entityType, entitiesType, ignoreProperties, d.pagination.entitiesPageSize(), opts.From)
+		resp, err := d.listPaginated(ctx, pathEntitiesObjects, params, entityType, addToResult)
 
 		runExtraction, ignoreProperties, err = handleListEntitiesError(entityType, resp, runExtraction, ignoreProperties, err)
 
@@ -733,13 +1207,61 @@ func (d *DynatraceClient) ListEntities(entitiesType EntitiesType) ([]string, err
 	return result, nil
 }
 
-func (d *DynatraceClient) listPaginated(urlPath string, params url.Values, logLabel string,
+func (d *DynatraceClient) EntityExists(ctx context.Context, entityId string) (bool, error) {
+	resp, err := rest.Get(ctx, d.client, d.environmentURL+pathEntitiesObjects+"/"+url.PathEscape(entityId))
+	if err != nil {
+		return false, fmt.Errorf("failed to GET entity %q: %w", entityId, err)
+	}
+
+	switch {
+	case success(resp):
+		return true, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to GET entity %q (HTTP %d)!\n\tResponse was: %s", entityId, resp.StatusCode, string(resp.Body))
+	}
+}
+
+func (d *DynatraceClient) ListEntityIds(ctx context.Context, entitySelector string) ([]string, error) {
+	var result []string
+
+	addToResult := func(body []byte) (int, int, error) {
+		var parsed struct {
+			Entities []struct {
+				EntityId string `json:"entityId"`
+			} `json:"entities"`
+		}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return 0, len(result), fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		for _, e := range parsed.Entities {
+			result = append(result, e.EntityId)
+		}
+		return len(parsed.Entities), len(result), nil
+	}
+
+	params := url.Values{
+		"entitySelector": []string{entitySelector},
+		"pageSize":       []string{d.pagination.entitiesPageSize()},
+		"from":           []string{genTimeframeUnixMilliString(defaultEntityDurationTimeframeFrom)},
+		"to":             []string{genTimeframeUnixMilliString(defaultEntityDurationTimeframeTo)},
+	}
+	if _, err := d.listPaginated(ctx, pathEntitiesObjects, params, entitySelector, addToResult); err != nil {
+		return nil, fmt.Errorf("failed to list entities matching %q: %w", entitySelector, err)
+	}
+
+	return result, nil
+}
+
+// listPaginated requests all pages of the given paginated API and hands each page to addToResult. If prefetching is
+// enabled, the next page is already requested while the current page is processed.
+func (d *DynatraceClient) listPaginated(ctx context.Context, urlPath string, params url.Values, logLabel string,
 	addToResult func(body []byte) (int, int, error)) (rest.Response, error) {
 
 	var resp rest.Response
 	startTime := time.Now()
-	receivedCount := 0
-	totalReceivedCount := 0
 
 	u, err := buildUrl(d.environmentURL, urlPath, params)
 	if err != nil {
@@ -749,39 +1271,45 @@ func (d *DynatraceClient) listPaginated(urlPath string, params url.Values, logLa
 		}
 	}
 
-	resp, receivedCount, totalReceivedCount, _, err = d.runAndProcessResponse(false, u, addToResult, receivedCount, totalReceivedCount, urlPath)
-	if err != nil {
-		return resp, RespError{
-			Err:        err,
-			StatusCode: resp.StatusCode,
-		}
-	}
-
 	nbCalls := 1
 	lastLogTime := time.Now()
-	expectedTotalCount := resp.TotalCount
-	nextPageKey := resp.NextPageKey
+	expectedTotalCount := 0
+	nextPageKey := ""
 	emptyResponseRetryCount := 0
 
+	pending := d.fetchPage(ctx, false, u, urlPath)
 	for {
+		page := <-pending
+		resp = page.resp
+		if page.err != nil {
+			return resp, RespError{
+				Err:        page.err,
+				StatusCode: resp.StatusCode,
+			}
+		}
+		if page.isLastAvailablePage {
+			break
+		}
 
-		if nextPageKey != "" {
-			logLongRunningExtractionProgress(&lastLogTime, startTime, nbCalls, resp, logLabel)
-
-			u = rest.AddNextPageQueryParams(u, nextPageKey)
-
-			var isLastAvailablePage bool
-			resp, receivedCount, totalReceivedCount, isLastAvailablePage, err = d.runAndProcessResponse(true, u, addToResult, receivedCount, totalReceivedCount, urlPath)
-			if err != nil {
-				return resp, RespError{
-					Err:        err,
-					StatusCode: resp.StatusCode,
-				}
+		var nextUrl *url.URL
+		var prefetched <-chan fetchedPage
+		if resp.NextPageKey != "" {
+			next := *u // AddNextPageQueryParams modifies the URL, which may still be in use by a prefetch
+			nextUrl = rest.AddNextPageQueryParams(&next, resp.NextPageKey)
+			if d.pagination.Prefetch {
+				prefetched = d.fetchPage(ctx, true, nextUrl, urlPath)
 			}
-			if isLastAvailablePage {
-				break
+		}
+
+		receivedCount, totalReceivedCount, err := addToResult(resp.Body)
+		if err != nil {
+			return resp, RespError{
+				Err:        err,
+				StatusCode: resp.StatusCode,
 			}
+		}
 
+		if page.isNextCall {
 			retry := false
 			retry, emptyResponseRetryCount, err = isRetryOnEmptyResponse(receivedCount, emptyResponseRetryCount, resp)
 			if err != nil {
@@ -792,32 +1320,64 @@ func (d *DynatraceClient) listPaginated(urlPath string, params url.Values, logLa
 			}
 
 			if retry {
+				// a prefetched page is discarded, as the current page is requested again
+				pending = d.fetchPage(ctx, true, page.url, urlPath)
 				continue
-			} else {
-				validateWrongCountExtracted(resp, totalReceivedCount, expectedTotalCount, urlPath, logLabel, nextPageKey, params)
-
-				nextPageKey = resp.NextPageKey
-				nbCalls++
-				emptyResponseRetryCount = 0
 			}
 
+			validateWrongCountExtracted(resp, totalReceivedCount, expectedTotalCount, urlPath, logLabel, nextPageKey, params)
+			nbCalls++
+			emptyResponseRetryCount = 0
 		} else {
+			expectedTotalCount = resp.TotalCount
+		}
 
+		if nextUrl == nil {
 			break
 		}
+
+		nextPageKey = resp.NextPageKey
+		logLongRunningExtractionProgress(&lastLogTime, startTime, nbCalls, resp, logLabel)
+
+		if prefetched != nil {
+			pending = prefetched
+		} else {
+			pending = d.fetchPage(ctx, true, nextUrl, urlPath)
+		}
 	}
 
 	return resp, nil
 
 }
 
-func (d *DynatraceClient) DeleteSettings(objectID string) error {
+// fetchedPage is the result of requesting a single page of a paginated API
+type fetchedPage struct {
+	url                 *url.URL
+	isNextCall          bool
+	resp                rest.Response
+	isLastAvailablePage bool
+	err                 error
+}
+
+// fetchPage requests the page at the given URL in the background. The returned channel receives the result once the
+// request completes.
+func (d *DynatraceClient) fetchPage(ctx context.Context, isNextCall bool, u *url.URL, urlPath string) <-chan fetchedPage {
+	result := make(chan fetchedPage, 1)
+	go func() {
+		resp, err := rest.GetWithRetry(ctx, d.client, u.String(), d.retrySettings.Normal)
+		isLastAvailablePage, err := validateRespErrors(isNextCall, err, resp, urlPath)
+		result <- fetchedPage{url: u, isNextCall: isNextCall, resp: resp, isLastAvailablePage: isLastAvailablePage, err: err}
+	}()
+	return result
+}
+
+func (d *DynatraceClient) DeleteSettings(ctx context.Context, objectID string) error {
 	u, err := url.Parse(d.environmentURL + d.settingsObjectAPIPath)
 	if err != nil {
 		return fmt.Errorf("failed to parse URL '%s': %w", d.environmentURL+d.settingsObjectAPIPath, err)
 	}
 
-	return rest.DeleteConfig(d.client, u.String(), objectID)
+	return rest.DeleteConfig(ctx, d.client, u.String(), objectID)
 
 }
 
@@ -837,22 +1397,6 @@ func isRetryOnEmptyResponse(receivedCount int, emptyResponseRetryCount int, resp
 	return false, emptyResponseRetryCount, nil
 }
 
-func (d *DynatraceClient) runAndProcessResponse(isNextCall bool, u *url.URL,
-	addToResult func(body []byte) (int, int, error),
-	receivedCount int, totalReceivedCount int, urlPath string) (rest.Response, int, int, bool, error) {
-	isLastAvailablePage := false
-
-	resp, err := rest.GetWithRetry(d.client, u.String(), d.retrySettings.Normal)
-	isLastAvailablePage, err = validateRespErrors(isNextCall, err, resp, urlPath)
-	if err != nil || isLastAvailablePage {
-		return resp, receivedCount, totalReceivedCount, isLastAvailablePage, err
-	}
-
-	receivedCount, totalReceivedCount, err = addToResult(resp.Body)
-
-	return resp, receivedCount, totalReceivedCount, isLastAvailablePage, err
-}
-
 func buildUrl(environmentUrl, urlPath string, params url.Values) (*url.URL, error) {
 	u, err := url.Parse(environmentUrl + urlPath)
 	if err != nil {
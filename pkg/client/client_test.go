@@ -20,11 +20,13 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2"
 	"net/http"
@@ -64,6 +66,16 @@ func TestNewClassicClient(t *testing.T) {
 		assert.Equal(t, ver, c.serverVersion, "'serverVersion' should be modified with 'WithServerVersion'")
 		assert.Equal(t, rest.DefaultRetrySettings, c.retrySettings, "'retrySettings' should be modified with 'WithRetrySettings' modifier")
 	})
+	t.Run("HTTP timeouts are applied", func(t *testing.T) {
+		c, err := NewClassicClient("https://some.url", "", WithHTTPTimeouts(HTTPTimeouts{Connect: time.Second, Request: time.Minute}))
+		assert.NoError(t, err)
+
+		assert.Equal(t, time.Minute, c.client.Timeout)
+		transport, ok := c.client.Transport.(*TokenAuthTransport)
+		assert.True(t, ok)
+		assert.NotEqual(t, http.DefaultTransport, transport.RoundTripper, "connect timeout should use a dedicated base transport")
+	})
+
 	t.Run("URL is empty - should throw an error", func(t *testing.T) {
 		_, err := NewClassicClient("", "")
 		assert.ErrorContains(t, err, "empty url")
@@ -154,7 +166,7 @@ func TestNewPlatformClient(t *testing.T) {
 
 		ver := version.Version{Major: 1, Minor: 2, Patch: 3}
 
-		c, err := NewPlatformClient(dtURL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"},
+		c, err := NewPlatformClient(context.TODO(), dtURL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"},
 			WithServerVersion(ver),
 			WithRetrySettings(rest.DefaultRetrySettings))
 
@@ -181,55 +193,78 @@ func TestNewPlatformClient(t *testing.T) {
 		assert.Equal(t, rest.DefaultRetrySettings, c.retrySettings, "'retrySettings' should be modified with 'WithRetrySettings' modifier")
 	})
 
+	t.Run("options are applied to the request of the classic URL", func(t *testing.T) {
+		var header string
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			switch req.URL.Path {
+			case "/oauth/token":
+				rw.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(rw).Encode(&oauth2.Token{AccessToken: "test-access-token", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)})
+			case classicEnvironmentDomainPath:
+				header = req.Header.Get("X-Gateway")
+				_, _ = rw.Write([]byte(`{"endpoint" : "/classic_endpoint"}`))
+			default:
+				rw.WriteHeader(404)
+			}
+		}))
+		defer server.Close()
+
+		_, err := NewPlatformClient(context.TODO(), server.URL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"},
+			WithRequestAdditions([]rest.RequestAddition{{Headers: map[string]string{"X-Gateway": "monaco"}}}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "monaco", header)
+	})
+
 	t.Run("URL is empty - should throw an error", func(t *testing.T) {
-		_, err := NewPlatformClient(server.URL, "", OauthCredentials{TokenURL: server.URL + "/wrong/address"})
+		_, err := NewPlatformClient(context.TODO(), server.URL, "", OauthCredentials{TokenURL: server.URL + "/wrong/address"})
 		assert.ErrorContains(t, err, "failed to query classic environment url")
 	})
 
 	t.Run("URL is empty - should throw an error", func(t *testing.T) {
-		_, err := NewPlatformClient("", "", OauthCredentials{})
+		_, err := NewPlatformClient(context.TODO(), "", "", OauthCredentials{})
 		assert.ErrorContains(t, err, "empty url")
 	})
 
 	t.Run("invalid URL - should throw an error", func(t *testing.T) {
-		_, err := NewPlatformClient("INVALID_URL", "", OauthCredentials{})
+		_, err := NewPlatformClient(context.TODO(), "INVALID_URL", "", OauthCredentials{})
 		assert.ErrorContains(t, err, "not valid")
 	})
 
 	t.Run("URL suffix is trimmed", func(t *testing.T) {
-		client, err := NewPlatformClient(server.URL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"})
+		client, err := NewPlatformClient(context.TODO(), server.URL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"})
 		assert.NoError(t, err)
 		assert.Equal(t, server.URL, client.environmentURL)
 	})
 
 	t.Run("URL with leading space - should return an error", func(t *testing.T) {
-		_, err := NewPlatformClient(" https://my-environment.live.dynatrace.com/", "", OauthCredentials{})
+		_, err := NewPlatformClient(context.TODO(), " https://my-environment.live.dynatrace.com/", "", OauthCredentials{})
 		assert.Error(t, err)
 	})
 
 	t.Run("URL starts with http", func(t *testing.T) {
-		client, err := NewPlatformClient(server.URL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"})
+		client, err := NewPlatformClient(context.TODO(), server.URL, "", OauthCredentials{TokenURL: server.URL + "/oauth/token"})
 		assert.NoError(t, err)
 		assert.Equal(t, server.URL, client.environmentURL)
 	})
 
 	t.Run("URL is without scheme - should throw an error", func(t *testing.T) {
-		_, err := NewPlatformClient("my-environment.live.dynatrace.com", "", OauthCredentials{})
+		_, err := NewPlatformClient(context.TODO(), "my-environment.live.dynatrace.com", "", OauthCredentials{})
 		assert.ErrorContains(t, err, "not valid")
 	})
 
 	t.Run("URL is without valid local path - should return an error", func(t *testing.T) {
-		_, err := NewPlatformClient("/my-environment/live/dynatrace.com/", "", OauthCredentials{})
+		_, err := NewPlatformClient(context.TODO(), "/my-environment/live/dynatrace.com/", "", OauthCredentials{})
 		assert.ErrorContains(t, err, "no host specified")
 	})
 
 	t.Run("without valid protocol - should return an error", func(t *testing.T) {
 		var err error
 
-		_, err = NewPlatformClient("https//my-environment.live.dynatrace.com/", "", OauthCredentials{})
+		_, err = NewPlatformClient(context.TODO(), "https//my-environment.live.dynatrace.com/", "", OauthCredentials{})
 		assert.ErrorContains(t, err, "not valid")
 
-		_, err = NewPlatformClient("http//my-environment.live.dynatrace.com/", "", OauthCredentials{})
+		_, err = NewPlatformClient(context.TODO(), "http//my-environment.live.dynatrace.com/", "", OauthCredentials{})
 		assert.ErrorContains(t, err, "not valid")
 	})
 }
@@ -244,7 +279,7 @@ func TestReadByIdReturnsAnErrorUponEncounteringAnError(t *testing.T) {
 		clientClassic:         testServer.Client(),
 	}
 
-	_, err := client.ReadConfigById(mockAPI, "test")
+	_, err := client.ReadConfigById(context.TODO(), mockAPI, "test")
 	assert.ErrorContains(t, err, "Response was")
 }
 
@@ -257,7 +292,7 @@ func TestReadByIdEscapesTheId(t *testing.T) {
 		environmentURLClassic: testServer.URL,
 		clientClassic:         testServer.Client(),
 	}
-	_, err := client.ReadConfigById(mockAPINotSingle, unescapedID)
+	_, err := client.ReadConfigById(context.TODO(), mockAPINotSingle, unescapedID)
 	assert.NoError(t, err)
 }
 
@@ -274,11 +309,38 @@ func TestReadByIdReturnsTheResponseGivenNoError(t *testing.T) {
 		clientClassic:         testServer.Client(),
 	}
 
-	resp, err := client.ReadConfigById(mockAPI, "test")
+	resp, err := client.ReadConfigById(context.TODO(), mockAPI, "test")
 	assert.NoError(t, err, "there should not be an error")
 	assert.Equal(t, body, resp)
 }
 
+func TestReorderConfigs(t *testing.T) {
+	orderedAPI := api.API{ID: "request-naming-service", URLPath: "/api/config/v1/service/requestNaming", Ordered: true}
+
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPut, req.Method)
+		assert.Equal(t, "/api/config/v1/service/requestNaming/order", req.URL.Path)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"values": []interface{}{
+			map[string]interface{}{"id": "id-2"},
+			map[string]interface{}{"id": "id-1"},
+		}}, body)
+
+		res.WriteHeader(http.StatusNoContent)
+	}))
+	defer func() { testServer.Close() }()
+
+	client := DynatraceClient{
+		environmentURLClassic: testServer.URL,
+		clientClassic:         testServer.Client(),
+	}
+
+	err := client.ReorderConfigs(context.TODO(), orderedAPI, []string{"id-2", "id-1"})
+	assert.NoError(t, err)
+}
+
 func TestListKnownSettings(t *testing.T) {
 
 	tests := []struct {
@@ -551,7 +613,7 @@ func TestListKnownSettings(t *testing.T) {
 				retrySettings:  testRetrySettings,
 			}
 
-			res, err1 := client.ListSettings(tt.givenSchemaID, tt.givenListSettingsOpts)
+			res, err1 := client.ListSettings(context.TODO(), tt.givenSchemaID, tt.givenListSettingsOpts)
 
 			if tt.wantError {
 				assert.Error(t, err1)
@@ -663,7 +725,7 @@ func TestGetSettingById(t *testing.T) {
 				settingsObjectAPIPath: "/api/v2/settings/objects",
 			}
 
-			settingsObj, err := d.GetSettingById(tt.args.objectID)
+			settingsObj, err := d.GetSettingById(context.TODO(), tt.args.objectID)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -758,7 +820,7 @@ func TestDeleteSettings(t *testing.T) {
 				settingsObjectAPIPath: settingsObjectAPIPathClassic,
 			}
 
-			if err := d.DeleteSettings(tt.args.objectID); (err != nil) != tt.wantErr {
+			if err := d.DeleteSettings(context.TODO(), tt.args.objectID); (err != nil) != tt.wantErr {
 				t.Errorf("DeleteSettings() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -790,7 +852,7 @@ func TestUpsertSettingsRetries(t *testing.T) {
 		retrySettings:  testRetrySettings,
 	}
 
-	_, err := client.UpsertSettings(SettingsObject{
+	_, err := client.UpsertSettings(context.TODO(), SettingsObject{
 		Id:       "42",
 		SchemaId: "some:schema",
 		Content:  []byte("{}"),
@@ -1063,7 +1125,7 @@ func TestListEntities(t *testing.T) {
 				retrySettings:  testRetrySettings,
 			}
 
-			res, err1 := client.ListEntities(tt.givenEntitiesType)
+			res, err1 := client.ListEntities(context.TODO(), tt.givenEntitiesType, ListEntitiesOptions{})
 
 			if tt.wantError {
 				assert.Error(t, err1)
@@ -1078,13 +1140,161 @@ func TestListEntities(t *testing.T) {
 	}
 }
 
+func TestEntityExists(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		want       bool
+		wantError  bool
+	}{
+		{"existing entity", http.StatusOK, true, false},
+		{"unknown entity", http.StatusNotFound, false, false},
+		{"HTTP error", http.StatusUnauthorized, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, pathEntitiesObjects+"/HOST-1234567890ABCDEF", req.URL.Path)
+				rw.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			client := DynatraceClient{
+				environmentURL: server.URL,
+				client:         server.Client(),
+				retrySettings:  testRetrySettings,
+			}
+
+			got, err := client.EntityExists(context.TODO(), "HOST-1234567890ABCDEF")
+
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestListEntityIds(t *testing.T) {
+	const selector = `type("HOST"),tag("prod")`
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, pathEntitiesObjects, req.URL.Path)
+		assert.Equal(t, selector, req.URL.Query().Get("entitySelector"))
+		_, _ = rw.Write([]byte(`{"totalCount": 2, "entities": [{"entityId": "HOST-1"}, {"entityId": "HOST-2"}]}`))
+	}))
+	defer server.Close()
+
+	client := DynatraceClient{
+		environmentURL: server.URL,
+		client:         server.Client(),
+		retrySettings:  testRetrySettings,
+	}
+
+	got, err := client.ListEntityIds(context.TODO(), selector)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"HOST-1", "HOST-2"}, got)
+}
+
+func TestListSettings_UsesPageSizeAndPrefetchesNextPage(t *testing.T) {
+	secondPageRequested := make(chan struct{})
+	emptyPageServed := false
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("nextPageKey") {
+		case "":
+			assert.Equal(t, "2", req.URL.Query().Get("pageSize"))
+			_, _ = rw.Write([]byte(`{ "items": [ {"objectId": "1"}, {"objectId": "2"} ], "nextPageKey": "page2", "totalCount": 5 }`))
+		case "page2":
+			if !emptyPageServed {
+				emptyPageServed = true
+				close(secondPageRequested)
+				_, _ = rw.Write([]byte(`{ "items": [ ], "nextPageKey": "page2", "totalCount": 5 }`))
+				return
+			}
+			_, _ = rw.Write([]byte(`{ "items": [ {"objectId": "3"}, {"objectId": "4"} ], "nextPageKey": "page3", "totalCount": 5 }`))
+		case "page3":
+			_, _ = rw.Write([]byte(`{ "items": [ {"objectId": "5"} ], "totalCount": 5 }`))
+		default:
+			t.Errorf("unexpected request %s", req.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := DynatraceClient{
+		environmentURL:        server.URL,
+		client:                server.Client(),
+		retrySettings:         testRetrySettings,
+		settingsObjectAPIPath: settingsObjectAPIPathClassic,
+		pagination:            Pagination{SettingsPageSize: 2, Prefetch: true},
+	}
+
+	got, err := client.ListSettings(context.TODO(), "builtin:something", ListSettingsOptions{Filter: func(o DownloadSettingsObject) bool {
+		if o.ObjectId == "1" {
+			// the first page is only processed once the second page was requested
+			select {
+			case <-secondPageRequested:
+			case <-time.After(5 * time.Second):
+				t.Error("second page was not prefetched")
+			}
+		}
+		return true
+	}})
+
+	assert.NoError(t, err)
+	ids := make([]string, len(got))
+	for i, o := range got {
+		ids[i] = o.ObjectId
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+}
+
+func TestPaginationFromEnvironment(t *testing.T) {
+	t.Setenv("MONACO_SETTINGS_PAGE_SIZE", "100")
+	t.Setenv("MONACO_ENTITIES_PAGE_SIZE", "")
+	t.Setenv("MONACO_FEAT_PAGINATION_PREFETCH", "false")
+
+	p := PaginationFromEnvironment()
+
+	assert.Equal(t, Pagination{SettingsPageSize: 100}, p)
+	assert.Equal(t, "100", p.settingsPageSize())
+	assert.Equal(t, defaultPageSizeEntities, p.entitiesPageSize())
+}
+
+func TestWithHTTPCache(t *testing.T) {
+	c, err := NewClassicClient("https://some.url", "token", WithHTTPCache(afero.NewMemMapFs(), "/cache", time.Minute))
+	assert.NoError(t, err)
+
+	transport, ok := c.client.Transport.(*TokenAuthTransport)
+	assert.True(t, ok)
+	assert.IsType(t, &rest.CachingTransport{}, transport.RoundTripper)
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+	c, err := NewClassicClient("https://some.url", "token", WithHTTPTimeouts(HTTPTimeouts{Connect: time.Second}), WithClientCertificate(cert))
+	assert.NoError(t, err)
+
+	transport, ok := c.client.Transport.(*TokenAuthTransport)
+	assert.True(t, ok)
+	base, ok := transport.RoundTripper.(*http.Transport)
+	assert.True(t, ok)
+	assert.Equal(t, []tls.Certificate{cert}, base.TLSClientConfig.Certificates)
+
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+	assert.True(t, defaultTransport.TLSClientConfig == nil || len(defaultTransport.TLSClientConfig.Certificates) == 0, "default transport must not be modified")
+}
+
 func TestCreateDynatraceClientWithAutoServerVersion(t *testing.T) {
 	t.Run("Server version is correctly set to determined value", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 			_, _ = rw.Write([]byte(`{"version" : "1.262.0.20230214-193525"}`))
 		}))
 
-		dcl, err := NewClassicClient(server.URL, "", WithAutoServerVersion())
+		dcl, err := NewClassicClient(server.URL, "", WithAutoServerVersion(context.TODO()))
 
 		server.Close()
 		assert.NoError(t, err)
@@ -1096,7 +1306,7 @@ func TestCreateDynatraceClientWithAutoServerVersion(t *testing.T) {
 			_, _ = rw.Write([]byte(`{}`))
 		}))
 
-		dcl, err := NewClassicClient(server.URL, "", WithAutoServerVersion())
+		dcl, err := NewClassicClient(server.URL, "", WithAutoServerVersion(context.TODO()))
 		server.Close()
 		assert.NoError(t, err)
 		assert.Equal(t, version.UnknownVersion, dcl.serverVersion)
@@ -1179,3 +1389,41 @@ func TestNonDefaultTokenURL(t *testing.T) {
 	assert.True(t, specialTokenURLCalled, "expected oAuth client to make an API call to the defined token URL")
 	assert.True(t, defaultTokenURLCalled == false, "expected oAuth client to make NO API call to the default URL")
 }
+
+func TestOAuthClient_SendsScopesAndAudienceAndRefreshesExpiredTokens(t *testing.T) {
+	tokenPath := "/custom/sso/token"
+	tokenRequests := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Add("Content-Type", "application/json")
+		if req.URL.Path == tokenPath {
+			tokenRequests++
+			assert.NoError(t, req.ParseForm())
+			assert.Equal(t, "urn:dtenvironment:abc", req.Form.Get("audience"))
+			assert.Equal(t, "scope-a scope-b", req.Form.Get("scope"))
+			// tokens expiring within the expiry delta of the oauth2 library are considered to be expired already
+			rw.Write([]byte(`{ "access_token":"ABC", "token_type":"Bearer", "expires_in":1 }`))
+		} else {
+			rw.Write([]byte(`{ "some":"reply" }`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, server.Client()) // ensure the oAuth client trusts the test server by passing its underlying client
+
+	c := NewOAuthClient(ctx, OauthCredentials{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		TokenURL:     serverURL.JoinPath(tokenPath).String(),
+		Scopes:       []string{"scope-a", "scope-b"},
+		Audience:     "urn:dtenvironment:abc",
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err = c.Do(&http.Request{Method: http.MethodGet, URL: serverURL.JoinPath("/some/api/call")})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, tokenRequests, "expected expired token to be requested again")
+}
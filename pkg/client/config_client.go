@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
@@ -31,12 +32,14 @@ import (
 )
 
 func upsertDynatraceObject(
+	ctx context.Context,
 	client *http.Client,
 	environmentUrl string,
 	objectName string,
 	theApi api.API,
 	payload []byte,
 	retrySettings rest.RetrySettings,
+	cache *nameLookupCache,
 ) (DynatraceEntity, error) {
 	isSingleConfigurationApi := theApi.SingleConfiguration
 	existingObjectId := ""
@@ -46,7 +49,7 @@ func upsertDynatraceObject(
 	// Single configuration APIs don't have an id which allows skipping this step
 	if !isSingleConfigurationApi {
 		var err error
-		existingObjectId, err = getObjectIdIfAlreadyExists(client, theApi, fullUrl, objectName, retrySettings)
+		existingObjectId, err = getObjectIdIfAlreadyExists(ctx, client, theApi, fullUrl, objectName, retrySettings, cache)
 		if err != nil {
 			return DynatraceEntity{}, err
 		}
@@ -63,16 +66,31 @@ func upsertDynatraceObject(
 
 	isUpdate := existingObjectId != ""
 
+	// Key user actions can't be updated, they are only defined by their name
+	if isUpdate && isKeyUserActionApi(theApi) {
+		log.Debug("\tKey user action %s (%s) already exists and can not be updated", objectName, existingObjectId)
+		return DynatraceEntity{
+			Id:          existingObjectId,
+			Name:        objectName,
+			Description: "Existing object",
+		}, nil
+	}
+
 	// Single configuration APIs don't have a POST, but a PUT endpoint
 	// and therefore always require an update
 	if isUpdate || isSingleConfigurationApi {
-		return updateDynatraceObject(client, fullUrl, objectName, existingObjectId, theApi, body, retrySettings)
-	} else {
-		return createDynatraceObject(client, fullUrl, objectName, theApi, body, retrySettings)
+		return updateDynatraceObject(ctx, client, fullUrl, objectName, existingObjectId, theApi, body, retrySettings)
+	}
+
+	entity, err := createDynatraceObject(ctx, client, fullUrl, objectName, theApi, body, retrySettings)
+	if err == nil && cache != nil {
+		cache.add(fullUrl, Value{Id: entity.Id, Name: objectName})
 	}
+	return entity, err
 }
 
 func upsertDynatraceEntityByNonUniqueNameAndId(
+	ctx context.Context,
 	client *http.Client,
 	environmentUrl string,
 	entityId string,
@@ -80,11 +98,12 @@ func upsertDynatraceEntityByNonUniqueNameAndId(
 	theApi api.API,
 	payload []byte,
 	retrySettings rest.RetrySettings,
+	cache *nameLookupCache,
 ) (DynatraceEntity, error) {
 	fullUrl := theApi.CreateURL(environmentUrl)
 	body := payload
 
-	existingEntities, err := getExistingValuesFromEndpoint(client, theApi, fullUrl, retrySettings)
+	existingEntities, err := findExistingValues(ctx, client, theApi, fullUrl, objectName, retrySettings, cache)
 	if err != nil {
 		return DynatraceEntity{}, fmt.Errorf("failed to query existing entities for upsert: %w", err)
 	}
@@ -102,13 +121,16 @@ func upsertDynatraceEntityByNonUniqueNameAndId(
 	}
 
 	if entityExists || len(entitiesWithSameName) == 0 { // create with fixed ID or update (if this moves to client logging can clearly state things)
-		entity, err := updateDynatraceObject(client, fullUrl, objectName, entityId, theApi, body, retrySettings)
+		entity, err := updateDynatraceObject(ctx, client, fullUrl, objectName, entityId, theApi, body, retrySettings)
+		if err == nil && !entityExists && cache != nil {
+			cache.add(fullUrl, Value{Id: entityId, Name: objectName})
+		}
 		return entity, err
 	}
 
 	if len(entitiesWithSameName) == 1 { // name is currently unique, update know entity
 		existingUuid := entitiesWithSameName[0].Id
-		entity, err := updateDynatraceObject(client, fullUrl, objectName, existingUuid, theApi, body, retrySettings)
+		entity, err := updateDynatraceObject(ctx, client, fullUrl, objectName, existingUuid, theApi, body, retrySettings)
 		return entity, err
 	}
 
@@ -121,10 +143,14 @@ func upsertDynatraceEntityByNonUniqueNameAndId(
 	}
 	log.Warn(msg.String(), len(entitiesWithSameName), theApi.ID, objectName, entityId, theApi.ID)
 
-	return updateDynatraceObject(client, fullUrl, objectName, entityId, theApi, body, retrySettings)
+	entity, err := updateDynatraceObject(ctx, client, fullUrl, objectName, entityId, theApi, body, retrySettings)
+	if err == nil && cache != nil {
+		cache.add(fullUrl, Value{Id: entityId, Name: objectName})
+	}
+	return entity, err
 }
 
-func createDynatraceObject(client *http.Client, urlString string, objectName string, theApi api.API, payload []byte, retrySettings rest.RetrySettings) (DynatraceEntity, error) {
+func createDynatraceObject(ctx context.Context, client *http.Client, urlString string, objectName string, theApi api.API, payload []byte, retrySettings rest.RetrySettings) (DynatraceEntity, error) {
 	parsedUrl, err := url.Parse(urlString)
 	if err != nil {
 		return DynatraceEntity{}, fmt.Errorf("invalid URL for creating Dynatrace config: %w", err)
@@ -139,7 +165,19 @@ func createDynatraceObject(client *http.Client, urlString string, objectName str
 		parsedUrl.RawQuery = queryParams.Encode()
 	}
 
-	resp, err := callWithRetryOnKnowTimingIssue(client, rest.Post, objectName, parsedUrl.String(), body, theApi, retrySettings)
+	// Key user actions of mobile apps are created by their name, and don't have any ID apart from it
+	if configType == "key-user-actions-mobile" {
+		resp, err := callWithRetryOnKnowTimingIssue(ctx, client, rest.Post, objectName, joinUrl(urlString, objectName), nil, theApi, retrySettings)
+		if err != nil {
+			return DynatraceEntity{}, err
+		}
+		if !success(resp) {
+			return DynatraceEntity{}, fmt.Errorf("Failed to create DT object %s (HTTP %d)!\n    Response was: %s", objectName, resp.StatusCode, string(resp.Body))
+		}
+		return DynatraceEntity{Id: objectName, Name: objectName, Description: "Created object"}, nil
+	}
+
+	resp, err := callWithRetryOnKnowTimingIssue(ctx, client, rest.Post, objectName, parsedUrl.String(), body, theApi, retrySettings)
 	if err != nil {
 		return DynatraceEntity{}, err
 	}
@@ -199,7 +237,7 @@ func unmarshalResponse(resp rest.Response, fullUrl string, configType string, ob
 	return dtEntity, nil
 }
 
-func updateDynatraceObject(client *http.Client, fullUrl string, objectName string, existingObjectId string, theApi api.API, payload []byte, retrySettings rest.RetrySettings) (DynatraceEntity, error) {
+func updateDynatraceObject(ctx context.Context, client *http.Client, fullUrl string, objectName string, existingObjectId string, theApi api.API, payload []byte, retrySettings rest.RetrySettings) (DynatraceEntity, error) {
 	path := joinUrl(fullUrl, existingObjectId)
 	body := payload
 
@@ -214,7 +252,7 @@ func updateDynatraceObject(client *http.Client, fullUrl string, objectName strin
 		body = stripCreateOnlyPropertiesFromAppMobile(body)
 	}
 
-	resp, err := callWithRetryOnKnowTimingIssue(client, rest.Put, objectName, path, body, theApi, retrySettings)
+	resp, err := callWithRetryOnKnowTimingIssue(ctx, client, rest.Put, objectName, path, body, theApi, retrySettings)
 
 	if err != nil {
 		return DynatraceEntity{}, err
@@ -225,9 +263,9 @@ func updateDynatraceObject(client *http.Client, fullUrl string, objectName strin
 	}
 
 	if theApi.NonUniqueName {
-		log.Debug("\tCreated/Updated object by ID for %s (%s)", objectName, existingObjectId)
+		log.WithCtxFields(ctx).Debug("\tCreated/Updated object by ID for %s (%s)", objectName, existingObjectId)
 	} else {
-		log.Debug("\tUpdated existing object for %s (%s)", objectName, existingObjectId)
+		log.WithCtxFields(ctx).Debug("\tUpdated existing object for %s (%s)", objectName, existingObjectId)
 	}
 
 	return DynatraceEntity{
@@ -249,9 +287,9 @@ func stripCreateOnlyPropertiesFromAppMobile(payload []byte) []byte {
 // callWithRetryOnKnowTimingIssue handles several know cases in which Dynatrace has a slight delay before newly created objects
 // can be used in further configuration. This is a cheap way to allow monaco to work around this, by waiting, then
 // retrying in case of know errors on upload.
-func callWithRetryOnKnowTimingIssue(client *http.Client, restCall rest.SendingRequest, objectName string, path string, body []byte, theApi api.API, retrySettings rest.RetrySettings) (rest.Response, error) {
+func callWithRetryOnKnowTimingIssue(ctx context.Context, client *http.Client, restCall rest.SendingRequest, objectName string, path string, body []byte, theApi api.API, retrySettings rest.RetrySettings) (rest.Response, error) {
 
-	resp, err := restCall(client, path, body)
+	resp, err := restCall(ctx, client, path, body)
 
 	if err == nil && success(resp) {
 		return resp, nil
@@ -282,7 +320,7 @@ func callWithRetryOnKnowTimingIssue(client *http.Client, restCall rest.SendingRe
 	}
 
 	if setting.MaxRetries > 0 {
-		return rest.SendWithRetry(client, restCall, objectName, path, body, setting)
+		return rest.SendWithRetry(ctx, client, restCall, objectName, path, body, setting)
 	}
 	return resp, nil
 }
@@ -346,8 +384,8 @@ func isLocationHeaderAvailable(resp rest.Response) (headerAvailable bool, header
 	return false, make([]string, 0)
 }
 
-func getObjectIdIfAlreadyExists(client *http.Client, api api.API, url string, objectName string, retrySettings rest.RetrySettings) (string, error) {
-	values, err := getExistingValuesFromEndpoint(client, api, url, retrySettings)
+func getObjectIdIfAlreadyExists(ctx context.Context, client *http.Client, api api.API, url string, objectName string, retrySettings rest.RetrySettings, cache *nameLookupCache) (string, error) {
+	values, err := findExistingValues(ctx, client, api, url, objectName, retrySettings, cache)
 
 	if err != nil {
 		return "", err
@@ -366,16 +404,46 @@ func getObjectIdIfAlreadyExists(client *http.Client, api api.API, url string, ob
 	}
 
 	if matchingObjectsFound > 1 {
-		log.Warn("Found %d configs with same name: %s. Please delete duplicates.", matchingObjectsFound, objectName)
+		log.WithCtxFields(ctx).Warn("Found %d configs with same name: %s. Please delete duplicates.", matchingObjectsFound, objectName)
 	}
 
 	if objectId != "" {
-		log.Debug("Found existing config %s (%s) with id %s", objectName, api.ID, objectId)
+		log.WithCtxFields(ctx).Debug("Found existing config %s (%s) with id %s", objectName, api.ID, objectId)
 	}
 
 	return objectId, nil
 }
 
+// findExistingValues lists the configs of an API an object of the given name is looked up in. APIs supporting a
+// NameSelector are filtered on server side. Otherwise, all configs are listed - or taken from the cache, if given.
+func findExistingValues(ctx context.Context, client *http.Client, theApi api.API, urlString string, objectName string, retrySettings rest.RetrySettings, cache *nameLookupCache) ([]Value, error) {
+	if theApi.NameSelector != "" {
+		query := url.Values{theApi.NameSelector: {nameSelector(objectName)}}
+		return getExistingValuesFromEndpoint(ctx, client, theApi, urlString+"?"+query.Encode(), retrySettings)
+	}
+
+	if cache == nil {
+		return getExistingValuesFromEndpoint(ctx, client, theApi, urlString, retrySettings)
+	}
+
+	if values, found := cache.get(urlString); found {
+		return values, nil
+	}
+	values, err := getExistingValuesFromEndpoint(ctx, client, theApi, urlString, retrySettings)
+	if err != nil {
+		return nil, err
+	}
+	cache.set(urlString, values)
+	return values, nil
+}
+
+// nameSelector returns the selector matching configs of the given name. Within selector values, '~' and '"' are
+// escaped by a '~'.
+func nameSelector(name string) string {
+	escaped := strings.NewReplacer("~", "~~", `"`, `~"`).Replace(name)
+	return `name("` + escaped + `")`
+}
+
 func escapeApiValueName(value Value) string {
 	valueName, err := template.EscapeSpecialCharactersInValue(value.Name, template.FullStringEscapeFunction)
 	if err != nil {
@@ -405,7 +473,46 @@ func isMobileApp(api api.API) bool {
 	return api.ID == "application-mobile"
 }
 
-func getExistingValuesFromEndpoint(client *http.Client, theApi api.API, urlString string, retrySettings rest.RetrySettings) (values []Value, err error) {
+func isKeyUserActionApi(api api.API) bool {
+	return api.ID == "key-user-actions-web" || api.ID == "key-user-actions-mobile"
+}
+
+// readKeyUserActionById returns the key user action with the given ID. There is no endpoint to read a single key
+// user action, thus it is looked up in the list of all key user actions of its application.
+func readKeyUserActionById(ctx context.Context, client *http.Client, theApi api.API, urlString string, id string) ([]byte, error) {
+	resp, err := rest.Get(ctx, client, urlString)
+	if err != nil {
+		return nil, err
+	}
+	if !success(resp) {
+		return nil, fmt.Errorf("failed to get existing configs for api %v (HTTP %v)!\n    Response was: %v", theApi.ID, resp.StatusCode, string(resp.Body))
+	}
+
+	var objmap map[string]interface{}
+	if err := json.Unmarshal(resp.Body, &objmap); err != nil {
+		return nil, err
+	}
+
+	_, actions := isResultArrayAvailable(objmap, theApi)
+	for _, a := range actions {
+		if action, ok := a.(map[string]interface{}); ok && keyUserActionId(action) == id {
+			return json.Marshal(action)
+		}
+	}
+	return nil, fmt.Errorf("key user action %q of api %v not found", id, theApi.ID)
+}
+
+// keyUserActionId returns the ID of a key user action - the Dynatrace entity ID for web applications, and the name
+// for mobile applications.
+func keyUserActionId(action map[string]interface{}) string {
+	if id, ok := action["meIdentifier"].(string); ok {
+		return id
+	}
+	name, _ := action["name"].(string)
+	return name
+}
+
+func getExistingValuesFromEndpoint(ctx context.Context, client *http.Client, theApi api.API, urlString string, retrySettings rest.RetrySettings) (values []Value, err error) {
 
 	parsedUrl, err := url.Parse(urlString)
 	if err != nil {
@@ -414,7 +521,7 @@ func getExistingValuesFromEndpoint(client *http.Client, theApi api.API, urlStrin
 
 	parsedUrl = addQueryParamsForNonStandardApis(theApi, parsedUrl)
 
-	resp, err := rest.Get(client, parsedUrl.String())
+	resp, err := rest.Get(ctx, client, parsedUrl.String())
 
 	if err != nil {
 		return nil, err
@@ -435,7 +542,7 @@ func getExistingValuesFromEndpoint(client *http.Client, theApi api.API, urlStrin
 		if resp.NextPageKey != "" {
 			parsedUrl = rest.AddNextPageQueryParams(parsedUrl, resp.NextPageKey)
 
-			resp, err = rest.GetWithRetry(client, parsedUrl.String(), retrySettings.Normal)
+			resp, err = rest.GetWithRetry(ctx, client, parsedUrl.String(), retrySettings.Normal)
 
 			if err != nil {
 				return nil, err
@@ -444,7 +551,7 @@ func getExistingValuesFromEndpoint(client *http.Client, theApi api.API, urlStrin
 			if !success(resp) && resp.StatusCode != http.StatusBadRequest {
 				return nil, fmt.Errorf("Failed to get further configs from paginated API %s (HTTP %d)!\n    Response was: %s", theApi.ID, resp.StatusCode, string(resp.Body))
 			} else if resp.StatusCode == http.StatusBadRequest {
-				log.Warn("Failed to get additional data from paginated API %s - pages may have been removed during request.\n    Response was: %s", theApi.ID, string(resp.Body))
+				log.WithCtxFields(ctx).Warn("Failed to get additional data from paginated API %s - pages may have been removed during request.\n    Response was: %s", theApi.ID, string(resp.Body))
 				break
 			}
 
@@ -546,6 +653,10 @@ func translateGenericValues(inputValues []interface{}, configType string) ([]Val
 	for _, input := range inputValues {
 		input := input.(map[string]interface{})
 
+		if configType == "key-user-actions-web" || configType == "key-user-actions-mobile" {
+			input["id"] = keyUserActionId(input)
+		}
+
 		if input["id"] == nil {
 			return values, fmt.Errorf("config of type %s was invalid: No id", configType)
 		}
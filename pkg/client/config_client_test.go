@@ -19,6 +19,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
@@ -101,6 +102,100 @@ func TestTranslateGenericValuesForReportsEndpoint(t *testing.T) {
 	assert.Equal(t, values[0].Name, "dashboardId")
 }
 
+func TestTranslateGenericValuesForKeyUserActions(t *testing.T) {
+	web, err := translateGenericValues([]interface{}{
+		map[string]interface{}{"name": "action", "meIdentifier": "APPLICATION_METHOD-1234"},
+	}, "key-user-actions-web")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, web, []Value{{Id: "APPLICATION_METHOD-1234", Name: "action"}})
+
+	mobile, err := translateGenericValues([]interface{}{
+		map[string]interface{}{"name": "action"},
+	}, "key-user-actions-mobile")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, mobile, []Value{{Id: "action", Name: "action"}})
+}
+
+func Test_readKeyUserActionById(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, req.Method, http.MethodGet)
+		_, _ = rw.Write([]byte(`{"keyUserActionList": [
+			{"name": "a", "actionType": "Load", "domain": "example.com", "meIdentifier": "APPLICATION_METHOD-1"},
+			{"name": "b", "actionType": "Xhr", "domain": "example.com", "meIdentifier": "APPLICATION_METHOD-2"}
+		]}`))
+	}))
+	defer server.Close()
+	testApi := api.API{ID: "key-user-actions-web", PropertyNameOfGetAllResponse: "keyUserActionList"}
+
+	got, err := readKeyUserActionById(context.TODO(), server.Client(), testApi, server.URL, "APPLICATION_METHOD-2")
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), `{"actionType":"Xhr","domain":"example.com","meIdentifier":"APPLICATION_METHOD-2","name":"b"}`)
+
+	_, err = readKeyUserActionById(context.TODO(), server.Client(), testApi, server.URL, "APPLICATION_METHOD-3")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func Test_upsertDynatraceObject_DoesNotUpdateExistingKeyUserAction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			t.Errorf("unexpected %s request, key user actions can not be updated", req.Method)
+		}
+		_, _ = rw.Write([]byte(`{"keyUserActionList": [{"name": "a", "meIdentifier": "APPLICATION_METHOD-1"}]}`))
+	}))
+	defer server.Close()
+	testApi := api.API{ID: "key-user-actions-web", PropertyNameOfGetAllResponse: "keyUserActionList"}
+
+	got, err := upsertDynatraceObject(context.TODO(), server.Client(), server.URL, "a", testApi, []byte(`{"name": "a"}`), testRetrySettings, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, got.Id, "APPLICATION_METHOD-1")
+}
+
+func Test_upsertDynatraceObject_CachesNameLookups(t *testing.T) {
+	var listRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			listRequests++
+			_, _ = rw.Write([]byte(`{"values": [{"id": "existing-id", "name": "existing"}]}`))
+		case http.MethodPost:
+			rw.WriteHeader(http.StatusCreated)
+			_, _ = rw.Write([]byte(`{"id": "created-id", "name": "created"}`))
+		case http.MethodPut:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	testApi := api.API{ID: "alerting-profile", PropertyNameOfGetAllResponse: api.StandardApiPropertyNameOfGetAllResponse}
+	cache := newNameLookupCache()
+
+	got, err := upsertDynatraceObject(context.TODO(), server.Client(), server.URL, "existing", testApi, []byte(`{}`), testRetrySettings, cache)
+	assert.NilError(t, err)
+	assert.Equal(t, got.Id, "existing-id")
+
+	got, err = upsertDynatraceObject(context.TODO(), server.Client(), server.URL, "created", testApi, []byte(`{}`), testRetrySettings, cache)
+	assert.NilError(t, err)
+	assert.Equal(t, got.Id, "created-id")
+
+	got, err = upsertDynatraceObject(context.TODO(), server.Client(), server.URL, "created", testApi, []byte(`{}`), testRetrySettings, cache)
+	assert.NilError(t, err)
+	assert.Equal(t, got.Id, "created-id", "created configs must be found in the cache")
+
+	assert.Equal(t, listRequests, 1)
+}
+
+func Test_getObjectIdIfAlreadyExists_UsesNameSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, req.URL.Query().Get("sloSelector"), `name("my ~"slo~~")`)
+		_, _ = rw.Write([]byte(`{"slo": [{"id": "slo-id", "name": "my \"slo~"}]}`))
+	}))
+	defer server.Close()
+	testApi := api.API{ID: "slo", URLPath: "/api/v2/slo", PropertyNameOfGetAllResponse: "slo", NameSelector: "sloSelector"}
+
+	got, err := getObjectIdIfAlreadyExists(context.TODO(), server.Client(), testApi, server.URL+testApi.URLPath, `my "slo~`, testRetrySettings, newNameLookupCache())
+	assert.NilError(t, err)
+	assert.Equal(t, got, "slo-id")
+}
+
 func TestJoinUrl(t *testing.T) {
 	urlBase := "url/"
 	path := "path"
@@ -347,7 +442,7 @@ func Test_getObjectIdIfAlreadyExists(t *testing.T) {
 			}))
 			defer server.Close()
 
-			got, err := getObjectIdIfAlreadyExists(server.Client(), testApi, server.URL, tt.givenObjectName, testRetrySettings)
+			got, err := getObjectIdIfAlreadyExists(context.TODO(), server.Client(), testApi, server.URL, tt.givenObjectName, testRetrySettings, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getObjectIdIfAlreadyExists() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -537,7 +632,7 @@ func Test_GetObjectIdIfAlreadyExists_WorksCorrectlyForAddedQueryParameters(t *te
 					MaxRetries: 3,
 				},
 			}
-			_, err := getObjectIdIfAlreadyExists(server.Client(), testApi, server.URL, "", s)
+			_, err := getObjectIdIfAlreadyExists(context.TODO(), server.Client(), testApi, server.URL, "", s, nil)
 
 			if tt.expectError {
 				assert.Assert(t, err != nil)
@@ -584,6 +679,15 @@ func Test_createDynatraceObject(t *testing.T) {
 			want:           DynatraceEntity{Id: "42", Name: "Test object"},
 			wantErr:        false,
 		},
+		{
+			name:                "Creates key user actions of mobile apps by name",
+			objectName:          "Test object",
+			apiKey:              "key-user-actions-mobile",
+			expectedQueryParams: []testQueryParams{},
+			serverResponse:      testServerResponse{statusCode: 200, body: ``},
+			want:                DynatraceEntity{Id: "Test object", Name: "Test object", Description: "Created object"},
+			wantErr:             false,
+		},
 		{
 			name:                "Returns err on server error",
 			objectName:          "Test object",
@@ -628,7 +732,7 @@ func Test_createDynatraceObject(t *testing.T) {
 			defer server.Close()
 			testApi := api.API{ID: tt.apiKey}
 
-			got, err := createDynatraceObject(server.Client(), server.URL, tt.objectName, testApi, []byte("{}"), testRetrySettings)
+			got, err := createDynatraceObject(context.TODO(), server.Client(), server.URL, tt.objectName, testApi, []byte("{}"), testRetrySettings)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("createDynatraceObject() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -681,7 +785,7 @@ func TestDeployConfigsTargetingClassicConfigNonUnique(t *testing.T) {
 
 			testApi := api.API{ID: "some-api", NonUniqueName: true, PropertyNameOfGetAllResponse: api.StandardApiPropertyNameOfGetAllResponse}
 
-			got, err := upsertDynatraceEntityByNonUniqueNameAndId(server.Client(), server.URL, generatedUuid, theConfigName, testApi, []byte("{}"), testRetrySettings)
+			got, err := upsertDynatraceEntityByNonUniqueNameAndId(context.TODO(), server.Client(), server.URL, generatedUuid, theConfigName, testApi, []byte("{}"), testRetrySettings, nil)
 			assert.NilError(t, err)
 			assert.Equal(t, got.Id, tt.expectedIdToBeUpserted)
 		})
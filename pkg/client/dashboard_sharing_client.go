@@ -0,0 +1,65 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"net/url"
+)
+
+const pathDashboards = "/api/config/v1/dashboards"
+
+func dashboardShareSettingsURL(environmentURL string, dashboardId string) string {
+	return environmentURL + pathDashboards + "/" + url.PathEscape(dashboardId) + "/shareSettings"
+}
+
+func (d *DynatraceClient) GetDashboardShareSettings(ctx context.Context, dashboardId string) ([]byte, error) {
+	resp, err := rest.Get(ctx, d.clientClassic, dashboardShareSettingsURL(d.environmentURLClassic, dashboardId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share settings of dashboard %q: %w", dashboardId, err)
+	}
+	if !success(resp) {
+		return nil, fmt.Errorf("failed to get share settings of dashboard %q (HTTP %d)!\n\tResponse was: %s", dashboardId, resp.StatusCode, string(resp.Body))
+	}
+	return resp.Body, nil
+}
+
+func (d *DynatraceClient) UpdateDashboardShareSettings(ctx context.Context, dashboardId string, shareSettings []byte) error {
+	var settings map[string]interface{}
+	if err := json.Unmarshal(shareSettings, &settings); err != nil {
+		return fmt.Errorf("invalid share settings of dashboard %q: %w", dashboardId, err)
+	}
+	// the API requires the ID of the dashboard to be part of the payload
+	settings["id"] = dashboardId
+
+	payload, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	resp, err := rest.Put(ctx, d.clientClassic, dashboardShareSettingsURL(d.environmentURLClassic, dashboardId), payload)
+	if err != nil {
+		return fmt.Errorf("failed to update share settings of dashboard %q: %w", dashboardId, err)
+	}
+	if !success(resp) {
+		return fmt.Errorf("failed to update share settings of dashboard %q (HTTP %d)!\n\tResponse was: %s", dashboardId, resp.StatusCode, string(resp.Body))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDashboardShareSettings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.Equal(t, "/api/config/v1/dashboards/dashboard-id/shareSettings", req.URL.Path)
+		_, _ = rw.Write([]byte(`{"id": "dashboard-id", "enabled": true}`))
+	}))
+	defer server.Close()
+
+	client := DynatraceClient{environmentURLClassic: server.URL, clientClassic: server.Client()}
+
+	got, err := client.GetDashboardShareSettings(context.TODO(), "dashboard-id")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id": "dashboard-id", "enabled": true}`, string(got))
+}
+
+func TestUpdateDashboardShareSettings(t *testing.T) {
+	t.Run("dashboard id is added to the share settings", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPut, req.Method)
+			assert.Equal(t, "/api/config/v1/dashboards/dashboard-id/shareSettings", req.URL.Path)
+
+			body, _ := io.ReadAll(req.Body)
+			assert.JSONEq(t, `{"id": "dashboard-id", "enabled": true}`, string(body))
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURLClassic: server.URL, clientClassic: server.Client()}
+
+		err := client.UpdateDashboardShareSettings(context.TODO(), "dashboard-id", []byte(`{"enabled": true}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed update is reported", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURLClassic: server.URL, clientClassic: server.Client()}
+
+		err := client.UpdateDashboardShareSettings(context.TODO(), "dashboard-id", []byte(`{"enabled": true}`))
+		assert.ErrorContains(t, err, "HTTP 400")
+	})
+}
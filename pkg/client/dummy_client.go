@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -52,7 +53,7 @@ func NewDummyClient() *DummyClient {
 	return &DummyClient{Entries: map[api.API][]DataEntry{}}
 }
 
-func (c *DummyClient) ListConfigs(a api.API) (values []Value, err error) {
+func (c *DummyClient) ListConfigs(ctx context.Context, a api.API) (values []Value, err error) {
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -73,7 +74,7 @@ func (c *DummyClient) ListConfigs(a api.API) (values []Value, err error) {
 	return result, nil
 }
 
-func (c *DummyClient) ReadByName(a api.API, name string) ([]byte, error) {
+func (c *DummyClient) ReadByName(ctx context.Context, a api.API, name string) ([]byte, error) {
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -89,7 +90,7 @@ func (c *DummyClient) ReadByName(a api.API, name string) ([]byte, error) {
 	return nil, fmt.Errorf("nothing found for name %s in api %s", name, a.ID)
 }
 
-func (c *DummyClient) ReadConfigById(a api.API, id string) ([]byte, error) {
+func (c *DummyClient) ReadConfigById(ctx context.Context, a api.API, id string) ([]byte, error) {
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -105,7 +106,7 @@ func (c *DummyClient) ReadConfigById(a api.API, id string) ([]byte, error) {
 	return nil, fmt.Errorf("nothing found for id %s in api %s", id, a.ID)
 }
 
-func (c *DummyClient) UpsertConfigByName(a api.API, name string, data []byte) (entity DynatraceEntity, err error) {
+func (c *DummyClient) UpsertConfigByName(ctx context.Context, a api.API, name string, data []byte) (entity DynatraceEntity, err error) {
 	entries, found := c.Entries[a]
 
 	if c.Entries == nil {
@@ -146,7 +147,7 @@ func (c *DummyClient) UpsertConfigByName(a api.API, name string, data []byte) (e
 	}, nil
 }
 
-func (c *DummyClient) UpsertConfigByNonUniqueNameAndId(a api.API, entityId string, name string, data []byte) (entity DynatraceEntity, err error) {
+func (c *DummyClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityId string, name string, data []byte) (entity DynatraceEntity, err error) {
 	entries, found := c.Entries[a]
 
 	if c.Entries == nil {
@@ -206,7 +207,7 @@ func (c *DummyClient) writeRequest(a api.API, name string, payload []byte) {
 	}
 }
 
-func (c *DummyClient) DeleteConfigById(a api.API, id string) error {
+func (c *DummyClient) DeleteConfigById(ctx context.Context, a api.API, id string) error {
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -229,7 +230,7 @@ func (c *DummyClient) DeleteConfigById(a api.API, id string) error {
 	return nil
 }
 
-func (c *DummyClient) ConfigExistsByName(a api.API, name string) (exists bool, id string, err error) {
+func (c *DummyClient) ConfigExistsByName(ctx context.Context, a api.API, name string) (exists bool, id string, err error) {
 	entries, found := c.Entries[a]
 
 	if !found {
@@ -245,32 +246,104 @@ func (c *DummyClient) ConfigExistsByName(a api.API, name string) (exists bool, i
 	return false, "", nil
 }
 
-func (c *DummyClient) UpsertSettings(obj SettingsObject) (DynatraceEntity, error) {
+func (c *DummyClient) ReorderConfigs(ctx context.Context, a api.API, ids []string) error {
+	return nil
+}
+
+func (c *DummyClient) UpsertSettings(ctx context.Context, obj SettingsObject) (DynatraceEntity, error) {
 	return DynatraceEntity{
 		Id:   obj.Id,
 		Name: obj.Id,
 	}, nil
 }
 
-func (c *DummyClient) ListSchemas() (SchemaList, error) {
+func (c *DummyClient) UpsertSettingsBatch(ctx context.Context, objs []SettingsObject) []SettingsBatchResult {
+	results := make([]SettingsBatchResult, len(objs))
+	for i, obj := range objs {
+		results[i].Entity, results[i].Err = c.UpsertSettings(ctx, obj)
+	}
+	return results
+}
+
+func (c *DummyClient) ListSchemas(ctx context.Context) (SchemaList, error) {
 	return make(SchemaList, 0), nil
 }
 
-func (c *DummyClient) GetSettingById(_ string) (*DownloadSettingsObject, error) {
+func (c *DummyClient) GetSchema(ctx context.Context, schemaId string) ([]byte, error) {
+	return []byte(`{"schemaId": "` + schemaId + `"}`), nil
+}
+
+func (c *DummyClient) GetSettingById(ctx context.Context, _ string) (*DownloadSettingsObject, error) {
 	return &DownloadSettingsObject{}, nil
 }
-func (c *DummyClient) ListSettings(_ string, _ ListSettingsOptions) ([]DownloadSettingsObject, error) {
+func (c *DummyClient) ListSettings(ctx context.Context, _ string, _ ListSettingsOptions) ([]DownloadSettingsObject, error) {
 	return make([]DownloadSettingsObject, 0), nil
 }
 
-func (l *DummyClient) DeleteSettings(_ string) error {
+func (l *DummyClient) DeleteSettings(ctx context.Context, _ string) error {
 	return nil
 }
 
-func (c *DummyClient) ListEntitiesTypes() ([]EntitiesType, error) {
+func (c *DummyClient) ListEntitiesTypes(ctx context.Context) ([]EntitiesType, error) {
 	return make([]EntitiesType, 0), nil
 }
 
-func (c *DummyClient) ListEntities(_ EntitiesType) ([]string, error) {
+func (c *DummyClient) ListEntities(ctx context.Context, _ EntitiesType, _ ListEntitiesOptions) ([]string, error) {
 	return make([]string, 0), nil
 }
+
+func (c *DummyClient) EntityExists(ctx context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+func (c *DummyClient) ListEntityIds(ctx context.Context, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *DummyClient) ListExtensionVersions(ctx context.Context, _ string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *DummyClient) UploadExtension(ctx context.Context, _ []byte) (ExtensionVersion, error) {
+	return ExtensionVersion{}, nil
+}
+
+func (c *DummyClient) UpdateExtensionEnvironmentConfiguration(ctx context.Context, _ string, _ []byte) error {
+	return nil
+}
+
+func (c *DummyClient) ListExtensionMonitoringConfigurations(ctx context.Context, _ string) ([]ExtensionMonitoringConfiguration, error) {
+	return nil, nil
+}
+
+func (c *DummyClient) UpsertExtensionMonitoringConfiguration(ctx context.Context, _ string, obj ExtensionMonitoringConfiguration) (DynatraceEntity, error) {
+	id := obj.ObjectId
+	if id == "" {
+		id = uuid.New().String()
+	}
+	return DynatraceEntity{Id: id, Name: id}, nil
+}
+
+func (c *DummyClient) GetDashboardShareSettings(ctx context.Context, _ string) ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+func (c *DummyClient) UpdateDashboardShareSettings(ctx context.Context, _ string, _ []byte) error {
+	return nil
+}
+
+func (c *DummyClient) AddTags(ctx context.Context, _ string, _ []Tag) (int, error) {
+	return 0, nil
+}
+
+func (c *DummyClient) DeleteTag(ctx context.Context, _ string, _ Tag) (int, error) {
+	return 0, nil
+}
+
+func (c *DummyClient) SendEvent(ctx context.Context, _ Event) error {
+	return nil
+}
+
+func (c *DummyClient) SendLog(ctx context.Context, _ string, _ map[string]string) error {
+	return nil
+}
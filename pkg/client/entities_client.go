@@ -19,6 +19,8 @@ import (
 	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
+	"time"
 	"unicode"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/throttle"
@@ -129,14 +131,17 @@ func isInvalidReflectionValue(value reflect.Value) bool {
 	}
 }
 
-func genListEntitiesParams(entityType string, entitiesType EntitiesType, ignoreProperties []string) url.Values {
+func genListEntitiesParams(entityType string, entitiesType EntitiesType, ignoreProperties []string, pageSize string, from time.Time) url.Values {
 	params := url.Values{
 		"entitySelector": []string{"type(\"" + entityType + "\")"},
-		"pageSize":       []string{defaultPageSizeEntities},
+		"pageSize":       []string{pageSize},
 		"fields":         []string{getEntitiesTypeFields(entitiesType, ignoreProperties)},
 		"from":           []string{genTimeframeUnixMilliString(defaultEntityDurationTimeframeFrom)},
 		"to":             []string{genTimeframeUnixMilliString(defaultEntityDurationTimeframeTo)},
 	}
+	if !from.IsZero() {
+		params.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+	}
 
 	return params
 }
@@ -17,7 +17,9 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/environment"
 )
@@ -44,3 +46,47 @@ func (e RespError) ConcurrentError() string {
 
 	return e.Error()
 }
+
+// SettingsConstraintViolationError is returned if the settings API rejects an object, as its value violates constraints
+// of the schema. The violations hold the path of each offending property within the value.
+type SettingsConstraintViolationError struct {
+	ExternalId string
+	StatusCode int
+	Message    string
+	Violations []ConstraintViolation
+}
+
+func (e SettingsConstraintViolationError) Error() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "failed to upsert settings object with externalId %s (HTTP %d): %s", e.ExternalId, e.StatusCode, e.Message)
+	for _, v := range e.Violations {
+		_, _ = fmt.Fprintf(&b, "\n\t- %s: %s", v.Path, v.Message)
+	}
+	return b.String()
+}
+
+// parseSettingsConstraintViolations parses the constraint violations of an error response of the settings API. The
+// objects endpoint responds with one result per posted object, while other endpoints respond with a single error.
+// False is returned if the response contains no constraint violations.
+func parseSettingsConstraintViolations(body []byte) (ErrorResponse, bool) {
+	var results []ErrorResponseStruct
+	if err := json.Unmarshal(body, &results); err != nil {
+		var single ErrorResponseStruct
+		if err := json.Unmarshal(body, &single); err != nil {
+			return ErrorResponse{}, false
+		}
+		results = []ErrorResponseStruct{single}
+	}
+
+	var resp ErrorResponse
+	for _, r := range results {
+		if len(r.ErrorResponse.ConstraintViolationList) == 0 {
+			continue
+		}
+		if resp.Message == "" {
+			resp.Message = r.ErrorResponse.Message
+		}
+		resp.ConstraintViolationList = append(resp.ConstraintViolationList, r.ErrorResponse.ConstraintViolationList...)
+	}
+	return resp, len(resp.ConstraintViolationList) > 0
+}
@@ -0,0 +1,138 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+)
+
+const pathEventsIngest = "/api/v2/events/ingest"
+const pathBizEventsIngest = "/api/v2/bizevents/ingest"
+const pathLogsIngest = "/api/v2/logs/ingest"
+
+// EventType defines the kind of event sent via [EventsClient.SendEvent]
+type EventType string
+
+const (
+	// CustomDeploymentEvent is sent as CUSTOM_DEPLOYMENT event via the events API
+	CustomDeploymentEvent EventType = "CUSTOM_DEPLOYMENT"
+	// CustomInfoEvent is sent as CUSTOM_INFO event via the events API
+	CustomInfoEvent EventType = "CUSTOM_INFO"
+	// BizEvent is sent as business event via the business events API, making it available in Grail
+	BizEvent EventType = "BIZ_EVENT"
+)
+
+// EventTypes lists all supported EventType values
+var EventTypes = []EventType{CustomDeploymentEvent, CustomInfoEvent, BizEvent}
+
+// Event is an event to be sent to a Dynatrace environment
+type Event struct {
+	Type EventType
+	// Title is the title of the event. For business events it's sent as 'event.type'.
+	Title string
+	// EntitySelector optionally restricts the entities the event is attached to. If empty, the event is environment-wide.
+	// It's ignored for business events.
+	EntitySelector string
+	// Properties are additional key-value pairs sent with the event
+	Properties map[string]string
+}
+
+type eventIngest struct {
+	EventType      EventType         `json:"eventType"`
+	Title          string            `json:"title"`
+	EntitySelector string            `json:"entitySelector,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+}
+
+func (d *DynatraceClient) SendEvent(ctx context.Context, event Event) error {
+	path, payload, err := buildEventPayload(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := rest.Post(ctx, d.clientClassic, d.environmentURLClassic+path, payload)
+	if err != nil {
+		return fmt.Errorf("failed to send %s event: %w", event.Type, err)
+	}
+
+	if !success(resp) {
+		return fmt.Errorf("failed to send %s event (HTTP %d)!\n\tResponse was: %s", event.Type, resp.StatusCode, string(resp.Body))
+	}
+
+	log.Debug("Sent %s event %q", event.Type, event.Title)
+	return nil
+}
+
+func (d *DynatraceClient) SendLog(ctx context.Context, content string, attributes map[string]string) error {
+	payload, err := json.Marshal([]map[string]string{buildLogRecord(content, attributes)})
+	if err != nil {
+		return err
+	}
+
+	resp, err := rest.Post(ctx, d.clientClassic, d.environmentURLClassic+pathLogsIngest, payload)
+	if err != nil {
+		return fmt.Errorf("failed to send log: %w", err)
+	}
+
+	if !success(resp) {
+		return fmt.Errorf("failed to send log (HTTP %d)!\n\tResponse was: %s", resp.StatusCode, string(resp.Body))
+	}
+	return nil
+}
+
+// buildLogRecord returns the log record to ingest, with 'log.source' set to monaco unless defined by the attributes.
+func buildLogRecord(content string, attributes map[string]string) map[string]string {
+	record := make(map[string]string, len(attributes)+2)
+	record["log.source"] = "monaco"
+	for k, v := range attributes {
+		record[k] = v
+	}
+	record["content"] = content
+	return record
+}
+
+// buildEventPayload returns the API path and the payload to send the given event with.
+func buildEventPayload(event Event) (string, []byte, error) {
+	switch event.Type {
+	case CustomDeploymentEvent, CustomInfoEvent:
+		payload, err := json.Marshal(eventIngest{
+			EventType:      event.Type,
+			Title:          event.Title,
+			EntitySelector: event.EntitySelector,
+			Properties:     event.Properties,
+		})
+		return pathEventsIngest, payload, err
+
+	case BizEvent:
+		bizEvent := make(map[string]string, len(event.Properties)+2)
+		for k, v := range event.Properties {
+			bizEvent[k] = v
+		}
+		bizEvent["event.type"] = event.Title
+		bizEvent["event.provider"] = "monaco"
+
+		payload, err := json.Marshal(bizEvent)
+		return pathBizEventsIngest, payload, err
+
+	default:
+		return "", nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+}
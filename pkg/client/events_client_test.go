@@ -0,0 +1,131 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendEvent(t *testing.T) {
+	tests := []struct {
+		name         string
+		event        Event
+		responseCode int
+		expectedPath string
+		expectedBody string
+		expectError  bool
+	}{
+		{
+			name: "deployment event",
+			event: Event{
+				Type:       CustomDeploymentEvent,
+				Title:      "Monaco deployment",
+				Properties: map[string]string{"configs": "2"},
+			},
+			responseCode: http.StatusCreated,
+			expectedPath: pathEventsIngest,
+			expectedBody: `{"eventType":"CUSTOM_DEPLOYMENT","title":"Monaco deployment","properties":{"configs":"2"}}`,
+		},
+		{
+			name: "info event with entity selector",
+			event: Event{
+				Type:           CustomInfoEvent,
+				Title:          "Monaco deployment",
+				EntitySelector: "type(HOST)",
+			},
+			responseCode: http.StatusCreated,
+			expectedPath: pathEventsIngest,
+			expectedBody: `{"eventType":"CUSTOM_INFO","title":"Monaco deployment","entitySelector":"type(HOST)"}`,
+		},
+		{
+			name: "biz event",
+			event: Event{
+				Type:       BizEvent,
+				Title:      "monaco.deployment",
+				Properties: map[string]string{"configs": "2"},
+			},
+			responseCode: http.StatusAccepted,
+			expectedPath: pathBizEventsIngest,
+			expectedBody: `{"configs":"2","event.provider":"monaco","event.type":"monaco.deployment"}`,
+		},
+		{
+			name:         "server error",
+			event:        Event{Type: CustomInfoEvent, Title: "Monaco deployment"},
+			responseCode: http.StatusBadRequest,
+			expectedPath: pathEventsIngest,
+			expectError:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodPost, req.Method)
+				assert.Equal(t, test.expectedPath, req.URL.Path)
+				if test.expectedBody != "" {
+					body, err := io.ReadAll(req.Body)
+					assert.NoError(t, err)
+					assert.JSONEq(t, test.expectedBody, string(body))
+				}
+				rw.WriteHeader(test.responseCode)
+			}))
+			defer server.Close()
+
+			c, err := NewClassicClient(server.URL, "token")
+			assert.NoError(t, err)
+
+			err = c.SendEvent(context.TODO(), test.event)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSendEvent_UnknownType(t *testing.T) {
+	c, err := NewClassicClient("https://some.url", "token")
+	assert.NoError(t, err)
+
+	err = c.SendEvent(context.TODO(), Event{Type: "UNKNOWN"})
+	assert.ErrorContains(t, err, "unknown event type")
+}
+
+func TestSendLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, pathLogsIngest, req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"content":"deleted config","log.source":"monaco","monaco.action":"delete"}]`, string(body))
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := NewClassicClient(server.URL, "token")
+	assert.NoError(t, err)
+
+	err = c.SendLog(context.TODO(), "deleted config", map[string]string{"monaco.action": "delete"})
+	assert.NoError(t, err)
+}
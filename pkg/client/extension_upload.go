@@ -19,6 +19,7 @@ package client
 import (
 	"archive/zip"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
@@ -38,9 +39,9 @@ const (
 	extensionNeedsUpdate
 )
 
-func uploadExtension(client *http.Client, apiPath string, extensionName string, payload []byte) (DynatraceEntity, error) {
+func uploadExtension(ctx context.Context, client *http.Client, apiPath string, extensionName string, payload []byte) (DynatraceEntity, error) {
 
-	status, err := validateIfExtensionShouldBeUploaded(client, apiPath, extensionName, payload)
+	status, err := validateIfExtensionShouldBeUploaded(ctx, client, apiPath, extensionName, payload)
 	if err != nil {
 		return DynatraceEntity{}, err
 	}
@@ -58,7 +59,7 @@ func uploadExtension(client *http.Client, apiPath string, extensionName string,
 		}, err
 	}
 
-	resp, err := rest.PostMultiPartFile(client, apiPath, buffer, contentType)
+	resp, err := rest.PostMultiPartFile(ctx, client, apiPath, buffer, contentType)
 
 	if err != nil {
 		return DynatraceEntity{}, err
@@ -69,7 +70,7 @@ func uploadExtension(client *http.Client, apiPath string, extensionName string,
 			Name: extensionName,
 		}, fmt.Errorf("upload of %s failed with status %d! Response: %s", extensionName, resp.StatusCode, string(resp.Body))
 	} else {
-		log.Debug("Extension upload successful for %s", extensionName)
+		log.WithCtxFields(ctx).Debug("Extension upload successful for %s", extensionName)
 
 		// As other configs depend on metrics created by extensions, and metric creation seems to happen with delay...
 		time.Sleep(1 * time.Second)
@@ -85,8 +86,8 @@ type Properties struct {
 	Version *string `json:"version"`
 }
 
-func validateIfExtensionShouldBeUploaded(client *http.Client, apiPath string, extensionName string, payload []byte) (status extensionStatus, err error) {
-	response, err := rest.Get(client, apiPath+"/"+extensionName)
+func validateIfExtensionShouldBeUploaded(ctx context.Context, client *http.Client, apiPath string, extensionName string, payload []byte) (status extensionStatus, err error) {
+	response, err := rest.Get(ctx, client, apiPath+"/"+extensionName)
 	if err != nil {
 		return extensionValidationError, err
 	}
@@ -119,7 +120,7 @@ func validateIfExtensionShouldBeUploaded(client *http.Client, apiPath string, ex
 	}
 
 	if curVersion == newVersion {
-		log.Info("Extension (%s) already deployed in version (%s), skipping.", extensionName, newVersion)
+		log.WithCtxFields(ctx).Info("Extension (%s) already deployed in version (%s), skipping.", extensionName, newVersion)
 		return extensionUpToDate, nil
 	}
 
@@ -19,6 +19,7 @@
 package client
 
 import (
+	"context"
 	"gotest.tools/assert"
 	"net/http"
 	"net/http/httptest"
@@ -34,7 +35,7 @@ func TestCorrectlyIdentifiesLowerLocalVersion(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionConfigOutdated)
 }
@@ -48,7 +49,7 @@ func TestCorrectlyIdentifiesEqualVersion(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.NilError(t, err)
 	assert.Equal(t, status, extensionUpToDate)
 }
@@ -62,7 +63,7 @@ func TestCorrectlyIdentifiesNecessaryUpdate(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.NilError(t, err)
 	assert.Equal(t, status, extensionNeedsUpdate)
 }
@@ -73,7 +74,7 @@ func TestCorrectlyIdentifiesMissingExtension(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", nil)
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", nil)
 	assert.NilError(t, err)
 	assert.Equal(t, status, extensionNeedsUpdate)
 }
@@ -87,7 +88,7 @@ func TestThrowsErrorOnRemoteParsingProblems(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionValidationError)
 }
@@ -101,7 +102,7 @@ func TestThrowsErrorOnLocalParsingProblems(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionValidationError)
 }
@@ -115,7 +116,7 @@ func TestThrowsErrorOnRemoteMissingVersions(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionValidationError)
 }
@@ -129,7 +130,7 @@ func TestThrowsErrorOnLocalMissingVersions(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionValidationError)
 }
@@ -142,7 +143,7 @@ func TestThrowsErrorOnRemoteNilReturn(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", []byte(localPayload))
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", []byte(localPayload))
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionValidationError)
 }
@@ -155,7 +156,7 @@ func TestThrowsErrorOnLocalNilPayload(t *testing.T) {
 	}))
 	defer server.Close()
 
-	status, err := validateIfExtensionShouldBeUploaded(server.Client(), server.URL, "name", nil)
+	status, err := validateIfExtensionShouldBeUploaded(context.TODO(), server.Client(), server.URL, "name", nil)
 	assert.Assert(t, err != nil)
 	assert.Equal(t, status, extensionValidationError)
 }
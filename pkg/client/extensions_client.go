@@ -0,0 +1,194 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+)
+
+const pathExtensions = "/api/v2/extensions"
+
+// ExtensionVersion identifies a version of an extension
+type ExtensionVersion struct {
+	Name    string `json:"extensionName"`
+	Version string `json:"version"`
+}
+
+// ExtensionMonitoringConfiguration is a monitoring configuration of an extension
+type ExtensionMonitoringConfiguration struct {
+	ObjectId string          `json:"objectId,omitempty"`
+	Scope    string          `json:"scope"`
+	Value    json.RawMessage `json:"value"`
+}
+
+func (d *DynatraceClient) ListExtensionVersions(ctx context.Context, extensionName string) ([]string, error) {
+	var result []string
+
+	u := d.environmentURL + pathExtensions + "/" + url.PathEscape(extensionName)
+	for {
+		resp, err := rest.Get(ctx, d.client, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of extension %q: %w", extensionName, err)
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		if !success(resp) {
+			return nil, fmt.Errorf("failed to list versions of extension %q (HTTP %d)!\n\tResponse was: %s", extensionName, resp.StatusCode, string(resp.Body))
+		}
+
+		var parsed struct {
+			Extensions  []ExtensionVersion `json:"extensions"`
+			NextPageKey string             `json:"nextPageKey"`
+		}
+		if err := json.Unmarshal(resp.Body, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal versions of extension %q: %w", extensionName, err)
+		}
+
+		for _, e := range parsed.Extensions {
+			result = append(result, e.Version)
+		}
+
+		if parsed.NextPageKey == "" {
+			return result, nil
+		}
+		u = d.environmentURL + pathExtensions + "/" + url.PathEscape(extensionName) + "?nextPageKey=" + url.QueryEscape(parsed.NextPageKey)
+	}
+}
+
+func (d *DynatraceClient) UploadExtension(ctx context.Context, artifact []byte) (ExtensionVersion, error) {
+	buffer := new(bytes.Buffer)
+	w := multipart.NewWriter(buffer)
+	part, err := w.CreateFormFile("file", "extension.zip")
+	if err != nil {
+		return ExtensionVersion{}, err
+	}
+	if _, err := part.Write(artifact); err != nil {
+		return ExtensionVersion{}, err
+	}
+	if err := w.Close(); err != nil {
+		return ExtensionVersion{}, err
+	}
+
+	resp, err := rest.PostMultiPartFile(ctx, d.client, d.environmentURL+pathExtensions, buffer, w.FormDataContentType())
+	if err != nil {
+		return ExtensionVersion{}, fmt.Errorf("failed to upload extension: %w", err)
+	}
+	if !success(resp) {
+		return ExtensionVersion{}, fmt.Errorf("failed to upload extension (HTTP %d)!\n\tResponse was: %s", resp.StatusCode, string(resp.Body))
+	}
+
+	var uploaded ExtensionVersion
+	if err := json.Unmarshal(resp.Body, &uploaded); err != nil {
+		return ExtensionVersion{}, fmt.Errorf("failed to unmarshal uploaded extension: %w", err)
+	}
+
+	log.Debug("Uploaded extension %q in version %s", uploaded.Name, uploaded.Version)
+	return uploaded, nil
+}
+
+func (d *DynatraceClient) UpdateExtensionEnvironmentConfiguration(ctx context.Context, extensionName string, payload []byte) error {
+	resp, err := rest.Put(ctx, d.client, d.environmentURL+pathExtensions+"/"+url.PathEscape(extensionName)+"/environmentConfiguration", payload)
+	if err != nil {
+		return fmt.Errorf("failed to update environment configuration of extension %q: %w", extensionName, err)
+	}
+	if !success(resp) {
+		return fmt.Errorf("failed to update environment configuration of extension %q (HTTP %d)!\n\tResponse was: %s", extensionName, resp.StatusCode, string(resp.Body))
+	}
+	return nil
+}
+
+func (d *DynatraceClient) ListExtensionMonitoringConfigurations(ctx context.Context, extensionName string) ([]ExtensionMonitoringConfiguration, error) {
+	var result []ExtensionMonitoringConfiguration
+
+	base := d.environmentURL + pathExtensions + "/" + url.PathEscape(extensionName) + "/monitoringConfigurations"
+	u := base
+	for {
+		resp, err := rest.Get(ctx, d.client, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list monitoring configurations of extension %q: %w", extensionName, err)
+		}
+		if !success(resp) {
+			return nil, fmt.Errorf("failed to list monitoring configurations of extension %q (HTTP %d)!\n\tResponse was: %s", extensionName, resp.StatusCode, string(resp.Body))
+		}
+
+		var parsed struct {
+			Items       []ExtensionMonitoringConfiguration `json:"items"`
+			NextPageKey string                             `json:"nextPageKey"`
+		}
+		if err := json.Unmarshal(resp.Body, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal monitoring configurations of extension %q: %w", extensionName, err)
+		}
+		result = append(result, parsed.Items...)
+
+		if parsed.NextPageKey == "" {
+			return result, nil
+		}
+		u = base + "?nextPageKey=" + url.QueryEscape(parsed.NextPageKey)
+	}
+}
+
+func (d *DynatraceClient) UpsertExtensionMonitoringConfiguration(ctx context.Context, extensionName string, c ExtensionMonitoringConfiguration) (DynatraceEntity, error) {
+	base := d.environmentURL + pathExtensions + "/" + url.PathEscape(extensionName) + "/monitoringConfigurations"
+
+	if c.ObjectId != "" {
+		payload, err := json.Marshal(struct {
+			Value json.RawMessage `json:"value"`
+		}{c.Value})
+		if err != nil {
+			return DynatraceEntity{}, err
+		}
+
+		resp, err := rest.Put(ctx, d.client, base+"/"+url.PathEscape(c.ObjectId), payload)
+		if err != nil {
+			return DynatraceEntity{}, fmt.Errorf("failed to update monitoring configuration %q of extension %q: %w", c.ObjectId, extensionName, err)
+		}
+		if !success(resp) {
+			return DynatraceEntity{}, fmt.Errorf("failed to update monitoring configuration %q of extension %q (HTTP %d)!\n\tResponse was: %s", c.ObjectId, extensionName, resp.StatusCode, string(resp.Body))
+		}
+		return DynatraceEntity{Id: c.ObjectId, Name: c.ObjectId}, nil
+	}
+
+	payload, err := json.Marshal([]ExtensionMonitoringConfiguration{c})
+	if err != nil {
+		return DynatraceEntity{}, err
+	}
+
+	resp, err := rest.Post(ctx, d.client, base, payload)
+	if err != nil {
+		return DynatraceEntity{}, fmt.Errorf("failed to create monitoring configuration of extension %q: %w", extensionName, err)
+	}
+	if !success(resp) {
+		return DynatraceEntity{}, fmt.Errorf("failed to create monitoring configuration of extension %q (HTTP %d)!\n\tResponse was: %s", extensionName, resp.StatusCode, string(resp.Body))
+	}
+
+	var created []struct {
+		ObjectId string `json:"objectId"`
+	}
+	if err := json.Unmarshal(resp.Body, &created); err != nil || len(created) != 1 {
+		return DynatraceEntity{}, fmt.Errorf("failed to parse response of created monitoring configuration of extension %q: %s", extensionName, string(resp.Body))
+	}
+	return DynatraceEntity{Id: created[0].ObjectId, Name: created[0].ObjectId}, nil
+}
@@ -0,0 +1,132 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListExtensionVersions(t *testing.T) {
+	t.Run("versions of all pages are returned", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, pathExtensions+"/com.dynatrace.extension.postgres", req.URL.Path)
+			if req.URL.Query().Get("nextPageKey") == "" {
+				_, _ = rw.Write([]byte(`{"extensions": [{"extensionName": "com.dynatrace.extension.postgres", "version": "1.0.0"}], "nextPageKey": "next"}`))
+				return
+			}
+			_, _ = rw.Write([]byte(`{"extensions": [{"extensionName": "com.dynatrace.extension.postgres", "version": "1.1.0"}]}`))
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURL: server.URL, client: server.Client(), retrySettings: testRetrySettings}
+
+		got, err := client.ListExtensionVersions(context.TODO(), "com.dynatrace.extension.postgres")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1.0.0", "1.1.0"}, got)
+	})
+
+	t.Run("unknown extension has no versions", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURL: server.URL, client: server.Client(), retrySettings: testRetrySettings}
+
+		got, err := client.ListExtensionVersions(context.TODO(), "com.dynatrace.extension.postgres")
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
+
+func TestUploadExtension(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, pathExtensions, req.URL.Path)
+
+		file, _, err := req.FormFile("file")
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(file)
+		assert.Equal(t, "zip content", string(content))
+
+		_, _ = rw.Write([]byte(`{"extensionName": "com.dynatrace.extension.postgres", "version": "1.1.0"}`))
+	}))
+	defer server.Close()
+
+	client := DynatraceClient{environmentURL: server.URL, client: server.Client(), retrySettings: testRetrySettings}
+
+	got, err := client.UploadExtension(context.TODO(), []byte("zip content"))
+	assert.NoError(t, err)
+	assert.Equal(t, ExtensionVersion{Name: "com.dynatrace.extension.postgres", Version: "1.1.0"}, got)
+}
+
+func TestUpsertExtensionMonitoringConfiguration(t *testing.T) {
+	const base = pathExtensions + "/com.dynatrace.extension.postgres/monitoringConfigurations"
+
+	t.Run("new configuration is created", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, base, req.URL.Path)
+
+			var body []ExtensionMonitoringConfiguration
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			assert.Len(t, body, 1)
+			assert.Equal(t, "ag_group-default", body[0].Scope)
+
+			_, _ = rw.Write([]byte(`[{"objectId": "new-id", "code": 200}]`))
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURL: server.URL, client: server.Client(), retrySettings: testRetrySettings}
+
+		got, err := client.UpsertExtensionMonitoringConfiguration(context.TODO(), "com.dynatrace.extension.postgres", ExtensionMonitoringConfiguration{
+			Scope: "ag_group-default",
+			Value: json.RawMessage(`{"enabled": true}`),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "new-id", got.Id)
+	})
+
+	t.Run("existing configuration is updated", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPut, req.Method)
+			assert.Equal(t, base+"/existing-id", req.URL.Path)
+
+			body, _ := io.ReadAll(req.Body)
+			assert.JSONEq(t, `{"value": {"enabled": true}}`, string(body))
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURL: server.URL, client: server.Client(), retrySettings: testRetrySettings}
+
+		got, err := client.UpsertExtensionMonitoringConfiguration(context.TODO(), "com.dynatrace.extension.postgres", ExtensionMonitoringConfiguration{
+			ObjectId: "existing-id",
+			Scope:    "ag_group-default",
+			Value:    json.RawMessage(`{"enabled": true}`),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "existing-id", got.Id)
+	})
+}
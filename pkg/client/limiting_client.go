@@ -15,6 +15,7 @@
 package client
 
 import (
+	"context"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/concurrency"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 )
@@ -34,104 +35,232 @@ func LimitClientParallelRequests(client Client, maxParallelRequests int) Client
 	}
 }
 
-func (l limitingClient) ListConfigs(a api.API) (values []Value, err error) {
+func (l limitingClient) ListConfigs(ctx context.Context, a api.API) (values []Value, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		values, err = l.client.ListConfigs(a)
+		values, err = l.client.ListConfigs(ctx, a)
 	})
 
 	return
 }
 
-func (l limitingClient) ReadConfigById(a api.API, id string) (json []byte, err error) {
+func (l limitingClient) ReadConfigById(ctx context.Context, a api.API, id string) (json []byte, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		json, err = l.client.ReadConfigById(a, id)
+		json, err = l.client.ReadConfigById(ctx, a, id)
 	})
 
 	return
 }
 
-func (l limitingClient) UpsertConfigByName(a api.API, name string, payload []byte) (entity DynatraceEntity, err error) {
+func (l limitingClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (entity DynatraceEntity, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		entity, err = l.client.UpsertConfigByName(a, name, payload)
+		entity, err = l.client.UpsertConfigByName(ctx, a, name, payload)
 	})
 
 	return
 }
 
-func (l limitingClient) UpsertConfigByNonUniqueNameAndId(a api.API, entityId string, name string, payload []byte) (entity DynatraceEntity, err error) {
+func (l limitingClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityId string, name string, payload []byte) (entity DynatraceEntity, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		entity, err = l.client.UpsertConfigByNonUniqueNameAndId(a, entityId, name, payload)
+		entity, err = l.client.UpsertConfigByNonUniqueNameAndId(ctx, a, entityId, name, payload)
 	})
 
 	return
 }
 
-func (l limitingClient) DeleteConfigById(a api.API, id string) (err error) {
+func (l limitingClient) DeleteConfigById(ctx context.Context, a api.API, id string) (err error) {
 	l.limiter.ExecuteBlocking(func() {
-		err = l.client.DeleteConfigById(a, id)
+		err = l.client.DeleteConfigById(ctx, a, id)
 	})
 
 	return
 }
 
-func (l limitingClient) ConfigExistsByName(a api.API, name string) (exists bool, id string, err error) {
+func (l limitingClient) ConfigExistsByName(ctx context.Context, a api.API, name string) (exists bool, id string, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		exists, id, err = l.client.ConfigExistsByName(a, name)
+		exists, id, err = l.client.ConfigExistsByName(ctx, a, name)
 	})
 
 	return
 }
 
-func (l limitingClient) UpsertSettings(obj SettingsObject) (e DynatraceEntity, err error) {
+func (l limitingClient) ReorderConfigs(ctx context.Context, a api.API, ids []string) (err error) {
 	l.limiter.ExecuteBlocking(func() {
-		e, err = l.client.UpsertSettings(obj)
+		err = l.client.ReorderConfigs(ctx, a, ids)
 	})
 
 	return
 }
 
-func (l limitingClient) ListSchemas() (s SchemaList, err error) {
+func (l limitingClient) UpsertSettings(ctx context.Context, obj SettingsObject) (e DynatraceEntity, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		s, err = l.client.ListSchemas()
+		e, err = l.client.UpsertSettings(ctx, obj)
 	})
 
 	return
 }
 
-func (l limitingClient) GetSettingById(objectId string) (o *DownloadSettingsObject, err error) {
+func (l limitingClient) UpsertSettingsBatch(ctx context.Context, objs []SettingsObject) (r []SettingsBatchResult) {
 	l.limiter.ExecuteBlocking(func() {
-		o, err = l.client.GetSettingById(objectId)
+		r = l.client.UpsertSettingsBatch(ctx, objs)
 	})
 
 	return
 }
-func (l limitingClient) ListSettings(schemaId string, opts ListSettingsOptions) (o []DownloadSettingsObject, err error) {
+
+func (l limitingClient) ListSchemas(ctx context.Context) (s SchemaList, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		s, err = l.client.ListSchemas(ctx)
+	})
+
+	return
+}
+
+func (l limitingClient) GetSchema(ctx context.Context, schemaId string) (s []byte, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		s, err = l.client.GetSchema(ctx, schemaId)
+	})
+
+	return
+}
+
+func (l limitingClient) GetSettingById(ctx context.Context, objectId string) (o *DownloadSettingsObject, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		o, err = l.client.GetSettingById(ctx, objectId)
+	})
+
+	return
+}
+func (l limitingClient) ListSettings(ctx context.Context, schemaId string, opts ListSettingsOptions) (o []DownloadSettingsObject, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		o, err = l.client.ListSettings(ctx, schemaId, opts)
+	})
+
+	return
+}
+
+func (l limitingClient) DeleteSettings(ctx context.Context, objectID string) (err error) {
+	l.limiter.ExecuteBlocking(func() {
+		err = l.client.DeleteSettings(ctx, objectID)
+	})
+
+	return
+}
+
+func (l limitingClient) ListEntitiesTypes(ctx context.Context) (e []EntitiesType, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		e, err = l.client.ListEntitiesTypes(ctx)
+	})
+
+	return
+}
+
+func (l limitingClient) ListEntities(ctx context.Context, entitiesType EntitiesType, opts ListEntitiesOptions) (o []string, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		o, err = l.client.ListEntities(ctx, entitiesType, opts)
+	})
+
+	return
+}
+
+func (l limitingClient) SendEvent(ctx context.Context, event Event) (err error) {
+	l.limiter.ExecuteBlocking(func() {
+		err = l.client.SendEvent(ctx, event)
+	})
+
+	return
+}
+
+func (l limitingClient) SendLog(ctx context.Context, content string, attributes map[string]string) (err error) {
+	l.limiter.ExecuteBlocking(func() {
+		err = l.client.SendLog(ctx, content, attributes)
+	})
+
+	return
+}
+
+func (l limitingClient) EntityExists(ctx context.Context, entityId string) (exists bool, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		exists, err = l.client.EntityExists(ctx, entityId)
+	})
+
+	return
+}
+
+func (l limitingClient) ListEntityIds(ctx context.Context, entitySelector string) (ids []string, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		ids, err = l.client.ListEntityIds(ctx, entitySelector)
+	})
+
+	return
+}
+
+func (l limitingClient) ListExtensionVersions(ctx context.Context, extensionName string) (versions []string, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		versions, err = l.client.ListExtensionVersions(ctx, extensionName)
+	})
+
+	return
+}
+
+func (l limitingClient) UploadExtension(ctx context.Context, artifact []byte) (v ExtensionVersion, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		v, err = l.client.UploadExtension(ctx, artifact)
+	})
+
+	return
+}
+
+func (l limitingClient) UpdateExtensionEnvironmentConfiguration(ctx context.Context, extensionName string, payload []byte) (err error) {
+	l.limiter.ExecuteBlocking(func() {
+		err = l.client.UpdateExtensionEnvironmentConfiguration(ctx, extensionName, payload)
+	})
+
+	return
+}
+
+func (l limitingClient) ListExtensionMonitoringConfigurations(ctx context.Context, extensionName string) (configs []ExtensionMonitoringConfiguration, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		configs, err = l.client.ListExtensionMonitoringConfigurations(ctx, extensionName)
+	})
+
+	return
+}
+
+func (l limitingClient) UpsertExtensionMonitoringConfiguration(ctx context.Context, extensionName string, c ExtensionMonitoringConfiguration) (e DynatraceEntity, err error) {
+	l.limiter.ExecuteBlocking(func() {
+		e, err = l.client.UpsertExtensionMonitoringConfiguration(ctx, extensionName, c)
+	})
+
+	return
+}
+
+func (l limitingClient) GetDashboardShareSettings(ctx context.Context, dashboardId string) (shareSettings []byte, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		o, err = l.client.ListSettings(schemaId, opts)
+		shareSettings, err = l.client.GetDashboardShareSettings(ctx, dashboardId)
 	})
 
 	return
 }
 
-func (l limitingClient) DeleteSettings(objectID string) (err error) {
+func (l limitingClient) UpdateDashboardShareSettings(ctx context.Context, dashboardId string, shareSettings []byte) (err error) {
 	l.limiter.ExecuteBlocking(func() {
-		err = l.client.DeleteSettings(objectID)
+		err = l.client.UpdateDashboardShareSettings(ctx, dashboardId, shareSettings)
 	})
 
 	return
 }
 
-func (l limitingClient) ListEntitiesTypes() (e []EntitiesType, err error) {
+func (l limitingClient) AddTags(ctx context.Context, entitySelector string, tags []Tag) (matched int, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		e, err = l.client.ListEntitiesTypes()
+		matched, err = l.client.AddTags(ctx, entitySelector, tags)
 	})
 
 	return
 }
 
-func (l limitingClient) ListEntities(entitiesType EntitiesType) (o []string, err error) {
+func (l limitingClient) DeleteTag(ctx context.Context, entitySelector string, tag Tag) (matched int, err error) {
 	l.limiter.ExecuteBlocking(func() {
-		o, err = l.client.ListEntities(entitiesType)
+		matched, err = l.client.DeleteTag(ctx, entitySelector, tag)
 	})
 
 	return
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/golang/mock/gomock"
@@ -33,8 +34,8 @@ func TestDecoratedClient_ReadById(t *testing.T) {
 	client := NewMockClient(gomock.NewController(t))
 	limited := LimitClientParallelRequests(client, 1)
 
-	client.EXPECT().ReadConfigById(a, "id").Return(givenJson, givenError)
-	j, e := limited.ReadConfigById(a, "id")
+	client.EXPECT().ReadConfigById(gomock.Any(), a, "id").Return(givenJson, givenError)
+	j, e := limited.ReadConfigById(context.TODO(), a, "id")
 
 	assert.DeepEqual(t, j, givenJson)
 	assert.Equal(t, e, givenError)
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
@@ -34,13 +35,13 @@ type classicEnvURL struct {
 
 // GetDynatraceClassicURL tries to fetch the URL of the classic environment using the API of a platform enabled
 // environment
-func GetDynatraceClassicURL(client *http.Client, environmentURL string) (string, error) {
+func GetDynatraceClassicURL(ctx context.Context, client *http.Client, environmentURL string) (string, error) {
 	endpointURL, err := url.JoinPath(environmentURL, classicEnvironmentDomainPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to build URL for API %q on environment URL %q", classicEnvironmentDomainPath, environmentURL)
 	}
 
-	resp, err := rest.Get(client, endpointURL)
+	resp, err := rest.Get(ctx, client, endpointURL)
 	if !resp.IsSuccess() || err != nil {
 		log.Debug("failed to query classic environment url from %q, falling back to deprecated endpoint %q: %v (HTTP %v)", classicEnvironmentDomainPath, deprecatedClassicEnvDomainPath, err, resp.StatusCode)
 
@@ -48,7 +49,7 @@ func GetDynatraceClassicURL(client *http.Client, environmentURL string) (string,
 		if err != nil {
 			return "", fmt.Errorf("failed to build URL for API %q on environment URL %q", deprecatedClassicEnvDomainPath, environmentURL)
 		}
-		resp, err = rest.Get(client, deprecatedEndpointURL)
+		resp, err = rest.Get(ctx, client, deprecatedEndpointURL)
 		if err != nil {
 			return "", fmt.Errorf("failed to query classic environment url after fallback to %q: %w", deprecatedClassicEnvDomainPath, err)
 		}
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	assert "github.com/stretchr/testify/assert"
 	"net/http"
 	"net/http/httptest"
@@ -66,7 +67,7 @@ func TestGetDynatraceClassicEnvironment(t *testing.T) {
 			}))
 			defer server.Close()
 
-			got, err := GetDynatraceClassicURL(&http.Client{}, server.URL)
+			got, err := GetDynatraceClassicURL(context.TODO(), &http.Client{}, server.URL)
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, err != nil)
 
@@ -85,7 +86,7 @@ func TestGetDynatraceClassicEnvironmentWorksWithTrailingSlash(t *testing.T) {
 	}))
 	defer server.Close()
 
-	got, err := GetDynatraceClassicURL(&http.Client{}, server.URL+"/")
+	got, err := GetDynatraceClassicURL(context.TODO(), &http.Client{}, server.URL+"/")
 	assert.Equal(t, "http://classic.env.com", got)
 	assert.NoError(t, err)
 }
@@ -104,7 +105,7 @@ func TestGetDynatraceClassicEnvironmentFallsBackToDeprecatedPath(t *testing.T) {
 	}))
 	defer server.Close()
 
-	got, err := GetDynatraceClassicURL(&http.Client{}, server.URL+"/")
+	got, err := GetDynatraceClassicURL(context.TODO(), &http.Client{}, server.URL+"/")
 	assert.Equal(t, "http://fallback.classic.env.com", got)
 	assert.NoError(t, err)
 }
@@ -0,0 +1,62 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"sync"
+)
+
+// nameLookupCache caches the configs listed per API URL to look up existing configs by name. Without it, each upsert
+// lists all configs of its API, so deploying all configs of an API sends as many list requests as there are configs.
+// Configs created via the client are added to the cache, thus it must only be used while no one else changes configs.
+type nameLookupCache struct {
+	mu     sync.Mutex
+	values map[string][]Value
+}
+
+func newNameLookupCache() *nameLookupCache {
+	return &nameLookupCache{values: make(map[string][]Value)}
+}
+
+func (c *nameLookupCache) get(url string) ([]Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values, found := c.values[url]
+	return values, found
+}
+
+func (c *nameLookupCache) set(url string, values []Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[url] = values
+}
+
+// add adds a config created via the client, if the configs of its API are cached
+func (c *nameLookupCache) add(url string, v Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	values, found := c.values[url]
+	if !found {
+		return
+	}
+	for _, existing := range values {
+		if existing.Id == v.Id {
+			return
+		}
+	}
+	c.values[url] = append(values, v)
+}
@@ -51,26 +51,35 @@ type settingsRequest struct {
 // POST Request body: https://www.dynatrace.com/support/help/dynatrace-api/environment-api/settings/objects/post-object#request-body-json-model
 //
 // To do this, we have to wrap the template in another object and send this object to the server.
-// Currently, we only encode one object into an array of objects, but we can optimize it to contain multiple elements to update.
 // Note payload limitations: https://www.dynatrace.com/support/help/dynatrace-api/basics/access-limit#payload-limit
 func buildPostRequestPayload(obj SettingsObject, externalId string) ([]byte, error) {
+	data, err := newSettingsRequest(obj, externalId)
+	if err != nil {
+		return nil, err
+	}
+	return marshalSettingsRequests([]settingsRequest{data})
+}
+
+// newSettingsRequest wraps the rendered config of the given object into the request model of the settings api.
+func newSettingsRequest(obj SettingsObject, externalId string) (settingsRequest, error) {
 	var value any
 	if err := json.Unmarshal(obj.Content, &value); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal rendered config: %w", err)
+		return settingsRequest{}, fmt.Errorf("failed to unmarshal rendered config: %w", err)
 	}
 
-	data := settingsRequest{
+	return settingsRequest{
 		SchemaId:      obj.SchemaId,
 		ExternalId:    externalId,
 		Scope:         obj.Scope,
 		Value:         value,
 		SchemaVersion: obj.SchemaVersion,
 		ObjectId:      obj.OriginObjectId,
-	}
+	}, nil
+}
 
-	// Create json obj. We currently marshal everything into an array, but we can optimize it to include multiple objects in the
-	// future. Look up limits when imp
-	fullObj, err := json.Marshal([]interface{}{data})
+// marshalSettingsRequests marshals the given requests into the array of objects posted to the settings api.
+func marshalSettingsRequests(requests []settingsRequest) ([]byte, error) {
+	fullObj, err := json.Marshal(requests)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal full object: %w", err)
 	}
@@ -112,3 +121,31 @@ func parsePostResponse(resp rest.Response) (DynatraceEntity, error) {
 		Name: parsed[0].ObjectId,
 	}, nil
 }
+
+// SettingsBatchResult is the result of upserting a single object of a batch
+type SettingsBatchResult struct {
+	Entity DynatraceEntity
+	// Err is set if the object could not be upserted
+	Err error
+}
+
+// batchPostResponse is the result of a single object posted to the settings api. Objects posted together are
+// reported in the order they were sent.
+type batchPostResponse struct {
+	Code     int    `json:"code"`
+	ObjectId string `json:"objectId"`
+}
+
+// parseBatchPostResponse unmarshalls the results of the objects of a batched post request. An error is returned if
+// the response does not contain exactly one result per object.
+func parseBatchPostResponse(body []byte, objects int) ([]batchPostResponse, error) {
+	var parsed []batchPostResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w. Response was: %s", err, string(body))
+	}
+
+	if len(parsed) != objects {
+		return nil, fmt.Errorf("response contained %d results for %d objects", len(parsed), objects)
+	}
+	return parsed, nil
+}
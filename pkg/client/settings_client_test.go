@@ -17,7 +17,9 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
 	"gotest.tools/assert"
@@ -162,7 +164,7 @@ func TestUpsertSettings(t *testing.T) {
 				settingsObjectAPIPath: settingsObjectAPIPathClassic,
 			}
 
-			resp, err := c.UpsertSettings(SettingsObject{
+			resp, err := c.UpsertSettings(context.TODO(), SettingsObject{
 				OriginObjectId: "anObjectID",
 				Id:             "user-provided-id",
 				SchemaId:       "builtin:alerting.profile",
@@ -175,3 +177,102 @@ func TestUpsertSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestUpsertSettings_ReturnsConstraintViolations(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		writer.WriteHeader(http.StatusBadRequest)
+		_, _ = writer.Write([]byte(`[{"code": 400, "error": {"code": 400, "message": "Validation failed", "constraintViolations": [
+			{"path": "rules/0/name", "message": "size must be between 1 and 100", "parameterLocation": "PAYLOAD_BODY", "location": null},
+			{"path": "enabled", "message": "must not be null", "parameterLocation": "PAYLOAD_BODY", "location": null}
+		]}}]`))
+	}))
+	defer server.Close()
+
+	c := DynatraceClient{
+		environmentURL:        server.URL,
+		client:                server.Client(),
+		retrySettings:         testRetrySettings,
+		settingsObjectAPIPath: settingsObjectAPIPathClassic,
+	}
+
+	_, err := c.UpsertSettings(context.TODO(), SettingsObject{
+		Id:       "user-provided-id",
+		SchemaId: "builtin:alerting.profile",
+		Scope:    "tenant",
+		Content:  []byte(`{}`),
+	})
+
+	var violationErr SettingsConstraintViolationError
+	assert.Assert(t, errors.As(err, &violationErr))
+	assert.Equal(t, violationErr.StatusCode, http.StatusBadRequest)
+	assert.Equal(t, violationErr.Message, "Validation failed")
+	assert.DeepEqual(t, violationErr.Violations, []ConstraintViolation{
+		{Path: "rules/0/name", Message: "size must be between 1 and 100", ParameterLocation: "PAYLOAD_BODY"},
+		{Path: "enabled", Message: "must not be null", ParameterLocation: "PAYLOAD_BODY"},
+	})
+	assert.ErrorContains(t, err, "\n\t- rules/0/name: size must be between 1 and 100")
+}
+
+func TestParseSettingsConstraintViolations(t *testing.T) {
+	t.Run("single error response", func(t *testing.T) {
+		resp, found := parseSettingsConstraintViolations([]byte(`{"error": {"code": 400, "message": "Constraints violated.", "constraintViolations": [{"path": "value", "message": "invalid"}]}}`))
+		assert.Assert(t, found)
+		assert.Equal(t, resp.Message, "Constraints violated.")
+		assert.DeepEqual(t, resp.ConstraintViolationList, []ConstraintViolation{{Path: "value", Message: "invalid"}})
+	})
+
+	t.Run("errors without violations", func(t *testing.T) {
+		_, found := parseSettingsConstraintViolations([]byte(`[{"code": 404, "error": {"code": 404, "message": "Not found"}}]`))
+		assert.Assert(t, !found)
+	})
+
+	t.Run("no JSON", func(t *testing.T) {
+		_, found := parseSettingsConstraintViolations([]byte(`Internal Server Error`))
+		assert.Assert(t, !found)
+	})
+}
+
+func TestUpsertSettingsBatch(t *testing.T) {
+	var posted [][]settingsRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, r *http.Request) {
+		var requests []settingsRequest
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&requests))
+		posted = append(posted, requests)
+
+		if len(requests) > 1 {
+			writer.WriteHeader(http.StatusMultiStatus)
+			_, _ = writer.Write([]byte(`[{"code": 200, "objectId": "id-a"}, {"code": 400, "error": {"code": 400, "message": "Validation failed"}}, {"code": 200, "objectId": "id-c"}]`))
+			return
+		}
+		_, _ = writer.Write([]byte(`[{"code": 200, "objectId": "id-b"}]`))
+	}))
+	defer server.Close()
+
+	c := DynatraceClient{
+		environmentURL:        server.URL,
+		client:                server.Client(),
+		retrySettings:         testRetrySettings,
+		settingsObjectAPIPath: settingsObjectAPIPathClassic,
+	}
+
+	objects := []SettingsObject{
+		{Id: "a", SchemaId: "builtin:alerting.profile", Scope: "environment", Content: []byte(`{}`)},
+		{Id: "b", SchemaId: "builtin:alerting.profile", Scope: "environment", Content: []byte(`{}`)},
+		{Id: "c", SchemaId: "builtin:alerting.profile", Scope: "environment", Content: []byte(`{}`)},
+		{Id: "invalid", SchemaId: "builtin:alerting.profile", Scope: "environment", Content: []byte(`{`)},
+	}
+	results := c.UpsertSettingsBatch(context.TODO(), objects)
+
+	assert.Equal(t, len(posted), 2, "expected a batched request and a retry of the failed object")
+	assert.Equal(t, len(posted[0]), 3)
+	assert.Equal(t, posted[1][0].ExternalId, idutils.GenerateExternalID("builtin:alerting.profile", "b"))
+
+	assert.Equal(t, len(results), 4)
+	assert.DeepEqual(t, results[0].Entity, DynatraceEntity{Id: "id-a", Name: "id-a"})
+	assert.DeepEqual(t, results[1].Entity, DynatraceEntity{Id: "id-b", Name: "id-b"})
+	assert.DeepEqual(t, results[2].Entity, DynatraceEntity{Id: "id-c", Name: "id-c"})
+	for _, r := range results[:3] {
+		assert.NilError(t, r.Err)
+	}
+	assert.ErrorContains(t, results[3].Err, "failed to build settings object")
+}
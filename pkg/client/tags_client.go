@@ -0,0 +1,82 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"net/url"
+)
+
+const pathTags = "/api/v2/tags"
+
+// tagsResponse is the response of adding and deleting tags
+type tagsResponse struct {
+	MatchedEntitiesCount int `json:"matchedEntitiesCount"`
+}
+
+// tagsURL returns the URL of the tags API with the given parameters. Entities are matched within the same timeframe
+// ListEntityIds uses, so that the entities listed for a selector are the ones tagged.
+func tagsURL(environmentURL string, entitySelector string, params url.Values) string {
+	params.Set("entitySelector", entitySelector)
+	params.Set("from", genTimeframeUnixMilliString(defaultEntityDurationTimeframeFrom))
+	return environmentURL + pathTags + "?" + params.Encode()
+}
+
+func (d *DynatraceClient) AddTags(ctx context.Context, entitySelector string, tags []Tag) (int, error) {
+	payload, err := json.Marshal(struct {
+		Tags []Tag `json:"tags"`
+	}{Tags: tags})
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := rest.Post(ctx, d.client, tagsURL(d.environmentURL, entitySelector, url.Values{}), payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add tags to entities matching %q: %w", entitySelector, err)
+	}
+	if !success(resp) {
+		return 0, fmt.Errorf("failed to add tags to entities matching %q (HTTP %d)!\n\tResponse was: %s", entitySelector, resp.StatusCode, string(resp.Body))
+	}
+	return parseTagsResponse(resp.Body)
+}
+
+func (d *DynatraceClient) DeleteTag(ctx context.Context, entitySelector string, tag Tag) (int, error) {
+	params := url.Values{"key": []string{tag.Key}}
+	if tag.Value != "" {
+		params.Set("value", tag.Value)
+	}
+
+	resp, err := rest.Delete(ctx, d.client, tagsURL(d.environmentURL, entitySelector, params))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete tag %q of entities matching %q: %w", tag, entitySelector, err)
+	}
+	if !success(resp) {
+		return 0, fmt.Errorf("failed to delete tag %q of entities matching %q (HTTP %d)!\n\tResponse was: %s", tag, entitySelector, resp.StatusCode, string(resp.Body))
+	}
+	return parseTagsResponse(resp.Body)
+}
+
+func parseTagsResponse(body []byte) (int, error) {
+	var parsed tagsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return parsed.MatchedEntitiesCount, nil
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAddTags(t *testing.T) {
+	t.Run("tags are added to entities matching the selector", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/api/v2/tags", req.URL.Path)
+			assert.Equal(t, `type("HOST")`, req.URL.Query().Get("entitySelector"))
+			assert.NotEmpty(t, req.URL.Query().Get("from"))
+
+			body, _ := io.ReadAll(req.Body)
+			assert.JSONEq(t, `{"tags": [{"key": "owner", "value": "team-a"}, {"key": "critical"}]}`, string(body))
+			_, _ = rw.Write([]byte(`{"matchedEntitiesCount": 3, "appliedTags": []}`))
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURL: server.URL, client: server.Client()}
+
+		matched, err := client.AddTags(context.TODO(), `type("HOST")`, []Tag{{Key: "owner", Value: "team-a"}, {Key: "critical"}})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, matched)
+	})
+
+	t.Run("error responses are returned", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = rw.Write([]byte(`{"error": "invalid selector"}`))
+		}))
+		defer server.Close()
+
+		client := DynatraceClient{environmentURL: server.URL, client: server.Client()}
+
+		_, err := client.AddTags(context.TODO(), `invalid`, []Tag{{Key: "owner"}})
+		assert.ErrorContains(t, err, "HTTP 400")
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	tests := []struct {
+		name          string
+		tag           Tag
+		expectedQuery map[string]string
+	}{
+		{
+			name:          "tag with value",
+			tag:           Tag{Key: "owner", Value: "team-a"},
+			expectedQuery: map[string]string{"key": "owner", "value": "team-a"},
+		},
+		{
+			name:          "tag without value",
+			tag:           Tag{Key: "critical"},
+			expectedQuery: map[string]string{"key": "critical", "value": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, http.MethodDelete, req.Method)
+				assert.Equal(t, "/api/v2/tags", req.URL.Path)
+				assert.Equal(t, `type("HOST")`, req.URL.Query().Get("entitySelector"))
+				for k, v := range tt.expectedQuery {
+					assert.Equal(t, v, req.URL.Query().Get(k))
+				}
+				_, _ = rw.Write([]byte(`{"matchedEntitiesCount": 2}`))
+			}))
+			defer server.Close()
+
+			client := DynatraceClient{environmentURL: server.URL, client: server.Client()}
+
+			matched, err := client.DeleteTag(context.TODO(), `type("HOST")`, tt.tag)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, matched)
+		})
+	}
+}
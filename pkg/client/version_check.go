@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
@@ -33,13 +34,13 @@ type ApiVersionObject struct {
 const versionPathClassic = "/api/v1/config/clusterversion"
 
 // GetDynatraceVersion returns the version of an environment
-func GetDynatraceVersion(client *http.Client, environmentURL string) (version.Version, error) {
+func GetDynatraceVersion(ctx context.Context, client *http.Client, environmentURL string) (version.Version, error) {
 	versionURL, err := url.JoinPath(environmentURL, versionPathClassic)
 	if err != nil {
 		return version.Version{}, fmt.Errorf("failed to build URL for API %q on environment URL %q", versionPathClassic, environmentURL)
 	}
 
-	resp, err := rest.Get(client, versionURL)
+	resp, err := rest.Get(ctx, client, versionURL)
 	if err != nil {
 		return version.Version{}, fmt.Errorf("failed to query version of Dynatrace environment: %w", err)
 	}
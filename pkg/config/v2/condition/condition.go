@@ -0,0 +1,298 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package condition evaluates boolean condition expressions, as they can be used to define whether a config is skipped.
+//
+// An expression is first rendered as a Go template, with the environment variables available as {{ .Env.NAME }}, and
+// the name and group of the environment the config is loaded for as {{ .Environment }} and {{ .Group }}.
+// The rendered expression supports:
+//   - the literals true and false
+//   - comparisons of values using == and !=. Values are either quoted ("value" or 'value') or single words.
+//   - negation using !, conjunction using && and disjunction using ||, in decreasing precedence
+//   - parentheses for grouping
+//
+// E.g. '"{{ .Env.STAGE }}" != "prod" && {{ .Environment }} != staging'
+package condition
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+	"unicode"
+)
+
+// Data holds the values available when rendering an expression.
+type Data struct {
+	// Env holds all environment variables
+	Env map[string]string
+	// Environment is the name of the environment the expression is evaluated for
+	Environment string
+	// Group is the name of the group of the environment the expression is evaluated for
+	Group string
+}
+
+// NewData returns the Data of the given environment, holding all current environment variables.
+func NewData(environment, group string) Data {
+	env := make(map[string]string)
+	for _, v := range os.Environ() {
+		if k, val, found := strings.Cut(v, "="); found {
+			env[k] = val
+		}
+	}
+
+	return Data{
+		Env:         env,
+		Environment: environment,
+		Group:       group,
+	}
+}
+
+// IsExpression returns whether the given value needs to be evaluated as an expression, rather than being a plain boolean.
+func IsExpression(value string) bool {
+	return strings.ContainsAny(value, "{}=!&|()")
+}
+
+// Evaluate renders the given expression using data and evaluates the result.
+func Evaluate(expression string, data Data) (bool, error) {
+	tmpl, err := template.New("condition").Option("missingkey=error").Parse(expression)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse condition %q: %w", expression, err)
+	}
+
+	rendered := bytes.Buffer{}
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return false, fmt.Errorf("failed to render condition %q: %w", expression, err)
+	}
+
+	tokens, err := tokenize(rendered.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q (rendered as %q): %w", expression, rendered.String(), err)
+	}
+
+	p := parser{tokens: tokens}
+	result, err := p.parse()
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q (rendered as %q): %w", expression, rendered.String(), err)
+	}
+	return result, nil
+}
+
+type tokenKind int
+
+const (
+	tokenValue tokenKind = iota
+	tokenEquals
+	tokenNotEquals
+	tokenNot
+	tokenAnd
+	tokenOr
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	// quoted is set for values defined within quotes, which are never interpreted as boolean literals
+	quoted bool
+	// pos is the position of the token in the rendered expression
+	pos int
+}
+
+type operator struct {
+	symbol string
+	kind   tokenKind
+}
+
+var operators = []operator{
+	{"==", tokenEquals},
+	{"!=", tokenNotEquals},
+	{"&&", tokenAnd},
+	{"||", tokenOr},
+	{"!", tokenNot},
+	{"(", tokenOpen},
+	{")", tokenClose},
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(s); {
+		if unicode.IsSpace(rune(s[i])) {
+			i++
+			continue
+		}
+
+		if op, found := operatorAt(s, i); found {
+			tokens = append(tokens, token{kind: op.kind, value: op.symbol, pos: i})
+			i += len(op.symbol)
+			continue
+		}
+
+		if s[i] == '"' || s[i] == '\'' {
+			end := strings.IndexByte(s[i+1:], s[i])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokenValue, value: s[i+1 : i+1+end], quoted: true, pos: i})
+			i += end + 2
+			continue
+		}
+
+		start := i
+		for i < len(s) && !unicode.IsSpace(rune(s[i])) && !strings.ContainsRune("\"'()=!&|", rune(s[i])) {
+			i++
+		}
+		if start == i {
+			return nil, fmt.Errorf("unexpected %q at position %d", s[i], i)
+		}
+		tokens = append(tokens, token{kind: tokenValue, value: s[start:i], pos: start})
+	}
+
+	return tokens, nil
+}
+
+func operatorAt(s string, i int) (operator, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(s[i:], op.symbol) {
+			return op, true
+		}
+	}
+	return operator{}, false
+}
+
+// parser evaluates a list of tokens by recursive descent.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parse() (bool, error) {
+	if len(p.tokens) == 0 {
+		return false, fmt.Errorf("condition is empty")
+	}
+
+	result, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("unexpected %q at position %d", p.tokens[p.pos].value, p.tokens[p.pos].pos)
+	}
+	return result, nil
+}
+
+func (p *parser) or() (bool, error) {
+	result, err := p.and()
+	if err != nil {
+		return false, err
+	}
+	for p.next(tokenOr) {
+		right, err := p.and()
+		if err != nil {
+			return false, err
+		}
+		result = result || right
+	}
+	return result, nil
+}
+
+func (p *parser) and() (bool, error) {
+	result, err := p.not()
+	if err != nil {
+		return false, err
+	}
+	for p.next(tokenAnd) {
+		right, err := p.not()
+		if err != nil {
+			return false, err
+		}
+		result = result && right
+	}
+	return result, nil
+}
+
+func (p *parser) not() (bool, error) {
+	if p.next(tokenNot) {
+		result, err := p.not()
+		return !result, err
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (bool, error) {
+	if p.next(tokenOpen) {
+		result, err := p.or()
+		if err != nil {
+			return false, err
+		}
+		if !p.next(tokenClose) {
+			return false, p.unexpected("')'")
+		}
+		return result, nil
+	}
+
+	left, err := p.value()
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case p.next(tokenEquals):
+		right, err := p.value()
+		return left.value == right.value, err
+	case p.next(tokenNotEquals):
+		right, err := p.value()
+		return left.value != right.value, err
+	}
+
+	if !left.quoted {
+		switch strings.ToLower(left.value) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("value %q at position %d is neither 'true' nor 'false', nor compared using '==' or '!='", left.value, left.pos)
+}
+
+func (p *parser) value() (token, error) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenValue {
+		return token{}, p.unexpected("a value")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+// next consumes the next token if it is of the given kind.
+func (p *parser) next(kind tokenKind) bool {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == kind {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) unexpected(expected string) error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("expected %s, but the condition ended. If a value is rendered empty, please quote it", expected)
+	}
+	t := p.tokens[p.pos]
+	return fmt.Errorf("expected %s, but found %q at position %d. If a value is rendered empty, please quote it", expected, t.value, t.pos)
+}
@@ -0,0 +1,80 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package condition
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEvaluate(t *testing.T) {
+	data := Data{
+		Env:         map[string]string{"STAGE": "dev", "EMPTY": ""},
+		Environment: "env1",
+		Group:       "group1",
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+		wantErr    string
+	}{
+		{expression: "true", want: true},
+		{expression: "FALSE", want: false},
+		{expression: `{{ .Env.STAGE }} != "prod"`, want: true},
+		{expression: `'{{ .Env.STAGE }}' == 'prod'`, want: false},
+		{expression: `{{ .Environment }} == env1 && {{ .Group }} == group1`, want: true},
+		{expression: `{{ .Environment }} == env2 || {{ .Group }} == group1`, want: true},
+		{expression: `true || false && false`, want: true},
+		{expression: `(true || false) && false`, want: false},
+		{expression: `!({{ .Env.STAGE }} == dev)`, want: false},
+		{expression: `!!true`, want: true},
+		{expression: `"{{ .Env.EMPTY }}" == ""`, want: true},
+		{expression: `"true" == true`, want: true},
+		{expression: `{{ .Env.EMPTY }} == ""`, wantErr: `expected a value, but found "=="`},
+		{expression: `{{ .Env.UNKNOWN }} == dev`, wantErr: "failed to render condition"},
+		{expression: `{{ .Env.STAGE }`, wantErr: "failed to parse condition"},
+		{expression: `dev`, wantErr: `value "dev" at position 0 is neither 'true' nor 'false'`},
+		{expression: `"true"`, wantErr: `value "true" at position 0 is neither 'true' nor 'false'`},
+		{expression: `(true`, wantErr: "expected ')', but the condition ended"},
+		{expression: `true false`, wantErr: `unexpected "false" at position 5`},
+		{expression: `"dev == dev`, wantErr: "unterminated quote at position 0"},
+		{expression: `dev = dev`, wantErr: `unexpected '=' at position 4`},
+		{expression: ` `, wantErr: "condition is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := Evaluate(tt.expression, data)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsExpression(t *testing.T) {
+	assert.True(t, IsExpression("{{ .Env.SKIP }}"))
+	assert.True(t, IsExpression("a != b"))
+	assert.False(t, IsExpression("true"))
+	assert.False(t, IsExpression("some value"))
+}
@@ -22,7 +22,10 @@ import (
 	compoundParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/compound"
 	envParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
 	listParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/list"
+	locationParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/location"
+	lookupParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/lookup"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	timestampParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/timestamp"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 )
@@ -37,7 +40,7 @@ const (
 
 	// ScopeParameter is special. It is the set scope as a parameter.
 	// A user must not set it as a parameter in the config.
-	// It is only a parameter iff the config is a settings-config.
+	// It is only a parameter iff the config is a settings-config or an extension monitoring configuration.
 	ScopeParameter = "scope"
 
 	// SkipParameter is special in that config should be deployed or not
@@ -56,8 +59,24 @@ const (
 	SettingsTypeId   TypeId = "settings"
 	ClassicApiTypeId TypeId = "classic"
 	EntityTypeId     TypeId = "entity"
+
+	ExtensionTypeId           TypeId = "extension"
+	ExtensionMonitoringTypeId TypeId = "extensionMonitoring"
+)
+
+const (
+	// ExtensionCoordinateType is the coordinate type of all configs of [ExtensionType]. It differs from the type ID,
+	// as the classic API of Extensions 1.0 is already named 'extension'.
+	ExtensionCoordinateType = "extension-v2"
+
+	// ExtensionMonitoringCoordinateType is the coordinate type of all configs of [ExtensionMonitoringType].
+	ExtensionMonitoringCoordinateType = "extension-monitoring"
 )
 
+// VariantSeparator separates the config ID from the name of the variant in the coordinates of config variants, e.g.
+// 'cpu-alert.eu' for the variant 'eu' of the config 'cpu-alert'.
+const VariantSeparator = "."
+
 type Type interface {
 	// ID returns the type-id.
 	ID() TypeId
@@ -87,6 +106,32 @@ func (EntityType) ID() TypeId {
 	return EntityTypeId
 }
 
+// ExtensionType is an Extensions 2.0 extension. Deploying it uploads the extension's artifact, if one is defined, and
+// activates the extension version defined by the config's payload.
+type ExtensionType struct {
+	// Name of the extension, e.g. com.dynatrace.extension.postgres
+	Name string
+	// ArtifactPath optionally is the path of the extension's zip artifact, relative to the config file.
+	ArtifactPath string
+	// Artifact holds the content of the zip artifact, if ArtifactPath is set.
+	Artifact []byte
+}
+
+func (ExtensionType) ID() TypeId {
+	return ExtensionTypeId
+}
+
+// ExtensionMonitoringType is a monitoring configuration of an Extensions 2.0 extension. Like settings, it has
+// a scope, which is stored as [ScopeParameter].
+type ExtensionMonitoringType struct {
+	// Extension is the name of the extension the monitoring configuration belongs to
+	Extension string
+}
+
+func (ExtensionMonitoringType) ID() TypeId {
+	return ExtensionMonitoringTypeId
+}
+
 // Config struct defining a configuration which can be deployed.
 type Config struct {
 	// template used to render the request send to the dynatrace api
@@ -112,10 +157,53 @@ type Config struct {
 
 	// OriginObjectId is the DT object ID of the object when it was downloaded from an environment
 	OriginObjectId string
+
+	// IgnoreOnDownload marks the remote object of this configuration to be left out when downloading.
+	IgnoreOnDownload bool
+
+	// IgnoreOnPurge marks the remote object of this configuration to be kept when purging an environment.
+	IgnoreOnPurge bool
+
+	// ExcludeFromDiff marks this configuration to be left out when comparing a project with an environment.
+	ExcludeFromDiff bool
+
+	// DependsOn holds the coordinates of all configs explicitly defined via 'dependsOn' which need to be deployed
+	// before this configuration, in addition to the configs referenced by parameters.
+	DependsOn []coordinate.Coordinate
+
+	// Priority defines the order of configs which could be deployed at the same time, as their dependencies are met.
+	// Configs with a higher priority are deployed first. It defaults to 0 and may be negative.
+	Priority int
+
+	// Position defines the position of the config among all configs of an ordered API, starting at 1.
+	// It is captured on download and reapplied after deployment. 0 means the config has no defined position.
+	Position int
+
+	// MovedFrom is the coordinate the config was originally defined at, if it was moved to its current coordinate.
+	// Deployed objects are identified by this coordinate, so that moving a config does not create new objects.
+	// The zero value means the config was not moved.
+	MovedFrom coordinate.Coordinate
+
+	// MissingKey defines how placeholders of the template without defined parameter are rendered. If empty, the
+	// behavior defined by template.DefaultMissingKey applies.
+	MissingKey template.MissingKey
+
+	// Variables holds the names of all parameters added from the variables of the project the config belongs to.
+	// They are not part of the config definition, thus never written with the config.
+	Variables []string
+
+	// Instantiated states that the project of the config is instantiated with variables. As several instantiations
+	// share the same config IDs, the settings objects of such configs are identified by their project as well.
+	Instantiated bool
 }
 
 func (c *Config) Render(properties map[string]interface{}) (string, error) {
-	renderedConfig, err := template.Render(c.Template, properties)
+	missingKey := c.MissingKey
+	if missingKey == "" {
+		missingKey = template.DefaultMissingKey()
+	}
+
+	renderedConfig, err := template.Render(c.Template, properties, missingKey)
 	if err != nil {
 		return "", err
 	}
@@ -143,16 +231,26 @@ func (c *Config) Render(properties map[string]interface{}) (string, error) {
 
 // DefaultParameterParsers map defining a set of default parsers which can be used to load configurations
 var DefaultParameterParsers = map[string]parameter.ParameterSerDe{
-	refParam.ReferenceParameterType:           refParam.ReferenceParameterSerde,
-	valueParam.ValueParameterType:             valueParam.ValueParameterSerde,
-	envParam.EnvironmentVariableParameterType: envParam.EnvironmentVariableParameterSerde,
-	compoundParam.CompoundParameterType:       compoundParam.CompoundParameterSerde,
-	listParam.ListParameterType:               listParam.ListParameterSerde,
+	refParam.ReferenceParameterType:              refParam.ReferenceParameterSerde,
+	valueParam.ValueParameterType:                valueParam.ValueParameterSerde,
+	envParam.EnvironmentVariableParameterType:    envParam.EnvironmentVariableParameterSerde,
+	compoundParam.CompoundParameterType:          compoundParam.CompoundParameterSerde,
+	listParam.ListParameterType:                  listParam.ListParameterSerde,
+	locationParam.SyntheticLocationParameterType: locationParam.SyntheticLocationParameterSerde,
+	lookupParam.LookupParameterType:              lookupParam.LookupParameterSerde,
+	timestampParam.TimestampParameterType:        timestampParam.TimestampParameterSerde,
 }
 
+// References returns the coordinates of all configs this config depends on - either referenced by a parameter or
+// explicitly defined via DependsOn.
 func (c *Config) References() []coordinate.Coordinate {
+	return append(c.ParameterReferences(), c.DependsOn...)
+}
 
-	count := 0
+// ParameterReferences returns the coordinates of all configs referenced by a parameter of this config. Unlike
+// References, configs only defined via DependsOn are not included.
+func (c *Config) ParameterReferences() []coordinate.Coordinate {
+	count := len(c.DependsOn)
 	for _, p := range c.Parameters {
 		count += len(p.GetReferences())
 	}
@@ -164,6 +262,24 @@ func (c *Config) References() []coordinate.Coordinate {
 			refs = append(refs, references[i].Config)
 		}
 	}
-
 	return refs
 }
+
+// SettingsObjectId returns the ID the settings object of this config is identified by, which its externalId is
+// generated from. It is the config ID of the origin coordinate, prefixed with the project for instantiated projects.
+func (c *Config) SettingsObjectId() string {
+	origin := c.OriginCoordinate()
+	if c.Instantiated {
+		return origin.Project + ":" + origin.ConfigId
+	}
+	return origin.ConfigId
+}
+
+// OriginCoordinate returns the coordinate deployed objects of this config are identified by. This is the coordinate
+// the config was moved from, if it was moved, and its current coordinate otherwise.
+func (c *Config) OriginCoordinate() coordinate.Coordinate {
+	if c.MovedFrom != (coordinate.Coordinate{}) {
+		return c.MovedFrom
+	}
+	return c.Coordinate
+}
@@ -0,0 +1,169 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"gopkg.in/yaml.v3"
+)
+
+// parameterBlock is a mapping of parameters, or the mapping defining a single parameter, within a config file
+type parameterBlock struct {
+	// parent is the mapping holding the block as value at index
+	parent *yaml.Node
+	index  int
+	// name is used to derive the anchor name of the block
+	name string
+}
+
+func (b parameterBlock) node() *yaml.Node {
+	return b.parent.Content[b.index]
+}
+
+// anchorRepeatedParameters factors parameter blocks defined more than once in the given config file into YAML
+// anchors, which the repeated blocks refer to by alias. Whole 'parameters' mappings are factored first, then single
+// parameters defined by a mapping, e.g. references. If no block is repeated, the data is returned unchanged.
+func anchorRepeatedParameters(data []byte) ([]byte, error) {
+	root := yamlnode.Parse(data)
+	if root == nil {
+		return data, nil
+	}
+
+	anchors := make(map[string]struct{})
+
+	parameterMaps := parameterMappings(root)
+	changed := anchorRepeated(parameterMaps, anchors)
+
+	var parameters []parameterBlock
+	for _, m := range parameterMaps {
+		if m.node().Kind != yaml.MappingNode {
+			continue // replaced by an alias
+		}
+		for i := 1; i < len(m.node().Content); i += 2 {
+			if m.node().Content[i].Kind == yaml.MappingNode {
+				parameters = append(parameters, parameterBlock{parent: m.node(), index: i, name: m.node().Content[i-1].Value})
+			}
+		}
+	}
+	changed = anchorRepeated(parameters, anchors) || changed
+
+	if !changed {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to serialize anchored parameters: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to serialize anchored parameters: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// parameterMappings returns the 'parameters' mappings of all configs and their overrides, in document order.
+func parameterMappings(root *yaml.Node) []parameterBlock {
+	var result []parameterBlock
+
+	add := func(definition *yaml.Node, name string) {
+		if definition == nil || definition.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 1; i < len(definition.Content); i += 2 {
+			if definition.Content[i-1].Value == "parameters" && definition.Content[i].Kind == yaml.MappingNode && len(definition.Content[i].Content) > 0 {
+				result = append(result, parameterBlock{parent: definition, index: i, name: name + "-parameters"})
+			}
+		}
+	}
+
+	for _, c := range yamlnode.Items(yamlnode.Get(root, "configs")) {
+		id := yamlnode.ScalarValue(c, "id")
+		add(yamlnode.Get(c, "config"), id)
+		for _, o := range yamlnode.Items(yamlnode.Get(c, "groupOverrides")) {
+			add(yamlnode.Get(o, "override"), id+"-"+yamlnode.ScalarValue(o, "group"))
+		}
+		for _, o := range yamlnode.Items(yamlnode.Get(c, "environmentOverrides")) {
+			add(yamlnode.Get(o, "override"), id+"-"+yamlnode.ScalarValue(o, "environment"))
+		}
+	}
+	return result
+}
+
+// anchorRepeated anchors the first of all equal blocks and replaces the others by an alias. It returns whether any
+// block was replaced.
+func anchorRepeated(blocks []parameterBlock, anchors map[string]struct{}) bool {
+	keys := make([]string, len(blocks))
+	count := make(map[string]int, len(blocks))
+	for i, b := range blocks {
+		keys[i] = canonical(b.node())
+		count[keys[i]]++
+	}
+
+	changed := false
+	anchored := make(map[string]*yaml.Node)
+	for i, b := range blocks {
+		if count[keys[i]] < 2 {
+			continue
+		}
+
+		anchor, found := anchored[keys[i]]
+		if !found {
+			anchor = b.node()
+			anchor.Anchor = uniqueAnchorName(b.name, anchors)
+			anchored[keys[i]] = anchor
+			continue
+		}
+
+		b.parent.Content[b.index] = &yaml.Node{Kind: yaml.AliasNode, Alias: anchor, Value: anchor.Anchor}
+		changed = true
+	}
+	return changed
+}
+
+// canonical returns a representation of the given node that is equal for nodes of equal content
+func canonical(n *yaml.Node) string {
+	data, err := yaml.Marshal(n)
+	if err != nil {
+		return fmt.Sprintf("%p", n) // never equal to another node
+	}
+	return string(data)
+}
+
+var invalidAnchorCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func uniqueAnchorName(name string, anchors map[string]struct{}) string {
+	base := invalidAnchorCharacters.ReplaceAllString(name, "_")
+	if base == "" {
+		base = "parameter"
+	}
+
+	result := base
+	for i := 2; ; i++ {
+		if _, found := anchors[result]; !found {
+			break
+		}
+		result = fmt.Sprintf("%s-%d", base, i)
+	}
+	anchors[result] = struct{}{}
+	return result
+}
@@ -0,0 +1,118 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"gopkg.in/yaml.v2"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestAnchorRepeatedParameters(t *testing.T) {
+	given := `configs:
+- id: a
+  config:
+    name: a
+    parameters:
+      zone:
+        type: reference
+        configType: management-zone
+        configId: zone
+        property: id
+      threshold: 5
+- id: b
+  config:
+    name: b
+    parameters:
+      zone:
+        type: reference
+        configType: management-zone
+        configId: zone
+        property: id
+      threshold: 10
+  environmentOverrides:
+  - environment: prod
+    override:
+      parameters:
+        zone:
+          type: reference
+          configType: management-zone
+          configId: zone
+          property: id
+        threshold: 10
+`
+
+	want := `configs:
+  - id: a
+    config:
+      name: a
+      parameters:
+        zone: &zone
+          type: reference
+          configType: management-zone
+          configId: zone
+          property: id
+        threshold: 5
+  - id: b
+    config:
+      name: b
+      parameters: &b-parameters
+        zone: *zone
+        threshold: 10
+    environmentOverrides:
+      - environment: prod
+        override:
+          parameters: *b-parameters
+`
+
+	got, err := anchorRepeatedParameters([]byte(given))
+
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), want)
+
+	var givenContent, gotContent topLevelDefinition
+	assert.NilError(t, yaml.Unmarshal([]byte(given), &givenContent))
+	assert.NilError(t, yaml.Unmarshal(got, &gotContent))
+	assert.DeepEqual(t, gotContent, givenContent)
+}
+
+func TestAnchorRepeatedParameters_LeavesFilesWithoutRepeatedParametersUnchanged(t *testing.T) {
+	given := `configs:
+- id: a
+  config:
+    name: a
+    parameters:
+      threshold: 5
+      zone:
+        type: reference
+        configType: management-zone
+        configId: zone
+        property: id
+- id: b
+  config:
+    name: b
+    parameters:
+      threshold: 5
+`
+
+	got, err := anchorRepeatedParameters([]byte(given))
+
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), given)
+}
@@ -17,9 +17,14 @@ package v2
 import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/condition"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -28,6 +33,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
 	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/transform"
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
@@ -47,6 +53,11 @@ type LoaderContext struct {
 	Environments    []manifest.EnvironmentDefinition
 	KnownApis       map[string]struct{}
 	ParametersSerDe map[string]parameter.ParameterSerDe
+	// Variables of the project, which are added as value parameters to all configs not defining a parameter of the same name
+	Variables map[string]string
+	// Strict states that unknown keys in sections of config files which are decoded leniently, like the 'type' of a
+	// config, fail loading instead of only being warned about
+	Strict bool
 }
 
 // LoadConfigs will search a given path for configuration yamls and parses them.
@@ -89,6 +100,40 @@ type ConfigLoaderContext struct {
 	*LoaderContext
 	Folder string
 	Path   string
+
+	// locations holds the positions of the config definitions in the file at Path, keyed by their ids
+	locations map[string]configLocation
+}
+
+// configLocation is the position of a config definition in its file, as well as of the parameters it defines
+type configLocation struct {
+	position   yamlnode.Position
+	parameters map[string]yamlnode.Position
+}
+
+// findConfigLocations looks up the positions of the config definitions and their parameters in the node tree of a
+// config file. If an id is defined several times, the first definition is returned.
+func findConfigLocations(root *yamlnode.Node) map[string]configLocation {
+	locations := make(map[string]configLocation)
+
+	for _, item := range yamlnode.Items(yamlnode.Get(root, "configs")) {
+		id := yamlnode.ScalarValue(item, "id")
+		if _, exists := locations[id]; exists {
+			continue
+		}
+
+		parameters := make(map[string]yamlnode.Position)
+		for _, key := range yamlnode.Keys(yamlnode.Get(item, "config", "parameters")) {
+			parameters[key.Value] = yamlnode.PositionOf(key)
+		}
+
+		locations[id] = configLocation{
+			position:   yamlnode.PositionOf(item),
+			parameters: parameters,
+		}
+	}
+
+	return locations
 }
 
 type SingleConfigLoadContext struct {
@@ -99,6 +144,8 @@ type SingleConfigLoadContext struct {
 type DefinitionParserError struct {
 	Location coordinate.Coordinate
 	Path     string
+	// Position is the line and column of the erroneous definition in the file at Path, if known
+	Position yamlnode.Position
 	Reason   string
 }
 
@@ -109,8 +156,9 @@ func newDefinitionParserError(configId string, context *SingleConfigLoadContext,
 			Type:     context.Type,
 			ConfigId: configId,
 		},
-		Path:   context.Path,
-		Reason: reason,
+		Path:     context.Path,
+		Position: context.locations[configId].position,
+		Reason:   reason,
 	}
 }
 
@@ -148,10 +196,16 @@ type ParameterDefinitionParserError struct {
 func newParameterDefinitionParserError(name string, configId string, context *SingleConfigLoadContext,
 	environment manifest.EnvironmentDefinition, reason string) ParameterDefinitionParserError {
 
-	return ParameterDefinitionParserError{
+	err := ParameterDefinitionParserError{
 		DetailedDefinitionParserError: newDetailedDefinitionParserError(configId, context, environment, reason),
 		ParameterName:                 name,
 	}
+
+	if p, found := context.locations[configId].parameters[name]; found {
+		err.Position = p
+	}
+
+	return err
 }
 
 var (
@@ -162,12 +216,12 @@ var (
 
 func (e ParameterDefinitionParserError) Error() string {
 	return fmt.Sprintf("%s: cannot parse parameter definition in `%s`: %s",
-		e.ParameterName, e.Path, e.Reason)
+		e.ParameterName, e.Position.Format(e.Path), e.Reason)
 }
 
 func (e DefinitionParserError) Error() string {
 	return fmt.Sprintf("cannot parse definition in `%s`: %s",
-		e.Path, e.Reason)
+		e.Position.Format(e.Path), e.Reason)
 }
 
 func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs []Config, errors []error) {
@@ -179,6 +233,7 @@ func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs
 	}
 
 	definition := topLevelDefinition{}
+	root := yamlnode.Parse(data)
 
 	err = yaml.UnmarshalStrict(data, &definition)
 
@@ -189,11 +244,26 @@ func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs
 			}
 		}
 
+		if unknownKeys := yamlnode.UnknownKeys(root, reflect.TypeOf(definition)); len(unknownKeys) > 0 {
+			return nil, unknownKeyErrors(filePath, unknownKeys)
+		}
+
 		return nil, []error{
 			fmt.Errorf("failed to load config '%s':\n%w", filePath, err),
 		}
 	}
 
+	// sections like the 'type' of a config are decoded leniently, thus unknown keys in them do not fail decoding
+	if unknownKeys := yamlnode.UnknownKeys(root, reflect.TypeOf(definition)); len(unknownKeys) > 0 {
+		if context.Strict {
+			return nil, unknownKeyErrors(filePath, unknownKeys)
+		}
+
+		for _, k := range unknownKeys {
+			log.Warn("Ignoring %s in config '%s'", k, filePath)
+		}
+	}
+
 	if len(definition.Configs) == 0 {
 		return nil, []error{
 			fmt.Errorf("no configurations found in file '%s'", filePath),
@@ -204,6 +274,7 @@ func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs
 		LoaderContext: context,
 		Folder:        folder,
 		Path:          filePath,
+		locations:     findConfigLocations(root),
 	}
 
 	for _, config := range definition.Configs {
@@ -224,6 +295,14 @@ func parseConfigs(fs afero.Fs, context *LoaderContext, filePath string) (configs
 	return configs, nil
 }
 
+func unknownKeyErrors(filePath string, unknownKeys []yamlnode.UnknownKey) []error {
+	errs := make([]error, len(unknownKeys))
+	for i, k := range unknownKeys {
+		errs[i] = fmt.Errorf("failed to load config '%s': %s", filePath, k)
+	}
+	return errs
+}
+
 // parseDefinition parses a single config entry
 func parseDefinition(
 	fs afero.Fs,
@@ -244,19 +323,73 @@ func parseDefinition(
 		return nil, append(errors, newDefinitionParserError(configId, singleConfigContext, e.Error()))
 	}
 
+	dependsOn, err := parseDependsOn(singleConfigContext, configId, definition.DependsOn)
+	if err != nil {
+		return nil, append(errors, newDefinitionParserError(configId, singleConfigContext, err.Error()))
+	}
+
+	if definition.Position < 0 {
+		return nil, append(errors, newDefinitionParserError(configId, singleConfigContext, fmt.Sprintf("position must not be negative, but is %d", definition.Position)))
+	}
+
+	var movedFrom coordinate.Coordinate
+	if m := definition.MovedFrom; m != nil {
+		movedFrom = coordinate.Coordinate{Project: m.Project, Type: m.Type, ConfigId: m.ConfigId}
+		if movedFrom.Type != singleConfigContext.Type {
+			return nil, append(errors, newDefinitionParserError(configId, singleConfigContext, fmt.Sprintf("`movedFrom` must have the type of the config `%s`, but has `%s`", singleConfigContext.Type, movedFrom.Type)))
+		}
+	}
+
+	var missingKey template.MissingKey
+	if definition.MissingKey != "" {
+		if missingKey, err = template.ParseMissingKey(definition.MissingKey); err != nil {
+			return nil, append(errors, newDefinitionParserError(configId, singleConfigContext, err.Error()))
+		}
+	}
+
+	if err := validateVariants(definition.Variants); err != nil {
+		return nil, append(errors, newDefinitionParserError(configId, singleConfigContext, err.Error()))
+	}
+
 	groupOverrideMap := toGroupOverrideMap(definition.GroupOverrides)
 	environmentOverrideMap := toEnvironmentOverrideMap(definition.EnvironmentOverrides)
 
-	for _, environment := range context.Environments {
-		result, definitionErrors := parseDefinitionForEnvironment(fs, singleConfigContext, configId, environment,
-			definition, groupOverrideMap, environmentOverrideMap)
+	// configs without variants are treated as having a single unnamed variant
+	variants := []*variantDefinition{nil}
+	if len(definition.Variants) > 0 {
+		variants = make([]*variantDefinition, len(definition.Variants))
+		for i := range definition.Variants {
+			variants[i] = &definition.Variants[i]
+		}
+	}
 
-		if definitionErrors != nil {
-			errors = append(errors, definitionErrors...)
-			continue
+	for _, variant := range variants {
+		id := configId
+		variantMovedFrom := movedFrom
+		if variant != nil {
+			id = VariantConfigId(configId, variant.Name)
+			// each variant was moved from the same variant of the original config, so their objects stay distinct
+			if movedFrom != (coordinate.Coordinate{}) {
+				variantMovedFrom.ConfigId = VariantConfigId(movedFrom.ConfigId, variant.Name)
+			}
 		}
 
-		results = append(results, result)
+		for _, environment := range context.Environments {
+			result, definitionErrors := parseDefinitionForEnvironment(fs, singleConfigContext, id, environment,
+				definition, variant, groupOverrideMap, environmentOverrideMap)
+
+			if definitionErrors != nil {
+				errors = append(errors, definitionErrors...)
+				continue
+			}
+
+			result.DependsOn = dependsOn
+			result.Priority = definition.Priority
+			result.Position = definition.Position
+			result.MovedFrom = variantMovedFrom
+			result.MissingKey = missingKey
+			results = append(results, result)
+		}
 	}
 
 	if errors != nil {
@@ -266,6 +399,85 @@ func parseDefinition(
 	return results, nil
 }
 
+// parseDependsOn parses the coordinates of the 'dependsOn' field of a config definition. Each entry is either a
+// short list of [configId], [configType, configId] or [project, configType, configId], or a mapping of the fields
+// 'project', 'configType' and 'configId'. Omitted fields are filled in from the current config.
+func parseDependsOn(context *SingleConfigLoadContext, configId string, dependsOn []interface{}) ([]coordinate.Coordinate, error) {
+	if len(dependsOn) == 0 {
+		return nil, nil
+	}
+
+	result := make([]coordinate.Coordinate, 0, len(dependsOn))
+	for _, entry := range dependsOn {
+		var c coordinate.Coordinate
+		var err error
+
+		switch v := entry.(type) {
+		case []interface{}:
+			c, err = arrayToDependsOnCoordinate(context, v)
+		case map[interface{}]interface{}:
+			c, err = mapToDependsOnCoordinate(context, v)
+		default:
+			err = fmt.Errorf("invalid `dependsOn` entry `%v`: expected a list like [configType, configId] or a mapping with fields `project`, `configType` and `configId`", entry)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if c.Project == context.ProjectId && c.Type == context.Type && c.ConfigId == configId {
+			return nil, fmt.Errorf("invalid `dependsOn` entry `%s`: a config can not depend on itself", c)
+		}
+
+		result = append(result, c)
+	}
+
+	return result, nil
+}
+
+func arrayToDependsOnCoordinate(context *SingleConfigLoadContext, arr []interface{}) (coordinate.Coordinate, error) {
+	c := coordinate.Coordinate{Project: context.ProjectId, Type: context.Type}
+
+	switch len(arr) {
+	case 1:
+		c.ConfigId = toString(arr[0])
+	case 2:
+		c.Type = toString(arr[0])
+		c.ConfigId = toString(arr[1])
+	case 3:
+		c.Project = toString(arr[0])
+		c.Type = toString(arr[1])
+		c.ConfigId = toString(arr[2])
+	default:
+		return coordinate.Coordinate{}, fmt.Errorf("`dependsOn` entries must have between 1 and 3 elements. you provided `%d`", len(arr))
+	}
+
+	return c, nil
+}
+
+func mapToDependsOnCoordinate(context *SingleConfigLoadContext, m map[interface{}]interface{}) (coordinate.Coordinate, error) {
+	c := coordinate.Coordinate{Project: context.ProjectId, Type: context.Type}
+
+	for k, v := range m {
+		switch toString(k) {
+		case "project":
+			c.Project = toString(v)
+		case "configType":
+			c.Type = toString(v)
+		case "configId":
+			c.ConfigId = toString(v)
+		default:
+			return coordinate.Coordinate{}, fmt.Errorf("unknown field `%v` in `dependsOn` entry. Allowed fields are `project`, `configType` and `configId`", k)
+		}
+	}
+
+	if c.ConfigId == "" {
+		return coordinate.Coordinate{}, fmt.Errorf("`dependsOn` entry `%v` is missing field `configId`", m)
+	}
+
+	return c, nil
+}
+
 func toEnvironmentOverrideMap(environments []environmentOverride) map[string]environmentOverride {
 	result := make(map[string]environmentOverride)
 
@@ -292,6 +504,7 @@ func parseDefinitionForEnvironment(
 	configId string,
 	environment manifest.EnvironmentDefinition,
 	definition topLevelConfigDefinition,
+	variant *variantDefinition,
 	groupOverrides map[string]groupOverride,
 	environmentOverride map[string]environmentOverride,
 ) (Config, []error) {
@@ -303,6 +516,12 @@ func parseDefinitionForEnvironment(
 
 	applyOverrides(&configDefinition, definition.Config)
 
+	if variant != nil {
+		for name, param := range variant.Parameters {
+			configDefinition.Parameters[name] = param
+		}
+	}
+
 	if override, found := groupOverrides[environment.Group]; found {
 		applyOverrides(&configDefinition, override.Override)
 	}
@@ -313,7 +532,52 @@ func parseDefinitionForEnvironment(
 
 	configDefinition.Template = filepath.FromSlash(configDefinition.Template)
 
-	return getConfigFromDefinition(fs, context, configId, environment, configDefinition, definition.Type)
+	c, errs := getConfigFromDefinition(fs, context, configId, environment, configDefinition, definition.Type)
+	if errs != nil {
+		return Config{}, errs
+	}
+
+	c.IgnoreOnDownload = definition.IgnoreOnDownload
+	c.IgnoreOnPurge = definition.IgnoreOnPurge
+	c.ExcludeFromDiff = definition.ExcludeFromDiff
+
+	if variant != nil && !variant.appliesTo(environment) {
+		c.Skip = true
+	}
+
+	return c, nil
+}
+
+// VariantConfigId returns the config ID of the given variant of a config.
+func VariantConfigId(configId, variant string) string {
+	return configId + VariantSeparator + variant
+}
+
+// validateVariants ensures that all variants of a config have a unique, valid name.
+func validateVariants(variants []variantDefinition) error {
+	names := make(map[string]bool, len(variants))
+	for i, v := range variants {
+		if v.Name == "" {
+			return fmt.Errorf("variant %d has no `name`", i+1)
+		}
+		if strings.ContainsAny(v.Name, ":/\\ ") {
+			return fmt.Errorf("name of variant %q must not contain colons, slashes or spaces", v.Name)
+		}
+		if names[v.Name] {
+			return fmt.Errorf("duplicate variant %q", v.Name)
+		}
+		names[v.Name] = true
+	}
+	return nil
+}
+
+// appliesTo returns whether the variant is deployed to the given environment. Variants without environments and
+// groups apply to all environments.
+func (v variantDefinition) appliesTo(environment manifest.EnvironmentDefinition) bool {
+	if len(v.Environments) == 0 && len(v.Groups) == 0 {
+		return true
+	}
+	return slices.Contains(v.Environments, environment.Name) || slices.Contains(v.Groups, environment.Group)
 }
 
 func applyOverrides(base *configDefinition, override configDefinition) {
@@ -366,6 +630,15 @@ func getConfigFromDefinition(
 		parameters = make(map[string]parameter.Parameter)
 	}
 
+	var variables []string
+	for name, v := range context.Variables {
+		if _, defined := parameters[name]; !defined {
+			parameters[name] = &valueParam.ValueParameter{Value: v}
+			variables = append(variables, name)
+		}
+	}
+	sort.Strings(variables)
+
 	skipConfig := false
 
 	if definition.Skip != nil {
@@ -393,8 +666,15 @@ func getConfigFromDefinition(
 		return Config{}, errors
 	}
 
-	if configType.isSettings() {
-		scopeParam, err := parseParameter(context, environment, configId, ScopeParameter, configType.Settings.Scope)
+	if configType.isSettings() || configType.isExtensionMonitoring() || (configType.isClassic() && configType.Scope != nil) {
+		scope := configType.Settings.Scope
+		if configType.isExtensionMonitoring() {
+			scope = configType.ExtensionMonitoring.Scope
+		} else if configType.isClassic() {
+			scope = configType.Scope
+		}
+
+		scopeParam, err := parseParameter(context, environment, configId, ScopeParameter, scope)
 		if err != nil {
 			return Config{}, []error{fmt.Errorf("failed to parse scope: %w", err)}
 		}
@@ -406,7 +686,7 @@ func getConfigFromDefinition(
 		parameters[ScopeParameter] = scopeParam
 	}
 
-	t, err := getType(configType)
+	t, err := getType(fs, context, configType)
 	if err != nil {
 		return Config{}, []error{fmt.Errorf("failed to parse type of config %q: %w", configId, err)}
 	}
@@ -424,10 +704,12 @@ func getConfigFromDefinition(
 		Parameters:     parameters,
 		Skip:           skipConfig,
 		OriginObjectId: definition.OriginObjectId,
+		Variables:      variables,
+		Instantiated:   len(context.Variables) > 0,
 	}, nil
 }
 
-func getType(typeDef typeDefinition) (Type, error) {
+func getType(fs afero.Fs, context *SingleConfigLoadContext, typeDef typeDefinition) (Type, error) {
 	switch {
 	case typeDef.isSettings():
 		return SettingsType{
@@ -445,8 +727,27 @@ func getType(typeDef typeDefinition) (Type, error) {
 			EntitiesType: typeDef.Entities.EntitiesType,
 		}, nil
 
+	case typeDef.isExtension():
+		t := ExtensionType{
+			Name:         typeDef.Extension.Name,
+			ArtifactPath: typeDef.Extension.Artifact,
+		}
+		if t.ArtifactPath != "" {
+			artifact, err := afero.ReadFile(fs, filepath.Join(context.Folder, filepath.FromSlash(t.ArtifactPath)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read artifact of extension %q: %w", t.Name, err)
+			}
+			t.Artifact = artifact
+		}
+		return t, nil
+
+	case typeDef.isExtensionMonitoring():
+		return ExtensionMonitoringType{
+			Extension: typeDef.ExtensionMonitoring.Extension,
+		}, nil
+
 	default:
-		return nil, fmt.Errorf("invalid typeDefinition - is neither Setting, Classic nor Entity")
+		return nil, fmt.Errorf("invalid typeDefinition - is neither Setting, Classic, Entity, Extension nor Extension Monitoring")
 	}
 }
 
@@ -465,6 +766,14 @@ func parseSkip(
 		return false, newParameterDefinitionParserError(SkipParameter, configId, context, environmentDefinition, "must be of type 'value' or 'environment'")
 	}
 
+	if expression, ok := conditionExpression(parsed); ok {
+		skip, err := condition.Evaluate(expression, condition.NewData(environmentDefinition.Name, environmentDefinition.Group))
+		if err != nil {
+			return false, newParameterDefinitionParserError(SkipParameter, configId, context, environmentDefinition, err.Error())
+		}
+		return skip, nil
+	}
+
 	resolved, err := parsed.ResolveValue(parameter.ResolveContext{
 		ConfigCoordinate: coordinate.Coordinate{
 			Project:  context.ProjectId,
@@ -487,6 +796,21 @@ func parseSkip(
 	return retVal, err
 }
 
+// conditionExpression returns the condition expression defined by a value parameter, e.g. `skip: '{{ .Env.STAGE }} != prod'`.
+// The expression is evaluated as is, as resolving the parameter would escape it.
+func conditionExpression(p parameter.Parameter) (string, bool) {
+	v, ok := p.(*valueParam.ValueParameter)
+	if !ok {
+		return "", false
+	}
+
+	expression, ok := v.Value.(string)
+	if !ok || !condition.IsExpression(expression) {
+		return "", false
+	}
+	return expression, true
+}
+
 // isSupportedParamTypeForSkip check is 'skip' section of configuration supports specified param type
 func isSupportedParamTypeForSkip(p parameter.Parameter) bool {
 	switch p.GetType() {
@@ -569,15 +893,28 @@ func parseParameter(context *SingleConfigLoadContext, environment manifest.Envir
 				fmt.Sprintf("unknown parameter type `%s`", parameterType))
 		}
 
-		return serDe.Deserializer(parameter.ParameterParserContext{
+		values := maps.ToStringMap(val)
+		rawTransformations, hasTransformations := values[transform.Field]
+		delete(values, transform.Field)
+
+		result, err := serDe.Deserializer(parameter.ParameterParserContext{
 			Coordinate: coordinate.Coordinate{
 				Project:  context.ProjectId,
 				Type:     context.Type,
 				ConfigId: configId,
 			},
 			ParameterName: name,
-			Value:         maps.ToStringMap(val),
+			Value:         values,
 		})
+		if err != nil || !hasTransformations {
+			return result, err
+		}
+
+		transformations, err := transform.Parse(rawTransformations)
+		if err != nil {
+			return nil, newParameterDefinitionParserError(name, configId, context, environment, err.Error())
+		}
+		return transform.New(result, transformations), nil
 	}
 
 	return valueParam.New(param), nil
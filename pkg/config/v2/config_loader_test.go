@@ -18,10 +18,12 @@ package v2
 
 import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/compound"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/list"
 	ref "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/transform"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
@@ -258,7 +260,7 @@ configs:
   type:
     api: some-api`,
 			nil,
-			[]string{"skip: cannot parse parameter definition in `test-file.yaml`: failed to resolve value: skip: cannot parse parameter: environment variable `ENV_VAR_SKIP_NOT_EXISTS` not set"},
+			[]string{"skip: cannot parse parameter definition in `test-file.yaml:3:3`: failed to resolve value: skip: cannot parse parameter: environment variable `ENV_VAR_SKIP_NOT_EXISTS` not set"},
 		},
 		{
 			"Skip parameter is defined with a wrong value - should throw an error",
@@ -279,6 +281,55 @@ configs:
 			nil,
 			[]string{"resolved value can only be 'true' or 'false'"},
 		},
+		{
+			"Skip parameter is defined as a condition expression",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    skip: '"{{ .Environment }}" == "env name" && ({{ .Group }} != prod || false)'
+  type:
+    api: some-api`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "profile",
+					},
+					Type: ClassicApiType{
+						Api: "some-api",
+					},
+					Parameters: Parameters{
+						"name": &value.ValueParameter{Value: "Star Trek Service"},
+					},
+					Skip:        true,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+			nil,
+		},
+		{
+			"Skip parameter is defined as an invalid condition expression - should throw an error",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile
+  config:
+    name: Star Trek Service
+    template: profile.json
+    skip: '{{ .Group }} != '
+  type:
+    api: some-api`,
+			nil,
+			[]string{"expected a value, but the condition ended"},
+		},
 		{
 			"Skip parameter is defined with a wrong value - should throw an error",
 			"test-file.yaml",
@@ -346,6 +397,357 @@ configs:
 			},
 			nil,
 		},
+		{
+			"loads ignore annotations",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  ignoreOnDownload: true
+  ignoreOnPurge: true
+  excludeFromDiff: true`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Parameters: Parameters{
+						"name":         &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						ScopeParameter: &value.ValueParameter{Value: "tenant"},
+					},
+					Skip:             false,
+					Environment:      "env name",
+					Group:            "default",
+					IgnoreOnDownload: true,
+					IgnoreOnPurge:    true,
+					ExcludeFromDiff:  true,
+				},
+			},
+			nil,
+		},
+		{
+			"loads explicit dependencies and priority",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  dependsOn:
+  - [other-profile]
+  - [some-api, some-config]
+  - [other-project, some-api, some-config]
+  - {configType: some-api, configId: another-config}
+  priority: 5`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Parameters: Parameters{
+						"name":         &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						ScopeParameter: &value.ValueParameter{Value: "tenant"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+					DependsOn: []coordinate.Coordinate{
+						{Project: "project", Type: "builtin:profile.test", ConfigId: "other-profile"},
+						{Project: "project", Type: "some-api", ConfigId: "some-config"},
+						{Project: "other-project", Type: "some-api", ConfigId: "some-config"},
+						{Project: "project", Type: "some-api", ConfigId: "another-config"},
+					},
+					Priority: 5,
+				},
+			},
+			nil,
+		},
+		{
+			"fails to load invalid explicit dependencies",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  dependsOn:
+  - [a, b, c, d]`,
+			nil,
+			[]string{"`dependsOn` entries must have between 1 and 3 elements"},
+		},
+		{
+			"fails to load dependency on itself",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  dependsOn:
+  - [profile-id]`,
+			nil,
+			[]string{"a config can not depend on itself"},
+		},
+		{
+			"loads position",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  position: 2`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Parameters: Parameters{
+						"name":         &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						ScopeParameter: &value.ValueParameter{Value: "tenant"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+					Position:    2,
+				},
+			},
+			nil,
+		},
+		{
+			"fails to load negative position",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  position: -1`,
+			nil,
+			[]string{"position must not be negative, but is -1"},
+		},
+		{
+			"loads classic config with scope",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: action-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    api: some-api
+    scope: APPLICATION-1234`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "some-api",
+						ConfigId: "action-id",
+					},
+					Type: ClassicApiType{
+						Api: "some-api",
+					},
+					Parameters: Parameters{
+						"name":         &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						ScopeParameter: &value.ValueParameter{Value: "APPLICATION-1234"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+				},
+			},
+			nil,
+		},
+		{
+			"loads movedFrom",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  movedFrom:
+    project: old-project
+    configType: 'builtin:profile.test'
+    configId: old-id`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Parameters: Parameters{
+						"name":         &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						ScopeParameter: &value.ValueParameter{Value: "tenant"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+					MovedFrom: coordinate.Coordinate{
+						Project:  "old-project",
+						Type:     "builtin:profile.test",
+						ConfigId: "old-id",
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"fails to load movedFrom of other type",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  movedFrom:
+    project: old-project
+    configType: dashboard
+    configId: old-id`,
+			nil,
+			[]string{"`movedFrom` must have the type of the config `builtin:profile.test`, but has `dashboard`"},
+		},
+		{
+			"loads missingKey",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  missingKey: zero`,
+			[]Config{
+				{
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "builtin:profile.test",
+						ConfigId: "profile-id",
+					},
+					Type: SettingsType{
+						SchemaId:      "builtin:profile.test",
+						SchemaVersion: "1.0",
+					},
+					Parameters: Parameters{
+						"name":         &value.ValueParameter{Value: "Star Trek > Star Wars"},
+						ScopeParameter: &value.ValueParameter{Value: "tenant"},
+					},
+					Skip:        false,
+					Environment: "env name",
+					Group:       "default",
+					MissingKey:  template.MissingKeyZero,
+				},
+			},
+			nil,
+		},
+		{
+			"fails to load unknown missingKey",
+			"test-file.yaml",
+			"test-file.yaml",
+			`
+configs:
+- id: profile-id
+  config:
+    name: 'Star Trek > Star Wars'
+    template: 'profile.json'
+  type:
+    settings:
+      schema: 'builtin:profile.test'
+      schemaVersion: '1.0'
+      scope: 'tenant'
+  missingKey: ignore`,
+			nil,
+			[]string{`unknown missing key behavior "ignore"`},
+		},
 		{
 			"loads settings 2.0 config with full value parameter as scope",
 			"test-file.yaml",
@@ -681,3 +1083,432 @@ configs:
 		})
 	}
 }
+
+func Test_parseConfigs_Extensions(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId: "project",
+		Path:      "some-dir/",
+		Environments: []manifest.EnvironmentDefinition{
+			{Name: "env name", Group: "default"},
+		},
+		ParametersSerDe: DefaultParameterParsers,
+	}
+
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "profile.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "artifacts/extension.zip", []byte("zip content"), 0644)
+	_ = afero.WriteFile(testFs, "extension.yaml", []byte(`
+configs:
+- id: postgres
+  config:
+    name: com.dynatrace.extension.postgres
+    template: profile.json
+  type:
+    extension:
+      name: com.dynatrace.extension.postgres
+      artifact: artifacts/extension.zip
+- id: postgres-db
+  config:
+    name: db
+    template: profile.json
+  type:
+    extensionMonitoring:
+      extension: com.dynatrace.extension.postgres
+      scope: ag_group-default
+`), 0644)
+
+	gotConfigs, gotErrors := parseConfigs(testFs, loaderContext, "extension.yaml")
+	assert.Assert(t, len(gotErrors) == 0, "expected no errors but got: %v", gotErrors)
+	assert.DeepEqual(t, gotConfigs, []Config{
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: ExtensionCoordinateType, ConfigId: "postgres"},
+			Type: ExtensionType{
+				Name:         "com.dynatrace.extension.postgres",
+				ArtifactPath: "artifacts/extension.zip",
+				Artifact:     []byte("zip content"),
+			},
+			Parameters: Parameters{
+				"name": &value.ValueParameter{Value: "com.dynatrace.extension.postgres"},
+			},
+			Environment: "env name",
+			Group:       "default",
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: ExtensionMonitoringCoordinateType, ConfigId: "postgres-db"},
+			Type:       ExtensionMonitoringType{Extension: "com.dynatrace.extension.postgres"},
+			Parameters: Parameters{
+				"name":         &value.ValueParameter{Value: "db"},
+				ScopeParameter: &value.ValueParameter{Value: "ag_group-default"},
+			},
+			Environment: "env name",
+			Group:       "default",
+		},
+	}, cmpopts.IgnoreInterfaces(struct{ template.Template }{}))
+
+	t.Run("missing artifact is reported", func(t *testing.T) {
+		_ = afero.WriteFile(testFs, "missing.yaml", []byte(`
+configs:
+- id: postgres
+  config:
+    name: com.dynatrace.extension.postgres
+    template: profile.json
+  type:
+    extension:
+      name: com.dynatrace.extension.postgres
+      artifact: missing.zip
+`), 0644)
+
+		_, errs := parseConfigs(testFs, loaderContext, "missing.yaml")
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], `failed to read artifact of extension "com.dynatrace.extension.postgres"`)
+	})
+
+	t.Run("monitoring configuration without scope is reported", func(t *testing.T) {
+		_ = afero.WriteFile(testFs, "noscope.yaml", []byte(`
+configs:
+- id: postgres-db
+  config:
+    name: db
+    template: profile.json
+  type:
+    extensionMonitoring:
+      extension: com.dynatrace.extension.postgres
+`), 0644)
+
+		_, errs := parseConfigs(testFs, loaderContext, "noscope.yaml")
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "type.extensionMonitoring.scope")
+	})
+}
+
+func Test_parseConfigs_ProjectVariables(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId: "project",
+		Path:      "some-dir/",
+		Environments: []manifest.EnvironmentDefinition{
+			{Name: "env name", Group: "default"},
+		},
+		KnownApis:       map[string]struct{}{"dashboard": {}},
+		ParametersSerDe: DefaultParameterParsers,
+		Variables:       map[string]string{"application": "payment", "owner": "team-a"},
+	}
+
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "board.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "board.yaml", []byte(`
+configs:
+- id: board
+  config:
+    name: board
+    template: board.json
+    parameters:
+      owner: team-b
+  type:
+    api: dashboard
+`), 0644)
+
+	gotConfigs, gotErrors := parseConfigs(testFs, loaderContext, "board.yaml")
+	assert.Assert(t, len(gotErrors) == 0, "expected no errors but got: %v", gotErrors)
+	assert.Equal(t, len(gotConfigs), 1)
+
+	assert.DeepEqual(t, gotConfigs[0].Parameters["application"], &value.ValueParameter{Value: "payment"})
+	assert.DeepEqual(t, gotConfigs[0].Parameters["owner"], &value.ValueParameter{Value: "team-b"})
+	assert.DeepEqual(t, gotConfigs[0].Variables, []string{"application"})
+}
+
+func Test_parseConfigs_Variants(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId: "project",
+		Path:      "some-dir/",
+		Environments: []manifest.EnvironmentDefinition{
+			{Name: "prod-eu", Group: "prod"},
+			{Name: "prod-us", Group: "prod"},
+			{Name: "dev", Group: "dev"},
+		},
+		KnownApis:       map[string]struct{}{"alerting-profile": {}},
+		ParametersSerDe: DefaultParameterParsers,
+	}
+
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "alert.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "alert.yaml", []byte(`
+configs:
+- id: cpu-alert
+  config:
+    name: cpu alert
+    template: alert.json
+    parameters:
+      threshold: 90
+      owner: team-a
+  type:
+    api: alerting-profile
+  environmentOverrides:
+  - environment: dev
+    override:
+      parameters:
+        owner: team-dev
+  variants:
+  - name: eu
+    parameters:
+      threshold: 80
+    environments: [prod-eu]
+  - name: us
+    groups: [prod]
+`), 0644)
+
+	gotConfigs, gotErrors := parseConfigs(testFs, loaderContext, "alert.yaml")
+	assert.Assert(t, len(gotErrors) == 0, "expected no errors but got: %v", gotErrors)
+	assert.Equal(t, len(gotConfigs), 6)
+
+	got := make(map[string]Config)
+	for _, c := range gotConfigs {
+		got[c.Coordinate.ConfigId+"@"+c.Environment] = c
+	}
+
+	assert.DeepEqual(t, got["cpu-alert.eu@prod-eu"].Parameters["threshold"], &value.ValueParameter{Value: 80})
+	assert.Assert(t, !got["cpu-alert.eu@prod-eu"].Skip)
+	assert.Assert(t, got["cpu-alert.eu@prod-us"].Skip, "variants are skipped in environments they are not selected for")
+	assert.Assert(t, got["cpu-alert.eu@dev"].Skip)
+
+	assert.DeepEqual(t, got["cpu-alert.us@prod-us"].Parameters["threshold"], &value.ValueParameter{Value: 90})
+	assert.Assert(t, !got["cpu-alert.us@prod-eu"].Skip)
+	assert.Assert(t, got["cpu-alert.us@dev"].Skip)
+	assert.DeepEqual(t, got["cpu-alert.us@dev"].Parameters["owner"], &value.ValueParameter{Value: "team-dev"})
+}
+
+func Test_parseConfigs_MovedVariants(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId:       "project",
+		Path:            "some-dir/",
+		Environments:    []manifest.EnvironmentDefinition{{Name: "env", Group: "default"}},
+		KnownApis:       map[string]struct{}{"alerting-profile": {}},
+		ParametersSerDe: DefaultParameterParsers,
+	}
+
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "alert.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "alert.yaml", []byte(`
+configs:
+- id: cpu-alert
+  config:
+    name: cpu alert
+    template: alert.json
+  type:
+    api: alerting-profile
+  movedFrom:
+    project: other
+    configType: alerting-profile
+    configId: alert
+  variants:
+  - name: eu
+  - name: us
+`), 0644)
+
+	gotConfigs, gotErrors := parseConfigs(testFs, loaderContext, "alert.yaml")
+	assert.Assert(t, len(gotErrors) == 0, "expected no errors but got: %v", gotErrors)
+	assert.Equal(t, len(gotConfigs), 2)
+
+	assert.DeepEqual(t, gotConfigs[0].OriginCoordinate(), coordinate.Coordinate{Project: "other", Type: "alerting-profile", ConfigId: "alert.eu"})
+	assert.DeepEqual(t, gotConfigs[1].OriginCoordinate(), coordinate.Coordinate{Project: "other", Type: "alerting-profile", ConfigId: "alert.us"})
+}
+
+func Test_parseConfigs_InvalidVariants(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId:       "project",
+		Path:            "some-dir/",
+		Environments:    []manifest.EnvironmentDefinition{{Name: "env", Group: "default"}},
+		KnownApis:       map[string]struct{}{"alerting-profile": {}},
+		ParametersSerDe: DefaultParameterParsers,
+	}
+
+	tests := []struct {
+		variants      string
+		wantErrorPart string
+	}{
+		{variants: "[{parameters: {a: b}}]", wantErrorPart: "variant 1 has no `name`"},
+		{variants: "[{name: eu}, {name: eu}]", wantErrorPart: `duplicate variant "eu"`},
+		{variants: "[{name: 'eu:west'}]", wantErrorPart: `name of variant "eu:west" must not contain colons, slashes or spaces`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantErrorPart, func(t *testing.T) {
+			testFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(testFs, "alert.json", []byte("{}"), 0644)
+			_ = afero.WriteFile(testFs, "alert.yaml", []byte(`
+configs:
+- id: cpu-alert
+  config:
+    name: cpu alert
+    template: alert.json
+  type:
+    api: alerting-profile
+  variants: `+tt.variants+`
+`), 0644)
+
+			_, gotErrors := parseConfigs(testFs, loaderContext, "alert.yaml")
+			assert.Equal(t, len(gotErrors), 1)
+			assert.ErrorContains(t, gotErrors[0], tt.wantErrorPart)
+		})
+	}
+}
+
+func Test_parseConfigs_ParameterTransformations(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId: "project",
+		Path:      "some-dir/",
+		Environments: []manifest.EnvironmentDefinition{
+			{Name: "env name", Group: "default"},
+		},
+		KnownApis:       map[string]struct{}{"dashboard": {}},
+		ParametersSerDe: DefaultParameterParsers,
+	}
+
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "board.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "board.yaml", []byte(`
+configs:
+- id: board
+  config:
+    name: board
+    template: board.json
+    parameters:
+      owner:
+        type: value
+        value: " Team A "
+        transform:
+          - trim
+          - regexReplace: { pattern: "\\s", replacement: "-" }
+          - toLower
+  type:
+    api: dashboard
+`), 0644)
+
+	gotConfigs, gotErrors := parseConfigs(testFs, loaderContext, "board.yaml")
+	assert.Assert(t, len(gotErrors) == 0, "expected no errors but got: %v", gotErrors)
+	assert.Equal(t, len(gotConfigs), 1)
+
+	owner, ok := gotConfigs[0].Parameters["owner"].(*transform.TransformedParameter)
+	assert.Assert(t, ok, "expected transformed parameter, but got %T", gotConfigs[0].Parameters["owner"])
+	assert.DeepEqual(t, owner.Parameter, &value.ValueParameter{Value: " Team A "})
+
+	resolved, err := owner.ResolveValue(parameter.ResolveContext{})
+	assert.NilError(t, err)
+	assert.Equal(t, resolved, "team-a")
+}
+
+func Test_parseConfigs_ErrorsContainPositions(t *testing.T) {
+	loaderContext := &LoaderContext{
+		ProjectId: "project",
+		Path:      "some-dir/",
+		Environments: []manifest.EnvironmentDefinition{
+			{Name: "env name", Group: "default"},
+		},
+		KnownApis:       map[string]struct{}{"dashboard": {}},
+		ParametersSerDe: DefaultParameterParsers,
+	}
+
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "board.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "board.yaml", []byte(`configs:
+- id: valid
+  config:
+    name: board
+    template: board.json
+  type:
+    api: dashboard
+- id: no-name
+  config:
+    template: board.json
+  type:
+    api: dashboard
+- id: invalid-reference
+  config:
+    name: board
+    template: board.json
+    parameters:
+      other: a
+      ref: []
+  type:
+    api: dashboard
+`), 0644)
+
+	_, gotErrors := parseConfigs(testFs, loaderContext, "board.yaml")
+	assert.Equal(t, len(gotErrors), 2)
+
+	assert.ErrorContains(t, gotErrors[0], "`board.yaml:8:3`: missing parameter `name`")
+	assert.ErrorContains(t, gotErrors[1], "ref: cannot parse parameter definition in `board.yaml:19:7`")
+}
+
+func Test_parseConfigs_UnknownKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		strict      bool
+		content     string
+		wantErrs    []string
+		wantConfigs int
+	}{
+		{
+			name: "unknown top-level key fails with suggestion",
+			content: `configs:
+- id: board
+  config:
+    name: board
+    template: board.json
+  type:
+    api: dashboard
+  enviromentOverrides: []
+`,
+			wantErrs: []string{`unknown key "configs[0].enviromentOverrides" (line 8, column 3) - did you mean "environmentOverrides"?`},
+		},
+		{
+			name: "unknown key in type is ignored if not strict",
+			content: `configs:
+- id: board
+  config:
+    name: board
+    template: board.json
+  type:
+    api: dashboard
+    setings: {}
+`,
+			wantConfigs: 1,
+		},
+		{
+			name:   "unknown key in type fails if strict",
+			strict: true,
+			content: `configs:
+- id: board
+  config:
+    name: board
+    template: board.json
+  type:
+    api: dashboard
+    setings: {}
+`,
+			wantErrs: []string{`unknown key "configs[0].type.setings" (line 8, column 5) - did you mean "settings"?`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaderContext := &LoaderContext{
+				ProjectId:       "project",
+				Path:            "some-dir/",
+				Environments:    []manifest.EnvironmentDefinition{{Name: "env name", Group: "default"}},
+				KnownApis:       map[string]struct{}{"dashboard": {}},
+				ParametersSerDe: DefaultParameterParsers,
+				Strict:          tt.strict,
+			}
+
+			testFs := afero.NewMemMapFs()
+			_ = afero.WriteFile(testFs, "board.json", []byte("{}"), 0644)
+			_ = afero.WriteFile(testFs, "board.yaml", []byte(tt.content), 0644)
+
+			gotConfigs, gotErrors := parseConfigs(testFs, loaderContext, "board.yaml")
+			assert.Equal(t, len(gotErrors), len(tt.wantErrs), "errors: %v", gotErrors)
+			for i := range tt.wantErrs {
+				assert.ErrorContains(t, gotErrors[i], tt.wantErrs[i])
+			}
+			assert.Equal(t, len(gotConfigs), tt.wantConfigs)
+		})
+	}
+}
@@ -19,13 +19,13 @@ import (
 )
 
 type groupOverride struct {
-	Group    string           `yaml:"group"`
-	Override configDefinition `yaml:"override"`
+	Group    string           `yaml:"group" jsonschema:"required"`
+	Override configDefinition `yaml:"override" jsonschema:"required"`
 }
 
 type environmentOverride struct {
-	Environment string           `yaml:"environment"`
-	Override    configDefinition `yaml:"override"`
+	Environment string           `yaml:"environment" jsonschema:"required"`
+	Override    configDefinition `yaml:"override" jsonschema:"required"`
 }
 
 type configDefinition struct {
@@ -37,15 +37,42 @@ type configDefinition struct {
 }
 
 type topLevelConfigDefinition struct {
-	Id                   string                `yaml:"id"`
-	Config               configDefinition      `yaml:"config"`
-	Type                 typeDefinition        `yaml:"type"`
+	Id                   string                `yaml:"id" jsonschema:"required"`
+	Config               configDefinition      `yaml:"config" jsonschema:"required"`
+	Type                 typeDefinition        `yaml:"type" jsonschema:"required"`
 	GroupOverrides       []groupOverride       `yaml:"groupOverrides,omitempty"`
 	EnvironmentOverrides []environmentOverride `yaml:"environmentOverrides,omitempty"`
+	IgnoreOnDownload     bool                  `yaml:"ignoreOnDownload,omitempty"`
+	IgnoreOnPurge        bool                  `yaml:"ignoreOnPurge,omitempty"`
+	ExcludeFromDiff      bool                  `yaml:"excludeFromDiff,omitempty"`
+	DependsOn            []interface{}         `yaml:"dependsOn,omitempty"`
+	Priority             int                   `yaml:"priority,omitempty"`
+	Position             int                   `yaml:"position,omitempty"`
+	MovedFrom            *coordinateDefinition `yaml:"movedFrom,omitempty"`
+	MissingKey           string                `yaml:"missingKey,omitempty" jsonschema:"enum=error|zero"`
+	Variants             []variantDefinition   `yaml:"variants,omitempty"`
+}
+
+// variantDefinition defines a variant of a config, deployed side-by-side with the other variants under the coordinate
+// of the config suffixed with the name of the variant.
+type variantDefinition struct {
+	Name string `yaml:"name" jsonschema:"required"`
+	// Parameters override the parameters of the config for this variant
+	Parameters map[string]configParameter `yaml:"parameters,omitempty"`
+	// Environments and Groups restrict the environments the variant is deployed to. In all others, it is skipped.
+	Environments []string `yaml:"environments,omitempty"`
+	Groups       []string `yaml:"groups,omitempty"`
+}
+
+// coordinateDefinition fully defines the coordinate of a config
+type coordinateDefinition struct {
+	Project  string `yaml:"project" jsonschema:"required"`
+	Type     string `yaml:"configType" jsonschema:"required"`
+	ConfigId string `yaml:"configId" jsonschema:"required"`
 }
 
 type topLevelDefinition struct {
-	Configs []topLevelConfigDefinition `yaml:"configs"`
+	Configs []topLevelConfigDefinition `yaml:"configs" jsonschema:"required"`
 }
 
 type configParameter interface{}
@@ -17,8 +17,10 @@ package v2
 import (
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/transform"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	"github.com/spf13/afero"
@@ -177,6 +179,12 @@ func writeTopLevelDefinitionToDisk(context *WriterContext, apiCoord apiCoordinat
 		return err
 	}
 
+	definitionYaml, err = anchorRepeatedParameters(definitionYaml)
+
+	if err != nil {
+		return err
+	}
+
 	sanitizedApi := sanitize(apiCoord.api)
 	targetConfigFile := filepath.Join(context.OutputFolder, context.ProjectFolder, sanitizedApi, "config.yaml")
 
@@ -257,9 +265,46 @@ func toTopLevelConfigDefinition(context *serializerContext, configs []Config) (t
 		Type:                 ct,
 		GroupOverrides:       groupOverrideConfigs,
 		EnvironmentOverrides: environmentOverrideConfigs,
+		IgnoreOnDownload:     configs[0].IgnoreOnDownload,
+		IgnoreOnPurge:        configs[0].IgnoreOnPurge,
+		ExcludeFromDiff:      configs[0].ExcludeFromDiff,
+		DependsOn:            toDependsOnDefinition(context.config, configs[0].DependsOn),
+		Priority:             configs[0].Priority,
+		Position:             configs[0].Position,
+		MovedFrom:            toCoordinateDefinition(configs[0].MovedFrom),
+		MissingKey:           string(configs[0].MissingKey),
 	}, templates, nil
 }
 
+// toCoordinateDefinition returns the definition of the given coordinate, or nil if it is the zero value.
+func toCoordinateDefinition(c coordinate.Coordinate) *coordinateDefinition {
+	if c == (coordinate.Coordinate{}) {
+		return nil
+	}
+	return &coordinateDefinition{Project: c.Project, Type: c.Type, ConfigId: c.ConfigId}
+}
+
+// toDependsOnDefinition writes the given dependencies as short lists, leaving out project and type if they are the
+// same as the ones of the depending config.
+func toDependsOnDefinition(config coordinate.Coordinate, dependsOn []coordinate.Coordinate) []interface{} {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+
+	result := make([]interface{}, len(dependsOn))
+	for i, d := range dependsOn {
+		switch {
+		case d.Project != config.Project:
+			result[i] = []interface{}{d.Project, d.Type, d.ConfigId}
+		case d.Type != config.Type:
+			result[i] = []interface{}{d.Type, d.ConfigId}
+		default:
+			result[i] = []interface{}{d.ConfigId}
+		}
+	}
+	return result
+}
+
 func extractConfigType(context *serializerContext, config Config) (typeDefinition, error) {
 
 	switch t := config.Type.(type) {
@@ -278,8 +323,20 @@ func extractConfigType(context *serializerContext, config Config) (typeDefinitio
 		}, nil
 
 	case ClassicApiType:
+		if _, found := config.Parameters[ScopeParameter]; !found {
+			return typeDefinition{
+				Api: config.Coordinate.Type,
+			}, nil
+		}
+
+		serializedScope, err := getScope(context, config)
+		if err != nil {
+			return typeDefinition{}, err
+		}
+
 		return typeDefinition{
-			Api: config.Coordinate.Type,
+			Api:   config.Coordinate.Type,
+			Scope: serializedScope,
 		}, nil
 
 	case EntityType:
@@ -289,6 +346,27 @@ func extractConfigType(context *serializerContext, config Config) (typeDefinitio
 			},
 		}, nil
 
+	case ExtensionType:
+		return typeDefinition{
+			Extension: extensionDefinition{
+				Name:     t.Name,
+				Artifact: t.ArtifactPath,
+			},
+		}, nil
+
+	case ExtensionMonitoringType:
+		serializedScope, err := getScope(context, config)
+		if err != nil {
+			return typeDefinition{}, err
+		}
+
+		return typeDefinition{
+			ExtensionMonitoring: extensionMonitoringDefinition{
+				Extension: t.Extension,
+				Scope:     serializedScope,
+			},
+		}, nil
+
 	default:
 		return typeDefinition{}, fmt.Errorf("unknown config-type (ID: %q)", config.Type.ID())
 	}
@@ -552,7 +630,7 @@ func toConfigDefinition(context *serializerContext, config Config) (configDefini
 		errs = append(errs, err)
 	}
 
-	params, convertErrs := convertParameters(&detailedContext, config.Parameters)
+	params, convertErrs := convertParameters(&detailedContext, withoutVariables(config))
 
 	errs = append(errs, convertErrs...)
 
@@ -613,6 +691,21 @@ func extractTemplate(context *detailedSerializerContext, config Config) (string,
 	return "", configTemplate{}, errors.New("unknown template type")
 }
 
+// withoutVariables returns the parameters of the given config, leaving out the ones added from project variables.
+func withoutVariables(config Config) Parameters {
+	if len(config.Variables) == 0 {
+		return config.Parameters
+	}
+
+	result := make(Parameters, len(config.Parameters))
+	for name, p := range config.Parameters {
+		if !slices.Contains(config.Variables, name) {
+			result[name] = p
+		}
+	}
+	return result
+}
+
 func convertParameters(context *detailedSerializerContext, parameters Parameters) (map[string]configParameter, []error) {
 	var errs []error
 	result := make(map[string]configParameter)
@@ -654,6 +747,10 @@ func parseNameParameter(context *detailedSerializerContext, config Config) (conf
 func toParameterDefinition(context *detailedSerializerContext, parameterName string,
 	param parameter.Parameter) (configParameter, error) {
 
+	if transformed, ok := param.(*transform.TransformedParameter); ok {
+		return toTransformedParameterDefinition(context, parameterName, transformed)
+	}
+
 	if isValueParameter(param) {
 		return toValueShorthandDefinition(context, parameterName, param)
 	}
@@ -676,6 +773,28 @@ func toParameterDefinition(context *detailedSerializerContext, parameterName str
 	return result, nil
 }
 
+// toTransformedParameterDefinition writes the wrapped parameter in its full form, adding its transformations.
+func toTransformedParameterDefinition(context *detailedSerializerContext, parameterName string,
+	param *transform.TransformedParameter) (configParameter, error) {
+
+	definition, err := toParameterDefinition(context, parameterName, param.Parameter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := definition.(map[string]interface{})
+	if !ok {
+		// value shorthands can not hold transformations
+		result = map[string]interface{}{
+			"type":  value.ValueParameterType,
+			"value": definition,
+		}
+	}
+	result[transform.Field] = transform.Serialize(param.Transformations)
+
+	return result, nil
+}
+
 func isValueParameter(param parameter.Parameter) bool {
 	return param.GetType() == value.ValueParameterType
 }
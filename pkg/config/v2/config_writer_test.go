@@ -30,6 +30,7 @@ import (
 	"testing"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/transform"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"gotest.tools/assert"
 )
@@ -553,6 +554,23 @@ func TestToParameterDefinitionShouldWithShortSyntaxActiveShouldDoNormalWhenParam
 		value.ValueParameterType, resultMap["type"])
 }
 
+func TestToParameterDefinitionWritesTransformations(t *testing.T) {
+	context := detailedSerializerContext{
+		serializerContext: &serializerContext{
+			WriterContext: &WriterContext{},
+		},
+	}
+
+	result, err := toParameterDefinition(&context, "test-param-1", transform.New(&value.ValueParameter{Value: " hello "}, []transform.Transformation{{Name: transform.Trim}}))
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, result, map[string]interface{}{
+		"type":      value.ValueParameterType,
+		"value":     " hello ",
+		"transform": []interface{}{"trim"},
+	})
+}
+
 func TestForSamePropertiesWithNothingSet(t *testing.T) {
 	configs := []extendedConfigDefinition{
 		{
@@ -768,6 +786,50 @@ func TestWriteConfigs(t *testing.T) {
 				"project/alerting-profile/config.yaml",
 			},
 		},
+		{
+			name: "Parent-scoped classic API write",
+			configs: []Config{
+				{
+					Template: template.CreateTemplateFromString("project/key-user-actions-web/a.json", ""),
+					Coordinate: coordinate.Coordinate{
+						Project:  "project",
+						Type:     "key-user-actions-web",
+						ConfigId: "configId",
+					},
+					Type: ClassicApiType{
+						Api: "key-user-actions-web",
+					},
+					Parameters: map[string]parameter.Parameter{
+						NameParameter:  &value.ValueParameter{Value: "name"},
+						ScopeParameter: value.New("APPLICATION-1234"),
+					},
+					SkipForConversion: value.New("false"),
+				},
+			},
+			expectedConfigs: map[string]topLevelDefinition{
+				"key-user-actions-web": {
+					Configs: []topLevelConfigDefinition{
+						{
+							Id: "configId",
+							Config: configDefinition{
+								Name:       "name",
+								Parameters: nil,
+								Template:   "a.json",
+								Skip:       "false",
+							},
+							Type: typeDefinition{
+								Api:   "key-user-actions-web",
+								Scope: "APPLICATION-1234",
+							},
+						},
+					},
+				},
+			},
+			expectedTemplatePaths: []string{
+				"project/key-user-actions-web/a.json",
+				"project/key-user-actions-web/config.yaml",
+			},
+		},
 		{
 			name: "Settings 2.0 schema write sanitizes names",
 			configs: []Config{
@@ -954,3 +1016,36 @@ func TestWriteConfigs(t *testing.T) {
 	}
 
 }
+
+func TestToDependsOnDefinition(t *testing.T) {
+	c := coordinate.Coordinate{Project: "project", Type: "extension", ConfigId: "config"}
+
+	got := toDependsOnDefinition(c, []coordinate.Coordinate{
+		{Project: "project", Type: "extension", ConfigId: "same-type"},
+		{Project: "project", Type: "builtin:schema", ConfigId: "other-type"},
+		{Project: "other-project", Type: "extension", ConfigId: "other-project"},
+	})
+
+	assert.DeepEqual(t, got, []interface{}{
+		[]interface{}{"same-type"},
+		[]interface{}{"builtin:schema", "other-type"},
+		[]interface{}{"other-project", "extension", "other-project"},
+	})
+	assert.Assert(t, toDependsOnDefinition(c, nil) == nil)
+}
+
+func TestWithoutVariables(t *testing.T) {
+	c := Config{
+		Parameters: Parameters{
+			NameParameter: &value.ValueParameter{Value: "name"},
+			"application": &value.ValueParameter{Value: "payment"},
+			"owner":       &value.ValueParameter{Value: "team-a"},
+		},
+		Variables: []string{"application"},
+	}
+
+	assert.DeepEqual(t, withoutVariables(c), Parameters{
+		NameParameter: &value.ValueParameter{Value: "name"},
+		"owner":       &value.ValueParameter{Value: "team-a"},
+	})
+}
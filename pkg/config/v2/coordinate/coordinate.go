@@ -14,7 +14,10 @@
 
 package coordinate
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Coordinate struct used to specify the location of a certain configuration
 type Coordinate struct {
@@ -32,6 +35,26 @@ func (c Coordinate) String() string {
 	return fmt.Sprintf("%s:%s:%s", c.Project, c.Type, c.ConfigId)
 }
 
+// Parse parses a coordinate in the format returned by [Coordinate.String], 'project:type:configId'. As types like
+// Settings 2.0 schema IDs may contain colons themselves, the project ends at the first and the config ID starts after
+// the last colon.
+func Parse(s string) (Coordinate, error) {
+	first, last := strings.Index(s, ":"), strings.LastIndex(s, ":")
+	if first < 0 || first == last {
+		return Coordinate{}, fmt.Errorf("invalid coordinate %q, expected format 'project:type:configId'", s)
+	}
+
+	c := Coordinate{
+		Project:  s[:first],
+		Type:     s[first+1 : last],
+		ConfigId: s[last+1:],
+	}
+	if c.Project == "" || c.Type == "" || c.ConfigId == "" {
+		return Coordinate{}, fmt.Errorf("invalid coordinate %q, project, type and configId must not be empty", s)
+	}
+	return c, nil
+}
+
 // Match tests if this coordinate is the same as the given one
 func (c Coordinate) Match(coordinate Coordinate) bool {
 	return c.Project == coordinate.Project &&
@@ -68,3 +68,32 @@ func TestMatchShouldReturnFalseOnNonMatchingSameApi(t *testing.T) {
 
 	assert.Assert(t, !result, "shouldn't match")
 }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		given   string
+		want    Coordinate
+		wantErr bool
+	}{
+		{given: "project:dashboard:id", want: Coordinate{Project: "project", Type: "dashboard", ConfigId: "id"}},
+		{given: "project:builtin:alerting.profile:id", want: Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "id"}},
+		{given: "project:id", wantErr: true},
+		{given: "project", wantErr: true},
+		{given: "project::id", wantErr: true},
+		{given: ":dashboard:id", wantErr: true},
+		{given: "project:dashboard:", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			got, err := Parse(tt.given)
+			if tt.wantErr {
+				assert.Assert(t, err != nil, "expected error for %q", tt.given)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+			assert.Equal(t, got.String(), tt.given)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package location
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// SyntheticLocationParameterType specifies the type of the parameter used in config files
+const SyntheticLocationParameterType = "syntheticLocation"
+
+var SyntheticLocationParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeSyntheticLocationParameter,
+	Deserializer: parseSyntheticLocationParameter,
+}
+
+// SyntheticLocationParameter resolves to the ID of a synthetic location, which is looked up by its name in the
+// environment a config is deployed to. As the IDs of private synthetic locations differ between environments,
+// this allows synthetic monitors to refer to their locations in a portable way.
+type SyntheticLocationParameter struct {
+	// Name of the synthetic location
+	Name string
+}
+
+func New(name string) *SyntheticLocationParameter {
+	return &SyntheticLocationParameter{Name: name}
+}
+
+// this forces the compiler to check if SyntheticLocationParameter is of type Parameter
+var _ parameter.Parameter = (*SyntheticLocationParameter)(nil)
+
+func (p *SyntheticLocationParameter) GetType() string {
+	return SyntheticLocationParameterType
+}
+
+func (p *SyntheticLocationParameter) GetReferences() []parameter.ParameterReference {
+	// synthetic location parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *SyntheticLocationParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.Lookup == nil {
+		return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("synthetic location %q can only be looked up during deployment", p.Name))
+	}
+
+	id, err := context.Lookup.SyntheticLocationId(p.Name)
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, err.Error())
+	}
+	return id, nil
+}
+
+// parseSyntheticLocationParameter parses a SyntheticLocationParameter from a given context.
+// it requires a non-empty `name` field to be set.
+func parseSyntheticLocationParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	name, ok := context.Value["name"]
+	if !ok {
+		return nil, parameter.NewParameterParserError(context, "missing property `name`")
+	}
+
+	n := strings.ToString(name)
+	if n == "" {
+		return nil, parameter.NewParameterParserError(context, "property `name` must not be empty")
+	}
+	return New(n), nil
+}
+
+func writeSyntheticLocationParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	locationParam, ok := context.Parameter.(*SyntheticLocationParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `SyntheticLocationParameter`")
+	}
+
+	return map[string]interface{}{
+		"name": locationParam.Name,
+	}, nil
+}
@@ -0,0 +1,96 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package location
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+)
+
+type lookupFunc func(name string) (string, error)
+
+func (f lookupFunc) SyntheticLocationId(name string) (string, error) {
+	return f(name)
+}
+
+func (f lookupFunc) EntityId(string) (string, error) {
+	panic("unexpected entity lookup")
+}
+
+func (f lookupFunc) ConfigId(string, string) (string, error) {
+	panic("unexpected config lookup")
+}
+
+func TestParseSyntheticLocationParameter(t *testing.T) {
+	param, err := parseSyntheticLocationParameter(parameter.ParameterParserContext{
+		Value: map[string]interface{}{"name": "my location"},
+	})
+
+	assert.NilError(t, err)
+	assert.Equal(t, param.GetType(), SyntheticLocationParameterType)
+	assert.Equal(t, param.(*SyntheticLocationParameter).Name, "my location")
+	assert.Equal(t, len(param.GetReferences()), 0)
+}
+
+func TestParseSyntheticLocationParameter_RequiresName(t *testing.T) {
+	for _, value := range []map[string]interface{}{{}, {"name": ""}} {
+		_, err := parseSyntheticLocationParameter(parameter.ParameterParserContext{Value: value})
+		assert.Assert(t, err != nil, "expected error for %v", value)
+	}
+}
+
+func TestWriteSyntheticLocationParameter(t *testing.T) {
+	result, err := writeSyntheticLocationParameter(parameter.ParameterWriterContext{Parameter: New("my location")})
+
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, map[string]interface{}{"name": "my location"})
+}
+
+func TestResolveValue(t *testing.T) {
+	t.Run("location is looked up by name", func(t *testing.T) {
+		result, err := New("my location").ResolveValue(parameter.ResolveContext{
+			Lookup: lookupFunc(func(name string) (string, error) {
+				assert.Equal(t, name, "my location")
+				return "SYNTHETIC_LOCATION-0000000000000001", nil
+			}),
+		})
+
+		assert.NilError(t, err)
+		assert.Equal(t, result, "SYNTHETIC_LOCATION-0000000000000001")
+	})
+
+	t.Run("failed lookups are reported", func(t *testing.T) {
+		_, err := New("my location").ResolveValue(parameter.ResolveContext{
+			ParameterName: "location",
+			Lookup: lookupFunc(func(string) (string, error) {
+				return "", fmt.Errorf("no synthetic location named \"my location\" exists")
+			}),
+		})
+
+		assert.ErrorContains(t, err, "location: cannot parse parameter: no synthetic location named")
+	})
+
+	t.Run("resolving without lookup fails", func(t *testing.T) {
+		_, err := New("my location").ResolveValue(parameter.ResolveContext{})
+
+		assert.ErrorContains(t, err, "can only be looked up during deployment")
+	})
+}
@@ -0,0 +1,131 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lookup
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// LookupParameterType specifies the type of the parameter used in config files
+const LookupParameterType = "lookup"
+
+var LookupParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeLookupParameter,
+	Deserializer: parseLookupParameter,
+}
+
+// LookupParameter resolves to the ID of an object existing in the environment a config is deployed to. The object
+// is either an entity matching an entity selector, or a config of an API identified by its name. The lookup fails,
+// if not exactly one object matches.
+//
+// This allows configs to refer to environment-specific IDs without hard-coding them.
+type LookupParameter struct {
+	// EntitySelector selects the entity to look up, e.g. type("HOST"),tag("production")
+	EntitySelector string
+
+	// Api is the ID of the API of the config to look up by its Name
+	Api string
+	// Name of the config to look up
+	Name string
+}
+
+// NewEntityLookup returns a parameter looking up the entity matching the given entity selector.
+func NewEntityLookup(entitySelector string) *LookupParameter {
+	return &LookupParameter{EntitySelector: entitySelector}
+}
+
+// NewConfigLookup returns a parameter looking up the config of the given API with the given name.
+func NewConfigLookup(api, name string) *LookupParameter {
+	return &LookupParameter{Api: api, Name: name}
+}
+
+// this forces the compiler to check if LookupParameter is of type Parameter
+var _ parameter.Parameter = (*LookupParameter)(nil)
+
+func (p *LookupParameter) GetType() string {
+	return LookupParameterType
+}
+
+func (p *LookupParameter) GetReferences() []parameter.ParameterReference {
+	// lookup parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+func (p *LookupParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	if context.Lookup == nil {
+		return nil, parameter.NewParameterResolveValueError(context, "lookups can only be resolved during deployment")
+	}
+
+	var id string
+	var err error
+	if p.EntitySelector != "" {
+		id, err = context.Lookup.EntityId(p.EntitySelector)
+	} else {
+		id, err = context.Lookup.ConfigId(p.Api, p.Name)
+	}
+
+	if err != nil {
+		return nil, parameter.NewParameterResolveValueError(context, err.Error())
+	}
+	return id, nil
+}
+
+// parseLookupParameter parses a LookupParameter from a given context.
+// it requires either an `entitySelector`, or both `api` and `name` fields to be set.
+func parseLookupParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	entitySelector := stringField(context, "entitySelector")
+	api := stringField(context, "api")
+	name := stringField(context, "name")
+
+	switch {
+	case entitySelector != "" && (api != "" || name != ""):
+		return nil, parameter.NewParameterParserError(context, "either `entitySelector` or `api` and `name` may be set, not both")
+	case entitySelector != "":
+		return NewEntityLookup(entitySelector), nil
+	case api != "" && name != "":
+		return NewConfigLookup(api, name), nil
+	case api != "" || name != "":
+		return nil, parameter.NewParameterParserError(context, "config lookups require both `api` and `name`")
+	default:
+		return nil, parameter.NewParameterParserError(context, "missing property `entitySelector`, or `api` and `name`")
+	}
+}
+
+func stringField(context parameter.ParameterParserContext, field string) string {
+	if v, ok := context.Value[field]; ok {
+		return strings.ToString(v)
+	}
+	return ""
+}
+
+func writeLookupParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	lookupParam, ok := context.Parameter.(*LookupParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `LookupParameter`")
+	}
+
+	if lookupParam.EntitySelector != "" {
+		return map[string]interface{}{
+			"entitySelector": lookupParam.EntitySelector,
+		}, nil
+	}
+	return map[string]interface{}{
+		"api":  lookupParam.Api,
+		"name": lookupParam.Name,
+	}, nil
+}
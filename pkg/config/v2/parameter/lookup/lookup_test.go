@@ -0,0 +1,130 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lookup
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+)
+
+// fakeLookup resolves entity selectors and "<api>/<name>" via the maps, failing for unknown keys
+type fakeLookup struct {
+	entities map[string]string
+	configs  map[string]string
+}
+
+func (f fakeLookup) SyntheticLocationId(string) (string, error) {
+	panic("unexpected synthetic location lookup")
+}
+
+func (f fakeLookup) EntityId(entitySelector string) (string, error) {
+	if id, ok := f.entities[entitySelector]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("no entity matching %q exists", entitySelector)
+}
+
+func (f fakeLookup) ConfigId(api, name string) (string, error) {
+	if id, ok := f.configs[api+"/"+name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("no %q config named %q exists", api, name)
+}
+
+func TestParseLookupParameter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   map[string]interface{}
+		want    *LookupParameter
+		wantErr string
+	}{
+		{
+			name:  "entity selector",
+			value: map[string]interface{}{"entitySelector": `type("HOST")`},
+			want:  NewEntityLookup(`type("HOST")`),
+		},
+		{
+			name:  "config",
+			value: map[string]interface{}{"api": "alerting-profile", "name": "profile"},
+			want:  NewConfigLookup("alerting-profile", "profile"),
+		},
+		{
+			name:    "both",
+			value:   map[string]interface{}{"entitySelector": `type("HOST")`, "api": "alerting-profile", "name": "profile"},
+			wantErr: "not both",
+		},
+		{
+			name:    "config without name",
+			value:   map[string]interface{}{"api": "alerting-profile"},
+			wantErr: "config lookups require both `api` and `name`",
+		},
+		{
+			name:    "nothing",
+			value:   map[string]interface{}{},
+			wantErr: "missing property",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLookupParameter(parameter.ParameterParserContext{Value: tt.value})
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestWriteLookupParameter(t *testing.T) {
+	for _, p := range []*LookupParameter{NewEntityLookup(`type("HOST")`), NewConfigLookup("alerting-profile", "profile")} {
+		written, err := writeLookupParameter(parameter.ParameterWriterContext{Parameter: p})
+		assert.NilError(t, err)
+
+		parsed, err := parseLookupParameter(parameter.ParameterParserContext{Value: written})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, parsed, p)
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	lookup := fakeLookup{
+		entities: map[string]string{`type("HOST")`: "HOST-1234567890ABCDEF"},
+		configs:  map[string]string{"alerting-profile/profile": "profile-id"},
+	}
+
+	got, err := NewEntityLookup(`type("HOST")`).ResolveValue(parameter.ResolveContext{Lookup: lookup})
+	assert.NilError(t, err)
+	assert.Equal(t, got, "HOST-1234567890ABCDEF")
+
+	got, err = NewConfigLookup("alerting-profile", "profile").ResolveValue(parameter.ResolveContext{Lookup: lookup})
+	assert.NilError(t, err)
+	assert.Equal(t, got, "profile-id")
+
+	_, err = NewConfigLookup("alerting-profile", "unknown").ResolveValue(parameter.ResolveContext{Lookup: lookup, ParameterName: "profileId"})
+	assert.ErrorContains(t, err, `profileId: cannot parse parameter: no "alerting-profile" config named "unknown" exists`)
+
+	_, err = NewEntityLookup(`type("HOST")`).ResolveValue(parameter.ResolveContext{})
+	assert.ErrorContains(t, err, "lookups can only be resolved during deployment")
+}
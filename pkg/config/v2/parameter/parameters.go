@@ -16,6 +16,7 @@ package parameter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
@@ -66,6 +67,27 @@ type ResolveContext struct {
 
 	// resolved values of the current config
 	ResolvedParameterValues Properties
+
+	// Lookup gives access to the objects existing in the environment the config is deployed to.
+	// It is nil if parameters are not resolved during deployment, e.g. while loading a project.
+	Lookup Lookup
+
+	// DeploymentTime is the time of the deployment the config is resolved for, equal for all configs deployed together.
+	// It is zero if parameters are not resolved during deployment.
+	DeploymentTime time.Time
+}
+
+// Lookup resolves objects existing in the environment a config is deployed to, so that parameters can refer to them
+// by properties stable across environments rather than their environment-specific IDs.
+type Lookup interface {
+	// SyntheticLocationId returns the ID of the synthetic location (public or private) with the given name.
+	SyntheticLocationId(name string) (string, error)
+
+	// EntityId returns the ID of the single entity matching the given entity selector.
+	EntityId(entitySelector string) (string, error)
+
+	// ConfigId returns the ID of the single config of the given API with the given name.
+	ConfigId(api, name string) (string, error)
 }
 
 type Parameter interface {
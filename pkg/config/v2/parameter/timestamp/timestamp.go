@@ -0,0 +1,159 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package timestamp
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"strconv"
+	gostrings "strings"
+	"time"
+	// embeds the time zone database, as it is not available in all environments monaco runs in, e.g. containers
+	_ "time/tzdata"
+)
+
+// TimestampParameterType specifies the type of the parameter used in config files
+const TimestampParameterType = "timestamp"
+
+// Formats of timestamps besides custom Go time layouts
+const (
+	RFC3339      = "rfc3339"
+	EpochMillis  = "epochMillis"
+	EpochSeconds = "epochSeconds"
+)
+
+var TimestampParameterSerde = parameter.ParameterSerDe{
+	Serializer:   writeTimestampParameter,
+	Deserializer: parseTimestampParameter,
+}
+
+// TimestampParameter resolves to the time of the deployment, shifted by an optional offset, e.g. to define
+// maintenance windows or timeframes relative to the deployment.
+type TimestampParameter struct {
+	// Offset is added to the deployment time. It is a Go duration like -1h30m, or a number of days like 7d.
+	Offset string
+	// Format is either RFC3339, EpochMillis, EpochSeconds or a Go time layout like 2006-01-02 15:04.
+	// It defaults to RFC3339.
+	Format string
+	// Timezone is the IANA name of the time zone the timestamp is formatted in, e.g. Europe/Vienna. It defaults to UTC.
+	Timezone string
+
+	offset   time.Duration
+	location *time.Location
+}
+
+// New returns a timestamp parameter, validating the given offset and time zone.
+func New(offset, format, timezone string) (*TimestampParameter, error) {
+	d, err := parseOffset(offset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `offset` %q: %w", offset, err)
+	}
+
+	location := time.UTC
+	if timezone != "" {
+		if location, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid `timezone` %q: %w", timezone, err)
+		}
+	}
+
+	return &TimestampParameter{Offset: offset, Format: format, Timezone: timezone, offset: d, location: location}, nil
+}
+
+// parseOffset parses Go durations, extended by days (e.g. 7d).
+func parseOffset(offset string) (time.Duration, error) {
+	if offset == "" {
+		return 0, nil
+	}
+	if days, found := gostrings.CutSuffix(offset, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("days must be a whole number")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(offset)
+}
+
+// this forces the compiler to check if TimestampParameter is of type Parameter
+var _ parameter.Parameter = (*TimestampParameter)(nil)
+
+func (p *TimestampParameter) GetType() string {
+	return TimestampParameterType
+}
+
+func (p *TimestampParameter) GetReferences() []parameter.ParameterReference {
+	// timestamp parameters cannot have references
+	return []parameter.ParameterReference{}
+}
+
+// ResolveValue returns the time of the deployment, so that all timestamps of a deployment are equal and rendering a
+// config twice yields the same result. Outside of deployments, the current time is used.
+func (p *TimestampParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	now := context.DeploymentTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+	t := now.Add(p.offset).In(p.location)
+
+	switch p.Format {
+	case "", RFC3339:
+		return t.Format(time.RFC3339), nil
+	case EpochMillis:
+		return t.UnixMilli(), nil
+	case EpochSeconds:
+		return t.Unix(), nil
+	default:
+		return t.Format(p.Format), nil
+	}
+}
+
+// parseTimestampParameter parses a TimestampParameter from a given context.
+// `offset`, `format` and `timezone` are optional fields.
+func parseTimestampParameter(context parameter.ParameterParserContext) (parameter.Parameter, error) {
+	p, err := New(stringField(context, "offset"), stringField(context, "format"), stringField(context, "timezone"))
+	if err != nil {
+		return nil, parameter.NewParameterParserError(context, err.Error())
+	}
+	return p, nil
+}
+
+func stringField(context parameter.ParameterParserContext, field string) string {
+	if v, ok := context.Value[field]; ok {
+		return strings.ToString(v)
+	}
+	return ""
+}
+
+func writeTimestampParameter(context parameter.ParameterWriterContext) (map[string]interface{}, error) {
+	timestampParam, ok := context.Parameter.(*TimestampParameter)
+	if !ok {
+		return nil, parameter.NewParameterWriterError(context, "unexpected type. parameter is not of type `TimestampParameter`")
+	}
+
+	result := make(map[string]interface{})
+	if timestampParam.Offset != "" {
+		result["offset"] = timestampParam.Offset
+	}
+	if timestampParam.Format != "" {
+		result["format"] = timestampParam.Format
+	}
+	if timestampParam.Timezone != "" {
+		result["timezone"] = timestampParam.Timezone
+	}
+	return result, nil
+}
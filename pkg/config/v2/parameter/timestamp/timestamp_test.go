@@ -0,0 +1,110 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package timestamp
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+	"time"
+)
+
+func TestParseTimestampParameter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:  "defaults",
+			value: map[string]interface{}{},
+		},
+		{
+			name:  "all fields",
+			value: map[string]interface{}{"offset": "-1h30m", "format": EpochMillis, "timezone": "Europe/Vienna"},
+		},
+		{
+			name:  "days",
+			value: map[string]interface{}{"offset": "7d"},
+		},
+		{
+			name:    "invalid offset",
+			value:   map[string]interface{}{"offset": "tomorrow"},
+			wantErr: "invalid `offset`",
+		},
+		{
+			name:    "fractional days",
+			value:   map[string]interface{}{"offset": "1.5d"},
+			wantErr: "days must be a whole number",
+		},
+		{
+			name:    "unknown timezone",
+			value:   map[string]interface{}{"timezone": "Middle/Earth"},
+			wantErr: "invalid `timezone`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			context := parameter.ParameterParserContext{Value: tt.value}
+
+			p, err := parseTimestampParameter(context)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+
+			written, err := writeTimestampParameter(parameter.ParameterWriterContext{Parameter: p})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, written, tt.value)
+		})
+	}
+}
+
+func TestTimestampParameter_ResolveValue(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		offset   string
+		format   string
+		timezone string
+		want     interface{}
+	}{
+		{"now", "", "", "", "2023-05-01T12:00:00Z"},
+		{"offset", "-1h30m", RFC3339, "", "2023-05-01T10:30:00Z"},
+		{"days", "7d", RFC3339, "", "2023-05-08T12:00:00Z"},
+		{"epoch millis", "", EpochMillis, "", int64(1682942400000)},
+		{"epoch seconds", "1s", EpochSeconds, "", int64(1682942401)},
+		{"custom layout", "", "2006-01-02 15:04", "", "2023-05-01 12:00"},
+		{"timezone", "", "2006-01-02 15:04", "Europe/Vienna", "2023-05-01 14:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.offset, tt.format, tt.timezone)
+			assert.NilError(t, err)
+
+			got, err := p.ResolveValue(parameter.ResolveContext{DeploymentTime: now})
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	istrings "github.com/dynatrace/dynatrace-configuration-as-code/internal/strings"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"regexp"
+	"strings"
+)
+
+// Field is the key under which the transformations of a parameter are defined in config files, e.g.
+//
+//	transform:
+//	  - trim
+//	  - regexReplace: { pattern: "[^a-z]", replacement: "-" }
+const Field = "transform"
+
+// Names of the supported transformations. Note that values of e.g. `value` and `environment` parameters are already
+// escaped for JSON templates, so JsonEscape is only needed for values resolved unescaped.
+const (
+	Trim         = "trim"
+	ToUpper      = "toUpper"
+	ToLower      = "toLower"
+	RegexReplace = "regexReplace"
+	JsonEscape   = "jsonEscape"
+	Base64       = "base64"
+)
+
+// Transformation is a single step applied to the resolved value of a parameter.
+type Transformation struct {
+	// Name is one of the supported transformations, e.g. Trim
+	Name string
+	// Pattern is the regular expression replaced by RegexReplace
+	Pattern string
+	// Replacement replaces all matches of Pattern. It may refer to capture groups, e.g. ${1}
+	Replacement string
+
+	regex *regexp.Regexp
+}
+
+// NewRegexReplace returns a transformation replacing all matches of the given pattern with replacement.
+func NewRegexReplace(pattern, replacement string) (Transformation, error) {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return Transformation{}, fmt.Errorf("invalid `pattern` of `%s`: %w", RegexReplace, err)
+	}
+	return Transformation{Name: RegexReplace, Pattern: pattern, Replacement: replacement, regex: regex}, nil
+}
+
+func (t Transformation) apply(s string) (string, error) {
+	switch t.Name {
+	case Trim:
+		return strings.TrimSpace(s), nil
+	case ToUpper:
+		return strings.ToUpper(s), nil
+	case ToLower:
+		return strings.ToLower(s), nil
+	case RegexReplace:
+		return t.regex.ReplaceAllString(s, t.Replacement), nil
+	case JsonEscape:
+		escaped, err := json.Marshal(s)
+		if err != nil {
+			return "", err
+		}
+		return string(escaped[1 : len(escaped)-1]), nil
+	case Base64:
+		return base64.StdEncoding.EncodeToString([]byte(s)), nil
+	default:
+		return "", fmt.Errorf("unknown transformation `%s`", t.Name)
+	}
+}
+
+// TransformedParameter wraps a parameter, applying transformations to its resolved value before it is inserted into
+// the template. Transformations are applied in order and work on the string representation of the value.
+type TransformedParameter struct {
+	parameter.Parameter
+	Transformations []Transformation
+}
+
+// New returns the given parameter, transforming its resolved value with the given transformations.
+func New(p parameter.Parameter, transformations []Transformation) *TransformedParameter {
+	return &TransformedParameter{Parameter: p, Transformations: transformations}
+}
+
+// this forces the compiler to check if TransformedParameter is of type Parameter
+var _ parameter.Parameter = (*TransformedParameter)(nil)
+
+func (p *TransformedParameter) ResolveValue(context parameter.ResolveContext) (interface{}, error) {
+	val, err := p.Parameter.ResolveValue(context)
+	if err != nil {
+		return nil, err
+	}
+
+	switch val.(type) {
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		return nil, parameter.NewParameterResolveValueError(context, "only single values can be transformed, not lists or maps")
+	}
+
+	s := istrings.ToString(val)
+	for _, t := range p.Transformations {
+		if s, err = t.apply(s); err != nil {
+			return nil, parameter.NewParameterResolveValueError(context, fmt.Sprintf("failed to apply transformation `%s`: %s", t.Name, err))
+		}
+	}
+	return s, nil
+}
+
+// Parse parses the list of transformations defined under Field. Transformations without arguments are given by
+// their name, transformations with arguments as a map of their name to their arguments.
+func Parse(raw interface{}) ([]Transformation, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("`%s` must be a list of transformations", Field)
+	}
+
+	result := make([]Transformation, 0, len(list))
+	for _, entry := range list {
+		t, err := parseTransformation(entry)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+func parseTransformation(entry interface{}) (Transformation, error) {
+	switch e := entry.(type) {
+	case string:
+		switch e {
+		case Trim, ToUpper, ToLower, JsonEscape, Base64:
+			return Transformation{Name: e}, nil
+		case RegexReplace:
+			return Transformation{}, fmt.Errorf("transformation `%s` requires `pattern` and `replacement`", RegexReplace)
+		default:
+			return Transformation{}, fmt.Errorf("unknown transformation `%s`", e)
+		}
+
+	case map[interface{}]interface{}:
+		return parseTransformation(maps.ToStringMap(e))
+
+	case map[string]interface{}:
+		args, found := e[RegexReplace]
+		if !found || len(e) != 1 {
+			return Transformation{}, fmt.Errorf("transformations with arguments must be a map with the single key `%s`", RegexReplace)
+		}
+
+		var argMap map[string]interface{}
+		switch a := args.(type) {
+		case map[interface{}]interface{}:
+			argMap = maps.ToStringMap(a)
+		case map[string]interface{}:
+			argMap = a
+		default:
+			return Transformation{}, fmt.Errorf("arguments of `%s` must be a map", RegexReplace)
+		}
+
+		pattern, found := argMap["pattern"]
+		if !found {
+			return Transformation{}, fmt.Errorf("transformation `%s` requires `pattern`", RegexReplace)
+		}
+		var replacement string
+		if r, found := argMap["replacement"]; found {
+			replacement = istrings.ToString(r)
+		}
+		return NewRegexReplace(istrings.ToString(pattern), replacement)
+
+	default:
+		return Transformation{}, fmt.Errorf("invalid transformation `%v`", entry)
+	}
+}
+
+// Serialize returns the transformations in the format read by Parse.
+func Serialize(transformations []Transformation) []interface{} {
+	result := make([]interface{}, len(transformations))
+	for i, t := range transformations {
+		if t.Name == RegexReplace {
+			result[i] = map[string]interface{}{
+				RegexReplace: map[string]interface{}{
+					"pattern":     t.Pattern,
+					"replacement": t.Replacement,
+				},
+			}
+		} else {
+			result[i] = t.Name
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transform
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	transformations, err := Parse([]interface{}{
+		"trim",
+		"toUpper",
+		map[interface{}]interface{}{"regexReplace": map[interface{}]interface{}{"pattern": "[^A-Z]+", "replacement": "-"}},
+		"base64",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, len(transformations), 4)
+	assert.Equal(t, transformations[2].Name, RegexReplace)
+	assert.Equal(t, transformations[2].Pattern, "[^A-Z]+")
+
+	assert.DeepEqual(t, Serialize(transformations), []interface{}{
+		"trim",
+		"toUpper",
+		map[string]interface{}{"regexReplace": map[string]interface{}{"pattern": "[^A-Z]+", "replacement": "-"}},
+		"base64",
+	})
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     interface{}
+		wantErr string
+	}{
+		{"no list", "trim", "must be a list"},
+		{"unknown transformation", []interface{}{"reverse"}, "unknown transformation `reverse`"},
+		{"regexReplace without arguments", []interface{}{"regexReplace"}, "requires `pattern` and `replacement`"},
+		{"regexReplace without pattern", []interface{}{map[string]interface{}{"regexReplace": map[string]interface{}{"replacement": "-"}}}, "requires `pattern`"},
+		{"invalid pattern", []interface{}{map[string]interface{}{"regexReplace": map[string]interface{}{"pattern": "("}}}, "invalid `pattern`"},
+		{"unknown transformation with arguments", []interface{}{map[string]interface{}{"replace": "x"}}, "single key `regexReplace`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.raw)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTransformedParameter_ResolveValue(t *testing.T) {
+	regexReplace, err := NewRegexReplace(`\s+`, "_")
+	assert.NilError(t, err)
+
+	tests := []struct {
+		name            string
+		value           interface{}
+		transformations []Transformation
+		want            interface{}
+	}{
+		{"trim", "  value ", []Transformation{{Name: Trim}}, "value"},
+		{"toUpper", "value", []Transformation{{Name: ToUpper}}, "VALUE"},
+		{"toLower", "VALUE", []Transformation{{Name: ToLower}}, "value"},
+		{"regexReplace", "a  b c", []Transformation{regexReplace}, "a_b_c"},
+		{"jsonEscape", "a\tb", []Transformation{{Name: JsonEscape}}, `a\tb`},
+		{"base64", "value", []Transformation{{Name: Base64}}, "dmFsdWU="},
+		{"numbers are transformed as strings", 42, []Transformation{{Name: Base64}}, "NDI="},
+		{"transformations are applied in order", " a b ", []Transformation{{Name: Trim}, regexReplace, {Name: ToUpper}}, "A_B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(&parameter.DummyParameter{Value: tt.value}, tt.transformations)
+			assert.Equal(t, p.GetType(), parameter.DummyParameterType)
+
+			got, err := p.ResolveValue(parameter.ResolveContext{})
+			assert.NilError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestTransformedParameter_ResolveValueFailsForMaps(t *testing.T) {
+	p := New(&parameter.DummyParameter{Value: map[string]interface{}{"a": "b"}}, []Transformation{{Name: Trim}})
+
+	_, err := p.ResolveValue(parameter.ResolveContext{ParameterName: "param"})
+	assert.ErrorContains(t, err, "only single values can be transformed")
+}
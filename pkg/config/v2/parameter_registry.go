@@ -0,0 +1,74 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+var (
+	customParameterParsersMutex sync.RWMutex
+	customParameterParsers      = map[string]parameter.ParameterSerDe{}
+)
+
+// RegisterParameterSerDe registers a custom parameter type, which is afterwards available to all
+// configurations loaded and written using ParameterParsers.
+// This allows extending monaco with organization-specific parameters (e.g. lookups in a CMDB) without
+// modifying DefaultParameterParsers.
+//
+// An error is returned if the type is empty, collides with a built-in or already registered type,
+// or the given SerDe does not define both a Serializer and a Deserializer.
+func RegisterParameterSerDe(parameterType string, serde parameter.ParameterSerDe) error {
+	if parameterType == "" {
+		return fmt.Errorf("parameter type must not be empty")
+	}
+
+	if serde.Serializer == nil || serde.Deserializer == nil {
+		return fmt.Errorf("parameter type %q must define a serializer and a deserializer", parameterType)
+	}
+
+	if _, exists := DefaultParameterParsers[parameterType]; exists {
+		return fmt.Errorf("parameter type %q is a built-in type and can not be overwritten", parameterType)
+	}
+
+	customParameterParsersMutex.Lock()
+	defer customParameterParsersMutex.Unlock()
+
+	if _, exists := customParameterParsers[parameterType]; exists {
+		return fmt.Errorf("parameter type %q is already registered", parameterType)
+	}
+
+	customParameterParsers[parameterType] = serde
+	return nil
+}
+
+// ParameterParsers returns all known parameter SerDes, consisting of DefaultParameterParsers and
+// all custom types registered via RegisterParameterSerDe. The returned map is a copy and safe to modify.
+func ParameterParsers() map[string]parameter.ParameterSerDe {
+	customParameterParsersMutex.RLock()
+	defer customParameterParsersMutex.RUnlock()
+
+	result := make(map[string]parameter.ParameterSerDe, len(DefaultParameterParsers)+len(customParameterParsers))
+	for k, v := range DefaultParameterParsers {
+		result[k] = v
+	}
+	for k, v := range customParameterParsers {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v2
+
+import (
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterParameterSerDe(t *testing.T) {
+	t.Cleanup(func() {
+		customParameterParsers = map[string]parameter.ParameterSerDe{}
+	})
+
+	err := RegisterParameterSerDe("cmdb", valueParam.ValueParameterSerde)
+	assert.NoError(t, err)
+
+	parsers := ParameterParsers()
+	assert.Contains(t, parsers, "cmdb")
+	for k := range DefaultParameterParsers {
+		assert.Contains(t, parsers, k)
+	}
+
+	err = RegisterParameterSerDe("cmdb", valueParam.ValueParameterSerde)
+	assert.Error(t, err, "registering a type twice must fail")
+
+	err = RegisterParameterSerDe(valueParam.ValueParameterType, valueParam.ValueParameterSerde)
+	assert.Error(t, err, "overwriting a built-in type must fail")
+
+	err = RegisterParameterSerDe("", valueParam.ValueParameterSerde)
+	assert.Error(t, err)
+
+	err = RegisterParameterSerDe("incomplete", parameter.ParameterSerDe{Deserializer: valueParam.ValueParameterSerde.Deserializer})
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+)
+
+// RemoteObjects holds the identifiers under which a set of configurations is expected to exist on a Dynatrace environment.
+// It allows commands working directly on an environment to recognize objects belonging to configurations that are marked
+// to be left untouched, e.g. by IgnoreOnDownload or IgnoreOnPurge.
+type RemoteObjects struct {
+	classicIds   map[string]map[string]struct{}
+	classicNames map[string]map[string]struct{}
+	settingsIds  map[string]struct{}
+}
+
+// NewRemoteObjects collects the known remote identifiers of all given configs.
+//
+// Classic configs are identified by their name (if it is a plain value), their origin object ID and their config ID
+// (both plain and as generated UUID). Settings are identified by their origin object ID and their generated external ID.
+func NewRemoteObjects(configs []Config) RemoteObjects {
+	r := RemoteObjects{
+		classicIds:   map[string]map[string]struct{}{},
+		classicNames: map[string]map[string]struct{}{},
+		settingsIds:  map[string]struct{}{},
+	}
+
+	for _, c := range configs {
+		switch t := c.Type.(type) {
+		case ClassicApiType:
+			origin := c.OriginCoordinate()
+			addToSet(r.classicIds, t.Api, origin.ConfigId)
+			addToSet(r.classicIds, t.Api, idutils.GenerateUuidFromConfigId(origin.Project, origin.ConfigId))
+			if c.OriginObjectId != "" {
+				addToSet(r.classicIds, t.Api, c.OriginObjectId)
+			}
+			if name, ok := plainName(c); ok {
+				addToSet(r.classicNames, t.Api, name)
+			}
+		case SettingsType:
+			r.settingsIds[idutils.GenerateExternalID(t.SchemaId, c.SettingsObjectId())] = struct{}{}
+			if c.OriginObjectId != "" {
+				r.settingsIds[c.OriginObjectId] = struct{}{}
+			}
+		}
+	}
+
+	return r
+}
+
+// IsEmpty returns true if no identifiers are known.
+func (r RemoteObjects) IsEmpty() bool {
+	return len(r.classicIds) == 0 && len(r.classicNames) == 0 && len(r.settingsIds) == 0
+}
+
+// ContainsClassic returns true if a classic config of the given API is known either by its ID or its name.
+func (r RemoteObjects) ContainsClassic(api, id, name string) bool {
+	if _, found := r.classicIds[api][id]; found {
+		return true
+	}
+	_, found := r.classicNames[api][name]
+	return found
+}
+
+// ContainsSettings returns true if a settings object is known either by its object ID or its external ID.
+func (r RemoteObjects) ContainsSettings(objectId, externalId string) bool {
+	if _, found := r.settingsIds[objectId]; found && objectId != "" {
+		return true
+	}
+	_, found := r.settingsIds[externalId]
+	return found && externalId != ""
+}
+
+// ContainsConfig returns true if the remote object of a config is known, i.e. the object the config was created from -
+// e.g. by downloading it - or, for settings, the object the config is deployed to.
+func (r RemoteObjects) ContainsConfig(c Config) bool {
+	switch t := c.Type.(type) {
+	case ClassicApiType:
+		name, _ := plainName(c)
+		if c.OriginObjectId != "" && r.ContainsClassic(t.Api, c.OriginObjectId, name) {
+			return true
+		}
+		return r.ContainsClassic(t.Api, c.Coordinate.ConfigId, name)
+	case SettingsType:
+		return r.ContainsSettings(c.OriginObjectId, idutils.GenerateExternalID(t.SchemaId, c.SettingsObjectId()))
+	default:
+		return false
+	}
+}
+
+func addToSet(m map[string]map[string]struct{}, key, value string) {
+	if _, found := m[key]; !found {
+		m[key] = map[string]struct{}{}
+	}
+	m[key][value] = struct{}{}
+}
+
+func plainName(c Config) (string, bool) {
+	p, ok := c.Parameters[NameParameter].(*valueParam.ValueParameter)
+	if !ok {
+		return "", false
+	}
+	name, ok := p.Value.(string)
+	return name, ok
+}
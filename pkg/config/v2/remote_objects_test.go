@@ -0,0 +1,81 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package v2
+
+import (
+	"testing"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteObjects(t *testing.T) {
+	dashboard := Config{
+		Coordinate:     coordinate.Coordinate{Project: "p", Type: "dashboard", ConfigId: "my-dashboard"},
+		Type:           ClassicApiType{Api: "dashboard"},
+		Parameters:     Parameters{NameParameter: &valueParam.ValueParameter{Value: "Manually managed"}},
+		OriginObjectId: "dashboard-origin-id",
+	}
+	setting := Config{
+		Coordinate:     coordinate.Coordinate{Project: "p", Type: "builtin:tags", ConfigId: "my-tag"},
+		Type:           SettingsType{SchemaId: "builtin:tags"},
+		OriginObjectId: "settings-origin-id",
+	}
+
+	r := NewRemoteObjects([]Config{dashboard, setting})
+
+	assert.False(t, r.IsEmpty())
+	assert.True(t, NewRemoteObjects(nil).IsEmpty())
+
+	assert.True(t, r.ContainsClassic("dashboard", "dashboard-origin-id", ""), "origin object ID")
+	assert.True(t, r.ContainsClassic("dashboard", "unknown", "Manually managed"), "name")
+	assert.True(t, r.ContainsClassic("dashboard", idutils.GenerateUuidFromConfigId("p", "my-dashboard"), ""), "generated UUID")
+	assert.False(t, r.ContainsClassic("alerting-profile", "dashboard-origin-id", "Manually managed"), "other API")
+	assert.False(t, r.ContainsClassic("dashboard", "unknown", "Other"))
+
+	assert.True(t, r.ContainsSettings("settings-origin-id", ""), "origin object ID")
+	assert.True(t, r.ContainsSettings("unknown", idutils.GenerateExternalID("builtin:tags", "my-tag")), "external ID")
+	assert.False(t, r.ContainsSettings("unknown", ""))
+	assert.False(t, r.ContainsSettings("", ""))
+
+	downloaded := Config{
+		Coordinate: coordinate.Coordinate{Project: "download", Type: "dashboard", ConfigId: "dashboard-origin-id"},
+		Type:       ClassicApiType{Api: "dashboard"},
+		Parameters: Parameters{NameParameter: &valueParam.ValueParameter{Value: "Renamed"}},
+	}
+	assert.True(t, r.ContainsConfig(downloaded))
+
+	downloadedSetting := Config{
+		Coordinate:     coordinate.Coordinate{Project: "download", Type: "builtin:tags", ConfigId: "abc"},
+		Type:           SettingsType{SchemaId: "builtin:tags"},
+		OriginObjectId: "settings-origin-id",
+	}
+	assert.True(t, r.ContainsConfig(downloadedSetting))
+
+	downloadedSetting.OriginObjectId = "other"
+	assert.False(t, r.ContainsConfig(downloadedSetting))
+
+	deployedSetting := Config{
+		Coordinate: coordinate.Coordinate{Project: "other", Type: "builtin:tags", ConfigId: "my-tag"},
+		Type:       SettingsType{SchemaId: "builtin:tags"},
+	}
+	assert.True(t, r.ContainsConfig(deployedSetting), "external ID")
+	deployedSetting.Type = SettingsType{SchemaId: "builtin:other"}
+	assert.False(t, r.ContainsConfig(deployedSetting), "external ID of other schema")
+}
@@ -0,0 +1,98 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package template
+
+import (
+	"sort"
+	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+	"text/template/parse"
+)
+
+// Fields returns the sorted names of all top-level fields (e.g. 'a' for '{{ .a.b }}') of the data used by the given
+// template. Fields relative to a changed dot (within 'range' and 'with') are not top-level, unless accessed via '$'.
+func Fields(t Template) ([]string, error) {
+	parsed, err := ParseTemplate(t.Id(), t.Content())
+	if err != nil {
+		return nil, err
+	}
+	return fieldsOf(parsed), nil
+}
+
+func fieldsOf(parsed *templ.Template) []string {
+	fields := make(map[string]struct{})
+	if parsed.Tree != nil {
+		collectFields(parsed.Tree.Root, true, fields)
+	}
+
+	result := make([]string, 0, len(fields))
+	for f := range fields {
+		result = append(result, f)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// collectFields adds all top-level fields used within node to fields. topLevelDot states whether '.' refers to the
+// template data within node.
+func collectFields(node parse.Node, topLevelDot bool, fields map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			collectFields(c, topLevelDot, fields)
+		}
+	case *parse.ActionNode:
+		collectFields(n.Pipe, topLevelDot, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			collectFields(c, topLevelDot, fields)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			collectFields(a, topLevelDot, fields)
+		}
+	case *parse.ChainNode:
+		collectFields(n.Node, topLevelDot, fields)
+	case *parse.FieldNode:
+		if topLevelDot {
+			fields[n.Ident[0]] = struct{}{}
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			fields[n.Ident[1]] = struct{}{}
+		}
+	case *parse.IfNode:
+		collectBranchFields(&n.BranchNode, topLevelDot, topLevelDot, fields)
+	case *parse.RangeNode:
+		collectBranchFields(&n.BranchNode, topLevelDot, false, fields)
+	case *parse.WithNode:
+		collectBranchFields(&n.BranchNode, topLevelDot, false, fields)
+	case *parse.TemplateNode:
+		collectFields(n.Pipe, topLevelDot, fields)
+	}
+}
+
+func collectBranchFields(n *parse.BranchNode, topLevelDot, topLevelDotInList bool, fields map[string]struct{}) {
+	collectFields(n.Pipe, topLevelDot, fields)
+	collectFields(n.List, topLevelDotInList, fields)
+	collectFields(n.ElseList, topLevelDot, fields)
+}
@@ -17,18 +17,99 @@ package template
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"strings"
 	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+)
+
+// MissingKey defines how rendering handles placeholders of a template for which no property is defined.
+type MissingKey string
+
+const (
+	// MissingKeyError fails rendering, reporting all placeholders without property at once. It is the default.
+	MissingKeyError MissingKey = "error"
+	// MissingKeyZero renders placeholders without property as empty values and logs a warning.
+	MissingKeyZero MissingKey = "zero"
 )
 
-// Render tries to render a given template with the given properties and returns the
-// resulting string. if any error occurs during rendering, an error is returned.
-func Render(template Template, properties map[string]interface{}) (string, error) {
+// MissingKeyEnvKey is the environment variable defining the MissingKey behavior of configs which do not define one.
+const MissingKeyEnvKey = "MONACO_TEMPLATE_MISSING_KEY"
+
+// ParseMissingKey parses the given MissingKey behavior.
+func ParseMissingKey(s string) (MissingKey, error) {
+	switch m := MissingKey(s); m {
+	case MissingKeyError, MissingKeyZero:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown missing key behavior %q, expected %q or %q", s, MissingKeyError, MissingKeyZero)
+	}
+}
+
+// DefaultMissingKey returns the MissingKey defined by MissingKeyEnvKey, or MissingKeyError if none or an invalid one is
+// defined.
+func DefaultMissingKey() MissingKey {
+	v := os.Getenv(MissingKeyEnvKey)
+	if v == "" {
+		return MissingKeyError
+	}
+
+	m, err := ParseMissingKey(v)
+	if err != nil {
+		log.Warn("Invalid value of environment variable %s: %v. Using %q", MissingKeyEnvKey, err, MissingKeyError)
+		return MissingKeyError
+	}
+	return m
+}
+
+// MissingKeysError is returned if a template uses placeholders for which no property is defined.
+type MissingKeysError struct {
+	// Template is the name of the template
+	Template string
+	// Keys holds the sorted names of all missing properties
+	Keys []string
+}
+
+func (e MissingKeysError) Error() string {
+	return fmt.Sprintf("template %s uses placeholders without defined parameter: %s", e.Template, strings.Join(e.Keys, ", "))
+}
+
+// Render tries to render a given template with the given properties and returns the resulting string. Placeholders
+// without property are handled as defined by missingKey. If any error occurs during rendering, an error is returned.
+func Render(template Template, properties map[string]interface{}, missingKey MissingKey) (string, error) {
 	parsedTemplate, err := ParseTemplate(template.Id(), template.Content())
 
 	if err != nil {
 		return "", fmt.Errorf("failure trying to render template %s: %w", template.Name(), err)
 	}
 
+	var missing []string
+	for _, f := range fieldsOf(parsedTemplate) {
+		if _, found := properties[f]; !found {
+			missing = append(missing, f)
+		}
+	}
+
+	if missingKey == MissingKeyZero {
+		parsedTemplate.Option("missingkey=zero")
+
+		if len(missing) > 0 {
+			log.Warn("Template %s uses placeholders without defined parameter, rendering them empty: %s", template.Name(), strings.Join(missing, ", "))
+
+			withMissing := make(map[string]interface{}, len(properties)+len(missing))
+			for k, v := range properties {
+				withMissing[k] = v
+			}
+			for _, m := range missing {
+				withMissing[m] = ""
+			}
+			properties = withMissing
+		}
+	} else if len(missing) > 0 {
+		return "", fmt.Errorf("failure trying to render template %s: %w", template.Name(), MissingKeysError{Template: template.Name(), Keys: missing})
+	}
+
 	result := bytes.Buffer{}
 
 	err = parsedTemplate.Execute(&result, properties)
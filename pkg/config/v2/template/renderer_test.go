@@ -19,6 +19,7 @@
 package template
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	templ "text/template" // nosemgrep: go.lang.security.audit.xss.import-text-template.import-text-template
@@ -181,7 +182,7 @@ func TestRender(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Render(tt.givenTemplate, tt.givenProperties)
+			got, err := Render(tt.givenTemplate, tt.givenProperties, MissingKeyError)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Render() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -192,3 +193,50 @@ func TestRender(t *testing.T) {
 		})
 	}
 }
+
+func TestRender_ReportsAllMissingKeysAtOnce(t *testing.T) {
+	template := &fileBasedTemplate{path: "a path", content: "{{ .animal }} {{ .color }} {{ .name }}"}
+
+	_, err := Render(template, map[string]interface{}{"name": "rabbit"}, MissingKeyError)
+
+	var missingKeysErr MissingKeysError
+	if !errors.As(err, &missingKeysErr) {
+		t.Fatalf("Render() error = %v, want MissingKeysError", err)
+	}
+	if want := []string{"animal", "color"}; !reflect.DeepEqual(missingKeysErr.Keys, want) {
+		t.Errorf("Render() missing keys = %v, want %v", missingKeysErr.Keys, want)
+	}
+}
+
+func TestRender_RendersMissingKeysEmptyWithMissingKeyZero(t *testing.T) {
+	template := &fileBasedTemplate{path: "a path", content: "Follow the {{ .color }}{{ .animal }}"}
+
+	got, err := Render(template, map[string]interface{}{"animal": "rabbit"}, MissingKeyZero)
+
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "Follow the rabbit"; got != want {
+		t.Errorf("Render() got = %q, want %q", got, want)
+	}
+}
+
+func TestDefaultMissingKey(t *testing.T) {
+	tests := []struct {
+		env  string
+		want MissingKey
+	}{
+		{"", MissingKeyError},
+		{"zero", MissingKeyZero},
+		{"error", MissingKeyError},
+		{"invalid", MissingKeyError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(MissingKeyEnvKey, tt.env)
+			if got := DefaultMissingKey(); got != tt.want {
+				t.Errorf("DefaultMissingKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -48,7 +48,7 @@ func TestConfigurationTemplatingFromFilesProducesValidJson(t *testing.T) {
 	testCfg := cfgs[0]
 	properties := getProperties(t, testCfg)
 
-	rendered, err := template.Render(testCfg.Template, properties)
+	rendered, err := template.Render(testCfg.Template, properties, template.MissingKeyError)
 	assert.NilError(t, err, "Expected template to render without error:\n %s", rendered)
 
 	err = json.ValidateJson(rendered, json.Location{})
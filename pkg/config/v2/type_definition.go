@@ -17,14 +17,22 @@ package v2
 import (
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/jsonschema"
 
 	"github.com/mitchellh/mapstructure"
 )
 
 type typeDefinition struct {
-	Api      string             `yaml:"api,omitempty"`
+	Api string `yaml:"api,omitempty"`
+	// Scope holds the ID of the parent object of configs of parent-scoped classic APIs, e.g. the application of key user
+	// actions.
+	Scope configParameter `yaml:"scope,omitempty"`
+
 	Settings settingsDefinition `yaml:"settings,omitempty"`
 	Entities entitiesDefinition `yaml:"entities,omitempty"`
+
+	Extension           extensionDefinition           `yaml:"extension,omitempty"`
+	ExtensionMonitoring extensionMonitoringDefinition `yaml:"extensionMonitoring,omitempty"`
 }
 
 type settingsDefinition struct {
@@ -37,6 +45,16 @@ type entitiesDefinition struct {
 	EntitiesType string `yaml:"entitiesType,omitempty"`
 }
 
+type extensionDefinition struct {
+	Name     string `yaml:"name,omitempty"`
+	Artifact string `yaml:"artifact,omitempty"`
+}
+
+type extensionMonitoringDefinition struct {
+	Extension string          `yaml:"extension,omitempty"`
+	Scope     configParameter `yaml:"scope,omitempty"`
+}
+
 // UnmarshalYAML Custom unmarshaler that knows how to handle typeDefinition.
 // 'type' section can come as string or as struct as it is defind in `typeDefinition`
 // function parameter more than once if necessary.
@@ -61,10 +79,22 @@ func (c *typeDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error
 	return fmt.Errorf("'type' section is not filed with proper values")
 }
 
+// JSONSchema implements [jsonschema.Customizer], as the type can be defined as the name of a classic API, too.
+func (typeDefinition) JSONSchema(generated *jsonschema.Schema) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{Type: "string", Description: "name of the classic config API"},
+			generated,
+		},
+	}
+}
+
 func (c *typeDefinition) isSound(knownApis map[string]struct{}) (bool, error) {
 	isClassicSound, classicErrs := c.isClassicSound(knownApis)
 	isSettingsSound, settingsErrs := c.Settings.isSettingsSound()
 	isEntitiesSound, entitiesErrs := c.Entities.isEntitiesSound()
+	isExtensionSound, extensionErrs := c.Extension.isExtensionSound()
+	isExtensionMonitoringSound, extensionMonitoringErrs := c.ExtensionMonitoring.isExtensionMonitoringSound()
 
 	types := 0
 	var err error
@@ -81,9 +111,17 @@ func (c *typeDefinition) isSound(knownApis map[string]struct{}) (bool, error) {
 		types += 1
 		err = entitiesErrs
 	}
+	if c.isExtension() {
+		types += 1
+		err = extensionErrs
+	}
+	if c.isExtensionMonitoring() {
+		types += 1
+		err = extensionMonitoringErrs
+	}
 
 	typesSound := 0
-	for _, isSound := range []bool{isClassicSound, isSettingsSound, isEntitiesSound} {
+	for _, isSound := range []bool{isClassicSound, isSettingsSound, isEntitiesSound, isExtensionSound, isExtensionMonitoringSound} {
 		if isSound {
 			typesSound += 1
 		}
@@ -134,6 +172,33 @@ func (f *entitiesDefinition) isEntitiesSound() (bool, error) {
 	return false, fmt.Errorf("next property missing: %v", e)
 }
 
+func (c *typeDefinition) isExtension() bool {
+	return c.Extension != extensionDefinition{}
+}
+func (e *extensionDefinition) isExtensionSound() (bool, error) {
+	if e.Name == "" {
+		return false, fmt.Errorf("next property missing: %v", []string{"type.extension.name"})
+	}
+	return true, nil
+}
+
+func (c *typeDefinition) isExtensionMonitoring() bool {
+	return c.ExtensionMonitoring != extensionMonitoringDefinition{}
+}
+func (e *extensionMonitoringDefinition) isExtensionMonitoringSound() (bool, error) {
+	var s []string
+	if e.Extension == "" {
+		s = append(s, "type.extensionMonitoring.extension")
+	}
+	if e.Scope == nil {
+		s = append(s, "type.extensionMonitoring.scope")
+	}
+	if s == nil {
+		return true, nil
+	}
+	return false, fmt.Errorf("next property missing: %v", s)
+}
+
 func (c *typeDefinition) isClassic() bool {
 	return c.Api != ""
 }
@@ -154,6 +219,10 @@ func (c *typeDefinition) GetApiType() string {
 		return c.Api
 	case c.isEntities():
 		return c.Entities.EntitiesType
+	case c.isExtension():
+		return ExtensionCoordinateType
+	case c.isExtensionMonitoring():
+		return ExtensionMonitoringCoordinateType
 	default:
 		return ""
 	}
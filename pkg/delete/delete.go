@@ -17,12 +17,15 @@
 package delete
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 )
 
 type DeletePointer struct {
@@ -30,7 +33,16 @@ type DeletePointer struct {
 	ConfigId string
 }
 
-func DeleteConfigs(client client.Client, apis api.APIs, entriesToDelete map[string][]DeletePointer) []error {
+// DeleteConfigs deletes the configs defined by the given entries. It is a shorthand for planning the deletion using
+// PlanDeletion and executing the resulting Plan.
+func DeleteConfigs(ctx context.Context, client client.Client, apis api.APIs, entriesToDelete map[string][]DeletePointer) []error {
+	plan, errs := PlanDeletion(ctx, client, apis, entriesToDelete)
+	return append(errs, plan.Execute(ctx, client)...)
+}
+
+// PlanDeletion determines which objects of the environment the given entries refer to, without deleting anything yet.
+func PlanDeletion(ctx context.Context, client client.Client, apis api.APIs, entriesToDelete map[string][]DeletePointer) (Plan, []error) {
+	var plan Plan
 	errs := make([]error, 0)
 
 	for targetApi, entries := range entriesToDelete {
@@ -38,73 +50,104 @@ func DeleteConfigs(client client.Client, apis api.APIs, entriesToDelete map[stri
 
 		// handle settings 2.0 objects
 		if !found {
-			deleteErrs := deleteSettingsObject(client, entries)
-			errs = append(errs, deleteErrs...)
-
+			errs = append(errs, planSettingsObjects(ctx, client, targetApi, entries, &plan)...)
 		} else {
-			deleteErrs := deleteClassicConfig(client, theApi, entries, targetApi)
-			errs = append(errs, deleteErrs...)
-
+			errs = append(errs, planClassicConfigs(ctx, client, theApi, entries, targetApi, &plan)...)
 		}
 	}
 
-	return errs
+	return plan, errs
+}
+
+func planClassicConfigs(ctx context.Context, client client.Client, theApi api.API, entries []DeletePointer, targetApi string, plan *Plan) []error {
+	if theApi.HasParent() {
+		return planParentScopedConfigs(ctx, client, theApi, entries, plan)
+	}
+	return planConfigsOfApi(ctx, client, theApi, entries, targetApi, plan)
 }
 
-func deleteClassicConfig(client client.Client, theApi api.API, entries []DeletePointer, targetApi string) []error {
+func planConfigsOfApi(ctx context.Context, client client.Client, theApi api.API, entries []DeletePointer, targetApi string, plan *Plan) []error {
 	errors := make([]error, 0)
 
-	values, err := client.ListConfigs(theApi)
+	values, err := client.ListConfigs(ctx, theApi)
 	if err != nil {
 		errors = append(errors, fmt.Errorf("failed to fetch existing configs of api `%v`. Skipping deletion all configs of this api. Reason: %w", theApi.ID, err))
 	}
+	plan.Existing += len(values)
 
 	values, errs := filterValuesToDelete(entries, values, theApi.ID)
 	errors = append(errors, errs...)
 
-	log.Info("Deleting configs of type %s...", theApi.ID)
-
 	if len(values) == 0 {
 		log.Debug("No values found to delete (%s)", targetApi)
 	}
 
 	for _, v := range values {
-		log.Debug("Deleting %v (%v)", v, targetApi)
-		if err := client.DeleteConfigById(theApi, v.Id); err != nil {
-			errors = append(errors, err)
-		}
+		plan.configs = append(plan.configs, plannedConfig{api: theApi, value: v})
 	}
 
 	return errors
 }
 
-func deleteSettingsObject(c client.Client, entries []DeletePointer) []error {
+// planParentScopedConfigs plans the deletion of configs of parent-scoped APIs. Their entries are defined as
+// '<api>/<parent-object-id>/<name or id>', as names and IDs are only unique within their parent object.
+func planParentScopedConfigs(ctx context.Context, client client.Client, theApi api.API, entries []DeletePointer, plan *Plan) []error {
 	errors := make([]error, 0)
 
+	entriesByParent := make(map[string][]DeletePointer)
 	for _, e := range entries {
-		externalID := idutils.GenerateExternalID(e.Type, e.ConfigId)
-		// get settings objects with matching external ID
-		objects, err := c.ListSettings(e.Type, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool { return o.ExternalId == externalID }})
-		if err != nil {
-			errors = append(errors, fmt.Errorf("could not fetch settings 2.0 objects with schema ID %s: %w", e.Type, err))
+		parentId, configId, found := strings.Cut(e.ConfigId, deleteDelimiter)
+		if !found || parentId == "" || configId == "" {
+			errors = append(errors, fmt.Errorf("invalid delete entry `%s%s%s`: configs of api `%v` need to be defined as `%s%s<%s-id>%s<name>`", e.Type, deleteDelimiter, e.ConfigId, theApi.ID, theApi.ID, deleteDelimiter, theApi.Parent, deleteDelimiter))
 			continue
 		}
+		entriesByParent[parentId] = append(entriesByParent[parentId], DeletePointer{Type: e.Type, ConfigId: configId})
+	}
 
-		if len(objects) == 0 {
-			log.Debug("No settings object found to delete: %s/%s", e.Type, e.ConfigId)
-			continue
+	for parentId, parentEntries := range entriesByParent {
+		errors = append(errors, planConfigsOfApi(ctx, client, theApi.ApplyParentObjectID(parentId), parentEntries, theApi.ID, plan)...)
+	}
+
+	return errors
+}
+
+func planSettingsObjects(ctx context.Context, c client.Client, schemaId string, entries []DeletePointer, plan *Plan) []error {
+	configIdsByExternalId := make(map[string]string, len(entries))
+	configIds := make(map[string]struct{}, len(entries))
+	for _, e := range entries {
+		configIdsByExternalId[idutils.GenerateExternalID(e.Type, e.ConfigId)] = e.ConfigId
+		configIds[e.ConfigId] = struct{}{}
+	}
+
+	// get settings objects with matching external ID, or an object ID given as config ID, counting all existing objects
+	// of the schema on the way
+	existing := 0
+	objects, err := c.ListSettings(ctx, schemaId, client.ListSettingsOptions{DiscardValue: true, Filter: func(o client.DownloadSettingsObject) bool {
+		existing++
+		if _, found := configIdsByExternalId[o.ExternalId]; found {
+			return true
 		}
+		_, found := configIds[o.ObjectId]
+		return found
+	}})
+	if err != nil {
+		return []error{fmt.Errorf("could not fetch settings 2.0 objects with schema ID %s: %w", schemaId, err)}
+	}
+	plan.Existing += existing
 
-		for _, obj := range objects {
-			log.Debug("Deleting settings object %s/%s with objectId %s", e.Type, e.ConfigId, obj.ObjectId)
-			err := c.DeleteSettings(obj.ObjectId)
-			if err != nil {
-				errors = append(errors, fmt.Errorf("could not delete settings 2.0 object with object ID %s", obj.ObjectId))
-			}
+	if len(objects) == 0 {
+		log.Debug("No settings objects found to delete (%s)", schemaId)
+	}
+
+	for _, obj := range objects {
+		configId, found := configIdsByExternalId[obj.ExternalId]
+		if !found {
+			configId = obj.ObjectId
 		}
+		plan.settings = append(plan.settings, plannedSettingsObject{schemaId: schemaId, configId: configId, objectId: obj.ObjectId})
 	}
 
-	return errors
+	return nil
 }
 
 // filterValuesToDelete filters the given values for only values we want to delete.
@@ -157,63 +200,139 @@ func filterValuesToDelete(entries []DeletePointer, existingValues []client.Value
 	return result, errs
 }
 
-func DeleteAllConfigs(client client.ConfigClient, apis api.APIs) (errors []error) {
+// DeleteAllConfigs deletes all configs of the given APIs, except those known to keep.
+func DeleteAllConfigs(ctx context.Context, client client.ConfigClient, apis api.APIs, keep config.RemoteObjects) []error {
+	var plan Plan
+	errs := planAllConfigs(ctx, client, apis, keep, &plan)
+	return append(errs, plan.executeConfigs(ctx, client)...)
+}
+
+// DeleteAllSettingsObjects deletes all settings objects that can be queried, except those known to keep.
+func DeleteAllSettingsObjects(ctx context.Context, c client.SettingsClient, keep config.RemoteObjects) []error {
+	var plan Plan
+	errs := planAllSettingsObjects(ctx, c, keep, &plan)
+	return append(errs, plan.executeSettings(ctx, c)...)
+}
+
+// PlanPurge determines all configs of the given APIs and all settings objects that can be queried, except those known
+// to keep, without deleting anything yet.
+func PlanPurge(ctx context.Context, c client.Client, apis api.APIs, keep config.RemoteObjects) (Plan, []error) {
+	var plan Plan
+	errs := planAllConfigs(ctx, c, apis, keep, &plan)
+	errs = append(errs, planAllSettingsObjects(ctx, c, keep, &plan)...)
+	return plan, errs
+}
+
+// PlanPurgeTypes determines all configs of the given APIs and all settings objects of the given schemas, except those
+// known to keep, without deleting anything yet.
+func PlanPurgeTypes(ctx context.Context, c client.Client, apis api.APIs, schemaIds []string, keep config.RemoteObjects) (Plan, []error) {
+	var plan Plan
+	errs := planAllConfigs(ctx, c, apis, keep, &plan)
+	errs = append(errs, planSettingsObjectsOfSchemas(ctx, c, schemaIds, nil, keep, &plan)...)
+	return plan, errs
+}
+
+// PlanPurgeByExternalIds determines all settings objects of the given schemas whose externalId is the one generated for
+// one of the given managed configs, except those known to keep, without deleting anything yet. If no schemas are given,
+// the objects of all schemas are considered.
+func PlanPurgeByExternalIds(ctx context.Context, c client.SettingsClient, schemaIds []string, managed config.RemoteObjects, keep config.RemoteObjects) (Plan, []error) {
+	var plan Plan
+	if len(schemaIds) == 0 {
+		var err error
+		if schemaIds, err = listSchemaIds(ctx, c); err != nil {
+			return plan, []error{err}
+		}
+	}
+
+	isManaged := func(o client.DownloadSettingsObject) bool { return managed.ContainsSettings("", o.ExternalId) }
+	errs := planSettingsObjectsOfSchemas(ctx, c, schemaIds, isManaged, keep, &plan)
+	return plan, errs
+}
+
+func planAllConfigs(ctx context.Context, client client.ConfigClient, apis api.APIs, keep config.RemoteObjects, plan *Plan) (errors []error) {
 
 	for _, api := range apis {
+		if api.HasParent() {
+			log.Debug("Skipping configs of type %s, they are deleted together with their parent configs of type %s", api.ID, api.Parent)
+			continue
+		}
+
 		log.Info("Collecting configs of type %s...", api.ID)
-		values, err := client.ListConfigs(api)
+		values, err := client.ListConfigs(ctx, api)
 		if err != nil {
 			errors = append(errors, err)
 			continue
 		}
-
-		log.Info("Deleting %d configs of type %s...", len(values), api.ID)
+		plan.Existing += len(values)
 
 		for _, v := range values {
-			log.Debug("Deleting config %s/%s", api.ID, v.Id)
-			// TODO(improvement): this could be improved by filtering for default configs the same way as Download does
-			err := client.DeleteConfigById(api, v.Id)
-
-			if err != nil {
-				errors = append(errors, err)
+			if keep.ContainsClassic(api.ID, v.Id, v.Name) {
+				log.Info("Keeping config %s/%s (%s) as it is marked to be ignored on purge", api.ID, v.Id, v.Name)
+				continue
 			}
+
+			// TODO(improvement): this could be improved by filtering for default configs the same way as Download does
+			plan.configs = append(plan.configs, plannedConfig{api: api, value: v})
 		}
 	}
 
 	return errors
 }
 
-// DeleteAllSettingsObjects deletes all settings objects that can be queried.
-func DeleteAllSettingsObjects(c client.SettingsClient) []error {
-	var errs []error
+func planAllSettingsObjects(ctx context.Context, c client.SettingsClient, keep config.RemoteObjects, plan *Plan) []error {
+	schemaIds, err := listSchemaIds(ctx, c)
+	if err != nil {
+		return []error{err}
+	}
 
-	schemas, err := c.ListSchemas()
+	return planSettingsObjectsOfSchemas(ctx, c, schemaIds, nil, keep, plan)
+}
+
+func listSchemaIds(ctx context.Context, c client.SettingsClient) ([]string, error) {
+	schemas, err := c.ListSchemas(ctx)
 	if err != nil {
-		return []error{fmt.Errorf("failed to fetch settings schemas. No settings will be deleted. Reason: %w", err)}
+		return nil, fmt.Errorf("failed to fetch settings schemas. No settings will be deleted. Reason: %w", err)
 	}
 
 	schemaIds := make([]string, len(schemas))
 	for i := range schemas {
 		schemaIds[i] = schemas[i].SchemaId
 	}
+	return schemaIds, nil
+}
 
-	log.Debug("Deleting settings of schemas %v", schemaIds)
+// planSettingsObjectsOfSchemas plans the deletion of all settings objects of the given schemas matching the given
+// filter. If the filter is nil, all objects are planned.
+func planSettingsObjectsOfSchemas(ctx context.Context, c client.SettingsClient, schemaIds []string, filter client.ListSettingsFilter, keep config.RemoteObjects, plan *Plan) []error {
+	var errs []error
+
+	log.Debug("Collecting settings of schemas %v", schemaIds)
 
 	for _, s := range schemaIds {
 		log.Info("Collecting configs of type %s...", s)
-		settings, err := c.ListSettings(s, client.ListSettingsOptions{DiscardValue: true})
+		settings, err := c.ListSettings(ctx, s, client.ListSettingsOptions{DiscardValue: true})
 		if err != nil {
 			errs = append(errs, err)
 			continue
 		}
+		plan.Existing += len(settings)
+
+		if filter != nil {
+			var filtered []client.DownloadSettingsObject
+			for _, setting := range settings {
+				if filter(setting) {
+					filtered = append(filtered, setting)
+				}
+			}
+			settings = filtered
+		}
 
-		log.Info("Deleting %d configs of type %s...", len(settings), s)
 		for _, setting := range settings {
-			log.Debug("Deleting settings object with objectId=%s", setting.ObjectId)
-			err := c.DeleteSettings(setting.ObjectId)
-			if err != nil {
-				errs = append(errs, err)
+			if keep.ContainsSettings(setting.ObjectId, setting.ExternalId) {
+				log.Info("Keeping settings object with objectId=%s as it is marked to be ignored on purge", setting.ObjectId)
+				continue
 			}
+			plan.settings = append(plan.settings, plannedSettingsObject{schemaId: s, objectId: setting.ObjectId})
 		}
 	}
 
@@ -34,7 +34,7 @@ type loaderContext struct {
 }
 
 type deleteFileDefinition struct {
-	DeleteEntries []string `yaml:"delete"`
+	DeleteEntries []string `yaml:"delete" jsonschema:"required"`
 }
 
 type DeleteEntryParserError struct {
@@ -268,3 +268,24 @@ func TestLoadEntriesToDeleteWithEmptyFile(t *testing.T) {
 	assert.Equal(t, 1, len(errors))
 	assert.Equal(t, 0, len(result))
 }
+
+func TestWriteDeleteFileCanBeLoaded(t *testing.T) {
+	deleteFilePath := filepath.FromSlash("/home/test/monaco/delete.yaml")
+	fs := afero.NewMemMapFs()
+
+	entries := []DeletePointer{
+		{Type: "management-zone", ConfigId: "test entity/entities"},
+		{Type: "auto-tag", ConfigId: "random tag"},
+	}
+
+	err := WriteDeleteFile(fs, deleteFilePath, entries)
+	assert.NilError(t, err)
+
+	result, errors := LoadEntriesToDelete(fs, []string{"management-zone", "auto-tag"}, deleteFilePath)
+
+	assert.Equal(t, 0, len(errors))
+	assert.DeepEqual(t, result, map[string][]DeletePointer{
+		"management-zone": {entries[0]},
+		"auto-tag":        {entries[1]},
+	})
+}
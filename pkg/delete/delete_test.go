@@ -19,10 +19,14 @@
 package delete
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -31,7 +35,7 @@ import (
 func TestDeleteSettings(t *testing.T) {
 	t.Run("TestDeleteSettings", func(t *testing.T) {
 		c := client.NewMockClient(gomock.NewController(t))
-		c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(schemaID string, listOpts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, schemaID string, listOpts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
 			assert.True(t, listOpts.Filter(client.DownloadSettingsObject{ExternalId: "monaco:YnVpbHRpbjphbGVydGluZy5wcm9maWxlJGlkMQ=="}))
 			return []client.DownloadSettingsObject{
 				{
@@ -45,7 +49,7 @@ func TestDeleteSettings(t *testing.T) {
 			}, nil
 
 		})
-		c.EXPECT().DeleteSettings(gomock.Eq("12345")).Return(nil)
+		c.EXPECT().DeleteSettings(gomock.Any(), gomock.Eq("12345")).Return(nil)
 		entriesToDelete := map[string][]DeletePointer{
 			"builtin:alerting.profile": {
 				{
@@ -54,13 +58,33 @@ func TestDeleteSettings(t *testing.T) {
 				},
 			},
 		}
-		errs := DeleteConfigs(c, api.NewV1APIs(), entriesToDelete)
+		errs := DeleteConfigs(context.TODO(), c, api.NewV1APIs(), entriesToDelete)
+		assert.Empty(t, errs, "errors should be empty")
+	})
+
+	t.Run("TestDeleteSettings - by object ID", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, schemaID string, listOpts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+			assert.True(t, listOpts.Filter(client.DownloadSettingsObject{ObjectId: "12345"}))
+			assert.False(t, listOpts.Filter(client.DownloadSettingsObject{ObjectId: "67890"}))
+			return []client.DownloadSettingsObject{{SchemaId: "builtin:alerting.profile", ObjectId: "12345"}}, nil
+		})
+		c.EXPECT().DeleteSettings(gomock.Any(), gomock.Eq("12345")).Return(nil)
+		entriesToDelete := map[string][]DeletePointer{
+			"builtin:alerting.profile": {
+				{
+					Type:     "builtin:alerting.profile",
+					ConfigId: "12345",
+				},
+			},
+		}
+		errs := DeleteConfigs(context.TODO(), c, api.NewV1APIs(), entriesToDelete)
 		assert.Empty(t, errs, "errors should be empty")
 	})
 
 	t.Run("TestDeleteSettings - List settings with external ID fails", func(t *testing.T) {
 		c := client.NewMockClient(gomock.NewController(t))
-		c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Return([]client.DownloadSettingsObject{}, client.RespError{Err: fmt.Errorf("WHOPS"), StatusCode: 0})
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return([]client.DownloadSettingsObject{}, client.RespError{Err: fmt.Errorf("WHOPS"), StatusCode: 0})
 		entriesToDelete := map[string][]DeletePointer{
 			"builtin:alerting.profile": {
 				{
@@ -69,13 +93,13 @@ func TestDeleteSettings(t *testing.T) {
 				},
 			},
 		}
-		errs := DeleteConfigs(c, api.NewV1APIs(), entriesToDelete)
+		errs := DeleteConfigs(context.TODO(), c, api.NewV1APIs(), entriesToDelete)
 		assert.Len(t, errs, 1, "errors should have len 1")
 	})
 
 	t.Run("TestDeleteSettings - List settings returns no objects", func(t *testing.T) {
 		c := client.NewMockClient(gomock.NewController(t))
-		c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Return([]client.DownloadSettingsObject{}, nil)
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return([]client.DownloadSettingsObject{}, nil)
 		entriesToDelete := map[string][]DeletePointer{
 			"builtin:alerting.profile": {
 				{
@@ -84,13 +108,13 @@ func TestDeleteSettings(t *testing.T) {
 				},
 			},
 		}
-		errs := DeleteConfigs(c, api.NewV1APIs(), entriesToDelete)
+		errs := DeleteConfigs(context.TODO(), c, api.NewV1APIs(), entriesToDelete)
 		assert.Len(t, errs, 0)
 	})
 
 	t.Run("TestDeleteSettings - Delete settings based on object ID fails", func(t *testing.T) {
 		c := client.NewMockClient(gomock.NewController(t))
-		c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Return([]client.DownloadSettingsObject{
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Return([]client.DownloadSettingsObject{
 			{
 				ExternalId:    "externalID",
 				SchemaVersion: "v1",
@@ -100,7 +124,7 @@ func TestDeleteSettings(t *testing.T) {
 				Value:         nil,
 			},
 		}, nil)
-		c.EXPECT().DeleteSettings(gomock.Eq("12345")).Return(fmt.Errorf("WHOPS"))
+		c.EXPECT().DeleteSettings(gomock.Any(), gomock.Eq("12345")).Return(fmt.Errorf("WHOPS"))
 		entriesToDelete := map[string][]DeletePointer{
 			"builtin:alerting.profile": {
 				{
@@ -109,7 +133,7 @@ func TestDeleteSettings(t *testing.T) {
 				},
 			},
 		}
-		errs := DeleteConfigs(c, api.NewV1APIs(), entriesToDelete)
+		errs := DeleteConfigs(context.TODO(), c, api.NewV1APIs(), entriesToDelete)
 		assert.Len(t, errs, 1, "errors should have len 1")
 	})
 
@@ -216,13 +240,13 @@ func TestSplitConfigsForDeletion(t *testing.T) {
 			entriesToDelete := map[string][]DeletePointer{a.ID: tc.args.entries}
 
 			client := client.NewMockClient(gomock.NewController(t))
-			client.EXPECT().ListConfigs(a).Return(tc.args.values, nil)
+			client.EXPECT().ListConfigs(gomock.Any(), a).Return(tc.args.values, nil)
 
 			for _, id := range tc.expect.ids {
-				client.EXPECT().DeleteConfigById(a, id)
+				client.EXPECT().DeleteConfigById(gomock.Any(), a, id)
 			}
 
-			errs := DeleteConfigs(client, apiMap, entriesToDelete)
+			errs := DeleteConfigs(context.TODO(), client, apiMap, entriesToDelete)
 
 			assert.Equal(t, len(errs), tc.expect.numErrs)
 		})
@@ -236,9 +260,92 @@ func TestSplitConfigsForDeletionClientReturnsError(t *testing.T) {
 	entriesToDelete := map[string][]DeletePointer{a.ID: {{}}}
 
 	client := client.NewMockClient(gomock.NewController(t))
-	client.EXPECT().ListConfigs(a).Return(nil, errors.New("error"))
+	client.EXPECT().ListConfigs(gomock.Any(), a).Return(nil, errors.New("error"))
 
-	errs := DeleteConfigs(client, apiMap, entriesToDelete)
+	errs := DeleteConfigs(context.TODO(), client, apiMap, entriesToDelete)
 
 	assert.NotEmpty(t, errs, "an error should be returned")
 }
+
+func TestDeleteConfigsOfParentScopedApi(t *testing.T) {
+	a := api.API{ID: "child", URLPath: "/parents/{parentId}/children", Parent: "parent"}
+
+	apiMap := api.APIs{a.ID: a}
+	entriesToDelete := map[string][]DeletePointer{a.ID: {
+		{Type: a.ID, ConfigId: "PARENT-1/child-name"},
+		{Type: a.ID, ConfigId: "missing-parent"},
+	}}
+
+	applied := a.ApplyParentObjectID("PARENT-1")
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), applied).Return([]client.Value{{Id: "child-id", Name: "child-name"}}, nil)
+	c.EXPECT().DeleteConfigById(gomock.Any(), applied, "child-id")
+
+	errs := DeleteConfigs(context.TODO(), c, apiMap, entriesToDelete)
+
+	assert.Len(t, errs, 1, "entry without parent object ID should fail")
+}
+
+func TestPlanPurgeTypes(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	a := api.NewAPIs()["alerting-profile"]
+	c.EXPECT().ListConfigs(gomock.Any(), a).Return([]client.Value{{Id: "c1", Name: "profile"}}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return([]client.DownloadSettingsObject{{ObjectId: "o1"}, {ObjectId: "o2"}}, nil)
+	c.EXPECT().ListSchemas(gomock.Any()).Times(0)
+
+	plan, errs := PlanPurgeTypes(context.TODO(), c, api.APIs{a.ID: a}, []string{"builtin:alerting.profile"}, config.RemoteObjects{})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 3, plan.Count())
+	assert.Equal(t, []string{"alerting-profile", "builtin:alerting.profile"}, plan.Types())
+}
+
+func TestPlanPurgeByExternalIds(t *testing.T) {
+	managed := config.NewRemoteObjects([]config.Config{{
+		Coordinate: coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"},
+		Type:       config.SettingsType{SchemaId: "builtin:alerting.profile"},
+	}})
+	settings := []client.DownloadSettingsObject{
+		{ObjectId: "o1", ExternalId: idutils.GenerateExternalID("builtin:alerting.profile", "profile")},
+		{ObjectId: "o2", ExternalId: idutils.GenerateExternalID("builtin:alerting.profile", "other-project-profile")},
+		{ObjectId: "o3", ExternalId: "other"},
+		{ObjectId: "o4"},
+	}
+
+	t.Run("given schemas", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSchemas(gomock.Any()).Times(0)
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).Return(settings, nil)
+
+		plan, errs := PlanPurgeByExternalIds(context.TODO(), c, []string{"builtin:alerting.profile"}, managed, config.RemoteObjects{})
+
+		assert.Empty(t, errs)
+		assert.Equal(t, 1, plan.Count(), "only objects of the managed configs are deleted")
+		assert.Equal(t, 4, plan.Existing)
+	})
+
+	t.Run("all schemas", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: "a"}, {SchemaId: "b"}}, nil)
+		c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(settings, nil)
+
+		plan, errs := PlanPurgeByExternalIds(context.TODO(), c, nil, managed, config.RemoteObjects{})
+
+		assert.Empty(t, errs)
+		assert.Equal(t, 2, plan.Count())
+		assert.Equal(t, []string{"a", "b"}, plan.Types())
+	})
+
+	t.Run("objects of other monaco features are kept", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), "builtin:tags.auto-tagging", gomock.Any()).Return([]client.DownloadSettingsObject{
+			{ObjectId: "lock", ExternalId: idutils.GenerateExternalID("builtin:tags.auto-tagging", "monaco-deploy-lock")},
+		}, nil)
+
+		plan, errs := PlanPurgeByExternalIds(context.TODO(), c, []string{"builtin:tags.auto-tagging"}, managed, config.RemoteObjects{})
+
+		assert.Empty(t, errs)
+		assert.Equal(t, 0, plan.Count())
+	})
+}
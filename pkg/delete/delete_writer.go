@@ -0,0 +1,41 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+)
+
+// WriteDeleteFile writes the given entries as delete file, which can be loaded again using LoadEntriesToDelete.
+func WriteDeleteFile(fs afero.Fs, deleteFile string, entries []DeletePointer) error {
+	definition := deleteFileDefinition{DeleteEntries: make([]string, len(entries))}
+	for i, e := range entries {
+		definition.DeleteEntries[i] = e.Type + deleteDelimiter + e.ConfigId
+	}
+
+	data, err := yaml.Marshal(definition)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delete entries: %w", err)
+	}
+
+	if err := afero.WriteFile(fs, deleteFile, data, 0664); err != nil {
+		return fmt.Errorf("failed to write delete file %q: %w", deleteFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+)
+
+// Plan holds the objects a deletion would delete from an environment. Plans are created by PlanDeletion and PlanPurge,
+// which allows checking the planned deletions - e.g. against a Threshold - before executing them.
+type Plan struct {
+	configs  []plannedConfig
+	settings []plannedSettingsObject
+
+	// Existing is the number of objects of the affected types that exist in the environment, including the planned ones
+	Existing int
+}
+
+type plannedConfig struct {
+	api   api.API
+	value client.Value
+}
+
+type plannedSettingsObject struct {
+	schemaId string
+	// configId is the config the object was deployed from, if known
+	configId string
+	objectId string
+}
+
+// Count returns the number of objects the plan deletes.
+func (p Plan) Count() int {
+	return len(p.configs) + len(p.settings)
+}
+
+// Types returns the sorted IDs of all APIs and settings schemas the plan deletes objects of.
+func (p Plan) Types() []string {
+	seen := make(map[string]struct{})
+	for _, c := range p.configs {
+		seen[c.api.ID] = struct{}{}
+	}
+	for _, s := range p.settings {
+		seen[s.schemaId] = struct{}{}
+	}
+
+	types := make([]string, 0, len(seen))
+	for t := range seen {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ExecuteOptions define how a Plan is executed by ExecuteWithOptions.
+type ExecuteOptions struct {
+	// BatchSize is the number of objects deleted before progress is reported and BatchPause is waited. If it's 0,
+	// progress is only reported once all objects are deleted.
+	BatchSize int
+	// BatchPause is waited after each batch, limiting the rate of delete requests
+	BatchPause time.Duration
+	// ContinueOnError states that objects failing to be deleted are recorded in Progress and the execution continues.
+	// Otherwise, the execution stops at the first error.
+	ContinueOnError bool
+	// Progress optionally records the deleted and failed objects. Objects already recorded as deleted are skipped,
+	// which allows resuming an interrupted execution.
+	Progress *Progress
+	// OnProgress is optionally called after each batch, e.g. to persist Progress
+	OnProgress func(done, total int)
+}
+
+// Progress records which objects of a plan were deleted or failed to be deleted, keyed by their type and ID.
+type Progress struct {
+	Deleted []string          `json:"deleted"`
+	Failed  map[string]string `json:"failed,omitempty"`
+}
+
+// deletion deletes a single planned object.
+type deletion struct {
+	// key identifies the object in Progress
+	key      string
+	typeId   string
+	describe string
+	delete   func(ctx context.Context) error
+}
+
+func (p Plan) configDeletions(c client.ConfigClient) []deletion {
+	result := make([]deletion, len(p.configs))
+	for i, pc := range p.configs {
+		pc := pc
+		result[i] = deletion{
+			key:      pc.api.ID + "/" + pc.value.Id,
+			typeId:   pc.api.ID,
+			describe: fmt.Sprintf("config %s/%s (%s)", pc.api.ID, pc.value.Id, pc.value.Name),
+			delete: func(ctx context.Context) error {
+				return c.DeleteConfigById(ctx, pc.api, pc.value.Id)
+			},
+		}
+	}
+	return result
+}
+
+func (p Plan) settingsDeletions(c client.SettingsClient) []deletion {
+	result := make([]deletion, len(p.settings))
+	for i, ps := range p.settings {
+		ps := ps
+		describe := fmt.Sprintf("settings object with objectId=%s", ps.objectId)
+		if ps.configId != "" {
+			describe = fmt.Sprintf("settings object %s/%s with objectId %s", ps.schemaId, ps.configId, ps.objectId)
+		}
+		result[i] = deletion{
+			key:      ps.schemaId + "/" + ps.objectId,
+			typeId:   ps.schemaId,
+			describe: describe,
+			delete: func(ctx context.Context) error {
+				if err := c.DeleteSettings(ctx, ps.objectId); err != nil {
+					return fmt.Errorf("could not delete settings 2.0 object with object ID %s: %w", ps.objectId, err)
+				}
+				return nil
+			},
+		}
+	}
+	return result
+}
+
+// Execute deletes all planned objects, continuing on errors.
+func (p Plan) Execute(ctx context.Context, c client.Client) []error {
+	return p.ExecuteWithOptions(ctx, c, ExecuteOptions{ContinueOnError: true})
+}
+
+// ExecuteWithOptions deletes all planned objects in batches, reporting the progress after each batch.
+func (p Plan) ExecuteWithOptions(ctx context.Context, c client.Client, opts ExecuteOptions) []error {
+	return execute(ctx, append(p.configDeletions(c), p.settingsDeletions(c)...), opts)
+}
+
+func (p Plan) executeConfigs(ctx context.Context, c client.ConfigClient) []error {
+	return execute(ctx, p.configDeletions(c), ExecuteOptions{ContinueOnError: true})
+}
+
+func (p Plan) executeSettings(ctx context.Context, c client.SettingsClient) []error {
+	return execute(ctx, p.settingsDeletions(c), ExecuteOptions{ContinueOnError: true})
+}
+
+func execute(ctx context.Context, deletions []deletion, opts ExecuteOptions) []error {
+	var errs []error
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = &Progress{}
+	}
+	if progress.Failed == nil {
+		progress.Failed = make(map[string]string)
+	}
+	alreadyDeleted := make(map[string]struct{}, len(progress.Deleted))
+	for _, key := range progress.Deleted {
+		alreadyDeleted[key] = struct{}{}
+	}
+	report := func(done int) {
+		if opts.OnProgress != nil {
+			opts.OnProgress(done, len(deletions))
+		}
+	}
+
+	previousType := ""
+	inBatch := 0
+	for i, d := range deletions {
+		if _, found := alreadyDeleted[d.key]; found {
+			log.Debug("Skipping %s, it was already deleted", d.describe)
+			continue
+		}
+
+		if d.typeId != previousType {
+			log.Info("Deleting configs of type %s...", d.typeId)
+			previousType = d.typeId
+		}
+
+		log.Debug("Deleting %s", d.describe)
+		if err := d.delete(ctx); err != nil {
+			errs = append(errs, err)
+			progress.Failed[d.key] = err.Error()
+			if !opts.ContinueOnError {
+				report(i)
+				return errs
+			}
+		} else {
+			progress.Deleted = append(progress.Deleted, d.key)
+			delete(progress.Failed, d.key)
+		}
+
+		inBatch++
+		if opts.BatchSize > 0 && inBatch >= opts.BatchSize && i < len(deletions)-1 {
+			report(i + 1)
+			inBatch = 0
+			select {
+			case <-ctx.Done():
+				return append(errs, ctx.Err())
+			case <-time.After(opts.BatchPause):
+			}
+		}
+	}
+
+	report(len(deletions))
+	return errs
+}
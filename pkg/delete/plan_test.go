@@ -0,0 +1,93 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testPlan() Plan {
+	return Plan{settings: []plannedSettingsObject{
+		{schemaId: "builtin:alerting.profile", objectId: "a"},
+		{schemaId: "builtin:alerting.profile", objectId: "b"},
+		{schemaId: "builtin:alerting.profile", objectId: "c"},
+	}}
+}
+
+func TestPlan_ExecuteWithOptions(t *testing.T) {
+	t.Run("progress is reported after each batch", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().DeleteSettings(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+		var reported []int
+		progress := &Progress{}
+		errs := testPlan().ExecuteWithOptions(context.TODO(), c, ExecuteOptions{
+			BatchSize:  2,
+			Progress:   progress,
+			OnProgress: func(done, total int) { reported = append(reported, done) },
+		})
+		assert.Empty(t, errs)
+		assert.Equal(t, []int{2, 3}, reported)
+		assert.Equal(t, []string{"builtin:alerting.profile/a", "builtin:alerting.profile/b", "builtin:alerting.profile/c"}, progress.Deleted)
+	})
+
+	t.Run("execution stops at the first error by default", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().DeleteSettings(gomock.Any(), "a").Return(nil)
+		c.EXPECT().DeleteSettings(gomock.Any(), "b").Return(errors.New("failed"))
+
+		progress := &Progress{}
+		errs := testPlan().ExecuteWithOptions(context.TODO(), c, ExecuteOptions{Progress: progress})
+		assert.Len(t, errs, 1)
+		assert.Equal(t, []string{"builtin:alerting.profile/a"}, progress.Deleted)
+		assert.Contains(t, progress.Failed, "builtin:alerting.profile/b")
+	})
+
+	t.Run("failures are recorded when continuing on errors", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().DeleteSettings(gomock.Any(), "a").Return(nil)
+		c.EXPECT().DeleteSettings(gomock.Any(), "b").Return(errors.New("failed"))
+		c.EXPECT().DeleteSettings(gomock.Any(), "c").Return(nil)
+
+		progress := &Progress{}
+		errs := testPlan().ExecuteWithOptions(context.TODO(), c, ExecuteOptions{ContinueOnError: true, Progress: progress})
+		assert.Len(t, errs, 1)
+		assert.Len(t, progress.Deleted, 2)
+		assert.Len(t, progress.Failed, 1)
+	})
+
+	t.Run("objects already deleted are skipped", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().DeleteSettings(gomock.Any(), "b").Return(nil)
+
+		progress := &Progress{
+			Deleted: []string{"builtin:alerting.profile/a", "builtin:alerting.profile/c"},
+			Failed:  map[string]string{"builtin:alerting.profile/b": "failed"},
+		}
+		errs := testPlan().ExecuteWithOptions(context.TODO(), c, ExecuteOptions{Progress: progress})
+		assert.Empty(t, errs)
+		assert.Len(t, progress.Deleted, 3)
+		assert.Empty(t, progress.Failed)
+	})
+}
@@ -0,0 +1,141 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Threshold limits the number of objects a deletion may delete from an environment. It is either an absolute number
+// of objects (e.g. '50'), or a percentage of the existing objects of the affected types (e.g. '20%').
+//
+// The zero value does not limit deletions. Threshold implements pflag.Value, so it can be used as a CLI flag directly.
+type Threshold struct {
+	limit      int
+	percentage bool
+	set        bool
+}
+
+// ParseThreshold parses a threshold given either as absolute number or as percentage, e.g. '50' or '20%'.
+func ParseThreshold(s string) (Threshold, error) {
+	value, percentage := strings.CutSuffix(strings.TrimSpace(s), "%")
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: expected a non-negative number (e.g. '50') or percentage (e.g. '20%%')", s)
+	}
+	if percentage && limit > 100 {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: percentage must not exceed 100%%", s)
+	}
+
+	return Threshold{limit: limit, percentage: percentage, set: true}, nil
+}
+
+// IsSet returns whether the threshold limits deletions at all.
+func (t Threshold) IsSet() bool {
+	return t.set
+}
+
+// Check returns a ThresholdExceededError if the plan deletes more objects than the threshold allows.
+func (t Threshold) Check(p Plan) error {
+	if !t.set {
+		return nil
+	}
+
+	exceeded := p.Count() > t.limit
+	if t.percentage {
+		exceeded = p.Count()*100 > t.limit*p.Existing
+	}
+
+	if exceeded {
+		return ThresholdExceededError{Threshold: t, Planned: p.Count(), Existing: p.Existing}
+	}
+	return nil
+}
+
+func (t Threshold) String() string {
+	if !t.set {
+		return ""
+	}
+	if t.percentage {
+		return strconv.Itoa(t.limit) + "%"
+	}
+	return strconv.Itoa(t.limit)
+}
+
+// Set implements pflag.Value
+func (t *Threshold) Set(s string) error {
+	parsed, err := ParseThreshold(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Type implements pflag.Value
+func (t *Threshold) Type() string {
+	return "threshold"
+}
+
+// ThresholdExceededError is returned if a deletion plans to delete more objects than a Threshold allows.
+type ThresholdExceededError struct {
+	Threshold Threshold
+	// Planned is the number of objects the deletion would delete
+	Planned int
+	// Existing is the number of existing objects of the affected types
+	Existing int
+}
+
+func (e ThresholdExceededError) Error() string {
+	return fmt.Sprintf("deletion of %d out of %d existing objects exceeds the threshold of %s", e.Planned, e.Existing, e.Threshold)
+}
+
+// Safeguard aborts deletions which delete more objects than expected, e.g. due to a malformed delete file.
+type Safeguard struct {
+	// MaxDeletes limits the number of objects deleted from each environment
+	MaxDeletes Threshold
+	// Confirmed allows deletions exceeding MaxDeletes
+	Confirmed bool
+}
+
+// Check checks the plans of all environments, keyed by environment name, against MaxDeletes. An error is returned if
+// any plan exceeds the threshold, unless the deletion is confirmed - in which case only a warning is logged.
+func (s Safeguard) Check(plans map[string]Plan) error {
+	envs := make([]string, 0, len(plans))
+	for env := range plans {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+
+	var errs []error
+	for _, env := range envs {
+		if err := s.MaxDeletes.Check(plans[env]); err != nil {
+			if s.Confirmed {
+				log.Warn("Environment %q: %s. Deleting anyway, as the deletion is confirmed", env, err)
+				continue
+			}
+			errs = append(errs, fmt.Errorf("environment %q: %w", env, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,134 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package delete
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		given   string
+		want    string
+		wantErr bool
+	}{
+		{given: "50", want: "50"},
+		{given: "0", want: "0"},
+		{given: "20%", want: "20%"},
+		{given: " 100% ", want: "100%"},
+		{given: "", wantErr: true},
+		{given: "-1", wantErr: true},
+		{given: "101%", wantErr: true},
+		{given: "ten", wantErr: true},
+		{given: "%", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.given, func(t *testing.T) {
+			got, err := ParseThreshold(tt.given)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, got.IsSet())
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestThreshold_Check(t *testing.T) {
+	plan := Plan{configs: make([]plannedConfig, 3), settings: make([]plannedSettingsObject, 2), Existing: 20}
+
+	tests := []struct {
+		threshold string
+		exceeded  bool
+	}{
+		{threshold: "5", exceeded: false},
+		{threshold: "4", exceeded: true},
+		{threshold: "25%", exceeded: false},
+		{threshold: "24%", exceeded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.threshold, func(t *testing.T) {
+			threshold, err := ParseThreshold(tt.threshold)
+			assert.NoError(t, err)
+
+			err = threshold.Check(plan)
+			if !tt.exceeded {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorAs(t, err, &ThresholdExceededError{})
+			assert.ErrorContains(t, err, "deletion of 5 out of 20 existing objects exceeds the threshold of "+tt.threshold)
+		})
+	}
+
+	t.Run("unset threshold does not limit deletions", func(t *testing.T) {
+		assert.NoError(t, Threshold{}.Check(plan))
+	})
+}
+
+func TestSafeguard_Check(t *testing.T) {
+	threshold, err := ParseThreshold("1")
+	assert.NoError(t, err)
+
+	plans := map[string]Plan{
+		"small": {configs: make([]plannedConfig, 1), Existing: 1},
+		"large": {configs: make([]plannedConfig, 2), Existing: 2},
+	}
+
+	err = Safeguard{MaxDeletes: threshold}.Check(plans)
+	assert.ErrorContains(t, err, `environment "large"`)
+	assert.NotContains(t, err.Error(), `environment "small"`)
+
+	assert.NoError(t, Safeguard{MaxDeletes: threshold, Confirmed: true}.Check(plans), "confirmed deletions must not be aborted")
+}
+
+func TestPlanDeletion_CountsExistingObjects(t *testing.T) {
+	a := api.API{ID: "some-id"}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), a).Return([]client.Value{{Id: "id1", Name: "d1"}, {Id: "id2", Name: "d2"}, {Id: "id3", Name: "d3"}}, nil)
+	c.EXPECT().ListSettings(gomock.Any(), "builtin:alerting.profile", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		var result []client.DownloadSettingsObject
+		for _, o := range []client.DownloadSettingsObject{
+			{ObjectId: "o1", ExternalId: "monaco:YnVpbHRpbjphbGVydGluZy5wcm9maWxlJGlkMQ=="},
+			{ObjectId: "o2", ExternalId: "other"},
+		} {
+			if opts.Filter(o) {
+				result = append(result, o)
+			}
+		}
+		return result, nil
+	})
+
+	plan, errs := PlanDeletion(context.TODO(), c, api.APIs{a.ID: a}, map[string][]DeletePointer{
+		a.ID:                       {{Type: a.ID, ConfigId: "d1"}},
+		"builtin:alerting.profile": {{Type: "builtin:alerting.profile", ConfigId: "id1"}},
+	})
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 2, plan.Count())
+	assert.Equal(t, 5, plan.Existing)
+}
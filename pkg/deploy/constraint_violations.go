@@ -0,0 +1,122 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// newSettingsDeployErr returns the deploy error of a settings config failing with the given error. Constraint
+// violations reported by the settings API are mapped back to the template and parameters of the config.
+func newSettingsDeployErr(c *config.Config, renderedConfig string, properties parameter.Properties, err error) configDeployErr {
+	var violations client.SettingsConstraintViolationError
+	if errors.As(err, &violations) {
+		return newConfigDeployErr(c, describeConstraintViolations(c, renderedConfig, properties, violations))
+	}
+	return newConfigDeployErr(c, err.Error())
+}
+
+// describeConstraintViolations describes the constraint violations reported by the settings API for the given config.
+// Each violated property is named with the template it is defined in, together with the parameters whose resolved
+// value ended up in the property - as these are most likely what needs to be fixed.
+func describeConstraintViolations(c *config.Config, renderedConfig string, properties parameter.Properties, e client.SettingsConstraintViolationError) string {
+	var rendered interface{}
+	_ = json.Unmarshal([]byte(renderedConfig), &rendered) // the payload was accepted as JSON, else there'd be no violations
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "settings object was rejected (HTTP %d): %s", e.StatusCode, e.Message)
+	for _, v := range e.Violations {
+		path := violationPath(v.Path)
+		_, _ = fmt.Fprintf(&b, "\n\t- property %q of template %q: %s", strings.Join(path, "/"), c.Template.Name(), v.Message)
+
+		value, found := valueAtPath(rendered, path)
+		if !found {
+			continue
+		}
+		if params := parametersWithValue(properties, value); len(params) > 0 {
+			_, _ = fmt.Fprintf(&b, " (value %q is set by parameter %s)", value, strings.Join(params, ", "))
+		}
+	}
+	return b.String()
+}
+
+// violationPath splits the path of a violated property, e.g. 'rules/0/name' or 'rules[0].name', into its segments.
+func violationPath(path string) []string {
+	path = strings.NewReplacer("[", "/", "]", "", ".", "/").Replace(path)
+
+	var segments []string
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+	return segments
+}
+
+// valueAtPath returns the scalar value found at the given path within the parsed JSON data, formatted as string.
+func valueAtPath(data interface{}, path []string) (string, bool) {
+	for _, segment := range path {
+		switch d := data.(type) {
+		case map[string]interface{}:
+			v, found := d[segment]
+			if !found {
+				return "", false
+			}
+			data = v
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(d) {
+				return "", false
+			}
+			data = d[i]
+		default:
+			return "", false
+		}
+	}
+	return scalarString(data)
+}
+
+// parametersWithValue returns the sorted names of all parameters whose resolved value equals the given value.
+func parametersWithValue(properties parameter.Properties, value string) []string {
+	var names []string
+	for name, v := range properties {
+		if s, ok := scalarString(v); ok && s == value {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func scalarString(v interface{}) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case bool, float64, int, int64:
+		return fmt.Sprint(s), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,59 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestNewSettingsDeployErr(t *testing.T) {
+	c := &config.Config{
+		Template: template.CreateTemplateFromString("profile.json", `{"name": "{{ .name }}", "rules": [{"severity": "{{ .severity }}"}]}`),
+	}
+	rendered := `{"name": "profile", "rules": [{"severity": "SEVERE", "delay": 5}]}`
+	properties := parameter.Properties{"name": "profile", "severity": "SEVERE", "alias": "SEVERE", "delay": 10}
+
+	t.Run("constraint violations are mapped to template and parameters", func(t *testing.T) {
+		err := newSettingsDeployErr(c, rendered, properties, client.SettingsConstraintViolationError{
+			StatusCode: 400,
+			Message:    "Validation failed",
+			Violations: []client.ConstraintViolation{
+				{Path: "rules/0/severity", Message: "unknown severity"},
+				{Path: "rules[0].delay", Message: "must be at least 10"},
+				{Path: "missing", Message: "must not be null"},
+			},
+		})
+
+		assert.Equal(t, err.Error(), `settings object was rejected (HTTP 400): Validation failed
+	- property "rules/0/severity" of template "profile.json": unknown severity (value "SEVERE" is set by parameter "alias", "severity")
+	- property "rules/0/delay" of template "profile.json": must be at least 10
+	- property "missing" of template "profile.json": must not be null`)
+	})
+
+	t.Run("other errors are kept", func(t *testing.T) {
+		err := newSettingsDeployErr(c, rendered, properties, errors.New("failed"))
+		assert.Equal(t, err.Error(), "failed")
+	})
+}
@@ -0,0 +1,55 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+)
+
+func isDashboard(a api.API) bool {
+	return a.ID == "dashboard"
+}
+
+// extractShareSettings removes the share settings from the given rendered dashboard payload, as they need to be
+// applied separately after the dashboard is deployed. If the payload does not define share settings, it is returned
+// as is and the returned share settings are nil.
+func extractShareSettings(renderedConfig string) (string, []byte, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(renderedConfig), &payload); err != nil {
+		return "", nil, err
+	}
+
+	shareSettings, found := payload[api.DashboardShareSettingsProperty]
+	if !found {
+		return renderedConfig, nil, nil
+	}
+
+	var settings map[string]interface{}
+	if err := json.Unmarshal(shareSettings, &settings); err != nil || settings == nil {
+		return "", nil, fmt.Errorf("'%s' of dashboard needs to be an object", api.DashboardShareSettingsProperty)
+	}
+
+	delete(payload, api.DashboardShareSettingsProperty)
+	remaining, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return string(remaining), shareSettings, nil
+}
@@ -0,0 +1,74 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDeployConfig_DashboardShareSettings(t *testing.T) {
+	newDashboard := func(payload string) config.Config {
+		return config.Config{
+			Type:       config.ClassicApiType{Api: "dashboard"},
+			Template:   template.CreateTemplateFromString("dashboard.json", payload),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "dashboard-1"},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "my dashboard"}},
+		}
+	}
+
+	t.Run("share settings are applied after the dashboard", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		gomock.InOrder(
+			c.EXPECT().UpsertConfigByName(gomock.Any(), dashboardApi, "my dashboard", []byte(`{"dashboardMetadata":{"name":"my dashboard"}}`)).
+				Return(client.DynatraceEntity{Id: "dashboard-id", Name: "my dashboard"}, nil),
+			c.EXPECT().UpdateDashboardShareSettings(gomock.Any(), "dashboard-id", []byte(`{"enabled": true}`)).Return(nil),
+		)
+
+		conf := newDashboard(`{"dashboardMetadata": {"name": "{{.name}}"}, "shareSettings": {"enabled": true}}`)
+		_, errs := deployConfig(context.TODO(), c, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("dashboards without share settings are deployed as is", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), dashboardApi, "my dashboard", []byte(`{"dashboardMetadata": {"name": "my dashboard"}}`)).
+			Return(client.DynatraceEntity{Id: "dashboard-id", Name: "my dashboard"}, nil)
+
+		conf := newDashboard(`{"dashboardMetadata": {"name": "{{.name}}"}}`)
+		_, errs := deployConfig(context.TODO(), c, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("invalid share settings are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+
+		conf := newDashboard(`{"dashboardMetadata": {"name": "{{.name}}"}, "shareSettings": true}`)
+		_, errs := deployConfig(context.TODO(), c, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "'shareSettings' of dashboard needs to be an object")
+	})
+}
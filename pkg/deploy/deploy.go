@@ -15,13 +15,19 @@
 package deploy
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/audit"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/deploy/validate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemamigration"
+	"time"
 )
 
 // DeployConfigsOptions defines additional options used by DeployConfigs
@@ -32,32 +38,141 @@ type DeployConfigsOptions struct {
 	// DryRun states that the deployment shall just run in dry-run mode, meaning
 	// that actual deployment of the configuration to a tenant will be skipped
 	DryRun bool
+	// DeploymentEvent optionally defines the type of event sent to the environment after all configs were deployed
+	// successfully. The event lists all deployed configs. No event is sent in dry-run mode or if nothing was deployed.
+	DeploymentEvent client.EventType
+	// CheckIdempotency states that each config is rendered twice before it is deployed, reporting configs whose
+	// renders differ.
+	CheckIdempotency bool
+	// ResolveSkippedReferences states that skipped configs referenced by deployed configs are looked up in the
+	// environment, instead of failing the configs referencing them. This allows skipping configs which were deployed
+	// in earlier runs, e.g. optional baseline projects.
+	ResolveSkippedReferences bool
+	// CheckSchemaVersions states that the schema versions of settings configs are compared with the versions available
+	// on the environment, warning about configs created with a different major version.
+	CheckSchemaVersions bool
+	// SchemaMigrations optionally defines how settings configs are migrated between major versions of their schema.
+	// Configs are only migrated if CheckSchemaVersions is set.
+	SchemaMigrations schemamigration.Table
+	// SettingsBatchSize is the maximum number of settings objects upserted in a single request. Consecutive settings
+	// configs of the same schema which do not reference each other are batched. Batching is disabled if it is below 2.
+	SettingsBatchSize int
+	// MaxFailures optionally defines how many configs may fail before the deployment is aborted. If it is set, the
+	// deployment continues on errors until the budget is exceeded, regardless of ContinueOnErr.
+	MaxFailures FailureBudget
+	// DeploymentTime optionally defines the time of the deployment, which timestamp parameters resolve to. If it is not
+	// set, the time the deployment starts at is used, which DeployConfigsForEnvironments shares between environments.
+	DeploymentTime time.Time
 }
 
 // DeployConfigs deploys the given configs with the given apis via the given client
 // NOTE: the given configs need to be sorted, otherwise deployment will
 // probably fail, as references cannot be resolved
-func DeployConfigs(client client.Client, apis api.APIs, sortedConfigs []config.Config, opts DeployConfigsOptions) []error {
+func DeployConfigs(ctx context.Context, client client.Client, apis api.APIs, sortedConfigs []config.Config, opts DeployConfigsOptions) []error {
 	entityMap := newEntityMap(apis)
+	lookup := newEnvironmentLookup(ctx, client, apis, opts.DryRun)
+	if opts.DeploymentTime.IsZero() {
+		opts.DeploymentTime = time.Now()
+	}
+	r := resolver{lookup: lookup, deploymentTime: opts.DeploymentTime}
 	var errors []error
+	var deployed []coordinate.Coordinate
+	positions := make(configPositions)
+
+	var versions *schemaVersions
+	if opts.CheckSchemaVersions {
+		versions = newSchemaVersions(client, opts.SchemaMigrations)
+	}
+
+	var referenced map[coordinate.Coordinate]struct{}
+	if opts.ResolveSkippedReferences {
+		referenced = referencedConfigs(sortedConfigs)
+	}
+
+	logAction, logVerb := getWordsForLogging(opts.DryRun)
+
+	failed := 0
+	total := countDeployable(sortedConfigs)
+
+	// handleResult records the result of deploying a config, returning false if the deployment has to stop
+	handleResult := func(c *config.Config, entity parameter.ResolvedEntity, deploymentErrors []error) bool {
+		if deploymentErrors != nil {
+			for _, err := range deploymentErrors {
+				errors = append(errors, fmt.Errorf("failed to %s config %s: %w", logVerb, c.Coordinate, err))
+			}
+
+			failed++
+			if opts.MaxFailures.IsSet() && !opts.DryRun {
+				if opts.MaxFailures.exceeded(failed, total) {
+					errors = append(errors, fmt.Errorf("aborted deployment: %d out of %d configs failed, exceeding the maximum of %s failures", failed, total, opts.MaxFailures))
+					return false
+				}
+			} else if !opts.ContinueOnErr && !opts.DryRun {
+				return false
+			}
+		} else {
+			deployed = append(deployed, c.Coordinate)
+
+			if t, ok := c.Type.(config.ClassicApiType); ok && apis[t.Api].Ordered && c.Position > 0 {
+				positions.add(t.Api, fmt.Sprint(entity.Properties[config.IdParameter]), c.Position)
+			}
+		}
+		entityMap.put(entity.Coordinate, entity)
+		return true
+	}
+
+	var batch settingsBatch
+
+	// upsertBatch upserts the pending batch of settings, returning false if the deployment has to stop
+	upsertBatch := func() bool {
+		for _, r := range batch.upsert(ctx, client) {
+			if !handleResult(r.conf, r.entity, r.errs) {
+				return false
+			}
+		}
+		return true
+	}
 
 	for _, c := range sortedConfigs {
 		c := c // to avoid implicit memory aliasing (gosec G601)
 
+		if err := ctx.Err(); err != nil {
+			return append(errors, fmt.Errorf("deployment cancelled before config %s: %w", c.Coordinate, err))
+		}
+
+		configCtx := log.WithFields(audit.WithCoordinate(ctx, c.Coordinate), log.CoordinateField(c.Coordinate))
+
 		if c.Skip {
-			log.Info("\tSkipping deployment of config %s", c.Coordinate)
-
-			entityMap.put(c.Coordinate, parameter.ResolvedEntity{
-				EntityName: c.Coordinate.ConfigId,
-				Coordinate: c.Coordinate,
-				Properties: parameter.Properties{},
-				Skip:       true,
-			})
+			log.WithCtxFields(configCtx).Info("\tSkipping deployment of config %s", c.Coordinate)
+
+			if _, isReferenced := referenced[c.Coordinate]; !isReferenced {
+				entityMap.put(c.Coordinate, skippedConfigEntity(&c))
+				continue
+			}
+
+			entity, err := resolveSkippedConfigRemotely(configCtx, client, apis, entityMap, lookup, r, &c)
+			if err != nil {
+				log.WithCtxFields(configCtx).Warn("\tFailed to look up skipped config %s in the environment, configs referencing it will fail: %v", c.Coordinate, err)
+				entity = skippedConfigEntity(&c)
+			} else {
+				log.WithCtxFields(configCtx).Debug("\tResolved skipped config %s to existing object %v", c.Coordinate, entity.Properties[config.IdParameter])
+			}
+			entityMap.put(c.Coordinate, entity)
 			continue
 		}
 
-		logAction, logVerb := getWordsForLogging(opts.DryRun)
-		log.Info("\t%s config %s", logAction, c.Coordinate)
+		// configs following a batch might reference its settings, thus the batch is upserted before they are resolved
+		if batch.len() > 0 && !batch.accepts(&c, opts.SettingsBatchSize) && !upsertBatch() {
+			return errors
+		}
+
+		log.WithCtxFields(configCtx).Info("\t%s config %s", logAction, c.Coordinate)
+
+		if _, isEntity := c.Type.(config.EntityType); opts.CheckIdempotency && !isEntity {
+			if err := checkIdempotentRendering(&c, entityMap.get(), r); err != nil {
+				errors = append(errors, err)
+			}
+		}
 
 		var entity parameter.ResolvedEntity
 		var deploymentErrors []error
@@ -65,35 +180,74 @@ func DeployConfigs(client client.Client, apis api.APIs, sortedConfigs []config.C
 		switch t := c.Type.(type) {
 
 		case config.EntityType:
-			log.Debug("Entity are not deployable, skipping entity type: %s", t.EntitiesType)
+			log.WithCtxFields(configCtx).Debug("Entity are not deployable, skipping entity type: %s", t.EntitiesType)
 			continue
 
 		case config.SettingsType:
-			entity, deploymentErrors = deploySetting(client, entityMap, &c)
+			if opts.SettingsBatchSize < 2 {
+				entity, deploymentErrors = deploySetting(configCtx, client, entityMap, r, versions, &c)
+				break
+			}
+
+			s, errs := prepareSetting(configCtx, entityMap, r, versions, &c)
+			if len(errs) > 0 {
+				// the batch holds configs sorted before this one, so they are upserted before its failure is handled
+				if batch.len() > 0 && !upsertBatch() {
+					return errors
+				}
+				deploymentErrors = errs
+				break
+			}
+			batch.add(s)
+			continue
 
 		case config.ClassicApiType:
-			entity, deploymentErrors = deployConfig(client, apis, entityMap, &c)
+			entity, deploymentErrors = deployConfig(configCtx, client, apis, entityMap, r, &c)
+
+		case config.ExtensionType:
+			entity, deploymentErrors = deployExtension(configCtx, client, entityMap, r, &c)
+
+		case config.ExtensionMonitoringType:
+			entity, deploymentErrors = deployExtensionMonitoring(configCtx, client, entityMap, r, &c)
 
 		default:
 			errors = append(errors, fmt.Errorf("unknown config-type (ID: %q)", c.Type.ID()))
 			continue
 		}
 
-		if deploymentErrors != nil {
-			for _, err := range deploymentErrors {
-				errors = append(errors, fmt.Errorf("failed to %s config %s: %w", logVerb, c.Coordinate, err))
-			}
+		if !handleResult(&c, entity, deploymentErrors) {
+			return errors
+		}
+	}
 
-			if !opts.ContinueOnErr && !opts.DryRun {
-				return errors
-			}
+	if batch.len() > 0 && !upsertBatch() {
+		return errors
+	}
+
+	if !opts.DryRun && len(positions) > 0 {
+		errors = append(errors, reorderConfigs(ctx, client, apis, positions)...)
+	}
+
+	if len(errors) == 0 && !opts.DryRun && opts.DeploymentEvent != "" && len(deployed) > 0 {
+		if err := sendDeploymentEvent(ctx, client, opts.DeploymentEvent, deployed); err != nil {
+			errors = append(errors, err)
 		}
-		entityMap.put(entity.Coordinate, entity)
 	}
 
 	return errors
 }
 
+// countDeployable returns the number of configs which are actually deployed, i.e. neither skipped nor entities.
+func countDeployable(configs []config.Config) int {
+	n := 0
+	for _, c := range configs {
+		if _, isEntity := c.Type.(config.EntityType); !c.Skip && !isEntity {
+			n++
+		}
+	}
+	return n
+}
+
 // getWordsForLogging returns fitting action and verb words to clearly tell a user if configuration is
 // deployed or validated when logging based on the dry-run boolean
 func getWordsForLogging(isDryRun bool) (action, verb string) {
@@ -103,7 +257,7 @@ func getWordsForLogging(isDryRun bool) (action, verb string) {
 	return "Deploying", "deploy"
 }
 
-func deployConfig(configClient client.ConfigClient, apis api.APIs, entityMap *entityMap, conf *config.Config) (parameter.ResolvedEntity, []error) {
+func deployConfig(ctx context.Context, configClient client.Client, apis api.APIs, entityMap *entityMap, r resolver, conf *config.Config) (parameter.ResolvedEntity, []error) {
 
 	t, ok := conf.Type.(config.ClassicApiType)
 	if !ok {
@@ -115,15 +269,24 @@ func deployConfig(configClient client.ConfigClient, apis api.APIs, entityMap *en
 		return parameter.ResolvedEntity{}, []error{fmt.Errorf("unknown api `%s`. this is most likely a bug", t.Api)}
 	}
 
-	properties, errors := resolveProperties(conf, entityMap.get())
+	properties, errors := resolveProperties(conf, entityMap.get(), r)
 	if len(errors) > 0 {
 		return parameter.ResolvedEntity{}, errors
 	}
 
+	if apiToDeploy.HasParent() {
+		parentObjectId, err := extractScope(properties)
+		if err != nil {
+			return parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, fmt.Sprintf("configs of API %q require the ID of their parent %q as scope: %s", apiToDeploy.ID, apiToDeploy.Parent, err))}
+		}
+		apiToDeploy = apiToDeploy.ApplyParentObjectID(parentObjectId)
+	}
+
 	configName, err := extractConfigName(conf, properties)
 	if err != nil {
 		errors = append(errors, err)
-	} else if entityMap.contains(apiToDeploy.ID, configName) && !apiToDeploy.NonUniqueName {
+	} else if entityMap.contains(apiToDeploy.ID, configName) && !apiToDeploy.NonUniqueName && !apiToDeploy.HasParent() {
+		// names of parent-scoped configs are only unique per parent object, thus duplicates are not detected for them
 		errors = append(errors, newConfigDeployErr(conf, fmt.Sprintf("duplicated config name `%s`", configName)))
 	}
 	if len(errors) > 0 {
@@ -135,21 +298,41 @@ func deployConfig(configClient client.ConfigClient, apis api.APIs, entityMap *en
 		return parameter.ResolvedEntity{}, []error{err}
 	}
 
+	var shareSettings []byte
+	if isDashboard(apiToDeploy) {
+		if renderedConfig, shareSettings, err = extractShareSettings(renderedConfig); err != nil {
+			return parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
+		}
+	}
+
+	for _, violation := range validate.ClassicConfig(apiToDeploy, configName, []byte(renderedConfig)) {
+		errors = append(errors, newConfigDeployErr(conf, violation.Error()))
+	}
+	if len(errors) > 0 {
+		return parameter.ResolvedEntity{}, errors
+	}
+
 	if apiToDeploy.DeprecatedBy != "" {
-		log.Warn("API for \"%s\" is deprecated! Please consider migrating to \"%s\"!", apiToDeploy.ID, apiToDeploy.DeprecatedBy)
+		log.WithCtxFields(ctx).Warn("API for \"%s\" is deprecated! Please consider migrating to \"%s\"!", apiToDeploy.ID, apiToDeploy.DeprecatedBy)
 	}
 
 	var entity client.DynatraceEntity
 	if apiToDeploy.NonUniqueName {
-		entity, err = upsertNonUniqueNameConfig(configClient, apiToDeploy, conf, configName, renderedConfig)
+		entity, err = upsertNonUniqueNameConfig(ctx, configClient, apiToDeploy, conf, configName, renderedConfig)
 	} else {
-		entity, err = configClient.UpsertConfigByName(apiToDeploy, configName, []byte(renderedConfig))
+		entity, err = configClient.UpsertConfigByName(ctx, apiToDeploy, configName, []byte(renderedConfig))
 	}
 
 	if err != nil {
 		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
 	}
 
+	if shareSettings != nil {
+		if err := configClient.UpdateDashboardShareSettings(ctx, entity.Id, shareSettings); err != nil {
+			return parameter.ResolvedEntity{}, []error{newConfigDeployErr(conf, err.Error())}
+		}
+	}
+
 	properties[config.IdParameter] = entity.Id
 	properties[config.NameParameter] = entity.Name
 
@@ -161,9 +344,11 @@ func deployConfig(configClient client.ConfigClient, apis api.APIs, entityMap *en
 	}, nil
 }
 
-func upsertNonUniqueNameConfig(client client.ConfigClient, apiToDeploy api.API, conf *config.Config, configName string, renderedConfig string) (client.DynatraceEntity, error) {
-	configId := conf.Coordinate.ConfigId
-	projectId := conf.Coordinate.Project
+func upsertNonUniqueNameConfig(ctx context.Context, client client.ConfigClient, apiToDeploy api.API, conf *config.Config, configName string, renderedConfig string) (client.DynatraceEntity, error) {
+	// objects of moved configs are identified by their original coordinate
+	origin := conf.OriginCoordinate()
+	configId := origin.ConfigId
+	projectId := origin.Project
 
 	entityUuid := configId
 
@@ -172,59 +357,90 @@ func upsertNonUniqueNameConfig(client client.ConfigClient, apiToDeploy api.API,
 		entityUuid = idutils.GenerateUuidFromConfigId(projectId, configId)
 	}
 
-	return client.UpsertConfigByNonUniqueNameAndId(apiToDeploy, entityUuid, configName, []byte(renderedConfig))
+	return client.UpsertConfigByNonUniqueNameAndId(ctx, apiToDeploy, entityUuid, configName, []byte(renderedConfig))
+}
+
+func deploySetting(ctx context.Context, settingsClient client.SettingsClient, entityMap *entityMap, r resolver, versions *schemaVersions, c *config.Config) (parameter.ResolvedEntity, []error) {
+	s, errors := prepareSetting(ctx, entityMap, r, versions, c)
+	if len(errors) > 0 {
+		return parameter.ResolvedEntity{}, errors
+	}
+
+	entity, err := settingsClient.UpsertSettings(ctx, s.object)
+	return s.result(entity, err)
+}
+
+// preparedSetting is a settings config whose properties are resolved and which is rendered, ready to be upserted.
+type preparedSetting struct {
+	ctx        context.Context
+	conf       *config.Config
+	properties parameter.Properties
+	object     client.SettingsObject
 }
 
-func deploySetting(settingsClient client.SettingsClient, entityMap *entityMap, c *config.Config) (parameter.ResolvedEntity, []error) {
+func prepareSetting(ctx context.Context, entityMap *entityMap, r resolver, versions *schemaVersions, c *config.Config) (preparedSetting, []error) {
 	t, ok := c.Type.(config.SettingsType)
 	if !ok {
-		return parameter.ResolvedEntity{}, []error{fmt.Errorf("config was not of expected type %q, but %q", config.SettingsTypeId, c.Type.ID())}
+		return preparedSetting{}, []error{fmt.Errorf("config was not of expected type %q, but %q", config.SettingsTypeId, c.Type.ID())}
 	}
 
-	properties, errors := resolveProperties(c, entityMap.get())
+	properties, errors := resolveProperties(c, entityMap.get(), r)
 	if len(errors) > 0 {
-		return parameter.ResolvedEntity{}, errors
+		return preparedSetting{}, errors
 	}
 
 	scope, err := extractScope(properties)
 	if err != nil {
-		return parameter.ResolvedEntity{}, []error{err}
+		return preparedSetting{}, []error{err}
 	}
 
 	renderedConfig, err := c.Render(properties)
 	if err != nil {
-		return parameter.ResolvedEntity{}, []error{err}
+		return preparedSetting{}, []error{err}
 	}
 
-	entity, err := settingsClient.UpsertSettings(client.SettingsObject{
-		Id:             c.Coordinate.ConfigId,
-		SchemaId:       t.SchemaId,
-		SchemaVersion:  t.SchemaVersion,
-		Scope:          scope,
-		Content:        []byte(renderedConfig),
-		OriginObjectId: c.OriginObjectId,
-	})
+	schemaVersion := t.SchemaVersion
+	if versions != nil {
+		renderedConfig, schemaVersion = versions.check(ctx, c, t, renderedConfig)
+	}
+
+	return preparedSetting{
+		ctx:        ctx,
+		conf:       c,
+		properties: properties,
+		object: client.SettingsObject{
+			Id:             c.SettingsObjectId(),
+			SchemaId:       t.SchemaId,
+			SchemaVersion:  schemaVersion,
+			Scope:          scope,
+			Content:        []byte(renderedConfig),
+			OriginObjectId: c.OriginObjectId,
+		},
+	}, nil
+}
+
+// result returns the resolved entity of the setting, given the result of upserting it.
+func (s preparedSetting) result(entity client.DynatraceEntity, err error) (parameter.ResolvedEntity, []error) {
 	if err != nil {
-		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+		return parameter.ResolvedEntity{}, []error{newSettingsDeployErr(s.conf, string(s.object.Content), s.properties, err)}
 	}
 
 	name := fmt.Sprintf("[UNKNOWN NAME]%s", entity.Id)
-	if configName, err := extractConfigName(c, properties); err == nil {
+	if configName, err := extractConfigName(s.conf, s.properties); err == nil {
 		name = configName
 	} else {
-		log.Warn("failed to extract name for Settings 2.0 object %q - ID will be used", entity.Id)
+		log.WithCtxFields(s.ctx).Warn("failed to extract name for Settings 2.0 object %q - ID will be used", entity.Id)
 	}
 
-	properties[config.IdParameter] = entity.Id
-	properties[config.NameParameter] = name
+	s.properties[config.IdParameter] = entity.Id
+	s.properties[config.NameParameter] = name
 
 	return parameter.ResolvedEntity{
 		EntityName: name,
-		Coordinate: c.Coordinate,
-		Properties: properties,
+		Coordinate: s.conf.Coordinate,
+		Properties: s.properties,
 		Skip:       false,
 	}, nil
-
 }
 
 func extractScope(properties parameter.Properties) (string, error) {
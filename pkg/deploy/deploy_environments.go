@@ -0,0 +1,69 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"time"
+)
+
+// EnvironmentClients maps environment names to the client used to deploy to that environment
+type EnvironmentClients map[string]client.Client
+
+// DeployConfigsForEnvironments deploys the sorted configs of each environment via the client registered
+// for that environment in the given EnvironmentClients.
+// This is the main entry point for tools embedding monaco: configs can be loaded using project.LoadProjects
+// and sorted using topologysort.GetSortedConfigsForEnvironments before being passed to this function.
+//
+// If no client is known for an environment, an error is reported for it and - unless ContinueOnErr or MaxFailures is
+// set - no further environments are deployed.
+func DeployConfigsForEnvironments(ctx context.Context, sortedConfigs project.ConfigsPerEnvironment, clients EnvironmentClients, apis api.APIs, opts DeployConfigsOptions) []error {
+	var errs []error
+
+	// all environments are deployed at the same time, as far as timestamp parameters are concerned
+	if opts.DeploymentTime.IsZero() {
+		opts.DeploymentTime = time.Now()
+	}
+
+	for envName, configs := range sortedConfigs {
+		c, found := clients[envName]
+		if !found {
+			errs = append(errs, fmt.Errorf("no client defined for environment `%s`", envName))
+			if !opts.ContinueOnErr && !opts.MaxFailures.IsSet() {
+				return errs
+			}
+			continue
+		}
+
+		logDeploymentInfo(opts.DryRun, envName)
+
+		errs = append(errs, DeployConfigs(log.WithFields(ctx, log.EnvironmentField(envName)), c, apis, configs, opts)...)
+	}
+
+	return errs
+}
+
+func logDeploymentInfo(dryRun bool, envName string) {
+	if dryRun {
+		log.Info("Validating configurations for environment `%s`...", envName)
+	} else {
+		log.Info("Deploying configurations to environment `%s`...", envName)
+	}
+}
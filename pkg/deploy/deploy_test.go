@@ -17,6 +17,7 @@
 package deploy
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
@@ -29,6 +30,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
 	"github.com/google/uuid"
 	"gotest.tools/assert"
@@ -78,7 +80,7 @@ func TestDeployConfig(t *testing.T) {
 		Skip:        false,
 	}
 
-	resolvedEntity, errors := deployConfig(client, testApiMap, newEntityMap(testApiMap), &conf)
+	resolvedEntity, errors := deployConfig(context.TODO(), client, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
 
 	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
 	assert.Equal(t, name, resolvedEntity.EntityName, "%s == %s")
@@ -89,6 +91,48 @@ func TestDeployConfig(t *testing.T) {
 	assert.Equal(t, false, resolvedEntity.Skip)
 }
 
+func TestDeployConfigOfParentScopedApi(t *testing.T) {
+	keyUserActionsApi := api.API{ID: "key-user-actions-web", URLPath: "/applications/" + api.ParentObjectIdPlaceholder + "/keyUserActions", Parent: "application-web"}
+	apis := api.APIs{keyUserActionsApi.ID: keyUserActionsApi}
+
+	newConfig := func(params []topologysort.ParameterWithName) config.Config {
+		return config.Config{
+			Type:       config.ClassicApiType{Api: keyUserActionsApi.ID},
+			Template:   generateDummyTemplate(t),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: keyUserActionsApi.ID, ConfigId: "action"},
+			Parameters: toParameterMap(params),
+		}
+	}
+
+	t.Run("parent object ID is applied", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "action name", gomock.Any()).DoAndReturn(func(_ context.Context, a api.API, name string, _ []byte) (client.DynatraceEntity, error) {
+			assert.Equal(t, a.URLPath, "/applications/APPLICATION-1/keyUserActions")
+			return client.DynatraceEntity{Id: "ACTION-1", Name: name}, nil
+		})
+
+		conf := newConfig([]topologysort.ParameterWithName{
+			{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "action name"}},
+			{Name: config.ScopeParameter, Parameter: &parameter.DummyParameter{Value: "APPLICATION-1"}},
+		})
+
+		_, errors := deployConfig(context.TODO(), c, apis, newEntityMap(apis), resolver{}, &conf)
+		assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
+	})
+
+	t.Run("fails without scope", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+
+		conf := newConfig([]topologysort.ParameterWithName{
+			{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "action name"}},
+		})
+
+		_, errors := deployConfig(context.TODO(), c, apis, newEntityMap(apis), resolver{}, &conf)
+		assert.Assert(t, len(errors) == 1)
+		assert.ErrorContains(t, errors[0], `require the ID of their parent "application-web" as scope`)
+	})
+}
+
 func TestDeploySettingShouldFailCyclicParameterDependencies(t *testing.T) {
 	ownerParameterName := "owner"
 	configCoordinates := coordinate.Coordinate{}
@@ -125,7 +169,7 @@ func TestDeploySettingShouldFailCyclicParameterDependencies(t *testing.T) {
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	_, errors := deploySetting(client, newEntityMap(testApiMap), conf)
+	_, errors := deploySetting(context.TODO(), client, newEntityMap(testApiMap), resolver{}, nil, conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -137,7 +181,7 @@ func TestDeploySettingShouldFailRenderTemplate(t *testing.T) {
 		Template: generateFaultyTemplate(t),
 	}
 
-	_, errors := deploySetting(client, newEntityMap(testApiMap), conf)
+	_, errors := deploySetting(context.TODO(), client, newEntityMap(testApiMap), resolver{}, nil, conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -167,14 +211,14 @@ func TestDeploySettingShouldFailUpsert(t *testing.T) {
 	}
 
 	c := client.NewMockSettingsClient(gomock.NewController(t))
-	c.EXPECT().UpsertSettings(gomock.Any()).Return(client.DynatraceEntity{}, fmt.Errorf("upsert failed"))
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Return(client.DynatraceEntity{}, fmt.Errorf("upsert failed"))
 
 	conf := &config.Config{
 		Type:       config.SettingsType{},
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	_, errors := deploySetting(c, newEntityMap(testApiMap), conf)
+	_, errors := deploySetting(context.TODO(), c, newEntityMap(testApiMap), resolver{}, nil, conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -201,7 +245,7 @@ func TestDeploySetting(t *testing.T) {
 	}
 
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().UpsertSettings(gomock.Any()).Times(1).Return(client.DynatraceEntity{
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Times(1).Return(client.DynatraceEntity{
 		Id:   "vu9U3hXa3q0AAAABABlidWlsdGluOMmE1NGMxvu9U3hXa3q0",
 		Name: "vu9U3hXa3q0AAAABABlidWlsdGluOMmE1NGMxvu9U3hXa3q0",
 	}, nil)
@@ -211,7 +255,28 @@ func TestDeploySetting(t *testing.T) {
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	_, errors := deploySetting(c, newEntityMap(testApiMap), conf)
+	_, errors := deploySetting(context.TODO(), c, newEntityMap(testApiMap), resolver{}, nil, conf)
+	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
+}
+
+func TestDeploySettingOfMovedConfigUsesOriginalId(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+		assert.Equal(t, obj.Id, "old-id")
+		return client.DynatraceEntity{Id: "objectId"}, nil
+	})
+
+	conf := &config.Config{
+		Coordinate: coordinate.Coordinate{Project: "new-project", Type: "builtin:test", ConfigId: "new-id"},
+		MovedFrom:  coordinate.Coordinate{Project: "old-project", Type: "builtin:test", ConfigId: "old-id"},
+		Type:       config.SettingsType{SchemaId: "builtin:test"},
+		Template:   generateDummyTemplate(t),
+		Parameters: toParameterMap([]topologysort.ParameterWithName{
+			{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "name"}},
+			{Name: config.ScopeParameter, Parameter: &parameter.DummyParameter{Value: "tenant"}},
+		}),
+	}
+	_, errors := deploySetting(context.TODO(), c, newEntityMap(testApiMap), resolver{}, nil, conf)
 	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
 }
 
@@ -246,7 +311,7 @@ func TestDeployedSettingGetsNameFromConfig(t *testing.T) {
 	}
 
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().UpsertSettings(gomock.Any()).Times(1).Return(client.DynatraceEntity{
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Times(1).Return(client.DynatraceEntity{
 		Id:   "vu9U3hXa3q0AAAABABlidWlsdGluOMmE1NGMxvu9U3hXa3q0",
 		Name: "vu9U3hXa3q0AAAABABlidWlsdGluOMmE1NGMxvu9U3hXa3q0",
 	}, nil)
@@ -256,7 +321,7 @@ func TestDeployedSettingGetsNameFromConfig(t *testing.T) {
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parameters),
 	}
-	res, errors := deploySetting(c, newEntityMap(testApiMap), conf)
+	res, errors := deploySetting(context.TODO(), c, newEntityMap(testApiMap), resolver{}, nil, conf)
 	assert.Equal(t, res.EntityName, cfgName, "expected resolved name to match configuration name")
 	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
 }
@@ -286,7 +351,7 @@ func TestSettingsNameExtractionDoesNotFailIfCfgNameBecomesOptional(t *testing.T)
 	objectId := "vu9U3hXa3q0AAAABABlidWlsdGluOMmE1NGMxvu9U3hXa3q0"
 
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().UpsertSettings(gomock.Any()).Times(1).Return(client.DynatraceEntity{
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Times(1).Return(client.DynatraceEntity{
 		Id:   objectId,
 		Name: objectId,
 	}, nil)
@@ -296,7 +361,7 @@ func TestSettingsNameExtractionDoesNotFailIfCfgNameBecomesOptional(t *testing.T)
 		Template:   generateDummyTemplate(t),
 		Parameters: toParameterMap(parametersWithoutName),
 	}
-	res, errors := deploySetting(c, newEntityMap(testApiMap), conf)
+	res, errors := deploySetting(context.TODO(), c, newEntityMap(testApiMap), resolver{}, nil, conf)
 	assert.Assert(t, strings.Contains(res.EntityName, objectId), "expected resolved name to contain objectID if name is not configured")
 	assert.Assert(t, len(errors) == 0, "there should be no errors (no errors: %d, %s)", len(errors), errors)
 }
@@ -327,7 +392,7 @@ func TestDeployConfigShouldFailOnAnAlreadyKnownEntityName(t *testing.T) {
 	}
 	entityMap := newEntityMap(testApiMap)
 	entityMap.put(coordinate.Coordinate{Type: "dashboard"}, parameter.ResolvedEntity{EntityName: name})
-	_, errors := deployConfig(client, testApiMap, entityMap, &conf)
+	_, errors := deployConfig(context.TODO(), client, testApiMap, entityMap, resolver{}, &conf)
 
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
@@ -379,7 +444,7 @@ func TestDeployConfigShouldFailCyclicParameterDependencies(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, newEntityMap(testApiMap), &conf)
+	_, errors := deployConfig(context.TODO(), client, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -400,7 +465,7 @@ func TestDeployConfigShouldFailOnMissingNameParameter(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, newEntityMap(testApiMap), &conf)
+	_, errors := deployConfig(context.TODO(), client, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -437,7 +502,7 @@ func TestDeployConfigShouldFailOnReferenceOnUnknownConfig(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, newEntityMap(testApiMap), &conf)
+	_, errors := deployConfig(context.TODO(), client, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -476,7 +541,7 @@ func TestDeployConfigShouldFailOnReferenceOnSkipConfig(t *testing.T) {
 		Skip:        false,
 	}
 
-	_, errors := deployConfig(client, testApiMap, newEntityMap(testApiMap), &conf)
+	_, errors := deployConfig(context.TODO(), client, testApiMap, newEntityMap(testApiMap), resolver{}, &conf)
 	assert.Assert(t, len(errors) > 0, "there should be errors (no errors: %d)", len(errors))
 }
 
@@ -485,7 +550,7 @@ func TestDeployConfigsWithNoConfigs(t *testing.T) {
 	var apis api.APIs
 	var sortedConfigs []config.Config
 
-	errors := DeployConfigs(client, apis, sortedConfigs, DeployConfigsOptions{})
+	errors := DeployConfigs(context.TODO(), client, apis, sortedConfigs, DeployConfigsOptions{})
 	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
 }
 
@@ -495,7 +560,7 @@ func TestDeployConfigsWithOneConfigToSkip(t *testing.T) {
 	sortedConfigs := []config.Config{
 		{Skip: true},
 	}
-	errors := DeployConfigs(client, apis, sortedConfigs, DeployConfigsOptions{})
+	errors := DeployConfigs(context.TODO(), client, apis, sortedConfigs, DeployConfigsOptions{})
 	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
 }
 
@@ -520,11 +585,11 @@ func TestDeployConfigsTargetingSettings(t *testing.T) {
 		},
 	}
 	//client.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Times(1).Return([]rest.DownloadSettingsObject{{ExternalId: "externalId"}}, nil)
-	c.EXPECT().UpsertSettings(gomock.Any()).Times(1).Return(client.DynatraceEntity{
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Times(1).Return(client.DynatraceEntity{
 		Id:   "42",
 		Name: "Super Special Settings Object",
 	}, nil)
-	errors := DeployConfigs(c, apis, sortedConfigs, DeployConfigsOptions{})
+	errors := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{})
 	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
 }
 
@@ -535,7 +600,7 @@ func TestDeployConfigsTargetingClassicConfigUnique(t *testing.T) {
 	theApi := api.API{ID: theApiName, URLPath: "path"}
 
 	client := client.NewMockClient(gomock.NewController(t))
-	client.EXPECT().UpsertConfigByName(gomock.Any(), theConfigName, gomock.Any()).Times(1)
+	client.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), theConfigName, gomock.Any()).Times(1)
 
 	apis := api.APIs{theApiName: theApi}
 	parameters := []topologysort.ParameterWithName{
@@ -557,7 +622,7 @@ func TestDeployConfigsTargetingClassicConfigUnique(t *testing.T) {
 		},
 	}
 
-	errors := DeployConfigs(client, apis, sortedConfigs, DeployConfigsOptions{})
+	errors := DeployConfigs(context.TODO(), client, apis, sortedConfigs, DeployConfigsOptions{})
 	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
 }
 
@@ -568,7 +633,7 @@ func TestDeployConfigsTargetingClassicConfigNonUniqueWithExistingCfgsOfSameName(
 	theApi := api.API{ID: theApiName, URLPath: "path", NonUniqueName: true}
 
 	client := client.NewMockClient(gomock.NewController(t))
-	client.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), gomock.Any(), theConfigName, gomock.Any())
+	client.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), gomock.Any(), gomock.Any(), theConfigName, gomock.Any())
 
 	apis := api.APIs{theApiName: theApi}
 	parameters := []topologysort.ParameterWithName{
@@ -590,7 +655,7 @@ func TestDeployConfigsTargetingClassicConfigNonUniqueWithExistingCfgsOfSameName(
 		},
 	}
 
-	errors := DeployConfigs(client, apis, sortedConfigs, DeployConfigsOptions{})
+	errors := DeployConfigs(context.TODO(), client, apis, sortedConfigs, DeployConfigsOptions{})
 	assert.Assert(t, len(errors) == 0, "there should be no errors (errors: %s)", errors)
 }
 
@@ -629,12 +694,12 @@ func TestDeployConfigsNoApi(t *testing.T) {
 	}
 
 	t.Run("missing api - continue on error", func(t *testing.T) {
-		errors := DeployConfigs(client, apis, sortedConfigs, DeployConfigsOptions{ContinueOnErr: true})
+		errors := DeployConfigs(context.TODO(), client, apis, sortedConfigs, DeployConfigsOptions{ContinueOnErr: true})
 		assert.Equal(t, 2, len(errors), fmt.Sprintf("Expected 2 errors, but just got %d", len(errors)))
 	})
 
 	t.Run("missing api - stop on error", func(t *testing.T) {
-		errors := DeployConfigs(client, apis, sortedConfigs, DeployConfigsOptions{})
+		errors := DeployConfigs(context.TODO(), client, apis, sortedConfigs, DeployConfigsOptions{})
 		assert.Equal(t, 1, len(errors), fmt.Sprintf("Expected 1 error, but just got %d", len(errors)))
 	})
 	// test continue on error
@@ -665,17 +730,61 @@ func TestDeployConfigsWithDeploymentErrors(t *testing.T) {
 	}
 
 	t.Run("deployment error - stop on error", func(t *testing.T) {
-		errors := DeployConfigs(&client.DummyClient{}, apis, sortedConfigs, DeployConfigsOptions{})
+		errors := DeployConfigs(context.TODO(), &client.DummyClient{}, apis, sortedConfigs, DeployConfigsOptions{})
 		assert.Equal(t, 1, len(errors), fmt.Sprintf("Expected 1 error, but just got %d", len(errors)))
 	})
 
 	t.Run("deployment error - stop on error", func(t *testing.T) {
-		errors := DeployConfigs(&client.DummyClient{}, apis, sortedConfigs, DeployConfigsOptions{ContinueOnErr: true})
+		errors := DeployConfigs(context.TODO(), &client.DummyClient{}, apis, sortedConfigs, DeployConfigsOptions{ContinueOnErr: true})
 		assert.Equal(t, 2, len(errors), fmt.Sprintf("Expected 1 error, but just got %d", len(errors)))
 	})
 
 }
 
+func TestDeployConfigsForEnvironments(t *testing.T) {
+	theApiName := "theApiName"
+	apis := api.APIs{theApiName: api.API{ID: theApiName, URLPath: "path"}}
+	configs := func() []config.Config {
+		return []config.Config{
+			{
+				Parameters: toParameterMap([]topologysort.ParameterWithName{}), // missing name parameter leads to deployment failure
+				Coordinate: coordinate.Coordinate{Type: theApiName},
+				Template:   generateDummyTemplate(t),
+				Type: config.ClassicApiType{
+					Api: theApiName,
+				},
+			},
+		}
+	}
+
+	t.Run("deploys all environments", func(t *testing.T) {
+		errors := DeployConfigsForEnvironments(context.TODO(),
+			project.ConfigsPerEnvironment{"env1": configs(), "env2": configs()},
+			EnvironmentClients{"env1": &client.DummyClient{}, "env2": &client.DummyClient{}},
+			apis,
+			DeployConfigsOptions{})
+		assert.Equal(t, 2, len(errors), fmt.Sprintf("Expected 2 errors, but just got %d", len(errors)))
+	})
+
+	t.Run("missing client - stop on error", func(t *testing.T) {
+		errors := DeployConfigsForEnvironments(context.TODO(),
+			project.ConfigsPerEnvironment{"env1": configs()},
+			EnvironmentClients{},
+			apis,
+			DeployConfigsOptions{})
+		assert.Equal(t, 1, len(errors), fmt.Sprintf("Expected 1 error, but just got %d", len(errors)))
+	})
+
+	t.Run("missing client - continue on error", func(t *testing.T) {
+		errors := DeployConfigsForEnvironments(context.TODO(),
+			project.ConfigsPerEnvironment{"env1": configs(), "env2": configs()},
+			EnvironmentClients{"env2": &client.DummyClient{}},
+			apis,
+			DeployConfigsOptions{ContinueOnErr: true})
+		assert.Equal(t, 2, len(errors), fmt.Sprintf("Expected 2 errors, but just got %d", len(errors)))
+	})
+}
+
 func toParameterMap(params []topologysort.ParameterWithName) map[string]parameter.Parameter {
 	result := make(map[string]parameter.Parameter)
 
@@ -699,3 +808,78 @@ func generateFaultyTemplate(t *testing.T) template.Template {
 	templ := template.CreateTemplateFromString("deploy_test-"+uuid.String(), "{")
 	return templ
 }
+
+func TestDeployConfigsSendsDeploymentEvent(t *testing.T) {
+	theApi := api.API{ID: "theApi", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+	sortedConfigs := []config.Config{
+		{
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "name"}},
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "config"},
+			Template:   generateDummyTemplate(t),
+			Type:       config.ClassicApiType{Api: theApi.ID},
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "skipped"},
+			Skip:       true,
+		},
+	}
+
+	t.Run("event is sent after successful deployment", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), theApi, "name", gomock.Any()).Return(client.DynatraceEntity{Id: "id", Name: "name"}, nil)
+		c.EXPECT().SendEvent(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e client.Event) error {
+			assert.Equal(t, client.CustomDeploymentEvent, e.Type)
+			assert.Equal(t, "1", e.Properties["configs.deployed.count"])
+			assert.Equal(t, "project:theApi:config", e.Properties["configs.deployed"])
+			assert.Equal(t, "project", e.Properties["dt.event.deployment.project"])
+			return nil
+		})
+
+		errs := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{DeploymentEvent: client.CustomDeploymentEvent})
+		assert.Assert(t, len(errs) == 0, "there should be no errors (errors: %s)", errs)
+	})
+
+	t.Run("no event is sent if deployment fails", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), theApi, "name", gomock.Any()).Return(client.DynatraceEntity{}, fmt.Errorf("failed"))
+		c.EXPECT().SendEvent(gomock.Any(), gomock.Any()).Times(0)
+
+		errs := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{DeploymentEvent: client.CustomDeploymentEvent, ContinueOnErr: true})
+		assert.Assert(t, len(errs) == 1)
+	})
+
+	t.Run("failing to send the event is reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), theApi, "name", gomock.Any()).Return(client.DynatraceEntity{Id: "id", Name: "name"}, nil)
+		c.EXPECT().SendEvent(gomock.Any(), gomock.Any()).Return(fmt.Errorf("forbidden"))
+
+		errs := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{DeploymentEvent: client.CustomInfoEvent})
+		assert.Assert(t, len(errs) == 1)
+	})
+}
+
+func TestJoinTruncated(t *testing.T) {
+	assert.Equal(t, "a, b, c", joinTruncated([]string{"a", "b", "c"}, 100))
+	assert.Equal(t, "aaa, ...", joinTruncated([]string{"aaa", "bbb", "ccc"}, 10))
+	assert.Equal(t, "aaa, bbbbb", joinTruncated([]string{"aaa", "bbbbb"}, 10))
+	assert.Equal(t, "aaaaaaa...", joinTruncated([]string{"aaaaaaaaaaaa", "bbb"}, 10))
+}
+
+func TestDeployConfigsReportsConstraintViolationsInDryRun(t *testing.T) {
+	theApi := api.API{ID: "alerting-profile", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	sortedConfigs := []config.Config{
+		{
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: strings.Repeat("a", 101)}},
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "config"},
+			Template:   generateDummyTemplate(t),
+			Type:       config.ClassicApiType{Api: theApi.ID},
+		},
+	}
+
+	errs := DeployConfigs(context.TODO(), client.NewDummyClient(), apis, sortedConfigs, DeployConfigsOptions{DryRun: true})
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], `"maximum name length" constraint of API "alerting-profile"`)
+}
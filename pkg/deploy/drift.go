@@ -0,0 +1,246 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Drift is a config whose object in the environment differs from the config.
+type Drift struct {
+	Coordinate coordinate.Coordinate
+	// ObjectId is the ID of the drifted object. It is empty if the object does not exist.
+	ObjectId string
+	// Reason describes the first difference found, e.g. the property holding a different value
+	Reason string
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s: %s", d.Coordinate, d.Reason)
+}
+
+// DetectDrift compares the given sorted configs with the objects deployed in the environment of the client, without
+// changing anything. Like skipped configs are resolved, settings objects are found by the externalId monaco assigned
+// them, and classic configs by their name. Only the properties defined by a config are compared, so properties the
+// environment adds to objects are not reported.
+//
+// Configs marked with 'excludeFromDiff' are not compared. Neither are configs of types other than settings and classic
+// configs, or configs of APIs scoped to a parent object. Errors are returned for configs which can not be resolved or
+// read.
+func DetectDrift(ctx context.Context, c client.Client, apis api.APIs, sortedConfigs []config.Config) ([]Drift, []error) {
+	entityMap := newEntityMap(apis)
+	lookup := newEnvironmentLookup(ctx, c, apis, false)
+	r := resolver{lookup: lookup, deploymentTime: time.Now()}
+
+	var drifts []Drift
+	var errs []error
+	for _, conf := range sortedConfigs {
+		conf := conf // to avoid implicit memory aliasing (gosec G601)
+
+		if err := ctx.Err(); err != nil {
+			return drifts, append(errs, fmt.Errorf("drift detection cancelled before config %s: %w", conf.Coordinate, err))
+		}
+
+		if conf.Skip || !driftDetectable(apis, &conf) {
+			entityMap.put(conf.Coordinate, skippedConfigEntity(&conf))
+			continue
+		}
+
+		if conf.ExcludeFromDiff {
+			// the object is still looked up, so configs referencing it can be compared
+			log.WithCtxFields(ctx).Debug("Not comparing config %s, as it is excluded from diffs", conf.Coordinate)
+			entity, err := resolveSkippedConfigRemotely(ctx, c, apis, entityMap, lookup, r, &conf)
+			if err != nil {
+				entity = skippedConfigEntity(&conf)
+			}
+			entityMap.put(conf.Coordinate, entity)
+			continue
+		}
+
+		entity, drift, err := detectConfigDrift(ctx, c, apis, entityMap, lookup, r, &conf)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to detect drift of config %s: %w", conf.Coordinate, err))
+			entity = skippedConfigEntity(&conf)
+		} else if drift != nil {
+			log.WithCtxFields(ctx).Debug("Config %s drifted: %s", conf.Coordinate, drift.Reason)
+			drifts = append(drifts, *drift)
+		}
+		entityMap.put(conf.Coordinate, entity)
+	}
+	return drifts, errs
+}
+
+// driftDetectable returns whether the object of the given config can be found and compared with it.
+func driftDetectable(apis api.APIs, conf *config.Config) bool {
+	switch t := conf.Type.(type) {
+	case config.SettingsType:
+		return true
+	case config.ClassicApiType:
+		a, found := apis[t.Api]
+		return found && !a.HasParent()
+	default:
+		return false
+	}
+}
+
+// detectConfigDrift returns the entity of the object deployed for the given config, and its drift if the object is
+// missing or differs from the config. Configs of missing objects resolve to a skipped entity.
+func detectConfigDrift(ctx context.Context, c client.Client, apis api.APIs, entityMap *entityMap, lookup *environmentLookup, r resolver, conf *config.Config) (parameter.ResolvedEntity, *Drift, error) {
+	var a api.API
+	if t, ok := conf.Type.(config.ClassicApiType); ok {
+		a = apis[t.Api]
+	}
+
+	var entity parameter.ResolvedEntity
+	var err error
+	if a.SingleConfiguration {
+		entity, err = resolveSingleConfiguration(entityMap, r, conf, a.ID)
+	} else {
+		entity, err = resolveSkippedConfigRemotely(ctx, c, apis, entityMap, lookup, r, conf)
+	}
+
+	if notFound := (objectNotFoundError{}); errors.As(err, &notFound) {
+		return skippedConfigEntity(conf), &Drift{Coordinate: conf.Coordinate, Reason: "object does not exist"}, nil
+	} else if err != nil {
+		return parameter.ResolvedEntity{}, nil, err
+	}
+	return compareWithRemote(ctx, c, a, entity, conf)
+}
+
+// resolveSingleConfiguration resolves the config of an API holding a single configuration, which has no name to be
+// looked up by.
+func resolveSingleConfiguration(entityMap *entityMap, r resolver, conf *config.Config, id string) (parameter.ResolvedEntity, error) {
+	properties, errs := resolveProperties(conf, entityMap.get(), r)
+	if len(errs) > 0 {
+		return parameter.ResolvedEntity{}, fmt.Errorf("failed to resolve parameters: %w", errs[0])
+	}
+	properties[config.IdParameter] = id
+	return parameter.ResolvedEntity{EntityName: id, Coordinate: conf.Coordinate, Properties: properties}, nil
+}
+
+// compareWithRemote renders the config of the given resolved entity and compares it with the object deployed for it.
+// The API is only used for classic configs.
+func compareWithRemote(ctx context.Context, c client.Client, a api.API, entity parameter.ResolvedEntity, conf *config.Config) (parameter.ResolvedEntity, *Drift, error) {
+	id := fmt.Sprint(entity.Properties[config.IdParameter])
+
+	rendered, err := conf.Render(entity.Properties)
+	if err != nil {
+		return parameter.ResolvedEntity{}, nil, err
+	}
+
+	var remote []byte
+	if _, isClassic := conf.Type.(config.ClassicApiType); isClassic {
+		if isDashboard(a) {
+			if rendered, _, err = extractShareSettings(rendered); err != nil {
+				return parameter.ResolvedEntity{}, nil, err
+			}
+		}
+		if remote, err = c.ReadConfigById(ctx, a, id); err != nil {
+			return parameter.ResolvedEntity{}, nil, fmt.Errorf("failed to read object %q: %w", id, err)
+		}
+	} else {
+		o, err := c.GetSettingById(ctx, id)
+		if err != nil {
+			return parameter.ResolvedEntity{}, nil, fmt.Errorf("failed to read settings object %q: %w", id, err)
+		}
+		if scope, err := extractScope(entity.Properties); err == nil && scope != o.Scope {
+			return entity, &Drift{Coordinate: conf.Coordinate, ObjectId: id, Reason: fmt.Sprintf("scope is %q instead of %q", o.Scope, scope)}, nil
+		}
+		remote = o.Value
+	}
+
+	var desired, actual interface{}
+	if err := json.Unmarshal([]byte(rendered), &desired); err != nil {
+		return parameter.ResolvedEntity{}, nil, fmt.Errorf("rendered config is not valid JSON: %w", err)
+	}
+	if err := json.Unmarshal(remote, &actual); err != nil {
+		return parameter.ResolvedEntity{}, nil, fmt.Errorf("object %q is not valid JSON: %w", id, err)
+	}
+
+	if path, differs := firstDifference(desired, actual, ""); differs {
+		return entity, &Drift{Coordinate: conf.Coordinate, ObjectId: id, Reason: fmt.Sprintf("property %q differs", path)}, nil
+	}
+	return entity, nil, nil
+}
+
+// firstDifference returns the path of the first property of desired which is missing or different in actual.
+// Properties of objects in actual which desired does not define are ignored. Arrays need to hold the same number of
+// elements, which are compared in order.
+func firstDifference(desired, actual interface{}, path string) (string, bool) {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return pathOrRoot(path), true
+		}
+		keys := maps.Keys(d)
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, found := a[k]
+			if !found {
+				return pathOrRoot(joinPath(path, k)), true
+			}
+			if p, differs := firstDifference(d[k], v, joinPath(path, k)); differs {
+				return p, true
+			}
+		}
+		return "", false
+
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(d) {
+			return pathOrRoot(path), true
+		}
+		for i := range d {
+			if p, differs := firstDifference(d[i], a[i], fmt.Sprintf("%s[%d]", path, i)); differs {
+				return p, true
+			}
+		}
+		return "", false
+
+	default:
+		return pathOrRoot(path), !reflect.DeepEqual(desired, actual)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// pathOrRoot returns the given path, or '.' for the root of a payload.
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,139 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	theApi := api.API{ID: "theApi", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	profile := coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	sortedConfigs := []config.Config{
+		{
+			Coordinate: profile,
+			Type:       config.SettingsType{SchemaId: profile.Type},
+			Template:   template.CreateTemplateFromString("profile", `{"name": "{{.name}}", "rules": [{"severity": "ERROR"}]}`),
+			Parameters: config.Parameters{
+				config.NameParameter:  &value.ValueParameter{Value: "profile"},
+				config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			},
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "config"},
+			Type:       config.ClassicApiType{Api: theApi.ID},
+			Template:   template.CreateTemplateFromString("config", `{"name": "{{.name}}", "profile": "{{.profile}}"}`),
+			Parameters: config.Parameters{
+				config.NameParameter: &value.ValueParameter{Value: "name"},
+				"profile":            reference.NewWithCoordinate(profile, config.IdParameter),
+			},
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "skipped"},
+			Type:       config.ClassicApiType{Api: theApi.ID},
+			Skip:       true,
+		},
+	}
+
+	expectProfile := func(c *client.MockClient, value string) {
+		c.EXPECT().ListSettings(gomock.Any(), profile.Type, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+			o := client.DownloadSettingsObject{ObjectId: "profile-id", ExternalId: idutils.GenerateExternalID(profile.Type, profile.ConfigId)}
+			assert.Assert(t, opts.Filter(o))
+			return []client.DownloadSettingsObject{o}, nil
+		})
+		c.EXPECT().GetSettingById(gomock.Any(), "profile-id").Return(&client.DownloadSettingsObject{ObjectId: "profile-id", Scope: "environment", Value: []byte(value)}, nil)
+	}
+
+	t.Run("objects matching their configs do not drift", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		expectProfile(c, `{"name": "profile", "enabled": true, "rules": [{"severity": "ERROR", "delay": 0}]}`)
+		c.EXPECT().ListConfigs(gomock.Any(), theApi).Return([]client.Value{{Id: "config-id", Name: "name"}}, nil)
+		c.EXPECT().ReadConfigById(gomock.Any(), theApi, "config-id").Return([]byte(`{"id": "config-id", "name": "name", "profile": "profile-id"}`), nil)
+
+		drifts, errs := DetectDrift(context.TODO(), c, apis, sortedConfigs)
+		assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+		assert.Equal(t, len(drifts), 0)
+	})
+
+	t.Run("changed and missing objects drift", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		expectProfile(c, `{"name": "profile", "rules": [{"severity": "WARN"}]}`)
+		c.EXPECT().ListConfigs(gomock.Any(), theApi).Return(nil, nil)
+
+		drifts, errs := DetectDrift(context.TODO(), c, apis, sortedConfigs)
+		assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+		assert.DeepEqual(t, drifts, []Drift{
+			{Coordinate: profile, ObjectId: "profile-id", Reason: `property "rules[0].severity" differs`},
+			{Coordinate: sortedConfigs[1].Coordinate, Reason: "object does not exist"},
+		})
+	})
+
+	t.Run("configs excluded from diffs are not compared, but can be referenced", func(t *testing.T) {
+		excluded := make([]config.Config, len(sortedConfigs))
+		copy(excluded, sortedConfigs)
+		excluded[0].ExcludeFromDiff = true
+
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), profile.Type, gomock.Any()).Return([]client.DownloadSettingsObject{{ObjectId: "profile-id"}}, nil)
+		c.EXPECT().ListConfigs(gomock.Any(), theApi).Return([]client.Value{{Id: "config-id", Name: "name"}}, nil)
+		c.EXPECT().ReadConfigById(gomock.Any(), theApi, "config-id").Return([]byte(`{"name": "name", "profile": "profile-id"}`), nil)
+
+		drifts, errs := DetectDrift(context.TODO(), c, apis, excluded)
+		assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+		assert.Equal(t, len(drifts), 0)
+	})
+}
+
+func TestFirstDifference(t *testing.T) {
+	tests := []struct {
+		name            string
+		desired, actual interface{}
+		wantPath        string
+		wantDiffers     bool
+	}{
+		{"equal values", "a", "a", "", false},
+		{"different root", "a", "b", ".", true},
+		{"additional remote properties", map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 1.0, "b": 2.0}, "", false},
+		{"missing property", map[string]interface{}{"a": 1.0}, map[string]interface{}{}, "a", true},
+		{"nested property", map[string]interface{}{"a": map[string]interface{}{"b": true}}, map[string]interface{}{"a": map[string]interface{}{"b": false}}, "a.b", true},
+		{"array length", []interface{}{1.0}, []interface{}{1.0, 2.0}, ".", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, differs := firstDifference(tt.desired, tt.actual, "")
+			assert.Equal(t, differs, tt.wantDiffers)
+			if differs {
+				assert.Equal(t, path, tt.wantPath)
+			}
+		})
+	}
+}
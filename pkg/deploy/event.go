@@ -0,0 +1,108 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	deploymentEventTitle    = "Monaco configuration deployment"
+	deploymentBizEventType  = "monaco.deployment"
+	deploymentEventSource   = "monaco"
+	maxEventPropertyLength  = 4096
+	truncatedPropertySuffix = ", ..."
+	truncatedValueSuffix    = "..."
+)
+
+// sendDeploymentEvent sends an event of the given type listing all deployed configs to the environment.
+func sendDeploymentEvent(ctx context.Context, c client.EventsClient, eventType client.EventType, deployed []coordinate.Coordinate) error {
+	event := newDeploymentEvent(eventType, deployed)
+
+	log.Info("Sending %s event for %d deployed configs", eventType, len(deployed))
+	if err := c.SendEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to send deployment event: %w", err)
+	}
+	return nil
+}
+
+func newDeploymentEvent(eventType client.EventType, deployed []coordinate.Coordinate) client.Event {
+	projects := map[string]struct{}{}
+	coordinates := make([]string, len(deployed))
+	for i, c := range deployed {
+		projects[c.Project] = struct{}{}
+		coordinates[i] = c.String()
+	}
+	sort.Strings(coordinates)
+
+	projectNames := make([]string, 0, len(projects))
+	for p := range projects {
+		projectNames = append(projectNames, p)
+	}
+	sort.Strings(projectNames)
+
+	title := deploymentEventTitle
+	if eventType == client.BizEvent {
+		title = deploymentBizEventType
+	}
+
+	return client.Event{
+		Type:  eventType,
+		Title: title,
+		Properties: map[string]string{
+			"dt.event.deployment.name":    deploymentEventTitle,
+			"dt.event.deployment.project": strings.Join(projectNames, ", "),
+			"source":                      deploymentEventSource,
+			"configs.deployed.count":      strconv.Itoa(len(deployed)),
+			"configs.deployed":            joinTruncated(coordinates, maxEventPropertyLength),
+		},
+	}
+}
+
+// joinTruncated joins the given values with ', '. If the result would exceed maxLength, all following values are
+// left out and the result ends with ', ...'. If not even the first value fits, it is truncated itself and ends with '...'.
+func joinTruncated(values []string, maxLength int) string {
+	if joined := strings.Join(values, ", "); len(joined) <= maxLength {
+		return joined
+	}
+
+	var sb strings.Builder
+	for i, v := range values {
+		sep := ""
+		if i > 0 {
+			sep = ", "
+		}
+		if sb.Len()+len(sep)+len(v) > maxLength-len(truncatedPropertySuffix) {
+			break
+		}
+		sb.WriteString(sep)
+		sb.WriteString(v)
+	}
+
+	if sb.Len() == 0 {
+		return values[0][:maxLength-len(truncatedValueSuffix)] + truncatedValueSuffix
+	}
+	sb.WriteString(truncatedPropertySuffix)
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// deployExtension uploads the artifact of an Extensions 2.0 extension, unless the version to activate is already
+// available in the environment, and activates the version defined by the rendered config.
+func deployExtension(ctx context.Context, extensionsClient client.ExtensionsClient, entityMap *entityMap, r resolver, c *config.Config) (parameter.ResolvedEntity, []error) {
+	t, ok := c.Type.(config.ExtensionType)
+	if !ok {
+		return parameter.ResolvedEntity{}, []error{fmt.Errorf("config was not of expected type %q, but %q", config.ExtensionTypeId, c.Type.ID())}
+	}
+
+	properties, errors := resolveProperties(c, entityMap.get(), r)
+	if len(errors) > 0 {
+		return parameter.ResolvedEntity{}, errors
+	}
+
+	renderedConfig, err := c.Render(properties)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{err}
+	}
+
+	var environmentConfiguration struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(renderedConfig), &environmentConfiguration); err != nil || environmentConfiguration.Version == "" {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, "extension payload needs to define the 'version' to activate")}
+	}
+	version := environmentConfiguration.Version
+
+	if len(t.Artifact) > 0 {
+		versions, err := extensionsClient.ListExtensionVersions(ctx, t.Name)
+		if err != nil {
+			return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+		}
+
+		if slices.Contains(versions, version) {
+			log.WithCtxFields(ctx).Debug("Extension %q is already available in version %s, skipping upload", t.Name, version)
+		} else {
+			uploaded, err := extensionsClient.UploadExtension(ctx, t.Artifact)
+			if err != nil {
+				return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+			}
+			log.WithCtxFields(ctx).Debug("Uploaded extension %q in version %s", uploaded.Name, uploaded.Version)
+		}
+	}
+
+	if err := extensionsClient.UpdateExtensionEnvironmentConfiguration(ctx, t.Name, []byte(renderedConfig)); err != nil {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+	}
+
+	properties[config.IdParameter] = t.Name
+	properties[config.NameParameter] = t.Name
+	properties["version"] = version
+
+	return parameter.ResolvedEntity{
+		EntityName: t.Name,
+		Coordinate: c.Coordinate,
+		Properties: properties,
+	}, nil
+}
+
+// deployExtensionMonitoring creates or updates a monitoring configuration of an Extensions 2.0 extension. Monitoring
+// configurations have no name, so the config's name is stored as their description, which identifies them on
+// subsequent deployments.
+func deployExtensionMonitoring(ctx context.Context, extensionsClient client.ExtensionsClient, entityMap *entityMap, r resolver, c *config.Config) (parameter.ResolvedEntity, []error) {
+	t, ok := c.Type.(config.ExtensionMonitoringType)
+	if !ok {
+		return parameter.ResolvedEntity{}, []error{fmt.Errorf("config was not of expected type %q, but %q", config.ExtensionMonitoringTypeId, c.Type.ID())}
+	}
+
+	properties, errors := resolveProperties(c, entityMap.get(), r)
+	if len(errors) > 0 {
+		return parameter.ResolvedEntity{}, errors
+	}
+
+	scope, err := extractScope(properties)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{err}
+	}
+
+	name, err := extractConfigName(c, properties)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{err}
+	}
+
+	renderedConfig, err := c.Render(properties)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{err}
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(renderedConfig), &value); err != nil {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, fmt.Sprintf("monitoring configuration payload is not a JSON object: %s", err))}
+	}
+	value["description"] = name
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+	}
+
+	existing, err := extensionsClient.ListExtensionMonitoringConfigurations(ctx, t.Extension)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+	}
+
+	objectId, err := findMonitoringConfiguration(existing, name)
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+	}
+
+	entity, err := extensionsClient.UpsertExtensionMonitoringConfiguration(ctx, t.Extension, client.ExtensionMonitoringConfiguration{
+		ObjectId: objectId,
+		Scope:    scope,
+		Value:    payload,
+	})
+	if err != nil {
+		return parameter.ResolvedEntity{}, []error{newConfigDeployErr(c, err.Error())}
+	}
+
+	properties[config.IdParameter] = entity.Id
+	properties[config.NameParameter] = name
+
+	return parameter.ResolvedEntity{
+		EntityName: name,
+		Coordinate: c.Coordinate,
+		Properties: properties,
+	}, nil
+}
+
+// findMonitoringConfiguration returns the object ID of the monitoring configuration with the given description, or
+// an empty string if none exists.
+func findMonitoringConfiguration(configs []client.ExtensionMonitoringConfiguration, description string) (string, error) {
+	var found []string
+	for _, mc := range configs {
+		var value struct {
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal(mc.Value, &value); err != nil {
+			continue
+		}
+		if value.Description == description {
+			found = append(found, mc.ObjectId)
+		}
+	}
+
+	if len(found) > 1 {
+		return "", fmt.Errorf("%d monitoring configurations with description %q exist, can't decide which to update", len(found), description)
+	}
+	if len(found) == 1 {
+		return found[0], nil
+	}
+	return "", nil
+}
@@ -0,0 +1,161 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+const extensionName = "com.dynatrace.extension.postgres"
+
+func newExtensionConfig(artifact []byte, payload string) config.Config {
+	return config.Config{
+		Template:   template.CreateTemplateFromString("extension.json", payload),
+		Coordinate: coordinate.Coordinate{Project: "project", Type: config.ExtensionCoordinateType, ConfigId: "postgres"},
+		Type:       config.ExtensionType{Name: extensionName, ArtifactPath: "extension.zip", Artifact: artifact},
+		Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: extensionName}},
+	}
+}
+
+func newMonitoringConfig(payload string) config.Config {
+	return config.Config{
+		Template:   template.CreateTemplateFromString("monitoring.json", payload),
+		Coordinate: coordinate.Coordinate{Project: "project", Type: config.ExtensionMonitoringCoordinateType, ConfigId: "db"},
+		Type:       config.ExtensionMonitoringType{Extension: extensionName},
+		Parameters: config.Parameters{
+			config.NameParameter:  &value.ValueParameter{Value: "db"},
+			config.ScopeParameter: &value.ValueParameter{Value: "ag_group-default"},
+		},
+	}
+}
+
+func TestDeployExtension(t *testing.T) {
+	artifact := []byte("zip content")
+
+	t.Run("artifact is uploaded and version activated", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListExtensionVersions(gomock.Any(), extensionName).Return([]string{"1.0.0"}, nil)
+		c.EXPECT().UploadExtension(gomock.Any(), artifact).Return(client.ExtensionVersion{Name: extensionName, Version: "1.1.0"}, nil)
+		c.EXPECT().UpdateExtensionEnvironmentConfiguration(gomock.Any(), extensionName, []byte(`{"version": "1.1.0"}`)).Return(nil)
+
+		conf := newExtensionConfig(artifact, `{"version": "1.1.0"}`)
+		entity, errs := deployExtension(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 0)
+		assert.Equal(t, entity.Properties["version"], "1.1.0")
+		assert.Equal(t, entity.Properties[config.IdParameter], extensionName)
+	})
+
+	t.Run("upload is skipped if the version is already available", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListExtensionVersions(gomock.Any(), extensionName).Return([]string{"1.0.0", "1.1.0"}, nil)
+		c.EXPECT().UpdateExtensionEnvironmentConfiguration(gomock.Any(), extensionName, gomock.Any()).Return(nil)
+
+		conf := newExtensionConfig(artifact, `{"version": "1.1.0"}`)
+		_, errs := deployExtension(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("extensions without artifact are only activated", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpdateExtensionEnvironmentConfiguration(gomock.Any(), extensionName, gomock.Any()).Return(nil)
+
+		conf := newExtensionConfig(nil, `{"version": "1.1.0"}`)
+		_, errs := deployExtension(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("payload without version is reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+
+		conf := newExtensionConfig(artifact, `{}`)
+		_, errs := deployExtension(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "extension payload needs to define the 'version' to activate")
+	})
+}
+
+func TestDeployExtensionMonitoring(t *testing.T) {
+	existing := []client.ExtensionMonitoringConfiguration{
+		{ObjectId: "other-id", Scope: "ag_group-default", Value: json.RawMessage(`{"description": "other"}`)},
+		{ObjectId: "db-id", Scope: "ag_group-default", Value: json.RawMessage(`{"description": "db"}`)},
+	}
+
+	t.Run("existing monitoring configuration is updated", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListExtensionMonitoringConfigurations(gomock.Any(), extensionName).Return(existing, nil)
+		c.EXPECT().UpsertExtensionMonitoringConfiguration(gomock.Any(), extensionName, client.ExtensionMonitoringConfiguration{
+			ObjectId: "db-id",
+			Scope:    "ag_group-default",
+			Value:    json.RawMessage(`{"description":"db","enabled":true}`),
+		}).Return(client.DynatraceEntity{Id: "db-id", Name: "db-id"}, nil)
+
+		conf := newMonitoringConfig(`{"enabled": true}`)
+		entity, errs := deployExtensionMonitoring(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 0)
+		assert.Equal(t, entity.EntityName, "db")
+		assert.Equal(t, entity.Properties[config.IdParameter], "db-id")
+	})
+
+	t.Run("new monitoring configuration is created", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListExtensionMonitoringConfigurations(gomock.Any(), extensionName).Return(existing[:1], nil)
+		c.EXPECT().UpsertExtensionMonitoringConfiguration(gomock.Any(), extensionName, client.ExtensionMonitoringConfiguration{
+			Scope: "ag_group-default",
+			Value: json.RawMessage(`{"description":"db","enabled":true}`),
+		}).Return(client.DynatraceEntity{Id: "new-id", Name: "new-id"}, nil)
+
+		conf := newMonitoringConfig(`{"enabled": true}`)
+		_, errs := deployExtensionMonitoring(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("ambiguous descriptions are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListExtensionMonitoringConfigurations(gomock.Any(), extensionName).Return(append(existing, existing[1]), nil)
+
+		conf := newMonitoringConfig(`{"enabled": true}`)
+		_, errs := deployExtensionMonitoring(context.TODO(), c, newEntityMap(testApiMap), resolver{}, &conf)
+
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], `2 monitoring configurations with description "db" exist`)
+	})
+}
+
+func TestDeployConfigsWithExtensionsInDryRun(t *testing.T) {
+	extension := newExtensionConfig([]byte("zip content"), `{"version": "1.1.0"}`)
+	monitoring := newMonitoringConfig(`{"enabled": true}`)
+
+	errs := DeployConfigs(context.TODO(), client.NewDummyClient(), testApiMap, []config.Config{extension, monitoring}, DeployConfigsOptions{DryRun: true})
+	assert.Equal(t, len(errs), 0)
+}
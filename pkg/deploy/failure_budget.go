@@ -0,0 +1,91 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FailureBudget limits the number of configs that may fail before the deployment to an environment is aborted. It is
+// either an absolute number of configs (e.g. '5'), or a percentage of the configs deployed to the environment
+// (e.g. '10%'). Deployments continue on errors as long as the budget is not exceeded.
+//
+// The zero value does not define a budget. FailureBudget implements pflag.Value, so it can be used as a CLI flag directly.
+type FailureBudget struct {
+	limit      int
+	percentage bool
+	set        bool
+}
+
+// ParseFailureBudget parses a failure budget given either as absolute number or as percentage, e.g. '5' or '10%'.
+func ParseFailureBudget(s string) (FailureBudget, error) {
+	value, percentage := strings.CutSuffix(strings.TrimSpace(s), "%")
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return FailureBudget{}, fmt.Errorf("invalid failure budget %q: expected a non-negative number (e.g. '5') or percentage (e.g. '10%%')", s)
+	}
+	if percentage && limit > 100 {
+		return FailureBudget{}, fmt.Errorf("invalid failure budget %q: percentage must not exceed 100%%", s)
+	}
+
+	return FailureBudget{limit: limit, percentage: percentage, set: true}, nil
+}
+
+// IsSet returns whether a budget is defined at all.
+func (b FailureBudget) IsSet() bool {
+	return b.set
+}
+
+// exceeded returns whether the given number of failed configs out of the total number of deployed configs exceeds
+// the budget.
+func (b FailureBudget) exceeded(failed, total int) bool {
+	if !b.set {
+		return false
+	}
+	if b.percentage {
+		return failed*100 > b.limit*total
+	}
+	return failed > b.limit
+}
+
+func (b FailureBudget) String() string {
+	if !b.set {
+		return ""
+	}
+	if b.percentage {
+		return strconv.Itoa(b.limit) + "%"
+	}
+	return strconv.Itoa(b.limit)
+}
+
+// Set implements pflag.Value
+func (b *FailureBudget) Set(s string) error {
+	parsed, err := ParseFailureBudget(s)
+	if err != nil {
+		return err
+	}
+	*b = parsed
+	return nil
+}
+
+// Type implements pflag.Value
+func (b *FailureBudget) Type() string {
+	return "budget"
+}
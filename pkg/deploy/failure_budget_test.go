@@ -0,0 +1,83 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestParseFailureBudget(t *testing.T) {
+	b, err := ParseFailureBudget("5")
+	assert.NilError(t, err)
+	assert.Equal(t, b.String(), "5")
+	assert.Assert(t, !b.exceeded(5, 100))
+	assert.Assert(t, b.exceeded(6, 100))
+
+	b, err = ParseFailureBudget("10%")
+	assert.NilError(t, err)
+	assert.Equal(t, b.String(), "10%")
+	assert.Assert(t, !b.exceeded(2, 20))
+	assert.Assert(t, b.exceeded(3, 20))
+
+	for _, invalid := range []string{"", "-1", "abc", "101%"} {
+		_, err = ParseFailureBudget(invalid)
+		assert.ErrorContains(t, err, "invalid failure budget", invalid)
+	}
+
+	assert.Assert(t, !FailureBudget{}.exceeded(100, 100))
+}
+
+func TestDeployConfigs_MaxFailures(t *testing.T) {
+	theApi := api.API{ID: "theApi", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	// configs without name parameter fail to deploy
+	sortedConfigs := make([]config.Config, 4)
+	for i := range sortedConfigs {
+		sortedConfigs[i] = config.Config{
+			Coordinate: coordinate.Coordinate{Project: "p", Type: theApi.ID, ConfigId: string(rune('a' + i))},
+			Type:       config.ClassicApiType{Api: theApi.ID},
+			Template:   generateDummyTemplate(t),
+			Parameters: config.Parameters{},
+		}
+	}
+
+	t.Run("deployment continues within the budget", func(t *testing.T) {
+		budget, err := ParseFailureBudget("4")
+		assert.NilError(t, err)
+
+		errs := DeployConfigs(context.TODO(), &client.DummyClient{}, apis, sortedConfigs, DeployConfigsOptions{MaxFailures: budget})
+		assert.Equal(t, len(errs), 4)
+	})
+
+	t.Run("deployment is aborted once the budget is exceeded", func(t *testing.T) {
+		budget, err := ParseFailureBudget("25%")
+		assert.NilError(t, err)
+
+		errs := DeployConfigs(context.TODO(), &client.DummyClient{}, apis, sortedConfigs, DeployConfigsOptions{MaxFailures: budget})
+		assert.Equal(t, len(errs), 3)
+		assert.ErrorContains(t, errs[2], "aborted deployment: 2 out of 4 configs failed, exceeding the maximum of 25% failures")
+	})
+}
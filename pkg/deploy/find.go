@@ -0,0 +1,66 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+)
+
+// FindObjectId returns the ID of the object deployed for the given config in the environment of the client, without
+// deploying anything. Like configs skipped during deployment, settings objects are found by the externalId monaco
+// assigned them, classic configs by their name. The name must not reference other configs, as they are not resolved.
+func FindObjectId(ctx context.Context, c client.Client, apis api.APIs, conf *config.Config) (string, error) {
+	lookup := newEnvironmentLookup(ctx, c, apis, false)
+
+	switch t := conf.Type.(type) {
+	case config.SettingsType:
+		return findSettingsObjectId(ctx, c, lookup, conf, t.SchemaId)
+
+	case config.ClassicApiType:
+		a, found := apis[t.Api]
+		switch {
+		case !found:
+			return "", fmt.Errorf("unknown api `%s`", t.Api)
+		case a.HasParent():
+			return "", fmt.Errorf("configs of API %q are scoped to a parent object and can not be looked up", a.ID)
+		case a.SingleConfiguration:
+			return a.ID, nil
+		}
+
+		parameters, errs := topologysort.SortParameters(conf.Group, conf.Environment, conf.Coordinate, conf.Parameters)
+		if len(errs) > 0 {
+			return "", errs[0]
+		}
+		// parameters referencing other configs fail to resolve, but the name is usually resolved without them
+		properties, _ := resolveParameterValues(conf, map[coordinate.Coordinate]parameter.ResolvedEntity{}, parameters, resolver{lookup: lookup})
+		name, err := extractConfigName(conf, properties)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve the name of %s: %w", conf.Coordinate, err)
+		}
+		return lookup.ConfigId(a.ID, name)
+
+	default:
+		return "", fmt.Errorf("configs of type %q can not be looked up", conf.Type.ID())
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"fmt"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"strings"
+)
+
+// checkIdempotentRendering resolves the parameters of the given config and renders it twice, returning an error if
+// both renders differ. Such nondeterministic configs, e.g. due to random values, differ from the environment on every
+// run, even if nothing was changed.
+//
+// Failures to resolve or render the config are not reported, as deploying the config reports them anyway.
+func checkIdempotentRendering(c *config.Config, entities map[coordinate.Coordinate]parameter.ResolvedEntity, r resolver) error {
+	render := func() (string, bool) {
+		properties, errs := resolveProperties(c, entities, r)
+		if len(errs) > 0 {
+			return "", false
+		}
+		rendered, err := c.Render(properties)
+		return rendered, err == nil
+	}
+
+	first, ok := render()
+	if !ok {
+		return nil
+	}
+	second, ok := render()
+	if !ok || first == second {
+		return nil
+	}
+
+	line, firstLine, secondLine := firstDifferentLine(first, second)
+	return newConfigDeployErr(c, fmt.Sprintf("template %q renders differently on consecutive runs, e.g. due to random values. "+
+		"First difference in line %d: %q != %q", c.Template.Name(), line, firstLine, secondLine))
+}
+
+// firstDifferentLine returns the number (starting at 1) and content of the first line differing between a and b.
+func firstDifferentLine(a, b string) (int, string, string) {
+	aLines, bLines := strings.Split(a, "\n"), strings.Split(b, "\n")
+	for i := 0; ; i++ {
+		var aLine, bLine string
+		if i < len(aLines) {
+			aLine = aLines[i]
+		}
+		if i < len(bLines) {
+			bLine = bLines[i]
+		}
+		if aLine != bLine || i >= len(aLines) || i >= len(bLines) {
+			return i + 1, aLine, bLine
+		}
+	}
+}
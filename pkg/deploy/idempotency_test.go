@@ -0,0 +1,82 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"gotest.tools/assert"
+	"testing"
+)
+
+// counterParameter resolves to a different value every time, like a timestamp would
+type counterParameter struct {
+	count int
+}
+
+func (p *counterParameter) GetType() string {
+	return "counter"
+}
+
+func (p *counterParameter) GetReferences() []parameter.ParameterReference {
+	return nil
+}
+
+func (p *counterParameter) ResolveValue(_ parameter.ResolveContext) (interface{}, error) {
+	p.count++
+	return fmt.Sprint(p.count), nil
+}
+
+func TestDeployConfigsChecksIdempotency(t *testing.T) {
+	theApi := api.API{ID: "alerting-profile", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	newConfig := func(id string, counter parameter.Parameter) config.Config {
+		return config.Config{
+			Parameters: config.Parameters{config.NameParameter: value.New(id), "counter": counter},
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: id},
+			Template:   template.CreateTemplateFromString("template.json", "{\n\"name\": \"{{ .name }}\",\n\"counter\": \"{{ .counter }}\"\n}"),
+			Type:       config.ClassicApiType{Api: theApi.ID},
+		}
+	}
+	sortedConfigs := []config.Config{
+		newConfig("deterministic", value.New("1")),
+		newConfig("nondeterministic", &counterParameter{}),
+	}
+
+	t.Run("nondeterministic configs are reported", func(t *testing.T) {
+		errs := DeployConfigs(context.TODO(), client.NewDummyClient(), apis, sortedConfigs, DeployConfigsOptions{DryRun: true, CheckIdempotency: true})
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], `template "template.json" renders differently on consecutive runs`)
+		assert.ErrorContains(t, errs[0], `First difference in line 3: "\"counter\": \"1\"" != "\"counter\": \"2\""`)
+		assert.Equal(t, errs[0].(configDeployErr).Config.ConfigId, "nondeterministic")
+	})
+
+	t.Run("nothing is checked by default", func(t *testing.T) {
+		errs := DeployConfigs(context.TODO(), client.NewDummyClient(), apis, sortedConfigs, DeployConfigsOptions{DryRun: true})
+		assert.Equal(t, len(errs), 0)
+	})
+}
@@ -0,0 +1,279 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/google/uuid"
+	"os"
+	"sync"
+	"time"
+)
+
+// The lock is stored as auto-tagging rule without any rules, which is harmless and writable with the permissions
+// needed to deploy settings. Each holder creates its own lock object, identified by an externalId unique to the holder.
+// As the names of auto-tagging rules are unique, creating the lock object fails while another holder's object exists,
+// which makes acquiring a lock atomic.
+const (
+	lockSchemaId = "builtin:tags.auto-tagging"
+	lockName     = "monaco-deploy-lock"
+)
+
+// DefaultLockLease is the time after which a lock that was not released is considered stale
+const DefaultLockLease = time.Hour
+
+// lockInfo is stored as description of the lock object.
+type lockInfo struct {
+	Holder   string    `json:"holder"`
+	Token    string    `json:"token"`
+	Acquired time.Time `json:"acquired"`
+	Expires  time.Time `json:"expires"`
+}
+
+// lockObject is a lock object found in an environment
+type lockObject struct {
+	objectId string
+	info     lockInfo
+}
+
+// Lock is an advisory lock held on an environment, preventing concurrent deployments using a lock. It does not
+// prevent any other changes to the environment. While it is held, its lease is renewed periodically, so long-running
+// deployments don't lose their lock.
+type Lock struct {
+	client client.SettingsClient
+	lease  time.Duration
+
+	mutex sync.Mutex
+	info  lockInfo
+
+	stopHeartbeat context.CancelFunc
+	heartbeatDone chan struct{}
+}
+
+// DefaultLockHolder identifies this process as holder of locks.
+func DefaultLockHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s (pid %d)", host, os.Getpid())
+}
+
+// AcquireLock acquires the lock of the environment of the given client for the given lease duration. It fails if
+// another holder's lock exists that is not yet expired. Expired locks are taken over. Until the lock is released, its
+// lease is renewed after a third of the lease duration.
+func AcquireLock(ctx context.Context, c client.SettingsClient, holder string, lease time.Duration) (*Lock, error) {
+	current, err := readLocks(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for _, o := range current {
+		if now.Before(o.info.Expires) {
+			return nil, fmt.Errorf("environment is locked by %s since %s until %s", o.info.Holder, o.info.Acquired.Format(time.RFC3339), o.info.Expires.Format(time.RFC3339))
+		}
+		log.WithCtxFields(ctx).Warn("Taking over stale deployment lock of %s, which expired at %s", o.info.Holder, o.info.Expires.Format(time.RFC3339))
+		if err := c.DeleteSettings(ctx, o.objectId); err != nil {
+			return nil, fmt.Errorf("failed to remove stale deployment lock of %s: %w", o.info.Holder, err)
+		}
+	}
+
+	l := &Lock{client: c, lease: lease, info: lockInfo{Holder: holder, Token: uuid.NewString(), Acquired: now, Expires: now.Add(lease)}}
+	if err := l.write(ctx); err != nil {
+		// creating the lock object fails if another holder created its lock object in the meantime
+		if current, readErr := readLocks(ctx, c); readErr == nil && len(current) > 0 {
+			return nil, fmt.Errorf("environment was concurrently locked by %s", current[0].info.Holder)
+		}
+		return nil, fmt.Errorf("failed to acquire deployment lock: %w", err)
+	}
+
+	// environments not enforcing unique names may hold several lock objects. None of the holders may then assume to
+	// hold the lock.
+	current, err = readLocks(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) != 1 || current[0].info.Token != l.info.Token {
+		if err := l.remove(ctx, current); err != nil {
+			log.WithCtxFields(ctx).Warn("Failed to remove deployment lock: %s", err)
+		}
+		return nil, errors.New("environment was concurrently locked by another process")
+	}
+
+	log.WithCtxFields(ctx).Info("Acquired deployment lock until %s", l.info.Expires.Format(time.RFC3339))
+	l.startHeartbeat(ctx)
+	return l, nil
+}
+
+// Renew extends the lease of the lock, starting now. It fails if the lock has been taken over by another holder.
+func (l *Lock) Renew(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current, err := readLocks(ctx, l.client)
+	if err != nil {
+		return err
+	}
+	if !containsToken(current, l.info.Token) {
+		return errors.New("deployment lock was taken over in the meantime")
+	}
+
+	l.info.Expires = time.Now().UTC().Add(l.lease)
+	if err := l.write(ctx); err != nil {
+		return fmt.Errorf("failed to renew deployment lock: %w", err)
+	}
+	log.WithCtxFields(ctx).Debug("Renewed deployment lock until %s", l.info.Expires.Format(time.RFC3339))
+	return nil
+}
+
+// Release releases the lock, unless it has been taken over by another holder in the meantime.
+func (l *Lock) Release(ctx context.Context) error {
+	if l.stopHeartbeat != nil {
+		l.stopHeartbeat()
+		<-l.heartbeatDone
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	current, err := readLocks(ctx, l.client)
+	if err != nil {
+		return err
+	}
+	if !containsToken(current, l.info.Token) {
+		log.WithCtxFields(ctx).Warn("Deployment lock was taken over in the meantime and is not released")
+		return nil
+	}
+
+	if err := l.remove(ctx, current); err != nil {
+		return fmt.Errorf("failed to release deployment lock: %w", err)
+	}
+	log.WithCtxFields(ctx).Info("Released deployment lock")
+	return nil
+}
+
+// startHeartbeat renews the lease of the lock after a third of its duration, until the lock is released.
+func (l *Lock) startHeartbeat(ctx context.Context) {
+	interval := l.lease / 3
+	if interval <= 0 {
+		return
+	}
+
+	heartbeatCtx, cancel := context.WithCancel(ctx)
+	l.stopHeartbeat = cancel
+	l.heartbeatDone = make(chan struct{})
+	go func() {
+		defer close(l.heartbeatDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				if err := l.Renew(heartbeatCtx); err != nil && heartbeatCtx.Err() == nil {
+					log.WithCtxFields(ctx).Error("Failed to renew deployment lock: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// remove deletes the lock object of this lock from the given lock objects.
+func (l *Lock) remove(ctx context.Context, objects []lockObject) error {
+	for _, o := range objects {
+		if o.info.Token != l.info.Token {
+			continue
+		}
+		if err := l.client.DeleteSettings(ctx, o.objectId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write creates the lock object of this lock, or updates it if it already exists.
+func (l *Lock) write(ctx context.Context) error {
+	description, err := json.Marshal(l.info)
+	if err != nil {
+		return fmt.Errorf("failed to serialize deployment lock: %w", err)
+	}
+	content, err := json.Marshal(map[string]interface{}{
+		"name":        lockName,
+		"description": string(description),
+		"rules":       []interface{}{},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize deployment lock: %w", err)
+	}
+
+	_, err = l.client.UpsertSettings(ctx, client.SettingsObject{Id: lockName + "-" + l.info.Token, SchemaId: lockSchemaId, Scope: "environment", Content: content})
+	return err
+}
+
+// readLocks returns the lock objects of the environment, or none if it is not locked.
+func readLocks(ctx context.Context, c client.SettingsClient) ([]lockObject, error) {
+	objects, err := c.ListSettings(ctx, lockSchemaId, client.ListSettingsOptions{
+		Filter: func(o client.DownloadSettingsObject) bool {
+			value, err := parseLockValue(o)
+			return err == nil && value.Name == lockName
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployment lock: %w", err)
+	}
+
+	locks := make([]lockObject, 0, len(objects))
+	for _, o := range objects {
+		value, err := parseLockValue(o)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse deployment lock: %w", err)
+		}
+		var info lockInfo
+		if err := json.Unmarshal([]byte(value.Description), &info); err != nil {
+			return nil, fmt.Errorf("failed to parse deployment lock %q: %w", value.Description, err)
+		}
+		locks = append(locks, lockObject{objectId: o.ObjectId, info: info})
+	}
+	return locks, nil
+}
+
+type lockValue struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func parseLockValue(o client.DownloadSettingsObject) (lockValue, error) {
+	var value lockValue
+	err := json.Unmarshal(o.Value, &value)
+	return value, err
+}
+
+func containsToken(objects []lockObject, token string) bool {
+	for _, o := range objects {
+		if o.info.Token == token {
+			return true
+		}
+	}
+	return false
+}
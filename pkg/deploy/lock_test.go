@@ -0,0 +1,168 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockingClient returns a mock client storing lock objects. Like auto-tagging rules, their names are unique.
+func lockingClient(t *testing.T, existing *lockInfo) (*client.MockClient, *[]client.DownloadSettingsObject) {
+	var objects []client.DownloadSettingsObject
+	var mutex sync.Mutex
+	store := func(objectId string, info lockInfo) {
+		description, err := json.Marshal(info)
+		assert.NilError(t, err)
+		value, err := json.Marshal(map[string]string{"name": lockName, "description": string(description)})
+		assert.NilError(t, err)
+		for i := range objects {
+			if objects[i].ObjectId == objectId {
+				objects[i].Value = value
+				return
+			}
+		}
+		objects = append(objects, client.DownloadSettingsObject{ObjectId: objectId, Value: value})
+	}
+	if existing != nil {
+		store("existing", *existing)
+	}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), lockSchemaId, gomock.Any()).DoAndReturn(func(context.Context, string, client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]client.DownloadSettingsObject(nil), objects...), nil
+	}).AnyTimes()
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var value lockValue
+		assert.NilError(t, json.Unmarshal(obj.Content, &value))
+		var info lockInfo
+		assert.NilError(t, json.Unmarshal([]byte(value.Description), &info))
+		for _, o := range objects {
+			if o.ObjectId != obj.Id {
+				return client.DynatraceEntity{}, errors.New("name must be unique")
+			}
+		}
+		store(obj.Id, info)
+		return client.DynatraceEntity{Id: obj.Id}, nil
+	}).AnyTimes()
+	c.EXPECT().DeleteSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, objectId string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for i := range objects {
+			if objects[i].ObjectId == objectId {
+				objects = append(objects[:i], objects[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}).AnyTimes()
+	return c, &objects
+}
+
+func TestAcquireLock(t *testing.T) {
+	t.Run("unlocked environments are locked and released", func(t *testing.T) {
+		c, objects := lockingClient(t, nil)
+
+		l, err := AcquireLock(context.TODO(), c, "job-1", time.Hour)
+		assert.NilError(t, err)
+		assert.Equal(t, len(*objects), 1)
+
+		_, err = AcquireLock(context.TODO(), c, "job-2", time.Hour)
+		assert.ErrorContains(t, err, "environment is locked by job-1")
+
+		assert.NilError(t, l.Release(context.TODO()))
+		assert.Equal(t, len(*objects), 0)
+	})
+
+	t.Run("stale locks are taken over", func(t *testing.T) {
+		c, objects := lockingClient(t, &lockInfo{Holder: "crashed", Token: "old", Acquired: time.Now().Add(-2 * time.Hour), Expires: time.Now().Add(-time.Hour)})
+
+		l, err := AcquireLock(context.TODO(), c, "job-1", time.Hour)
+		assert.NilError(t, err)
+		assert.Equal(t, l.info.Holder, "job-1")
+		assert.Equal(t, len(*objects), 1)
+		assert.NilError(t, l.Release(context.TODO()))
+	})
+
+	t.Run("concurrently created locks fail", func(t *testing.T) {
+		c, _ := lockingClient(t, nil)
+
+		// another holder creates its lock object between reading and writing the lock
+		other := &Lock{client: c, info: lockInfo{Holder: "job-2", Token: "other", Expires: time.Now().Add(time.Hour)}}
+		l := &Lock{client: c, info: lockInfo{Holder: "job-1", Token: "mine", Expires: time.Now().Add(time.Hour)}}
+		assert.NilError(t, other.write(context.TODO()))
+
+		assert.ErrorContains(t, l.write(context.TODO()), "name must be unique")
+		_, err := AcquireLock(context.TODO(), c, "job-1", time.Hour)
+		assert.ErrorContains(t, err, "environment is locked by job-2")
+	})
+
+	t.Run("locks taken over are not released", func(t *testing.T) {
+		c, objects := lockingClient(t, nil)
+
+		l, err := AcquireLock(context.TODO(), c, "job-1", time.Hour)
+		assert.NilError(t, err)
+
+		// simulate the lock being taken over after it expired
+		*objects = nil
+		other := &Lock{client: c, info: lockInfo{Holder: "job-2", Token: "other", Expires: time.Now().Add(time.Hour)}}
+		assert.NilError(t, other.write(context.TODO()))
+
+		assert.NilError(t, l.Release(context.TODO()))
+		assert.Equal(t, len(*objects), 1)
+	})
+
+	t.Run("renewing extends the lease", func(t *testing.T) {
+		c, _ := lockingClient(t, nil)
+
+		l, err := AcquireLock(context.TODO(), c, "job-1", time.Hour)
+		assert.NilError(t, err)
+		l.info.Expires = time.Now().Add(time.Minute)
+
+		assert.NilError(t, l.Renew(context.TODO()))
+		current, err := readLocks(context.TODO(), c)
+		assert.NilError(t, err)
+		assert.Equal(t, len(current), 1)
+		assert.Assert(t, current[0].info.Expires.After(time.Now().Add(59*time.Minute)))
+		assert.NilError(t, l.Release(context.TODO()))
+	})
+
+	t.Run("leases are renewed while the lock is held", func(t *testing.T) {
+		c, _ := lockingClient(t, nil)
+
+		l, err := AcquireLock(context.TODO(), c, "job-1", 30*time.Millisecond)
+		assert.NilError(t, err)
+		time.Sleep(100 * time.Millisecond)
+
+		_, err = AcquireLock(context.TODO(), c, "job-2", time.Hour)
+		assert.ErrorContains(t, err, "environment is locked by job-1")
+		assert.NilError(t, l.Release(context.TODO()))
+	})
+}
@@ -0,0 +1,134 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"strings"
+)
+
+const syntheticLocationAPIId = "synthetic-location"
+
+// dryRunPlaceholderId is the result of lookups in dry-run mode which do not match any object, as the dry-run client
+// does not know the objects existing in the environment.
+const dryRunPlaceholderId = "DRY_RUN_PLACEHOLDER-0000000000000000"
+
+// environmentLookup implements [parameter.Lookup] by reading the objects of the environment via a client. Results are
+// cached for the duration of a deployment.
+type environmentLookup struct {
+	ctx    context.Context
+	client client.Client
+	apis   api.APIs
+	// dryRun makes lookups not matching any object resolve to a placeholder.
+	dryRun bool
+	// configIdsPerApi caches the ids of all configs per name, per api id
+	configIdsPerApi map[string]map[string][]string
+	// entityIds caches the ids of all entities per entity selector
+	entityIds map[string][]string
+}
+
+var _ parameter.Lookup = (*environmentLookup)(nil)
+
+func newEnvironmentLookup(ctx context.Context, c client.Client, apis api.APIs, dryRun bool) *environmentLookup {
+	return &environmentLookup{
+		ctx:             ctx,
+		client:          c,
+		apis:            apis,
+		dryRun:          dryRun,
+		configIdsPerApi: make(map[string]map[string][]string),
+		entityIds:       make(map[string][]string),
+	}
+}
+
+func (l *environmentLookup) SyntheticLocationId(name string) (string, error) {
+	ids, err := l.configIds(api.NewAPIs()[syntheticLocationAPIId], name)
+	if err != nil {
+		return "", err
+	}
+	return l.single(ids, fmt.Sprintf("synthetic location named %q", name))
+}
+
+func (l *environmentLookup) EntityId(entitySelector string) (string, error) {
+	ids, found := l.entityIds[entitySelector]
+	if !found {
+		var err error
+		if ids, err = l.client.ListEntityIds(l.ctx, entitySelector); err != nil {
+			return "", err
+		}
+		l.entityIds[entitySelector] = ids
+	}
+	return l.single(ids, fmt.Sprintf("entity matching %q", entitySelector))
+}
+
+func (l *environmentLookup) ConfigId(apiId, name string) (string, error) {
+	a, found := l.apis[apiId]
+	if !found {
+		return "", fmt.Errorf("unknown API %q", apiId)
+	}
+
+	ids, err := l.configIds(a, name)
+	if err != nil {
+		return "", err
+	}
+	return l.single(ids, fmt.Sprintf("%q config named %q", apiId, name))
+}
+
+func (l *environmentLookup) configIds(a api.API, name string) ([]string, error) {
+	idsByName, found := l.configIdsPerApi[a.ID]
+	if !found {
+		values, err := l.client.ListConfigs(l.ctx, a)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %q configs: %w", a.ID, err)
+		}
+
+		idsByName = make(map[string][]string, len(values))
+		for _, v := range values {
+			idsByName[v.Name] = append(idsByName[v.Name], v.Id)
+		}
+		l.configIdsPerApi[a.ID] = idsByName
+	}
+	return idsByName[name], nil
+}
+
+// single returns the only id of the given ids, describing the looked up object as what in case of errors.
+func (l *environmentLookup) single(ids []string, what string) (string, error) {
+	switch {
+	case len(ids) == 1:
+		return ids[0], nil
+	case len(ids) > 1:
+		return "", fmt.Errorf("expected exactly one %s, but found %d (%s)", what, len(ids), strings.Join(ids, ", "))
+	case l.dryRun:
+		log.Debug("No %s found, using a placeholder in dry-run", what)
+		return dryRunPlaceholderId, nil
+	default:
+		return "", objectNotFoundError{what: what}
+	}
+}
+
+// objectNotFoundError is returned by lookups not matching any object in the environment.
+type objectNotFoundError struct {
+	what string
+}
+
+func (e objectNotFoundError) Error() string {
+	return fmt.Sprintf("no %s exists", e.what)
+}
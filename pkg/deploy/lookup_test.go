@@ -0,0 +1,156 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/location"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestEnvironmentLookup_SyntheticLocationId(t *testing.T) {
+	locations := []client.Value{
+		{Id: "SYNTHETIC_LOCATION-0000000000000001", Name: "private"},
+		{Id: "SYNTHETIC_LOCATION-0000000000000002", Name: "twice"},
+		{Id: "SYNTHETIC_LOCATION-0000000000000003", Name: "twice"},
+	}
+
+	t.Run("locations are listed once and resolved by name", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()[syntheticLocationAPIId]).Times(1).Return(locations, nil)
+
+		l := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false)
+
+		for i := 0; i < 2; i++ {
+			id, err := l.SyntheticLocationId("private")
+			assert.NilError(t, err)
+			assert.Equal(t, id, "SYNTHETIC_LOCATION-0000000000000001")
+		}
+	})
+
+	t.Run("ambiguous names are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()[syntheticLocationAPIId]).Return(locations, nil)
+
+		_, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false).SyntheticLocationId("twice")
+		assert.ErrorContains(t, err, `expected exactly one synthetic location named "twice", but found 2 (SYNTHETIC_LOCATION-0000000000000002, SYNTHETIC_LOCATION-0000000000000003)`)
+	})
+
+	t.Run("unknown names are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()[syntheticLocationAPIId]).Return(locations, nil)
+
+		_, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false).SyntheticLocationId("unknown")
+		assert.ErrorContains(t, err, `no synthetic location named "unknown" exists`)
+	})
+
+	t.Run("unknown names resolve to placeholders in dry-run", func(t *testing.T) {
+		id, err := newEnvironmentLookup(context.TODO(), client.NewDummyClient(), api.NewAPIs(), true).SyntheticLocationId("my location")
+		assert.NilError(t, err)
+		assert.Equal(t, id, dryRunPlaceholderId)
+	})
+
+	t.Run("failing to list locations is reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()[syntheticLocationAPIId]).Return(nil, fmt.Errorf("unauthorized"))
+
+		_, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), true).SyntheticLocationId("private")
+		assert.ErrorContains(t, err, `failed to list "synthetic-location" configs: unauthorized`)
+	})
+}
+
+func TestEnvironmentLookup_EntityId(t *testing.T) {
+	const selector = `type("HOST"),tag("prod")`
+
+	t.Run("entities are listed once per selector", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntityIds(gomock.Any(), selector).Times(1).Return([]string{"HOST-1234567890ABCDEF"}, nil)
+
+		l := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false)
+
+		for i := 0; i < 2; i++ {
+			id, err := l.EntityId(selector)
+			assert.NilError(t, err)
+			assert.Equal(t, id, "HOST-1234567890ABCDEF")
+		}
+	})
+
+	t.Run("several matches are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntityIds(gomock.Any(), selector).Return([]string{"HOST-1", "HOST-2"}, nil)
+
+		_, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false).EntityId(selector)
+		assert.ErrorContains(t, err, `expected exactly one entity matching "type(\"HOST\"),tag(\"prod\")", but found 2 (HOST-1, HOST-2)`)
+	})
+
+	t.Run("no matches are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListEntityIds(gomock.Any(), selector).Return(nil, nil)
+
+		_, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false).EntityId(selector)
+		assert.ErrorContains(t, err, `no entity matching "type(\"HOST\"),tag(\"prod\")" exists`)
+	})
+}
+
+func TestEnvironmentLookup_ConfigId(t *testing.T) {
+	t.Run("config is looked up by api and name", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()["alerting-profile"]).Return([]client.Value{{Id: "id", Name: "profile"}}, nil)
+
+		id, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false).ConfigId("alerting-profile", "profile")
+		assert.NilError(t, err)
+		assert.Equal(t, id, "id")
+	})
+
+	t.Run("unknown apis are reported", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+
+		_, err := newEnvironmentLookup(context.TODO(), c, api.NewAPIs(), false).ConfigId("unknown", "profile")
+		assert.ErrorContains(t, err, `unknown API "unknown"`)
+	})
+}
+
+func TestDeployConfigsResolvesSyntheticLocations(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), api.NewAPIs()[syntheticLocationAPIId]).Return([]client.Value{{Id: "SYNTHETIC_LOCATION-0000000000000001", Name: "private"}}, nil)
+	c.EXPECT().UpsertConfigByName(gomock.Any(), gomock.Any(), "monitor", []byte(`{"locations": ["SYNTHETIC_LOCATION-0000000000000001"]}`)).
+		Return(client.DynatraceEntity{Id: "SYNTHETIC_TEST-1", Name: "monitor"}, nil)
+
+	conf := config.Config{
+		Template:   template.CreateTemplateFromString("tpl", `{"locations": ["{{ .location }}"]}`),
+		Coordinate: coordinate.Coordinate{Project: "project", Type: "synthetic-monitor", ConfigId: "monitor"},
+		Type:       config.ClassicApiType{Api: "synthetic-monitor"},
+		Parameters: config.Parameters{
+			config.NameParameter: &value.ValueParameter{Value: "monitor"},
+			"location":           location.New("private"),
+		},
+	}
+
+	errs := DeployConfigs(context.TODO(), c, api.NewAPIs(), []config.Config{conf}, DeployConfigsOptions{})
+	assert.Equal(t, len(errs), 0)
+}
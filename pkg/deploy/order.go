@@ -0,0 +1,117 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"sort"
+)
+
+// positionedConfig is a deployed config of an ordered API, with the position it should be applied at.
+type positionedConfig struct {
+	id       string
+	position int
+}
+
+// configPositions collects the deployed configs of ordered APIs with a defined position, per api id.
+type configPositions map[string][]positionedConfig
+
+func (p configPositions) add(apiId string, id string, position int) {
+	p[apiId] = append(p[apiId], positionedConfig{id: id, position: position})
+}
+
+// reorderConfigs applies the positions of all deployed configs of ordered APIs to the environment. Configs of the
+// environment which were not deployed keep their order relative to each other and fill the remaining positions.
+func reorderConfigs(ctx context.Context, c client.ConfigClient, apis api.APIs, positions configPositions) []error {
+	var errs []error
+	for apiId, configs := range positions {
+		a := apis[apiId]
+
+		existing, err := c.ListConfigs(ctx, a)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to reorder %q configs: %w", apiId, err))
+			continue
+		}
+
+		existingIds := make([]string, len(existing))
+		for i, v := range existing {
+			existingIds[i] = v.Id
+		}
+
+		order := mergeOrder(existingIds, configs)
+		if equalOrder(existingIds, order) {
+			log.Debug("Order of %q configs is up to date", apiId)
+			continue
+		}
+
+		log.Debug("Reordering %d %q configs", len(order), apiId)
+		if err := c.ReorderConfigs(ctx, a, order); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reorder %q configs: %w", apiId, err))
+		}
+	}
+	return errs
+}
+
+// mergeOrder places the given positioned configs at their position among the existing ids. All other existing
+// ids keep their relative order. Positions exceeding the number of configs are appended at the end.
+func mergeOrder(existingIds []string, configs []positionedConfig) []string {
+	positioned := make([]positionedConfig, len(configs))
+	copy(positioned, configs)
+	sort.SliceStable(positioned, func(i, j int) bool {
+		return positioned[i].position < positioned[j].position
+	})
+
+	isPositioned := make(map[string]struct{}, len(positioned))
+	for _, p := range positioned {
+		isPositioned[p.id] = struct{}{}
+	}
+
+	var others []string
+	for _, id := range existingIds {
+		if _, f := isPositioned[id]; !f {
+			others = append(others, id)
+		}
+	}
+
+	result := make([]string, 0, len(others)+len(positioned))
+	for len(positioned) > 0 || len(others) > 0 {
+		if len(positioned) > 0 && (positioned[0].position <= len(result)+1 || len(others) == 0) {
+			result = append(result, positioned[0].id)
+			positioned = positioned[1:]
+		} else {
+			result = append(result, others[0])
+			others = others[1:]
+		}
+	}
+	return result
+}
+
+func equalOrder(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestMergeOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		configs  []positionedConfig
+		want     []string
+	}{
+		{
+			name:     "positioned configs are placed among existing ones",
+			existing: []string{"a", "b", "c", "d"},
+			configs:  []positionedConfig{{id: "d", position: 1}, {id: "a", position: 3}},
+			want:     []string{"d", "b", "a", "c"},
+		},
+		{
+			name:     "new configs are placed at their position",
+			existing: []string{"a", "b"},
+			configs:  []positionedConfig{{id: "new", position: 2}},
+			want:     []string{"a", "new", "b"},
+		},
+		{
+			name:     "exceeding positions are appended",
+			existing: []string{"a", "b"},
+			configs:  []positionedConfig{{id: "y", position: 20}, {id: "x", position: 10}},
+			want:     []string{"a", "b", "x", "y"},
+		},
+		{
+			name:     "configs without existing ones are sorted by position",
+			existing: nil,
+			configs:  []positionedConfig{{id: "b", position: 2}, {id: "a", position: 1}},
+			want:     []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, mergeOrder(tt.existing, tt.configs), tt.want)
+		})
+	}
+}
+
+func TestDeployConfigs_ReordersOrderedApis(t *testing.T) {
+	orderedApi := api.API{ID: "request-naming-service", URLPath: "/api/config/v1/service/requestNaming", NonUniqueName: true, Ordered: true}
+	apis := api.APIs{orderedApi.ID: orderedApi}
+
+	newRule := func(id string, position int) config.Config {
+		return config.Config{
+			Template:   generateDummyTemplate(t),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: orderedApi.ID, ConfigId: id},
+			Type:       config.ClassicApiType{Api: orderedApi.ID},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: id}},
+			Position:   position,
+		}
+	}
+
+	t.Run("deployed configs are moved to their position", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), orderedApi, gomock.Any(), "first", gomock.Any()).Return(client.DynatraceEntity{Id: "id-1", Name: "first"}, nil)
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), orderedApi, gomock.Any(), "second", gomock.Any()).Return(client.DynatraceEntity{Id: "id-2", Name: "second"}, nil)
+		c.EXPECT().ListConfigs(gomock.Any(), orderedApi).Return([]client.Value{{Id: "other"}, {Id: "id-2"}, {Id: "id-1"}}, nil)
+		c.EXPECT().ReorderConfigs(gomock.Any(), orderedApi, []string{"id-1", "id-2", "other"}).Return(nil)
+
+		errs := DeployConfigs(context.TODO(), c, apis, []config.Config{newRule("second", 2), newRule("first", 1)}, DeployConfigsOptions{})
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("configs in order are not reordered", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), orderedApi, gomock.Any(), "first", gomock.Any()).Return(client.DynatraceEntity{Id: "id-1", Name: "first"}, nil)
+		c.EXPECT().ListConfigs(gomock.Any(), orderedApi).Return([]client.Value{{Id: "id-1"}, {Id: "other"}}, nil)
+
+		errs := DeployConfigs(context.TODO(), c, apis, []config.Config{newRule("first", 1)}, DeployConfigsOptions{})
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("configs without position are not reordered", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByNonUniqueNameAndId(gomock.Any(), orderedApi, gomock.Any(), "first", gomock.Any()).Return(client.DynatraceEntity{Id: "id-1", Name: "first"}, nil)
+
+		errs := DeployConfigs(context.TODO(), c, apis, []config.Config{newRule("first", 0)}, DeployConfigsOptions{})
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("dry-run does not reorder", func(t *testing.T) {
+		errs := DeployConfigs(context.TODO(), client.NewDummyClient(), apis, []config.Config{newRule("first", 1)}, DeployConfigsOptions{DryRun: true})
+		assert.Equal(t, len(errs), 0)
+	})
+}
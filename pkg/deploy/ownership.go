@@ -0,0 +1,63 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/audit"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/ownership"
+)
+
+// ownershipMarkingClient decorates a client, appending an ownership marker naming the deployed config to the
+// description of config API objects.
+type ownershipMarkingClient struct {
+	client.Client
+}
+
+// WithOwnershipMarkers returns a client marking the config API objects it upserts as managed by monaco, by appending
+// the coordinate of the deployed config to their description. Settings 2.0 objects are already identified by their
+// externalId. Objects are only marked if their payload has a description and the context names the config (see
+// audit.WithCoordinate).
+func WithOwnershipMarkers(c client.Client) client.Client {
+	return &ownershipMarkingClient{Client: c}
+}
+
+func (c *ownershipMarkingClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (client.DynatraceEntity, error) {
+	payload, err := mark(ctx, payload)
+	if err != nil {
+		return client.DynatraceEntity{}, err
+	}
+	return c.Client.UpsertConfigByName(ctx, a, name, payload)
+}
+
+func (c *ownershipMarkingClient) UpsertConfigByNonUniqueNameAndId(ctx context.Context, a api.API, entityId string, name string, payload []byte) (client.DynatraceEntity, error) {
+	payload, err := mark(ctx, payload)
+	if err != nil {
+		return client.DynatraceEntity{}, err
+	}
+	return c.Client.UpsertConfigByNonUniqueNameAndId(ctx, a, entityId, name, payload)
+}
+
+func mark(ctx context.Context, payload []byte) ([]byte, error) {
+	coord, ok := audit.CoordinateFrom(ctx)
+	if !ok {
+		return payload, nil
+	}
+	return ownership.Mark(payload, coord)
+}
@@ -0,0 +1,50 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestWithOwnershipMarkers(t *testing.T) {
+	theApi := api.API{ID: "alerting-profile", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	conf := config.Config{
+		Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "name"}},
+		Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "profile"},
+		Template:   template.CreateTemplateFromString("template", `{"name": "{{.name}}", "description": "Team A"}`),
+		Type:       config.ClassicApiType{Api: theApi.ID},
+	}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertConfigByName(gomock.Any(), theApi, "name", []byte(`{"description":"Team A\n\n[managed by monaco – project:alerting-profile:profile]","name":"name"}`)).Return(client.DynatraceEntity{Id: "id", Name: "name"}, nil)
+
+	errs := DeployConfigs(context.TODO(), WithOwnershipMarkers(c), apis, []config.Config{conf}, DeployConfigsOptions{})
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+}
@@ -0,0 +1,85 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"strings"
+)
+
+// remoteNameValidatingClient decorates a dry-run client. Before a config of a unique-name API is validated, the
+// names of the objects existing in the environment are checked, as an upsert by name is ambiguous if several
+// objects of the same name exist.
+type remoteNameValidatingClient struct {
+	client.Client
+	remote client.ConfigClient
+	// idsByNamePerApi caches the ids of all remote objects per name, per api id
+	idsByNamePerApi map[string]map[string][]string
+}
+
+// WithRemoteNameValidation returns a client validating config names against the objects existing in an environment,
+// before passing them on to the given dry-run client. Remote objects are only read via the remote client, never written.
+//
+// Without it, duplicate names are only detected among the configs deployed in the same run.
+func WithRemoteNameValidation(dryRunClient client.Client, remote client.ConfigClient) client.Client {
+	return &remoteNameValidatingClient{
+		Client:          dryRunClient,
+		remote:          remote,
+		idsByNamePerApi: make(map[string]map[string][]string),
+	}
+}
+
+func (c *remoteNameValidatingClient) UpsertConfigByName(ctx context.Context, a api.API, name string, payload []byte) (client.DynatraceEntity, error) {
+	// the parent objects of parent-scoped configs might not exist yet in a dry-run, and names are only unique per parent
+	if a.HasParent() {
+		return c.Client.UpsertConfigByName(ctx, a, name, payload)
+	}
+
+	idsByName, err := c.remoteIdsByName(ctx, a)
+	if err != nil {
+		return client.DynatraceEntity{}, err
+	}
+
+	if ids := idsByName[name]; len(ids) > 1 {
+		return client.DynatraceEntity{}, fmt.Errorf("%d %q configs named %q already exist in the environment (%s), deploying would update an arbitrary one of them. Please remove the duplicates or rename the config",
+			len(ids), a.ID, name, strings.Join(ids, ", "))
+	}
+
+	return c.Client.UpsertConfigByName(ctx, a, name, payload)
+}
+
+func (c *remoteNameValidatingClient) remoteIdsByName(ctx context.Context, a api.API) (map[string][]string, error) {
+	if idsByName, found := c.idsByNamePerApi[a.ID]; found {
+		return idsByName, nil
+	}
+
+	values, err := c.remote.ListConfigs(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing %q configs of the environment: %w", a.ID, err)
+	}
+
+	idsByName := make(map[string][]string, len(values))
+	for _, v := range values {
+		idsByName[v.Name] = append(idsByName[v.Name], v.Id)
+	}
+	c.idsByNamePerApi[a.ID] = idsByName
+
+	return idsByName, nil
+}
@@ -0,0 +1,76 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestWithRemoteNameValidation(t *testing.T) {
+	theApi := api.API{ID: "alerting-profile", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	newConfig := func(id, name string) config.Config {
+		return config.Config{
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: name}},
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: id},
+			Template:   generateDummyTemplate(t),
+			Type:       config.ClassicApiType{Api: theApi.ID},
+		}
+	}
+
+	t.Run("duplicate remote names are reported", func(t *testing.T) {
+		remote := client.NewMockClient(gomock.NewController(t))
+		remote.EXPECT().ListConfigs(gomock.Any(), theApi).Times(1).Return([]client.Value{
+			{Id: "1", Name: "duplicated"},
+			{Id: "2", Name: "duplicated"},
+			{Id: "3", Name: "unique"},
+		}, nil)
+
+		c := WithRemoteNameValidation(client.NewDummyClient(), remote)
+		errs := DeployConfigs(context.TODO(), c, apis, []config.Config{
+			newConfig("a", "duplicated"),
+			newConfig("b", "unique"),
+			newConfig("c", "new"),
+		}, DeployConfigsOptions{DryRun: true})
+
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], `2 "alerting-profile" configs named "duplicated" already exist in the environment (1, 2)`)
+	})
+
+	t.Run("failing to list remote configs is reported", func(t *testing.T) {
+		remote := client.NewMockClient(gomock.NewController(t))
+		remote.EXPECT().ListConfigs(gomock.Any(), theApi).Return(nil, fmt.Errorf("unauthorized"))
+
+		c := WithRemoteNameValidation(client.NewDummyClient(), remote)
+		errs := DeployConfigs(context.TODO(), c, apis, []config.Config{newConfig("a", "name")}, DeployConfigsOptions{DryRun: true})
+
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "failed to list existing")
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
+	"time"
 )
 
 // TODO: unexport this function
@@ -28,6 +29,23 @@ func ResolveParameterValues(
 	entities map[coordinate.Coordinate]parameter.ResolvedEntity,
 	parameters []topologysort.ParameterWithName,
 ) (parameter.Properties, []error) {
+	return resolveParameterValues(conf, entities, parameters, resolver{})
+}
+
+// resolver holds what the parameters of the configs deployed together are resolved against.
+type resolver struct {
+	// lookup gives access to the objects of the environment, see parameter.ResolveContext
+	lookup parameter.Lookup
+	// deploymentTime is the time of the deployment, see parameter.ResolveContext
+	deploymentTime time.Time
+}
+
+func resolveParameterValues(
+	conf *config.Config,
+	entities map[coordinate.Coordinate]parameter.ResolvedEntity,
+	parameters []topologysort.ParameterWithName,
+	r resolver,
+) (parameter.Properties, []error) {
 
 	var errors []error
 
@@ -51,6 +69,8 @@ func ResolveParameterValues(
 			Environment:             conf.Environment,
 			ParameterName:           name,
 			ResolvedParameterValues: properties,
+			Lookup:                  r.lookup,
+			DeploymentTime:          r.deploymentTime,
 		})
 
 		if err != nil {
@@ -74,13 +94,13 @@ func ResolveParameterValues(
 	return properties, nil
 }
 
-func resolveProperties(c *config.Config, entities map[coordinate.Coordinate]parameter.ResolvedEntity) (parameter.Properties, []error) {
+func resolveProperties(c *config.Config, entities map[coordinate.Coordinate]parameter.ResolvedEntity, r resolver) (parameter.Properties, []error) {
 	var errors []error
 
 	parameters, sortErrs := topologysort.SortParameters(c.Group, c.Environment, c.Coordinate, c.Parameters)
 	errors = append(errors, sortErrs...)
 
-	properties, errs := ResolveParameterValues(c, entities, parameters)
+	properties, errs := resolveParameterValues(c, entities, parameters, r)
 	errors = append(errors, errs...)
 
 	if len(errors) > 0 {
@@ -21,9 +21,11 @@ import (
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/timestamp"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2/topologysort"
 	"gotest.tools/assert"
 	"testing"
+	"time"
 )
 
 func TestResolveParameterValues(t *testing.T) {
@@ -75,6 +77,27 @@ func TestResolveParameterValues(t *testing.T) {
 	assert.Equal(t, timeout, values[timeoutParameterName])
 }
 
+func TestResolveParameterValues_TimestampsAreRelativeToDeploymentTime(t *testing.T) {
+	from, err := timestamp.New("-1h", timestamp.RFC3339, "")
+	assert.NilError(t, err)
+	parameters := []topologysort.ParameterWithName{
+		{Name: config.NameParameter, Parameter: &parameter.DummyParameter{Value: "test"}},
+		{Name: "from", Parameter: from},
+	}
+	conf := config.Config{
+		Template:    generateDummyTemplate(t),
+		Coordinate:  coordinate.Coordinate{Project: "project1", Type: "dashboard", ConfigId: "dashboard-1"},
+		Environment: "development",
+		Parameters:  toParameterMap(parameters),
+	}
+	deploymentTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	values, errs := resolveParameterValues(&conf, map[coordinate.Coordinate]parameter.ResolvedEntity{}, parameters, resolver{deploymentTime: deploymentTime})
+
+	assert.Assert(t, len(errs) == 0, "there should be no errors (errors: %s)", errs)
+	assert.Equal(t, "2023-05-01T11:00:00Z", values["from"])
+}
+
 func TestResolveParameterValuesShouldFailWhenReferencingNonExistingConfig(t *testing.T) {
 	nonExistingConfig := coordinate.Coordinate{
 		Project:  "non-existing",
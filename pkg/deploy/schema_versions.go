@@ -0,0 +1,96 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemamigration"
+	"sync"
+)
+
+// schemaVersions compares the schema versions settings configs were created with to the versions available on the
+// environment. The schemas of the environment are listed once, when the first config is checked.
+type schemaVersions struct {
+	client     client.SettingsClient
+	migrations schemamigration.Table
+
+	once   sync.Once
+	latest map[string]string
+}
+
+func newSchemaVersions(c client.SettingsClient, migrations schemamigration.Table) *schemaVersions {
+	return &schemaVersions{client: c, migrations: migrations}
+}
+
+func (s *schemaVersions) latestVersion(ctx context.Context, schemaId string) (string, bool) {
+	s.once.Do(func() {
+		s.latest = map[string]string{}
+		schemas, err := s.client.ListSchemas(ctx)
+		if err != nil {
+			log.WithCtxFields(ctx).Warn("Failed to list schemas of the environment, schema versions of configs are not checked: %v", err)
+			return
+		}
+		for _, schema := range schemas {
+			s.latest[schema.SchemaId] = schema.LatestSchemaVersion
+		}
+	})
+
+	v, found := s.latest[schemaId]
+	return v, found
+}
+
+// check compares the schema version of the config with the version available on the environment. If their major
+// versions differ, the rendered config is migrated to the available version if a migration is defined, else a warning
+// is logged. It returns the rendered config and the schema version to deploy it with.
+func (s *schemaVersions) check(ctx context.Context, c *config.Config, t config.SettingsType, renderedConfig string) (string, string) {
+	if t.SchemaVersion == "" {
+		return renderedConfig, t.SchemaVersion
+	}
+
+	available, found := s.latestVersion(ctx, t.SchemaId)
+	if !found {
+		return renderedConfig, t.SchemaVersion
+	}
+
+	configVersion, err := version.ParseVersion(t.SchemaVersion)
+	if err != nil {
+		return renderedConfig, t.SchemaVersion
+	}
+	availableVersion, err := version.ParseVersion(available)
+	if err != nil || configVersion.Major == availableVersion.Major {
+		return renderedConfig, t.SchemaVersion
+	}
+
+	m, found := s.migrations.Find(t.SchemaId, configVersion.Major, availableVersion.Major)
+	if !found {
+		log.WithCtxFields(ctx).Warn("Config %s was created with version %s of schema %q, but the environment provides version %s. Its deployment might fail due to incompatible changes of the schema.",
+			c.Coordinate, t.SchemaVersion, t.SchemaId, available)
+		return renderedConfig, t.SchemaVersion
+	}
+
+	migrated, err := m.Apply(renderedConfig)
+	if err != nil {
+		log.WithCtxFields(ctx).Warn("Failed to migrate config %s from version %s to %s of schema %q: %v", c.Coordinate, t.SchemaVersion, available, t.SchemaId, err)
+		return renderedConfig, t.SchemaVersion
+	}
+	log.WithCtxFields(ctx).Info("\tMigrated config %s from version %s to %s of schema %q", c.Coordinate, t.SchemaVersion, available, t.SchemaId)
+	return migrated, available
+}
@@ -0,0 +1,101 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/schemamigration"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDeployConfigsChecksSchemaVersions(t *testing.T) {
+	schemaId := "builtin:alerting.profile"
+	configWithVersion := func(schemaVersion string) []config.Config {
+		return []config.Config{{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: schemaId, ConfigId: "profile"},
+			Type:       config.SettingsType{SchemaId: schemaId, SchemaVersion: schemaVersion},
+			Template:   template.CreateTemplateFromString("template", `{"delayInMinutes": 5}`),
+			Parameters: config.Parameters{
+				config.NameParameter:  &value.ValueParameter{Value: "profile"},
+				config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			},
+		}}
+	}
+	migrations := schemamigration.Table{{SchemaId: schemaId, FromMajor: 7, ToMajor: 8, RenamedFields: map[string]string{"delayInMinutes": "delay"}}}
+
+	tests := []struct {
+		name            string
+		schemaVersion   string
+		migrations      schemamigration.Table
+		expectedVersion string
+		expectedContent string
+	}{
+		{
+			name:            "configs of the same major version are deployed unchanged",
+			schemaVersion:   "8.0.1",
+			migrations:      migrations,
+			expectedVersion: "8.0.1",
+			expectedContent: `{"delayInMinutes": 5}`,
+		},
+		{
+			name:            "configs of a different major version are deployed unchanged without migration",
+			schemaVersion:   "7.4",
+			expectedVersion: "7.4",
+			expectedContent: `{"delayInMinutes": 5}`,
+		},
+		{
+			name:            "configs of a different major version are migrated",
+			schemaVersion:   "7.4",
+			migrations:      migrations,
+			expectedVersion: "8.2",
+			expectedContent: "{\n  \"delay\": 5\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := client.NewMockClient(gomock.NewController(t))
+			c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: schemaId, LatestSchemaVersion: "8.2"}}, nil)
+			c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+				assert.Equal(t, obj.SchemaVersion, tt.expectedVersion)
+				assert.Equal(t, string(obj.Content), tt.expectedContent)
+				return client.DynatraceEntity{Id: "id"}, nil
+			})
+
+			errs := DeployConfigs(context.TODO(), c, api.APIs{}, configWithVersion(tt.schemaVersion), DeployConfigsOptions{CheckSchemaVersions: true, SchemaMigrations: tt.migrations})
+			assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+		})
+	}
+
+	t.Run("schema versions are not checked by default", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Return(client.DynatraceEntity{Id: "id"}, nil)
+
+		errs := DeployConfigs(context.TODO(), c, api.APIs{}, configWithVersion("7.4"), DeployConfigsOptions{})
+		assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+	})
+}
@@ -0,0 +1,108 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+)
+
+// UnknownScopeError is returned if a settings object is scoped to an entity that does not exist in the environment.
+type UnknownScopeError struct {
+	SchemaId string
+	Scope    string
+}
+
+func (e UnknownScopeError) Error() string {
+	return fmt.Sprintf("scope %q of %q settings object does not exist in the environment. "+
+		"If the config was created for another environment, its entity IDs need to be mapped to the matching entities of this environment - "+
+		"e.g. by matching the entities downloaded from both environments and referencing the resulting entity IDs", e.Scope, e.SchemaId)
+}
+
+// scopeValidatingClient decorates a client, verifying that the entity a settings object is scoped to exists before
+// upserting it.
+type scopeValidatingClient struct {
+	client.Client
+	entities client.EntitiesClient
+	// knownScopes caches the existence of already checked entities
+	knownScopes map[string]bool
+}
+
+// WithScopeValidation returns a client verifying that the entity (e.g. HOST-1234567890ABCDEF) a settings object is
+// scoped to exists, before passing it on to the given client. Entities are looked up via the given entities client.
+//
+// Without it, unknown scopes are only reported by the settings API itself, and not at all in dry-run mode.
+func WithScopeValidation(c client.Client, entities client.EntitiesClient) client.Client {
+	return &scopeValidatingClient{
+		Client:      c,
+		entities:    entities,
+		knownScopes: make(map[string]bool),
+	}
+}
+
+func (c *scopeValidatingClient) UpsertSettings(ctx context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+	if err := c.validateScope(ctx, obj); err != nil {
+		return client.DynatraceEntity{}, err
+	}
+
+	return c.Client.UpsertSettings(ctx, obj)
+}
+
+func (c *scopeValidatingClient) UpsertSettingsBatch(ctx context.Context, objs []client.SettingsObject) []client.SettingsBatchResult {
+	results := make([]client.SettingsBatchResult, len(objs))
+
+	var valid []int
+	var validObjs []client.SettingsObject
+	for i, obj := range objs {
+		if err := c.validateScope(ctx, obj); err != nil {
+			results[i].Err = err
+			continue
+		}
+		valid = append(valid, i)
+		validObjs = append(validObjs, obj)
+	}
+
+	if len(validObjs) > 0 {
+		for j, r := range c.Client.UpsertSettingsBatch(ctx, validObjs) {
+			results[valid[j]] = r
+		}
+	}
+	return results
+}
+
+// validateScope returns an error if the object is scoped to an entity that does not exist.
+func (c *scopeValidatingClient) validateScope(ctx context.Context, obj client.SettingsObject) error {
+	if !idutils.IsMeId(obj.Scope) {
+		return nil
+	}
+
+	exists, found := c.knownScopes[obj.Scope]
+	if !found {
+		var err error
+		if exists, err = c.entities.EntityExists(ctx, obj.Scope); err != nil {
+			return fmt.Errorf("failed to validate scope %q: %w", obj.Scope, err)
+		}
+		c.knownScopes[obj.Scope] = exists
+	}
+
+	if !exists {
+		return UnknownScopeError{SchemaId: obj.SchemaId, Scope: obj.Scope}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestWithScopeValidation(t *testing.T) {
+	const hostId = "HOST-1234567890ABCDEF"
+
+	newSetting := func(id, scope string) config.Config {
+		return config.Config{
+			Template:   generateDummyTemplate(t),
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "builtin:tags.auto-tagging", ConfigId: id},
+			Type:       config.SettingsType{SchemaId: "builtin:tags.auto-tagging", SchemaVersion: "1.0"},
+			Parameters: config.Parameters{config.ScopeParameter: &value.ValueParameter{Value: scope}},
+		}
+	}
+
+	t.Run("unknown entity scope is reported", func(t *testing.T) {
+		remote := client.NewMockClient(gomock.NewController(t))
+		remote.EXPECT().EntityExists(gomock.Any(), hostId).Times(1).Return(false, nil)
+
+		c := WithScopeValidation(client.NewDummyClient(), remote)
+		errs := DeployConfigs(context.TODO(), c, nil, []config.Config{
+			newSetting("a", hostId),
+			newSetting("b", hostId),
+		}, DeployConfigsOptions{DryRun: true, ContinueOnErr: true})
+
+		assert.Equal(t, len(errs), 2)
+		assert.ErrorContains(t, errs[0], `scope "HOST-1234567890ABCDEF" of "builtin:tags.auto-tagging" settings object does not exist`)
+	})
+
+	t.Run("existing entity scope is deployed", func(t *testing.T) {
+		remote := client.NewMockClient(gomock.NewController(t))
+		remote.EXPECT().EntityExists(gomock.Any(), hostId).Times(1).Return(true, nil)
+
+		c := WithScopeValidation(client.NewDummyClient(), remote)
+		errs := DeployConfigs(context.TODO(), c, nil, []config.Config{
+			newSetting("a", hostId),
+			newSetting("b", hostId),
+		}, DeployConfigsOptions{DryRun: true})
+
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("non-entity scopes are not validated", func(t *testing.T) {
+		remote := client.NewMockClient(gomock.NewController(t))
+
+		c := WithScopeValidation(client.NewDummyClient(), remote)
+		errs := DeployConfigs(context.TODO(), c, nil, []config.Config{newSetting("a", "environment")}, DeployConfigsOptions{DryRun: true})
+
+		assert.Equal(t, len(errs), 0)
+	})
+
+	t.Run("failing to look up the entity is reported", func(t *testing.T) {
+		remote := client.NewMockClient(gomock.NewController(t))
+		remote.EXPECT().EntityExists(gomock.Any(), hostId).Return(false, fmt.Errorf("unauthorized"))
+
+		c := WithScopeValidation(client.NewDummyClient(), remote)
+		errs := DeployConfigs(context.TODO(), c, nil, []config.Config{newSetting("a", hostId)}, DeployConfigsOptions{DryRun: true})
+
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "failed to validate scope")
+	})
+}
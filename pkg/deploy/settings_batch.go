@@ -0,0 +1,92 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// settingsBatch collects consecutive settings configs of the same schema to upsert them in a single request. As the
+// configs are sorted, a config can only depend on configs before it - configs referencing a config of the batch are
+// not added, but deployed after the batch.
+type settingsBatch struct {
+	settings    []preparedSetting
+	coordinates map[coordinate.Coordinate]struct{}
+}
+
+// settingResult is the result of deploying a single config of a batch
+type settingResult struct {
+	conf   *config.Config
+	entity parameter.ResolvedEntity
+	errs   []error
+}
+
+func (b *settingsBatch) len() int {
+	return len(b.settings)
+}
+
+// accepts returns whether the given settings config can be added to the batch, i.e. whether the batch is not full yet,
+// the config has the same schema as the batch and does not reference any of its configs.
+func (b *settingsBatch) accepts(c *config.Config, maxSize int) bool {
+	if b.len() == 0 {
+		return true
+	}
+	if t, ok := c.Type.(config.SettingsType); !ok || t.SchemaId != b.settings[0].object.SchemaId || b.len() >= maxSize {
+		return false
+	}
+	for _, ref := range c.References() {
+		if _, found := b.coordinates[ref]; found {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *settingsBatch) add(s preparedSetting) {
+	if b.coordinates == nil {
+		b.coordinates = make(map[coordinate.Coordinate]struct{})
+	}
+	b.settings = append(b.settings, s)
+	b.coordinates[s.conf.Coordinate] = struct{}{}
+}
+
+// upsert upserts all settings of the batch and empties it. A single setting is upserted on its own.
+func (b *settingsBatch) upsert(ctx context.Context, c client.SettingsClient) []settingResult {
+	settings := b.settings
+	*b = settingsBatch{}
+
+	if len(settings) == 1 {
+		entity, errs := settings[0].result(c.UpsertSettings(settings[0].ctx, settings[0].object))
+		return []settingResult{{conf: settings[0].conf, entity: entity, errs: errs}}
+	}
+
+	objects := make([]client.SettingsObject, len(settings))
+	for i, s := range settings {
+		objects[i] = s.object
+	}
+
+	results := make([]settingResult, len(settings))
+	for i, r := range c.UpsertSettingsBatch(ctx, objects) {
+		entity, errs := settings[i].result(r.Entity, r.Err)
+		results[i] = settingResult{conf: settings[i].conf, entity: entity, errs: errs}
+	}
+	return results
+}
@@ -0,0 +1,104 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDeployConfigsBatchesSettings(t *testing.T) {
+	setting := func(schemaId, id string, params config.Parameters) config.Config {
+		params[config.ScopeParameter] = &value.ValueParameter{Value: "environment"}
+		return config.Config{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: schemaId, ConfigId: id},
+			Type:       config.SettingsType{SchemaId: schemaId},
+			Template:   template.CreateTemplateFromString("template", `{"ref": "{{.ref}}"}`),
+			Parameters: params,
+		}
+	}
+
+	sortedConfigs := []config.Config{
+		setting("schema-a", "a1", config.Parameters{"ref": &value.ValueParameter{Value: "x"}}),
+		setting("schema-a", "a2", config.Parameters{"ref": &value.ValueParameter{Value: "x"}}),
+		setting("schema-a", "a3", config.Parameters{"ref": reference.New("project", "schema-a", "a1", config.IdParameter)}),
+		setting("schema-b", "b1", config.Parameters{"ref": reference.New("project", "schema-a", "a2", config.IdParameter)}),
+	}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	gomock.InOrder(
+		c.EXPECT().UpsertSettingsBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, objs []client.SettingsObject) []client.SettingsBatchResult {
+			assert.Equal(t, len(objs), 2)
+			return []client.SettingsBatchResult{
+				{Entity: client.DynatraceEntity{Id: "id-a1"}},
+				{Entity: client.DynatraceEntity{Id: "id-a2"}},
+			}
+		}),
+		// a3 references a1, thus it is not batched together with it, and b1 is of another schema
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+			assert.Equal(t, obj.Id, "a3")
+			assert.Equal(t, string(obj.Content), `{"ref": "id-a1"}`)
+			return client.DynatraceEntity{Id: "id-a3"}, nil
+		}),
+		c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, obj client.SettingsObject) (client.DynatraceEntity, error) {
+			assert.Equal(t, obj.Id, "b1")
+			assert.Equal(t, string(obj.Content), `{"ref": "id-a2"}`)
+			return client.DynatraceEntity{Id: "id-b1"}, nil
+		}),
+	)
+
+	errs := DeployConfigs(context.TODO(), c, api.APIs{}, sortedConfigs, DeployConfigsOptions{SettingsBatchSize: 10})
+	assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+}
+
+func TestDeployConfigsReportsErrorsOfBatchedSettings(t *testing.T) {
+	sortedConfigs := make([]config.Config, 3)
+	for i, id := range []string{"a", "b", "c"} {
+		sortedConfigs[i] = config.Config{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: "schema", ConfigId: id},
+			Type:       config.SettingsType{SchemaId: "schema"},
+			Template:   generateDummyTemplate(t),
+			Parameters: config.Parameters{
+				config.NameParameter:  &value.ValueParameter{Value: id},
+				config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			},
+		}
+	}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().UpsertSettingsBatch(gomock.Any(), gomock.Len(2)).Return([]client.SettingsBatchResult{
+		{Entity: client.DynatraceEntity{Id: "id-a"}},
+		{Err: errors.New("failed")},
+	})
+	c.EXPECT().UpsertSettings(gomock.Any(), gomock.Any()).Return(client.DynatraceEntity{Id: "id-c"}, nil)
+
+	errs := DeployConfigs(context.TODO(), c, api.APIs{}, sortedConfigs, DeployConfigsOptions{SettingsBatchSize: 2, ContinueOnErr: true})
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "project:schema:b")
+}
@@ -0,0 +1,129 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+)
+
+// resolveSkippedConfigRemotely looks up the object a skipped config deployed in an earlier run, so configs referencing
+// it can still be deployed. Settings objects are found by the externalId monaco assigned them, classic configs by their
+// name. Only the id and name of the object are resolved - all other properties are taken from the config itself.
+func resolveSkippedConfigRemotely(ctx context.Context, c client.Client, apis api.APIs, entityMap *entityMap, lookup *environmentLookup, r resolver, conf *config.Config) (parameter.ResolvedEntity, error) {
+	properties, errs := resolveProperties(conf, entityMap.get(), r)
+	if len(errs) > 0 {
+		return parameter.ResolvedEntity{}, fmt.Errorf("failed to resolve parameters: %w", errs[0])
+	}
+
+	name, nameErr := extractConfigName(conf, properties)
+
+	var id string
+	var err error
+	switch t := conf.Type.(type) {
+	case config.SettingsType:
+		id, err = findSettingsObjectId(ctx, c, lookup, conf, t.SchemaId)
+		if nameErr != nil {
+			name = id
+		}
+
+	case config.ClassicApiType:
+		a, found := apis[t.Api]
+		switch {
+		case !found:
+			return parameter.ResolvedEntity{}, fmt.Errorf("unknown api `%s`. this is most likely a bug", t.Api)
+		case a.HasParent():
+			return parameter.ResolvedEntity{}, fmt.Errorf("configs of API %q are scoped to a parent object and can not be looked up", a.ID)
+		case nameErr != nil:
+			return parameter.ResolvedEntity{}, nameErr
+		}
+		id, err = lookup.ConfigId(a.ID, name)
+
+	default:
+		return parameter.ResolvedEntity{}, fmt.Errorf("configs of type %q can not be looked up", conf.Type.ID())
+	}
+
+	if err != nil {
+		return parameter.ResolvedEntity{}, err
+	}
+
+	properties[config.IdParameter] = id
+	properties[config.NameParameter] = name
+
+	return parameter.ResolvedEntity{
+		EntityName: name,
+		Coordinate: conf.Coordinate,
+		Properties: properties,
+		Skip:       false,
+	}, nil
+}
+
+// findSettingsObjectId returns the object id of the settings object deployed for the given config, identified by its
+// externalId, or by the object id it was downloaded from.
+func findSettingsObjectId(ctx context.Context, c client.SettingsClient, lookup *environmentLookup, conf *config.Config, schemaId string) (string, error) {
+	externalId := idutils.GenerateExternalID(schemaId, conf.SettingsObjectId())
+
+	objects, err := c.ListSettings(ctx, schemaId, client.ListSettingsOptions{
+		DiscardValue: true,
+		Filter: func(o client.DownloadSettingsObject) bool {
+			return o.ExternalId == externalId || (conf.OriginObjectId != "" && o.ObjectId == conf.OriginObjectId)
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list settings of schema %q: %w", schemaId, err)
+	}
+
+	ids := make([]string, len(objects))
+	for i, o := range objects {
+		ids[i] = o.ObjectId
+	}
+	return lookup.single(ids, fmt.Sprintf("settings object of schema %q with externalId %q", schemaId, externalId))
+}
+
+// skippedConfigEntity returns the entity stored for a skipped config which is not resolved remotely. Configs
+// referencing it fail to deploy.
+func skippedConfigEntity(conf *config.Config) parameter.ResolvedEntity {
+	return parameter.ResolvedEntity{
+		EntityName: conf.Coordinate.ConfigId,
+		Coordinate: conf.Coordinate,
+		Properties: parameter.Properties{},
+		Skip:       true,
+	}
+}
+
+// referencedConfigs returns the coordinates of all configs referenced by parameters of configs which are not skipped,
+// including skipped configs referenced by other referenced configs. Configs only named in dependsOn are not included, as
+// no value of theirs is needed. The given configs need to be sorted.
+func referencedConfigs(sortedConfigs []config.Config) map[coordinate.Coordinate]struct{} {
+	referenced := make(map[coordinate.Coordinate]struct{})
+	for i := len(sortedConfigs) - 1; i >= 0; i-- {
+		c := sortedConfigs[i]
+		if _, isReferenced := referenced[c.Coordinate]; c.Skip && !isReferenced {
+			continue
+		}
+		for _, ref := range c.ParameterReferences() {
+			referenced[ref] = struct{}{}
+		}
+	}
+	return referenced
+}
@@ -0,0 +1,130 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploy
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/golang/mock/gomock"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestDeployConfigsResolvesSkippedReferences(t *testing.T) {
+	theApi := api.API{ID: "theApi", URLPath: "path"}
+	apis := api.APIs{theApi.ID: theApi}
+
+	baseline := coordinate.Coordinate{Project: "baseline", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	sortedConfigs := []config.Config{
+		{
+			Coordinate: baseline,
+			Type:       config.SettingsType{SchemaId: baseline.Type},
+			Template:   generateDummyTemplate(t),
+			Parameters: config.Parameters{
+				config.NameParameter:  &value.ValueParameter{Value: "profile"},
+				config.ScopeParameter: &value.ValueParameter{Value: "environment"},
+			},
+			Skip: true,
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "baseline", Type: theApi.ID, ConfigId: "unreferenced"},
+			Type:       config.ClassicApiType{Api: theApi.ID},
+			Skip:       true,
+		},
+		{
+			Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "config"},
+			Type:       config.ClassicApiType{Api: theApi.ID},
+			Template:   template.CreateTemplateFromString("template", `{"profile": "{{.profile}}"}`),
+			Parameters: config.Parameters{
+				config.NameParameter: &value.ValueParameter{Value: "name"},
+				"profile":            reference.NewWithCoordinate(baseline, config.IdParameter),
+			},
+		},
+	}
+
+	t.Run("skipped configs are looked up by their externalId", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), baseline.Type, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+			objects := []client.DownloadSettingsObject{
+				{ObjectId: "other", ExternalId: idutils.GenerateExternalID(baseline.Type, "other")},
+				{ObjectId: "object-id", ExternalId: idutils.GenerateExternalID(baseline.Type, baseline.ConfigId)},
+			}
+			var filtered []client.DownloadSettingsObject
+			for _, o := range objects {
+				if opts.Filter(o) {
+					filtered = append(filtered, o)
+				}
+			}
+			return filtered, nil
+		})
+		c.EXPECT().UpsertConfigByName(gomock.Any(), theApi, "name", []byte(`{"profile": "object-id"}`)).Return(client.DynatraceEntity{Id: "id", Name: "name"}, nil)
+
+		errs := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{ResolveSkippedReferences: true})
+		assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+	})
+
+	t.Run("configs referencing skipped configs not found fail", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSettings(gomock.Any(), baseline.Type, gomock.Any()).Return(nil, nil)
+
+		errs := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{ResolveSkippedReferences: true})
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "referencing skipped config")
+	})
+
+	t.Run("skipped configs only named in dependsOn are not looked up", func(t *testing.T) {
+		dependency := coordinate.Coordinate{Project: "baseline", Type: theApi.ID, ConfigId: "dependency"}
+		configs := []config.Config{
+			{
+				Coordinate: dependency,
+				Type:       config.ClassicApiType{Api: theApi.ID},
+				Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "dependency"}},
+				Skip:       true,
+			},
+			{
+				Coordinate: coordinate.Coordinate{Project: "project", Type: theApi.ID, ConfigId: "config"},
+				Type:       config.ClassicApiType{Api: theApi.ID},
+				Template:   template.CreateTemplateFromString("template", `{}`),
+				Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "name"}},
+				DependsOn:  []coordinate.Coordinate{dependency},
+			},
+		}
+
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().UpsertConfigByName(gomock.Any(), theApi, "name", []byte(`{}`)).Return(client.DynatraceEntity{Id: "id", Name: "name"}, nil)
+
+		errs := DeployConfigs(context.TODO(), c, apis, configs, DeployConfigsOptions{ResolveSkippedReferences: true})
+		assert.Equal(t, len(errs), 0, "unexpected errors: %v", errs)
+	})
+
+	t.Run("skipped configs are not looked up by default", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+
+		errs := DeployConfigs(context.TODO(), c, apis, sortedConfigs, DeployConfigsOptions{})
+		assert.Equal(t, len(errs), 1)
+		assert.ErrorContains(t, errs[0], "referencing skipped config")
+	})
+}
@@ -0,0 +1,136 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package validate checks rendered classic config payloads against well-known constraints of the Dynatrace APIs.
+// This allows to report violations before anything is uploaded - and in dry-run mode, where the APIs are never called.
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPayloadSize is the maximum size in bytes of a request body accepted by the config APIs.
+	MaxPayloadSize = 5 * 1024 * 1024
+
+	// MaxDashboardTiles is the maximum number of tiles a dashboard may contain.
+	MaxDashboardTiles = 100
+)
+
+// maxNameLengths holds the maximum length of config names per API id, for APIs known to limit it.
+var maxNameLengths = map[string]int{
+	"alerting-profile":       100,
+	"auto-tag":               200,
+	"dashboard":              200,
+	"management-zone":        500,
+	"maintenance-window":     200,
+	"notification":           200,
+	"request-attributes":     200,
+	"slo":                    200,
+	"synthetic-monitor":      500,
+	"application-web":        200,
+	"application-mobile":     200,
+	"calculated-metrics-log": 300,
+}
+
+// ConstraintViolationError is returned if a payload violates a constraint of the API it is deployed to.
+type ConstraintViolationError struct {
+	// Api is the id of the API the payload is deployed to
+	Api string
+	// Constraint is a short description of the violated constraint
+	Constraint string
+	// Reason describes the violation and how to resolve it
+	Reason string
+}
+
+func (e ConstraintViolationError) Error() string {
+	return fmt.Sprintf("payload violates %q constraint of API %q: %s", e.Constraint, e.Api, e.Reason)
+}
+
+// constraint checks the rendered payload of a config named name, returning a violation error or nil.
+type constraint func(a api.API, name string, payload []byte) error
+
+var constraints = []constraint{
+	payloadSize,
+	nameLength,
+	dashboardTiles,
+}
+
+// ClassicConfig checks the rendered payload of a config named name against all well-known constraints of the given
+// API. All violations are returned.
+func ClassicConfig(a api.API, name string, payload []byte) []error {
+	var errs []error
+	for _, c := range constraints {
+		if err := c(a, name, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func payloadSize(a api.API, _ string, payload []byte) error {
+	if len(payload) <= MaxPayloadSize {
+		return nil
+	}
+	return ConstraintViolationError{
+		Api:        a.ID,
+		Constraint: "maximum payload size",
+		Reason:     fmt.Sprintf("payload is %d bytes, but at most %d bytes are accepted. Please split the config into several smaller ones", len(payload), MaxPayloadSize),
+	}
+}
+
+func nameLength(a api.API, name string, _ []byte) error {
+	maxLength, found := maxNameLengths[a.ID]
+	if !found {
+		return nil
+	}
+
+	length := utf8.RuneCountInString(name)
+	if length <= maxLength {
+		return nil
+	}
+	return ConstraintViolationError{
+		Api:        a.ID,
+		Constraint: "maximum name length",
+		Reason:     fmt.Sprintf("name %q is %d characters long, but at most %d characters are accepted. Please shorten the 'name' parameter", name, length, maxLength),
+	}
+}
+
+func dashboardTiles(a api.API, _ string, payload []byte) error {
+	if a.ID != "dashboard" {
+		return nil
+	}
+
+	var dashboard struct {
+		Tiles []json.RawMessage `json:"tiles"`
+	}
+	if err := json.Unmarshal(payload, &dashboard); err != nil {
+		// the payload was validated to be JSON before - if the tiles can't be read, it's up to the API to report it
+		return nil
+	}
+
+	if len(dashboard.Tiles) <= MaxDashboardTiles {
+		return nil
+	}
+	return ConstraintViolationError{
+		Api:        a.ID,
+		Constraint: "maximum number of tiles",
+		Reason:     fmt.Sprintf("dashboard contains %d tiles, but at most %d are accepted. Please split the dashboard into several ones", len(dashboard.Tiles), MaxDashboardTiles),
+	}
+}
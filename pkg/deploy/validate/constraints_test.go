@@ -0,0 +1,105 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestClassicConfig(t *testing.T) {
+	dashboardApi := api.API{ID: "dashboard"}
+	alertingProfileApi := api.API{ID: "alerting-profile"}
+	unknownApi := api.API{ID: "unknown"}
+
+	tiles := func(n int) []byte {
+		return []byte(fmt.Sprintf(`{"tiles": [%s]}`, strings.TrimSuffix(strings.Repeat(`{},`, n), ",")))
+	}
+
+	tests := []struct {
+		name           string
+		api            api.API
+		configName     string
+		payload        []byte
+		wantViolations []string
+	}{
+		{
+			name:       "valid payload",
+			api:        dashboardApi,
+			configName: "name",
+			payload:    tiles(MaxDashboardTiles),
+		},
+		{
+			name:           "too large payload",
+			api:            unknownApi,
+			configName:     "name",
+			payload:        []byte(`"` + strings.Repeat("a", MaxPayloadSize) + `"`),
+			wantViolations: []string{"maximum payload size"},
+		},
+		{
+			name:           "too long name",
+			api:            alertingProfileApi,
+			configName:     strings.Repeat("ä", 101),
+			payload:        []byte(`{}`),
+			wantViolations: []string{"maximum name length"},
+		},
+		{
+			name:       "name length of unknown api is not checked",
+			api:        unknownApi,
+			configName: strings.Repeat("a", 1000),
+			payload:    []byte(`{}`),
+		},
+		{
+			name:           "too many dashboard tiles",
+			api:            dashboardApi,
+			configName:     "name",
+			payload:        tiles(MaxDashboardTiles + 1),
+			wantViolations: []string{"maximum number of tiles"},
+		},
+		{
+			name:           "all violations are reported",
+			api:            dashboardApi,
+			configName:     strings.Repeat("a", 201),
+			payload:        tiles(MaxDashboardTiles + 1),
+			wantViolations: []string{"maximum name length", "maximum number of tiles"},
+		},
+		{
+			name:       "tiles of other apis are not checked",
+			api:        alertingProfileApi,
+			configName: "name",
+			payload:    tiles(MaxDashboardTiles + 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ClassicConfig(tt.api, tt.configName, tt.payload)
+
+			assert.Len(t, errs, len(tt.wantViolations))
+			for i, err := range errs {
+				var violation ConstraintViolationError
+				assert.ErrorAs(t, err, &violation)
+				assert.Equal(t, tt.wantViolations[i], violation.Constraint)
+				assert.Equal(t, tt.api.ID, violation.Api)
+			}
+		})
+	}
+}
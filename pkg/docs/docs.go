@@ -0,0 +1,241 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package docs renders human-readable documentation of the configs of monaco projects.
+package docs
+
+import (
+	"fmt"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/transform"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"sort"
+	"strings"
+)
+
+// maxValueLength is the length values are shortened to in descriptions of parameters
+const maxValueLength = 80
+
+// Inventory lists all configs of a set of projects.
+type Inventory struct {
+	// Environments are the environments the configs were loaded for, sorted
+	Environments []string
+	Projects     []Project
+}
+
+// Project holds the configs of a project, per type.
+type Project struct {
+	Id    string
+	Types []Type
+}
+
+// Type holds the configs of a config API or settings schema within a project.
+type Type struct {
+	Id      string
+	Configs []Config
+}
+
+// Config documents a config across all environments.
+type Config struct {
+	Coordinate coordinate.Coordinate
+	// Kind is the kind of the config type, e.g. 'classic-api' or 'settings'
+	Kind       string
+	Parameters []Parameter
+	// SkippedIn lists the environments the config is not deployed to
+	SkippedIn []string
+	// References are the configs this config depends on, sorted
+	References []coordinate.Coordinate
+}
+
+// Parameter documents a parameter of a config. Its description is the one of the first environment, overrides list
+// the environments defining the parameter differently.
+type Parameter struct {
+	Name        string
+	Description string
+	Overrides   []Override
+}
+
+// Override is the description of a parameter in an environment differing from the one of the first environment.
+type Override struct {
+	Environment string
+	Description string
+}
+
+// New returns the inventory of the configs of the given projects for the given environments.
+func New(projects []project.Project, environments []string) Inventory {
+	envs := append([]string(nil), environments...)
+	sort.Strings(envs)
+
+	inv := Inventory{Environments: envs}
+	for _, p := range sortedProjects(projects) {
+		inv.Projects = append(inv.Projects, newProject(p, envs))
+	}
+	return inv
+}
+
+func sortedProjects(projects []project.Project) []project.Project {
+	sorted := append([]project.Project(nil), projects...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+	return sorted
+}
+
+func newProject(p project.Project, envs []string) Project {
+	// configs of each coordinate, per environment
+	configs := make(map[coordinate.Coordinate]map[string]config.Config)
+	for _, env := range envs {
+		for _, cs := range p.Configs[env] {
+			for _, c := range cs {
+				if configs[c.Coordinate] == nil {
+					configs[c.Coordinate] = make(map[string]config.Config)
+				}
+				configs[c.Coordinate][env] = c
+			}
+		}
+	}
+
+	coordinates := make([]coordinate.Coordinate, 0, len(configs))
+	for c := range configs {
+		coordinates = append(coordinates, c)
+	}
+	sort.Slice(coordinates, func(i, j int) bool { return coordinates[i].String() < coordinates[j].String() })
+
+	result := Project{Id: p.Id}
+	for _, c := range coordinates {
+		if len(result.Types) == 0 || result.Types[len(result.Types)-1].Id != c.Type {
+			result.Types = append(result.Types, Type{Id: c.Type})
+		}
+		t := &result.Types[len(result.Types)-1]
+		t.Configs = append(t.Configs, newConfig(configs[c], envs))
+	}
+	return result
+}
+
+func newConfig(perEnv map[string]config.Config, envs []string) Config {
+	var base config.Config
+	var baseEnv string
+	for _, env := range envs {
+		if c, found := perEnv[env]; found {
+			base, baseEnv = c, env
+			break
+		}
+	}
+
+	result := Config{Coordinate: base.Coordinate, Kind: string(base.Type.ID())}
+
+	names := make(map[string]struct{})
+	references := make(map[coordinate.Coordinate]struct{})
+	for _, env := range envs {
+		c, found := perEnv[env]
+		if !found {
+			continue
+		}
+		if c.Skip {
+			result.SkippedIn = append(result.SkippedIn, env)
+		}
+		for name := range c.Parameters {
+			names[name] = struct{}{}
+		}
+		for _, r := range c.References() {
+			references[r] = struct{}{}
+		}
+	}
+
+	for _, name := range sortedKeys(names) {
+		p := Parameter{Name: name, Description: describe(base.Parameters[name])}
+		for _, env := range envs {
+			c, found := perEnv[env]
+			if !found || env == baseEnv {
+				continue
+			}
+			if d := describe(c.Parameters[name]); d != p.Description {
+				p.Overrides = append(p.Overrides, Override{Environment: env, Description: d})
+			}
+		}
+		result.Parameters = append(result.Parameters, p)
+	}
+
+	for r := range references {
+		result.References = append(result.References, r)
+	}
+	sort.Slice(result.References, func(i, j int) bool { return result.References[i].String() < result.References[j].String() })
+
+	return result
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// describe returns a human-readable description of the given parameter.
+func describe(p parameter.Parameter) string {
+	switch p := p.(type) {
+	case nil:
+		return "not set"
+	case *value.ValueParameter:
+		return shorten(fmt.Sprintf("%v", p.Value))
+	case *reference.ReferenceParameter:
+		return fmt.Sprintf("`%s` of config %s", p.Property, p.Config)
+	case *environment.EnvironmentVariableParameter:
+		if p.HasDefaultValue {
+			return fmt.Sprintf("environment variable %s (default: %s)", p.Name, shorten(p.DefaultValue))
+		}
+		return fmt.Sprintf("environment variable %s", p.Name)
+	case *transform.TransformedParameter:
+		names := make([]string, len(p.Transformations))
+		for i, t := range p.Transformations {
+			names[i] = t.Name
+		}
+		return fmt.Sprintf("%s, transformed by %s", describe(p.Parameter), strings.Join(names, ", "))
+	default:
+		if refs := p.GetReferences(); len(refs) > 0 {
+			configs := make([]string, len(refs))
+			for i, r := range refs {
+				configs[i] = r.Config.String()
+			}
+			return fmt.Sprintf("%s parameter referencing %s", p.GetType(), strings.Join(configs, ", "))
+		}
+		return fmt.Sprintf("%s parameter", p.GetType())
+	}
+}
+
+func shorten(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if r := []rune(s); len(r) > maxValueLength {
+		return string(r[:maxValueLength-3]) + "..."
+	}
+	return s
+}
+
+// Count returns the number of configs in the inventory
+func (inv Inventory) Count() int {
+	n := 0
+	for _, p := range inv.Projects {
+		for _, t := range p.Types {
+			n += len(t.Configs)
+		}
+	}
+	return n
+}
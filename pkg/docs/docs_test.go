@@ -0,0 +1,127 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docs
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/environment"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"gotest.tools/assert"
+	"strings"
+	"testing"
+)
+
+var (
+	zone    = coordinate.Coordinate{Project: "infra", Type: "management-zone", ConfigId: "zone"}
+	profile = coordinate.Coordinate{Project: "infra", Type: "alerting-profile", ConfigId: "profile"}
+)
+
+func testProjects(t *testing.T) []project.Project {
+	zoneConfig := func(name string, skip bool) config.Config {
+		return config.Config{
+			Coordinate: zone,
+			Type:       config.ClassicApiType{Api: zone.Type},
+			Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: name}},
+			Skip:       skip,
+		}
+	}
+	profileConfig := config.Config{
+		Coordinate: profile,
+		Type:       config.ClassicApiType{Api: profile.Type},
+		Parameters: config.Parameters{
+			config.NameParameter: &environment.EnvironmentVariableParameter{Name: "PROFILE_NAME"},
+			"zone":               reference.NewWithCoordinate(zone, "id"),
+		},
+	}
+
+	return []project.Project{{
+		Id: "infra",
+		Configs: project.ConfigsPerTypePerEnvironments{
+			"prod": {
+				zone.Type:    {zoneConfig("Production", false)},
+				profile.Type: {profileConfig},
+			},
+			"dev": {
+				zone.Type:    {zoneConfig("Development", true)},
+				profile.Type: {profileConfig},
+			},
+		},
+	}}
+}
+
+func TestNew(t *testing.T) {
+	inv := New(testProjects(t), []string{"prod", "dev"})
+
+	assert.DeepEqual(t, inv.Environments, []string{"dev", "prod"})
+	assert.Equal(t, inv.Count(), 2)
+	assert.Equal(t, len(inv.Projects), 1)
+
+	types := inv.Projects[0].Types
+	assert.Equal(t, len(types), 2)
+	assert.Equal(t, types[0].Id, profile.Type)
+	assert.Equal(t, types[1].Id, zone.Type)
+
+	p := types[0].Configs[0]
+	assert.DeepEqual(t, p.References, []coordinate.Coordinate{zone})
+	assert.DeepEqual(t, p.Parameters, []Parameter{
+		{Name: config.NameParameter, Description: "environment variable PROFILE_NAME"},
+		{Name: "zone", Description: "`id` of config infra:management-zone:zone"},
+	})
+
+	z := types[1].Configs[0]
+	assert.DeepEqual(t, z.SkippedIn, []string{"dev"})
+	assert.DeepEqual(t, z.Parameters, []Parameter{
+		{Name: config.NameParameter, Description: "Development", Overrides: []Override{{Environment: "prod", Description: "Production"}}},
+	})
+}
+
+func TestNew_ShortensLongValues(t *testing.T) {
+	long := strings.Repeat("ä", 100)
+	d := describe(&value.ValueParameter{Value: long})
+	assert.Equal(t, len([]rune(d)), maxValueLength)
+	assert.Assert(t, strings.HasSuffix(d, "..."))
+}
+
+func TestMarkdown(t *testing.T) {
+	md := string(Markdown(New(testProjects(t), []string{"prod", "dev"})))
+
+	assert.Assert(t, strings.Contains(md, "## Project `infra`"))
+	assert.Assert(t, strings.Contains(md, "| `management-zone` | 1 |"))
+	assert.Assert(t, strings.Contains(md, "| `name` | Development | `prod`: Production |"))
+	assert.Assert(t, strings.Contains(md, "Skipped in `dev`."))
+	assert.Assert(t, strings.Contains(md, "```mermaid\nflowchart LR\n"))
+	assert.Assert(t, strings.Contains(md, "  c0[\"infra:alerting-profile:profile\"]\n  c1[\"infra:management-zone:zone\"]\n  c0 --> c1\n"))
+}
+
+func TestHTML(t *testing.T) {
+	html, err := HTML(New(testProjects(t), []string{"prod", "dev"}))
+	assert.NilError(t, err)
+
+	assert.Assert(t, strings.Contains(string(html), "<h2>Project <code>infra</code></h2>"))
+	assert.Assert(t, strings.Contains(string(html), `<pre class="mermaid">`))
+	assert.Assert(t, strings.Contains(string(html), "c0 --&gt; c1"))
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := Render(Inventory{}, "pdf")
+	assert.ErrorContains(t, err, "unknown format")
+}
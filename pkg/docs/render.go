@@ -0,0 +1,196 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"html/template"
+	"strings"
+)
+
+// Formats the inventory can be rendered in
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Render renders the inventory in the given format.
+func Render(inv Inventory, format string) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(inv), nil
+	case FormatHTML:
+		return HTML(inv)
+	default:
+		return nil, fmt.Errorf("unknown format %q! expected %q or %q", format, FormatMarkdown, FormatHTML)
+	}
+}
+
+// Markdown renders the inventory as Markdown. The dependency graph is a Mermaid diagram, which is rendered as image
+// by common Git hosting platforms.
+func Markdown(inv Inventory) []byte {
+	var b strings.Builder
+
+	b.WriteString("# Configuration inventory\n\n")
+	fmt.Fprintf(&b, "%d configs in %d projects, documented for the environments %s.\n\n", inv.Count(), len(inv.Projects), codeList(inv.Environments))
+
+	for _, p := range inv.Projects {
+		fmt.Fprintf(&b, "## Project `%s`\n\n", p.Id)
+		b.WriteString("| Type | Configs |\n|---|---|\n")
+		for _, t := range p.Types {
+			fmt.Fprintf(&b, "| `%s` | %d |\n", t.Id, len(t.Configs))
+		}
+		b.WriteString("\n")
+
+		for _, t := range p.Types {
+			fmt.Fprintf(&b, "### `%s`\n\n", t.Id)
+			for _, c := range t.Configs {
+				fmt.Fprintf(&b, "#### `%s`\n\n", c.Coordinate.ConfigId)
+				if len(c.SkippedIn) > 0 {
+					fmt.Fprintf(&b, "Skipped in %s.\n\n", codeList(c.SkippedIn))
+				}
+				if len(c.Parameters) > 0 {
+					b.WriteString("| Parameter | Value | Environment overrides |\n|---|---|---|\n")
+					for _, param := range c.Parameters {
+						overrides := make([]string, len(param.Overrides))
+						for i, o := range param.Overrides {
+							overrides[i] = fmt.Sprintf("`%s`: %s", o.Environment, markdownCell(o.Description))
+						}
+						fmt.Fprintf(&b, "| `%s` | %s | %s |\n", param.Name, markdownCell(param.Description), strings.Join(overrides, "<br>"))
+					}
+					b.WriteString("\n")
+				}
+				if len(c.References) > 0 {
+					b.WriteString("Depends on:\n\n")
+					for _, r := range c.References {
+						fmt.Fprintf(&b, "- `%s`\n", r)
+					}
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+
+	b.WriteString("## Dependencies\n\n```mermaid\n")
+	b.WriteString(mermaidGraph(inv))
+	b.WriteString("```\n")
+
+	return []byte(b.String())
+}
+
+func codeList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "`" + v + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// mermaidGraph returns a Mermaid flowchart with an edge from each config to each config it depends on.
+func mermaidGraph(inv Inventory) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	nodes := make(map[coordinate.Coordinate]string)
+	node := func(c coordinate.Coordinate) string {
+		if id, found := nodes[c]; found {
+			return id
+		}
+		id := fmt.Sprintf("c%d", len(nodes))
+		nodes[c] = id
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, strings.ReplaceAll(c.String(), `"`, "#quot;"))
+		return id
+	}
+
+	for _, p := range inv.Projects {
+		for _, t := range p.Types {
+			for _, c := range t.Configs {
+				from := node(c.Coordinate)
+				for _, r := range c.References {
+					fmt.Fprintf(&b, "  %s --> %s\n", from, node(r))
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Configuration inventory</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+code { background: #f4f4f4; }
+</style>
+</head>
+<body>
+<h1>Configuration inventory</h1>
+<p>{{.Inventory.Count}} configs in {{len .Inventory.Projects}} projects, documented for the environments {{range $i, $e := .Inventory.Environments}}{{if $i}}, {{end}}<code>{{$e}}</code>{{end}}.</p>
+{{range .Inventory.Projects}}
+<h2>Project <code>{{.Id}}</code></h2>
+<table>
+<tr><th>Type</th><th>Configs</th></tr>
+{{range .Types}}<tr><td><code>{{.Id}}</code></td><td>{{len .Configs}}</td></tr>
+{{end}}</table>
+{{range .Types}}
+<h3><code>{{.Id}}</code></h3>
+{{range .Configs}}
+<h4><code>{{.Coordinate.ConfigId}}</code></h4>
+{{if .SkippedIn}}<p>Skipped in {{range $i, $e := .SkippedIn}}{{if $i}}, {{end}}<code>{{$e}}</code>{{end}}.</p>{{end}}
+{{if .Parameters}}<table>
+<tr><th>Parameter</th><th>Value</th><th>Environment overrides</th></tr>
+{{range .Parameters}}<tr><td><code>{{.Name}}</code></td><td>{{.Description}}</td><td>{{range $i, $o := .Overrides}}{{if $i}}<br>{{end}}<code>{{$o.Environment}}</code>: {{$o.Description}}{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .References}}<p>Depends on:</p>
+<ul>
+{{range .References}}<li><code>{{.}}</code></li>
+{{end}}</ul>{{end}}
+{{end}}{{end}}{{end}}
+<h2>Dependencies</h2>
+<pre class="mermaid">
+{{.Graph}}</pre>
+<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+mermaid.initialize({ startOnLoad: true });
+</script>
+</body>
+</html>
+`))
+
+// HTML renders the inventory as a single HTML page. The dependency graph is rendered by Mermaid, which is loaded from
+// a CDN when the page is viewed.
+func HTML(inv Inventory) ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		Inventory Inventory
+		Graph     string
+	}{inv, mermaidGraph(inv)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render documentation: %w", err)
+	}
+	return buf.Bytes(), nil
+}
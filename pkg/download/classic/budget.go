@@ -0,0 +1,94 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classic
+
+import (
+	"context"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"sync"
+	"time"
+)
+
+// maxBudgetInterval caps the interval between requests of an API when slowing down after rate limited requests
+const maxBudgetInterval = 10 * time.Second
+
+// apiBudget limits the rate of requests sent for a single API. It slows down adaptively when requests are rate
+// limited: further requests are suspended for the time requested by the environment, and the rate is halved.
+//
+// A budget without rate limits the requests only while suspended.
+type apiBudget struct {
+	apiId string
+
+	mutex sync.Mutex
+	// interval is the minimum time between two requests, 0 if the rate is not limited
+	interval time.Duration
+	// next is the earliest time the next request may be sent
+	next time.Time
+
+	now func() time.Time
+}
+
+func newAPIBudget(apiId string, qps float64) *apiBudget {
+	b := &apiBudget{apiId: apiId, now: time.Now}
+	if qps > 0 {
+		b.interval = time.Duration(float64(time.Second) / qps)
+	}
+	return b
+}
+
+// wait blocks until the next request may be sent, or the context is done.
+func (b *apiBudget) wait(ctx context.Context) error {
+	b.mutex.Lock()
+	now := b.now()
+	start := b.next
+	if start.Before(now) {
+		start = now
+	}
+	b.next = start.Add(b.interval)
+	b.mutex.Unlock()
+
+	d := start.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// slowDown suspends further requests for the given duration and halves the rate. It is a rest.RateLimitListener.
+func (b *apiBudget) slowDown(wait time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if resume := b.now().Add(wait); b.next.Before(resume) {
+		b.next = resume
+	}
+	if b.interval > 0 && b.interval < maxBudgetInterval {
+		b.interval *= 2
+		if b.interval > maxBudgetInterval {
+			b.interval = maxBudgetInterval
+		}
+		log.Debug("\tRequests of type '%v' are rate limited, slowing down to one request every %v", b.apiId, b.interval)
+	}
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classic
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAPIBudget_WaitSpacesRequests(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newAPIBudget("api", 10)
+	b.now = func() time.Time { return now }
+
+	b.next = now.Add(-time.Second) // pretend the first request may be sent immediately
+	assert.NoError(t, b.wait(context.Background()))
+	assert.Equal(t, now.Add(100*time.Millisecond), b.next)
+}
+
+func TestAPIBudget_UnlimitedBudgetDoesNotWait(t *testing.T) {
+	b := newAPIBudget("api", 0)
+
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, b.wait(context.Background()))
+	}
+	assert.Zero(t, b.interval)
+}
+
+func TestAPIBudget_SlowDownSuspendsAndHalvesRate(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newAPIBudget("api", 10)
+	b.now = func() time.Time { return now }
+
+	b.slowDown(5 * time.Second)
+	assert.Equal(t, now.Add(5*time.Second), b.next)
+	assert.Equal(t, 200*time.Millisecond, b.interval)
+
+	b.slowDown(time.Second)
+	assert.Equal(t, now.Add(5*time.Second), b.next, "shorter suspension must not shorten an earlier one")
+	assert.Equal(t, 400*time.Millisecond, b.interval)
+}
+
+func TestAPIBudget_SlowDownCapsInterval(t *testing.T) {
+	b := newAPIBudget("api", 0.15)
+
+	b.slowDown(0)
+	assert.Equal(t, maxBudgetInterval, b.interval)
+}
+
+func TestAPIBudget_SlowDownSuspendsUnlimitedBudget(t *testing.T) {
+	b := newAPIBudget("api", 0)
+
+	b.slowDown(time.Hour)
+	assert.Zero(t, b.interval)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, b.wait(ctx), context.DeadlineExceeded)
+}
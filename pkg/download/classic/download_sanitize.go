@@ -21,6 +21,9 @@ var apiSanitizeFunctions = map[string]func(properties map[string]interface{}) ma
 	"service-detection-full-web-request":   removeOrderProperty,
 	"service-detection-opaque-web-service": removeOrderProperty,
 	"service-detection-opaque-web-request": removeOrderProperty,
+	"key-user-actions-web": func(properties map[string]interface{}) map[string]interface{} {
+		return removeByPath(properties, []string{"meIdentifier"})
+	},
 	"maintenance-window": func(properties map[string]interface{}) map[string]interface{} {
 		if s, ok := properties["scope"].(map[string]interface{}); ok {
 			var emptyEntities, emptyMatches bool
@@ -17,8 +17,15 @@
 package classic
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/ownership"
+	"net/http"
 	"sync"
 	"time"
 
@@ -30,10 +37,11 @@ import (
 	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
 )
 
-func DownloadAllConfigs(apisToDownload api.APIs, client client.Client, projectName string) project.ConfigsPerType {
-	return NewDownloader(client).DownloadAll(apisToDownload, projectName)
+func DownloadAllConfigs(ctx context.Context, apisToDownload api.APIs, client client.Client, projectName string, opts ...func(*Downloader)) project.ConfigsPerType {
+	return NewDownloader(client, opts...).DownloadAll(ctx, apisToDownload, projectName)
 }
 
 // Downloader is responsible for downloading classic Dynatrace APIs
@@ -45,6 +53,21 @@ type Downloader struct {
 	// client is the actual rest client used to call
 	// the dynatrace APIs
 	client client.Client
+
+	// workers limits the number of configs downloaded concurrently. If nil, all configs are downloaded concurrently.
+	workers chan struct{}
+
+	// qpsPerAPI limits the requests sent per second for each API. If 0, the rate is not limited.
+	qpsPerAPI float64
+
+	// configIds, if set, restricts the download to the configs of the given IDs per API
+	configIds map[string][]string
+
+	// report, if set, records the results of the download per API
+	report *report.Report
+
+	// ownership selects configs by whether they are marked as managed by monaco
+	ownership ownership.Filter
 }
 
 // WithAPIFilters sets the api filters for the Downloader
@@ -54,6 +77,48 @@ func WithAPIFilters(apiFilters map[string]apiFilter) func(*Downloader) {
 	}
 }
 
+// WithWorkers limits the number of configs downloaded concurrently. Values <= 0 do not limit downloads.
+func WithWorkers(workers int) func(*Downloader) {
+	return func(d *Downloader) {
+		if workers > 0 {
+			d.workers = make(chan struct{}, workers)
+		} else {
+			d.workers = nil
+		}
+	}
+}
+
+// WithQPSPerAPI limits the requests sent per second for each API. Values <= 0 do not limit the rate. Regardless of
+// the limit, requests of an API are suspended when the environment rate limits them, and the rate is halved.
+func WithQPSPerAPI(qps float64) func(*Downloader) {
+	return func(d *Downloader) {
+		d.qpsPerAPI = qps
+	}
+}
+
+// WithConfigIds restricts the download to the configs of the given IDs, grouped by API ID. Configs of APIs not
+// contained in the map are not downloaded at all.
+func WithConfigIds(ids map[string][]string) func(*Downloader) {
+	return func(d *Downloader) {
+		d.configIds = ids
+	}
+}
+
+// WithReport records the results of the download per API in the given report, e.g. which configs were filtered
+func WithReport(r *report.Report) func(*Downloader) {
+	return func(d *Downloader) {
+		d.report = r
+	}
+}
+
+// WithOwnershipFilter restricts the download to configs managed by monaco or not, recognized by the ownership marker
+// in their description.
+func WithOwnershipFilter(f ownership.Filter) func(*Downloader) {
+	return func(d *Downloader) {
+		d.ownership = f
+	}
+}
+
 // NewDownloader creates a new Downloader
 func NewDownloader(client client.Client, opts ...func(*Downloader)) *Downloader {
 	c := &Downloader{
@@ -69,7 +134,7 @@ func NewDownloader(client client.Client, opts ...func(*Downloader)) *Downloader
 // DownloadAllConfigs downloads all specified APIs from a given environment.
 //
 // See package documentation for implementation details.
-func (d *Downloader) DownloadAll(apisToDownload api.APIs, projectName string) project.ConfigsPerType {
+func (d *Downloader) DownloadAll(ctx context.Context, apisToDownload api.APIs, projectName string) project.ConfigsPerType {
 	results := make(project.ConfigsPerType, len(apisToDownload))
 	mutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
@@ -81,23 +146,38 @@ func (d *Downloader) DownloadAll(apisToDownload api.APIs, projectName string) pr
 		currentApi := currentApi // prevent data race
 		go func() {
 			defer wg.Done()
-			configsToDownload, err := d.findConfigsToDownload(currentApi)
+
+			budget := newAPIBudget(currentApi.ID, d.qpsPerAPI)
+			ctx := log.WithFields(rest.WithRateLimitListener(ctx, budget.slowDown), log.TypeField(currentApi.ID))
+
+			if currentApi.HasParent() {
+				configs := d.downloadConfigsOfParentScopedAPI(ctx, currentApi, budget, projectName)
+				if len(configs) > 0 {
+					mutex.Lock()
+					results[currentApi.ID] = configs
+					mutex.Unlock()
+				}
+				return
+			}
+
+			configsToDownload, err := d.findConfigsToDownload(ctx, currentApi, budget)
 			if err != nil {
-				log.Error("\tFailed to fetch configs of type '%v', skipping download of this type. Reason: %v", currentApi.ID, err)
+				log.WithCtxFields(ctx).Error("\tFailed to fetch configs of type '%v', skipping download of this type. Reason: %v", currentApi.ID, err)
+				d.report.FailType(currentApi.ID, err)
 				return
 			}
 			// filter all configs we do not want to download. All remaining will be downloaded
 			configsToDownload = d.filterConfigsToSkip(currentApi, configsToDownload)
 
 			if len(configsToDownload) == 0 {
-				log.Debug("\tNo configs of type '%v' to download", currentApi.ID)
+				log.WithCtxFields(ctx).Debug("\tNo configs of type '%v' to download", currentApi.ID)
 				return
 			}
 
-			log.Debug("\tFound %d configs of type '%v' to download", len(configsToDownload), currentApi.ID)
-			configs := d.downloadConfigsOfAPI(currentApi, configsToDownload, projectName)
+			log.WithCtxFields(ctx).Debug("\tFound %d configs of type '%v' to download", len(configsToDownload), currentApi.ID)
+			configs := d.downloadConfigsOfAPI(ctx, currentApi, budget, configsToDownload, projectName)
 
-			log.Debug("\tFinished downloading all configs of type '%v'", currentApi.ID)
+			log.WithCtxFields(ctx).Debug("\tFinished downloading all configs of type '%v'", currentApi.ID)
 			if len(configs) > 0 {
 				mutex.Lock()
 				results[currentApi.ID] = configs
@@ -115,33 +195,108 @@ func (d *Downloader) DownloadAll(apisToDownload api.APIs, projectName string) pr
 	return results
 }
 
-func (d *Downloader) downloadConfigsOfAPI(api api.API, values []client.Value, projectName string) []config.Config {
+// downloadConfigsOfParentScopedAPI downloads the configs of a parent-scoped API for all objects of its parent API.
+// The ID of the parent object is stored as scope of the configs, which is resolved to a reference if the parent
+// config is downloaded, too. As config IDs are only unique per parent object, the ID of the parent is prepended.
+func (d *Downloader) downloadConfigsOfParentScopedAPI(ctx context.Context, currentApi api.API, budget *apiBudget, projectName string) []config.Config {
+	parentApi, found := api.NewAPIs()[currentApi.Parent]
+	if !found {
+		log.WithCtxFields(ctx).Error("\tUnknown parent '%v' of type '%v', skipping download of this type", currentApi.Parent, currentApi.ID)
+		d.report.FailType(currentApi.ID, fmt.Errorf("unknown parent %q", currentApi.Parent))
+		return nil
+	}
+
+	if err := budget.wait(ctx); err != nil {
+		log.WithCtxFields(ctx).Error("\tFailed to fetch parent configs of type '%v', skipping download of type '%v'. Reason: %v", parentApi.ID, currentApi.ID, err)
+		d.report.FailType(currentApi.ID, fmt.Errorf("failed to fetch parent configs of type %q: %w", parentApi.ID, err))
+		return nil
+	}
+	parents, err := d.client.ListConfigs(ctx, parentApi)
+	if err != nil {
+		log.WithCtxFields(ctx).Error("\tFailed to fetch parent configs of type '%v', skipping download of type '%v'. Reason: %v", parentApi.ID, currentApi.ID, err)
+		d.report.FailType(currentApi.ID, fmt.Errorf("failed to fetch parent configs of type %q: %w", parentApi.ID, err))
+		return nil
+	}
+
+	var results []config.Config
+	for _, parent := range parents {
+		scopedApi := currentApi.ApplyParentObjectID(parent.Id)
+
+		if err := budget.wait(ctx); err != nil {
+			log.WithCtxFields(ctx).Error("\tFailed to fetch configs of type '%v' of %v '%v', skipping them. Reason: %v", currentApi.ID, parentApi.ID, parent.Id, err)
+			d.report.Fail(currentApi.ID, parent.Id, parent.Name, fmt.Errorf("failed to fetch configs of %s %q: %w", parentApi.ID, parent.Id, err))
+			continue
+		}
+		configsToDownload, err := d.client.ListConfigs(ctx, scopedApi)
+		if err != nil {
+			log.WithCtxFields(ctx).Error("\tFailed to fetch configs of type '%v' of %v '%v', skipping them. Reason: %v", currentApi.ID, parentApi.ID, parent.Id, err)
+			d.report.Fail(currentApi.ID, parent.Id, parent.Name, fmt.Errorf("failed to fetch configs of %s %q: %w", parentApi.ID, parent.Id, err))
+			continue
+		}
+		configsToDownload = d.filterConfigsToSkip(currentApi, configsToDownload)
+
+		log.WithCtxFields(ctx).Debug("\tFound %d configs of type '%v' of %v '%v' to download", len(configsToDownload), currentApi.ID, parentApi.ID, parent.Id)
+		configs := d.downloadConfigsOfAPI(ctx, scopedApi, budget, configsToDownload, projectName)
+		for i := range configs {
+			configs[i].Coordinate.ConfigId = parent.Id + "-" + configs[i].Coordinate.ConfigId
+			configs[i].Parameters[config.ScopeParameter] = &valueParam.ValueParameter{Value: parent.Id}
+		}
+		results = append(results, configs...)
+	}
+
+	log.WithCtxFields(ctx).Debug("\tFinished downloading all configs of type '%v'", currentApi.ID)
+	return results
+}
+
+func (d *Downloader) downloadConfigsOfAPI(ctx context.Context, api api.API, budget *apiBudget, values []client.Value, projectName string) []config.Config {
 	results := make([]config.Config, 0, len(values))
 	mutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
 	wg.Add(len(values))
 
-	for _, value := range values {
-		value := value
+	for i, value := range values {
+		i, value := i, value
+		d.acquireWorker()
 		go func() {
 			defer wg.Done()
-			downloadedJson, err := d.downloadAndUnmarshalConfig(api, value)
+			defer d.releaseWorker()
+			downloadedJson, err := d.downloadAndUnmarshalConfig(ctx, api, budget, value)
+			var unsupported unsupportedContentError
+			if errors.As(err, &unsupported) {
+				log.WithCtxFields(ctx).Warn("Skipping config '%v' (%v) in api '%v': %v", value.Id, value.Name, api.ID, err)
+				d.report.Skip(api.ID, value.Id, value.Name, err.Error())
+				return
+			}
 			if err != nil {
-				log.Error("Error fetching config '%v' in api '%v': %v", value.Id, api.ID, err)
+				log.WithCtxFields(ctx).Error("Error fetching config '%v' in api '%v': %v", value.Id, api.ID, err)
+				d.report.Fail(api.ID, value.Id, value.Name, err)
 				return
 			}
 
 			if !d.skipPersist(api, downloadedJson) {
-				log.Debug("\tSkipping persisting config %v (%v) in API %v", value.Id, value.Name, api.ID)
+				log.WithCtxFields(ctx).Debug("\tSkipping persisting config %v (%v) in API %v", value.Id, value.Name, api.ID)
+				d.report.Filter(api.ID, value.Id, value.Name, d.apiFilters[api.ID].filterReason())
+				return
+			}
+
+			if !d.ownership.Selects(ownership.IsMarked(downloadedJson)) {
+				log.WithCtxFields(ctx).Debug("\tSkipping config %v (%v) in API %v: %s", value.Id, value.Name, api.ID, d.ownership.Reason())
+				d.report.Filter(api.ID, value.Id, value.Name, d.ownership.Reason())
 				return
 			}
 
 			c, err := d.createConfigForDownloadedJson(downloadedJson, api, value, projectName)
 			if err != nil {
-				log.Error("Error creating config for %v in api %v: %v", value.Id, api.ID, err)
+				log.WithCtxFields(ctx).Error("Error creating config for %v in api %v: %v", value.Id, api.ID, err)
+				d.report.Fail(api.ID, value.Id, value.Name, err)
 				return
 			}
 
+			if api.Ordered {
+				// values are listed in the order they are applied in the environment
+				c.Position = i + 1
+			}
+
 			mutex.Lock()
 			results = append(results, c)
 			mutex.Unlock()
@@ -152,8 +307,24 @@ func (d *Downloader) downloadConfigsOfAPI(api api.API, values []client.Value, pr
 	return results
 }
 
-func (d *Downloader) downloadAndUnmarshalConfig(theApi api.API, value client.Value) (map[string]interface{}, error) {
-	response, err := d.client.ReadConfigById(theApi, value.Id)
+// acquireWorker blocks until a worker is available to download a config, if the number of workers is limited.
+func (d *Downloader) acquireWorker() {
+	if d.workers != nil {
+		d.workers <- struct{}{}
+	}
+}
+
+func (d *Downloader) releaseWorker() {
+	if d.workers != nil {
+		<-d.workers
+	}
+}
+
+func (d *Downloader) downloadAndUnmarshalConfig(ctx context.Context, theApi api.API, budget *apiBudget, value client.Value) (map[string]interface{}, error) {
+	if err := budget.wait(ctx); err != nil {
+		return nil, err
+	}
+	response, err := d.client.ReadConfigById(ctx, theApi, value.Id)
 
 	if err != nil {
 		return nil, err
@@ -162,12 +333,62 @@ func (d *Downloader) downloadAndUnmarshalConfig(theApi api.API, value client.Val
 	var data map[string]interface{}
 	err = json.Unmarshal(response, &data)
 	if err != nil {
-		return nil, err
+		return nil, newUnsupportedContentError(response, err)
+	}
+
+	if theApi.ID == "dashboard" {
+		if err := budget.wait(ctx); err != nil {
+			return nil, err
+		}
+		d.addShareSettings(ctx, value, data)
 	}
 
 	return data, nil
 }
 
+// unsupportedContentError is returned for configs whose payload is not a JSON object, e.g. binary extension
+// archives. Such configs can not be represented as templates, thus they are skipped.
+type unsupportedContentError struct {
+	contentType string
+	err         error
+}
+
+func newUnsupportedContentError(payload []byte, err error) unsupportedContentError {
+	contentType := "application/json"
+	if !json.Valid(payload) {
+		contentType = http.DetectContentType(payload)
+	}
+	return unsupportedContentError{contentType: contentType, err: err}
+}
+
+func (e unsupportedContentError) Error() string {
+	return fmt.Sprintf("payload is not a JSON object (detected content type %q): %v", e.contentType, e.err)
+}
+
+func (e unsupportedContentError) Unwrap() error {
+	return e.err
+}
+
+// addShareSettings adds the share settings of the given dashboard to its payload, as they are not part of the
+// dashboard itself. If they can't be read, the dashboard is downloaded without them.
+func (d *Downloader) addShareSettings(ctx context.Context, dashboard client.Value, data map[string]interface{}) {
+	response, err := d.client.GetDashboardShareSettings(ctx, dashboard.Id)
+	if err != nil {
+		log.WithCtxFields(ctx).Warn("Failed to download share settings of dashboard %q (%s), downloading it without them: %v", dashboard.Name, dashboard.Id, err)
+		return
+	}
+
+	var shareSettings map[string]interface{}
+	if err := json.Unmarshal(response, &shareSettings); err != nil {
+		log.WithCtxFields(ctx).Warn("Failed to parse share settings of dashboard %q (%s), downloading it without them: %v", dashboard.Name, dashboard.Id, err)
+		return
+	}
+
+	// the ID of the dashboard is set on deployment
+	delete(shareSettings, "id")
+	data[api.DashboardShareSettingsProperty] = shareSettings
+}
+
 func (d *Downloader) createConfigForDownloadedJson(mappedJson map[string]interface{}, theApi api.API, value client.Value, projectId string) (config.Config, error) {
 	templ, err := d.createTemplate(mappedJson, value, theApi.ID)
 	if err != nil {
@@ -202,16 +423,19 @@ func (d *Downloader) createTemplate(mappedJson map[string]interface{}, value cli
 	return templ, nil
 }
 
-func (d *Downloader) findConfigsToDownload(currentApi api.API) ([]client.Value, error) {
+func (d *Downloader) findConfigsToDownload(ctx context.Context, currentApi api.API, budget *apiBudget) ([]client.Value, error) {
 	if currentApi.SingleConfiguration {
-		log.Debug("\tFetching singleton-configuration '%v'", currentApi.ID)
+		log.WithCtxFields(ctx).Debug("\tFetching singleton-configuration '%v'", currentApi.ID)
 
 		// singleton-config. We use the api-id as mock-id
 		singletonConfigToDownload := client.Value{Id: currentApi.ID, Name: currentApi.ID}
 		return []client.Value{singletonConfigToDownload}, nil
 	}
-	log.Debug("\tFetching all '%v' configs", currentApi.ID)
-	return d.client.ListConfigs(currentApi)
+	log.WithCtxFields(ctx).Debug("\tFetching all '%v' configs", currentApi.ID)
+	if err := budget.wait(ctx); err != nil {
+		return nil, err
+	}
+	return d.client.ListConfigs(ctx, currentApi)
 }
 
 func (d *Downloader) skipPersist(a api.API, json map[string]interface{}) bool {
@@ -220,22 +444,29 @@ func (d *Downloader) skipPersist(a api.API, json map[string]interface{}) bool {
 	}
 	return true
 }
-func (d *Downloader) skipDownload(a api.API, value client.Value) bool {
-	if cases := d.apiFilters[a.ID]; cases.shouldBeSkippedPreDownload != nil {
-		return cases.shouldBeSkippedPreDownload(value)
+
+// skipDownload returns true and the reason if the given config is not downloaded
+func (d *Downloader) skipDownload(a api.API, value client.Value) (bool, string) {
+	if d.configIds != nil && !slices.Contains(d.configIds[a.ID], value.Id) {
+		return true, "not among the requested IDs"
+	}
+
+	if cases := d.apiFilters[a.ID]; cases.shouldBeSkippedPreDownload != nil && cases.shouldBeSkippedPreDownload(value) {
+		return true, cases.filterReason()
 	}
 
-	return false
+	return false, ""
 }
 
 func (d *Downloader) filterConfigsToSkip(a api.API, value []client.Value) []client.Value {
 	valuesToDownload := make([]client.Value, 0, len(value))
 
 	for _, value := range value {
-		if !d.skipDownload(a, value) {
+		if skip, reason := d.skipDownload(a, value); !skip {
 			valuesToDownload = append(valuesToDownload, value)
 		} else {
 			log.Debug("Skipping download of config  '%v' of API '%v'", value.Id, a.ID)
+			d.report.Filter(a.ID, value.Id, value.Name, reason)
 		}
 	}
 
@@ -17,9 +17,13 @@
 package classic
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -27,29 +31,29 @@ import (
 
 func TestDownloadAllConfigs_FailedToFindConfigsToDownload(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).Return([]client.Value{}, fmt.Errorf("NO"))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Return([]client.Value{}, fmt.Errorf("NO"))
 	downloader := NewDownloader(c)
 	testAPI := api.API{ID: "API_ID", URLPath: "API_PATH", NonUniqueName: true}
 	apiMap := api.APIs{"API_ID": testAPI}
 
-	assert.Len(t, downloader.DownloadAll(apiMap, "project"), 0)
+	assert.Len(t, downloader.DownloadAll(context.TODO(), apiMap, "project"), 0)
 }
 
 func TestDownloadAll_NoConfigsToDownloadFound(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).Return([]client.Value{}, nil)
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Return([]client.Value{}, nil)
 	downloader := NewDownloader(c)
 	testAPI := api.API{ID: "API_ID", URLPath: "API_PATH", NonUniqueName: true}
 
 	apiMap := api.APIs{"API_ID": testAPI}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 0)
 }
 
 func TestDownloadAll_ConfigsDownloaded(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -60,18 +64,91 @@ func TestDownloadAll_ConfigsDownloaded(t *testing.T) {
 	downloader := NewDownloader(c)
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 2)
 }
 
+func TestDownloadAll_OrderedAPICapturesPosition(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Return([]client.Value{{Id: "ID_1", Name: "NAME_1"}, {Id: "ID_2", Name: "NAME_2"}}, nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil).Times(2)
+	downloader := NewDownloader(c)
+	testAPI := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true, Ordered: true}
+
+	configurations := downloader.DownloadAll(context.TODO(), api.APIs{"API_ID_1": testAPI}, "project")
+	assert.Len(t, configurations["API_ID_1"], 2)
+
+	positions := make(map[string]int)
+	for _, conf := range configurations["API_ID_1"] {
+		positions[conf.Coordinate.ConfigId] = conf.Position
+	}
+	assert.Equal(t, map[string]int{"ID_1": 1, "ID_2": 2}, positions)
+}
+
+func TestDownloadAll_SkipsAndReportsUnsupportedContent(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Return([]client.Value{{Id: "JSON", Name: "json"}, {Id: "ZIP", Name: "zip"}, {Id: "LIST", Name: "list"}}, nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ api.API, id string) ([]byte, error) {
+		switch id {
+		case "ZIP":
+			return []byte("PK\x03\x04\x14\x00\x08\x00"), nil
+		case "LIST":
+			return []byte(`[{}]`), nil
+		}
+		return []byte(`{}`), nil
+	}).Times(3)
+
+	r := report.New()
+	downloader := NewDownloader(c, WithReport(r))
+	testAPI := api.API{ID: "API_ID", URLPath: "API_PATH", NonUniqueName: true}
+
+	configurations := downloader.DownloadAll(context.TODO(), api.APIs{"API_ID": testAPI}, "project")
+	assert.Len(t, configurations["API_ID"], 1)
+
+	assert.Len(t, r.Types["API_ID"].Skipped, 2)
+	reasons := make(map[string]string)
+	for _, s := range r.Types["API_ID"].Skipped {
+		reasons[s.Id] = s.Reason
+	}
+	assert.Contains(t, reasons["ZIP"], `"application/zip"`)
+	assert.Contains(t, reasons["LIST"], `"application/json"`)
+}
+
+func TestDownloadAll_ParentScopedAPIIsDownloadedPerParent(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
+		switch a.URLPath {
+		case api.NewAPIs()["application-web"].URLPath:
+			return []client.Value{{Id: "APP-1", Name: "app 1"}, {Id: "APP-2", Name: "app 2"}}, nil
+		case "/apps/APP-1/actions":
+			return []client.Value{{Id: "ACTION-1", Name: "action 1"}}, nil
+		case "/apps/APP-2/actions":
+			return []client.Value{{Id: "ACTION-2", Name: "action 2"}}, nil
+		}
+		return nil, fmt.Errorf("unexpected URL path %q", a.URLPath)
+	}).Times(3)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte(`{"name": "action"}`), nil).Times(2)
+	downloader := NewDownloader(c)
+	testAPI := api.API{ID: "API_ID", URLPath: "/apps/" + api.ParentObjectIdPlaceholder + "/actions", Parent: "application-web"}
+
+	configurations := downloader.DownloadAll(context.TODO(), api.APIs{"API_ID": testAPI}, "project")
+	assert.Len(t, configurations["API_ID"], 2)
+
+	scopes := make(map[string]interface{})
+	for _, conf := range configurations["API_ID"] {
+		scopes[conf.Coordinate.ConfigId] = conf.Parameters[config.ScopeParameter].(*valueParam.ValueParameter).Value
+	}
+	assert.Equal(t, map[string]interface{}{"APP-1-ACTION-1": "APP-1", "APP-2-ACTION-2": "APP-2"}, scopes)
+}
+
 func TestDownloadAll_ConfigsDownloaded_WithEmptyFilter(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -82,29 +159,29 @@ func TestDownloadAll_ConfigsDownloaded_WithEmptyFilter(t *testing.T) {
 	downloader := NewDownloader(c, WithAPIFilters(map[string]apiFilter{}))
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 2)
 }
 
 func TestDownloadAll_SingleConfigurationAPI(t *testing.T) {
 	client := client.NewMockClient(gomock.NewController(t))
-	client.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	client.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
 	downloader := NewDownloader(client)
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", SingleConfiguration: true, NonUniqueName: true}
 	apiMap := api.APIs{"API_ID_1": testAPI1}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 1)
 }
 
 func TestDownloadAll_ErrorFetchingConfig(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -118,7 +195,7 @@ func TestDownloadAll_ErrorFetchingConfig(t *testing.T) {
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
 
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).DoAndReturn(func(a api.API, id string) (json []byte, err error) {
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API, id string) (json []byte, err error) {
 		if a.ID == "API_ID_1" {
 			return []byte("{}"), fmt.Errorf("NO")
 		}
@@ -126,14 +203,14 @@ func TestDownloadAll_ErrorFetchingConfig(t *testing.T) {
 	}).Times(2)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 1)
 }
 
 func TestDownloadAll_SkipConfigThatShouldNotBePersisted(t *testing.T) {
 
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -151,18 +228,18 @@ func TestDownloadAll_SkipConfigThatShouldNotBePersisted(t *testing.T) {
 
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil).Times(2)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil).Times(2)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 1)
 }
 
 func TestDownloadAll_SkipConfigBeforeDownload(t *testing.T) {
 
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -180,25 +257,37 @@ func TestDownloadAll_SkipConfigBeforeDownload(t *testing.T) {
 
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 1)
 }
 
+func TestDownloadAll_OnlyConfigIdsAreDownloaded(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).Return([]client.Value{{Id: "wanted", Name: "a"}, {Id: "other", Name: "b"}}, nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), "wanted").Return([]byte("{}"), nil)
+
+	testAPI := api.API{ID: "API_ID", URLPath: "API_PATH", NonUniqueName: true}
+	downloader := NewDownloader(c, WithConfigIds(map[string][]string{"API_ID": {"wanted"}}))
+
+	configurations := downloader.DownloadAll(context.TODO(), api.APIs{"API_ID": testAPI}, "project")
+	assert.Len(t, configurations["API_ID"], 1)
+}
+
 func TestDownloadAll_EmptyAPIMap_NothingIsDownloaded(t *testing.T) {
 	client := client.NewMockClient(gomock.NewController(t))
 	downloader := NewDownloader(client)
 
-	configurations := downloader.DownloadAll(api.APIs{}, "project")
+	configurations := downloader.DownloadAll(context.TODO(), api.APIs{}, "project")
 	assert.Len(t, configurations, 0)
 }
 
 func TestDownloadAll_APIWithoutAnyConfigAvailableAreNotDownloaded(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -209,17 +298,17 @@ func TestDownloadAll_APIWithoutAnyConfigAvailableAreNotDownloaded(t *testing.T)
 	downloader := NewDownloader(c)
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 1)
 }
 
 func TestDownloadAll_MalformedResponseFromAnAPI(t *testing.T) {
 	c := client.NewMockClient(gomock.NewController(t))
-	c.EXPECT().ListConfigs(gomock.Any()).DoAndReturn(func(a api.API) ([]client.Value, error) {
+	c.EXPECT().ListConfigs(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a api.API) ([]client.Value, error) {
 		if a.ID == "API_ID_1" {
 			return []client.Value{{Id: "API_ID_1", Name: "API_NAME_1"}}, nil
 		} else if a.ID == "API_ID_2" {
@@ -230,11 +319,11 @@ func TestDownloadAll_MalformedResponseFromAnAPI(t *testing.T) {
 	downloader := NewDownloader(c)
 	testAPI1 := api.API{ID: "API_ID_1", URLPath: "API_PATH_1", NonUniqueName: true}
 	testAPI2 := api.API{ID: "API_ID_2", URLPath: "API_PATH_2", NonUniqueName: true}
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("-1"), nil)
-	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("-1"), nil)
+	c.EXPECT().ReadConfigById(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte("{}"), nil)
 
 	apiMap := api.APIs{"API_ID_1": testAPI1, "API_ID_2": testAPI2}
 
-	configurations := downloader.DownloadAll(apiMap, "project")
+	configurations := downloader.DownloadAll(context.TODO(), apiMap, "project")
 	assert.Len(t, configurations, 1)
 }
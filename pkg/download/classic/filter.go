@@ -27,6 +27,17 @@ type apiFilter struct {
 
 	// shouldConfigBePersisted is an optional callback to check whether a config should be persisted after being downloaded
 	shouldConfigBePersisted func(json map[string]interface{}) bool
+
+	// reason describes why configs are filtered. It is recorded in the download report.
+	reason string
+}
+
+// filterReason returns why configs of the given API are filtered
+func (f apiFilter) filterReason() string {
+	if f.reason == "" {
+		return "excluded by the download filter of the API"
+	}
+	return f.reason
 }
 
 var apiFilters = map[string]apiFilter{
@@ -45,11 +56,13 @@ var apiFilters = map[string]apiFilter{
 
 			return true
 		},
+		reason: "dashboard owned by Dynatrace or preset",
 	},
 	"synthetic-location": {
 		shouldConfigBePersisted: func(json map[string]interface{}) bool {
 			return json["type"] == "PRIVATE"
 		},
+		reason: "public location provided by Dynatrace",
 	},
 	"hosts-auto-update": {
 		// check that the property 'updateWindows' is not empty, otherwise discard.
@@ -66,10 +79,44 @@ var apiFilters = map[string]apiFilter{
 
 			return len(windows) > 0
 		},
+		reason: "no update windows defined",
 	},
 	"anomaly-detection-metrics": {
 		shouldBeSkippedPreDownload: func(value client.Value) bool {
 			return strings.HasPrefix(value.Id, "dynatrace.") || strings.HasPrefix(value.Id, "ruxit.")
 		},
+		reason: "built-in metric event",
 	},
+	"calculated-metrics-service":            {shouldBeSkippedPreDownload: isBuiltinCalculatedMetric("calc:service."), reason: builtinCalculatedMetricReason},
+	"calculated-metrics-log":                {shouldBeSkippedPreDownload: isBuiltinCalculatedMetric("calc:log."), reason: builtinCalculatedMetricReason},
+	"calculated-metrics-application-mobile": {shouldBeSkippedPreDownload: isBuiltinCalculatedMetric("calc:apps.mobile."), reason: builtinCalculatedMetricReason},
+	"calculated-metrics-application-web":    {shouldBeSkippedPreDownload: isBuiltinCalculatedMetric("calc:apps.web."), reason: builtinCalculatedMetricReason},
+	"calculated-metrics-synthetic":          {shouldBeSkippedPreDownload: isBuiltinCalculatedMetric("calc:synthetic."), reason: builtinCalculatedMetricReason},
+}
+
+const builtinCalculatedMetricReason = "built-in calculated metric"
+
+// builtinCalculatedMetricNamespaces are the namespaces of calculated metrics Dynatrace creates automatically, e.g.
+// for built-in SLOs. Such metrics are re-created by Dynatrace and can't be managed via configuration as code.
+var builtinCalculatedMetricNamespaces = []string{"builtin.", "dt.", "dynatrace.", "ruxit."}
+
+// isBuiltinCalculatedMetric returns a filter skipping calculated metrics created by Dynatrace. The ID of a calculated
+// metric is its metric key, consisting of the given API specific prefix and the metric name - e.g. 'calc:service.requests'.
+func isBuiltinCalculatedMetric(keyPrefix string) func(value client.Value) bool {
+	return func(value client.Value) bool {
+		name := strings.TrimPrefix(value.Id, keyPrefix)
+		for _, ns := range builtinCalculatedMetricNamespaces {
+			if strings.HasPrefix(name, ns) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SkippedPreDownload returns true if the given value of an API is never downloaded, e.g. because it is a Dynatrace
+// provided default config.
+func SkippedPreDownload(apiID string, value client.Value) bool {
+	f, found := apiFilters[apiID]
+	return found && f.shouldBeSkippedPreDownload != nil && f.shouldBeSkippedPreDownload(value)
 }
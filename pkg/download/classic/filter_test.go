@@ -19,6 +19,7 @@
 package classic
 
 import (
+	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 	"github.com/stretchr/testify/assert"
@@ -135,4 +136,22 @@ func TestShouldConfigBeSkipped(t *testing.T) {
 			Id: "test.something",
 		}))
 	})
+
+	for _, tc := range []struct {
+		apiId, metricKey string
+		skipped          bool
+	}{
+		{"calculated-metrics-service", "calc:service.requests", false},
+		{"calculated-metrics-service", "calc:service.dt.requests", true},
+		{"calculated-metrics-service", "calc:service.builtin.slo", true},
+		{"calculated-metrics-service", "calc:service.my.dt.metric", false},
+		{"calculated-metrics-log", "calc:log.dynatrace.errors", true},
+		{"calculated-metrics-application-mobile", "calc:apps.mobile.ruxit.crashes", true},
+		{"calculated-metrics-application-web", "calc:apps.web.actions", false},
+		{"calculated-metrics-synthetic", "calc:synthetic.dt.availability", true},
+	} {
+		t.Run(fmt.Sprintf("%s - %s skipped: %t", tc.apiId, tc.metricKey, tc.skipped), func(t *testing.T) {
+			assert.Equal(t, tc.skipped, apiFilters[tc.apiId].shouldBeSkippedPreDownload(client.Value{Id: tc.metricKey}))
+		})
+	}
 }
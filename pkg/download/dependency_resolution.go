@@ -94,13 +94,20 @@ func newResolutionContext(configs project.ConfigsPerType) dependencyResolutionCo
 }
 
 func resolveScope(configToBeUpdated *config.Config, ids map[string]config.Config) {
-	if configToBeUpdated.Type.ID() != config.SettingsTypeId {
-		return
-	}
-
 	scopeParam, found := configToBeUpdated.Parameters[config.ScopeParameter]
-	if !found {
-		log.Error(fmt.Sprintf("Setting found without a scope parameter. Skipping resolution for this config. Coordinate: %s.", configToBeUpdated.Coordinate))
+
+	switch configToBeUpdated.Type.ID() {
+	case config.SettingsTypeId:
+		if !found {
+			log.Error(fmt.Sprintf("Setting found without a scope parameter. Skipping resolution for this config. Coordinate: %s.", configToBeUpdated.Coordinate))
+			return
+		}
+	case config.ClassicApiTypeId:
+		// only configs of parent-scoped APIs hold the ID of their parent object as scope
+		if !found {
+			return
+		}
+	default:
 		return
 	}
 
@@ -355,6 +355,49 @@ func TestDependencyResolution(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Scope of parent-scoped classic config is replaced in dependency resolution",
+			setup: project.ConfigsPerType{
+				"application-web": []config.Config{
+					{
+						Template:   template.NewDownloadTemplate("APPLICATION-1", "app", ""),
+						Coordinate: coordinate.Coordinate{Project: "project", Type: "application-web", ConfigId: "APPLICATION-1"},
+						Type:       config.ClassicApiType{Api: "application-web"},
+						Parameters: config.Parameters{},
+					},
+				},
+				"key-user-actions-web": []config.Config{
+					{
+						Template:   template.NewDownloadTemplate("ACTION-1", "action", ""),
+						Coordinate: coordinate.Coordinate{Project: "project", Type: "key-user-actions-web", ConfigId: "APPLICATION-1-ACTION-1"},
+						Type:       config.ClassicApiType{Api: "key-user-actions-web"},
+						Parameters: config.Parameters{
+							config.ScopeParameter: &valueParam.ValueParameter{Value: "APPLICATION-1"},
+						},
+					},
+				},
+			},
+			expected: project.ConfigsPerType{
+				"application-web": []config.Config{
+					{
+						Template:   template.NewDownloadTemplate("APPLICATION-1", "app", ""),
+						Coordinate: coordinate.Coordinate{Project: "project", Type: "application-web", ConfigId: "APPLICATION-1"},
+						Type:       config.ClassicApiType{Api: "application-web"},
+						Parameters: config.Parameters{},
+					},
+				},
+				"key-user-actions-web": []config.Config{
+					{
+						Template:   template.NewDownloadTemplate("ACTION-1", "action", ""),
+						Coordinate: coordinate.Coordinate{Project: "project", Type: "key-user-actions-web", ConfigId: "APPLICATION-1-ACTION-1"},
+						Type:       config.ClassicApiType{Api: "key-user-actions-web"},
+						Parameters: config.Parameters{
+							config.ScopeParameter: refParam.New("project", "application-web", "APPLICATION-1", "id"),
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "Scope-resolution transitive",
 			setup: project.ConfigsPerType{
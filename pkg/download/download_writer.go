@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/writer"
@@ -30,12 +32,20 @@ import (
 )
 
 type WriterContext struct {
-	EnvironmentUrl         string
-	ProjectToWrite         project.Project
+	EnvironmentUrl string
+	ProjectToWrite project.Project
+	// SplitProjects are written next to ProjectToWrite into the same manifest, e.g. when a download is split by team
+	SplitProjects          []project.Project
 	Auth                   manifest.Auth
 	OutputFolder           string
 	ForceOverwriteManifest bool
-	timestampString        string
+	// WriteSnapshot additionally writes a Snapshot of the downloaded objects into the project folder
+	WriteSnapshot bool
+	// ServerVersion is the version of the Dynatrace server downloaded from, recorded in snapshots. It may be unknown.
+	ServerVersion version.Version
+	// Report, if set, is written into the output folder, next to the manifest
+	Report          *report.Report
+	timestampString string
 }
 
 func (c WriterContext) GetOutputFolderFilePath() string {
@@ -45,6 +55,17 @@ func (c WriterContext) GetOutputFolderFilePath() string {
 	return c.OutputFolder
 }
 
+func (c WriterContext) snapshotEnvironment() SnapshotEnvironment {
+	env := SnapshotEnvironment{URL: c.EnvironmentUrl, AuthType: AuthTypeToken}
+	if !c.ServerVersion.Invalid() {
+		env.ServerVersion = c.ServerVersion.String()
+	}
+	if c.Auth.OAuth != nil {
+		env.AuthType = AuthTypeOAuth
+	}
+	return env
+}
+
 // WriteToDisk writes all projects to the disk
 func WriteToDisk(fs afero.Fs, writerContext WriterContext) error {
 	writerContext.timestampString = time.Now().Format("2006-01-02-150405")
@@ -66,14 +87,36 @@ func writeToDisk(fs afero.Fs, writerContext WriterContext) error {
 		Fs:              fs,
 		OutputDir:       outputFolder,
 		ManifestName:    manifestName,
-		ParametersSerde: config.DefaultParameterParsers,
-	}, m, []project.Project{writerContext.ProjectToWrite})
+		ParametersSerde: config.ParameterParsers(),
+	}, m, writerContext.projects())
 
 	if len(errs) > 0 {
 		errutils.PrintErrors(errs)
 		return fmt.Errorf("failed to persist downloaded configurations")
 	}
 
+	if writerContext.WriteSnapshot {
+		for _, p := range writerContext.projects() {
+			snapshot := CreateSnapshot(p, writerContext.snapshotEnvironment(), time.Now())
+			if err := writeSnapshot(fs, filepath.Join(outputFolder, p.Id), snapshot); err != nil {
+				return err
+			}
+			log.Info("Snapshot of %d downloaded objects written to '%s'", len(snapshot.Objects), filepath.Join(outputFolder, p.Id, SnapshotFileName))
+		}
+	}
+
+	if writerContext.Report != nil {
+		path := filepath.Join(outputFolder, report.FileName)
+		if err := writerContext.Report.Write(fs, path); err != nil {
+			return err
+		}
+		if n := writerContext.Report.Problems(); n > 0 {
+			log.Warn("%d objects or types could not be downloaded, see '%s' for details", n, path)
+		} else {
+			log.Info("Download report written to '%s'", path)
+		}
+	}
+
 	log.Info("Downloaded configurations written to '%s'", outputFolder)
 	return nil
 }
@@ -95,12 +138,18 @@ func getManifestFilePath(fs afero.Fs, writerContext WriterContext) string {
 	return fmt.Sprintf("manifest_%s.yaml", writerContext.timestampString)
 }
 
+// projects returns ProjectToWrite followed by the SplitProjects
+func (c WriterContext) projects() []project.Project {
+	return append([]project.Project{c.ProjectToWrite}, c.SplitProjects...)
+}
+
 func createManifest(wc WriterContext) manifest.Manifest {
-	projectDefinition := manifest.ProjectDefinitionByProjectID{
-		wc.ProjectToWrite.Id: {
-			Name: wc.ProjectToWrite.Id,
-			Path: wc.ProjectToWrite.Id,
-		},
+	projectDefinition := manifest.ProjectDefinitionByProjectID{}
+	for _, p := range wc.projects() {
+		projectDefinition[p.Id] = manifest.ProjectDefinition{
+			Name: p.Id,
+			Path: p.Id,
+		}
 	}
 
 	return manifest.Manifest{
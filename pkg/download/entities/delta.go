@@ -0,0 +1,124 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+)
+
+// retentionTimeframe is the timeframe in which entities need to have been seen to be part of a download. It matches the
+// timeframe of a full download, so that a delta download merged with a previous dump contains the same entities.
+const retentionTimeframe = 5 * 7 * 24 * time.Hour
+
+// PreviousDump holds the entities of a previous download per entities type. It is used to only download entities
+// seen since the previous download and merge them with the previously downloaded ones.
+type PreviousDump map[string][]string
+
+// entityHeader holds the fields of a downloaded entity needed to merge downloads
+type entityHeader struct {
+	EntityId    string `json:"entityId"`
+	LastSeenTms int64  `json:"lastSeenTms"`
+}
+
+// LoadPreviousDump loads the entities of a previously downloaded project. Each entities type is expected in
+// '<projectFolder>/<type>/<type>.json', as written by the entities download.
+func LoadPreviousDump(fs afero.Fs, projectFolder string) (PreviousDump, error) {
+	dirs, err := afero.ReadDir(fs, projectFolder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous download %q: %w", projectFolder, err)
+	}
+
+	dump := make(PreviousDump, len(dirs))
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		file := filepath.Join(projectFolder, d.Name(), d.Name()+".json")
+		content, err := afero.ReadFile(fs, file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read previously downloaded entities %q: %w", file, err)
+		}
+
+		var entities []json.RawMessage
+		if err := json.Unmarshal(content, &entities); err != nil {
+			return nil, fmt.Errorf("failed to parse previously downloaded entities %q: %w", file, err)
+		}
+		for _, e := range entities {
+			dump[d.Name()] = append(dump[d.Name()], string(e))
+		}
+	}
+	return dump, nil
+}
+
+// lastSeen returns the latest time an entity of the given type was seen in the previous dump, or the zero time if
+// the previous dump holds no entities of that type.
+func (p PreviousDump) lastSeen(entitiesType string) time.Time {
+	var latest int64
+	for _, e := range p[entitiesType] {
+		var h entityHeader
+		if err := json.Unmarshal([]byte(e), &h); err != nil {
+			continue
+		}
+		if h.LastSeenTms > latest {
+			latest = h.LastSeenTms
+		}
+	}
+	if latest == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(latest)
+}
+
+// mergeEntities merges freshly downloaded entities into the previously downloaded ones. Entities present in both are
+// replaced by their downloaded version, entities not seen within the retention timeframe are dropped.
+func mergeEntities(previous, downloaded []string, now time.Time) []string {
+	cutoff := now.Add(-retentionTimeframe).UnixMilli()
+
+	downloadedById := make(map[string]string, len(downloaded))
+	for _, e := range downloaded {
+		var h entityHeader
+		if err := json.Unmarshal([]byte(e), &h); err != nil || h.EntityId == "" {
+			continue
+		}
+		downloadedById[h.EntityId] = e
+	}
+
+	result := make([]string, 0, len(previous)+len(downloaded))
+	for _, e := range previous {
+		var h entityHeader
+		if err := json.Unmarshal([]byte(e), &h); err != nil {
+			log.Warn("Dropping previously downloaded entity that can not be parsed: %s", err)
+			continue
+		}
+		if _, found := downloadedById[h.EntityId]; found || h.LastSeenTms < cutoff {
+			continue
+		}
+		result = append(result, e)
+	}
+	return append(result, downloaded...)
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package entities
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+)
+
+func entity(id string, lastSeen time.Time) string {
+	return fmt.Sprintf(`{"entityId":%q,"lastSeenTms":%d}`, id, lastSeen.UnixMilli())
+}
+
+func TestLoadPreviousDump(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	seen := time.UnixMilli(1672531200000)
+	assert.NoError(t, afero.WriteFile(fs, "prev/project/HOST/HOST.json", []byte("["+entity("HOST-1", seen)+","+entity("HOST-2", seen)+"]"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "prev/project/SERVICE/other.json", []byte("[]"), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "prev/project/manifest.yaml", []byte(""), 0644))
+
+	dump, err := LoadPreviousDump(fs, "prev/project")
+
+	assert.NoError(t, err)
+	assert.Equal(t, PreviousDump{"HOST": {entity("HOST-1", seen), entity("HOST-2", seen)}}, dump)
+}
+
+func TestLoadPreviousDump_InvalidJSON(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "prev/HOST/HOST.json", []byte("{"), 0644))
+
+	_, err := LoadPreviousDump(fs, "prev")
+
+	assert.ErrorContains(t, err, "HOST.json")
+}
+
+func TestPreviousDump_lastSeen(t *testing.T) {
+	older := time.UnixMilli(1672531200000)
+	newer := older.Add(time.Hour)
+	dump := PreviousDump{"HOST": {entity("HOST-1", newer), entity("HOST-2", older)}}
+
+	assert.Equal(t, newer, dump.lastSeen("HOST"))
+	assert.True(t, dump.lastSeen("SERVICE").IsZero())
+	assert.True(t, PreviousDump(nil).lastSeen("HOST").IsZero())
+}
+
+func TestMergeEntities(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	expired := now.Add(-retentionTimeframe - time.Hour)
+
+	previous := []string{entity("HOST-1", recent), entity("HOST-2", recent), entity("HOST-3", expired)}
+	downloaded := []string{entity("HOST-2", now), entity("HOST-4", now)}
+
+	actual := mergeEntities(previous, downloaded, now)
+
+	assert.Equal(t, []string{entity("HOST-1", recent), entity("HOST-2", now), entity("HOST-4", now)}, actual)
+}
+
+func TestDownload_WithPreviousDump(t *testing.T) {
+	lastSeen := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	hostType := client.EntitiesType{EntitiesTypeId: "HOST"}
+	serviceType := client.EntitiesType{EntitiesTypeId: "SERVICE"}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListEntitiesTypes(gomock.Any()).Return([]client.EntitiesType{hostType, serviceType}, nil)
+	c.EXPECT().ListEntities(gomock.Any(), hostType, client.ListEntitiesOptions{From: lastSeen}).Return([]string{entity("HOST-2", lastSeen)}, nil)
+	c.EXPECT().ListEntities(gomock.Any(), serviceType, client.ListEntitiesOptions{}).Return([]string{entity("SERVICE-1", lastSeen)}, nil)
+
+	previous := PreviousDump{"HOST": {entity("HOST-1", lastSeen)}}
+	res := NewEntitiesDownloader(c, WithPreviousDump(previous)).DownloadAll(context.TODO(), "project")
+
+	assert.Equal(t, "["+entity("HOST-1", lastSeen)+","+entity("HOST-2", lastSeen)+"]", res["HOST"][0].Template.Content())
+	assert.Equal(t, "["+entity("SERVICE-1", lastSeen)+"]", res["SERVICE"][0].Template.Content())
+}
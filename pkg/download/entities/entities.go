@@ -17,9 +17,11 @@
 package entities
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
@@ -36,29 +38,45 @@ import (
 // Downloader is responsible for downloading Settings 2.0 objects
 type Downloader struct {
 	client client.EntitiesClient
+
+	// previous, if set, holds the entities of a previous download. Only entities seen since then are downloaded and
+	// merged with the previous ones.
+	previous PreviousDump
+}
+
+// WithPreviousDump makes the Downloader only download entities seen since the given previous download and merge
+// them with it. Entities types not contained in the previous download are downloaded completely.
+func WithPreviousDump(previous PreviousDump) func(*Downloader) {
+	return func(d *Downloader) {
+		d.previous = previous
+	}
 }
 
 // NewEntitiesDownloader creates a new downloader for Settings 2.0 objects
-func NewEntitiesDownloader(c client.EntitiesClient) *Downloader {
-	return &Downloader{
+func NewEntitiesDownloader(c client.EntitiesClient, opts ...func(*Downloader)) *Downloader {
+	d := &Downloader{
 		client: c,
 	}
+	for _, o := range opts {
+		o(d)
+	}
+	return d
 }
 
 // Download downloads all entities objects for the given entities Types
 
-func Download(c client.EntitiesClient, specificEntitiesTypes []string, projectName string) v2.ConfigsPerType {
-	return NewEntitiesDownloader(c).Download(specificEntitiesTypes, projectName)
+func Download(ctx context.Context, c client.EntitiesClient, specificEntitiesTypes []string, projectName string) v2.ConfigsPerType {
+	return NewEntitiesDownloader(c).Download(ctx, specificEntitiesTypes, projectName)
 }
 
 // DownloadAll downloads all entities objects for a given project
-func DownloadAll(c client.EntitiesClient, projectName string) v2.ConfigsPerType {
-	return NewEntitiesDownloader(c).DownloadAll(projectName)
+func DownloadAll(ctx context.Context, c client.EntitiesClient, projectName string) v2.ConfigsPerType {
+	return NewEntitiesDownloader(c).DownloadAll(ctx, projectName)
 }
 
 // Download downloads specific entities objects for the given entities Types and a given project
 // The returned value is a map of entities objects with the entities Type as keys
-func (d *Downloader) Download(specificEntitiesTypes []string, projectName string) v2.ConfigsPerType {
+func (d *Downloader) Download(ctx context.Context, specificEntitiesTypes []string, projectName string) v2.ConfigsPerType {
 	if len(specificEntitiesTypes) == 0 {
 		log.Error("No Specific entity type profided for the specific-types option ")
 		return nil
@@ -67,7 +85,7 @@ func (d *Downloader) Download(specificEntitiesTypes []string, projectName string
 	log.Debug("Fetching specific entities types to download")
 
 	// get ALL entities types
-	entitiesTypes, err := d.client.ListEntitiesTypes()
+	entitiesTypes, err := d.client.ListEntitiesTypes(ctx)
 	if err != nil {
 		log.Error("Failed to fetch all known entities types. Skipping entities download. Reason: %s", err)
 		return nil
@@ -79,7 +97,7 @@ func (d *Downloader) Download(specificEntitiesTypes []string, projectName string
 		return nil
 	}
 
-	return d.download(filteredEntitiesTypes, projectName)
+	return d.download(ctx, filteredEntitiesTypes, projectName)
 }
 
 func filterSpecificEntitiesTypes(specificEntitiesTypes []string, entitiesTypes []client.EntitiesType) []client.EntitiesType {
@@ -105,20 +123,20 @@ func filterSpecificEntitiesTypes(specificEntitiesTypes []string, entitiesTypes [
 
 // DownloadAll downloads all entities objects for a given project.
 // The returned value is a map of entities objects with the entities Type as keys
-func (d *Downloader) DownloadAll(projectName string) v2.ConfigsPerType {
+func (d *Downloader) DownloadAll(ctx context.Context, projectName string) v2.ConfigsPerType {
 	log.Debug("Fetching all entities types to download")
 
 	// get ALL entities types
-	entitiesTypes, err := d.client.ListEntitiesTypes()
+	entitiesTypes, err := d.client.ListEntitiesTypes(ctx)
 	if err != nil {
 		log.Error("Failed to fetch all known entities types. Skipping entities download. Reason: %s", err)
 		return nil
 	}
 
-	return d.download(entitiesTypes, projectName)
+	return d.download(ctx, entitiesTypes, projectName)
 }
 
-func (d *Downloader) download(entitiesTypes []client.EntitiesType, projectName string) v2.ConfigsPerType {
+func (d *Downloader) download(ctx context.Context, entitiesTypes []client.EntitiesType, projectName string) v2.ConfigsPerType {
 	results := make(v2.ConfigsPerType, len(entitiesTypes))
 	downloadMutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
@@ -129,7 +147,8 @@ func (d *Downloader) download(entitiesTypes []client.EntitiesType, projectName s
 		go func(entityType client.EntitiesType) {
 			defer wg.Done()
 
-			objects, err := d.client.ListEntities(entityType)
+			since := d.previous.lastSeen(entityType.EntitiesTypeId)
+			objects, err := d.client.ListEntities(ctx, entityType, client.ListEntitiesOptions{From: since})
 			if err != nil {
 				var errMsg string
 				var respErr client.RespError
@@ -141,6 +160,10 @@ func (d *Downloader) download(entitiesTypes []client.EntitiesType, projectName s
 				log.Error("Failed to fetch all entities for entities Type %s: %v", entityType.EntitiesTypeId, errMsg)
 				return
 			}
+			if !since.IsZero() {
+				log.Debug("Downloaded %d entities for entities Type %s seen since %s", len(objects), entityType.EntitiesTypeId, since.Format(time.RFC3339))
+				objects = mergeEntities(d.previous[entityType.EntitiesTypeId], objects, time.Now())
+			}
 			if len(objects) == 0 {
 				return
 			}
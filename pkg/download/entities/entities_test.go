@@ -19,6 +19,7 @@
 package entities
 
 import (
+	"context"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
@@ -113,10 +114,10 @@ func TestDownloadAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := client.NewMockClient(gomock.NewController(t))
 			entityTypeList, err := tt.mockValues.EntitiesTypeList()
-			c.EXPECT().ListEntitiesTypes().Times(tt.mockValues.EntitiesTypeListCalls).Return(entityTypeList, err)
+			c.EXPECT().ListEntitiesTypes(gomock.Any()).Times(tt.mockValues.EntitiesTypeListCalls).Return(entityTypeList, err)
 			entities, err := tt.mockValues.EntitiesList()
-			c.EXPECT().ListEntities(gomock.Any()).Times(tt.mockValues.EntitiesListCalls).Return(entities, err)
-			res := NewEntitiesDownloader(c).DownloadAll("projectName")
+			c.EXPECT().ListEntities(gomock.Any(), gomock.Any(), gomock.Any()).Times(tt.mockValues.EntitiesListCalls).Return(entities, err)
+			res := NewEntitiesDownloader(c).DownloadAll(context.TODO(), "projectName")
 			assert.Equal(t, tt.want, res)
 		})
 	}
@@ -214,10 +215,10 @@ func TestDownload(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := client.NewMockClient(gomock.NewController(t))
 			entityTypeList, err := tt.mockValues.EntitiesTypeList()
-			c.EXPECT().ListEntitiesTypes().Times(tt.mockValues.EntitiesTypeListCalls).Return(entityTypeList, err)
+			c.EXPECT().ListEntitiesTypes(gomock.Any()).Times(tt.mockValues.EntitiesTypeListCalls).Return(entityTypeList, err)
 			entities, err := tt.mockValues.EntitiesList()
-			c.EXPECT().ListEntities(gomock.Any()).Times(tt.mockValues.EntitiesListCalls).Return(entities, err)
-			res := NewEntitiesDownloader(c).Download(tt.EntitiesTypes, "projectName")
+			c.EXPECT().ListEntities(gomock.Any(), gomock.Any(), gomock.Any()).Times(tt.mockValues.EntitiesListCalls).Return(entities, err)
+			res := NewEntitiesDownloader(c).Download(context.TODO(), tt.EntitiesTypes, "projectName")
 			assert.Equal(t, tt.want, res)
 		})
 	}
@@ -0,0 +1,154 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package report collects what happened to the objects of an environment during a download, to be written to a
+// report file next to the downloaded configurations. It allows verifying that a download is complete without
+// scrolling through logs.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FileName is the name of the report file written into the output folder of a download
+const FileName = "download-report.json"
+
+// Report collects the results of a download per API or settings schema. It is safe for concurrent use. All methods
+// may be called on a nil report, in which case nothing is recorded.
+type Report struct {
+	mutex sync.Mutex
+	// Types holds the results per API or settings schema. Types without any objects are not listed.
+	Types map[string]*TypeReport `json:"types"`
+}
+
+// TypeReport summarizes the download of the objects of a single API or settings schema
+type TypeReport struct {
+	// Found is the number of objects existing in the environment, i.e. the sum of all objects below
+	Found      int `json:"found"`
+	Downloaded int `json:"downloaded"`
+	// Filtered objects were left out on purpose, e.g. as they are presets of Dynatrace
+	Filtered []Object `json:"filtered,omitempty"`
+	// Skipped objects can not be represented as configs, e.g. as their payload is not JSON
+	Skipped []Object `json:"skipped,omitempty"`
+	// Failed objects could not be downloaded due to an error
+	Failed []Object `json:"failed,omitempty"`
+	// Error is set if the objects of the type could not be listed at all
+	Error string `json:"error,omitempty"`
+}
+
+// Object is an object which exists in the environment, but was not downloaded
+type Object struct {
+	Id   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Reason is why the object was filtered or skipped
+	Reason string `json:"reason,omitempty"`
+	// Error is why the download of the object failed
+	Error string `json:"error,omitempty"`
+}
+
+// New returns an empty report
+func New() *Report {
+	return &Report{Types: make(map[string]*TypeReport)}
+}
+
+// Downloaded records the number of downloaded objects of the given type
+func (r *Report) Downloaded(typ string, n int) {
+	r.update(typ, func(t *TypeReport) { t.Downloaded = n })
+}
+
+// Filter records that the object of the given type and ID was left out on purpose for the given reason
+func (r *Report) Filter(typ, id, name, reason string) {
+	r.update(typ, func(t *TypeReport) { t.Filtered = append(t.Filtered, Object{Id: id, Name: name, Reason: reason}) })
+}
+
+// Skip records that the object of the given type and ID can not be downloaded for the given reason
+func (r *Report) Skip(typ, id, name, reason string) {
+	r.update(typ, func(t *TypeReport) { t.Skipped = append(t.Skipped, Object{Id: id, Name: name, Reason: reason}) })
+}
+
+// Fail records that the download of the object of the given type and ID failed
+func (r *Report) Fail(typ, id, name string, err error) {
+	r.update(typ, func(t *TypeReport) { t.Failed = append(t.Failed, Object{Id: id, Name: name, Error: err.Error()}) })
+}
+
+// FailType records that the objects of the given type could not be listed
+func (r *Report) FailType(typ string, err error) {
+	r.update(typ, func(t *TypeReport) { t.Error = err.Error() })
+}
+
+func (r *Report) update(typ string, f func(t *TypeReport)) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	t, found := r.Types[typ]
+	if !found {
+		t = &TypeReport{}
+		r.Types[typ] = t
+	}
+	f(t)
+}
+
+// Problems returns the number of objects and types which could not be downloaded due to errors or unsupported content
+func (r *Report) Problems() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	n := 0
+	for _, t := range r.Types {
+		n += len(t.Skipped) + len(t.Failed)
+		if t.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Write writes the report as JSON to the given path. Objects are sorted by ID, so that reports of unchanged
+// environments are equal.
+func (r *Report) Write(fs afero.Fs, path string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, t := range r.Types {
+		t.Found = t.Downloaded + len(t.Filtered) + len(t.Skipped) + len(t.Failed)
+		for _, objects := range [][]Object{t.Filtered, t.Skipped, t.Failed} {
+			objects := objects
+			sort.Slice(objects, func(i, j int) bool { return objects[i].Id < objects[j].Id })
+		}
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal download report: %w", err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return fmt.Errorf("failed to create folder of download report %q: %w", path, err)
+	}
+	if err := afero.WriteFile(fs, path, b, 0664); err != nil {
+		return fmt.Errorf("failed to write download report %q: %w", path, err)
+	}
+	return nil
+}
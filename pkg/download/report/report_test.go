@@ -0,0 +1,77 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReport_Write(t *testing.T) {
+	r := New()
+	r.Downloaded("dashboard", 3)
+	r.Filter("dashboard", "z", "dashboard z", "dashboard owned by Dynatrace or preset")
+	r.Filter("dashboard", "a", "dashboard a", "dashboard owned by Dynatrace or preset")
+	r.Fail("dashboard", "b", "dashboard b", fmt.Errorf("HTTP 500"))
+	r.Skip("extension", "ext", "", "payload is not a JSON object")
+	r.FailType("builtin:alerting.profile", fmt.Errorf("HTTP 403"))
+
+	assert.Equal(t, 3, r.Problems())
+
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, r.Write(fs, "out/"+FileName))
+
+	b, err := afero.ReadFile(fs, "out/"+FileName)
+	assert.NoError(t, err)
+
+	var written Report
+	assert.NoError(t, json.Unmarshal(b, &written))
+	assert.Equal(t, map[string]*TypeReport{
+		"dashboard": {
+			Found:      6,
+			Downloaded: 3,
+			Filtered: []Object{
+				{Id: "a", Name: "dashboard a", Reason: "dashboard owned by Dynatrace or preset"},
+				{Id: "z", Name: "dashboard z", Reason: "dashboard owned by Dynatrace or preset"},
+			},
+			Failed: []Object{{Id: "b", Name: "dashboard b", Error: "HTTP 500"}},
+		},
+		"extension": {
+			Found:   1,
+			Skipped: []Object{{Id: "ext", Reason: "payload is not a JSON object"}},
+		},
+		"builtin:alerting.profile": {
+			Error: "HTTP 403",
+		},
+	}, written.Types)
+}
+
+func TestReport_NilReportRecordsNothing(t *testing.T) {
+	var r *Report
+	assert.NotPanics(t, func() {
+		r.Downloaded("dashboard", 1)
+		r.Filter("dashboard", "id", "name", "reason")
+		r.Skip("dashboard", "id", "name", "reason")
+		r.Fail("dashboard", "id", "name", fmt.Errorf("error"))
+		r.FailType("dashboard", fmt.Errorf("error"))
+	})
+}
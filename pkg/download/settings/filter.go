@@ -17,7 +17,10 @@
 package settings
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"strings"
 )
 
 // noOpFilter is a settings 2.0 filter that does nothing
@@ -47,6 +50,17 @@ func (f Filters) Get(schemaID string) Filter {
 	return noOpFilter
 }
 
+// Discarded returns true if the given settings object is never downloaded, e.g. because it is a Dynatrace provided
+// default object. Objects are discarded based on their value, thus it must be listed.
+func Discarded(o client.DownloadSettingsObject) bool {
+	var value map[string]interface{}
+	if err := json.Unmarshal(o.Value, &value); err != nil {
+		return false
+	}
+	discard, _ := defaultSettingsFilters.Get(o.SchemaId).ShouldDiscard(value)
+	return discard
+}
+
 func formatDefaultDiscardReasonMsg(entityName interface{}) string {
 	return fmt.Sprintf("%q cannot be managed via configuration as code", entityName)
 }
@@ -86,4 +100,12 @@ var defaultSettingsFilters = Filters{
 			return json["summary"] == "Default Kubernetes Log Events", formatDefaultDiscardReasonMsg("Default Kubernetes Log Events")
 		},
 	},
+	// metric events created by Dynatrace, e.g. by extensions, are re-created automatically. They are identified by the
+	// ID of the classic metric event they originate from, using the same prefixes as the classic anomaly-detection-metrics API.
+	"builtin:anomaly-detection.metric-events": {
+		ShouldDiscard: func(json map[string]interface{}) (bool, string) {
+			legacyId, _ := json["legacyId"].(string)
+			return strings.HasPrefix(legacyId, "dynatrace.") || strings.HasPrefix(legacyId, "ruxit."), formatDefaultDiscardReasonMsg(json["summary"])
+		},
+	},
 }
@@ -94,6 +94,30 @@ func TestShouldDiscard(t *testing.T) {
 			json:    map[string]interface{}{"summary": "my log event"},
 			discard: false,
 		},
+		{
+			name:    "builtin:anomaly-detection.metric-events - discarded if 'legacyId' starts with 'dynatrace.'",
+			schema:  "builtin:anomaly-detection.metric-events",
+			json:    map[string]interface{}{"summary": "High CPU", "legacyId": "dynatrace.cpu.high"},
+			discard: true,
+		},
+		{
+			name:    "builtin:anomaly-detection.metric-events - discarded if 'legacyId' starts with 'ruxit.'",
+			schema:  "builtin:anomaly-detection.metric-events",
+			json:    map[string]interface{}{"summary": "Low disk", "legacyId": "ruxit.python.disk"},
+			discard: true,
+		},
+		{
+			name:    "builtin:anomaly-detection.metric-events - not discarded without 'legacyId'",
+			schema:  "builtin:anomaly-detection.metric-events",
+			json:    map[string]interface{}{"summary": "my metric event"},
+			discard: false,
+		},
+		{
+			name:    "builtin:anomaly-detection.metric-events - not discarded for user-created 'legacyId'",
+			schema:  "builtin:anomaly-detection.metric-events",
+			json:    map[string]interface{}{"summary": "my metric event", "legacyId": "b836ff25-24e3-496d-8dce-d94110815ab5"},
+			discard: false,
+		},
 	}
 
 	for _, tc := range tests {
@@ -17,12 +17,16 @@
 package settings
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
 
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
@@ -30,6 +34,8 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/ownership"
 	v2 "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 )
 
@@ -41,6 +47,21 @@ type Downloader struct {
 	// filters specifies which settings 2.0 objects need special treatment under
 	// certain conditions and need to be skipped
 	filters Filters
+
+	// modifiedSince, if set, restricts the download to settings 2.0 objects modified after the given time
+	modifiedSince time.Time
+
+	// objectIds, if set, restricts the download to the settings 2.0 objects of the given IDs
+	objectIds []string
+
+	// report, if set, records the results of the download per schema
+	report *report.Report
+
+	// ownership selects settings 2.0 objects by whether their externalId was generated by monaco
+	ownership ownership.Filter
+
+	// ignored holds the settings 2.0 objects which are left out of the download, recognized by object or external ID
+	ignored config.RemoteObjects
 }
 
 // WithFilters sets specific settings filters for settings 2.0 object that needs to be filtered following
@@ -51,6 +72,44 @@ func WithFilters(filters Filters) func(*Downloader) {
 	}
 }
 
+// WithModifiedSince restricts the download to settings 2.0 objects that were modified after the given time.
+// Objects for which the environment does not provide modification information are always downloaded.
+func WithModifiedSince(t time.Time) func(*Downloader) {
+	return func(d *Downloader) {
+		d.modifiedSince = t
+	}
+}
+
+// WithObjectIds restricts the download to the settings 2.0 objects of the given object IDs.
+func WithObjectIds(ids []string) func(*Downloader) {
+	return func(d *Downloader) {
+		d.objectIds = ids
+	}
+}
+
+// WithReport records the results of the download per schema in the given report, e.g. which objects were filtered
+func WithReport(r *report.Report) func(*Downloader) {
+	return func(d *Downloader) {
+		d.report = r
+	}
+}
+
+// WithOwnershipFilter restricts the download to settings 2.0 objects managed by monaco or not, recognized by their
+// externalId.
+func WithOwnershipFilter(f ownership.Filter) func(*Downloader) {
+	return func(d *Downloader) {
+		d.ownership = f
+	}
+}
+
+// WithIgnoredObjects leaves the given settings 2.0 objects out of the download, e.g. the ones of configs marked with
+// 'ignoreOnDownload'. Objects are recognized by their object ID, or by their external ID.
+func WithIgnoredObjects(ignored config.RemoteObjects) func(*Downloader) {
+	return func(d *Downloader) {
+		d.ignored = ignored
+	}
+}
+
 // NewSettingsDownloader creates a new downloader for Settings 2.0 objects
 func NewSettingsDownloader(client client.SettingsClient, opts ...func(*Downloader)) *Downloader {
 	d := &Downloader{
@@ -65,28 +124,28 @@ func NewSettingsDownloader(client client.SettingsClient, opts ...func(*Downloade
 
 // Download downloads all settings 2.0 objects for the given schema IDs
 
-func Download(client client.SettingsClient, schemaIDs []string, projectName string) v2.ConfigsPerType {
-	return NewSettingsDownloader(client).Download(schemaIDs, projectName)
+func Download(ctx context.Context, client client.SettingsClient, schemaIDs []string, projectName string) v2.ConfigsPerType {
+	return NewSettingsDownloader(client).Download(ctx, schemaIDs, projectName)
 }
 
 // DownloadAll downloads all settings 2.0 objects for a given project
-func DownloadAll(client client.SettingsClient, projectName string) v2.ConfigsPerType {
-	return NewSettingsDownloader(client).DownloadAll(projectName)
+func DownloadAll(ctx context.Context, client client.SettingsClient, projectName string) v2.ConfigsPerType {
+	return NewSettingsDownloader(client).DownloadAll(ctx, projectName)
 }
 
 // Download downloads all settings 2.0 objects for the given schema IDs and a given project
 // The returned value is a map of settings 2.0 objects with the schema ID as keys
-func (d *Downloader) Download(schemaIDs []string, projectName string) v2.ConfigsPerType {
-	return d.download(schemaIDs, projectName)
+func (d *Downloader) Download(ctx context.Context, schemaIDs []string, projectName string) v2.ConfigsPerType {
+	return d.download(ctx, schemaIDs, projectName)
 }
 
 // DownloadAll downloads all settings 2.0 objects for a given project.
 // The returned value is a map of settings 2.0 objects with the schema ID as keys
-func (d *Downloader) DownloadAll(projectName string) v2.ConfigsPerType {
+func (d *Downloader) DownloadAll(ctx context.Context, projectName string) v2.ConfigsPerType {
 	log.Debug("Fetching all schemas to download")
 
 	// get ALL schemas
-	schemas, err := d.client.ListSchemas()
+	schemas, err := d.client.ListSchemas(ctx)
 	if err != nil {
 		log.Error("Failed to fetch all known schemas. Skipping settings download. Reason: %s", err)
 		return nil
@@ -97,10 +156,10 @@ func (d *Downloader) DownloadAll(projectName string) v2.ConfigsPerType {
 		ids = append(ids, i.SchemaId)
 	}
 
-	return d.download(ids, projectName)
+	return d.download(ctx, ids, projectName)
 }
 
-func (d *Downloader) download(schemas []string, projectName string) v2.ConfigsPerType {
+func (d *Downloader) download(ctx context.Context, schemas []string, projectName string) v2.ConfigsPerType {
 	results := make(v2.ConfigsPerType, len(schemas))
 	downloadMutex := sync.Mutex{}
 	wg := sync.WaitGroup{}
@@ -108,8 +167,9 @@ func (d *Downloader) download(schemas []string, projectName string) v2.ConfigsPe
 	for _, schema := range schemas {
 		go func(s string) {
 			defer wg.Done()
-			log.Debug("Downloading all settings for schema %s", s)
-			objects, err := d.client.ListSettings(s, client.ListSettingsOptions{})
+			ctx := log.WithFields(ctx, log.TypeField(s))
+			log.WithCtxFields(ctx).Debug("Downloading all settings for schema %s", s)
+			objects, err := d.client.ListSettings(ctx, s, client.ListSettingsOptions{Filter: d.listFilter()})
 			if err != nil {
 				var errMsg string
 				var respErr client.RespError
@@ -118,19 +178,20 @@ func (d *Downloader) download(schemas []string, projectName string) v2.ConfigsPe
 				} else {
 					errMsg = err.Error()
 				}
-				log.Error("Failed to fetch all settings for schema %s: %v", s, errMsg)
+				log.WithCtxFields(ctx).Error("Failed to fetch all settings for schema %s: %v", s, errMsg)
+				d.report.FailType(s, err)
 				return
 			}
 			if len(objects) == 0 {
 				return
 			}
-			log.Info("Downloaded %d settings for schema %s", len(objects), s)
+			log.WithCtxFields(ctx).Info("Downloaded %d settings for schema %s", len(objects), s)
 			configs := d.convertAllObjects(objects, projectName)
 			downloadMutex.Lock()
 			results[s] = configs
 			downloadMutex.Unlock()
 
-			log.Debug("Finished downloading all (%d) settings for schema %s", len(objects), s)
+			log.WithCtxFields(ctx).Debug("Finished downloading all (%d) settings for schema %s", len(objects), s)
 		}(schema)
 	}
 	wg.Wait()
@@ -138,6 +199,37 @@ func (d *Downloader) download(schemas []string, projectName string) v2.ConfigsPe
 	return results
 }
 
+// listFilter returns the filter applied when listing settings 2.0 objects, or nil if all objects shall be downloaded
+func (d *Downloader) listFilter() client.ListSettingsFilter {
+	if d.modifiedSince.IsZero() && d.objectIds == nil && d.ownership == ownership.All && d.ignored.IsEmpty() {
+		return nil
+	}
+	return func(o client.DownloadSettingsObject) bool {
+		if d.ignored.ContainsSettings(o.ObjectId, o.ExternalId) {
+			log.Info("Skipping download of setting %q of schema %q as it is marked with 'ignoreOnDownload'", o.ObjectId, o.SchemaId)
+			d.report.Filter(o.SchemaId, o.ObjectId, "", "marked with 'ignoreOnDownload'")
+			return false
+		}
+		if d.objectIds != nil && !slices.Contains(d.objectIds, o.ObjectId) {
+			d.report.Filter(o.SchemaId, o.ObjectId, "", "not among the requested IDs")
+			return false
+		}
+		if !d.ownership.Selects(ownership.IsManagedExternalId(o.ExternalId)) {
+			d.report.Filter(o.SchemaId, o.ObjectId, "", d.ownership.Reason())
+			return false
+		}
+		if d.modifiedSince.IsZero() || o.ModificationInfo == nil {
+			return true
+		}
+		if !o.ModificationInfo.LastModified().After(d.modifiedSince) {
+			log.Debug("Skipping setting %q of schema %q as it was last modified at %s", o.ObjectId, o.SchemaId, o.ModificationInfo.LastModified().Format(time.RFC3339))
+			d.report.Filter(o.SchemaId, o.ObjectId, "", fmt.Sprintf("last modified at %s, before the requested time", o.ModificationInfo.LastModified().Format(time.RFC3339)))
+			return false
+		}
+		return true
+	}
+}
+
 func (d *Downloader) convertAllObjects(objects []client.DownloadSettingsObject, projectName string) []config.Config {
 	result := make([]config.Config, 0, len(objects))
 	for _, o := range objects {
@@ -146,11 +238,13 @@ func (d *Downloader) convertAllObjects(objects []client.DownloadSettingsObject,
 		var contentUnmarshalled map[string]interface{}
 		if err := json.Unmarshal(o.Value, &contentUnmarshalled); err != nil {
 			log.Error("Unable to unmarshal JSON value of settings 2.0 object: %v", err)
-			return result
+			d.report.Fail(o.SchemaId, o.ObjectId, "", fmt.Errorf("unable to unmarshal JSON value: %w", err))
+			continue
 		}
 		// skip discarded settings objects
 		if shouldDiscard, reason := d.filters.Get(o.SchemaId).ShouldDiscard(contentUnmarshalled); shouldDiscard {
 			log.Warn("Downloaded setting of schema %q will be discarded. Reason: %s", o.SchemaId, reason)
+			d.report.Filter(o.SchemaId, o.ObjectId, "", reason)
 			continue
 		}
 
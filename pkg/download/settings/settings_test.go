@@ -19,6 +19,7 @@
 package settings
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
@@ -28,10 +29,13 @@ import (
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/download/report"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/ownership"
 	v2 "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestDownloadAll(t *testing.T) {
@@ -168,10 +172,10 @@ func TestDownloadAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := client.NewMockClient(gomock.NewController(t))
 			schemas, err := tt.mockValues.Schemas()
-			c.EXPECT().ListSchemas().Times(tt.mockValues.ListSchemasCalls).Return(schemas, err)
+			c.EXPECT().ListSchemas(gomock.Any()).Times(tt.mockValues.ListSchemasCalls).Return(schemas, err)
 			settings, err := tt.mockValues.Settings()
-			c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Times(tt.mockValues.ListSettingsCalls).Return(settings, err)
-			res := NewSettingsDownloader(c, WithFilters(tt.filters)).DownloadAll("projectName")
+			c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(tt.mockValues.ListSettingsCalls).Return(settings, err)
+			res := NewSettingsDownloader(c, WithFilters(tt.filters)).DownloadAll(context.TODO(), "projectName")
 			assert.Equal(t, tt.want, res)
 		})
 	}
@@ -247,9 +251,122 @@ func TestDownload(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := client.NewMockClient(gomock.NewController(t))
 			settings, err := tt.mockValues.Settings()
-			c.EXPECT().ListSettings(gomock.Any(), gomock.Any()).Times(tt.mockValues.ListSettingsCalls).Return(settings, err)
-			res := NewSettingsDownloader(c).Download(tt.Schemas, "projectName")
+			c.EXPECT().ListSettings(gomock.Any(), gomock.Any(), gomock.Any()).Times(tt.mockValues.ListSettingsCalls).Return(settings, err)
+			res := NewSettingsDownloader(c).Download(context.TODO(), tt.Schemas, "projectName")
 			assert.Equal(t, tt.want, res)
 		})
 	}
 }
+
+func TestDownload_ModifiedSince(t *testing.T) {
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	object := func(objectId string, modified *time.Time) client.DownloadSettingsObject {
+		o := client.DownloadSettingsObject{ObjectId: objectId, SchemaId: "id1"}
+		if modified != nil {
+			o.ModificationInfo = &client.SettingsModificationInfo{LastModifiedTime: modified.UnixMilli()}
+		}
+		return o
+	}
+	before := since.Add(-time.Hour)
+	after := since.Add(time.Hour)
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "id1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		assert.NotNil(t, opts.Filter)
+		assert.False(t, opts.Filter(object("old", &before)))
+		assert.False(t, opts.Filter(object("exact", &since)))
+		assert.True(t, opts.Filter(object("new", &after)))
+		assert.True(t, opts.Filter(object("unknown", nil)), "objects without modification info must be kept")
+		return nil, nil
+	})
+
+	NewSettingsDownloader(c, WithModifiedSince(since)).Download(context.TODO(), []string{"id1"}, "projectName")
+}
+
+func TestDownload_NoListFilterByDefault(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "id1", client.ListSettingsOptions{}).Return(nil, nil)
+
+	NewSettingsDownloader(c).Download(context.TODO(), []string{"id1"}, "projectName")
+}
+
+func TestDownload_ObjectIds(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "id1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		assert.NotNil(t, opts.Filter)
+		assert.True(t, opts.Filter(client.DownloadSettingsObject{ObjectId: "wanted", SchemaId: "id1"}))
+		assert.False(t, opts.Filter(client.DownloadSettingsObject{ObjectId: "other", SchemaId: "id1"}))
+		return nil, nil
+	})
+
+	NewSettingsDownloader(c, WithObjectIds([]string{"wanted"})).Download(context.TODO(), []string{"id1"}, "projectName")
+}
+
+func TestDownload_OwnershipFilter(t *testing.T) {
+	managed := client.DownloadSettingsObject{ObjectId: "managed", SchemaId: "id1", ExternalId: idutils.GenerateExternalID("id1", "config")}
+	manual := client.DownloadSettingsObject{ObjectId: "manual", SchemaId: "id1"}
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "id1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		assert.NotNil(t, opts.Filter)
+		assert.False(t, opts.Filter(managed))
+		assert.True(t, opts.Filter(manual))
+		return nil, nil
+	})
+
+	r := report.New()
+	NewSettingsDownloader(c, WithOwnershipFilter(ownership.Unmanaged), WithReport(r)).Download(context.TODO(), []string{"id1"}, "projectName")
+	assert.Equal(t, []report.Object{{Id: "managed", Reason: "managed by monaco"}}, r.Types["id1"].Filtered)
+}
+
+func TestDownload_IgnoredObjects(t *testing.T) {
+	ignored := config.NewRemoteObjects([]config.Config{{
+		Coordinate:     coordinate.Coordinate{Project: "p", Type: "id1", ConfigId: "config"},
+		Type:           config.SettingsType{SchemaId: "id1"},
+		OriginObjectId: "origin",
+	}})
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "id1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		assert.NotNil(t, opts.Filter)
+		assert.False(t, opts.Filter(client.DownloadSettingsObject{ObjectId: "origin", SchemaId: "id1"}))
+		assert.False(t, opts.Filter(client.DownloadSettingsObject{ObjectId: "deployed", SchemaId: "id1", ExternalId: idutils.GenerateExternalID("id1", "config")}))
+		assert.True(t, opts.Filter(client.DownloadSettingsObject{ObjectId: "other", SchemaId: "id1"}))
+		return nil, nil
+	})
+
+	r := report.New()
+	NewSettingsDownloader(c, WithIgnoredObjects(ignored), WithReport(r)).Download(context.TODO(), []string{"id1"}, "projectName")
+	assert.Equal(t, []report.Object{
+		{Id: "origin", Reason: "marked with 'ignoreOnDownload'"},
+		{Id: "deployed", Reason: "marked with 'ignoreOnDownload'"},
+	}, r.Types["id1"].Filtered)
+}
+
+func TestDownload_RecordsFilteredAndFailedObjectsInReport(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSettings(gomock.Any(), "id1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts client.ListSettingsOptions) ([]client.DownloadSettingsObject, error) {
+		assert.False(t, opts.Filter(client.DownloadSettingsObject{ObjectId: "other", SchemaId: "id1"}))
+		return []client.DownloadSettingsObject{
+			{ObjectId: "wanted", SchemaId: "id1", Value: json.RawMessage(`{}`)},
+			{ObjectId: "discarded", SchemaId: "id1", Value: json.RawMessage(`{"discard": true}`)},
+			{ObjectId: "invalid", SchemaId: "id1", Value: json.RawMessage(`[]`)},
+		}, nil
+	})
+	c.EXPECT().ListSettings(gomock.Any(), "id2", gomock.Any()).Return(nil, fmt.Errorf("HTTP 403"))
+
+	filters := Filters{"id1": {ShouldDiscard: func(json map[string]interface{}) (bool, string) {
+		return json["discard"] == true, "discarded on purpose"
+	}}}
+	r := report.New()
+	configs := NewSettingsDownloader(c, WithObjectIds([]string{"wanted", "discarded", "invalid"}), WithFilters(filters), WithReport(r)).Download(context.TODO(), []string{"id1", "id2"}, "projectName")
+
+	assert.Len(t, configs["id1"], 1)
+	assert.Equal(t, []report.Object{
+		{Id: "other", Reason: "not among the requested IDs"},
+		{Id: "discarded", Reason: "discarded on purpose"},
+	}, r.Types["id1"].Filtered)
+	assert.Len(t, r.Types["id1"].Failed, 1)
+	assert.Equal(t, "invalid", r.Types["id1"].Failed[0].Id)
+	assert.Equal(t, "HTTP 403", r.Types["id2"].Error)
+}
@@ -0,0 +1,165 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/version"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// SnapshotFileName is the name of the snapshot file written into the downloaded project's folder
+const SnapshotFileName = "snapshot.json"
+
+// Snapshot records a SHA-256 hash of every downloaded object together with metadata of the environment it was
+// downloaded from. It allows verifying later on that the downloaded configurations are unchanged.
+type Snapshot struct {
+	CreatedAt     time.Time           `json:"createdAt"`
+	MonacoVersion string              `json:"monacoVersion"`
+	Environment   SnapshotEnvironment `json:"environment"`
+	Project       string              `json:"project"`
+	Objects       []SnapshotObject    `json:"objects"`
+}
+
+// Auth types recorded in a SnapshotEnvironment
+const (
+	AuthTypeToken = "token"
+	AuthTypeOAuth = "oauth"
+)
+
+// SnapshotEnvironment holds the metadata of the environment a Snapshot was taken from
+type SnapshotEnvironment struct {
+	URL string `json:"url"`
+	// ServerVersion is the version of the Dynatrace server, e.g. '1.270.0'. It is empty if it could not be determined.
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// AuthType is the type of authentication used for the download, either AuthTypeToken or AuthTypeOAuth
+	AuthType string `json:"authType"`
+}
+
+// SnapshotObject holds the hash of a single downloaded object
+type SnapshotObject struct {
+	Coordinate     string `json:"coordinate"`
+	OriginObjectId string `json:"originObjectId,omitempty"`
+	SHA256         string `json:"sha256"`
+}
+
+// CreateSnapshot hashes the template content of all configs of the given project.
+// Objects are sorted by their coordinate so that snapshots of unchanged environments only differ in their creation time.
+func CreateSnapshot(p project.Project, environment SnapshotEnvironment, createdAt time.Time) Snapshot {
+	var objects []SnapshotObject
+
+	for _, configsPerType := range p.Configs {
+		for _, configs := range configsPerType {
+			for _, c := range configs {
+				objects = append(objects, SnapshotObject{
+					Coordinate:     c.Coordinate.String(),
+					OriginObjectId: originObjectId(c),
+					SHA256:         HashContent(c.Template.Content()),
+				})
+			}
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Coordinate < objects[j].Coordinate
+	})
+
+	return Snapshot{
+		CreatedAt:     createdAt.UTC(),
+		MonacoVersion: version.MonitoringAsCode,
+		Environment:   environment,
+		Project:       p.Id,
+		Objects:       objects,
+	}
+}
+
+// HashContent returns the hex encoded SHA-256 hash of the given content
+func HashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// originObjectId returns the Dynatrace ID of a downloaded config. Classic configs are downloaded with their ID as config ID.
+func originObjectId(c config.Config) string {
+	if c.OriginObjectId != "" {
+		return c.OriginObjectId
+	}
+	if _, isClassic := c.Type.(config.ClassicApiType); isClassic {
+		return c.Coordinate.ConfigId
+	}
+	return ""
+}
+
+func writeSnapshot(fs afero.Fs, projectFolder string, s Snapshot) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	path := filepath.Join(projectFolder, SnapshotFileName)
+	if err := afero.WriteFile(fs, path, b, 0664); err != nil {
+		return fmt.Errorf("failed to write snapshot %q: %w", path, err)
+	}
+	return nil
+}
+
+// ReadSnapshot reads the snapshot written into the given project folder. If the folder holds no snapshot, nil is
+// returned.
+func ReadSnapshot(fs afero.Fs, projectFolder string) (*Snapshot, error) {
+	path := filepath.Join(projectFolder, SnapshotFileName)
+	data, err := afero.ReadFile(fs, path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %q: %w", path, err)
+	}
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %q: %w", path, err)
+	}
+	return &s, nil
+}
+
+// OriginObjectIds returns the coordinates of all objects of the snapshot with known origin object ID, keyed by that ID.
+// Objects with invalid coordinates are left out.
+func (s Snapshot) OriginObjectIds() map[string]coordinate.Coordinate {
+	result := make(map[string]coordinate.Coordinate)
+	for _, o := range s.Objects {
+		if o.OriginObjectId == "" {
+			continue
+		}
+		c, err := coordinate.Parse(o.Coordinate)
+		if err != nil {
+			continue
+		}
+		result[o.OriginObjectId] = c
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package download
+
+import (
+	"encoding/json"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	v2 "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteToDisk_WritesSnapshot(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	configs := v2.ConfigsPerType{
+		"dashboard": []config.Config{
+			{
+				Type:       config.ClassicApiType{Api: "dashboard"},
+				Template:   template.CreateTemplateFromString("b.json", `{"b": 2}`),
+				Coordinate: coordinate.Coordinate{Project: "proj", Type: "dashboard", ConfigId: "b-id"},
+				Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "b"}},
+			},
+			{
+				Type:       config.ClassicApiType{Api: "dashboard"},
+				Template:   template.CreateTemplateFromString("a.json", `{"a": 1}`),
+				Coordinate: coordinate.Coordinate{Project: "proj", Type: "dashboard", ConfigId: "a-id"},
+				Parameters: config.Parameters{config.NameParameter: &value.ValueParameter{Value: "a"}},
+			},
+		},
+		"builtin:tags": []config.Config{
+			{
+				Type:           config.SettingsType{SchemaId: "builtin:tags"},
+				Template:       template.CreateTemplateFromString("c.json", `{"c": 3}`),
+				Coordinate:     coordinate.Coordinate{Project: "proj", Type: "builtin:tags", ConfigId: "c-id"},
+				Parameters:     config.Parameters{config.NameParameter: &value.ValueParameter{Value: "c"}, config.ScopeParameter: &value.ValueParameter{Value: "environment"}},
+				OriginObjectId: "c-object-id",
+			},
+		},
+	}
+
+	err := WriteToDisk(fs, WriterContext{
+		EnvironmentUrl: "https://env.dynatrace.com",
+		ProjectToWrite: CreateProjectData(configs, "proj"),
+		OutputFolder:   "out",
+		WriteSnapshot:  true,
+		ServerVersion:  version.Version{Major: 1, Minor: 270, Patch: 1},
+	})
+	assert.NilError(t, err)
+
+	b, err := afero.ReadFile(fs, filepath.Join("out", "proj", SnapshotFileName))
+	assert.NilError(t, err)
+
+	var s Snapshot
+	assert.NilError(t, json.Unmarshal(b, &s))
+
+	assert.Equal(t, s.Project, "proj")
+	assert.DeepEqual(t, s.Environment, SnapshotEnvironment{URL: "https://env.dynatrace.com", ServerVersion: "1.270.1", AuthType: AuthTypeToken})
+	assert.Assert(t, time.Since(s.CreatedAt) < time.Minute)
+	assert.DeepEqual(t, s.Objects, []SnapshotObject{
+		{Coordinate: "proj:builtin:tags:c-id", OriginObjectId: "c-object-id", SHA256: HashContent(`{"c": 3}`)},
+		{Coordinate: "proj:dashboard:a-id", OriginObjectId: "a-id", SHA256: HashContent(`{"a": 1}`)},
+		{Coordinate: "proj:dashboard:b-id", OriginObjectId: "b-id", SHA256: HashContent(`{"b": 2}`)},
+	})
+}
+
+func TestWriterContext_SnapshotEnvironment(t *testing.T) {
+	c := WriterContext{
+		EnvironmentUrl: "https://env.apps.dynatrace.com",
+		Auth:           manifest.Auth{OAuth: &manifest.OAuth{}},
+	}
+	assert.DeepEqual(t, c.snapshotEnvironment(), SnapshotEnvironment{URL: "https://env.apps.dynatrace.com", AuthType: AuthTypeOAuth})
+}
+
+func TestWriteToDisk_WritesNoSnapshotByDefault(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	err := WriteToDisk(fs, WriterContext{
+		EnvironmentUrl: "https://env.dynatrace.com",
+		ProjectToWrite: CreateProjectData(v2.ConfigsPerType{}, "proj"),
+		OutputFolder:   "out",
+	})
+	assert.NilError(t, err)
+
+	exists, err := afero.Exists(fs, filepath.Join("out", "proj", SnapshotFileName))
+	assert.NilError(t, err)
+	assert.Assert(t, !exists)
+}
+
+func TestHashContent(t *testing.T) {
+	assert.Equal(t, HashContent(""), "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+}
+
+func TestReadSnapshot(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	s, err := ReadSnapshot(fs, "proj")
+	assert.NilError(t, err)
+	assert.Assert(t, s == nil, "folders without snapshot have none")
+
+	written := Snapshot{Project: "proj", Objects: []SnapshotObject{
+		{Coordinate: "proj:builtin:tags:c-id", OriginObjectId: "c-object-id"},
+		{Coordinate: "proj:dashboard:a-id"},
+		{Coordinate: "invalid", OriginObjectId: "invalid-object-id"},
+	}}
+	assert.NilError(t, writeSnapshot(fs, "proj", written))
+
+	s, err = ReadSnapshot(fs, "proj")
+	assert.NilError(t, err)
+	assert.Equal(t, s.Project, "proj")
+	assert.DeepEqual(t, s.OriginObjectIds(), map[string]coordinate.Coordinate{
+		"c-object-id": {Project: "proj", Type: "builtin:tags", ConfigId: "c-id"},
+	})
+
+	assert.NilError(t, afero.WriteFile(fs, filepath.Join("broken", SnapshotFileName), []byte("{"), 0644))
+	_, err = ReadSnapshot(fs, "broken")
+	assert.ErrorContains(t, err, "failed to parse snapshot")
+}
@@ -0,0 +1,256 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SplitByManagementZone is the value of Split to split downloads by management zone
+const SplitByManagementZone = "management-zone"
+
+// splitByTagPrefix prefixes the tag key of a Split by tag, e.g. 'tag:owner'
+const splitByTagPrefix = "tag:"
+
+// managementZoneTypes are the API and schema of management zone configs
+var managementZoneTypes = []string{"management-zone", "builtin:management-zones"}
+
+// Split defines how a download is split into one project per team. The zero value does not split downloads.
+type Split struct {
+	// ManagementZone splits configs by the single management zone they reference
+	ManagementZone bool
+	// TagKey splits configs by the value of the tag of this key, e.g. 'owner' for configs tagged 'owner:team-a'
+	TagKey string
+}
+
+// ParseSplit parses 'management-zone' or 'tag:<key>'. An empty string does not split downloads.
+func ParseSplit(s string) (Split, error) {
+	switch {
+	case s == "":
+		return Split{}, nil
+	case s == SplitByManagementZone:
+		return Split{ManagementZone: true}, nil
+	case strings.HasPrefix(s, splitByTagPrefix) && len(s) > len(splitByTagPrefix):
+		return Split{TagKey: strings.TrimPrefix(s, splitByTagPrefix)}, nil
+	default:
+		return Split{}, fmt.Errorf("invalid split %q! expected '%s' or '%s<tag key>'", s, SplitByManagementZone, splitByTagPrefix)
+	}
+}
+
+// Enabled returns whether downloads are split
+func (s Split) Enabled() bool {
+	return s.ManagementZone || s.TagKey != ""
+}
+
+// SplitProjects splits the downloaded configs into one project per management zone or tag value, named after it.
+// Configs which can not be attributed to exactly one of them are kept in the project of the given name, which is
+// always the first project returned. References to moved configs are updated, so projects reference each other.
+//
+// The configs of all projects are stored for the environment of the given project name, like CreateProjectData.
+func SplitProjects(configs project.ConfigsPerType, projectName string, s Split) []project.Project {
+	owners := make(map[coordinate.Coordinate]string)
+	for _, cs := range configs {
+		for _, c := range cs {
+			var owner string
+			if s.ManagementZone {
+				owner = managementZoneOwner(c, configs)
+			} else {
+				owner = tagOwner(c, s.TagKey)
+			}
+			if p := projectId(owner); p != "" && p != projectName {
+				owners[c.Coordinate] = p
+			}
+		}
+	}
+
+	moved := func(c coordinate.Coordinate) coordinate.Coordinate {
+		if p, found := owners[c]; found {
+			c.Project = p
+		}
+		return c
+	}
+
+	perProject := map[string]project.ConfigsPerType{projectName: {}}
+	for t, cs := range configs {
+		for _, c := range cs {
+			c.Coordinate = moved(c.Coordinate)
+			params := make(config.Parameters, len(c.Parameters))
+			for name, p := range c.Parameters {
+				if ref, ok := p.(*reference.ReferenceParameter); ok {
+					p = reference.NewWithCoordinate(moved(ref.Config), ref.Property)
+				}
+				params[name] = p
+			}
+			c.Parameters = params
+
+			if perProject[c.Coordinate.Project] == nil {
+				perProject[c.Coordinate.Project] = project.ConfigsPerType{}
+			}
+			perProject[c.Coordinate.Project][t] = append(perProject[c.Coordinate.Project][t], c)
+		}
+	}
+
+	ids := make([]string, 0, len(perProject))
+	for id := range perProject {
+		if id != projectName {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	result := []project.Project{CreateProjectData(perProject[projectName], projectName)}
+	for _, id := range ids {
+		log.Info("Split %d configurations into project '%s'", countConfigs(perProject[id]), id)
+		p := CreateProjectData(perProject[id], projectName)
+		p.Id = id
+		result = append(result, p)
+	}
+	return result
+}
+
+func countConfigs(configs project.ConfigsPerType) int {
+	n := 0
+	for _, cs := range configs {
+		n += len(cs)
+	}
+	return n
+}
+
+// managementZoneOwner returns the name of the management zone the config is, or references, or an empty string if it
+// references none or several management zones.
+func managementZoneOwner(c config.Config, configs project.ConfigsPerType) string {
+	if isManagementZone(c.Coordinate) {
+		return displayName(c)
+	}
+
+	var zones []coordinate.Coordinate
+	for _, ref := range c.References() {
+		if isManagementZone(ref) {
+			zones = append(zones, ref)
+		}
+	}
+	if len(zones) != 1 {
+		return ""
+	}
+
+	for _, zone := range configs[zones[0].Type] {
+		if zone.Coordinate == zones[0] {
+			return displayName(zone)
+		}
+	}
+	return ""
+}
+
+func isManagementZone(c coordinate.Coordinate) bool {
+	for _, t := range managementZoneTypes {
+		if c.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// displayName returns the value of the name parameter of the config, or the name property of its payload if it is not a
+// template placeholder.
+func displayName(c config.Config) string {
+	if v, ok := c.Parameters[config.NameParameter].(*valueParam.ValueParameter); ok {
+		if name := fmt.Sprint(v.Value); v.Value != nil && name != "" {
+			return name
+		}
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(c.Template.Content()), &payload); err == nil {
+		if name, ok := payload["name"].(string); ok && name != "" && !strings.Contains(name, "{{") {
+			return name
+		}
+	}
+	return ""
+}
+
+// tagOwner returns the value of the tag of the given key found in the payload of the config, or an empty string if it
+// has none or several different values. Tags are read from lists named 'tags', containing either strings like
+// 'key:value' or objects with 'key' and 'value' properties.
+func tagOwner(c config.Config, key string) string {
+	var payload interface{}
+	if err := json.Unmarshal([]byte(c.Template.Content()), &payload); err != nil {
+		return ""
+	}
+
+	values := make(map[string]struct{})
+	collectTagValues(payload, key, values)
+	if len(values) != 1 {
+		return ""
+	}
+	for v := range values {
+		return v
+	}
+	return ""
+}
+
+func collectTagValues(node interface{}, key string, values map[string]struct{}) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, v := range n {
+			if tags, ok := v.([]interface{}); ok && k == "tags" {
+				for _, tag := range tags {
+					if value, found := tagValue(tag, key); found {
+						values[value] = struct{}{}
+					}
+				}
+				continue
+			}
+			collectTagValues(v, key, values)
+		}
+	case []interface{}:
+		for _, v := range n {
+			collectTagValues(v, key, values)
+		}
+	}
+}
+
+func tagValue(tag interface{}, key string) (string, bool) {
+	switch t := tag.(type) {
+	case string:
+		k, v, found := strings.Cut(t, ":")
+		return v, found && k == key && v != ""
+	case map[string]interface{}:
+		if t["key"] != key {
+			return "", false
+		}
+		v, ok := t["value"].(string)
+		return v, ok && v != ""
+	}
+	return "", false
+}
+
+var invalidProjectIdChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// projectId turns a management zone name or tag value into a project ID usable as folder name
+func projectId(owner string) string {
+	return strings.Trim(invalidProjectIdChars.ReplaceAllString(strings.ToLower(owner), "-"), "-")
+}
@@ -0,0 +1,134 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package download
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestParseSplit(t *testing.T) {
+	s, err := ParseSplit("")
+	assert.NilError(t, err)
+	assert.Equal(t, s.Enabled(), false)
+
+	s, err = ParseSplit("management-zone")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, s, Split{ManagementZone: true})
+
+	s, err = ParseSplit("tag:owner")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, s, Split{TagKey: "owner"})
+
+	for _, invalid := range []string{"tag:", "team"} {
+		_, err = ParseSplit(invalid)
+		assert.ErrorContains(t, err, "invalid split")
+	}
+}
+
+func TestSplitProjects_ByManagementZone(t *testing.T) {
+	zone := coordinate.Coordinate{Project: "proj", Type: "management-zone", ConfigId: "zone"}
+	owned := coordinate.Coordinate{Project: "proj", Type: "alerting-profile", ConfigId: "owned"}
+	shared := coordinate.Coordinate{Project: "proj", Type: "alerting-profile", ConfigId: "shared"}
+
+	configs := project.ConfigsPerType{
+		zone.Type: {
+			{
+				Coordinate: zone,
+				Template:   template.NewDownloadTemplate("zone", "zone", `{"name": "{{.name}}", "rules": []}`),
+				Parameters: config.Parameters{config.NameParameter: &valueParam.ValueParameter{Value: "Team A"}},
+			},
+		},
+		owned.Type: {
+			{
+				Coordinate: owned,
+				Template:   template.NewDownloadTemplate("owned", "owned", `{}`),
+				Parameters: config.Parameters{"zone": refParam.NewWithCoordinate(zone, "id")},
+			},
+			{
+				Coordinate: shared,
+				Template:   template.NewDownloadTemplate("shared", "shared", `{}`),
+				Parameters: config.Parameters{"owned": refParam.NewWithCoordinate(owned, "id")},
+			},
+		},
+	}
+
+	projects := SplitProjects(configs, "proj", Split{ManagementZone: true})
+	assert.Equal(t, len(projects), 2)
+	assert.Equal(t, projects[0].Id, "proj")
+	assert.Equal(t, projects[1].Id, "team-a")
+
+	base := projects[0].Configs["proj"]
+	assert.Equal(t, len(base[owned.Type]), 1)
+	assert.Equal(t, base[owned.Type][0].Coordinate, shared)
+	assert.Equal(t, base[owned.Type][0].Parameters["owned"].(*refParam.ReferenceParameter).Config.Project, "team-a", "references to moved configs must be updated")
+
+	team := projects[1].Configs["proj"]
+	assert.Equal(t, team[zone.Type][0].Coordinate.Project, "team-a")
+	assert.Equal(t, team[owned.Type][0].Coordinate.Project, "team-a")
+	assert.Equal(t, team[owned.Type][0].Parameters["zone"].(*refParam.ReferenceParameter).Config.Project, "team-a")
+
+	assert.Equal(t, configs[owned.Type][1].Parameters["owned"].(*refParam.ReferenceParameter).Config.Project, "proj", "the downloaded configs must not be modified")
+}
+
+func TestSplitProjects_ByTag(t *testing.T) {
+	configs := project.ConfigsPerType{
+		"builtin:tags": {
+			{
+				Coordinate: coordinate.Coordinate{Project: "proj", Type: "builtin:tags", ConfigId: "string-tag"},
+				Template:   template.NewDownloadTemplate("string-tag", "string-tag", `{"rule": {"tags": ["owner:Team B", "env"]}}`),
+			},
+			{
+				Coordinate: coordinate.Coordinate{Project: "proj", Type: "builtin:tags", ConfigId: "object-tag"},
+				Template:   template.NewDownloadTemplate("object-tag", "object-tag", `{"tags": [{"key": "owner", "value": "Team B"}]}`),
+			},
+			{
+				Coordinate: coordinate.Coordinate{Project: "proj", Type: "builtin:tags", ConfigId: "ambiguous"},
+				Template:   template.NewDownloadTemplate("ambiguous", "ambiguous", `{"tags": ["owner:a", "owner:b"]}`),
+			},
+			{
+				Coordinate: coordinate.Coordinate{Project: "proj", Type: "builtin:tags", ConfigId: "untagged"},
+				Template:   template.NewDownloadTemplate("untagged", "untagged", `{}`),
+			},
+		},
+	}
+
+	projects := SplitProjects(configs, "proj", Split{TagKey: "owner"})
+	assert.Equal(t, len(projects), 2)
+	assert.Equal(t, len(projects[0].Configs["proj"]["builtin:tags"]), 2)
+	assert.Equal(t, projects[1].Id, "team-b")
+	assert.Equal(t, len(projects[1].Configs["proj"]["builtin:tags"]), 2)
+}
+
+func TestDisplayName(t *testing.T) {
+	templated := config.Config{Template: template.NewDownloadTemplate("zone", "zone", `{"name": "{{.name}}"}`)}
+	assert.Equal(t, displayName(templated), "", "template placeholders are no names")
+
+	templated.Parameters = config.Parameters{config.NameParameter: &valueParam.ValueParameter{Value: "Team A"}}
+	assert.Equal(t, displayName(templated), "Team A")
+
+	plain := config.Config{Template: template.NewDownloadTemplate("zone", "zone", `{"name": "Team B"}`)}
+	assert.Equal(t, displayName(plain), "Team B")
+}
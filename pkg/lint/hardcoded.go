@@ -0,0 +1,73 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"regexp"
+)
+
+// hardcodedPattern matches values in templates which are specific to a single environment.
+type hardcodedPattern struct {
+	kind       string
+	regex      *regexp.Regexp
+	suggestion string
+}
+
+var hardcodedPatterns = []hardcodedPattern{
+	{
+		kind:       "entity ID",
+		regex:      regexp.MustCompile(`\b[A-Z][A-Z_]*-[0-9A-F]{16}\b`),
+		suggestion: "entity IDs differ between environments, consider a reference to the config of the entity, or a parameter overridden per environment",
+	},
+	{
+		kind:       "environment URL",
+		regex:      regexp.MustCompile(`https?://(?:[a-z0-9-]+\.(?:live|apps|sprint\.apps|dev\.apps|sprint|dev)\.dynatrace(?:labs)?\.com|[a-zA-Z0-9.-]+/e/[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})`),
+		suggestion: "consider an 'environment' parameter or a parameter overridden per environment",
+	},
+	{
+		kind:       "email address",
+		regex:      regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+		suggestion: "recipients often differ between environments, consider a parameter overridden per environment",
+	},
+}
+
+// HardcodedValuesRule reports values in templates which are most likely specific to the environment the config was
+// downloaded from, like entity IDs, environment URLs and email addresses. Such configs can't be promoted to other
+// environments as they are, but need these values to be parameterized. As such values are often intended, e.g. in
+// configs deployed to a single environment only, findings are reported as warnings.
+var HardcodedValuesRule = Rule{
+	Id:      "hardcoded-value",
+	Warning: true,
+	Check: func(c config.Config) []string {
+		content := c.Template.Content()
+
+		var msgs []string
+		for _, p := range hardcodedPatterns {
+			reported := make(map[string]struct{})
+			for _, match := range p.regex.FindAllString(content, -1) {
+				if _, found := reported[match]; found {
+					continue
+				}
+				reported[match] = struct{}{}
+				msgs = append(msgs, fmt.Sprintf("template %q contains hard-coded %s %q: %s", c.Template.Name(), p.kind, match, p.suggestion))
+			}
+		}
+		return msgs
+	},
+}
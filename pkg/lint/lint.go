@@ -0,0 +1,142 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lint checks loaded projects for likely mistakes, which don't prevent a deployment, but most likely lead to
+// unintended results - e.g. parameters which are defined, but never used in the template.
+package lint
+
+import (
+	"fmt"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"sort"
+	"strings"
+)
+
+// Rule checks a config loaded for a single environment.
+type Rule struct {
+	// Id identifies the rule in findings
+	Id string
+	// Check returns a message for each problem found in the given config
+	Check func(c config.Config) []string
+	// Warning states that the problems found by the rule are worth looking at, but don't fail the check, as they are
+	// often intended
+	Warning bool
+}
+
+// DefaultRules holds all rules applied by the lint command.
+var DefaultRules = []Rule{
+	UnusedParametersRule,
+	UndefinedParametersRule,
+	ParentScopeRule,
+	HardcodedValuesRule,
+}
+
+// Finding is a problem reported by a rule for a config.
+type Finding struct {
+	// Rule is the id of the rule reporting the problem
+	Rule string
+	// Coordinate of the config the problem was found in
+	Coordinate coordinate.Coordinate
+	// Environments holds the names of all environments the problem was found for, as configs are checked
+	// separately for every environment they are loaded for
+	Environments []string
+	// Message describes the problem
+	Message string
+	// Warning states that the finding doesn't fail the check, see Rule.Warning
+	Warning bool
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s (environments: %s): %s [%s]", f.Coordinate, strings.Join(f.Environments, ", "), f.Message, f.Rule)
+}
+
+// WithoutRules returns the given rules, except the ones with the given ids. An error is returned if an id doesn't
+// identify any of the rules.
+func WithoutRules(rules []Rule, ids []string) ([]Rule, error) {
+	disabled := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		disabled[id] = true
+	}
+
+	var filtered []Rule
+	for _, r := range rules {
+		if disabled[r.Id] {
+			delete(disabled, r.Id)
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+
+	if len(disabled) > 0 {
+		unknown := make([]string, 0, len(disabled))
+		for id := range disabled {
+			unknown = append(unknown, id)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown rule(s) %s", strings.Join(unknown, ", "))
+	}
+	return filtered, nil
+}
+
+// Lint checks all configs of the given projects using the given rules. Equal findings of several environments are
+// merged into a single one. Findings are sorted by config coordinate, rule and message.
+func Lint(projects []project.Project, rules []Rule) []Finding {
+	type key struct {
+		rule       string
+		coordinate coordinate.Coordinate
+		message    string
+		warning    bool
+	}
+
+	environments := make(map[key][]string)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			for _, r := range rules {
+				for _, msg := range r.Check(c) {
+					k := key{rule: r.Id, coordinate: c.Coordinate, message: msg, warning: r.Warning}
+					environments[k] = append(environments[k], c.Environment)
+				}
+			}
+		})
+	}
+
+	findings := make([]Finding, 0, len(environments))
+	for k, envs := range environments {
+		sort.Strings(envs)
+		findings = append(findings, Finding{
+			Rule:         k.rule,
+			Coordinate:   k.coordinate,
+			Environments: envs,
+			Message:      k.message,
+			Warning:      k.warning,
+		})
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Coordinate != b.Coordinate {
+			return a.Coordinate.String() < b.Coordinate.String()
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Message < b.Message
+	})
+
+	return findings
+}
@@ -0,0 +1,326 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/compound"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testCoordinate = coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "config"}
+
+func newConfig(env, content string, params config.Parameters) config.Config {
+	return config.Config{
+		Template:    template.CreateTemplateFromString("template.json", content),
+		Coordinate:  testCoordinate,
+		Environment: env,
+		Parameters:  params,
+	}
+}
+
+func newCompoundParameter(t *testing.T, name, format string, refs ...parameter.ParameterReference) *compound.CompoundParameter {
+	p, err := compound.New(name, format, refs)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestUnusedParametersRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		params  config.Parameters
+		want    []string
+	}{
+		{
+			name:    "all parameters used",
+			content: `{"name": "{{ .name }}", "a": "{{ .a.nested }}", "b": {{ if .b }}1{{ end }}}`,
+			params:  config.Parameters{"name": value.New("n"), "a": value.New("a"), "b": value.New("b")},
+		},
+		{
+			name:    "reserved parameters are never reported",
+			content: `{}`,
+			params:  config.Parameters{config.NameParameter: value.New("n"), config.ScopeParameter: value.New("s")},
+		},
+		{
+			name:    "unused parameters are reported",
+			content: `{"a": "{{ .a }}"}`,
+			params:  config.Parameters{"a": value.New("a"), "c": value.New("c"), "b": value.New("b")},
+			want: []string{
+				`parameter "b" is defined, but never used in template "template.json"`,
+				`parameter "c" is defined, but never used in template "template.json"`,
+			},
+		},
+		{
+			name:    "parameters used by other parameters are not reported",
+			content: `{"a": "{{ .a }}"}`,
+			params: config.Parameters{
+				"a": newCompoundParameter(t, "a", "{{ .b }}", parameter.ParameterReference{Config: testCoordinate, Property: "b"}),
+				"b": value.New("b"),
+			},
+		},
+		{
+			name:    "fields within range refer to the element",
+			content: `[{{ range .list }}"{{ .unknown }}{{ $.b }}"{{ end }}]`,
+			params:  config.Parameters{"list": value.New("l"), "b": value.New("b"), "unknown": value.New("u")},
+			want:    []string{`parameter "unknown" is defined, but never used in template "template.json"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnusedParametersRule.Check(newConfig("env", tt.content, tt.params))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnusedParametersRule_IgnoresProjectVariables(t *testing.T) {
+	c := newConfig("env", `{}`, config.Parameters{"application": value.New("payment"), "b": value.New("b")})
+	c.Variables = []string{"application"}
+
+	got := UnusedParametersRule.Check(c)
+	assert.Equal(t, []string{`parameter "b" is defined, but never used in template "template.json"`}, got)
+}
+
+func TestUndefinedParametersRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		params  config.Parameters
+		want    []string
+	}{
+		{
+			name:    "all placeholders defined",
+			content: `{"a": "{{ .a }}", "b": {{ with .b }}"{{ .c }}"{{ else }}"{{ .d }}"{{ end }}}`,
+			params:  config.Parameters{"a": value.New("a"), "b": value.New("b"), "d": value.New("d")},
+		},
+		{
+			name:    "undefined placeholders are reported",
+			content: `{"a": "{{ .a }}", "b": "{{ .b | printf "%s" }}"}`,
+			params:  config.Parameters{},
+			want: []string{
+				`template "template.json" uses placeholder "{{ .a }}", but no parameter "a" is defined`,
+				`template "template.json" uses placeholder "{{ .b }}", but no parameter "b" is defined`,
+			},
+		},
+		{
+			name:    "invalid templates are reported",
+			content: `{"a": "{{ .a }"}`,
+			want:    []string{`failed to parse template "template.json": template: template.json:1: unexpected "}" in operand`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UndefinedParametersRule.Check(newConfig("env", tt.content, tt.params))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLint_MergesFindingsOfEnvironments(t *testing.T) {
+	p := project.Project{
+		Id: "project",
+		Configs: project.ConfigsPerTypePerEnvironments{
+			"env2": {"dashboard": {newConfig("env2", `{}`, config.Parameters{"a": value.New("a")})}},
+			"env1": {"dashboard": {newConfig("env1", `{}`, config.Parameters{"a": value.New("a")})}},
+			"env3": {"dashboard": {newConfig("env3", `{"a": "{{ .a }}"}`, config.Parameters{"a": value.New("a")})}},
+		},
+	}
+
+	got := Lint([]project.Project{p}, DefaultRules)
+
+	assert.Equal(t, []Finding{
+		{
+			Rule:         UnusedParametersRule.Id,
+			Coordinate:   testCoordinate,
+			Environments: []string{"env1", "env2"},
+			Message:      `parameter "a" is defined, but never used in template "template.json"`,
+		},
+	}, got)
+}
+
+func TestParentScopeRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		api    string
+		params config.Parameters
+		want   []string
+	}{
+		{
+			name:   "scope references parent config",
+			api:    "key-user-actions-web",
+			params: config.Parameters{config.ScopeParameter: reference.New("project", "application-web", "app", "id")},
+		},
+		{
+			name:   "scope is an object ID",
+			api:    "key-user-actions-web",
+			params: config.Parameters{config.ScopeParameter: value.New("APPLICATION-1234")},
+		},
+		{
+			name: "missing scope is reported",
+			api:  "key-user-actions-web",
+			want: []string{`configs of API "key-user-actions-web" require the ID of their parent "application-web" as scope, but no scope is defined`},
+		},
+		{
+			name:   "scope referencing other type is reported",
+			api:    "key-user-actions-mobile",
+			params: config.Parameters{config.ScopeParameter: reference.New("project", "application-web", "app", "id")},
+			want:   []string{`scope references "project:application-web:app", but configs of API "key-user-actions-mobile" are scoped to configs of API "application-mobile"`},
+		},
+		{
+			name:   "scope of other classic APIs is reported",
+			api:    "alerting-profile",
+			params: config.Parameters{config.ScopeParameter: value.New("APPLICATION-1234")},
+			want:   []string{`scope is defined, but configs of API "alerting-profile" are not scoped to a parent and ignore it`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig("env", `{}`, tt.params)
+			c.Type = config.ClassicApiType{Api: tt.api}
+
+			got := ParentScopeRule.Check(c)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHardcodedValuesRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "parameterized template",
+			content: `{"host": "{{ .hostId }}", "url": "{{ .url }}", "recipients": ["{{ .team }}"]}`,
+		},
+		{
+			name:    "entity IDs are reported once",
+			content: `{"hosts": ["HOST-1234567890ABCDEF", "HOST-1234567890ABCDEF"], "pg": "PROCESS_GROUP-ABCDEF1234567890"}`,
+			want: []string{
+				`template "template.json" contains hard-coded entity ID "HOST-1234567890ABCDEF": entity IDs differ between environments, consider a reference to the config of the entity, or a parameter overridden per environment`,
+				`template "template.json" contains hard-coded entity ID "PROCESS_GROUP-ABCDEF1234567890": entity IDs differ between environments, consider a reference to the config of the entity, or a parameter overridden per environment`,
+			},
+		},
+		{
+			name:    "environment URLs are reported",
+			content: `{"saas": "https://abc12345.live.dynatrace.com/ui/dashboards", "managed": "https://managed.example.com/e/0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d/api", "other": "https://www.dynatrace.com"}`,
+			want: []string{
+				`template "template.json" contains hard-coded environment URL "https://abc12345.live.dynatrace.com": consider an 'environment' parameter or a parameter overridden per environment`,
+				`template "template.json" contains hard-coded environment URL "https://managed.example.com/e/0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d": consider an 'environment' parameter or a parameter overridden per environment`,
+			},
+		},
+		{
+			name:    "email addresses are reported",
+			content: `{"recipients": ["team-a@example.com"]}`,
+			want:    []string{`template "template.json" contains hard-coded email address "team-a@example.com": recipients often differ between environments, consider a parameter overridden per environment`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HardcodedValuesRule.Check(newConfig("env", tt.content, nil))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCollectKnownObjects(t *testing.T) {
+	downloaded := newConfig("env", `{}`, nil)
+	downloaded.Coordinate = coordinate.Coordinate{Project: "project", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	downloaded.OriginObjectId = "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU"
+
+	classic := newConfig("env", `{}`, nil)
+	classic.Type = config.ClassicApiType{Api: "dashboard"}
+	classic.Coordinate = coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"}
+
+	named := newConfig("env", `{}`, nil)
+	named.Type = config.ClassicApiType{Api: "dashboard"}
+	named.Coordinate = coordinate.Coordinate{Project: "project", Type: "dashboard", ConfigId: "overview-dashboard"}
+
+	short := newConfig("env", `{}`, nil)
+	short.OriginObjectId = "1234"
+
+	p := project.Project{
+		Id: "project",
+		Configs: project.ConfigsPerTypePerEnvironments{
+			"env": {"any": {downloaded, classic, named, short}},
+		},
+	}
+
+	assert.Equal(t, KnownObjects{
+		downloaded.OriginObjectId:              downloaded.Coordinate,
+		"0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d": classic.Coordinate,
+	}, CollectKnownObjects([]project.Project{p}))
+}
+
+func TestReferenceSuggestionRule(t *testing.T) {
+	profile := coordinate.Coordinate{Project: "other", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	known := KnownObjects{
+		"vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU": profile,
+		"0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d":                testCoordinate,
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "raw ID of other config",
+			content: `{"alertingProfile": "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU"}`,
+			want:    []string{`template "template.json" contains ID "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU" of the object deployed by config "other:builtin:alerting.profile:profile", consider replacing it with a reference parameter: {type: reference, project: other, configType: builtin:alerting.profile, configId: profile, property: id}`},
+		},
+		{
+			name:    "ID of the config itself",
+			content: `{"id": "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"}`,
+		},
+		{
+			name:    "ID as part of a longer ID",
+			content: `{"alertingProfile": "xvu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU-2"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReferenceSuggestionRule(known).Check(newConfig("env", tt.content, nil))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithoutRules(t *testing.T) {
+	got, err := WithoutRules(DefaultRules, []string{HardcodedValuesRule.Id, ParentScopeRule.Id})
+	assert.NoError(t, err)
+
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.Id)
+	}
+	assert.Equal(t, []string{UnusedParametersRule.Id, UndefinedParametersRule.Id}, ids)
+
+	_, err = WithoutRules(DefaultRules, []string{"unknown", HardcodedValuesRule.Id})
+	assert.EqualError(t, err, "unknown rule(s) unknown")
+}
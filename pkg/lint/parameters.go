@@ -0,0 +1,89 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"sort"
+)
+
+// UnusedParametersRule reports parameters which are neither used in the template, nor by another parameter of the
+// same config. Reserved parameters like 'name' are used by monaco itself and never reported, neither are project
+// variables, as they are not specific to the config.
+var UnusedParametersRule = Rule{
+	Id: "unused-parameter",
+	Check: func(c config.Config) []string {
+		fields, err := template.Fields(c.Template)
+		if err != nil {
+			return nil // reported by UndefinedParametersRule
+		}
+
+		used := make(map[string]struct{}, len(fields))
+		for _, f := range fields {
+			used[f] = struct{}{}
+		}
+		for _, p := range c.Parameters {
+			for _, ref := range p.GetReferences() {
+				if ref.Config == c.Coordinate {
+					used[ref.Property] = struct{}{}
+				}
+			}
+		}
+
+		var msgs []string
+		for _, name := range SortedParameterNames(c.Parameters) {
+			if _, found := used[name]; found || slices.Contains(config.ReservedParameterNames, name) || slices.Contains(c.Variables, name) {
+				continue
+			}
+			msgs = append(msgs, fmt.Sprintf("parameter %q is defined, but never used in template %q", name, c.Template.Name()))
+		}
+		return msgs
+	},
+}
+
+// UndefinedParametersRule reports placeholders of the template for which no parameter is defined. Rendering such a
+// template fails.
+var UndefinedParametersRule = Rule{
+	Id: "undefined-parameter",
+	Check: func(c config.Config) []string {
+		fields, err := template.Fields(c.Template)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to parse template %q: %s", c.Template.Name(), err)}
+		}
+
+		var msgs []string
+		for _, f := range fields {
+			if _, found := c.Parameters[f]; !found {
+				msgs = append(msgs, fmt.Sprintf("template %q uses placeholder %q, but no parameter %q is defined", c.Template.Name(), "{{ ."+f+" }}", f))
+			}
+		}
+		return msgs
+	},
+}
+
+// SortedParameterNames returns the names of the given parameters in alphabetical order.
+func SortedParameterNames(params config.Parameters) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
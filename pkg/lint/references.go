@@ -0,0 +1,112 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"regexp"
+	"sort"
+)
+
+// minObjectIdLength is the minimum length of object IDs looked for in templates. Shorter IDs are too likely to match
+// unrelated text.
+const minObjectIdLength = 8
+
+// classicObjectIdPattern matches the IDs of objects of classic APIs, which downloaded configs use as config ID: UUIDs
+// and entity IDs like APPLICATION-1234567890ABCDEF.
+var classicObjectIdPattern = regexp.MustCompile(`^(?:[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[A-Z][A-Z_]*-[0-9A-F]{16})$`)
+
+// KnownObjects maps the IDs of Dynatrace objects to the coordinates of the configs deploying them.
+type KnownObjects map[string]coordinate.Coordinate
+
+// CollectKnownObjects returns the objects deployed by the configs of the given projects, as far as their IDs are
+// known: the IDs of the objects configs were downloaded from, and the IDs of classic configs downloaded with their
+// object ID as config ID.
+func CollectKnownObjects(projects []project.Project) KnownObjects {
+	known := make(KnownObjects)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			if c.OriginObjectId != "" {
+				known.Add(c.OriginObjectId, c.Coordinate)
+				return
+			}
+			if _, isClassic := c.Type.(config.ClassicApiType); isClassic && classicObjectIdPattern.MatchString(c.Coordinate.ConfigId) {
+				known.Add(c.Coordinate.ConfigId, c.Coordinate)
+			}
+		})
+	}
+	return known
+}
+
+// Add records that the object of the given ID is deployed by the config at the given coordinate. IDs which are too
+// short to be told apart from unrelated text, or already known, are ignored.
+func (k KnownObjects) Add(id string, c coordinate.Coordinate) {
+	if len(id) < minObjectIdLength {
+		return
+	}
+	if _, found := k[id]; !found {
+		k[id] = c
+	}
+}
+
+// RawReference is the ID of an object deployed by another config, contained as is in the template of a config.
+type RawReference struct {
+	// Id of the object
+	Id string
+	// Target is the coordinate of the config deploying the object
+	Target coordinate.Coordinate
+}
+
+// FindRawReferences returns the IDs of known objects deployed by other configs which are contained in the template of
+// the given config, sorted by ID. IDs are only found as a whole, not as part of longer IDs.
+func FindRawReferences(c config.Config, known KnownObjects) []RawReference {
+	content := c.Template.Content()
+
+	var result []RawReference
+	for id, target := range known {
+		if target == c.Coordinate || !idutils.ContainsId(content, id) {
+			continue
+		}
+		result = append(result, RawReference{Id: id, Target: target})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+	return result
+}
+
+// ReferenceSuggestionRule reports IDs of objects deployed by other configs which are contained as is in templates,
+// suggesting to replace them with reference parameters. References keep working if the IDs differ between
+// environments, and ensure that the referenced configs are deployed first.
+func ReferenceSuggestionRule(known KnownObjects) Rule {
+	return Rule{
+		Id: "raw-reference",
+		Check: func(c config.Config) []string {
+			var msgs []string
+			for _, r := range FindRawReferences(c, known) {
+				msgs = append(msgs, fmt.Sprintf("template %q contains ID %q of the object deployed by config %q, consider replacing it with a reference parameter: {type: reference, project: %s, configType: %s, configId: %s, property: id}",
+					c.Template.Name(), r.Id, r.Target, r.Target.Project, r.Target.Type, r.Target.ConfigId))
+			}
+			return msgs
+		},
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lint
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+)
+
+// ParentScopeRule reports classic configs whose scope doesn't match their API. Configs of parent-scoped APIs (e.g.
+// key user actions) need the ID of their parent object as scope, preferably as reference to the parent config, while
+// the scope of configs of other classic APIs is ignored.
+var ParentScopeRule = Rule{
+	Id: "parent-scope",
+	Check: func(c config.Config) []string {
+		t, ok := c.Type.(config.ClassicApiType)
+		if !ok {
+			return nil
+		}
+		a, found := api.NewAPIs()[t.Api]
+		if !found {
+			return nil
+		}
+
+		scope, hasScope := c.Parameters[config.ScopeParameter]
+		if !a.HasParent() {
+			if hasScope {
+				return []string{fmt.Sprintf("scope is defined, but configs of API %q are not scoped to a parent and ignore it", a.ID)}
+			}
+			return nil
+		}
+
+		if !hasScope {
+			return []string{fmt.Sprintf("configs of API %q require the ID of their parent %q as scope, but no scope is defined", a.ID, a.Parent)}
+		}
+
+		var msgs []string
+		for _, ref := range scope.GetReferences() {
+			if ref.Config.Type != a.Parent {
+				msgs = append(msgs, fmt.Sprintf("scope references %q, but configs of API %q are scoped to configs of API %q", ref.Config, a.ID, a.Parent))
+			}
+		}
+		return msgs
+	},
+}
@@ -15,14 +15,22 @@
 package manifest
 
 import (
+	"crypto/tls"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/maps"
+	"time"
 )
 
 type ProjectDefinition struct {
 	Name  string
 	Group string
 	Path  string
+	// Variables are available as parameters to all configs of the project. They allow instantiating the configs of
+	// the same path several times as different projects, e.g. once per application.
+	Variables map[string]string
+	// Overrides holds the names of projects whose Settings 2.0 configs are intentionally replaced by configs of this
+	// project deployed to the same object. Grouping projects can be given by their group name.
+	Overrides []string
 }
 
 func (p ProjectDefinition) String() string {
@@ -36,6 +44,11 @@ type OAuth struct {
 	ClientID      AuthSecret
 	ClientSecret  AuthSecret
 	TokenEndpoint *URLDefinition
+	// Scopes optionally restricts the scopes requested for the access token
+	Scopes []string
+	// Audience is optionally sent as 'audience' parameter of the token request, e.g. if the token endpoint is a
+	// custom SSO issuing tokens for several audiences
+	Audience string
 }
 
 // GetTokenEndpointValue returns the defined token endpoint or an empty string if it's not set.
@@ -57,6 +70,26 @@ type EnvironmentDefinition struct {
 	Group string
 	URL   URLDefinition
 	Auth  Auth
+
+	// RequestAdditions holds headers and query parameters added to all HTTP requests against this environment.
+	// They are applied after the ones defined in [HTTPSettings.RequestAdditions] and take precedence for equal keys.
+	RequestAdditions []RequestAddition
+
+	// ClientCertificate is the optional certificate presented when establishing TLS connections to this environment
+	ClientCertificate *ClientCertificate
+}
+
+// ClientCertificate holds a client certificate used for mutual TLS, e.g. required by gateways in front of Dynatrace
+// Managed.
+type ClientCertificate struct {
+	// CertFile is the path to the PEM encoded certificate as defined in the manifest
+	CertFile string
+
+	// KeyFile is the path to the PEM encoded private key as defined in the manifest
+	KeyFile string
+
+	// Certificate is the certificate and key loaded from CertFile and KeyFile during manifest reading
+	Certificate tls.Certificate
 }
 
 // URLType describes from where the url is loaded.
@@ -97,6 +130,25 @@ type AuthSecret struct {
 
 type ProjectDefinitionByProjectID map[string]ProjectDefinition
 
+// AccountDefinition holds all information about a Dynatrace account, whose users, groups and policies are managed
+// via the account management API.
+type AccountDefinition struct {
+	// Name is the name of the account used within monaco
+	Name string
+
+	// AccountUUID is the UUID of the account
+	AccountUUID string
+
+	// ApiURL is the optional URL of the account management API. If not set, the default Dynatrace API URL is used.
+	ApiURL *URLDefinition
+
+	// OAuth holds the OAuth client credentials used to access the account management API
+	OAuth OAuth
+}
+
+// Accounts is a map of account-name -> AccountDefinition
+type Accounts map[string]AccountDefinition
+
 // Environments is a map of environment-name -> EnvironmentDefinition
 type Environments map[string]EnvironmentDefinition
 
@@ -105,10 +157,104 @@ func (e Environments) Names() []string {
 	return maps.Keys(e)
 }
 
+// HTTPSettings holds settings applied to all HTTP calls made against the environments of a manifest.
+// A zero value means that no explicit timeout is set.
+type HTTPSettings struct {
+	// ConnectTimeout is the maximum time to wait for a connection to an environment to be established
+	ConnectTimeout time.Duration
+
+	// RequestTimeout is the maximum time a single HTTP request, including reading the response, may take
+	RequestTimeout time.Duration
+
+	// RequestAdditions holds headers and query parameters added to HTTP requests against all environments
+	RequestAdditions []RequestAddition
+
+	// ClientCertificate is the certificate used for mutual TLS. It is only set by [HTTPSettings.ForEnvironment].
+	ClientCertificate *tls.Certificate
+}
+
+// ForEnvironment returns the HTTP settings to use for the given environment. The environment's request additions are
+// appended after the manifest-wide ones, so that they take precedence.
+func (s HTTPSettings) ForEnvironment(env EnvironmentDefinition) HTTPSettings {
+	if env.ClientCertificate != nil {
+		s.ClientCertificate = &env.ClientCertificate.Certificate
+	}
+
+	if len(env.RequestAdditions) == 0 {
+		return s
+	}
+
+	additions := make([]RequestAddition, 0, len(s.RequestAdditions)+len(env.RequestAdditions))
+	additions = append(additions, s.RequestAdditions...)
+	additions = append(additions, env.RequestAdditions...)
+	s.RequestAdditions = additions
+	return s
+}
+
+// RequestAddition defines headers and query parameters added to HTTP requests.
+type RequestAddition struct {
+	// PathPrefix restricts the additions to requests whose URL path starts with it. If empty, they apply to all requests.
+	PathPrefix string
+
+	// Headers are set on matching requests, replacing headers of the same name.
+	Headers map[string]string
+
+	// QueryParameters are set on matching requests, replacing query parameters of the same name.
+	QueryParameters map[string]string
+}
+
 type Manifest struct {
 	// Projects defined in the manifest, split by project-name
 	Projects ProjectDefinitionByProjectID
 
 	// Environments defined in the manifest, split by environment-name
 	Environments Environments
+
+	// AccountProjects defined in the manifest, split by project-name. Instead of configs, they hold the users, groups
+	// and policies of accounts.
+	AccountProjects ProjectDefinitionByProjectID
+
+	// Accounts defined in the manifest, split by account-name
+	Accounts Accounts
+
+	// HTTP holds the optional HTTP settings defined in the manifest
+	HTTP HTTPSettings
+
+	// Offline holds the optional settings for environments without access to the internet
+	Offline OfflineSettings
+
+	// Rollout optionally defines the order environment groups are deployed in
+	Rollout *RolloutPolicy
+
+	// VariablesFile is the path of the variables file as defined in the manifest, relative to the manifest unless
+	// absolute. It is empty if the manifest defines none.
+	VariablesFile string
+}
+
+// RolloutPolicy defines the stages a deployment progresses through, e.g. dev, staging and prod. Stages are deployed one
+// after another, and the deployment halts at the first stage failing.
+type RolloutPolicy struct {
+	Stages []RolloutStage
+}
+
+// RolloutStage holds environment groups deployed together.
+type RolloutStage struct {
+	Groups []string
+
+	// Wait is the time waited before the stage is deployed, e.g. to let earlier stages surface problems
+	Wait time.Duration
+
+	// Approval states that the stage is only deployed once it is approved manually
+	Approval bool
+}
+
+// OfflineSettings holds settings for environments without access to the internet, e.g. air-gapped Dynatrace Managed
+// setups.
+type OfflineSettings struct {
+	// Enabled defines whether auxiliary calls, like verifying the type and version of environments, are skipped.
+	Enabled bool
+
+	// OAuthTokenEndpoint is the token endpoint used instead of the public Dynatrace SSO. It is already applied to all
+	// environments and accounts not defining their own token endpoint during manifest reading.
+	OAuthTokenEndpoint *URLDefinition
 }
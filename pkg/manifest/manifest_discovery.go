@@ -0,0 +1,217 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	accountapi "github.com/dynatrace/dynatrace-configuration-as-code/pkg/account"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/rest"
+	"regexp"
+	"strings"
+)
+
+// defaultDiscoveryURL is the URL of discovered environments if a discovery does not define one
+const defaultDiscoveryURL = "https://{{ .id }}.live.dynatrace.com"
+
+// EnvironmentDiscoverer returns the environments of the given account.
+type EnvironmentDiscoverer func(ctx context.Context, a AccountDefinition) ([]accountapi.Environment, error)
+
+// accountAPIDiscoverer returns a discoverer listing the environments of accounts using the account management API.
+// Requests are made with the timeouts and request additions of the given HTTP settings.
+func accountAPIDiscoverer(httpSettings HTTPSettings) EnvironmentDiscoverer {
+	additions := make([]rest.RequestAddition, len(httpSettings.RequestAdditions))
+	for i, a := range httpSettings.RequestAdditions {
+		additions[i] = rest.RequestAddition{PathPrefix: a.PathPrefix, Headers: a.Headers, QueryParameters: a.QueryParameters}
+	}
+
+	return func(ctx context.Context, a AccountDefinition) ([]accountapi.Environment, error) {
+		credentials := client.OauthCredentials{
+			ClientID:     a.OAuth.ClientID.Value,
+			ClientSecret: a.OAuth.ClientSecret.Value,
+			TokenURL:     a.OAuth.GetTokenEndpointValue(),
+			Scopes:       a.OAuth.Scopes,
+			Audience:     a.OAuth.Audience,
+		}
+		if len(credentials.Scopes) == 0 {
+			credentials.Scopes = accountapi.EnvironmentOAuthScopes
+		}
+
+		httpClient := client.NewOAuthClient(ctx, credentials)
+		client.ApplyHTTPTimeouts(httpClient, client.HTTPTimeouts{Connect: httpSettings.ConnectTimeout, Request: httpSettings.RequestTimeout})
+		if len(additions) > 0 {
+			httpClient.Transport = rest.NewRequestAdditionsTransport(httpClient.Transport, additions)
+		}
+
+		apiURL := ""
+		if a.ApiURL != nil {
+			apiURL = a.ApiURL.Value
+		}
+		return accountapi.NewClient(httpClient, apiURL, a.AccountUUID).ListEnvironments(ctx)
+	}
+}
+
+// discoverEnvironments adds the discovered environments to all groups defining a discovery. Groups not selected by
+// the loader context are not discovered.
+func discoverEnvironments(context *LoaderContext, m *manifest, httpSettings HTTPSettings, offline OfflineSettings) []error {
+	discover := context.DiscoverEnvironments
+	if discover == nil {
+		discover = accountAPIDiscoverer(httpSettings)
+	}
+
+	var errs []error
+	for i := range m.EnvironmentGroups {
+		g := &m.EnvironmentGroups[i]
+		if g.Discover == nil || !shouldDiscover(context, g.Name) {
+			continue
+		}
+
+		envs, err := discoverGroup(context.ctx(), discover, m.Accounts, offline, *g.Discover)
+		if err != nil {
+			errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("failed to discover environments of group %q: %s", g.Name, err)})
+			continue
+		}
+		log.Debug("Discovered %d environment(s) in group %q", len(envs), g.Name)
+		g.Environments = append(g.Environments, envs...)
+	}
+	return errs
+}
+
+// definesDiscovery returns whether any of the given groups defines a discovery.
+func definesDiscovery(groups []group) bool {
+	for _, g := range groups {
+		if g.Discover != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func shouldDiscover(context *LoaderContext, group string) bool {
+	// environments may be discovered in any group
+	if len(context.Groups) == 0 || len(context.Environments) > 0 {
+		return true
+	}
+	return slices.Contains(context.Groups, group)
+}
+
+// discoverGroup discovers the environments of a group. In offline mode, the account needs to define the URL of the
+// account management API, and the token endpoint of the offline settings is used unless the account defines one.
+func discoverGroup(ctx context.Context, discover EnvironmentDiscoverer, accounts []account, offline OfflineSettings, d discovery) ([]environment, error) {
+	var def *AccountDefinition
+	for _, a := range accounts {
+		if a.Name == d.Account {
+			parsed, err := parseAccount(a)
+			if err != nil {
+				return nil, fmt.Errorf("invalid account `%s`: %w", a.Name, err)
+			}
+			def = &parsed
+		}
+	}
+	if def == nil {
+		return nil, fmt.Errorf("account %q is not defined", d.Account)
+	}
+
+	if offline.Enabled && def.ApiURL == nil {
+		return nil, fmt.Errorf("account %q needs to define `apiUrl` to discover environments in offline mode", d.Account)
+	}
+	if offline.OAuthTokenEndpoint != nil && def.OAuth.TokenEndpoint == nil {
+		def.OAuth.TokenEndpoint = offline.OAuthTokenEndpoint
+	}
+
+	var pattern *regexp.Regexp
+	if d.NamePattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(d.NamePattern); err != nil {
+			return nil, fmt.Errorf("invalid `namePattern`: %w", err)
+		}
+	}
+
+	discovered, err := discover(ctx, *def)
+	if err != nil {
+		return nil, err
+	}
+
+	urlTemplate := d.URL
+	if urlTemplate == "" {
+		urlTemplate = defaultDiscoveryURL
+	}
+
+	var result []environment
+	for _, e := range discovered {
+		if pattern != nil && !pattern.MatchString(e.Name) {
+			continue
+		}
+		if !hasTags(e, d.Tags) {
+			continue
+		}
+
+		env, err := discoveredEnvironment(e, urlTemplate, d.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("environment %q: %w", e.ID, err)
+		}
+		result = append(result, env)
+	}
+	return result, nil
+}
+
+// hasTags returns whether the environment has all given tags. Tags given as 'key' match any tag of that key, tags given
+// as 'key:value' only match the same value.
+func hasTags(e accountapi.Environment, tags []string) bool {
+	for _, t := range tags {
+		found := false
+		for _, et := range e.Tags {
+			if key, _, _ := strings.Cut(et, ":"); et == t || (!strings.Contains(t, ":") && key == t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// discoveredEnvironment returns the definition of a discovered environment, which is named by its ID.
+func discoveredEnvironment(e accountapi.Environment, urlTemplate string, a auth) (environment, error) {
+	vars := variables{"id": e.ID, "name": e.Name}
+
+	u, err := vars.resolve(urlTemplate)
+	if err != nil {
+		return environment{}, fmt.Errorf("invalid `url`: %w", err)
+	}
+
+	if a.Token.Name, err = vars.resolve(a.Token.Name); err != nil {
+		return environment{}, fmt.Errorf("invalid `auth`: %w", err)
+	}
+	if a.OAuth != nil {
+		o := *a.OAuth
+		if o.ClientID.Name, err = vars.resolve(o.ClientID.Name); err != nil {
+			return environment{}, fmt.Errorf("invalid `auth`: %w", err)
+		}
+		if o.ClientSecret.Name, err = vars.resolve(o.ClientSecret.Name); err != nil {
+			return environment{}, fmt.Errorf("invalid `auth`: %w", err)
+		}
+		a.OAuth = &o
+	}
+
+	return environment{Name: e.ID, URL: url{Type: urlTypeValue, Value: u}, Auth: a}, nil
+}
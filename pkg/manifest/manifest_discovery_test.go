@@ -0,0 +1,136 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"context"
+	"errors"
+	accountapi "github.com/dynatrace/dynatrace-configuration-as-code/pkg/account"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const discoveringManifest = `manifestVersion: 1.0
+projects: [{name: a}]
+accounts:
+- name: my-account
+  accountUUID: 8e7c4dd6-3bf2-4c3a-9d4b-8e2c5d5e2f1a
+  oAuth: {clientId: {name: CLIENT_ID}, clientSecret: {name: CLIENT_SECRET}}
+environmentGroups:
+- name: static
+  environments:
+  - {name: static, url: {value: "https://static.example.com"}, auth: {token: {name: TOKEN}}}
+- name: discovered
+  discover:
+    account: my-account
+    namePattern: "^prod-"
+    tags: [team, "stage:prod"]
+    auth: {token: {name: "TOKEN_{{ .id }}"}}
+`
+
+func TestLoadManifest_DiscoversEnvironments(t *testing.T) {
+	t.Setenv("TOKEN", "mock token")
+	t.Setenv("TOKEN_abc12345", "mock token")
+	t.Setenv("CLIENT_ID", "client id")
+	t.Setenv("CLIENT_SECRET", "client secret")
+
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(discoveringManifest), 0400))
+
+	discover := func(_ context.Context, a AccountDefinition) ([]accountapi.Environment, error) {
+		assert.Equal(t, "8e7c4dd6-3bf2-4c3a-9d4b-8e2c5d5e2f1a", a.AccountUUID)
+		return []accountapi.Environment{
+			{ID: "abc12345", Name: "prod-eu", Tags: []string{"team:a", "stage:prod"}},
+			{ID: "def45678", Name: "prod-us", Tags: []string{"team:b", "stage:canary"}},
+			{ID: "xyz98765", Name: "dev-eu", Tags: []string{"team:a", "stage:prod"}},
+		}, nil
+	}
+
+	t.Run("matching environments are added to the group", func(t *testing.T) {
+		m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", Discover: true, DiscoverEnvironments: discover})
+		assert.Empty(t, errs)
+		assert.Len(t, m.Environments, 2)
+
+		env := m.Environments["abc12345"]
+		assert.Equal(t, "discovered", env.Group)
+		assert.Equal(t, "https://abc12345.live.dynatrace.com", env.URL.Value)
+		assert.Equal(t, "TOKEN_abc12345", env.Auth.Token.Name)
+	})
+
+	t.Run("groups not selected are not discovered", func(t *testing.T) {
+		failing := func(context.Context, AccountDefinition) ([]accountapi.Environment, error) {
+			t.Fatal("unexpected discovery")
+			return nil, nil
+		}
+		m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", Groups: []string{"static"}, Discover: true, DiscoverEnvironments: failing})
+		assert.Empty(t, errs)
+		assert.Len(t, m.Environments, 1)
+	})
+
+	t.Run("discovery errors are reported", func(t *testing.T) {
+		failing := func(context.Context, AccountDefinition) ([]accountapi.Environment, error) {
+			return nil, errors.New("unauthorized")
+		}
+		_, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", Discover: true, DiscoverEnvironments: failing})
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], `failed to discover environments of group "discovered": unauthorized`)
+	})
+
+	t.Run("environments are only discovered if requested", func(t *testing.T) {
+		failing := func(context.Context, AccountDefinition) ([]accountapi.Environment, error) {
+			t.Fatal("unexpected discovery")
+			return nil, nil
+		}
+		m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", DiscoverEnvironments: failing})
+		assert.Empty(t, errs)
+		assert.Len(t, m.Environments, 1)
+	})
+}
+
+func TestLoadManifest_DiscoveryInOfflineMode(t *testing.T) {
+	t.Setenv("TOKEN", "mock token")
+	t.Setenv("CLIENT_ID", "client id")
+	t.Setenv("CLIENT_SECRET", "client secret")
+
+	offline := "\noffline:\n  enabled: true\n  oAuthTokenEndpoint: {value: \"https://sso.example.com/token\"}\n"
+	discover := func(_ context.Context, a AccountDefinition) ([]accountapi.Environment, error) {
+		assert.Equal(t, "https://sso.example.com/token", a.OAuth.GetTokenEndpointValue())
+		return nil, nil
+	}
+
+	t.Run("accounts need an API URL", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(discoveringManifest+offline), 0400))
+
+		_, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", Discover: true, DiscoverEnvironments: discover})
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "needs to define `apiUrl` to discover environments in offline mode")
+	})
+
+	t.Run("the offline token endpoint is used", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		withApiURL := strings.Replace(discoveringManifest, "  accountUUID:", "  apiUrl: {value: \"https://api.example.com\"}\n  accountUUID:", 1)
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(withApiURL+offline), 0400))
+
+		_, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", Discover: true, DiscoverEnvironments: discover})
+		assert.Empty(t, errs)
+	})
+}
@@ -0,0 +1,170 @@
+// @license
+// Copyright 2023 Dynatrace LLC
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/version"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Environment variables used to define a manifest without any YAML file, see [LoaderContext.FromEnv].
+//
+// Every environment listed in EnvManifestEnvironments is configured by variables prefixed with
+// 'MONACO_ENVIRONMENT_<NAME>_', where <NAME> is the upper-cased environment name with all characters other than
+// letters and digits replaced by '_'. E.g. the URL of environment 'prod-eu' is read from 'MONACO_ENVIRONMENT_PROD_EU_URL'.
+const (
+	// EnvManifestProjects holds a comma separated list of project paths. Each path defines a simple project named after its folder.
+	EnvManifestProjects = "MONACO_PROJECTS"
+	// EnvManifestEnvironments holds a comma separated list of environment names.
+	EnvManifestEnvironments = "MONACO_ENVIRONMENTS"
+	// EnvManifestConnectTimeout optionally holds the connect timeout of all HTTP calls, e.g. '10s'.
+	EnvManifestConnectTimeout = "MONACO_HTTP_CONNECT_TIMEOUT"
+	// EnvManifestRequestTimeout optionally holds the request timeout of all HTTP calls, e.g. '1m'.
+	EnvManifestRequestTimeout = "MONACO_HTTP_REQUEST_TIMEOUT"
+
+	envManifestEnvironmentPrefix = "MONACO_ENVIRONMENT_"
+	envSuffixURL                 = "_URL"
+	envSuffixGroup               = "_GROUP"
+	envSuffixToken               = "_TOKEN"
+	envSuffixOAuthClientID       = "_OAUTH_CLIENT_ID"
+	envSuffixOAuthClientSecret   = "_OAUTH_CLIENT_SECRET"
+	envSuffixOAuthTokenEndpoint  = "_OAUTH_TOKEN_ENDPOINT"
+	envSuffixOAuthScopes         = "_OAUTH_SCOPES"
+	envSuffixOAuthAudience       = "_OAUTH_AUDIENCE"
+
+	// fileEnvSuffix is appended to the name of any environment variable a secret or URL is read from. If the variable
+	// itself is not set, but the one with suffix is, the value is read from the file it points to. This allows using
+	// secrets mounted as files, e.g. in Kubernetes.
+	fileEnvSuffix = "_FILE"
+
+	defaultEnvManifestGroup = "default"
+)
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]`)
+
+// EnvironmentVariablePrefix returns the prefix of all variables configuring the given environment in a manifest defined by environment variables.
+func EnvironmentVariablePrefix(environmentName string) string {
+	return envManifestEnvironmentPrefix + nonAlphanumeric.ReplaceAllString(strings.ToUpper(environmentName), "_")
+}
+
+// manifestFromEnv creates the persisted manifest representation from environment variables, so that it can be
+// loaded and validated the same way as a manifest file.
+func manifestFromEnv() (manifest, error) {
+	projects := splitList(os.Getenv(EnvManifestProjects))
+	if len(projects) == 0 {
+		return manifest{}, fmt.Errorf("environment variable %q must define at least one project", EnvManifestProjects)
+	}
+
+	environments := splitList(os.Getenv(EnvManifestEnvironments))
+	if len(environments) == 0 {
+		return manifest{}, fmt.Errorf("environment variable %q must define at least one environment", EnvManifestEnvironments)
+	}
+
+	m := manifest{
+		ManifestVersion: version.ManifestVersion,
+	}
+
+	for _, p := range projects {
+		m.Projects = append(m.Projects, project{Name: filepath.Base(filepath.Clean(p)), Path: p})
+	}
+
+	var groups []string
+	environmentsPerGroup := map[string][]environment{}
+	for _, name := range environments {
+		prefix := EnvironmentVariablePrefix(name)
+
+		if !isEnvOrFileSet(prefix + envSuffixURL) {
+			return manifest{}, fmt.Errorf("environment variable %q must define the URL of environment %q", prefix+envSuffixURL, name)
+		}
+
+		env := environment{
+			Name: name,
+			URL:  url{Type: urlTypeEnvironment, Value: prefix + envSuffixURL},
+			Auth: auth{Token: authSecret{Type: typeEnvironment, Name: prefix + envSuffixToken}},
+		}
+
+		if isEnvOrFileSet(prefix + envSuffixOAuthClientID) {
+			env.Auth.OAuth = &oAuth{
+				ClientID:     authSecret{Type: typeEnvironment, Name: prefix + envSuffixOAuthClientID},
+				ClientSecret: authSecret{Type: typeEnvironment, Name: prefix + envSuffixOAuthClientSecret},
+				Scopes:       splitList(os.Getenv(prefix + envSuffixOAuthScopes)),
+				Audience:     os.Getenv(prefix + envSuffixOAuthAudience),
+			}
+			if isEnvOrFileSet(prefix + envSuffixOAuthTokenEndpoint) {
+				env.Auth.OAuth.TokenEndpoint = &url{Type: urlTypeEnvironment, Value: prefix + envSuffixOAuthTokenEndpoint}
+			}
+		}
+
+		groupName := os.Getenv(prefix + envSuffixGroup)
+		if groupName == "" {
+			groupName = defaultEnvManifestGroup
+		}
+		if _, exists := environmentsPerGroup[groupName]; !exists {
+			groups = append(groups, groupName)
+		}
+		environmentsPerGroup[groupName] = append(environmentsPerGroup[groupName], env)
+	}
+
+	for _, g := range groups {
+		m.EnvironmentGroups = append(m.EnvironmentGroups, group{Name: g, Environments: environmentsPerGroup[g]})
+	}
+
+	connectTimeout, requestTimeout := os.Getenv(EnvManifestConnectTimeout), os.Getenv(EnvManifestRequestTimeout)
+	if connectTimeout != "" || requestTimeout != "" {
+		m.HTTP = &httpSettings{ConnectTimeout: connectTimeout, RequestTimeout: requestTimeout}
+	}
+
+	return m, nil
+}
+
+// lookupEnvOrFile returns the value of the given environment variable. If it is not set, but a variable of the same
+// name with suffix '_FILE' is, the content of the referenced file is returned instead.
+func lookupEnvOrFile(name string) (string, bool, error) {
+	if v, found := os.LookupEnv(name); found {
+		return v, true, nil
+	}
+
+	path, found := os.LookupEnv(name + fileEnvSuffix)
+	if !found {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", true, fmt.Errorf("failed to read file %q referenced by environment variable %q: %w", path, name+fileEnvSuffix, err)
+	}
+	return strings.TrimSpace(string(content)), true, nil
+}
+
+// isEnvOrFileSet checks whether the given environment variable, or its '_FILE' variant is set.
+func isEnvOrFileSet(name string) bool {
+	_, found := os.LookupEnv(name)
+	_, fileFound := os.LookupEnv(name + fileEnvSuffix)
+	return found || fileFound
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEnvironmentVariablePrefix(t *testing.T) {
+	assert.Equal(t, "MONACO_ENVIRONMENT_PROD_EU_1", EnvironmentVariablePrefix("prod-eu.1"))
+	assert.Equal(t, "MONACO_ENVIRONMENT_DEV", EnvironmentVariablePrefix("dev"))
+}
+
+func TestLoadManifest_FromEnv(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("file token\n"), 0600))
+
+	t.Setenv(EnvManifestProjects, "projects/a, projects/b")
+	t.Setenv(EnvManifestEnvironments, "dev,prod-eu")
+	t.Setenv(EnvManifestRequestTimeout, "1m")
+	t.Setenv("MONACO_ENVIRONMENT_DEV_URL", "https://dev.dynatrace.com")
+	t.Setenv("MONACO_ENVIRONMENT_DEV_TOKEN", "dev token")
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_URL", "https://prod.dynatrace.com")
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_GROUP", "production")
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_TOKEN_FILE", tokenFile)
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_OAUTH_CLIENT_ID", "client-id")
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_OAUTH_CLIENT_SECRET", "client-secret")
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_OAUTH_SCOPES", "scope-a, scope-b")
+	t.Setenv("MONACO_ENVIRONMENT_PROD_EU_OAUTH_AUDIENCE", "urn:dtenvironment:abc")
+
+	mani, errs := LoadManifest(&LoaderContext{
+		Fs:           afero.NewMemMapFs(),
+		ManifestPath: "manifest.yaml",
+		FromEnv:      true,
+	})
+	assert.Empty(t, errs)
+
+	assert.Equal(t, Manifest{
+		Projects: map[string]ProjectDefinition{
+			"a": {Name: "a", Path: "projects/a"},
+			"b": {Name: "b", Path: "projects/b"},
+		},
+		Environments: map[string]EnvironmentDefinition{
+			"dev": {
+				Name:  "dev",
+				URL:   URLDefinition{Type: EnvironmentURLType, Name: "MONACO_ENVIRONMENT_DEV_URL", Value: "https://dev.dynatrace.com"},
+				Group: "default",
+				Auth:  Auth{Token: AuthSecret{Name: "MONACO_ENVIRONMENT_DEV_TOKEN", Value: "dev token"}},
+			},
+			"prod-eu": {
+				Name:  "prod-eu",
+				URL:   URLDefinition{Type: EnvironmentURLType, Name: "MONACO_ENVIRONMENT_PROD_EU_URL", Value: "https://prod.dynatrace.com"},
+				Group: "production",
+				Auth: Auth{
+					Token: AuthSecret{Name: "MONACO_ENVIRONMENT_PROD_EU_TOKEN", Value: "file token"},
+					OAuth: &OAuth{
+						ClientID:     AuthSecret{Name: "MONACO_ENVIRONMENT_PROD_EU_OAUTH_CLIENT_ID", Value: "client-id"},
+						ClientSecret: AuthSecret{Name: "MONACO_ENVIRONMENT_PROD_EU_OAUTH_CLIENT_SECRET", Value: "client-secret"},
+						Scopes:       []string{"scope-a", "scope-b"},
+						Audience:     "urn:dtenvironment:abc",
+					},
+				},
+			},
+		},
+		HTTP: HTTPSettings{RequestTimeout: time.Minute},
+	}, mani)
+}
+
+func TestLoadManifest_FromEnvFailsOnMissingVariables(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		errContains string
+	}{
+		{
+			name:        "no projects",
+			env:         map[string]string{EnvManifestEnvironments: "dev"},
+			errContains: EnvManifestProjects,
+		},
+		{
+			name:        "no environments",
+			env:         map[string]string{EnvManifestProjects: "p"},
+			errContains: EnvManifestEnvironments,
+		},
+		{
+			name:        "no URL",
+			env:         map[string]string{EnvManifestProjects: "p", EnvManifestEnvironments: "dev"},
+			errContains: "MONACO_ENVIRONMENT_DEV_URL",
+		},
+		{
+			name: "unreadable token file",
+			env: map[string]string{
+				EnvManifestProjects:                 "p",
+				EnvManifestEnvironments:             "dev",
+				"MONACO_ENVIRONMENT_DEV_URL":        "https://dev.dynatrace.com",
+				"MONACO_ENVIRONMENT_DEV_TOKEN_FILE": "/does/not/exist",
+			},
+			errContains: "MONACO_ENVIRONMENT_DEV_TOKEN_FILE",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(EnvManifestProjects, "")
+			t.Setenv(EnvManifestEnvironments, "")
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			_, errs := LoadManifest(&LoaderContext{
+				Fs:           afero.NewMemMapFs(),
+				ManifestPath: "manifest.yaml",
+				FromEnv:      true,
+			})
+			assert.Len(t, errs, 1)
+			assert.ErrorContains(t, errs[0], test.errContains)
+		})
+	}
+}
+
+func TestLoadManifest_ReadsSecretsFromFiles(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("mounted token"), 0600))
+	t.Setenv("MOUNTED_TOKEN_FILE", tokenFile)
+
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: MOUNTED_TOKEN}}}]}]
+`), 0400))
+
+	mani, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml"})
+	assert.Empty(t, errs)
+	assert.Equal(t, "mounted token", mani.Environments["c"].Auth.Token.Value)
+}
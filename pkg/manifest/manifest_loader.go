@@ -15,18 +15,22 @@
 package manifest
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
 	version2 "github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/version"
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v2"
-	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"time"
 )
 
 // LoaderContext holds all information for [LoadManifest]
@@ -50,11 +54,72 @@ type LoaderContext struct {
 	//
 	// If Groups contains items that do not match any environment in the specified manifest file, the loading errors.
 	Groups []string
+
+	// FromEnv defines that the manifest is not read from ManifestPath, but created from environment variables.
+	// See [EnvManifestProjects] and [EnvManifestEnvironments] for details.
+	// Projects are still resolved relative to the directory of ManifestPath.
+	FromEnv bool
+
+	// Discover defines that the environments of groups defining a discovery are discovered, which requires requests to
+	// the account management API. It should only be set by commands deploying to the environments. Otherwise, groups
+	// only hold the environments defined in the manifest.
+	Discover bool
+
+	// DiscoverEnvironments lists the environments of accounts for groups defining a discovery. If it's nil, the
+	// account management API is used, with the HTTP and offline settings of the manifest.
+	DiscoverEnvironments EnvironmentDiscoverer
+
+	// Context is used for requests made while loading, e.g. to discover environments. If it's nil, the background
+	// context is used.
+	Context context.Context
+}
+
+func (c *LoaderContext) ctx() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
 }
 
 type projectLoaderContext struct {
 	fs           afero.Fs
 	manifestPath string
+	// locations holds the positions of the project definitions in the manifest, keyed by their names
+	locations map[string]yamlnode.Position
+}
+
+// manifestLocations holds the positions of the project and environment definitions in a manifest file, keyed by their
+// names. If a name is defined several times, the position of the first definition is stored.
+type manifestLocations struct {
+	projects     map[string]yamlnode.Position
+	environments map[string]yamlnode.Position
+}
+
+// findManifestLocations looks up the positions of the project and environment definitions in the node tree of a
+// manifest.
+func findManifestLocations(root *yamlnode.Node) manifestLocations {
+	locations := manifestLocations{
+		projects:     make(map[string]yamlnode.Position),
+		environments: make(map[string]yamlnode.Position),
+	}
+
+	for _, p := range yamlnode.Items(yamlnode.Get(root, "projects")) {
+		addLocation(locations.projects, yamlnode.ScalarValue(p, "name"), yamlnode.PositionOf(p))
+	}
+
+	for _, g := range yamlnode.Items(yamlnode.Get(root, "environmentGroups")) {
+		for _, e := range yamlnode.Items(yamlnode.Get(g, "environments")) {
+			addLocation(locations.environments, yamlnode.ScalarValue(e, "name"), yamlnode.PositionOf(e))
+		}
+	}
+
+	return locations
+}
+
+func addLocation(locations map[string]yamlnode.Position, name string, p yamlnode.Position) {
+	if _, exists := locations[name]; !exists {
+		locations[name] = p
+	}
 }
 
 type manifestLoaderError struct {
@@ -70,42 +135,58 @@ type environmentLoaderError struct {
 	manifestLoaderError
 	Group       string
 	Environment string
+	// Position is the line and column of the environment definition in the manifest, if known
+	Position yamlnode.Position
 }
 
-func newManifestEnvironmentLoaderError(manifest string, group string, env string, reason string) environmentLoaderError {
+func newManifestEnvironmentLoaderError(manifest string, position yamlnode.Position, group string, env string, reason string) environmentLoaderError {
 	return environmentLoaderError{
 		manifestLoaderError: manifestLoaderError{manifest, reason},
 		Group:               group,
 		Environment:         env,
+		Position:            position,
 	}
 }
 
 func (e environmentLoaderError) Error() string {
-	return fmt.Sprintf("%s:%s:%s: %s", e.ManifestPath, e.Group, e.Environment, e.Reason)
+	return fmt.Sprintf("%s:%s:%s: %s", e.Position.Format(e.ManifestPath), e.Group, e.Environment, e.Reason)
 }
 
 type projectLoaderError struct {
 	manifestLoaderError
 	Project string
+	// Position is the line and column of the project definition in the manifest, if known
+	Position yamlnode.Position
 }
 
-func newManifestProjectLoaderError(manifest string, project string, reason string) projectLoaderError {
+func newManifestProjectLoaderError(context *projectLoaderContext, project string, reason string) projectLoaderError {
 	return projectLoaderError{
-		manifestLoaderError: manifestLoaderError{manifest, reason},
+		manifestLoaderError: manifestLoaderError{context.manifestPath, reason},
 		Project:             project,
+		Position:            context.locations[project],
 	}
 }
 
 func (e projectLoaderError) Error() string {
-	return fmt.Sprintf("%s:%s: %s", e.ManifestPath, e.Project, e.Reason)
+	return fmt.Sprintf("%s:%s: %s", e.Position.Format(e.ManifestPath), e.Project, e.Reason)
 }
 
 func LoadManifest(context *LoaderContext) (Manifest, []error) {
 	log.Debug("Loading manifest %q. Restrictions: groups=%q, environments=%q", context.ManifestPath, context.Groups, context.Environments)
 
-	manifestYAML, err := readManifestYAML(context)
-	if err != nil {
-		return Manifest{}, []error{err}
+	var manifestYAML manifest
+	var locations manifestLocations
+	var err error
+	if context.FromEnv {
+		manifestYAML, err = manifestFromEnv()
+		if err != nil {
+			return Manifest{}, []error{manifestLoaderError{context.ManifestPath, fmt.Sprintf("failed to create manifest from environment variables: %s", err)}}
+		}
+	} else {
+		manifestYAML, locations, err = readManifestYAML(context)
+		if err != nil {
+			return Manifest{}, []error{err}
+		}
 	}
 	if errs := verifyManifestYAML(manifestYAML); errs != nil {
 		var retErrs []error
@@ -115,6 +196,28 @@ func LoadManifest(context *LoaderContext) (Manifest, []error) {
 		return Manifest{}, retErrs
 	}
 
+	if errs := resolveVariables(context, &manifestYAML); errs != nil {
+		return Manifest{}, errs
+	}
+
+	// HTTP and offline settings apply to discovering environments as well, thus are parsed beforehand
+	var errs []error
+	httpSettings, err := parseHTTPSettings(manifestYAML.HTTP)
+	if err != nil {
+		errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("invalid http settings: %s", err)})
+	}
+
+	offlineSettings, err := parseOfflineSettings(manifestYAML.Offline)
+	if err != nil {
+		errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("invalid offline settings: %s", err)})
+	}
+
+	if errs == nil && context.Discover {
+		if errs := discoverEnvironments(context, &manifestYAML, httpSettings, offlineSettings); errs != nil {
+			return Manifest{}, errs
+		}
+	}
+
 	manifestPath := filepath.Clean(context.ManifestPath)
 
 	workingDir := filepath.Dir(manifestPath)
@@ -128,36 +231,357 @@ func LoadManifest(context *LoaderContext) (Manifest, []error) {
 
 	relativeManifestPath := filepath.Base(manifestPath)
 
-	projectDefinitions, projectErrors := toProjectDefinitions(&projectLoaderContext{
+	projectContext := &projectLoaderContext{
 		fs:           workingDirFs,
 		manifestPath: relativeManifestPath,
-	}, manifestYAML.Projects)
+		locations:    locations.projects,
+	}
 
-	var errs []error
-	if projectErrors != nil {
+	var projectDefinitions, accountProjectDefinitions ProjectDefinitionByProjectID
+
+	// project names need to be unique across config and account projects
+	if duplicateErrs := checkForDuplicateDefinitions(projectContext, manifestYAML.Projects); duplicateErrs != nil {
+		errs = append(errs, duplicateErrs...)
+	} else {
+		configProjects, accountProjects := splitAccountProjects(manifestYAML.Projects)
+
+		var projectErrors []error
+		projectDefinitions, projectErrors = toProjectDefinitions(projectContext, configProjects)
 		errs = append(errs, projectErrors...)
-	} else if len(projectDefinitions) == 0 {
-		errs = append(errs, manifestLoaderError{context.ManifestPath, "no projects defined in manifest"})
+
+		if len(accountProjects) > 0 {
+			var accountProjectErrors []error
+			accountProjectDefinitions, accountProjectErrors = toProjectDefinitions(projectContext, accountProjects)
+			errs = append(errs, accountProjectErrors...)
+		}
+
+		if errs == nil && len(projectDefinitions) == 0 && len(accountProjectDefinitions) == 0 {
+			errs = append(errs, manifestLoaderError{context.ManifestPath, "no projects defined in manifest"})
+		}
 	}
 
-	environmentDefinitions, manifestErrors := toEnvironments(context, manifestYAML.EnvironmentGroups)
+	environmentDefinitions, manifestErrors := toEnvironments(context, manifestYAML.EnvironmentGroups, locations.environments)
 
 	if manifestErrors != nil {
 		errs = append(errs, manifestErrors...)
-	} else if len(environmentDefinitions) == 0 {
+	} else if len(environmentDefinitions) == 0 && (context.Discover || !definesDiscovery(manifestYAML.EnvironmentGroups)) {
 		errs = append(errs, manifestLoaderError{context.ManifestPath, "no environments defined in manifest"})
 	}
 
+	accounts, accountErrors := toAccounts(context, manifestYAML.Accounts)
+	if accountErrors != nil {
+		errs = append(errs, accountErrors...)
+	}
+
+	rolloutPolicy, err := parseRollout(manifestYAML.Rollout, manifestYAML.EnvironmentGroups)
+	if err != nil {
+		errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("invalid rollout: %s", err)})
+	}
+
 	if errs != nil {
 		return Manifest{}, errs
 	}
 
+	if offlineSettings.OAuthTokenEndpoint != nil {
+		applyDefaultTokenEndpoint(environmentDefinitions, accounts, *offlineSettings.OAuthTokenEndpoint)
+	}
+
 	return Manifest{
-		Projects:     projectDefinitions,
-		Environments: environmentDefinitions,
+		Projects:        projectDefinitions,
+		Environments:    environmentDefinitions,
+		AccountProjects: accountProjectDefinitions,
+		Accounts:        accounts,
+		HTTP:            httpSettings,
+		Offline:         offlineSettings,
+		Rollout:         rolloutPolicy,
+		VariablesFile:   manifestYAML.VariablesFile,
 	}, nil
 }
 
+// parseRollout parses the rollout policy. Each group of the manifest must be part of exactly one stage.
+func parseRollout(r *rollout, groups []group) (*RolloutPolicy, error) {
+	if r == nil {
+		return nil, nil
+	}
+	if len(r.Stages) == 0 {
+		return nil, errors.New("no `stages` defined")
+	}
+
+	stageOfGroup := make(map[string]int)
+	policy := RolloutPolicy{Stages: make([]RolloutStage, len(r.Stages))}
+	for i, s := range r.Stages {
+		if len(s.Groups) == 0 {
+			return nil, fmt.Errorf("stage %d defines no `groups`", i+1)
+		}
+		for _, g := range s.Groups {
+			if previous, found := stageOfGroup[g]; found {
+				return nil, fmt.Errorf("group %q is part of stages %d and %d", g, previous+1, i+1)
+			}
+			stageOfGroup[g] = i
+		}
+
+		var wait time.Duration
+		if s.Wait != "" {
+			var err error
+			if wait, err = time.ParseDuration(s.Wait); err != nil || wait < 0 {
+				return nil, fmt.Errorf("invalid `wait` %q of stage %d: expected a duration like '10m'", s.Wait, i+1)
+			}
+		}
+
+		policy.Stages[i] = RolloutStage{Groups: s.Groups, Wait: wait, Approval: s.Approval}
+	}
+
+	known := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		known[g.Name] = true
+		if _, found := stageOfGroup[g.Name]; !found {
+			return nil, fmt.Errorf("group %q is not part of any stage", g.Name)
+		}
+	}
+	for g := range stageOfGroup {
+		if !known[g] {
+			return nil, fmt.Errorf("unknown group %q", g)
+		}
+	}
+
+	return &policy, nil
+}
+
+func parseOfflineSettings(o *offline) (OfflineSettings, error) {
+	if o == nil {
+		return OfflineSettings{}, nil
+	}
+
+	result := OfflineSettings{Enabled: o.Enabled}
+	if o.OAuthTokenEndpoint != nil {
+		urlDef, err := parseURLDefinition(*o.OAuthTokenEndpoint)
+		if err != nil {
+			return OfflineSettings{}, fmt.Errorf("failed to parse `oAuthTokenEndpoint`: %w", err)
+		}
+		result.OAuthTokenEndpoint = &urlDef
+	}
+	return result, nil
+}
+
+// applyDefaultTokenEndpoint sets the given token endpoint for all environments and accounts using OAuth without
+// defining their own token endpoint.
+func applyDefaultTokenEndpoint(environments map[string]EnvironmentDefinition, accounts map[string]AccountDefinition, tokenEndpoint URLDefinition) {
+	for name, env := range environments {
+		if env.Auth.OAuth != nil && env.Auth.OAuth.TokenEndpoint == nil {
+			o := *env.Auth.OAuth
+			o.TokenEndpoint = &tokenEndpoint
+			env.Auth.OAuth = &o
+			environments[name] = env
+		}
+	}
+
+	for name, acc := range accounts {
+		if acc.OAuth.TokenEndpoint == nil {
+			acc.OAuth.TokenEndpoint = &tokenEndpoint
+			accounts[name] = acc
+		}
+	}
+}
+
+// splitAccountProjects splits the given project definitions into projects holding configs and projects holding
+// account resources. Account projects are returned as simple projects.
+func splitAccountProjects(projects []project) (configProjects []project, accountProjects []project) {
+	for _, p := range projects {
+		if p.Type == accountProjectType {
+			p.Type = simpleProjectType
+			accountProjects = append(accountProjects, p)
+			continue
+		}
+		configProjects = append(configProjects, p)
+	}
+	return configProjects, accountProjects
+}
+
+func toAccounts(context *LoaderContext, definitions []account) (Accounts, []error) {
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	result := make(Accounts, len(definitions))
+
+	for _, a := range definitions {
+		if a.Name == "" {
+			errs = append(errs, manifestLoaderError{context.ManifestPath, "account is missing a name"})
+			continue
+		}
+
+		if _, found := result[a.Name]; found {
+			errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("duplicated account name `%s`", a.Name)})
+			continue
+		}
+
+		parsed, err := parseAccount(a)
+		if err != nil {
+			errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("invalid account `%s`: %s", a.Name, err)})
+			continue
+		}
+
+		result[a.Name] = parsed
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+	return result, nil
+}
+
+func parseAccount(a account) (AccountDefinition, error) {
+	if a.AccountUUID == "" {
+		return AccountDefinition{}, errors.New("`accountUUID` is not set")
+	}
+
+	oAuth, err := parseOAuth(a.OAuth)
+	if err != nil {
+		return AccountDefinition{}, fmt.Errorf("failed to parse `oAuth`: %w", err)
+	}
+
+	var apiURL *URLDefinition
+	if a.ApiURL != nil {
+		u, err := parseURLDefinition(*a.ApiURL)
+		if err != nil {
+			return AccountDefinition{}, fmt.Errorf("failed to parse `apiUrl`: %w", err)
+		}
+		apiURL = &u
+	}
+
+	return AccountDefinition{
+		Name:        a.Name,
+		AccountUUID: a.AccountUUID,
+		ApiURL:      apiURL,
+		OAuth:       oAuth,
+	}, nil
+}
+
+func parseHTTPSettings(s *httpSettings) (HTTPSettings, error) {
+	if s == nil {
+		return HTTPSettings{}, nil
+	}
+
+	connectTimeout, err := parseTimeout(s.ConnectTimeout)
+	if err != nil {
+		return HTTPSettings{}, fmt.Errorf("failed to parse `connectTimeout`: %w", err)
+	}
+
+	requestTimeout, err := parseTimeout(s.RequestTimeout)
+	if err != nil {
+		return HTTPSettings{}, fmt.Errorf("failed to parse `requestTimeout`: %w", err)
+	}
+
+	additions, err := parseRequestAdditions(s.Headers, s.QueryParameters, s.APIs)
+	if err != nil {
+		return HTTPSettings{}, err
+	}
+
+	return HTTPSettings{
+		ConnectTimeout:   connectTimeout,
+		RequestTimeout:   requestTimeout,
+		RequestAdditions: additions,
+	}, nil
+}
+
+// parseRequestAdditions converts the general headers and query parameters, followed by the ones of each API.
+// Entries without any header or query parameter are omitted.
+func parseRequestAdditions(headers, queryParameters map[string]httpValue, apis []apiRequestAdditions) ([]RequestAddition, error) {
+	var result []RequestAddition
+
+	general, err := parseRequestAddition("", headers, queryParameters)
+	if err != nil {
+		return nil, err
+	}
+	if general != nil {
+		result = append(result, *general)
+	}
+
+	for i, a := range apis {
+		if a.PathPrefix == "" {
+			return nil, fmt.Errorf("`apis[%d]` has no `pathPrefix`", i)
+		}
+
+		addition, err := parseRequestAddition(a.PathPrefix, a.Headers, a.QueryParameters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse `apis[%d]`: %w", i, err)
+		}
+		if addition != nil {
+			result = append(result, *addition)
+		}
+	}
+
+	return result, nil
+}
+
+func parseRequestAddition(pathPrefix string, headers, queryParameters map[string]httpValue) (*RequestAddition, error) {
+	if len(headers) == 0 && len(queryParameters) == 0 {
+		return nil, nil
+	}
+
+	h, err := parseHTTPValues(headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse `headers`: %w", err)
+	}
+
+	q, err := parseHTTPValues(queryParameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse `queryParameters`: %w", err)
+	}
+
+	return &RequestAddition{
+		PathPrefix:      pathPrefix,
+		Headers:         h,
+		QueryParameters: q,
+	}, nil
+}
+
+func parseHTTPValues(values map[string]httpValue) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]string, len(values))
+	for name, v := range values {
+		if name == "" {
+			return nil, errors.New("name must not be empty")
+		}
+
+		switch v.Type {
+		case "", urlTypeValue:
+			result[name] = v.Value
+		case urlTypeEnvironment:
+			val, found, err := lookupEnvOrFile(v.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+			if !found {
+				return nil, fmt.Errorf("%q: environment variable %q could not be found", name, v.Value)
+			}
+			result[name] = val
+		default:
+			return nil, fmt.Errorf("%q: %q is not a valid value type", name, v.Type)
+		}
+	}
+	return result, nil
+}
+
+func parseTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("timeout must not be negative, but is %q", timeout)
+	}
+	return d, nil
+}
+
 func parseAuth(a auth) (Auth, error) {
 	token, err := parseAuthSecret(a.Token)
 	if err != nil {
@@ -192,7 +616,10 @@ func parseAuthSecret(s authSecret) (AuthSecret, error) {
 		return AuthSecret{}, errors.New("no name given or empty")
 	}
 
-	v, f := os.LookupEnv(s.Name)
+	v, f, err := lookupEnvOrFile(s.Name)
+	if err != nil {
+		return AuthSecret{}, err
+	}
 	if !f {
 		return AuthSecret{}, fmt.Errorf("environment-variable %q was not found", s.Name)
 	}
@@ -215,51 +642,59 @@ func parseOAuth(a oAuth) (OAuth, error) {
 		return OAuth{}, fmt.Errorf("failed to parse ClientSecret: %w", err)
 	}
 
+	o := OAuth{
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		TokenEndpoint: nil,
+		Scopes:        a.Scopes,
+		Audience:      a.Audience,
+	}
+
 	if a.TokenEndpoint != nil {
 		urlDef, err := parseURLDefinition(*a.TokenEndpoint)
 		if err != nil {
 			return OAuth{}, fmt.Errorf(`failed to parse "tokenEndpoint": %w`, err)
 		}
-
-		return OAuth{
-			ClientID:      clientID,
-			ClientSecret:  clientSecret,
-			TokenEndpoint: &urlDef,
-		}, nil
+		o.TokenEndpoint = &urlDef
 	}
 
-	return OAuth{
-		ClientID:      clientID,
-		ClientSecret:  clientSecret,
-		TokenEndpoint: nil,
-	}, nil
+	return o, nil
 }
 
-func readManifestYAML(context *LoaderContext) (manifest, error) {
+func readManifestYAML(context *LoaderContext) (manifest, manifestLocations, error) {
 	manifestPath := filepath.Clean(context.ManifestPath)
 
 	if !files.IsYamlFileExtension(manifestPath) {
-		return manifest{}, manifestLoaderError{context.ManifestPath, "manifest file is not a yaml"}
+		return manifest{}, manifestLocations{}, manifestLoaderError{context.ManifestPath, "manifest file is not a yaml"}
 	}
 
 	if exists, err := files.DoesFileExist(context.Fs, manifestPath); err != nil {
-		return manifest{}, err
+		return manifest{}, manifestLocations{}, err
 	} else if !exists {
-		return manifest{}, manifestLoaderError{context.ManifestPath, "specified manifest file is either no file or does not exist"}
+		return manifest{}, manifestLocations{}, manifestLoaderError{context.ManifestPath, "specified manifest file is either no file or does not exist"}
 	}
 
 	rawData, err := afero.ReadFile(context.Fs, manifestPath)
 	if err != nil {
-		return manifest{}, manifestLoaderError{context.ManifestPath, fmt.Sprintf("error while reading the manifest: %s", err)}
+		return manifest{}, manifestLocations{}, manifestLoaderError{context.ManifestPath, fmt.Sprintf("error while reading the manifest: %s", err)}
 	}
 
 	var m manifest
 
+	root := yamlnode.Parse(rawData)
+
 	err = yaml.UnmarshalStrict(rawData, &m)
 	if err != nil {
-		return manifest{}, manifestLoaderError{context.ManifestPath, fmt.Sprintf("error during parsing the manifest: %s", err)}
+		if unknownKeys := yamlnode.UnknownKeys(root, reflect.TypeOf(m)); len(unknownKeys) > 0 {
+			reasons := make([]string, len(unknownKeys))
+			for i, k := range unknownKeys {
+				reasons[i] = k.String()
+			}
+			return manifest{}, manifestLocations{}, manifestLoaderError{context.ManifestPath, fmt.Sprintf("error during parsing the manifest: %s", strings.Join(reasons, ", "))}
+		}
+		return manifest{}, manifestLocations{}, manifestLoaderError{context.ManifestPath, fmt.Sprintf("error during parsing the manifest: %s", err)}
 	}
-	return m, nil
+	return m, findManifestLocations(root), nil
 }
 
 func verifyManifestYAML(m manifest) []error {
@@ -304,7 +739,7 @@ func validateManifestVersion(manifestVersion string) error {
 	return nil
 }
 
-func toEnvironments(context *LoaderContext, groups []group) (map[string]EnvironmentDefinition, []error) { // nolint:gocognit
+func toEnvironments(context *LoaderContext, groups []group, locations map[string]yamlnode.Position) (map[string]EnvironmentDefinition, []error) { // nolint:gocognit
 	var errors []error
 	environments := make(map[string]EnvironmentDefinition)
 
@@ -341,7 +776,7 @@ func toEnvironments(context *LoaderContext, groups []group) (map[string]Environm
 				continue
 			}
 
-			parsedEnv, configErrors := parseEnvironment(context, env, group.Name)
+			parsedEnv, configErrors := parseEnvironment(context, env, group.Name, locations[env.Name])
 
 			if configErrors != nil {
 				errors = append(errors, configErrors...)
@@ -389,17 +824,31 @@ func shouldSkipEnv(context *LoaderContext, group group, env environment) bool {
 	return true
 }
 
-func parseEnvironment(context *LoaderContext, config environment, group string) (EnvironmentDefinition, []error) {
+func parseEnvironment(context *LoaderContext, config environment, group string, position yamlnode.Position) (EnvironmentDefinition, []error) {
 	var errs []error
 
 	a, err := parseAuth(config.Auth)
 	if err != nil {
-		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, fmt.Sprintf("failed to parse auth section: %s", err)))
+		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, position, group, config.Name, fmt.Sprintf("failed to parse auth section: %s", err)))
 	}
 
 	urlDef, err := parseURLDefinition(config.URL)
 	if err != nil {
-		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, group, config.Name, err.Error()))
+		errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, position, group, config.Name, err.Error()))
+	}
+
+	var additions []RequestAddition
+	var cert *ClientCertificate
+	if config.HTTP != nil {
+		additions, err = parseRequestAdditions(config.HTTP.Headers, config.HTTP.QueryParameters, config.HTTP.APIs)
+		if err != nil {
+			errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, position, group, config.Name, fmt.Sprintf("failed to parse http section: %s", err)))
+		}
+
+		cert, err = loadClientCertificate(context, config.HTTP.ClientCertificate)
+		if err != nil {
+			errs = append(errs, newManifestEnvironmentLoaderError(context.ManifestPath, position, group, config.Name, fmt.Sprintf("failed to load client certificate: %s", err)))
+		}
 	}
 
 	if len(errs) > 0 {
@@ -407,13 +856,52 @@ func parseEnvironment(context *LoaderContext, config environment, group string)
 	}
 
 	return EnvironmentDefinition{
-		Name:  config.Name,
-		URL:   urlDef,
-		Auth:  a,
-		Group: group,
+		Name:              config.Name,
+		URL:               urlDef,
+		Auth:              a,
+		Group:             group,
+		RequestAdditions:  additions,
+		ClientCertificate: cert,
 	}, nil
 }
 
+// loadClientCertificate reads the certificate and key files, which are resolved relative to the manifest.
+func loadClientCertificate(context *LoaderContext, c *clientCertificate) (*ClientCertificate, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("both `certFile` and `keyFile` are required")
+	}
+
+	certPEM, err := afero.ReadFile(context.Fs, resolveRelativeToManifest(context, c.CertFile))
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := afero.ReadFile(context.Fs, resolveRelativeToManifest(context, c.KeyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientCertificate{
+		CertFile:    c.CertFile,
+		KeyFile:     c.KeyFile,
+		Certificate: cert,
+	}, nil
+}
+
+func resolveRelativeToManifest(context *LoaderContext, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(context.ManifestPath), path)
+}
+
 func parseURLDefinition(u url) (URLDefinition, error) {
 
 	// Depending on the type, the url.value either contains the env var name or the direct value of the url
@@ -431,7 +919,10 @@ func parseURLDefinition(u url) (URLDefinition, error) {
 	}
 
 	if u.Type == urlTypeEnvironment {
-		val, found := os.LookupEnv(u.Value)
+		val, found, err := lookupEnvOrFile(u.Value)
+		if err != nil {
+			return URLDefinition{}, err
+		}
 		if !found {
 			return URLDefinition{}, fmt.Errorf("environment variable %q could not be found", u.Value)
 		}
@@ -480,6 +971,8 @@ func toProjectDefinitions(context *projectLoaderContext, definitions []project)
 		}
 	}
 
+	errors = append(errors, checkOverrides(context, definitions)...)
+
 	if errors != nil {
 		return nil, errors
 	}
@@ -487,6 +980,37 @@ func toProjectDefinitions(context *projectLoaderContext, definitions []project)
 	return result, nil
 }
 
+// checkOverrides verifies that all projects given in 'overrides' are defined. Grouping projects can be referenced by
+// their name as well.
+func checkOverrides(context *projectLoaderContext, definitions []project) (errors []error) {
+	known := make(map[string]struct{}, len(definitions))
+	for _, project := range definitions {
+		known[project.Name] = struct{}{}
+	}
+
+	for _, project := range definitions {
+		for _, o := range project.Overrides {
+			if o == project.Name {
+				errors = append(errors, newManifestProjectLoaderError(context, project.Name, "project can not override itself"))
+			} else if _, found := known[o]; !found && !isGroupedProjectName(definitions, o) {
+				errors = append(errors, newManifestProjectLoaderError(context, project.Name, fmt.Sprintf("overridden project `%s` is not defined", o)))
+			}
+		}
+	}
+	return errors
+}
+
+// isGroupedProjectName returns whether the given name is the name of a project of a grouping project, i.e.
+// '<group>.<folder>'. The folders are not known before they are read, hence only the group is checked.
+func isGroupedProjectName(definitions []project, name string) bool {
+	for _, project := range definitions {
+		if project.Type == groupProjectType && strings.HasPrefix(name, project.Name+".") {
+			return true
+		}
+	}
+	return false
+}
+
 func checkForDuplicateDefinitions(context *projectLoaderContext, definitions []project) (errors []error) {
 	definedIds := map[string]struct{}{}
 	for _, project := range definitions {
@@ -513,35 +1037,39 @@ func parseProjectDefinition(context *projectLoaderContext, project project) ([]P
 	case groupProjectType:
 		return parseGroupingProjectDefinition(context, project)
 	default:
-		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name,
+		return nil, []error{newManifestProjectLoaderError(context, project.Name,
 			fmt.Sprintf("invalid project type `%s`", projectType))}
 	}
 }
 
 func parseSimpleProjectDefinition(context *projectLoaderContext, project project) ([]ProjectDefinition, []error) {
 	if project.Path == "" && project.Name == "" {
-		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name,
+		return nil, []error{newManifestProjectLoaderError(context, project.Name,
 			"project is missing both name and path")}
 	}
 
 	if strings.ContainsAny(project.Name, `/\`) {
-		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name,
+		return nil, []error{newManifestProjectLoaderError(context, project.Name,
 			`project name is not allowed to contain '/' or '\'`)}
 	}
 
 	if project.Path == "" {
 		return []ProjectDefinition{
 			{
-				Name: project.Name,
-				Path: project.Name,
+				Name:      project.Name,
+				Path:      project.Name,
+				Variables: project.Variables,
+				Overrides: project.Overrides,
 			},
 		}, nil
 	}
 
 	return []ProjectDefinition{
 		{
-			Name: project.Name,
-			Path: project.Path,
+			Name:      project.Name,
+			Path:      project.Path,
+			Variables: project.Variables,
+			Overrides: project.Overrides,
 		},
 	}, nil
 }
@@ -552,7 +1080,7 @@ func parseGroupingProjectDefinition(context *projectLoaderContext, project proje
 	files, err := afero.ReadDir(context.fs, projectPath)
 
 	if err != nil {
-		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name, fmt.Sprintf("failed to read project dir: %v", err))}
+		return nil, []error{newManifestProjectLoaderError(context, project.Name, fmt.Sprintf("failed to read project dir: %v", err))}
 	}
 
 	var result []ProjectDefinition
@@ -563,15 +1091,17 @@ func parseGroupingProjectDefinition(context *projectLoaderContext, project proje
 		}
 
 		result = append(result, ProjectDefinition{
-			Name:  project.Name + "." + file.Name(),
-			Group: project.Name,
-			Path:  filepath.Join(projectPath, file.Name()),
+			Name:      project.Name + "." + file.Name(),
+			Group:     project.Name,
+			Path:      filepath.Join(projectPath, file.Name()),
+			Variables: project.Variables,
+			Overrides: project.Overrides,
 		})
 	}
 
 	if result == nil {
 		// TODO should we really fail here?
-		return nil, []error{newManifestProjectLoaderError(context.manifestPath, project.Name,
+		return nil, []error{newManifestProjectLoaderError(context, project.Name,
 			fmt.Sprintf("no projects found in `%s`", projectPath))}
 	}
 
@@ -19,6 +19,12 @@
 package manifest
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
 	monacoVersion "github.com/dynatrace/dynatrace-configuration-as-code/internal/version"
@@ -27,8 +33,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 	"math"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func Test_extractUrlType(t *testing.T) {
@@ -192,6 +200,25 @@ func Test_parseProjectDefinition_SimpleType(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"parses_project_variables",
+			args{
+				context: nil,
+				project: project{
+					Name:      "payment-monitoring",
+					Path:      "templates/service-monitoring",
+					Variables: map[string]string{"application": "payment"},
+				},
+			},
+			[]ProjectDefinition{
+				{
+					Name:      "payment-monitoring",
+					Path:      "templates/service-monitoring",
+					Variables: map[string]string{"application": "payment"},
+				},
+			},
+			nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -466,7 +493,7 @@ func Test_toProjectDefinitions(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			context := &projectLoaderContext{testFs, "path/to/a/manifest.yaml"}
+			context := &projectLoaderContext{fs: testFs, manifestPath: "path/to/a/manifest.yaml"}
 
 			got, gotErrs := toProjectDefinitions(context, tt.projectDefinitions)
 
@@ -1503,6 +1530,55 @@ environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {t
 				},
 			},
 		},
+		{
+			name: "No errors with oAuth scopes and audience",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}, oAuth: {clientId: {name: client-id}, clientSecret: {name: client-secret}, tokenEndpoint: {value: "https://custom.sso.token.endpoint"}, scopes: [storage:logs:read, settings:objects:write], audience: "urn:dtenvironment:abc"}}}]}]
+`,
+			errsContain: []string{},
+			expectedManifest: Manifest{
+				Projects: map[string]ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						URL: URLDefinition{
+							Type:  ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: Auth{
+							Token: AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+							OAuth: &OAuth{
+								ClientID: AuthSecret{
+									Name:  "client-id",
+									Value: "resolved-client-id",
+								},
+								ClientSecret: AuthSecret{
+									Name:  "client-secret",
+									Value: "resolved-client-secret",
+								},
+								TokenEndpoint: &URLDefinition{
+									Type:  ValueURLType,
+									Value: "https://custom.sso.token.endpoint",
+								},
+								Scopes:   []string{"storage:logs:read", "settings:objects:write"},
+								Audience: "urn:dtenvironment:abc",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "OAuth token endpoint is specified via environment variable that doesn't exists",
 			manifestContent: `
@@ -1645,6 +1721,260 @@ environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {t
 `,
 			errsContain: []string{`environment-variable "not-found" was not found`},
 		},
+		{
+			name: "HTTP timeouts are loaded",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+http: {connectTimeout: 10s, requestTimeout: 2m}
+`,
+			errsContain: []string{},
+			expectedManifest: Manifest{
+				Projects: map[string]ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						URL: URLDefinition{
+							Type:  ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: Auth{
+							Token: AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+						},
+					},
+				},
+				HTTP: HTTPSettings{
+					ConnectTimeout: 10 * time.Second,
+					RequestTimeout: 2 * time.Minute,
+				},
+			},
+		},
+		{
+			name: "Invalid HTTP timeout",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+http: {requestTimeout: forever}
+`,
+			errsContain: []string{"failed to parse `requestTimeout`"},
+		},
+		{
+			name: "HTTP headers and query parameters are loaded",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, http: {headers: {X-Route: {value: env-route}}}}]}]
+http:
+  headers: {X-Forwarded-User: {type: environment, value: client-id}}
+  queryParameters: {tenant: {value: abc}}
+  apis: [{pathPrefix: /api/v2/settings, headers: {X-Route: {value: settings}}}]
+`,
+			errsContain: []string{},
+			expectedManifest: Manifest{
+				Projects: map[string]ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						URL: URLDefinition{
+							Type:  ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: Auth{
+							Token: AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+						},
+						RequestAdditions: []RequestAddition{
+							{Headers: map[string]string{"X-Route": "env-route"}},
+						},
+					},
+				},
+				HTTP: HTTPSettings{
+					RequestAdditions: []RequestAddition{
+						{
+							Headers:         map[string]string{"X-Forwarded-User": "resolved-client-id"},
+							QueryParameters: map[string]string{"tenant": "abc"},
+						},
+						{
+							PathPrefix: "/api/v2/settings",
+							Headers:    map[string]string{"X-Route": "settings"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Offline settings are loaded and the token endpoint is applied",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [
+  {name: c, url: {value: d}, auth: {token: {name: e}, oAuth: {clientId: {name: client-id}, clientSecret: {name: client-secret}}}},
+  {name: f, url: {value: g}, auth: {token: {name: e}, oAuth: {clientId: {name: client-id}, clientSecret: {name: client-secret}, tokenEndpoint: {value: "https://own.sso"}}}}
+]}]
+offline: {enabled: true, oAuthTokenEndpoint: {value: "https://sso.mirror"}}
+`,
+			errsContain: []string{},
+			expectedManifest: Manifest{
+				Projects: map[string]ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				Environments: map[string]EnvironmentDefinition{
+					"c": {
+						Name:  "c",
+						URL:   URLDefinition{Type: ValueURLType, Value: "d"},
+						Group: "b",
+						Auth: Auth{
+							Token: AuthSecret{Name: "e", Value: "mock token"},
+							OAuth: &OAuth{
+								ClientID:      AuthSecret{Name: "client-id", Value: "resolved-client-id"},
+								ClientSecret:  AuthSecret{Name: "client-secret", Value: "resolved-client-secret"},
+								TokenEndpoint: &URLDefinition{Type: ValueURLType, Value: "https://sso.mirror"},
+							},
+						},
+					},
+					"f": {
+						Name:  "f",
+						URL:   URLDefinition{Type: ValueURLType, Value: "g"},
+						Group: "b",
+						Auth: Auth{
+							Token: AuthSecret{Name: "e", Value: "mock token"},
+							OAuth: &OAuth{
+								ClientID:      AuthSecret{Name: "client-id", Value: "resolved-client-id"},
+								ClientSecret:  AuthSecret{Name: "client-secret", Value: "resolved-client-secret"},
+								TokenEndpoint: &URLDefinition{Type: ValueURLType, Value: "https://own.sso"},
+							},
+						},
+					},
+				},
+				Offline: OfflineSettings{
+					Enabled:            true,
+					OAuthTokenEndpoint: &URLDefinition{Type: ValueURLType, Value: "https://sso.mirror"},
+				},
+			},
+		},
+		{
+			name: "Overridden project is not defined",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p, overrides: [b]}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+`,
+			errsContain: []string{"overridden project `b` is not defined"},
+		},
+		{
+			name: "HTTP header env var not found",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+http: {headers: {X-Forwarded-User: {type: environment, value: not-found}}}
+`,
+			errsContain: []string{`environment variable "not-found" could not be found`},
+		},
+		{
+			name: "HTTP API additions without path prefix",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}, http: {apis: [{headers: {X-Route: {value: r}}}]}}]}]
+`,
+			errsContain: []string{"`apis[0]` has no `pathPrefix`"},
+		},
+		{
+			name: "Accounts and account projects are loaded",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}, {name: acc, type: account}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+accounts: [{name: my-account, accountUUID: uuid, apiUrl: {value: "https://api.example.com"}, oAuth: {clientId: {name: client-id}, clientSecret: {name: client-secret}}}]
+`,
+			errsContain: []string{},
+			expectedManifest: Manifest{
+				Projects: map[string]ProjectDefinition{
+					"a": {
+						Name: "a",
+						Path: "p",
+					},
+				},
+				AccountProjects: map[string]ProjectDefinition{
+					"acc": {
+						Name: "acc",
+						Path: "acc",
+					},
+				},
+				Environments: map[string]EnvironmentDefinition{
+					"c": {
+						Name: "c",
+						URL: URLDefinition{
+							Type:  ValueURLType,
+							Value: "d",
+						},
+						Group: "b",
+						Auth: Auth{
+							Token: AuthSecret{
+								Name:  "e",
+								Value: "mock token",
+							},
+						},
+					},
+				},
+				Accounts: Accounts{
+					"my-account": {
+						Name:        "my-account",
+						AccountUUID: "uuid",
+						ApiURL: &URLDefinition{
+							Type:  ValueURLType,
+							Value: "https://api.example.com",
+						},
+						OAuth: OAuth{
+							ClientID:     AuthSecret{Name: "client-id", Value: "resolved-client-id"},
+							ClientSecret: AuthSecret{Name: "client-secret", Value: "resolved-client-secret"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Account project names must be unique",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}, {name: a, type: account}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+`,
+			errsContain: []string{"duplicated project name `a`"},
+		},
+		{
+			name: "Account without UUID",
+			manifestContent: `
+manifestVersion: 1.0
+projects: [{name: a, path: p}]
+environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {token: {name: e}}}]}]
+accounts: [{name: my-account, oAuth: {clientId: {name: client-id}, clientSecret: {name: client-secret}}}]
+`,
+			errsContain: []string{"invalid account `my-account`: `accountUUID` is not set"},
+		},
 		{
 			name: "ClientSecret env var not found",
 			manifestContent: `
@@ -1680,3 +2010,188 @@ environmentGroups: [{name: b, environments: [{name: c, url: {value: d}, auth: {t
 		})
 	}
 }
+
+func TestLoadManifest_ErrorsContainPositions(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`manifestVersion: 1.0
+projects:
+- name: valid
+- name: invalid
+  type: unknown
+environmentGroups:
+- name: default
+  environments:
+  - name: env
+    url:
+      value: https://example.com
+    auth:
+      token:
+        type: unknown
+        name: TOKEN
+`), 0400))
+
+	_, errs := LoadManifest(&LoaderContext{
+		Fs:           fs,
+		ManifestPath: "manifest.yaml",
+	})
+
+	assert.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], "manifest.yaml:4:3:invalid: invalid project type `unknown`")
+	assert.ErrorContains(t, errs[1], "manifest.yaml:9:5:default:env: failed to parse auth section")
+}
+
+func TestLoadManifest_UnknownKeysAreReportedWithSuggestion(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(`manifestVersion: 1.0
+projects:
+- name: project
+enviromentGroups: []
+`), 0400))
+
+	_, errs := LoadManifest(&LoaderContext{
+		Fs:           fs,
+		ManifestPath: "manifest.yaml",
+	})
+
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], `unknown key "enviromentGroups" (line 4, column 1) - did you mean "environmentGroups"?`)
+}
+
+func TestLoadManifest_ClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateCertificate(t)
+
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "project/certs/client.crt", certPEM, 0400))
+	assert.NoError(t, afero.WriteFile(fs, "project/certs/client.key", keyPEM, 0400))
+	assert.NoError(t, afero.WriteFile(fs, "project/manifest.yaml", []byte(`manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups: [{name: b, environments: [
+  {name: with-cert, url: {value: d}, auth: {token: {name: e}}, http: {clientCertificate: {certFile: certs/client.crt, keyFile: certs/client.key}}},
+  {name: missing-key, url: {value: d}, auth: {token: {name: e}}, http: {clientCertificate: {certFile: certs/client.crt, keyFile: certs/missing.key}}}
+]}]
+`), 0400))
+	t.Setenv("e", "mock token")
+
+	_, errs := LoadManifest(&LoaderContext{
+		Fs:           fs,
+		ManifestPath: "project/manifest.yaml",
+	})
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "missing-key: failed to load client certificate")
+
+	m, errs := LoadManifest(&LoaderContext{
+		Fs:           fs,
+		ManifestPath: "project/manifest.yaml",
+		Environments: []string{"with-cert"},
+	})
+	assert.Empty(t, errs)
+
+	cert := m.Environments["with-cert"].ClientCertificate
+	assert.NotNil(t, cert)
+	assert.Equal(t, "certs/client.crt", cert.CertFile)
+	assert.Equal(t, "certs/client.key", cert.KeyFile)
+	assert.Len(t, cert.Certificate.Certificate, 1)
+
+	assert.Same(t, &cert.Certificate, m.HTTP.ForEnvironment(m.Environments["with-cert"]).ClientCertificate)
+}
+
+func generateCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "monaco"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestLoadManifest_Rollout(t *testing.T) {
+	const groups = `manifestVersion: 1.0
+projects: [{name: a}]
+environmentGroups:
+  - {name: dev, environments: [{name: dev1, url: {value: d}, auth: {token: {name: e}}}]}
+  - {name: staging, environments: [{name: staging1, url: {value: d}, auth: {token: {name: e}}}]}
+  - {name: prod, environments: [{name: prod1, url: {value: d}, auth: {token: {name: e}}}]}
+`
+	t.Setenv("e", "mock token")
+
+	tests := []struct {
+		name          string
+		rollout       string
+		want          *RolloutPolicy
+		wantErrorPart string
+	}{
+		{
+			name: "stages are parsed",
+			rollout: `rollout:
+  stages:
+    - groups: [dev]
+    - groups: [staging]
+      wait: 10m
+    - groups: [prod]
+      approval: true
+`,
+			want: &RolloutPolicy{Stages: []RolloutStage{
+				{Groups: []string{"dev"}},
+				{Groups: []string{"staging"}, Wait: 10 * time.Minute},
+				{Groups: []string{"prod"}, Approval: true},
+			}},
+		},
+		{
+			name:    "no rollout",
+			rollout: "",
+			want:    nil,
+		},
+		{
+			name:          "no stages",
+			rollout:       "rollout: {stages: []}",
+			wantErrorPart: "invalid rollout: no `stages` defined",
+		},
+		{
+			name:          "group missing",
+			rollout:       "rollout: {stages: [{groups: [dev, staging]}]}",
+			wantErrorPart: `invalid rollout: group "prod" is not part of any stage`,
+		},
+		{
+			name:          "group in several stages",
+			rollout:       "rollout: {stages: [{groups: [dev, staging]}, {groups: [staging, prod]}]}",
+			wantErrorPart: `invalid rollout: group "staging" is part of stages 1 and 2`,
+		},
+		{
+			name:          "unknown group",
+			rollout:       "rollout: {stages: [{groups: [dev, staging, prod, qa]}]}",
+			wantErrorPart: `invalid rollout: unknown group "qa"`,
+		},
+		{
+			name:          "invalid wait",
+			rollout:       "rollout: {stages: [{groups: [dev, staging]}, {groups: [prod], wait: soon}]}",
+			wantErrorPart: "invalid rollout: invalid `wait` \"soon\" of stage 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(groups+tt.rollout), 0400))
+
+			m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml"})
+			if tt.wantErrorPart != "" {
+				assert.Len(t, errs, 1)
+				assert.ErrorContains(t, errs[0], tt.wantErrorPart)
+				return
+			}
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.want, m.Rollout)
+		})
+	}
+}
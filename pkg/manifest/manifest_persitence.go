@@ -17,10 +17,15 @@ package manifest
 const simpleProjectType = "simple"
 const groupProjectType = "grouping"
 
+// accountProjectType defines a project holding account resources (users, groups, policies) instead of configs
+const accountProjectType = "account"
+
 type project struct {
-	Name string `yaml:"name"`
-	Type string `yaml:"type,omitempty"`
-	Path string `yaml:"path,omitempty"`
+	Name      string            `yaml:"name"`
+	Type      string            `yaml:"type,omitempty" jsonschema:"enum=simple|grouping|account"`
+	Path      string            `yaml:"path,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+	Overrides []string          `yaml:"overrides,omitempty"`
 }
 
 type secretType string
@@ -34,14 +39,16 @@ const typeEnvironment secretType = "environment"
 //
 // This struct is meant to be reused for fields that require the same behavior.
 type authSecret struct {
-	Type secretType `yaml:"type"`
-	Name string     `yaml:"name"`
+	Type secretType `yaml:"type" jsonschema:"enum=environment"`
+	Name string     `yaml:"name" jsonschema:"required"`
 }
 
 type oAuth struct {
-	ClientID      authSecret `yaml:"clientId"`
-	ClientSecret  authSecret `yaml:"clientSecret"`
+	ClientID      authSecret `yaml:"clientId" jsonschema:"required"`
+	ClientSecret  authSecret `yaml:"clientSecret" jsonschema:"required"`
 	TokenEndpoint *url       `yaml:"tokenEndpoint,omitempty"`
+	Scopes        []string   `yaml:"scopes,omitempty"`
+	Audience      string     `yaml:"audience,omitempty"`
 }
 
 type auth struct {
@@ -50,11 +57,14 @@ type auth struct {
 }
 
 type environment struct {
-	Name string `yaml:"name"`
-	URL  url    `yaml:"url"`
+	Name string `yaml:"name" jsonschema:"required"`
+	URL  url    `yaml:"url" jsonschema:"required"`
 
 	// Auth contains all authentication related information
 	Auth auth `yaml:"auth,omitempty"`
+
+	// HTTP contains headers and query parameters added to all requests against this environment
+	HTTP *environmentHTTPSettings `yaml:"http,omitempty"`
 }
 
 type urlType string
@@ -65,17 +75,107 @@ const (
 )
 
 type url struct {
-	Type  urlType `yaml:"type,omitempty"`
-	Value string  `yaml:"value"`
+	Type  urlType `yaml:"type,omitempty" jsonschema:"enum=environment|value"`
+	Value string  `yaml:"value" jsonschema:"required"`
 }
 
 type group struct {
-	Name         string        `yaml:"name"`
-	Environments []environment `yaml:"environments"`
+	Name         string        `yaml:"name" jsonschema:"required"`
+	Environments []environment `yaml:"environments,omitempty"`
+	// Discover adds the environments of an account to the group when the manifest is loaded
+	Discover *discovery `yaml:"discover,omitempty"`
+}
+
+// discovery defines how environments are discovered using the account management API. URL and auth may refer to the
+// ID and name of each discovered environment, e.g. `https://{{ .id }}.live.dynatrace.com`.
+type discovery struct {
+	// Account is the name of the account in the manifest whose environments are discovered
+	Account string `yaml:"account" jsonschema:"required"`
+	// NamePattern is a regular expression the names of discovered environments must match
+	NamePattern string `yaml:"namePattern,omitempty"`
+	// Tags are tags discovered environments must all have, given as 'key' or 'key:value'
+	Tags []string `yaml:"tags,omitempty"`
+	URL  string   `yaml:"url,omitempty"`
+	Auth auth     `yaml:"auth" jsonschema:"required"`
+}
+
+// httpSettings defines settings applied to all HTTP calls made against any environment of the manifest.
+// Timeouts are defined as duration strings (e.g. "30s", "5m").
+type httpSettings struct {
+	ConnectTimeout string `yaml:"connectTimeout,omitempty"`
+	RequestTimeout string `yaml:"requestTimeout,omitempty"`
+
+	Headers         map[string]httpValue  `yaml:"headers,omitempty"`
+	QueryParameters map[string]httpValue  `yaml:"queryParameters,omitempty"`
+	APIs            []apiRequestAdditions `yaml:"apis,omitempty"`
+}
+
+// environmentHTTPSettings defines settings applied to all HTTP calls made against a single environment.
+type environmentHTTPSettings struct {
+	Headers           map[string]httpValue  `yaml:"headers,omitempty"`
+	QueryParameters   map[string]httpValue  `yaml:"queryParameters,omitempty"`
+	APIs              []apiRequestAdditions `yaml:"apis,omitempty"`
+	ClientCertificate *clientCertificate    `yaml:"clientCertificate,omitempty"`
+}
+
+// clientCertificate defines the PEM encoded certificate and key files used for mutual TLS. Relative paths are
+// resolved relative to the manifest.
+type clientCertificate struct {
+	CertFile string `yaml:"certFile" jsonschema:"required"`
+	KeyFile  string `yaml:"keyFile" jsonschema:"required"`
+}
+
+// apiRequestAdditions defines headers and query parameters only added to requests whose path starts with PathPrefix.
+type apiRequestAdditions struct {
+	PathPrefix      string               `yaml:"pathPrefix" jsonschema:"required"`
+	Headers         map[string]httpValue `yaml:"headers,omitempty"`
+	QueryParameters map[string]httpValue `yaml:"queryParameters,omitempty"`
+}
+
+// httpValue is the value of a header or query parameter. Like the url, it is either given directly or loaded from
+// an environment variable.
+type httpValue struct {
+	Type  urlType `yaml:"type,omitempty" jsonschema:"enum=environment|value"`
+	Value string  `yaml:"value" jsonschema:"required"`
+}
+
+// offline defines settings for environments without access to the internet. If enabled, no auxiliary calls besides
+// the ones needed for the actual command are made. The OAuth token endpoint is used by all environments and accounts
+// not defining their own, instead of the public Dynatrace SSO.
+type offline struct {
+	Enabled            bool `yaml:"enabled,omitempty"`
+	OAuthTokenEndpoint *url `yaml:"oAuthTokenEndpoint,omitempty"`
+}
+
+// account defines a Dynatrace account managed via the account management API.
+type account struct {
+	Name        string `yaml:"name" jsonschema:"required"`
+	AccountUUID string `yaml:"accountUUID" jsonschema:"required"`
+	ApiURL      *url   `yaml:"apiUrl,omitempty"`
+	OAuth       oAuth  `yaml:"oAuth" jsonschema:"required"`
 }
 
 type manifest struct {
-	ManifestVersion   string    `yaml:"manifestVersion"`
-	Projects          []project `yaml:"projects"`
-	EnvironmentGroups []group   `yaml:"environmentGroups"`
+	ManifestVersion   string        `yaml:"manifestVersion" jsonschema:"required"`
+	Projects          []project     `yaml:"projects" jsonschema:"required"`
+	EnvironmentGroups []group       `yaml:"environmentGroups" jsonschema:"required"`
+	Accounts          []account     `yaml:"accounts,omitempty"`
+	HTTP              *httpSettings `yaml:"http,omitempty"`
+	Offline           *offline      `yaml:"offline,omitempty"`
+	// VariablesFile is a YAML file of variables that group names and URLs of environments may refer to, e.g.
+	// `https://{{ .tenant }}.live.dynatrace.com`. It is resolved relative to the manifest.
+	VariablesFile string `yaml:"variablesFile,omitempty"`
+	// Rollout defines the order environment groups are deployed in
+	Rollout *rollout `yaml:"rollout,omitempty"`
+}
+
+type rollout struct {
+	Stages []rolloutStage `yaml:"stages" jsonschema:"required"`
+}
+
+type rolloutStage struct {
+	Groups []string `yaml:"groups" jsonschema:"required"`
+	// Wait is a duration string, e.g. "10m"
+	Wait     string `yaml:"wait,omitempty"`
+	Approval bool   `yaml:"approval,omitempty"`
 }
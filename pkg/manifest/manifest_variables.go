@@ -0,0 +1,118 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// EnvManifestVariablesFile optionally holds the path of a variables file, taking precedence over the `variablesFile`
+// of the manifest. This allows to use the same manifest for several similarly structured tenants.
+const EnvManifestVariablesFile = "MONACO_MANIFEST_VARIABLES_FILE"
+
+// variables are the values group names and environment URLs of a manifest may refer to, e.g.
+// `https://{{ .tenant }}.live.dynatrace.com`.
+type variables map[string]string
+
+// loadVariables returns the variables of the variables file, overridden by environment variables of the same name.
+func loadVariables(context *LoaderContext, variablesFile string) (variables, error) {
+	vars := make(variables)
+
+	var path string
+	if p, found := os.LookupEnv(EnvManifestVariablesFile); found {
+		path = p
+	} else if variablesFile != "" {
+		path = resolveRelativeToManifest(context, variablesFile)
+	}
+
+	if path != "" {
+		data, err := afero.ReadFile(context.Fs, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read variables file: %w", err)
+		}
+
+		var values map[string]interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse variables file %q: %w", path, err)
+		}
+		for k, v := range values {
+			switch v.(type) {
+			case map[interface{}]interface{}, []interface{}:
+				return nil, fmt.Errorf("variable %q of variables file %q must be a single value", k, path)
+			}
+			vars[k] = fmt.Sprint(v)
+		}
+	}
+
+	for _, e := range os.Environ() {
+		if k, v, found := strings.Cut(e, "="); found {
+			vars[k] = v
+		}
+	}
+	return vars, nil
+}
+
+// resolve renders the given value as Go template of the variables. Values without template actions are returned as is.
+func (v variables) resolve(value string) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New("").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", value, err)
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, map[string]string(v)); err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", value, err)
+	}
+	return b.String(), nil
+}
+
+// resolveVariables resolves the variables in group names and URL values of the given manifest.
+func resolveVariables(context *LoaderContext, m *manifest) []error {
+	vars, err := loadVariables(context, m.VariablesFile)
+	if err != nil {
+		return []error{manifestLoaderError{context.ManifestPath, err.Error()}}
+	}
+
+	var errs []error
+	for i := range m.EnvironmentGroups {
+		g := &m.EnvironmentGroups[i]
+		if g.Name, err = vars.resolve(g.Name); err != nil {
+			errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("invalid group name: %s", err)})
+		}
+
+		for j := range g.Environments {
+			u := &g.Environments[j].URL
+			if u.Type != "" && u.Type != urlTypeValue {
+				continue
+			}
+			if u.Value, err = vars.resolve(u.Value); err != nil {
+				errs = append(errs, manifestLoaderError{context.ManifestPath, fmt.Sprintf("invalid URL of environment %q: %s", g.Environments[j].Name, err)})
+			}
+		}
+	}
+	return errs
+}
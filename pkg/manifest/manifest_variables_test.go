@@ -0,0 +1,90 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const templatedManifest = `manifestVersion: 1.0
+variablesFile: vars.yaml
+projects: [{name: a}]
+environmentGroups:
+- name: "{{ .stage }}"
+  environments:
+  - {name: tenant, url: {value: "https://{{ .tenant }}.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+`
+
+func TestLoadManifest_ResolvesVariables(t *testing.T) {
+	t.Setenv("TOKEN", "mock token")
+
+	t.Run("variables are read from the variables file", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "project/manifest.yaml", []byte(templatedManifest), 0400))
+		assert.NoError(t, afero.WriteFile(fs, "project/vars.yaml", []byte("tenant: abc12345\nstage: prod\n"), 0400))
+
+		m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "project/manifest.yaml"})
+		assert.Empty(t, errs)
+		assert.Equal(t, "https://abc12345.live.dynatrace.com", m.Environments["tenant"].URL.Value)
+		assert.Equal(t, "prod", m.Environments["tenant"].Group)
+	})
+
+	t.Run("environment variables take precedence over the variables file", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(templatedManifest), 0400))
+		assert.NoError(t, afero.WriteFile(fs, "vars.yaml", []byte("tenant: abc12345\nstage: prod\n"), 0400))
+		t.Setenv("tenant", "xyz98765")
+
+		m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml"})
+		assert.Empty(t, errs)
+		assert.Equal(t, "https://xyz98765.live.dynatrace.com", m.Environments["tenant"].URL.Value)
+	})
+
+	t.Run("the variables file can be set by environment variable", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(templatedManifest), 0400))
+		assert.NoError(t, afero.WriteFile(fs, "other.yaml", []byte("tenant: other\nstage: dev\n"), 0400))
+		t.Setenv(EnvManifestVariablesFile, "other.yaml")
+
+		m, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml", Groups: []string{"dev"}})
+		assert.Empty(t, errs)
+		assert.Equal(t, "https://other.live.dynatrace.com", m.Environments["tenant"].URL.Value)
+	})
+
+	t.Run("undefined variables fail", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(templatedManifest), 0400))
+		assert.NoError(t, afero.WriteFile(fs, "vars.yaml", []byte("stage: prod\n"), 0400))
+
+		_, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml"})
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], `invalid URL of environment "tenant"`)
+	})
+
+	t.Run("missing variables file fails", func(t *testing.T) {
+		fs := afero.NewMemMapFs()
+		assert.NoError(t, afero.WriteFile(fs, "manifest.yaml", []byte(templatedManifest), 0400))
+
+		_, errs := LoadManifest(&LoaderContext{Fs: fs, ManifestPath: "manifest.yaml"})
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "failed to read variables file")
+	})
+}
@@ -45,17 +45,67 @@ func WriteManifest(context *WriterContext, manifestToWrite Manifest) error {
 	}
 
 	projects := toWriteableProjects(manifestToWrite.Projects)
+	projects = append(projects, toWriteableAccountProjects(manifestToWrite.AccountProjects)...)
 	groups := toWriteableEnvironmentGroups(manifestToWrite.Environments)
 
 	m := manifest{
 		ManifestVersion:   version.ManifestVersion,
 		Projects:          projects,
 		EnvironmentGroups: groups,
+		Accounts:          toWriteableAccounts(manifestToWrite.Accounts),
+		HTTP:              toWriteableHTTPSettings(manifestToWrite.HTTP),
+		Offline:           toWriteableOfflineSettings(manifestToWrite.Offline),
+		Rollout:           toWriteableRollout(manifestToWrite.Rollout),
 	}
 
 	return persistManifestToDisk(context, m)
 }
 
+// toWriteableHTTPSettings converts the timeouts of the given settings. Request additions are not written, as their
+// values may have been resolved from environment variables.
+func toWriteableHTTPSettings(s HTTPSettings) *httpSettings {
+	if s.ConnectTimeout == 0 && s.RequestTimeout == 0 {
+		return nil
+	}
+
+	result := httpSettings{}
+	if s.ConnectTimeout > 0 {
+		result.ConnectTimeout = s.ConnectTimeout.String()
+	}
+	if s.RequestTimeout > 0 {
+		result.RequestTimeout = s.RequestTimeout.String()
+	}
+	return &result
+}
+
+func toWriteableRollout(r *RolloutPolicy) *rollout {
+	if r == nil {
+		return nil
+	}
+
+	result := rollout{Stages: make([]rolloutStage, len(r.Stages))}
+	for i, s := range r.Stages {
+		result.Stages[i] = rolloutStage{Groups: s.Groups, Approval: s.Approval}
+		if s.Wait > 0 {
+			result.Stages[i].Wait = s.Wait.String()
+		}
+	}
+	return &result
+}
+
+func toWriteableOfflineSettings(o OfflineSettings) *offline {
+	if !o.Enabled && o.OAuthTokenEndpoint == nil {
+		return nil
+	}
+
+	result := offline{Enabled: o.Enabled}
+	if o.OAuthTokenEndpoint != nil {
+		u := toWriteableURLDefinition(*o.OAuthTokenEndpoint)
+		result.OAuthTokenEndpoint = &u
+	}
+	return &result
+}
+
 func persistManifestToDisk(context *WriterContext, m manifest) error {
 	manifestAsYaml, err := yaml.Marshal(m)
 
@@ -75,14 +125,16 @@ func toWriteableProjects(projects map[string]ProjectDefinition) (result []projec
 			groupName, groupPath := extractGroupedProjectDetails(projectDefinition)
 
 			groups[groupName] = project{
-				Name: groupName,
-				Path: groupPath,
-				Type: groupProjectType,
+				Name:      groupName,
+				Path:      groupPath,
+				Type:      groupProjectType,
+				Variables: projectDefinition.Variables,
+				Overrides: projectDefinition.Overrides,
 			}
 			continue
 		}
 
-		p := project{Name: projectDefinition.Name}
+		p := project{Name: projectDefinition.Name, Variables: projectDefinition.Variables, Overrides: projectDefinition.Overrides}
 
 		if projectDefinition.Name != projectDefinition.Path {
 			p.Path = projectDefinition.Path
@@ -98,6 +150,50 @@ func toWriteableProjects(projects map[string]ProjectDefinition) (result []projec
 	return result
 }
 
+func toWriteableAccountProjects(projects map[string]ProjectDefinition) (result []project) {
+	for _, projectDefinition := range projects {
+		p := project{Name: projectDefinition.Name, Type: accountProjectType}
+
+		if projectDefinition.Name != projectDefinition.Path {
+			p.Path = projectDefinition.Path
+		}
+
+		result = append(result, p)
+	}
+	return result
+}
+
+func toWriteableAccounts(accounts Accounts) (result []account) {
+	for _, a := range accounts {
+		var apiURL *url
+		if a.ApiURL != nil {
+			u := toWriteableURLDefinition(*a.ApiURL)
+			apiURL = &u
+		}
+
+		result = append(result, account{
+			Name:        a.Name,
+			AccountUUID: a.AccountUUID,
+			ApiURL:      apiURL,
+			OAuth:       *getOAuthCredentials(&a.OAuth),
+		})
+	}
+	return result
+}
+
+func toWriteableURLDefinition(u URLDefinition) url {
+	if u.Type == EnvironmentURLType {
+		return url{
+			Type:  urlTypeEnvironment,
+			Value: u.Name,
+		}
+	}
+
+	return url{
+		Value: u.Value,
+	}
+}
+
 func isGroupingProject(projectDefinition ProjectDefinition) bool {
 	return strings.Contains(projectDefinition.Name, ".") &&
 		strings.ReplaceAll(projectDefinition.Name, ".", "/") == projectDefinition.Path
@@ -121,6 +217,14 @@ func toWriteableEnvironmentGroups(environments map[string]EnvironmentDefinition)
 			URL:  toWriteableURL(env),
 			Auth: getAuth(env),
 		}
+		if env.ClientCertificate != nil {
+			e.HTTP = &environmentHTTPSettings{
+				ClientCertificate: &clientCertificate{
+					CertFile: env.ClientCertificate.CertFile,
+					KeyFile:  env.ClientCertificate.KeyFile,
+				},
+			}
+		}
 
 		environmentPerGroup[env.Group] = append(environmentPerGroup[env.Group], e)
 	}
@@ -140,16 +244,7 @@ func getAuth(env EnvironmentDefinition) auth {
 }
 
 func toWriteableURL(environment EnvironmentDefinition) url {
-	if environment.URL.Type == EnvironmentURLType {
-		return url{
-			Type:  urlTypeEnvironment,
-			Value: environment.URL.Name,
-		}
-	}
-
-	return url{
-		Value: environment.URL.Value,
-	}
+	return toWriteableURLDefinition(environment.URL)
 }
 
 // getTokenSecret returns the tokenConfig with some legacy magic string append that still might be used (?)
@@ -197,5 +292,7 @@ func getOAuthCredentials(a *OAuth) *oAuth {
 			Name: a.ClientSecret.Name,
 		},
 		TokenEndpoint: te,
+		Scopes:        a.Scopes,
+		Audience:      a.Audience,
 	}
 }
@@ -0,0 +1,134 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ownership marks objects deployed by monaco, so that they can be told apart from objects created manually.
+//
+// Settings 2.0 objects are identified by the externalId monaco generates for them (see idutils.GenerateExternalID).
+// Config API objects have no externalId, thus a marker naming the deploying config is appended to their description.
+package ownership
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"strings"
+)
+
+// descriptionField is the top-level property of payloads markers are appended to
+const descriptionField = "description"
+
+// markerPrefix starts every marker, followed by the coordinate of the config, e.g.
+// '[managed by monaco – project:alerting-profile:profile]'
+const markerPrefix = "[managed by monaco"
+
+// Marker returns the marker appended to the descriptions of objects deployed from the config of the given coordinate.
+func Marker(c coordinate.Coordinate) string {
+	return fmt.Sprintf("%s – %s]", markerPrefix, c)
+}
+
+// Mark appends the marker of the given coordinate to the description of the given JSON payload, replacing any marker
+// it already contains. Payloads without top-level description are returned unchanged, as not all APIs accept one.
+func Mark(payload []byte, c coordinate.Coordinate) ([]byte, error) {
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &properties); err != nil {
+		return nil, fmt.Errorf("failed to add ownership marker: %w", err)
+	}
+
+	raw, found := properties[descriptionField]
+	if !found {
+		return payload, nil
+	}
+
+	var description string
+	if err := json.Unmarshal(raw, &description); err != nil && string(raw) != "null" {
+		return nil, fmt.Errorf("failed to add ownership marker: %q is not a string", descriptionField)
+	}
+
+	description = strings.TrimSpace(unmarked(description))
+	if description != "" {
+		description += "\n\n"
+	}
+	description += Marker(c)
+
+	marked, err := json.Marshal(description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add ownership marker: %w", err)
+	}
+	properties[descriptionField] = marked
+	return json.Marshal(properties)
+}
+
+// unmarked returns the description without the marker it ends with, if any.
+func unmarked(description string) string {
+	if i := strings.LastIndex(description, markerPrefix); i >= 0 && strings.HasSuffix(description, "]") {
+		return description[:i]
+	}
+	return description
+}
+
+// IsMarked returns whether the description of the given payload contains a marker.
+func IsMarked(payload map[string]interface{}) bool {
+	description, ok := payload[descriptionField].(string)
+	return ok && strings.Contains(description, markerPrefix)
+}
+
+// IsManagedExternalId returns whether the given externalId of a settings 2.0 object was generated by monaco.
+func IsManagedExternalId(externalId string) bool {
+	return strings.HasPrefix(externalId, idutils.ExternalIDPrefix)
+}
+
+// Filter selects objects by whether they are managed by monaco. The zero value selects all objects.
+type Filter string
+
+const (
+	// All selects all objects
+	All Filter = ""
+	// Managed selects objects deployed by monaco
+	Managed Filter = "managed"
+	// Unmanaged selects objects not deployed by monaco, e.g. created manually
+	Unmanaged Filter = "unmanaged"
+)
+
+// ParseFilter parses 'managed' or 'unmanaged'. An empty string selects all objects.
+func ParseFilter(s string) (Filter, error) {
+	switch f := Filter(s); f {
+	case All, Managed, Unmanaged:
+		return f, nil
+	default:
+		return All, fmt.Errorf("invalid ownership filter %q! expected '%s' or '%s'", s, Managed, Unmanaged)
+	}
+}
+
+// Selects returns whether an object which is managed or not is selected by the filter.
+func (f Filter) Selects(managed bool) bool {
+	switch f {
+	case Managed:
+		return managed
+	case Unmanaged:
+		return !managed
+	default:
+		return true
+	}
+}
+
+// Reason returns why objects not selected by the filter are filtered
+func (f Filter) Reason() string {
+	if f == Managed {
+		return "not managed by monaco"
+	}
+	return "managed by monaco"
+}
@@ -0,0 +1,94 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ownership
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func TestMark(t *testing.T) {
+	c := coordinate.Coordinate{Project: "project", Type: "alerting-profile", ConfigId: "profile"}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			"marker is appended to description",
+			`{"description": "Team A", "id": 1234567890123456789}`,
+			`{"description":"Team A\n\n[managed by monaco – project:alerting-profile:profile]","id":1234567890123456789}`,
+		},
+		{
+			"empty description is replaced",
+			`{"description": null}`,
+			`{"description":"[managed by monaco – project:alerting-profile:profile]"}`,
+		},
+		{
+			"existing marker is replaced",
+			`{"description": "Team A\n\n[managed by monaco – other:alerting-profile:profile]"}`,
+			`{"description":"Team A\n\n[managed by monaco – project:alerting-profile:profile]"}`,
+		},
+		{
+			"payloads without description are not changed",
+			`{"name": "profile"}`,
+			`{"name": "profile"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Mark([]byte(tt.payload), c)
+			assert.NilError(t, err)
+			assert.Equal(t, string(got), tt.want)
+		})
+	}
+
+	_, err := Mark([]byte(`{"description": 1}`), c)
+	assert.ErrorContains(t, err, "not a string")
+}
+
+func TestIsMarked(t *testing.T) {
+	assert.Equal(t, IsMarked(map[string]interface{}{"description": "Team A\n\n" + Marker(coordinate.Coordinate{})}), true)
+	assert.Equal(t, IsMarked(map[string]interface{}{"description": "Team A"}), false)
+	assert.Equal(t, IsMarked(map[string]interface{}{}), false)
+}
+
+func TestIsManagedExternalId(t *testing.T) {
+	assert.Equal(t, IsManagedExternalId(idutils.GenerateExternalID("builtin:alerting.profile", "profile")), true)
+	assert.Equal(t, IsManagedExternalId("terraform:profile"), false)
+	assert.Equal(t, IsManagedExternalId(""), false)
+}
+
+func TestFilter(t *testing.T) {
+	for _, s := range []string{"", "managed", "unmanaged"} {
+		_, err := ParseFilter(s)
+		assert.NilError(t, err)
+	}
+	_, err := ParseFilter("manual")
+	assert.ErrorContains(t, err, "invalid ownership filter")
+
+	assert.Equal(t, All.Selects(true) && All.Selects(false), true)
+	assert.Equal(t, Managed.Selects(true), true)
+	assert.Equal(t, Managed.Selects(false), false)
+	assert.Equal(t, Unmanaged.Selects(true), false)
+	assert.Equal(t, Unmanaged.Selects(false), true)
+}
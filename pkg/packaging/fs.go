@@ -0,0 +1,106 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// routingFs serves all paths below root from the read-only package content, and all other paths from the underlying
+// file system. Unlike a union of both, files of the underlying file system can neither shadow nor extend the package.
+type routingFs struct {
+	root string
+	pkg  afero.Fs
+	host afero.Fs
+}
+
+// newRoutingFs returns a file system serving root read-only from pkg, and everything else from host.
+func newRoutingFs(root string, pkg, host afero.Fs) afero.Fs {
+	return routingFs{root: filepath.Clean(root), pkg: afero.NewReadOnlyFs(pkg), host: host}
+}
+
+// route returns the file system responsible for the given path.
+func (r routingFs) route(name string) afero.Fs {
+	if !filepath.IsAbs(name) {
+		return r.host
+	}
+	name = filepath.Clean(name)
+	if name == r.root || strings.HasPrefix(name, r.root+string(filepath.Separator)) {
+		return r.pkg
+	}
+	return r.host
+}
+
+func (r routingFs) Create(name string) (afero.File, error) {
+	return r.route(name).Create(name)
+}
+
+func (r routingFs) Mkdir(name string, perm os.FileMode) error {
+	return r.route(name).Mkdir(name, perm)
+}
+
+func (r routingFs) MkdirAll(path string, perm os.FileMode) error {
+	return r.route(path).MkdirAll(path, perm)
+}
+
+func (r routingFs) Open(name string) (afero.File, error) {
+	return r.route(name).Open(name)
+}
+
+func (r routingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return r.route(name).OpenFile(name, flag, perm)
+}
+
+func (r routingFs) Remove(name string) error {
+	return r.route(name).Remove(name)
+}
+
+func (r routingFs) RemoveAll(path string) error {
+	return r.route(path).RemoveAll(path)
+}
+
+func (r routingFs) Rename(oldname, newname string) error {
+	from, to := r.route(oldname), r.route(newname)
+	if from != r.host || to != r.host {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: os.ErrPermission}
+	}
+	return r.host.Rename(oldname, newname)
+}
+
+func (r routingFs) Stat(name string) (os.FileInfo, error) {
+	return r.route(name).Stat(name)
+}
+
+func (r routingFs) Name() string {
+	return "routingFs"
+}
+
+func (r routingFs) Chmod(name string, mode os.FileMode) error {
+	return r.route(name).Chmod(name, mode)
+}
+
+func (r routingFs) Chown(name string, uid, gid int) error {
+	return r.route(name).Chown(name, uid, gid)
+}
+
+func (r routingFs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return r.route(name).Chtimes(name, atime, mtime)
+}
@@ -0,0 +1,264 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package packaging bundles a manifest and its projects into an immutable, content-addressed zip archive, so the very
+// same artifact can be promoted through environments.
+//
+// A package contains the manifest as ManifestFile, all files of its projects and its variables file at their path
+// relative to the manifest, and optionally a LockFile pinning the versions of the Settings 2.0 schemas used by the
+// projects. The archive is written deterministically, so packaging the same content twice results in the same digest.
+//
+// Packages can be signed with an Ed25519 key. The signature is stored next to the package, see SignatureExtension.
+package packaging
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ManifestFile is the name of the manifest in a package
+	ManifestFile = "manifest.yaml"
+	// LockFile is the name of the file pinning the schema versions in a package
+	LockFile = "schemas.lock.json"
+)
+
+// modTime is the modification time of all files in a package. Using a fixed time keeps archives of the same content
+// identical. Zip archives can't represent times before 1980.
+var modTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Lock maps the IDs of Settings 2.0 schemas to the version settings configs without explicit schema version are
+// deployed with.
+type Lock map[string]string
+
+// Package is an opened package.
+type Package struct {
+	// Fs contains the files of the package, layered over the filesystem the package was opened from. Writes go to the
+	// underlying filesystem.
+	Fs afero.Fs
+	// ManifestPath is the absolute path of the manifest in Fs
+	ManifestPath string
+	// Digest is the content address of the package, e.g. 'sha256:3a7bd3e2...'
+	Digest string
+	// Lock holds the schema versions pinned by the package. It is empty if the package contains no LockFile.
+	Lock Lock
+}
+
+// Build packages the manifest at manifestPath and the projects of m, which must be loaded from it, into a zip archive.
+// Projects and the variables file of the manifest must be located within the directory of the manifest.
+func Build(fs afero.Fs, manifestPath string, m manifest.Manifest, lock Lock) ([]byte, error) {
+	files := map[string][]byte{}
+
+	content, err := afero.ReadFile(fs, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %q: %w", manifestPath, err)
+	}
+	files[ManifestFile] = content
+
+	if len(lock) > 0 {
+		content, err := json.MarshalIndent(lock, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[LockFile] = content
+	}
+
+	workingDir := filepath.Dir(manifestPath)
+	for _, p := range m.Projects {
+		if err := addProject(fs, workingDir, p, files); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.VariablesFile != "" {
+		if err := addVariablesFile(fs, workingDir, m.VariablesFile, files); err != nil {
+			return nil, err
+		}
+	}
+
+	return writeZip(files)
+}
+
+func addVariablesFile(fs afero.Fs, workingDir string, variablesFile string, files map[string][]byte) error {
+	if filepath.IsAbs(variablesFile) {
+		return fmt.Errorf("variables file %q can not be packaged: its path must be relative to the manifest", variablesFile)
+	}
+	rel := filepath.Clean(variablesFile)
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("variables file %q can not be packaged: its path is outside of the directory of the manifest", variablesFile)
+	}
+
+	content, err := afero.ReadFile(fs, filepath.Join(workingDir, rel))
+	if err != nil {
+		return fmt.Errorf("failed to read variables file %q: %w", variablesFile, err)
+	}
+	files[filepath.ToSlash(rel)] = content
+	return nil
+}
+
+func addProject(fs afero.Fs, workingDir string, p manifest.ProjectDefinition, files map[string][]byte) error {
+	if filepath.IsAbs(p.Path) {
+		return fmt.Errorf("project %q can not be packaged: its path %q must be relative to the manifest", p.Name, p.Path)
+	}
+	root := filepath.Clean(p.Path)
+	if root == ".." || strings.HasPrefix(root, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("project %q can not be packaged: its path %q is outside of the directory of the manifest", p.Name, p.Path)
+	}
+
+	return afero.Walk(fs, filepath.Join(workingDir, root), func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(workingDir, p)
+		if err != nil {
+			return err
+		}
+		content, err := afero.ReadFile(fs, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+}
+
+func writeZip(files map[string][]byte) ([]byte, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Digest returns the content address of the given archive.
+func Digest(archive []byte) string {
+	sum := sha256.Sum256(archive)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// FileName returns the name of the archive file of a package, based on its digest.
+func FileName(digest string) string {
+	_, sum, _ := strings.Cut(digest, ":")
+	if len(sum) > 12 {
+		sum = sum[:12]
+	}
+	return "monaco-package-" + sum + ".zip"
+}
+
+// Open reads the package at archivePath in fs.
+func Open(fs afero.Fs, archivePath string) (Package, error) {
+	archive, err := afero.ReadFile(fs, archivePath)
+	if err != nil {
+		return Package{}, fmt.Errorf("failed to read package %q: %w", archivePath, err)
+	}
+	return open(fs, archivePath, archive)
+}
+
+func open(fs afero.Fs, archivePath string, archive []byte) (Package, error) {
+	digest := Digest(archive)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return Package{}, fmt.Errorf("failed to read package %q: %w", archivePath, err)
+	}
+
+	// the package is extracted to a folder unique to its content. It is served exclusively from the package, so that
+	// files of the underlying fs at the same path can't alter the (verified) package content.
+	_, sum, _ := strings.Cut(digest, ":")
+	root := filepath.Join(string(filepath.Separator), ".monaco-package", sum)
+
+	packageFs := afero.NewMemMapFs()
+	for _, f := range zr.File {
+		name := path.Clean(f.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return Package{}, fmt.Errorf("package %q contains invalid path %q", archivePath, f.Name)
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return Package{}, fmt.Errorf("failed to read %q from package %q: %w", f.Name, archivePath, err)
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(name))
+		if err := packageFs.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			return Package{}, err
+		}
+		if err := afero.WriteFile(packageFs, target, content, 0664); err != nil {
+			return Package{}, err
+		}
+	}
+
+	manifestPath := filepath.Join(root, ManifestFile)
+	if exists, _ := afero.Exists(packageFs, manifestPath); !exists {
+		return Package{}, fmt.Errorf("package %q contains no %s", archivePath, ManifestFile)
+	}
+
+	lock := Lock{}
+	if content, err := afero.ReadFile(packageFs, filepath.Join(root, LockFile)); err == nil {
+		if err := json.Unmarshal(content, &lock); err != nil {
+			return Package{}, fmt.Errorf("invalid %s in package %q: %w", LockFile, archivePath, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return Package{}, err
+	}
+
+	return Package{
+		Fs:           newRoutingFs(root, packageFs, fs),
+		ManifestPath: manifestPath,
+		Digest:       digest,
+		Lock:         lock,
+	}, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
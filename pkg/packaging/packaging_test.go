@@ -0,0 +1,165 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildAndOpen(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	manifestPath, _ := filepath.Abs("workdir/manifest.yaml")
+	workingDir := filepath.Dir(manifestPath)
+	_ = afero.WriteFile(fs, manifestPath, []byte("manifestVersion: 1.0"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(workingDir, "project", "profile", "config.yaml"), []byte("configs: []"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(workingDir, "project", "profile", "profile.json"), []byte("{}"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(workingDir, "unrelated", "file.json"), []byte("{}"), 0644)
+
+	m := manifest.Manifest{Projects: manifest.ProjectDefinitionByProjectID{"project": {Name: "project", Path: "project"}}}
+	lock := Lock{"builtin:alerting.profile": "8.0"}
+
+	archive, err := Build(fs, manifestPath, m, lock)
+	assert.NoError(t, err)
+
+	t.Run("packages of the same content are identical", func(t *testing.T) {
+		again, err := Build(fs, manifestPath, m, lock)
+		assert.NoError(t, err)
+		assert.Equal(t, Digest(archive), Digest(again))
+	})
+
+	t.Run("packages contain the manifest, projects and lock", func(t *testing.T) {
+		_ = afero.WriteFile(fs, "package.zip", archive, 0644)
+
+		p, err := Open(fs, "package.zip")
+		assert.NoError(t, err)
+		assert.Equal(t, Digest(archive), p.Digest)
+		assert.Equal(t, lock, p.Lock)
+
+		content, err := afero.ReadFile(p.Fs, p.ManifestPath)
+		assert.NoError(t, err)
+		assert.Equal(t, "manifestVersion: 1.0", string(content))
+
+		root := filepath.Dir(p.ManifestPath)
+		for _, f := range []string{"project/profile/config.yaml", "project/profile/profile.json"} {
+			exists, _ := afero.Exists(p.Fs, filepath.Join(root, filepath.FromSlash(f)))
+			assert.True(t, exists, f)
+		}
+		exists, _ := afero.Exists(p.Fs, filepath.Join(root, "unrelated", "file.json"))
+		assert.False(t, exists)
+	})
+
+	t.Run("writes go to the underlying filesystem", func(t *testing.T) {
+		p, err := Open(fs, "package.zip")
+		assert.NoError(t, err)
+
+		_ = afero.WriteFile(p.Fs, "audit.log", []byte("record"), 0644)
+		exists, _ := afero.Exists(fs, "audit.log")
+		assert.True(t, exists)
+	})
+
+	t.Run("files of the underlying filesystem can't alter the package", func(t *testing.T) {
+		p, err := Open(fs, "package.zip")
+		assert.NoError(t, err)
+
+		root := filepath.Dir(p.ManifestPath)
+		_ = afero.WriteFile(fs, p.ManifestPath, []byte("manifestVersion: 1.0\nprojects: [planted]"), 0644)
+		_ = afero.WriteFile(fs, filepath.Join(root, "planted", "config.yaml"), []byte("configs: []"), 0644)
+
+		content, err := afero.ReadFile(p.Fs, p.ManifestPath)
+		assert.NoError(t, err)
+		assert.Equal(t, "manifestVersion: 1.0", string(content))
+
+		exists, _ := afero.Exists(p.Fs, filepath.Join(root, "planted", "config.yaml"))
+		assert.False(t, exists)
+
+		err = afero.WriteFile(p.Fs, p.ManifestPath, []byte("changed"), 0644)
+		assert.Error(t, err)
+	})
+
+	t.Run("file names are based on the digest", func(t *testing.T) {
+		assert.Equal(t, "monaco-package-0123456789ab.zip", FileName("sha256:0123456789abcdef"))
+	})
+}
+
+func TestBuildFailsForProjectsOutsideOfManifestDirectory(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	manifestPath, _ := filepath.Abs("workdir/manifest.yaml")
+	_ = afero.WriteFile(fs, manifestPath, []byte("manifestVersion: 1.0"), 0644)
+
+	m := manifest.Manifest{Projects: manifest.ProjectDefinitionByProjectID{"project": {Name: "project", Path: "../project"}}}
+
+	_, err := Build(fs, manifestPath, m, nil)
+	assert.ErrorContains(t, err, "outside of the directory of the manifest")
+}
+
+func TestBuildAndOpen_VariablesFile(t *testing.T) {
+	t.Setenv("TOKEN", "mock token")
+
+	fs := afero.NewMemMapFs()
+	manifestPath, _ := filepath.Abs("workdir/manifest.yaml")
+	workingDir := filepath.Dir(manifestPath)
+	_ = afero.WriteFile(fs, manifestPath, []byte(`manifestVersion: 1.0
+variablesFile: vars/prod.yaml
+projects: [{name: project}]
+environmentGroups:
+- name: default
+  environments:
+  - {name: tenant, url: {value: "https://{{ .tenant }}.live.dynatrace.com"}, auth: {token: {name: TOKEN}}}
+`), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(workingDir, "vars", "prod.yaml"), []byte("tenant: abc12345"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(workingDir, "project", "config.yaml"), []byte("configs: []"), 0644)
+
+	m, errs := manifest.LoadManifest(&manifest.LoaderContext{Fs: fs, ManifestPath: manifestPath})
+	assert.Empty(t, errs)
+
+	archive, err := Build(fs, manifestPath, m, nil)
+	assert.NoError(t, err)
+	_ = afero.WriteFile(fs, "package.zip", archive, 0644)
+	_ = fs.Remove(filepath.Join(workingDir, "vars", "prod.yaml"))
+
+	p, err := Open(fs, "package.zip")
+	assert.NoError(t, err)
+
+	m, errs = manifest.LoadManifest(&manifest.LoaderContext{Fs: p.Fs, ManifestPath: p.ManifestPath})
+	assert.Empty(t, errs)
+	assert.Equal(t, "https://abc12345.live.dynatrace.com", m.Environments["tenant"].URL.Value)
+}
+
+func TestBuildFailsForVariablesFileOutsideOfManifestDirectory(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	manifestPath, _ := filepath.Abs("workdir/manifest.yaml")
+	_ = afero.WriteFile(fs, manifestPath, []byte("manifestVersion: 1.0"), 0644)
+
+	_, err := Build(fs, manifestPath, manifest.Manifest{VariablesFile: "../vars.yaml"}, nil)
+	assert.ErrorContains(t, err, "outside of the directory of the manifest")
+}
+
+func TestOpenFailsWithoutManifest(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	archive, err := writeZip(map[string][]byte{"project/config.yaml": []byte("configs: []")})
+	assert.NoError(t, err)
+	_ = afero.WriteFile(fs, "package.zip", archive, 0644)
+
+	_, err = Open(fs, "package.zip")
+	assert.ErrorContains(t, err, "contains no manifest.yaml")
+}
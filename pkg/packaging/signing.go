@@ -0,0 +1,119 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"strings"
+)
+
+// SignatureExtension is appended to the path of a package to get the path of its signature.
+const SignatureExtension = ".sig"
+
+// ErrInvalidSignature is returned if the signature of a package does not match the package and public key.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// LoadPrivateKey reads a PEM encoded PKCS #8 Ed25519 private key, e.g. generated by
+// 'openssl genpkey -algorithm ed25519 -out signing-key.pem'.
+func LoadPrivateKey(fs afero.Fs, path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(fs, path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %q: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %q is not an Ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+// LoadPublicKey reads a PEM encoded PKIX Ed25519 public key, e.g. extracted from a private key by
+// 'openssl pkey -in signing-key.pem -pubout -out signing-key.pub.pem'.
+func LoadPublicKey(fs afero.Fs, path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(fs, path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %q: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %q is not an Ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+func readPEM(fs afero.Fs, path string, blockType string) ([]byte, error) {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %q: %w", path, err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("key %q is not a PEM encoded %s", path, strings.ToLower(blockType))
+	}
+	return block.Bytes, nil
+}
+
+// Sign returns the base64 encoded signature of the archive.
+func Sign(archive []byte, key ed25519.PrivateKey) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, archive)) + "\n")
+}
+
+// Verify returns ErrInvalidSignature if the signature written by Sign does not match the archive and key.
+func Verify(archive []byte, signature []byte, key ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(key, archive, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// OpenVerified reads the package at archivePath like Open, after verifying that its signature at
+// archivePath+SignatureExtension was created with the private key of the given public key.
+func OpenVerified(fs afero.Fs, archivePath string, key ed25519.PublicKey) (Package, error) {
+	archive, err := afero.ReadFile(fs, archivePath)
+	if err != nil {
+		return Package{}, fmt.Errorf("failed to read package %q: %w", archivePath, err)
+	}
+
+	signaturePath := archivePath + SignatureExtension
+	signature, err := afero.ReadFile(fs, signaturePath)
+	if err != nil {
+		return Package{}, fmt.Errorf("failed to read signature %q of package %q: %w", signaturePath, archivePath, err)
+	}
+
+	if err := Verify(archive, signature, key); err != nil {
+		return Package{}, fmt.Errorf("package %q was not signed by the given key: %w", archivePath, err)
+	}
+	return open(fs, archivePath, archive)
+}
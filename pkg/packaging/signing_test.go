@@ -0,0 +1,85 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packaging
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// writeKeyPair writes a new Ed25519 key pair in PEM format to key.pem and key.pub.pem
+func writeKeyPair(t *testing.T, fs afero.Fs) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	privDer, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	pubDer, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+
+	_ = afero.WriteFile(fs, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer}), 0600)
+	_ = afero.WriteFile(fs, "key.pub.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0644)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	writeKeyPair(t, fs)
+
+	priv, err := LoadPrivateKey(fs, "key.pem")
+	assert.NoError(t, err)
+	pub, err := LoadPublicKey(fs, "key.pub.pem")
+	assert.NoError(t, err)
+
+	archive, err := writeZip(map[string][]byte{ManifestFile: []byte("manifestVersion: 1.0")})
+	assert.NoError(t, err)
+	_ = afero.WriteFile(fs, "package.zip", archive, 0644)
+	_ = afero.WriteFile(fs, "package.zip"+SignatureExtension, Sign(archive, priv), 0644)
+
+	t.Run("signed packages are opened", func(t *testing.T) {
+		p, err := OpenVerified(fs, "package.zip", pub)
+		assert.NoError(t, err)
+		assert.Equal(t, Digest(archive), p.Digest)
+	})
+
+	t.Run("tampered packages are rejected", func(t *testing.T) {
+		assert.ErrorIs(t, Verify(append(archive, 0), Sign(archive, priv), pub), ErrInvalidSignature)
+	})
+
+	t.Run("packages signed by other keys are rejected", func(t *testing.T) {
+		other, _, _ := ed25519.GenerateKey(rand.Reader)
+		_, err := OpenVerified(fs, "package.zip", other)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("unsigned packages are rejected", func(t *testing.T) {
+		_ = afero.WriteFile(fs, "unsigned.zip", archive, 0644)
+		_, err := OpenVerified(fs, "unsigned.zip", pub)
+		assert.ErrorContains(t, err, "failed to read signature")
+	})
+
+	t.Run("public keys are not accepted as private keys", func(t *testing.T) {
+		_, err := LoadPrivateKey(fs, "key.pub.pem")
+		assert.ErrorContains(t, err, "is not a PEM encoded private key")
+	})
+}
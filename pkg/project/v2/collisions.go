@@ -0,0 +1,205 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v2
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	configErrors "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"strings"
+)
+
+// CoordinateCollisionError is returned if two Settings 2.0 configs of different projects are deployed to the same
+// object. The external ID identifying settings objects only contains the schema and config ID, but not the project,
+// hence which config wins would depend on the order of deployment.
+type CoordinateCollisionError struct {
+	Config             coordinate.Coordinate
+	Template           string
+	EnvironmentDetails configErrors.EnvironmentDetails
+	Other              coordinate.Coordinate
+	OtherTemplate      string
+}
+
+func (e CoordinateCollisionError) Coordinates() coordinate.Coordinate {
+	return e.Config
+}
+
+func (e CoordinateCollisionError) LocationDetails() configErrors.EnvironmentDetails {
+	return e.EnvironmentDetails
+}
+
+func (e CoordinateCollisionError) Error() string {
+	hint := "Rename one of them"
+	if e.Config.Project != e.Other.Project {
+		hint += ", or define which project overrides the other using 'overrides' in the manifest"
+	}
+	return fmt.Sprintf("config `%s` (template %q) and config `%s` (template %q) are deployed to the same settings object, "+
+		"as they share schema and config ID. %s", e.Config, e.Template, e.Other, e.OtherTemplate, hint)
+}
+
+// collisionKey identifies the settings object a config is deployed to
+type collisionKey struct {
+	environment string
+	schemaId    string
+	configId    string
+}
+
+// resolveCollisions finds Settings 2.0 configs of different projects which are deployed to the same object. If one of
+// the projects explicitly overrides the other, the overridden config is removed, and all references to it are replaced
+// by references to the overriding config. Otherwise, an error is returned.
+func resolveCollisions(projects []Project, definitions manifest.ProjectDefinitionByProjectID) []error {
+	var errs []error
+	seen := make(map[collisionKey]*config.Config)
+	// overridden holds, per environment, the coordinates of overridden configs and the configs overriding them
+	overridden := make(map[string]map[coordinate.Coordinate]coordinate.Coordinate)
+	override := func(env string, overriddenConfig, by coordinate.Coordinate) {
+		log.Debug("Config %s overrides config %s in environment %q", by, overriddenConfig, env)
+		if overridden[env] == nil {
+			overridden[env] = make(map[coordinate.Coordinate]coordinate.Coordinate)
+		}
+		overridden[env][overriddenConfig] = by
+	}
+
+	for _, p := range projects {
+		for _, configsPerType := range p.Configs {
+			for _, configs := range configsPerType {
+				for i := range configs {
+					c := &configs[i]
+					t, ok := c.Type.(config.SettingsType)
+					if !ok || c.Skip {
+						continue
+					}
+
+					key := collisionKey{environment: c.Environment, schemaId: t.SchemaId, configId: c.SettingsObjectId()}
+					other, found := seen[key]
+					switch {
+					case !found:
+						seen[key] = c
+					case overrides(definitions[c.Coordinate.Project], other.Coordinate.Project):
+						override(c.Environment, other.Coordinate, c.Coordinate)
+						seen[key] = c
+					case overrides(definitions[other.Coordinate.Project], c.Coordinate.Project):
+						override(c.Environment, c.Coordinate, other.Coordinate)
+					default:
+						errs = append(errs, CoordinateCollisionError{
+							Config:             c.Coordinate,
+							Template:           templatePath(c),
+							EnvironmentDetails: configErrors.EnvironmentDetails{Group: c.Group, Environment: c.Environment},
+							Other:              other.Coordinate,
+							OtherTemplate:      templatePath(other),
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if errs == nil {
+		removeOverridden(projects, overridden)
+	}
+	return errs
+}
+
+// removeOverridden removes the given overridden configs of each environment, and re-points all references to them to
+// the configs overriding them.
+func removeOverridden(projects []Project, overridden map[string]map[coordinate.Coordinate]coordinate.Coordinate) {
+	// resolves chains of overrides, e.g. if a config overrides a config which overrides another one
+	winner := func(env string, c coordinate.Coordinate) coordinate.Coordinate {
+		for by, found := overridden[env][c]; found; by, found = overridden[env][c] {
+			c = by
+		}
+		return c
+	}
+
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			if len(overridden[env]) == 0 {
+				continue
+			}
+			for typ, configs := range configsPerType {
+				retained := configs[:0]
+				for _, c := range configs {
+					if _, isOverridden := overridden[env][c.Coordinate]; isOverridden {
+						continue
+					}
+					repointReferences(&c, func(ref coordinate.Coordinate) coordinate.Coordinate { return winner(env, ref) })
+					retained = append(retained, c)
+				}
+				configsPerType[typ] = retained
+			}
+		}
+	}
+}
+
+// repointReferences replaces the coordinates of all references and dependencies of the config using the given function.
+func repointReferences(c *config.Config, repoint func(coordinate.Coordinate) coordinate.Coordinate) {
+	var parameters config.Parameters
+	for name, p := range c.Parameters {
+		ref, ok := p.(*reference.ReferenceParameter)
+		if !ok {
+			continue
+		}
+		if target := repoint(ref.Config); target != ref.Config {
+			if parameters == nil {
+				// parameters may be shared with the configs of other environments, thus they are copied
+				parameters = make(config.Parameters, len(c.Parameters))
+				for n, v := range c.Parameters {
+					parameters[n] = v
+				}
+			}
+			parameters[name] = reference.NewWithCoordinate(target, ref.Property)
+		}
+	}
+	if parameters != nil {
+		c.Parameters = parameters
+	}
+
+	var dependsOn []coordinate.Coordinate
+	for _, d := range c.DependsOn {
+		dependsOn = append(dependsOn, repoint(d))
+	}
+	c.DependsOn = dependsOn
+}
+
+// overrides returns whether the given project definition overrides the given project, either by its name or by the
+// name of its group.
+func overrides(definition manifest.ProjectDefinition, project string) bool {
+	if slices.Contains(definition.Overrides, project) {
+		return true
+	}
+
+	for _, o := range definition.Overrides {
+		if strings.HasPrefix(project, o+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// templatePath returns the path of the template of the given config, which locates the config within its project.
+func templatePath(c *config.Config) string {
+	if t, ok := c.Template.(template.FileBasedTemplate); ok {
+		return t.FilePath()
+	}
+	return c.Template.Id()
+}
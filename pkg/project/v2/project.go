@@ -62,6 +62,17 @@ func (p Project) HasDependencyOn(environment string, project Project) bool {
 	return false
 }
 
+// ForEveryConfigDo executes the given action for each config of every environment and type of the project.
+func (p Project) ForEveryConfigDo(action func(c config.Config)) {
+	for _, configsPerType := range p.Configs {
+		for _, configs := range configsPerType {
+			for _, c := range configs {
+				action(c)
+			}
+		}
+	}
+}
+
 func (p Project) String() string {
 	if p.GroupId != "" {
 		return fmt.Sprintf("%s [group: %s]", p.Id, p.GroupId)
@@ -69,3 +80,27 @@ func (p Project) String() string {
 
 	return p.Id
 }
+
+// RemoteObjectsPerEnvironment collects, per environment, the remote objects of all configs of the given projects
+// matching the filter.
+func RemoteObjectsPerEnvironment(projects []Project, filter func(c config.Config) bool) map[string]config.RemoteObjects {
+	configsPerEnvironment := make(map[string][]config.Config)
+
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			for _, configs := range configsPerType {
+				for _, c := range configs {
+					if filter(c) {
+						configsPerEnvironment[env] = append(configsPerEnvironment[env], c)
+					}
+				}
+			}
+		}
+	}
+
+	result := make(map[string]config.RemoteObjects, len(configsPerEnvironment))
+	for env, configs := range configsPerEnvironment {
+		result[env] = config.NewRemoteObjects(configs)
+	}
+	return result
+}
@@ -34,6 +34,9 @@ type ProjectLoaderContext struct {
 	WorkingDir      string
 	Manifest        manifest.Manifest
 	ParametersSerde map[string]parameter.ParameterSerDe
+	// Strict states that unknown keys in config files fail loading, even if they are part of leniently decoded sections.
+	// See [config.LoaderContext] for details.
+	Strict bool
 }
 
 type DuplicateConfigIdentifierError struct {
@@ -53,6 +56,25 @@ func (e DuplicateConfigIdentifierError) Error() string {
 	return fmt.Sprintf("Config IDs need to be unique to project/type, found duplicate `%s`", e.Config)
 }
 
+// UnknownDependencyError is returned if a config explicitly depends on a config which does not exist.
+type UnknownDependencyError struct {
+	Config             coordinate.Coordinate
+	EnvironmentDetails configErrors.EnvironmentDetails
+	DependsOn          coordinate.Coordinate
+}
+
+func (e UnknownDependencyError) Coordinates() coordinate.Coordinate {
+	return e.Config
+}
+
+func (e UnknownDependencyError) LocationDetails() configErrors.EnvironmentDetails {
+	return e.EnvironmentDetails
+}
+
+func (e UnknownDependencyError) Error() string {
+	return fmt.Sprintf("config `%s` defined in `dependsOn` does not exist", e.DependsOn)
+}
+
 func newDuplicateConfigIdentifierError(c config.Config) DuplicateConfigIdentifierError {
 	return DuplicateConfigIdentifierError{
 		Config: c.Coordinate,
@@ -64,6 +86,29 @@ func newDuplicateConfigIdentifierError(c config.Config) DuplicateConfigIdentifie
 }
 
 func LoadProjects(fs afero.Fs, context ProjectLoaderContext) ([]Project, []error) {
+	projects, errors := LoadProjectsPartially(fs, context)
+	if errors != nil {
+		return nil, errors
+	}
+
+	addExtensionDependencies(projects)
+
+	if errs := validateExplicitDependencies(projects); errs != nil {
+		return nil, errs
+	}
+
+	if errs := resolveCollisions(projects, context.Manifest.Projects); errs != nil {
+		return nil, errs
+	}
+
+	return projects, nil
+}
+
+// LoadProjectsPartially loads all projects of the manifest, skipping the ones which fail to load. It returns the
+// projects loaded, as well as the errors of the skipped ones. As not all projects may be loaded, dependencies and
+// collisions between projects are not resolved. Use it where a subset of the projects is better than none, e.g. to
+// know which objects to leave untouched.
+func LoadProjectsPartially(fs afero.Fs, context ProjectLoaderContext) ([]Project, []error) {
 	environments := toEnvironmentSlice(context.Manifest.Environments)
 	projects := make([]Project, 0)
 
@@ -88,11 +133,96 @@ func LoadProjects(fs afero.Fs, context ProjectLoaderContext) ([]Project, []error
 		projects = append(projects, project)
 	}
 
-	if errors != nil {
-		return nil, errors
+	return projects, errors
+}
+
+// validateExplicitDependencies checks that all configs defined in 'dependsOn' exist in the same environment.
+// In contrast to references, explicit dependencies are not resolved during deployment, thus a typo would not be noticed.
+func validateExplicitDependencies(projects []Project) []error {
+	known := make(map[string]map[coordinate.Coordinate]struct{})
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			if _, f := known[c.Environment]; !f {
+				known[c.Environment] = make(map[coordinate.Coordinate]struct{})
+			}
+			known[c.Environment][c.Coordinate] = struct{}{}
+		})
 	}
 
-	return projects, nil
+	var errs []error
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			for _, d := range c.DependsOn {
+				if _, f := known[c.Environment][d]; !f {
+					errs = append(errs, UnknownDependencyError{
+						Config:             c.Coordinate,
+						EnvironmentDetails: configErrors.EnvironmentDetails{Group: c.Group, Environment: c.Environment},
+						DependsOn:          d,
+					})
+				}
+			}
+		})
+	}
+	return errs
+}
+
+// addExtensionDependencies makes all configs requiring an Extensions 2.0 extension depend on the config of the
+// extension in the same environment, so that the extension is activated before they are deployed. These are its
+// monitoring configurations, as well as settings of the schema the extension defines, which is named after it.
+func addExtensionDependencies(projects []Project) {
+	extensions := make(map[string]map[string]config.Config)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			if t, ok := c.Type.(config.ExtensionType); ok {
+				if _, f := extensions[c.Environment]; !f {
+					extensions[c.Environment] = make(map[string]config.Config)
+				}
+				extensions[c.Environment][t.Name] = c
+			}
+		})
+	}
+
+	if len(extensions) == 0 {
+		return
+	}
+
+	for _, p := range projects {
+		for env, configsPerType := range p.Configs {
+			for _, configs := range configsPerType {
+				for i := range configs {
+					var extensionName string
+					switch t := configs[i].Type.(type) {
+					case config.ExtensionMonitoringType:
+						extensionName = t.Extension
+					case config.SettingsType:
+						extensionName = t.SchemaId
+					default:
+						continue
+					}
+
+					extension, found := extensions[env][extensionName]
+					if !found || containsCoordinate(configs[i].DependsOn, extension.Coordinate) {
+						continue
+					}
+
+					// the dependsOn slice is shared between the environments of a config, thus needs to be copied
+					configs[i].DependsOn = append(append([]coordinate.Coordinate{}, configs[i].DependsOn...), extension.Coordinate)
+					if extension.Coordinate.Project != p.Id && !configs[i].Skip && !containsProject(p.Dependencies[env], extension.Coordinate.Project) {
+						p.Dependencies[env] = append(p.Dependencies[env], extension.Coordinate.Project)
+					}
+				}
+			}
+		}
+	}
+}
+
+func containsCoordinate(coordinates []coordinate.Coordinate, c coordinate.Coordinate) bool {
+	for _, other := range coordinates {
+		if other == c {
+			return true
+		}
+	}
+	return false
 }
 
 func toEnvironmentSlice(environments map[string]manifest.EnvironmentDefinition) []manifest.EnvironmentDefinition {
@@ -118,6 +248,12 @@ func loadProject(fs afero.Fs, context ProjectLoaderContext, projectDefinition ma
 
 	log.Debug("Loading project `%s` (%s)...", projectDefinition.Name, projectDefinition.Path)
 
+	for name := range projectDefinition.Variables {
+		if slices.Contains(config.ReservedParameterNames, name) {
+			return Project{}, []error{fmt.Errorf("failed to load project `%s`: variable name `%s` is not allowed (reserved)", projectDefinition.Name, name)}
+		}
+	}
+
 	configs, errors := loadConfigsOfProject(fs, context, projectDefinition, environments)
 
 	if d := findDuplicatedConfigIdentifiers(configs); d != nil {
@@ -173,6 +309,8 @@ func loadConfigsOfProject(fs afero.Fs, context ProjectLoaderContext, projectDefi
 			Environments:    environments,
 			KnownApis:       context.KnownApis,
 			ParametersSerDe: context.ParametersSerde,
+			Variables:       projectDefinition.Variables,
+			Strict:          context.Strict,
 		})
 
 		if errs != nil {
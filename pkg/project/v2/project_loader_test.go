@@ -17,14 +17,17 @@
 package v2
 
 import (
+	"errors"
 	"fmt"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/errutils"
 	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
 	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
 	"github.com/spf13/afero"
 	"reflect"
+	"strings"
 	"testing"
 
 	"gotest.tools/assert"
@@ -513,6 +516,112 @@ func TestLoadProjects_ReturnsErrOnOverlappingCoordinate_InSameFile(t *testing.T)
 	assert.Equal(t, len(gotErrs), 1, "Expected to fail on overlapping coordinates")
 }
 
+func TestLoadProjects_ExplicitDependencies(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: Test Profile\n    template: profile.json\n  type:\n    api: alerting-profile\n  dependsOn:\n  - [other-project, dashboard, board]"), 0644)
+	_ = afero.WriteFile(testFs, "project/alerting-profile/profile.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "other-project/dashboard/board.yaml", []byte("configs:\n- id: board\n  config:\n    name: Test Dashboard\n    template: board.json\n  type:\n    api: dashboard"), 0644)
+	_ = afero.WriteFile(testFs, "other-project/dashboard/board.json", []byte("{}"), 0644)
+
+	t.Run("dependencies are added to project dependencies", func(t *testing.T) {
+		got, gotErrs := LoadProjects(testFs, getSimpleProjectLoaderContext([]string{"project", "other-project"}))
+
+		assert.Equal(t, len(gotErrs), 0, "Expected to load projects without error")
+		for _, p := range got {
+			if p.Id == "project" {
+				assert.DeepEqual(t, p.Dependencies["env"], []string{"other-project"})
+			}
+		}
+	})
+
+	t.Run("unknown dependencies are reported", func(t *testing.T) {
+		_, gotErrs := LoadProjects(testFs, getSimpleProjectLoaderContext([]string{"project"}))
+
+		assert.Equal(t, len(gotErrs), 1)
+		var depErr UnknownDependencyError
+		assert.Assert(t, errors.As(gotErrs[0], &depErr))
+		assert.Equal(t, depErr.DependsOn, coordinate.Coordinate{Project: "other-project", Type: "dashboard", ConfigId: "board"})
+	})
+}
+
+func TestLoadProjects_ExtensionDependencies(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "extensions/extension/extension.yaml", []byte("configs:\n- id: postgres\n  config:\n    name: com.dynatrace.extension.postgres\n    template: extension.json\n  type:\n    extension:\n      name: com.dynatrace.extension.postgres"), 0644)
+	_ = afero.WriteFile(testFs, "extensions/extension/extension.json", []byte(`{"version": "1.0.0"}`), 0644)
+	_ = afero.WriteFile(testFs, "project/monitoring/monitoring.yaml", []byte("configs:\n- id: db\n  config:\n    name: db\n    template: monitoring.json\n  type:\n    extensionMonitoring:\n      extension: com.dynatrace.extension.postgres\n      scope: ag_group-default"), 0644)
+	_ = afero.WriteFile(testFs, "project/monitoring/monitoring.json", []byte("{}"), 0644)
+
+	got, gotErrs := LoadProjects(testFs, getSimpleProjectLoaderContext([]string{"extensions", "project"}))
+	assert.Equal(t, len(gotErrs), 0, "Expected to load projects without error")
+
+	extension := coordinate.Coordinate{Project: "extensions", Type: config.ExtensionCoordinateType, ConfigId: "postgres"}
+	for _, p := range got {
+		if p.Id == "project" {
+			assert.DeepEqual(t, p.Dependencies["env"], []string{"extensions"})
+			assert.DeepEqual(t, p.Configs["env"][config.ExtensionMonitoringCoordinateType][0].DependsOn, []coordinate.Coordinate{extension})
+		}
+	}
+}
+
+func TestLoadProjects_InstantiatesProjectWithVariables(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "templates/monitoring/dashboard/board.yaml", []byte("configs:\n- id: board\n  config:\n    name:\n      type: compound\n      format: \"{{ .application }} overview\"\n      references: [application]\n    template: board.json\n  type:\n    api: dashboard"), 0644)
+	_ = afero.WriteFile(testFs, "templates/monitoring/dashboard/board.json", []byte(`{"owner": "{{ .owner }}"}`), 0644)
+
+	context := getSimpleProjectLoaderContext(nil)
+	context.Manifest.Projects = manifest.ProjectDefinitionByProjectID{
+		"payment":  {Name: "payment", Path: "templates/monitoring", Variables: map[string]string{"application": "payment", "owner": "team-a"}},
+		"checkout": {Name: "checkout", Path: "templates/monitoring", Variables: map[string]string{"application": "checkout", "owner": "team-b"}},
+	}
+
+	t.Run("each instantiation is loaded as distinct project", func(t *testing.T) {
+		got, gotErrs := LoadProjects(testFs, context)
+		assert.Equal(t, len(gotErrs), 0, "Expected to load projects without error: %v", gotErrs)
+		assert.Equal(t, len(got), 2)
+
+		for _, p := range got {
+			c := p.Configs["env"]["dashboard"][0]
+			assert.Equal(t, c.Coordinate, coordinate.Coordinate{Project: p.Id, Type: "dashboard", ConfigId: "board"})
+			assert.DeepEqual(t, c.Parameters["application"], &value.ValueParameter{Value: p.Id})
+			assert.DeepEqual(t, c.Variables, []string{"application", "owner"})
+		}
+	})
+
+	t.Run("reserved variable names are reported", func(t *testing.T) {
+		context.Manifest.Projects = manifest.ProjectDefinitionByProjectID{
+			"payment": {Name: "payment", Path: "templates/monitoring", Variables: map[string]string{"name": "payment"}},
+		}
+
+		_, gotErrs := LoadProjects(testFs, context)
+		assert.Equal(t, len(gotErrs), 1)
+		assert.ErrorContains(t, gotErrs[0], "variable name `name` is not allowed (reserved)")
+	})
+}
+
+func TestLoadProjects_InstantiatedSettingsAreDistinctObjects(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "templates/monitoring/tags/tag.yaml", []byte("configs:\n- id: tag\n  config:\n    name: \"{{ .application }}\"\n    template: tag.json\n  type:\n    settings:\n      schema: builtin:tags.auto-tagging\n      scope: environment"), 0644)
+	_ = afero.WriteFile(testFs, "templates/monitoring/tags/tag.json", []byte(`{"name": "{{ .name }}"}`), 0644)
+
+	context := getSimpleProjectLoaderContext(nil)
+	context.Manifest.Projects = manifest.ProjectDefinitionByProjectID{
+		"payment":  {Name: "payment", Path: "templates/monitoring", Variables: map[string]string{"application": "payment"}},
+		"checkout": {Name: "checkout", Path: "templates/monitoring", Variables: map[string]string{"application": "checkout"}},
+	}
+
+	got, gotErrs := LoadProjects(testFs, context)
+	assert.Equal(t, len(gotErrs), 0, "instantiations must not collide: %v", gotErrs)
+	assert.Equal(t, len(got), 2)
+
+	ids := map[string]bool{}
+	for _, p := range got {
+		c := p.Configs["env"]["builtin:tags.auto-tagging"][0]
+		assert.Equal(t, c.SettingsObjectId(), p.Id+":tag")
+		ids[c.SettingsObjectId()] = true
+	}
+	assert.Equal(t, len(ids), 2, "each instantiation must deploy its own settings object")
+}
+
 func Test_loadProject_returnsErrorIfProjectPathDoesNotExist(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	ctx := ProjectLoaderContext{}
@@ -569,3 +678,93 @@ func getFullProjectLoaderContext(apis []string, projects []string, environments
 		ParametersSerde: config.DefaultParameterParsers,
 	}
 }
+
+func TestLoadProjects_SettingsCollisions(t *testing.T) {
+	newTestFs := func() afero.Fs {
+		fs := afero.NewMemMapFs()
+		for _, p := range []string{"base", "team"} {
+			_ = afero.WriteFile(fs, p+"/profiles/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: profile\n    template: profile.json\n  type:\n    settings:\n      schema: builtin:alerting.profile\n      scope: environment"), 0644)
+			_ = afero.WriteFile(fs, p+"/profiles/profile.json", []byte("{}"), 0644)
+		}
+		return fs
+	}
+	testFs := newTestFs()
+
+	t.Run("collision is reported with both templates", func(t *testing.T) {
+		_, gotErrs := LoadProjects(testFs, getSimpleProjectLoaderContext([]string{"base", "team"}))
+		assert.Equal(t, len(gotErrs), 1)
+
+		var collisionErr CoordinateCollisionError
+		assert.Assert(t, errors.As(gotErrs[0], &collisionErr))
+		assert.ErrorContains(t, gotErrs[0], "base/profiles/profile.json")
+		assert.ErrorContains(t, gotErrs[0], "team/profiles/profile.json")
+	})
+
+	t.Run("overriding project replaces the config of the overridden one", func(t *testing.T) {
+		context := getSimpleProjectLoaderContext(nil)
+		context.Manifest.Projects = manifest.ProjectDefinitionByProjectID{
+			"base": {Name: "base", Path: "base"},
+			"team": {Name: "team", Path: "team", Overrides: []string{"base"}},
+		}
+
+		got, gotErrs := LoadProjects(testFs, context)
+		assert.Equal(t, len(gotErrs), 0, "Expected to load projects without error: %v", gotErrs)
+
+		for _, p := range got {
+			assert.Equal(t, len(p.Configs["env"]["builtin:alerting.profile"]), map[string]int{"base": 0, "team": 1}[p.Id])
+		}
+	})
+
+	t.Run("references to the overridden config are re-pointed to the overriding one", func(t *testing.T) {
+		fs := newTestFs()
+		_ = afero.WriteFile(fs, "base/notifications/notification.yaml", []byte("configs:\n- id: notification\n  config:\n    name: notification\n    template: notification.json\n    parameters:\n      profile:\n        type: reference\n        configType: builtin:alerting.profile\n        configId: profile\n        property: id\n  type:\n    settings:\n      schema: builtin:problem.notifications\n      scope: environment"), 0644)
+		_ = afero.WriteFile(fs, "base/notifications/notification.json", []byte(`{"profile": "{{.profile}}"}`), 0644)
+
+		context := getSimpleProjectLoaderContext(nil)
+		context.Manifest.Projects = manifest.ProjectDefinitionByProjectID{
+			"base": {Name: "base", Path: "base"},
+			"team": {Name: "team", Path: "team", Overrides: []string{"base"}},
+		}
+
+		got, gotErrs := LoadProjects(fs, context)
+		assert.Equal(t, len(gotErrs), 0, "Expected to load projects without error: %v", gotErrs)
+
+		for _, p := range got {
+			if p.Id != "base" {
+				continue
+			}
+			notification := p.Configs["env"]["builtin:problem.notifications"][0]
+			ref, ok := notification.Parameters["profile"].(*reference.ReferenceParameter)
+			assert.Assert(t, ok)
+			want := coordinate.Coordinate{Project: "team", Type: "builtin:alerting.profile", ConfigId: "profile"}
+			assert.Equal(t, ref.Config, want)
+			assert.DeepEqual(t, notification.References(), []coordinate.Coordinate{want})
+		}
+	})
+}
+
+func TestCoordinateCollisionError_Error(t *testing.T) {
+	base := coordinate.Coordinate{Project: "base", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	team := coordinate.Coordinate{Project: "team", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	moved := coordinate.Coordinate{Project: "base", Type: "builtin:alerting.profile", ConfigId: "moved"}
+
+	assert.ErrorContains(t, CoordinateCollisionError{Config: team, Other: base}, "'overrides'")
+	assert.Assert(t, !strings.Contains(CoordinateCollisionError{Config: moved, Other: base}.Error(), "'overrides'"))
+}
+
+func TestLoadProjectsPartially(t *testing.T) {
+	testFs := afero.NewMemMapFs()
+	_ = afero.WriteFile(testFs, "valid/alerting-profile/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: profile\n    template: profile.json\n  type:\n    api: alerting-profile"), 0644)
+	_ = afero.WriteFile(testFs, "valid/alerting-profile/profile.json", []byte("{}"), 0644)
+	_ = afero.WriteFile(testFs, "broken/alerting-profile/profile.yaml", []byte("configs:\n- id: profile\n  config:\n    name: profile\n    template: missing.json\n  type:\n    api: alerting-profile"), 0644)
+
+	context := getSimpleProjectLoaderContext([]string{"valid", "broken"})
+
+	_, gotErrs := LoadProjects(testFs, context)
+	assert.Assert(t, len(gotErrs) > 0)
+
+	got, gotErrs := LoadProjectsPartially(testFs, context)
+	assert.Assert(t, len(gotErrs) > 0)
+	assert.Equal(t, len(got), 1)
+	assert.Equal(t, got[0].Id, "valid")
+}
@@ -0,0 +1,183 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package topologysort
+
+import (
+	"container/heap"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+)
+
+// dependencyGraph holds the dependencies between a list of configs by their index in the list.
+type dependencyGraph struct {
+	// dependencies holds for each config the indices of all configs it depends on
+	dependencies [][]int
+	// dependents holds for each config the indices of all configs depending on it
+	dependents [][]int
+}
+
+// newDependencyGraph builds the dependency graph of the given configs. As during sorting, dependencies of skipped
+// configs are ignored, as are references to configs not contained in the list.
+func newDependencyGraph(configs []config.Config) dependencyGraph {
+	indices := make(map[coordinate.Coordinate]int, len(configs))
+	for i, c := range configs {
+		indices[c.Coordinate] = i
+	}
+
+	g := dependencyGraph{
+		dependencies: make([][]int, len(configs)),
+		dependents:   make([][]int, len(configs)),
+	}
+
+	for i := range configs {
+		if configs[i].Skip {
+			continue
+		}
+
+		seen := make(map[int]struct{})
+		for _, ref := range configs[i].References() {
+			j, found := indices[ref]
+			if !found || i == j {
+				continue
+			}
+			if _, dup := seen[j]; dup {
+				continue
+			}
+			seen[j] = struct{}{}
+
+			g.dependencies[i] = append(g.dependencies[i], j)
+			g.dependents[j] = append(g.dependents[j], i)
+		}
+	}
+
+	return g
+}
+
+// sortByPriority re-orders the given topologically sorted configs, so that configs with a higher [config.Config.Priority]
+// are deployed as early as their dependencies allow. Configs of the same priority keep their relative order.
+// If no config defines a priority, the configs are returned as they are.
+func sortByPriority(sortedConfigs []config.Config) []config.Config {
+	if !anyPrioritized(sortedConfigs) {
+		return sortedConfigs
+	}
+
+	g := newDependencyGraph(sortedConfigs)
+
+	remaining := make([]int, len(sortedConfigs))
+	ready := &priorityQueue{configs: sortedConfigs}
+	for i := range sortedConfigs {
+		remaining[i] = len(g.dependencies[i])
+		if remaining[i] == 0 {
+			ready.indices = append(ready.indices, i)
+		}
+	}
+	heap.Init(ready)
+
+	result := make([]config.Config, 0, len(sortedConfigs))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		result = append(result, sortedConfigs[i])
+
+		for _, d := range g.dependents[i] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				heap.Push(ready, d)
+			}
+		}
+	}
+
+	return result
+}
+
+func anyPrioritized(configs []config.Config) bool {
+	for _, c := range configs {
+		if c.Priority != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// priorityQueue orders config indices by descending priority and ascending index.
+type priorityQueue struct {
+	configs []config.Config
+	indices []int
+}
+
+func (q *priorityQueue) Len() int {
+	return len(q.indices)
+}
+
+func (q *priorityQueue) Less(i, j int) bool {
+	a, b := q.indices[i], q.indices[j]
+	if q.configs[a].Priority != q.configs[b].Priority {
+		return q.configs[a].Priority > q.configs[b].Priority
+	}
+	return a < b
+}
+
+func (q *priorityQueue) Swap(i, j int) {
+	q.indices[i], q.indices[j] = q.indices[j], q.indices[i]
+}
+
+func (q *priorityQueue) Push(x any) {
+	q.indices = append(q.indices, x.(int))
+}
+
+func (q *priorityQueue) Pop() any {
+	last := q.indices[len(q.indices)-1]
+	q.indices = q.indices[:len(q.indices)-1]
+	return last
+}
+
+// GetCriticalPath returns the longest chain of dependencies within the given topologically sorted configs, starting
+// with the config deployed first. As every config of the chain needs to wait for the previous one to be deployed,
+// the chain limits how fast the configs can be deployed in parallel.
+// If several chains are of the same length, the one ending first in the given configs is returned.
+func GetCriticalPath(sortedConfigs []config.Config) []coordinate.Coordinate {
+	if len(sortedConfigs) == 0 {
+		return nil
+	}
+
+	g := newDependencyGraph(sortedConfigs)
+
+	length := make([]int, len(sortedConfigs))
+	previous := make([]int, len(sortedConfigs))
+	end := 0
+
+	for i := range sortedConfigs {
+		length[i] = 1
+		previous[i] = -1
+
+		for _, d := range g.dependencies[i] {
+			if length[d]+1 > length[i] {
+				length[i] = length[d] + 1
+				previous[i] = d
+			}
+		}
+
+		if length[i] > length[end] {
+			end = i
+		}
+	}
+
+	path := make([]coordinate.Coordinate, length[end])
+	for i, pos := end, length[end]-1; i >= 0; i, pos = previous[i], pos-1 {
+		path[pos] = sortedConfigs[i].Coordinate
+	}
+	return path
+}
@@ -0,0 +1,84 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package topologysort
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"gotest.tools/assert"
+	"testing"
+)
+
+func coordinates(configs []config.Config) []coordinate.Coordinate {
+	result := make([]coordinate.Coordinate, len(configs))
+	for i, c := range configs {
+		result[i] = c.Coordinate
+	}
+	return result
+}
+
+func TestSortConfigs_Priority(t *testing.T) {
+	a := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}
+	b := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "b"}
+	c := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "c"}
+	d := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "d"}
+
+	t.Run("without priorities the order is unchanged", func(t *testing.T) {
+		configs := []config.Config{{Coordinate: a}, {Coordinate: b}, {Coordinate: c}}
+		assert.DeepEqual(t, coordinates(sortByPriority(configs)), []coordinate.Coordinate{a, b, c})
+	})
+
+	t.Run("higher priorities are deployed first", func(t *testing.T) {
+		configs := []config.Config{{Coordinate: a}, {Coordinate: b, Priority: -1}, {Coordinate: c, Priority: 10}}
+		assert.DeepEqual(t, coordinates(sortByPriority(configs)), []coordinate.Coordinate{c, a, b})
+	})
+
+	t.Run("dependencies are respected", func(t *testing.T) {
+		sorted, errs := sortConfigs([]config.Config{
+			{Coordinate: a},
+			{Coordinate: b},
+			{Coordinate: c},
+			{Coordinate: d, Priority: 10, DependsOn: []coordinate.Coordinate{c}},
+		})
+
+		assert.Equal(t, len(errs), 0)
+		got := coordinates(sorted)
+		assert.Equal(t, got[0], c)
+		assert.Equal(t, got[1], d)
+	})
+}
+
+func TestGetCriticalPath(t *testing.T) {
+	a := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "a"}
+	b := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "b"}
+	c := coordinate.Coordinate{Project: "p", Type: "t", ConfigId: "c"}
+	d := coordinate.Coordinate{Project: "q", Type: "t", ConfigId: "d"}
+
+	assert.Assert(t, GetCriticalPath(nil) == nil)
+
+	sorted := []config.Config{
+		{Coordinate: a},
+		{Coordinate: b},
+		{Coordinate: c, DependsOn: []coordinate.Coordinate{a}},
+		{Coordinate: d, DependsOn: []coordinate.Coordinate{c, b}},
+	}
+	assert.DeepEqual(t, GetCriticalPath(sorted), []coordinate.Coordinate{a, c, d})
+
+	assert.DeepEqual(t, GetCriticalPath([]config.Config{{Coordinate: a}, {Coordinate: b}}), []coordinate.Coordinate{a})
+}
@@ -90,11 +90,18 @@ type CircularDependencyConfigSortError struct {
 	Config      coordinate.Coordinate
 	Environment string
 	DependsOn   []coordinate.Coordinate
+	// ExplicitDependsOn holds the subset of DependsOn which are defined manually via 'dependsOn' instead of by references
+	ExplicitDependsOn []coordinate.Coordinate
 }
 
 func (e CircularDependencyConfigSortError) Error() string {
-	return fmt.Sprintf("%s:%s: is part of circular dependency.\n depends on: %s",
+	msg := fmt.Sprintf("%s:%s: is part of circular dependency.\n depends on: %s",
 		e.Environment, e.Config, joinCoordinatesToString(e.DependsOn))
+
+	if len(e.ExplicitDependsOn) > 0 {
+		msg += fmt.Sprintf("\n of which are defined via 'dependsOn': %s", joinCoordinatesToString(e.ExplicitDependsOn))
+	}
+	return msg
 }
 
 func joinCoordinatesToString(coordinates []coordinate.Coordinate) string {
@@ -264,7 +271,7 @@ func sortConfigs(configs []config.Config) ([]config.Config, []error) {
 		result = append(result, configs[sorted[i]])
 	}
 
-	return result, nil
+	return sortByPriority(result), nil
 }
 
 // referencesLookup is a double lookup map to check dependencies between configs using their coordinates
@@ -336,16 +343,19 @@ func parseConfigSortErrors(sortErrs []sort.TopologySortError, configs []config.C
 		for _, index := range sortErr.UnresolvedIncomingEdgesFrom {
 			dependingConfig := configs[index]
 
-			if err, exists := depErrs[dependingConfig.Coordinate]; exists {
-				err.DependsOn = append(err.DependsOn, conf.Coordinate)
-				depErrs[dependingConfig.Coordinate] = err
-			} else {
-				depErrs[dependingConfig.Coordinate] = CircularDependencyConfigSortError{
+			err, exists := depErrs[dependingConfig.Coordinate]
+			if !exists {
+				err = CircularDependencyConfigSortError{
 					Config:      dependingConfig.Coordinate,
 					Environment: dependingConfig.Environment,
-					DependsOn:   []coordinate.Coordinate{conf.Coordinate},
 				}
 			}
+
+			err.DependsOn = append(err.DependsOn, conf.Coordinate)
+			if containsCoordinate(dependingConfig.DependsOn, conf.Coordinate) {
+				err.ExplicitDependsOn = append(err.ExplicitDependsOn, conf.Coordinate)
+			}
+			depErrs[dependingConfig.Coordinate] = err
 		}
 	}
 
@@ -359,6 +369,15 @@ func parseConfigSortErrors(sortErrs []sort.TopologySortError, configs []config.C
 	return errs
 }
 
+func containsCoordinate(coordinates []coordinate.Coordinate, c coordinate.Coordinate) bool {
+	for _, cc := range coordinates {
+		if cc == c {
+			return true
+		}
+	}
+	return false
+}
+
 func sortProjects(projects []project.Project, environments []string) (ProjectsPerEnvironment, []error) {
 	var errs []error
 
@@ -663,3 +663,32 @@ func Test_parseConfigSortErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestSortConfigs_ExplicitDependencies(t *testing.T) {
+	c1 := coordinate.Coordinate{Project: "p1", Type: "extension", ConfigId: "c1"}
+	c2 := coordinate.Coordinate{Project: "p1", Type: "builtin:schema", ConfigId: "c2"}
+
+	t.Run("dependsOn is respected", func(t *testing.T) {
+		sorted, errs := sortConfigs([]config.Config{
+			{Coordinate: c2, DependsOn: []coordinate.Coordinate{c1}},
+			{Coordinate: c1},
+		})
+
+		assert.Assert(t, len(errs) == 0, "expected no errors, got: %v", errs)
+		assert.Equal(t, len(sorted), 2)
+		assert.Equal(t, sorted[0].Coordinate, c1)
+		assert.Equal(t, sorted[1].Coordinate, c2)
+	})
+
+	t.Run("cycles mention explicit dependencies", func(t *testing.T) {
+		_, errs := sortConfigs([]config.Config{
+			{Coordinate: c2, DependsOn: []coordinate.Coordinate{c1}},
+			{Coordinate: c1, DependsOn: []coordinate.Coordinate{c2}},
+		})
+
+		assert.Equal(t, len(errs), 2)
+		for _, err := range errs {
+			assert.ErrorContains(t, err, "defined via 'dependsOn'")
+		}
+	})
+}
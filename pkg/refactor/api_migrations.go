@@ -0,0 +1,254 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// apiMigration converts the templates of configs of a deprecated config API to the payload of the API or settings
+// schema replacing it.
+type apiMigration struct {
+	// scope is the scope of the settings objects the configs are migrated to. It defaults to 'environment'.
+	scope string
+	// migrate converts a template of the deprecated API. Templates are passed as parsed JSON, so placeholders within
+	// strings are kept.
+	migrate func(payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// apiMigrations are the migrations supported by MigrateAPI, per deprecated API and target.
+var apiMigrations = map[string]map[string]apiMigration{
+	"alerting-profile": {
+		"builtin:alerting.profile": {migrate: migrateAlertingProfile},
+	},
+	"maintenance-window": {
+		"builtin:alerting.maintenance-window": {migrate: migrateMaintenanceWindow},
+	},
+}
+
+// SupportedAPIMigrations returns the supported migrations in the form '<API> -> <target>', sorted.
+func SupportedAPIMigrations() []string {
+	var result []string
+	for from, targets := range apiMigrations {
+		for to := range targets {
+			result = append(result, fmt.Sprintf("%s -> %s", from, to))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// migrateAlertingProfile converts an alerting profile of the config API to a 'builtin:alerting.profile' settings object.
+func migrateAlertingProfile(p map[string]interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{
+		"name":           p["displayName"],
+		"severityRules":  []interface{}{},
+		"eventFilters":   []interface{}{},
+		"managementZone": p["mzId"],
+	}
+
+	for _, r := range items(p["rules"]) {
+		rule := object(r)
+		tagFilter := object(rule["tagFilter"])
+
+		var tags []interface{}
+		for _, t := range items(tagFilter["tagFilters"]) {
+			tags = append(tags, tagString(object(t)))
+		}
+		if tags == nil {
+			tags = []interface{}{}
+		}
+
+		result["severityRules"] = append(result["severityRules"].([]interface{}), map[string]interface{}{
+			"severityLevel":        rule["severityLevel"],
+			"delayInMinutes":       rule["delayInMinutes"],
+			"tagFilterIncludeMode": tagFilter["includeMode"],
+			"tagFilter":            tags,
+		})
+	}
+
+	for _, f := range items(p["eventTypeFilters"]) {
+		filter := object(f)
+		switch {
+		case filter["predefinedEventFilter"] != nil:
+			result["eventFilters"] = append(result["eventFilters"].([]interface{}), map[string]interface{}{
+				"type":             "PREDEFINED",
+				"predefinedFilter": filter["predefinedEventFilter"],
+			})
+		case filter["customEventFilter"] != nil:
+			custom := object(filter["customEventFilter"])
+			result["eventFilters"] = append(result["eventFilters"].([]interface{}), map[string]interface{}{
+				"type": "CUSTOM",
+				"customFilter": map[string]interface{}{
+					"titleFilter":       textFilter(object(custom["customTitleFilter"])),
+					"descriptionFilter": textFilter(object(custom["customDescriptionFilter"])),
+				},
+			})
+		default:
+			return nil, fmt.Errorf("unknown event type filter %v", f)
+		}
+	}
+
+	return result, nil
+}
+
+// tagString converts a tag given as object of context, key and value to the string form used by settings, e.g.
+// '[AWS]key:value'.
+func tagString(tag map[string]interface{}) string {
+	s := fmt.Sprint(tag["key"])
+	if v, ok := tag["value"]; ok && v != nil && v != "" {
+		s += ":" + fmt.Sprint(v)
+	}
+	if c, ok := tag["context"]; ok && c != nil && c != "CONTEXTLESS" {
+		s = fmt.Sprintf("[%v]%s", c, s)
+	}
+	return s
+}
+
+func textFilter(f map[string]interface{}) map[string]interface{} {
+	if f == nil {
+		return map[string]interface{}{"enabled": false}
+	}
+	caseInsensitive, _ := f["caseInsensitive"].(bool)
+	return map[string]interface{}{
+		"enabled":       f["enabled"],
+		"operator":      f["operator"],
+		"value":         f["value"],
+		"negate":        f["negate"],
+		"caseSensitive": !caseInsensitive,
+	}
+}
+
+// migrateMaintenanceWindow converts a maintenance window of the config API to a 'builtin:alerting.maintenance-window'
+// settings object.
+func migrateMaintenanceWindow(p map[string]interface{}) (map[string]interface{}, error) {
+	schedule, err := maintenanceSchedule(object(p["schedule"]))
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []interface{}{}
+	scope := object(p["scope"])
+	for _, e := range items(scope["entities"]) {
+		filters = append(filters, map[string]interface{}{"entityId": e, "entityTags": []interface{}{}, "managementZones": []interface{}{}})
+	}
+	for _, m := range items(scope["matches"]) {
+		match := object(m)
+		filter := map[string]interface{}{"entityTags": []interface{}{}, "managementZones": []interface{}{}}
+		if t, ok := match["type"]; ok && t != nil {
+			filter["entityType"] = t
+		}
+		if mz, ok := match["mzId"]; ok && mz != nil {
+			filter["managementZones"] = []interface{}{mz}
+		}
+		for _, t := range items(match["tags"]) {
+			filter["entityTags"] = append(filter["entityTags"].([]interface{}), tagString(object(t)))
+		}
+		filters = append(filters, filter)
+	}
+
+	suppressSynthetic, _ := p["suppressSyntheticMonitorsExecution"].(bool)
+	return map[string]interface{}{
+		"enabled": true,
+		"generalProperties": map[string]interface{}{
+			"name":                             p["name"],
+			"description":                      p["description"],
+			"maintenanceType":                  p["type"],
+			"suppression":                      p["suppression"],
+			"disableSyntheticMonitorExecution": suppressSynthetic,
+		},
+		"schedule": schedule,
+		"filters":  filters,
+	}, nil
+}
+
+// classicMaintenanceLayout is the layout of the start and end of maintenance windows of the config API
+const classicMaintenanceLayout = "2006-01-02 15:04"
+
+func maintenanceSchedule(s map[string]interface{}) (map[string]interface{}, error) {
+	start, err := time.Parse(classicMaintenanceLayout, fmt.Sprint(s["start"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start of schedule %q, expected the format 'YYYY-MM-DD hh:mm'", s["start"])
+	}
+	end, err := time.Parse(classicMaintenanceLayout, fmt.Sprint(s["end"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid end of schedule %q, expected the format 'YYYY-MM-DD hh:mm'", s["end"])
+	}
+
+	recurrenceType := fmt.Sprint(s["recurrenceType"])
+	if recurrenceType == "ONCE" {
+		return map[string]interface{}{
+			"scheduleType": "ONCE",
+			"onceRecurrence": map[string]interface{}{
+				"startTime": start.Format("2006-01-02T15:04:05"),
+				"endTime":   end.Format("2006-01-02T15:04:05"),
+				"timeZone":  s["zoneId"],
+			},
+		}, nil
+	}
+
+	recurrence := object(s["recurrence"])
+	windowStart, err := time.Parse("15:04", fmt.Sprint(recurrence["startTime"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start time of recurrence %q, expected the format 'hh:mm'", recurrence["startTime"])
+	}
+	duration, ok := recurrence["durationMinutes"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("the recurrence requires 'durationMinutes' as number")
+	}
+	windowEnd := windowStart.Add(time.Duration(duration) * time.Minute)
+
+	details := map[string]interface{}{
+		"timeWindow": map[string]interface{}{
+			"startTime": windowStart.Format("15:04:05"),
+			"endTime":   windowEnd.Format("15:04:05"),
+			"timeZone":  s["zoneId"],
+		},
+		"recurrenceRange": map[string]interface{}{
+			"scheduleStartDate": start.Format(time.DateOnly),
+			"scheduleEndDate":   end.Format(time.DateOnly),
+		},
+	}
+	switch recurrenceType {
+	case "DAILY":
+	case "WEEKLY":
+		details["dayOfWeek"] = recurrence["dayOfWeek"]
+	case "MONTHLY":
+		details["dayOfMonth"] = recurrence["dayOfMonth"]
+	default:
+		return nil, fmt.Errorf("unknown recurrence type %q", recurrenceType)
+	}
+
+	key := strings.ToLower(recurrenceType) + "Recurrence"
+	return map[string]interface{}{
+		"scheduleType": recurrenceType,
+		key:            details,
+	}, nil
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func items(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
@@ -0,0 +1,145 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// configFile is a config file of a project, parsed into YAML nodes to allow changing it while preserving comments
+type configFile struct {
+	path     string
+	project  string
+	document *yaml.Node
+	modified bool
+}
+
+func (f *configFile) root() *yaml.Node {
+	return f.document.Content[0]
+}
+
+func (f *configFile) entries() []*yaml.Node {
+	return yamlnode.Items(yamlnode.Get(f.root(), "configs"))
+}
+
+// newConfigFile returns a new file of the given project holding no config.
+func newConfigFile(path, project string) *configFile {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	yamlnode.Set(root, "configs", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"})
+
+	return &configFile{
+		path:     path,
+		project:  project,
+		document: &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}},
+	}
+}
+
+// loadConfigFiles loads all config files of the given projects. Like the project loader, hidden directories are
+// skipped. YAML files which do not define configs, like delete files, are ignored.
+func loadConfigFiles(fs afero.Fs, projects manifest.ProjectDefinitionByProjectID) ([]*configFile, error) {
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var result []*configFile
+	for _, name := range names {
+		err := afero.Walk(fs, projects[name].Path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != projects[name].Path && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !files.IsYamlFileExtension(path) {
+				return nil
+			}
+
+			f, err := loadConfigFile(fs, path, name)
+			if err != nil {
+				return err
+			}
+			if f != nil {
+				result = append(result, f)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config files of project %q: %w", name, err)
+		}
+	}
+
+	return result, nil
+}
+
+func loadConfigFile(fs afero.Fs, path, project string) (*configFile, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+
+	if len(document.Content) == 0 || yamlnode.Get(document.Content[0], "configs") == nil {
+		return nil, nil
+	}
+
+	return &configFile{path: path, project: project, document: &document}, nil
+}
+
+// write writes the file if it was modified. Files without any config left are removed.
+func (f *configFile) write(fs afero.Fs) error {
+	if !f.modified {
+		return nil
+	}
+
+	if len(f.entries()) == 0 {
+		return fs.Remove(f.path)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(f.document); err != nil {
+		return fmt.Errorf("failed to serialize %q: %w", f.path, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to serialize %q: %w", f.path, err)
+	}
+
+	if err := fs.MkdirAll(filepath.Dir(f.path), 0777); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, f.path, buf.Bytes(), 0664)
+}
@@ -0,0 +1,208 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+)
+
+// MigrateAPI migrates all configs of the deprecated config API 'from' to the config API or settings schema 'to'. If
+// 'to' is empty, the API deprecating 'from' is used. The types of the configs are rewritten, their templates are
+// converted by the migration registered for the pair, and all references across the given projects are rewritten.
+//
+// Migrating to a settings schema requires a registered migration, as payloads differ. Objects already deployed via
+// the deprecated API are not touched and need to be removed once the migrated configs are deployed.
+func MigrateAPI(fs afero.Fs, projects manifest.ProjectDefinitionByProjectID, from, to string) error {
+	apis := api.NewAPIs()
+	fromAPI, found := apis[from]
+	if !found {
+		return fmt.Errorf("unknown config API %q", from)
+	}
+	if to == "" {
+		if fromAPI.DeprecatedBy == "" {
+			return fmt.Errorf("config API %q is not deprecated, thus the target of the migration must be given", from)
+		}
+		to = fromAPI.DeprecatedBy
+	}
+	if _, known := apis[to]; !known {
+		// APIs of monaco v1 which were replaced by their successor, e.g. 'dashboard-v2', are resolved to the latter
+		if legacy, isLegacy := api.NewV1APIs()[to]; isLegacy {
+			current := api.GetV2ID(legacy)
+			if current == from {
+				log.Info("Configs of API %q are already deployed as %q, as its handling of non-unique names is the default by now. Nothing needs to be migrated", from, to)
+				return nil
+			}
+			to = current
+		}
+	}
+	if from == to {
+		return fmt.Errorf("source and target of the migration are the same: %q", from)
+	}
+	if fromAPI.HasParent() {
+		return fmt.Errorf("configs of API %q are scoped to a parent object and can not be migrated", from)
+	}
+
+	_, toClassic := apis[to]
+	migration, found := apiMigrations[from][to]
+	if !found && !toClassic {
+		return fmt.Errorf("migrating config API %q to %q is not supported. Supported migrations: %s", from, to, strings.Join(SupportedAPIMigrations(), ", "))
+	}
+	if fromAPI.DeprecatedBy != "" && fromAPI.DeprecatedBy != to {
+		log.Warn("Config API %q is deprecated by %q, not by %q", from, fromAPI.DeprecatedBy, to)
+	}
+
+	files, err := loadConfigFiles(fs, projects)
+	if err != nil {
+		return err
+	}
+
+	rename := func(c coordinate.Coordinate) coordinate.Coordinate {
+		if c.Type == from {
+			c.Type = to
+		}
+		return c
+	}
+
+	templates := make(map[string]struct{})
+	migrated := 0
+	for _, f := range files {
+		for _, e := range f.entries() {
+			self := entryCoordinate(f.project, e)
+			if self.Type == from {
+				for _, n := range templateNodes(e) {
+					templates[filepath.Join(filepath.Dir(f.path), filepath.FromSlash(n.Value))] = struct{}{}
+				}
+			}
+			if n := rewriteReferences(e, self, rename(self), rename); n > 0 && self.Type != from {
+				log.Info("Rewrote %d reference(s) in config %s (%s)", n, self, f.path)
+				f.modified = true
+			}
+			if self.Type != from {
+				continue
+			}
+			setEntryType(e, to, toClassic, migration.scope)
+			f.modified = true
+			migrated++
+			log.Info("Migrated config %s (%s) to %q", self, f.path, to)
+		}
+	}
+	if migrated == 0 {
+		return fmt.Errorf("no config of API %q found", from)
+	}
+
+	for _, f := range files {
+		for _, e := range f.entries() {
+			if entryType(e) == to {
+				continue
+			}
+			for _, n := range templateNodes(e) {
+				if _, shared := templates[filepath.Join(filepath.Dir(f.path), filepath.FromSlash(n.Value))]; shared {
+					return fmt.Errorf("template %q is also used by config %s, which is not migrated. Please use separate templates", n.Value, entryCoordinate(f.project, e))
+				}
+			}
+		}
+	}
+
+	// all templates are converted before anything is written, so a template which can't be converted leaves the
+	// projects untouched
+	converted := make(map[string][]byte, len(templates))
+	if migration.migrate != nil {
+		for t := range templates {
+			content, err := migrateTemplate(fs, t, migration)
+			if err != nil {
+				return fmt.Errorf("failed to migrate template %q: %w", t, err)
+			}
+			converted[t] = content
+		}
+	}
+
+	for t, content := range converted {
+		if err := afero.WriteFile(fs, t, content, 0664); err != nil {
+			return fmt.Errorf("failed to write template %q: %w", t, err)
+		}
+		log.Info("Migrated template %q", t)
+	}
+	for _, f := range files {
+		if err := f.write(fs); err != nil {
+			return fmt.Errorf("failed to write %q: %w", f.path, err)
+		}
+	}
+
+	log.Info("Migrated %d config(s) from %q to %q. Objects deployed via %q are not removed automatically", migrated, from, to, from)
+	return nil
+}
+
+// setEntryType sets the type of a config entry to the given config API or settings schema.
+func setEntryType(entry *yaml.Node, to string, toClassic bool, scope string) {
+	if toClassic {
+		if t := yamlnode.Get(entry, "type"); t != nil && t.Kind == yaml.MappingNode {
+			yamlnode.Set(t, "api", yamlnode.Scalar(to))
+			return
+		}
+		yamlnode.Set(entry, "type", yamlnode.Scalar(to))
+		return
+	}
+
+	if scope == "" {
+		scope = "environment"
+	}
+	settings := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	yamlnode.Set(settings, "schema", yamlnode.Scalar(to))
+	yamlnode.Set(settings, "scope", yamlnode.Scalar(scope))
+	t := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	yamlnode.Set(t, "settings", settings)
+	yamlnode.Set(entry, "type", t)
+}
+
+// migrateTemplate returns the converted content of the template at the given path. Templates need to be valid JSON,
+// i.e. all placeholders need to be within strings.
+func migrateTemplate(fs afero.Fs, path string, m apiMigration) ([]byte, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("template is not valid JSON and needs to be migrated manually: %w", err)
+	}
+
+	migrated, err := m.migrate(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(migrated); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
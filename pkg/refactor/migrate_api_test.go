@@ -0,0 +1,214 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"encoding/json"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const alertingProfiles = `configs:
+- id: profile
+  type: alerting-profile
+  config:
+    name: Team A
+    template: profile.json
+`
+
+const alertingProfileTemplate = `{
+  "displayName": "{{.name}}",
+  "mzId": null,
+  "rules": [
+    {
+      "severityLevel": "AVAILABILITY",
+      "tagFilter": {
+        "includeMode": "INCLUDE_ANY",
+        "tagFilters": [{"context": "CONTEXTLESS", "key": "team", "value": "a & b"}, {"context": "AWS", "key": "env"}]
+      },
+      "delayInMinutes": 5
+    }
+  ],
+  "eventTypeFilters": [
+    {"predefinedEventFilter": {"eventType": "OSI_HIGH_CPU", "negate": false}}
+  ]
+}`
+
+const notifications = `configs:
+- id: notification
+  type:
+    api: notification
+  config:
+    name: Notify
+    template: notification.json
+    parameters:
+      profile: [b, alerting-profile, profile, id]
+`
+
+func TestMigrateAPI(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for path, content := range map[string]string{
+		"a/dashboard/config.yaml":          projectA,
+		"a/dashboard/overview.json":        "{}",
+		"a/dashboard/details.json":         "{}",
+		"a/notification/config.yaml":       notifications,
+		"a/notification/notification.json": "{}",
+		"b/profile/config.yaml":            alertingProfiles,
+		"b/profile/profile.json":           alertingProfileTemplate,
+	} {
+		assert.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+	}
+
+	err := MigrateAPI(fs, testProjects, "alerting-profile", "")
+	assert.NoError(t, err)
+
+	projects := loadProjects(t, fs)
+	migrated := coordinate.Coordinate{Project: "b", Type: "builtin:alerting.profile", ConfigId: "profile"}
+	profile := findConfig(projects, migrated)
+	if assert.NotNil(t, profile) {
+		assert.Equal(t, config.SettingsType{SchemaId: "builtin:alerting.profile"}, profile.Type)
+	}
+
+	notification := findConfig(projects, coordinate.Coordinate{Project: "a", Type: "notification", ConfigId: "notification"})
+	if assert.NotNil(t, notification) {
+		assert.Equal(t, migrated, notification.Parameters["profile"].(*reference.ReferenceParameter).Config)
+	}
+
+	content, err := afero.ReadFile(fs, "b/profile/profile.json")
+	assert.NoError(t, err)
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(content, &payload))
+	assert.Equal(t, map[string]interface{}{
+		"name":           "{{.name}}",
+		"managementZone": nil,
+		"severityRules": []interface{}{
+			map[string]interface{}{
+				"severityLevel":        "AVAILABILITY",
+				"delayInMinutes":       float64(5),
+				"tagFilterIncludeMode": "INCLUDE_ANY",
+				"tagFilter":            []interface{}{"team:a & b", "[AWS]env"},
+			},
+		},
+		"eventFilters": []interface{}{
+			map[string]interface{}{
+				"type":             "PREDEFINED",
+				"predefinedFilter": map[string]interface{}{"eventType": "OSI_HIGH_CPU", "negate": false},
+			},
+		},
+	}, payload)
+	assert.Contains(t, string(content), "a & b", "templates should not be HTML escaped")
+}
+
+func TestMigrateAPI_Errors(t *testing.T) {
+	tests := []struct {
+		name, from, to string
+		expected       string
+	}{
+		{"unknown API", "unknown", "", "unknown config API"},
+		{"not deprecated without target", "dashboard", "", "is not deprecated"},
+		{"unsupported migration", "management-zone", "", "is not supported"},
+		{"no configs", "maintenance-window", "", "no config of API"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MigrateAPI(setupFs(t), testProjects, tt.from, tt.to)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestMigrateAPI_TemplatesAreConvertedBeforeWriting(t *testing.T) {
+	fs := setupFs(t)
+	for path, content := range map[string]string{
+		"b/profile/config.yaml": alertingProfiles + `- id: broken
+  type: alerting-profile
+  config:
+    name: Broken
+    template: broken.json
+`,
+		"b/profile/profile.json": alertingProfileTemplate,
+		"b/profile/broken.json":  `{"displayName": {{.name}}}`,
+	} {
+		assert.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+	}
+
+	err := MigrateAPI(fs, testProjects, "alerting-profile", "")
+	assert.ErrorContains(t, err, "broken.json")
+
+	content, err := afero.ReadFile(fs, "b/profile/profile.json")
+	assert.NoError(t, err)
+	assert.Equal(t, alertingProfileTemplate, string(content))
+	content, err = afero.ReadFile(fs, "b/profile/config.yaml")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "builtin:alerting.profile")
+}
+
+func TestMigrateAPI_LegacyTarget(t *testing.T) {
+	fs := setupFs(t)
+
+	err := MigrateAPI(fs, testProjects, "dashboard", "dashboard-v2")
+	assert.NoError(t, err)
+
+	content, err := afero.ReadFile(fs, "a/dashboard/config.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, projectA, string(content))
+}
+
+func TestMigrateMaintenanceWindow(t *testing.T) {
+	var classic map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+  "name": "Weekly",
+  "description": "backup",
+  "type": "PLANNED",
+  "suppression": "DONT_DETECT_PROBLEMS",
+  "suppressSyntheticMonitorsExecution": true,
+  "scope": {"entities": ["HOST-1"], "matches": [{"type": "SERVICE", "mzId": "42", "tags": [{"context": "CONTEXTLESS", "key": "team", "value": "a"}], "tagCombination": "AND"}]},
+  "schedule": {
+    "recurrenceType": "WEEKLY",
+    "start": "2023-01-01 00:00",
+    "end": "2023-12-31 23:59",
+    "zoneId": "Europe/Vienna",
+    "recurrence": {"dayOfWeek": "SUNDAY", "startTime": "23:00", "durationMinutes": 90}
+  }
+}`), &classic))
+
+	got, err := migrateMaintenanceWindow(classic)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"scheduleType": "WEEKLY",
+		"weeklyRecurrence": map[string]interface{}{
+			"dayOfWeek":       "SUNDAY",
+			"timeWindow":      map[string]interface{}{"startTime": "23:00:00", "endTime": "00:30:00", "timeZone": "Europe/Vienna"},
+			"recurrenceRange": map[string]interface{}{"scheduleStartDate": "2023-01-01", "scheduleEndDate": "2023-12-31"},
+		},
+	}, got["schedule"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"entityId": "HOST-1", "entityTags": []interface{}{}, "managementZones": []interface{}{}},
+		map[string]interface{}{"entityType": "SERVICE", "entityTags": []interface{}{"team:a"}, "managementZones": []interface{}{"42"}},
+	}, got["filters"])
+	assert.Equal(t, true, got["generalProperties"].(map[string]interface{})["disableSyntheticMonitorExecution"])
+
+	classic["schedule"].(map[string]interface{})["start"] = "tomorrow"
+	_, err = migrateMaintenanceWindow(classic)
+	assert.ErrorContains(t, err, "invalid start")
+}
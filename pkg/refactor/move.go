@@ -0,0 +1,307 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package refactor implements changes of config files which span a whole monaco repository.
+package refactor
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
+)
+
+// templateMove is a template file which needs to be copied to a new location.
+type templateMove struct {
+	from, to string
+}
+
+// Move moves the config 'from' to the coordinate 'to'. The config definition is moved to the config file at the same
+// relative path in the target project and its template is moved along. All references and explicit dependencies
+// across the given projects are rewritten to the new coordinate.
+//
+// The original coordinate is recorded as 'movedFrom' of the config, so that objects already deployed keep being
+// identified by it, and are updated instead of being created anew.
+func Move(fs afero.Fs, projects manifest.ProjectDefinitionByProjectID, from, to coordinate.Coordinate) error {
+	if from == to {
+		return fmt.Errorf("source and target of the move are the same: %s", from)
+	}
+	if from.Type != to.Type {
+		return fmt.Errorf("configs can not change their type: %q can not be moved to %q", from.Type, to.Type)
+	}
+	for _, p := range []string{from.Project, to.Project} {
+		if _, found := projects[p]; !found {
+			return fmt.Errorf("project %q is not defined in the manifest", p)
+		}
+	}
+
+	files, err := loadConfigFiles(fs, projects)
+	if err != nil {
+		return err
+	}
+
+	source, entry := findEntry(files, from)
+	if entry == nil {
+		return fmt.Errorf("config %s does not exist", from)
+	}
+	if _, existing := findEntry(files, to); existing != nil {
+		return fmt.Errorf("config %s already exists", to)
+	}
+
+	target := source
+	if from.Project != to.Project {
+		target = targetFile(files, projects, source, to.Project)
+		if !containsFile(files, target) {
+			files = append(files, target)
+		}
+	}
+
+	templates, err := moveTemplates(fs, files, entry, filepath.Dir(source.path), filepath.Dir(target.path), from, to)
+	if err != nil {
+		return err
+	}
+
+	rename := func(c coordinate.Coordinate) coordinate.Coordinate {
+		if c == from {
+			return to
+		}
+		return c
+	}
+	for _, f := range files {
+		for _, e := range f.entries() {
+			self := entryCoordinate(f.project, e)
+			if n := rewriteReferences(e, self, rename(self), rename); n > 0 && e != entry {
+				log.Info("Rewrote %d reference(s) in config %s (%s)", n, self, f.path)
+				f.modified = true
+			}
+		}
+	}
+
+	yamlnode.Set(entry, "id", yamlnode.Scalar(to.ConfigId))
+	setMovedFrom(entry, from, to)
+
+	if target != source {
+		configs := yamlnode.Get(source.root(), "configs")
+		for i, e := range configs.Content {
+			if e == entry {
+				configs.Content = append(configs.Content[:i], configs.Content[i+1:]...)
+				break
+			}
+		}
+		yamlnode.Get(target.root(), "configs").Content = append(yamlnode.Get(target.root(), "configs").Content, entry)
+	}
+	source.modified = true
+	target.modified = true
+	log.Info("Moved config %s (%s) to %s (%s)", from, source.path, to, target.path)
+
+	for _, t := range templates {
+		if err := copyFile(fs, t.from, t.to); err != nil {
+			return fmt.Errorf("failed to move template %q to %q: %w", t.from, t.to, err)
+		}
+		log.Info("Moved template %q to %q", t.from, t.to)
+	}
+
+	for _, f := range files {
+		if err := f.write(fs); err != nil {
+			return fmt.Errorf("failed to write %q: %w", f.path, err)
+		}
+	}
+
+	for _, t := range templates {
+		if templateInUse(files, t.from) {
+			log.Info("Template %q is still used by other configs and is kept", t.from)
+		} else {
+			if err := fs.Remove(t.from); err != nil {
+				return fmt.Errorf("failed to remove template %q: %w", t.from, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsFile(files []*configFile, f *configFile) bool {
+	for _, other := range files {
+		if other == f {
+			return true
+		}
+	}
+	return false
+}
+
+func findEntry(files []*configFile, c coordinate.Coordinate) (*configFile, *yaml.Node) {
+	for _, f := range files {
+		if f.project != c.Project {
+			continue
+		}
+		for _, e := range f.entries() {
+			if entryCoordinate(f.project, e) == c {
+				return f, e
+			}
+		}
+	}
+	return nil, nil
+}
+
+// targetFile returns the config file of the target project at the same path relative to the project as the source
+// file. If it does not exist yet, a new file is returned.
+func targetFile(files []*configFile, projects manifest.ProjectDefinitionByProjectID, source *configFile, project string) *configFile {
+	path := source.path
+	if rel, err := filepath.Rel(projects[source.project].Path, source.path); err == nil {
+		path = filepath.Join(projects[project].Path, rel)
+	}
+
+	for _, f := range files {
+		if filepath.Clean(f.path) == filepath.Clean(path) {
+			return f
+		}
+	}
+	return newConfigFile(path, project)
+}
+
+// setMovedFrom records the coordinate the config was moved from. If the config was already moved before, its original
+// coordinate is kept, unless it is moved back to it.
+func setMovedFrom(entry *yaml.Node, from, to coordinate.Coordinate) {
+	if existing := yamlnode.Get(entry, "movedFrom"); existing != nil {
+		original := coordinate.Coordinate{
+			Project:  yamlnode.ScalarValue(existing, "project"),
+			Type:     yamlnode.ScalarValue(existing, "configType"),
+			ConfigId: yamlnode.ScalarValue(existing, "configId"),
+		}
+		if original == to {
+			yamlnode.Delete(entry, "movedFrom")
+		}
+		return
+	}
+
+	movedFrom := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	yamlnode.Set(movedFrom, "project", yamlnode.Scalar(from.Project))
+	yamlnode.Set(movedFrom, "configType", yamlnode.Scalar(from.Type))
+	yamlnode.Set(movedFrom, "configId", yamlnode.Scalar(from.ConfigId))
+	yamlnode.Set(entry, "movedFrom", movedFrom)
+}
+
+// templateNodes returns the nodes of all templates of a config entry, including the ones of its overrides.
+func templateNodes(entry *yaml.Node) []*yaml.Node {
+	var result []*yaml.Node
+	if t := yamlnode.Get(entry, "config", "template"); t != nil {
+		result = append(result, t)
+	}
+	for _, overrides := range []string{"groupOverrides", "environmentOverrides"} {
+		for _, o := range yamlnode.Items(yamlnode.Get(entry, overrides)) {
+			if t := yamlnode.Get(o, "override", "template"); t != nil {
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
+
+// moveTemplates updates the templates of the moved entry and returns the template files which need to be copied.
+// Templates in the directory of the config are moved to the target directory, and renamed if they are named after the
+// config ID. Templates outside the directory of the config stay in place.
+func moveTemplates(fs afero.Fs, files []*configFile, entry *yaml.Node, sourceDir, targetDir string, from, to coordinate.Coordinate) ([]templateMove, error) {
+	var moves []templateMove
+	for _, n := range templateNodes(entry) {
+		value := filepath.FromSlash(n.Value)
+		oldPath := filepath.Join(sourceDir, value)
+		newPath := oldPath
+
+		if !strings.HasPrefix(filepath.Clean(value), "..") {
+			name := filepath.Base(value)
+			if ext := filepath.Ext(name); strings.TrimSuffix(name, ext) == from.ConfigId {
+				name = to.ConfigId + ext
+			}
+			newPath = filepath.Join(targetDir, filepath.Dir(value), name)
+		}
+
+		if newPath != oldPath && !containsMove(moves, oldPath) {
+			if err := checkTemplateTarget(fs, oldPath, newPath); err != nil {
+				return nil, err
+			}
+			moves = append(moves, templateMove{from: oldPath, to: newPath})
+		}
+
+		rel, err := filepath.Rel(targetDir, newPath)
+		if err != nil {
+			return nil, err
+		}
+		n.Value = filepath.ToSlash(rel)
+	}
+
+	return moves, nil
+}
+
+func containsMove(moves []templateMove, from string) bool {
+	for _, m := range moves {
+		if m.from == from {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTemplateTarget ensures that moving a template does not overwrite a different file.
+func checkTemplateTarget(fs afero.Fs, from, to string) error {
+	exists, err := afero.Exists(fs, to)
+	if err != nil || !exists {
+		return err
+	}
+
+	a, err := afero.ReadFile(fs, from)
+	if err != nil {
+		return err
+	}
+	b, err := afero.ReadFile(fs, to)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(a, b) {
+		return fmt.Errorf("can not move template %q to %q, as a different file already exists there", from, to)
+	}
+	return nil
+}
+
+// templateInUse returns whether any config still references the given template file.
+func templateInUse(files []*configFile, path string) bool {
+	for _, f := range files {
+		for _, e := range f.entries() {
+			for _, n := range templateNodes(e) {
+				if filepath.Join(filepath.Dir(f.path), filepath.FromSlash(n.Value)) == path {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func copyFile(fs afero.Fs, from, to string) error {
+	data, err := afero.ReadFile(fs, from)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(filepath.Dir(to), 0777); err != nil {
+		return err
+	}
+	return afero.WriteFile(fs, to, data, 0664)
+}
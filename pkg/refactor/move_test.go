@@ -0,0 +1,215 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/api"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var testProjects = manifest.ProjectDefinitionByProjectID{
+	"a": {Name: "a", Path: "a"},
+	"b": {Name: "b", Path: "b"},
+}
+
+const projectA = `configs:
+# the dashboard to move
+- id: overview
+  type: dashboard
+  config:
+    name: Overview
+    template: overview.json
+- id: details
+  type:
+    api: dashboard
+  config:
+    name: Details
+    template: details.json
+    parameters:
+      link: [overview, id]
+      self: [name]
+  dependsOn:
+  - [overview]
+`
+
+const projectB = `configs:
+- id: profile
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope:
+        type: reference
+        project: a
+        configType: dashboard
+        configId: overview
+        property: id
+  config:
+    name: Profile
+    template: profile.json
+`
+
+func setupFs(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	for path, content := range map[string]string{
+		"a/dashboard/config.yaml":    projectA,
+		"a/dashboard/overview.json":  "{}",
+		"a/dashboard/details.json":   "{}",
+		"b/settings/config.yaml":     projectB,
+		"b/settings/profile.json":    "{}",
+		"a/dashboard/.hidden/x.yaml": "configs: [{id: broken}]",
+	} {
+		assert.NoError(t, afero.WriteFile(fs, path, []byte(content), 0644))
+	}
+	return fs
+}
+
+func loadProjects(t *testing.T, fs afero.Fs) []project.Project {
+	projects, errs := project.LoadProjects(fs, project.ProjectLoaderContext{
+		KnownApis:  api.NewAPIs().GetApiNameLookup(),
+		WorkingDir: ".",
+		Manifest: manifest.Manifest{
+			Projects:     testProjects,
+			Environments: map[string]manifest.EnvironmentDefinition{"env": {Name: "env", Group: "default"}},
+		},
+		ParametersSerde: config.ParameterParsers(),
+		Strict:          true,
+	})
+	assert.Empty(t, errs)
+	return projects
+}
+
+func findConfig(projects []project.Project, c coordinate.Coordinate) *config.Config {
+	for _, p := range projects {
+		for _, conf := range p.Configs["env"][c.Type] {
+			if conf.Coordinate == c {
+				conf := conf
+				return &conf
+			}
+		}
+	}
+	return nil
+}
+
+func TestMove_ToOtherProject(t *testing.T) {
+	fs := setupFs(t)
+	from := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "overview"}
+	to := coordinate.Coordinate{Project: "b", Type: "dashboard", ConfigId: "main"}
+
+	err := Move(fs, testProjects, from, to)
+	assert.NoError(t, err)
+
+	exists, _ := afero.Exists(fs, "a/dashboard/overview.json")
+	assert.False(t, exists, "template should have been moved")
+	exists, _ = afero.Exists(fs, "b/dashboard/main.json")
+	assert.True(t, exists, "template should have been moved and renamed")
+
+	projects := loadProjects(t, fs)
+
+	moved := findConfig(projects, to)
+	if assert.NotNil(t, moved) {
+		assert.Equal(t, from, moved.MovedFrom)
+		assert.Equal(t, from, moved.OriginCoordinate())
+	}
+	assert.Nil(t, findConfig(projects, from))
+
+	details := findConfig(projects, coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "details"})
+	if assert.NotNil(t, details) {
+		assert.Equal(t, to, details.Parameters["link"].(*reference.ReferenceParameter).Config)
+		assert.Equal(t, details.Coordinate, details.Parameters["self"].(*reference.ReferenceParameter).Config)
+		assert.Equal(t, []coordinate.Coordinate{to}, details.DependsOn)
+	}
+
+	profile := findConfig(projects, coordinate.Coordinate{Project: "b", Type: "builtin:alerting.profile", ConfigId: "profile"})
+	if assert.NotNil(t, profile) {
+		assert.Equal(t, to, profile.Parameters[config.ScopeParameter].(*reference.ReferenceParameter).Config)
+	}
+
+	content, err := afero.ReadFile(fs, "b/settings/config.yaml")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "project:", "reference within the same project should be written without the project")
+}
+
+func TestMove_RenameWithinProject(t *testing.T) {
+	fs := setupFs(t)
+	from := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "details"}
+	to := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "details-renamed"}
+
+	err := Move(fs, testProjects, from, to)
+	assert.NoError(t, err)
+
+	content, err := afero.ReadFile(fs, "a/dashboard/config.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "# the dashboard to move", "comments should be preserved")
+	assert.Contains(t, string(content), "template: details-renamed.json")
+
+	projects := loadProjects(t, fs)
+	renamed := findConfig(projects, to)
+	if assert.NotNil(t, renamed) {
+		assert.Equal(t, from, renamed.MovedFrom)
+		assert.Equal(t, to, renamed.Parameters["self"].(*reference.ReferenceParameter).Config)
+	}
+}
+
+func TestMove_BackToOriginRemovesMovedFrom(t *testing.T) {
+	fs := setupFs(t)
+	origin := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "overview"}
+	moved := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "moved"}
+
+	assert.NoError(t, Move(fs, testProjects, origin, moved))
+	assert.NoError(t, Move(fs, testProjects, moved, origin))
+
+	c := findConfig(loadProjects(t, fs), origin)
+	if assert.NotNil(t, c) {
+		assert.Equal(t, coordinate.Coordinate{}, c.MovedFrom)
+	}
+}
+
+func TestMove_Errors(t *testing.T) {
+	overview := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "overview"}
+
+	tests := []struct {
+		name     string
+		from, to coordinate.Coordinate
+		expected string
+	}{
+		{"same coordinate", overview, overview, "are the same"},
+		{"different type", overview, coordinate.Coordinate{Project: "a", Type: "alerting-profile", ConfigId: "overview"}, "can not change their type"},
+		{"unknown project", overview, coordinate.Coordinate{Project: "c", Type: "dashboard", ConfigId: "overview"}, "project \"c\" is not defined"},
+		{"unknown config", coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "unknown"}, overview, "does not exist"},
+		{"existing target", overview, coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "details"}, "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := setupFs(t)
+			err := Move(fs, testProjects, tt.from, tt.to)
+			assert.ErrorContains(t, err, tt.expected)
+
+			content, _ := afero.ReadFile(fs, "a/dashboard/config.yaml")
+			assert.Equal(t, projectA, string(content), "files must not be changed on errors")
+		})
+	}
+}
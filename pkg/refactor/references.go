@@ -0,0 +1,256 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"gopkg.in/yaml.v3"
+)
+
+// entryType returns the type of the coordinate of a config entry, i.e. the name of its API, its schema ID, its
+// entities type or the type of extension configs.
+func entryType(entry *yaml.Node) string {
+	t := yamlnode.Get(entry, "type")
+	if t != nil && t.Kind == yaml.ScalarNode {
+		return t.Value
+	}
+
+	switch {
+	case yamlnode.ScalarValue(t, "api") != "":
+		return yamlnode.ScalarValue(t, "api")
+	case yamlnode.Get(t, "settings") != nil:
+		return yamlnode.ScalarValue(yamlnode.Get(t, "settings"), "schema")
+	case yamlnode.Get(t, "entities") != nil:
+		return yamlnode.ScalarValue(yamlnode.Get(t, "entities"), "entitiesType")
+	case yamlnode.Get(t, "extension") != nil:
+		return config.ExtensionCoordinateType
+	case yamlnode.Get(t, "extensionMonitoring") != nil:
+		return config.ExtensionMonitoringCoordinateType
+	default:
+		return ""
+	}
+}
+
+func entryCoordinate(project string, entry *yaml.Node) coordinate.Coordinate {
+	return coordinate.Coordinate{Project: project, Type: entryType(entry), ConfigId: yamlnode.ScalarValue(entry, "id")}
+}
+
+// configSections returns the 'config' section of a config entry and the 'override' sections of its overrides.
+func configSections(entry *yaml.Node) []*yaml.Node {
+	sections := []*yaml.Node{yamlnode.Get(entry, "config")}
+	for _, o := range yamlnode.Items(yamlnode.Get(entry, "groupOverrides")) {
+		sections = append(sections, yamlnode.Get(o, "override"))
+	}
+	for _, o := range yamlnode.Items(yamlnode.Get(entry, "environmentOverrides")) {
+		sections = append(sections, yamlnode.Get(o, "override"))
+	}
+	return sections
+}
+
+// parameterNodes returns the values of all parameters of a config entry, including the ones of its overrides and the
+// scope of its type, which may hold references.
+func parameterNodes(entry *yaml.Node) []*yaml.Node {
+	var result []*yaml.Node
+	add := func(n *yaml.Node) {
+		if n != nil {
+			result = append(result, n)
+		}
+	}
+
+	for _, s := range configSections(entry) {
+		add(yamlnode.Get(s, "name"))
+		add(yamlnode.Get(s, "skip"))
+
+		parameters := yamlnode.Get(s, "parameters")
+		for _, k := range yamlnode.Keys(parameters) {
+			add(yamlnode.Get(parameters, k.Value))
+		}
+	}
+
+	add(yamlnode.Get(entry, "type", "scope"))
+	add(yamlnode.Get(entry, "type", "settings", "scope"))
+	add(yamlnode.Get(entry, "type", "extensionMonitoring", "scope"))
+
+	return result
+}
+
+// rewriteReferences rewrites all references and explicit dependencies of the config entry at the coordinate self,
+// which is moved to newSelf. References are resolved relative to self, renamed, and written relative to newSelf in the
+// shortest form. References which resolve to the same config as before are left untouched, unless the entry itself
+// is moved. It returns the number of rewritten references.
+func rewriteReferences(entry *yaml.Node, self, newSelf coordinate.Coordinate, rename func(coordinate.Coordinate) coordinate.Coordinate) int {
+	count := 0
+
+	for _, p := range parameterNodes(entry) {
+		switch {
+		case p.Kind == yaml.SequenceNode:
+			target, property, ok := parseShortReference(self, p)
+			if !ok || !needsRewrite(self, newSelf, target, rename) {
+				continue
+			}
+			p.Content = shortReference(newSelf, rename(target), property).Content
+			count++
+
+		case p.Kind == yaml.MappingNode && yamlnode.ScalarValue(p, "type") == refParam.ReferenceParameterType:
+			target := mappingCoordinate(self, p, self.ConfigId)
+			if !needsRewrite(self, newSelf, target, rename) {
+				continue
+			}
+			setMappingCoordinate(p, newSelf, rename(target))
+			count++
+		}
+	}
+
+	for _, d := range yamlnode.Items(yamlnode.Get(entry, "dependsOn")) {
+		switch d.Kind {
+		case yaml.SequenceNode:
+			target, ok := parseShortDependency(self, d)
+			if !ok || !needsRewrite(self, newSelf, target, rename) {
+				continue
+			}
+			d.Content = shortDependency(newSelf, rename(target)).Content
+			count++
+
+		case yaml.MappingNode:
+			target := mappingCoordinate(self, d, "")
+			if target.ConfigId == "" || !needsRewrite(self, newSelf, target, rename) {
+				continue
+			}
+			setMappingCoordinate(d, newSelf, rename(target))
+			yamlnode.Set(d, "configId", yamlnode.Scalar(rename(target).ConfigId))
+			count++
+		}
+	}
+
+	return count
+}
+
+func needsRewrite(self, newSelf, target coordinate.Coordinate, rename func(coordinate.Coordinate) coordinate.Coordinate) bool {
+	return self != newSelf || rename(target) != target
+}
+
+// parseShortReference parses a reference defined as list of [property], [configId, property],
+// [type, configId, property] or [project, type, configId, property].
+func parseShortReference(self coordinate.Coordinate, n *yaml.Node) (coordinate.Coordinate, string, bool) {
+	v := scalarValues(n)
+	switch len(v) {
+	case 1:
+		return self, v[0], true
+	case 2:
+		return coordinate.Coordinate{Project: self.Project, Type: self.Type, ConfigId: v[0]}, v[1], true
+	case 3:
+		return coordinate.Coordinate{Project: self.Project, Type: v[0], ConfigId: v[1]}, v[2], true
+	case 4:
+		return coordinate.Coordinate{Project: v[0], Type: v[1], ConfigId: v[2]}, v[3], true
+	default:
+		return coordinate.Coordinate{}, "", false
+	}
+}
+
+func shortReference(self, target coordinate.Coordinate, property string) *yaml.Node {
+	switch {
+	case target == self:
+		return yamlnode.Sequence(property)
+	case target.Project == self.Project && target.Type == self.Type:
+		return yamlnode.Sequence(target.ConfigId, property)
+	case target.Project == self.Project:
+		return yamlnode.Sequence(target.Type, target.ConfigId, property)
+	default:
+		return yamlnode.Sequence(target.Project, target.Type, target.ConfigId, property)
+	}
+}
+
+// parseShortDependency parses a 'dependsOn' entry defined as list of [configId], [type, configId] or
+// [project, type, configId].
+func parseShortDependency(self coordinate.Coordinate, n *yaml.Node) (coordinate.Coordinate, bool) {
+	v := scalarValues(n)
+	switch len(v) {
+	case 1:
+		return coordinate.Coordinate{Project: self.Project, Type: self.Type, ConfigId: v[0]}, true
+	case 2:
+		return coordinate.Coordinate{Project: self.Project, Type: v[0], ConfigId: v[1]}, true
+	case 3:
+		return coordinate.Coordinate{Project: v[0], Type: v[1], ConfigId: v[2]}, true
+	default:
+		return coordinate.Coordinate{}, false
+	}
+}
+
+func shortDependency(self, target coordinate.Coordinate) *yaml.Node {
+	switch {
+	case target.Project == self.Project && target.Type == self.Type:
+		return yamlnode.Sequence(target.ConfigId)
+	case target.Project == self.Project:
+		return yamlnode.Sequence(target.Type, target.ConfigId)
+	default:
+		return yamlnode.Sequence(target.Project, target.Type, target.ConfigId)
+	}
+}
+
+// mappingCoordinate returns the coordinate defined by the 'project', 'configType' and 'configId' fields of a
+// mapping, filled in from self and the given default config ID if missing.
+func mappingCoordinate(self coordinate.Coordinate, n *yaml.Node, defaultConfigId string) coordinate.Coordinate {
+	c := coordinate.Coordinate{Project: self.Project, Type: self.Type, ConfigId: defaultConfigId}
+	if v := yamlnode.Get(n, "project"); v != nil {
+		c.Project = v.Value
+	}
+	if v := yamlnode.Get(n, "configType"); v != nil {
+		c.Type = v.Value
+	}
+	if v := yamlnode.Get(n, "configId"); v != nil {
+		c.ConfigId = v.Value
+	}
+	return c
+}
+
+// setMappingCoordinate sets the 'project', 'configType' and 'configId' fields of a mapping to the target, leaving out
+// the ones which are the same as the ones of self.
+func setMappingCoordinate(n *yaml.Node, self, target coordinate.Coordinate) {
+	sameProject := self.Project == target.Project
+	sameType := sameProject && self.Type == target.Type
+	sameConfig := sameType && self.ConfigId == target.ConfigId
+
+	for _, f := range []struct {
+		key   string
+		value string
+		keep  bool
+	}{
+		{"project", target.Project, !sameProject},
+		{"configType", target.Type, !sameType},
+		{"configId", target.ConfigId, !sameConfig},
+	} {
+		if f.keep {
+			yamlnode.Set(n, f.key, yamlnode.Scalar(f.value))
+		} else {
+			yamlnode.Delete(n, f.key)
+		}
+	}
+}
+
+func scalarValues(n *yaml.Node) []string {
+	values := make([]string, 0, len(n.Content))
+	for _, c := range n.Content {
+		if c.Kind != yaml.ScalarNode {
+			return nil
+		}
+		values = append(values, c.Value)
+	}
+	return values
+}
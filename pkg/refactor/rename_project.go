@@ -0,0 +1,110 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	valueParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// RenameProject rewrites the config files in the given folder, which were written for the project 'from', so that
+// they can be used in the project 'to'. References to configs of project 'from' are rewritten to project 'to', and
+// the name 'from' is replaced by 'to' in config names and value parameters, e.g. in a name like 'from: Overview'.
+// Configs and templates are not moved.
+func RenameProject(fs afero.Fs, folder, from, to string) error {
+	files, err := loadConfigFiles(fs, manifest.ProjectDefinitionByProjectID{from: {Name: from, Path: folder}})
+	if err != nil {
+		return err
+	}
+
+	rename := func(c coordinate.Coordinate) coordinate.Coordinate {
+		if c.Project == from {
+			c.Project = to
+		}
+		return c
+	}
+	for _, f := range files {
+		for _, e := range f.entries() {
+			self := entryCoordinate(f.project, e)
+			if n := rewriteReferences(e, self, rename(self), rename); n > 0 {
+				log.Debug("Rewrote %d reference(s) in config %s (%s)", n, self, f.path)
+				f.modified = true
+			}
+			if n := renameInValues(e, from, to); n > 0 {
+				log.Debug("Replaced project name in %d value(s) of config %s (%s)", n, self, f.path)
+				f.modified = true
+			}
+		}
+	}
+
+	for _, f := range files {
+		if err := f.write(fs); err != nil {
+			return fmt.Errorf("failed to write %q: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// renameInValues replaces the name 'from' by 'to' in the names and value parameters of the config entry, including the
+// ones of its overrides. It returns the number of replaced occurrences.
+func renameInValues(entry *yaml.Node, from, to string) int {
+	count := 0
+	for _, s := range configSections(entry) {
+		count += renameInScalars(yamlnode.Get(s, "name"), from, to)
+
+		parameters := yamlnode.Get(s, "parameters")
+		for _, k := range yamlnode.Keys(parameters) {
+			p := yamlnode.Get(parameters, k.Value)
+			switch {
+			case p.Kind == yaml.ScalarNode:
+				count += renameInScalars(p, from, to)
+			case p.Kind == yaml.MappingNode && yamlnode.ScalarValue(p, "type") == valueParam.ValueParameterType:
+				count += renameInScalars(yamlnode.Get(p, "value"), from, to)
+			}
+		}
+	}
+	return count
+}
+
+// renameInScalars replaces the name 'from' by 'to' in all scalars of the given node, e.g. in all values of a map.
+func renameInScalars(n *yaml.Node, from, to string) int {
+	if n == nil {
+		return 0
+	}
+	if n.Kind == yaml.ScalarNode {
+		var count int
+		n.Value, count = idutils.ReplaceId(n.Value, from, to)
+		return count
+	}
+
+	count := 0
+	for i, c := range n.Content {
+		// keys of maps are not renamed, as they are part of the structure of the value
+		if n.Kind == yaml.MappingNode && i%2 == 0 {
+			continue
+		}
+		count += renameInScalars(c, from, to)
+	}
+	return count
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const sampleProject = `configs:
+- id: profile
+  type:
+    settings:
+      schema: builtin:alerting.profile
+      scope: environment
+  config:
+    name: Profile of sample
+    template: profile.json
+    parameters:
+      title: sample overview
+      labels:
+        type: value
+        value:
+          owner: sample
+      dashboard: [sample, dashboard, overview, id]
+      zone:
+        type: reference
+        project: sample
+        configType: management-zone
+        configId: zone
+        property: id
+      other:
+        type: reference
+        project: shared
+        configType: management-zone
+        configId: zone
+        property: id
+`
+
+func TestRenameProject(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, afero.WriteFile(fs, "team/sample/config.yaml", []byte(sampleProject), 0644))
+
+	err := RenameProject(fs, "team/sample", "sample", "team")
+	assert.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "team/sample/config.yaml")
+	assert.NoError(t, err)
+	content := string(data)
+	assert.NotContains(t, content, "sample")
+	assert.Contains(t, content, "project: shared")
+	assert.Contains(t, content, "dashboard: [dashboard, overview, id]")
+	assert.Contains(t, content, "name: Profile of team")
+	assert.Contains(t, content, "title: team overview")
+	assert.Contains(t, content, "owner: team")
+}
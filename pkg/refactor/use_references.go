@@ -0,0 +1,191 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/yamlnode"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	refParam "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/manifest"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RawReference is the ID of the object deployed by the config Target, contained as is in the templates of the config
+// at Config.
+type RawReference struct {
+	Config coordinate.Coordinate
+	Id     string
+	Target coordinate.Coordinate
+}
+
+// UseReferences replaces the given raw IDs in templates with placeholders of reference parameters resolving the ID of
+// the target config, which are added to the configs. Templates shared with other configs are left untouched, as the
+// parameters would be missing for them. It returns the number of replaced IDs.
+func UseReferences(fs afero.Fs, projects manifest.ProjectDefinitionByProjectID, refs []RawReference) (int, error) {
+	files, err := loadConfigFiles(fs, projects)
+	if err != nil {
+		return 0, err
+	}
+
+	refsPerConfig := make(map[coordinate.Coordinate][]RawReference)
+	for _, r := range refs {
+		refsPerConfig[r.Config] = append(refsPerConfig[r.Config], r)
+	}
+	configs := make([]coordinate.Coordinate, 0, len(refsPerConfig))
+	for c := range refsPerConfig {
+		configs = append(configs, c)
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].String() < configs[j].String()
+	})
+
+	replaced := 0
+	for _, c := range configs {
+		f, entry := findEntry(files, c)
+		if entry == nil {
+			return replaced, fmt.Errorf("config %s does not exist", c)
+		}
+
+		n, err := useReferences(fs, files, f, entry, c, refsPerConfig[c])
+		if err != nil {
+			return replaced, err
+		}
+		if n > 0 {
+			log.Info("Replaced %d raw ID(s) with reference parameters in config %s (%s)", n, c, f.path)
+			f.modified = true
+			replaced += n
+		}
+	}
+
+	for _, f := range files {
+		if err := f.write(fs); err != nil {
+			return replaced, fmt.Errorf("failed to write %q: %w", f.path, err)
+		}
+	}
+	return replaced, nil
+}
+
+// useReferences replaces the raw IDs in the templates of the given config entry and adds the reference parameters
+// to it. It returns the number of replaced IDs.
+func useReferences(fs afero.Fs, files []*configFile, f *configFile, entry *yaml.Node, self coordinate.Coordinate, refs []RawReference) (int, error) {
+	parameterNames := make(map[coordinate.Coordinate]string)
+	replaced := 0
+
+	for _, t := range templateNodes(entry) {
+		path := filepath.Join(filepath.Dir(f.path), filepath.FromSlash(t.Value))
+		if templateUsers(files, path) > 1 {
+			log.Warn("Template %q of config %s is shared with other configs, replace its raw IDs manually", path, self)
+			continue
+		}
+
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return replaced, fmt.Errorf("failed to read template %q: %w", path, err)
+		}
+
+		content := string(data)
+		for _, r := range refs {
+			name, found := parameterNames[r.Target]
+			if !found {
+				name = referenceParameterName(entry, r.Target)
+			}
+
+			var n int
+			if content, n = idutils.ReplaceId(content, r.Id, "{{ ."+name+" }}"); n == 0 {
+				continue
+			}
+			if !found {
+				addReferenceParameter(entry, self, r.Target, name)
+				parameterNames[r.Target] = name
+			}
+			replaced++
+		}
+
+		if content != string(data) {
+			if err := afero.WriteFile(fs, path, []byte(content), 0664); err != nil {
+				return replaced, fmt.Errorf("failed to write template %q: %w", path, err)
+			}
+		}
+	}
+	return replaced, nil
+}
+
+// templateUsers returns the number of config entries using the given template file.
+func templateUsers(files []*configFile, path string) int {
+	count := 0
+	for _, f := range files {
+		for _, e := range f.entries() {
+			for _, n := range templateNodes(e) {
+				if filepath.Join(filepath.Dir(f.path), filepath.FromSlash(n.Value)) == path {
+					count++
+					break
+				}
+			}
+		}
+	}
+	return count
+}
+
+// referenceParameterName returns a parameter name for a reference to the given target, which is not yet used by the
+// config entry, e.g. 'overviewId' for a reference to the config 'overview'. Names are valid template fields.
+func referenceParameterName(entry *yaml.Node, target coordinate.Coordinate) string {
+	var b strings.Builder
+	for i, r := range target.ConfigId {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteString("config")
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	base := b.String() + "Id"
+
+	parameters := yamlnode.Get(entry, "config", "parameters")
+	name := base
+	for i := 2; yamlnode.Get(parameters, name) != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	return name
+}
+
+// addReferenceParameter adds a parameter of the given name to the config entry, resolving the ID of the target.
+func addReferenceParameter(entry *yaml.Node, self, target coordinate.Coordinate, name string) {
+	cfg := yamlnode.Get(entry, "config")
+	parameters := yamlnode.Get(cfg, "parameters")
+	if parameters == nil {
+		parameters = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		yamlnode.Set(cfg, "parameters", parameters)
+	}
+
+	p := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	yamlnode.Set(p, "type", yamlnode.Scalar(refParam.ReferenceParameterType))
+	setMappingCoordinate(p, self, target)
+	yamlnode.Set(p, "property", yamlnode.Scalar("id"))
+	yamlnode.Set(parameters, name, p)
+}
@@ -0,0 +1,99 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package refactor
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestUseReferences(t *testing.T) {
+	fs := setupFs(t)
+	assert.NoError(t, afero.WriteFile(fs, "b/settings/profile.json", []byte(`{"dashboard": "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", "other": "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d-x"}`), 0644))
+
+	overview := coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "overview"}
+	profile := coordinate.Coordinate{Project: "b", Type: "builtin:alerting.profile", ConfigId: "profile"}
+
+	n, err := UseReferences(fs, testProjects, []RawReference{
+		{Config: profile, Id: "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d", Target: overview},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	content, err := afero.ReadFile(fs, "b/settings/profile.json")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"dashboard": "{{ .overviewId }}", "other": "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d-x"}`, string(content))
+
+	projects := loadProjects(t, fs)
+	c := findConfig(projects, profile)
+	if assert.NotNil(t, c) {
+		ref, ok := c.Parameters["overviewId"].(*reference.ReferenceParameter)
+		if assert.True(t, ok, "reference parameter should have been added") {
+			assert.Equal(t, overview, ref.Config)
+			assert.Equal(t, "id", ref.Property)
+		}
+	}
+}
+
+func TestUseReferences_SharedTemplatesAreKept(t *testing.T) {
+	fs := setupFs(t)
+	assert.NoError(t, afero.WriteFile(fs, "a/dashboard/config.yaml", []byte(`configs:
+- id: overview
+  type: dashboard
+  config:
+    name: Overview
+    template: shared.json
+- id: details
+  type: dashboard
+  config:
+    name: Details
+    template: shared.json
+`), 0644))
+	assert.NoError(t, afero.WriteFile(fs, "a/dashboard/shared.json", []byte(`{"profile": "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU"}`), 0644))
+
+	n, err := UseReferences(fs, testProjects, []RawReference{{
+		Config: coordinate.Coordinate{Project: "a", Type: "dashboard", ConfigId: "overview"},
+		Id:     "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU",
+		Target: coordinate.Coordinate{Project: "b", Type: "builtin:alerting.profile", ConfigId: "profile"},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	content, err := afero.ReadFile(fs, "a/dashboard/shared.json")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "vu9U3hXa3q0AAAABABhidWlsdGluOmFsZXJ0aW5nLnByb2ZpbGU")
+}
+
+func Test_referenceParameterName(t *testing.T) {
+	entry := yamlEntry(t, `{id: x, config: {parameters: {overviewId: taken}}}`)
+
+	assert.Equal(t, "overviewId2", referenceParameterName(entry, coordinate.Coordinate{ConfigId: "overview"}))
+	assert.Equal(t, "my_dashboardId", referenceParameterName(entry, coordinate.Coordinate{ConfigId: "my-dashboard"}))
+	assert.Equal(t, "config0a1b_2cId", referenceParameterName(entry, coordinate.Coordinate{ConfigId: "0a1b-2c"}))
+}
+
+func yamlEntry(t *testing.T, s string) *yaml.Node {
+	var document yaml.Node
+	assert.NoError(t, yaml.Unmarshal([]byte(s), &document))
+	return document.Content[0]
+}
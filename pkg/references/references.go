@@ -0,0 +1,146 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package references finds configs referring to a config or to a Dynatrace object, e.g. to assess the impact of
+// changing or deleting it.
+package references
+
+import (
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/idutils"
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/lint"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"sort"
+	"strings"
+)
+
+// Kind states how a config refers to the target.
+type Kind string
+
+const (
+	// ReferenceParameter is a reference parameter resolving a property of the target config
+	ReferenceParameter Kind = "reference parameter"
+	// Dependency is an entry of 'dependsOn'
+	Dependency Kind = "dependsOn"
+	// Template is an occurrence of the raw ID in the template
+	Template Kind = "template"
+	// ValueParameter is an occurrence of the raw ID in the value of a value parameter
+	ValueParameter Kind = "value parameter"
+)
+
+// Reference is a config referring to the target.
+type Reference struct {
+	// Coordinate of the referring config
+	Coordinate coordinate.Coordinate
+	// Environments holds the names of all environments the config refers to the target in, as configs are loaded
+	// separately for every environment
+	Environments []string
+	// Kind states how the config refers to the target
+	Kind Kind
+	// Detail names where the target is referred to, e.g. the name of the parameter or template
+	Detail string
+}
+
+func (r Reference) String() string {
+	if r.Detail == "" {
+		return fmt.Sprintf("%s (environments: %s): %s", r.Coordinate, strings.Join(r.Environments, ", "), r.Kind)
+	}
+	return fmt.Sprintf("%s (environments: %s): %s %s", r.Coordinate, strings.Join(r.Environments, ", "), r.Kind, r.Detail)
+}
+
+// ToConfig returns all configs referring to the target config, using reference parameters or explicit dependencies.
+func ToConfig(projects []project.Project, target coordinate.Coordinate) []Reference {
+	return find(projects, func(c config.Config) []Reference {
+		var result []Reference
+		for _, name := range lint.SortedParameterNames(c.Parameters) {
+			for _, ref := range c.Parameters[name].GetReferences() {
+				if ref.Config == target {
+					result = append(result, Reference{Kind: ReferenceParameter, Detail: fmt.Sprintf("%s (property %s)", name, ref.Property)})
+				}
+			}
+		}
+		for _, d := range c.DependsOn {
+			if d == target {
+				result = append(result, Reference{Kind: Dependency})
+			}
+		}
+		return result
+	})
+}
+
+// ToId returns all configs containing the raw ID of a Dynatrace object, e.g. an entity ID, in their template or in the
+// value of a value parameter. Like lint.FindRawReferences, the ID is only found as a whole, not as part of a longer ID.
+func ToId(projects []project.Project, id string) []Reference {
+	return find(projects, func(c config.Config) []Reference {
+		var result []Reference
+		if c.Template != nil && idutils.ContainsId(c.Template.Content(), id) {
+			result = append(result, Reference{Kind: Template, Detail: c.Template.Name()})
+		}
+		for _, name := range lint.SortedParameterNames(c.Parameters) {
+			if v, ok := c.Parameters[name].(*value.ValueParameter); ok && idutils.ContainsId(fmt.Sprint(v.Value), id) {
+				result = append(result, Reference{Kind: ValueParameter, Detail: name})
+			}
+		}
+		return result
+	})
+}
+
+// find collects the references returned by the given function for all configs. Equal references of several
+// environments are merged into a single one. References are sorted by config coordinate, kind and detail.
+func find(projects []project.Project, referencesOf func(c config.Config) []Reference) []Reference {
+	type key struct {
+		coordinate coordinate.Coordinate
+		kind       Kind
+		detail     string
+	}
+
+	environments := make(map[key][]string)
+	for _, p := range projects {
+		p.ForEveryConfigDo(func(c config.Config) {
+			for _, r := range referencesOf(c) {
+				k := key{coordinate: c.Coordinate, kind: r.Kind, detail: r.Detail}
+				environments[k] = append(environments[k], c.Environment)
+			}
+		})
+	}
+
+	result := make([]Reference, 0, len(environments))
+	for k, envs := range environments {
+		sort.Strings(envs)
+		result = append(result, Reference{
+			Coordinate:   k.coordinate,
+			Environments: envs,
+			Kind:         k.kind,
+			Detail:       k.detail,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Coordinate != b.Coordinate {
+			return a.Coordinate.String() < b.Coordinate.String()
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Detail < b.Detail
+	})
+
+	return result
+}
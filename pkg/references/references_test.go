@@ -0,0 +1,105 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package references
+
+import (
+	config "github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/coordinate"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/reference"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/parameter/value"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/config/v2/template"
+	project "github.com/dynatrace/dynatrace-configuration-as-code/pkg/project/v2"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	target    = coordinate.Coordinate{Project: "infra", Type: "management-zone", ConfigId: "mz"}
+	dashboard = coordinate.Coordinate{Project: "app", Type: "dashboard", ConfigId: "dashboard"}
+	profile   = coordinate.Coordinate{Project: "app", Type: "alerting-profile", ConfigId: "profile"}
+)
+
+func newConfig(c coordinate.Coordinate, env, content string, params config.Parameters, dependsOn ...coordinate.Coordinate) config.Config {
+	return config.Config{
+		Template:    template.CreateTemplateFromString("template.json", content),
+		Coordinate:  c,
+		Environment: env,
+		Parameters:  params,
+		DependsOn:   dependsOn,
+	}
+}
+
+func testProjects() []project.Project {
+	configsOf := func(env string) map[string][]config.Config {
+		return map[string][]config.Config{
+			"dashboard": {newConfig(dashboard, env, `{"entity": "HOST-1234"}`, config.Parameters{
+				"mz": reference.NewWithCoordinate(target, "id"),
+			})},
+			"alerting-profile": {newConfig(profile, env, `{}`, config.Parameters{
+				"host": value.New("HOST-1234"),
+			}, target)},
+		}
+	}
+
+	return []project.Project{
+		{
+			Id: "app",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env2": configsOf("env2"),
+				"env1": configsOf("env1"),
+			},
+		},
+		{
+			Id: "infra",
+			Configs: project.ConfigsPerTypePerEnvironments{
+				"env1": {"management-zone": {newConfig(target, "env1", `{}`, nil)}},
+			},
+		},
+	}
+}
+
+func TestToConfig(t *testing.T) {
+	got := ToConfig(testProjects(), target)
+
+	assert.Equal(t, []Reference{
+		{Coordinate: profile, Environments: []string{"env1", "env2"}, Kind: Dependency},
+		{Coordinate: dashboard, Environments: []string{"env1", "env2"}, Kind: ReferenceParameter, Detail: "mz (property id)"},
+	}, got)
+}
+
+func TestToConfig_NothingFound(t *testing.T) {
+	got := ToConfig(testProjects(), dashboard)
+	assert.Empty(t, got)
+}
+
+func TestToId(t *testing.T) {
+	got := ToId(testProjects(), "HOST-1234")
+
+	assert.Equal(t, []Reference{
+		{Coordinate: profile, Environments: []string{"env1", "env2"}, Kind: ValueParameter, Detail: "host"},
+		{Coordinate: dashboard, Environments: []string{"env1", "env2"}, Kind: Template, Detail: "template.json"},
+	}, got)
+}
+
+func TestReference_String(t *testing.T) {
+	assert.Equal(t, "app:dashboard:dashboard (environments: env1, env2): template template.json",
+		Reference{Coordinate: dashboard, Environments: []string{"env1", "env2"}, Kind: Template, Detail: "template.json"}.String())
+	assert.Equal(t, "app:alerting-profile:profile (environments: env1): dependsOn",
+		Reference{Coordinate: profile, Environments: []string{"env1"}, Kind: Dependency}.String())
+}
@@ -0,0 +1,209 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/spf13/afero"
+)
+
+// CachingTransport is an http.RoundTripper caching successful GET responses on disk, keyed by their URL and credentials.
+// Cached responses younger than the TTL are returned without sending a request. Older responses carrying an ETag are
+// revalidated using 'If-None-Match' and reused if they are unchanged.
+//
+// Time frame parameters (see timeParameters) are relative to the current time for most requests, e.g. when listing
+// entities. To reuse such responses, the key holds their offset from the current time, rounded to minutes.
+//
+// The cache is meant for read-only commands run repeatedly against the same environment. It does not notice changes
+// made by other requests, hence it must not be used when changing configs.
+type CachingTransport struct {
+	base http.RoundTripper
+	fs   afero.Fs
+	dir  string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mutex sync.Mutex
+}
+
+// timeParameters are the query parameters holding the bounds of the time frame of a request, in Unix milliseconds
+var timeParameters = []string{"from", "to"}
+
+// cacheEntry is a cached response as stored on disk
+type cacheEntry struct {
+	Key        string      `json:"key"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	ETag       string      `json:"etag,omitempty"`
+	StoredAt   time.Time   `json:"storedAt"`
+}
+
+// NewCachingTransport creates a new CachingTransport storing responses in the given directory. If base is nil,
+// http.DefaultTransport is used.
+func NewCachingTransport(base http.RoundTripper, fs afero.Fs, dir string, ttl time.Duration) *CachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &CachingTransport{base: base, fs: fs, dir: dir, ttl: ttl, now: time.Now}
+}
+
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	key := t.key(req)
+	entry, found := t.load(key)
+	if found && t.now().Sub(entry.StoredAt) < t.ttl {
+		log.Debug("Using cached response for %s", url)
+		return entry.response(req), nil
+	}
+
+	if found && entry.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if found && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		log.Debug("Cached response for %s is unchanged", url)
+		entry.StoredAt = t.now()
+		t.store(entry)
+		return entry.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(cacheEntry{
+		Key:        key,
+		URL:        url,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		ETag:       resp.Header.Get("ETag"),
+		StoredAt:   t.now(),
+	})
+
+	return resp, nil
+}
+
+// key returns the key the response to the given request is cached by. It consists of the URL, with time frame
+// parameters replaced by their offset from the current time, and a hash of the credentials, so that responses are not
+// shared between tokens.
+func (t *CachingTransport) key(req *http.Request) string {
+	u := *req.URL
+	query := u.Query()
+	for _, p := range timeParameters {
+		millis, err := strconv.ParseInt(query.Get(p), 10, 64)
+		if err != nil {
+			continue
+		}
+		offset := time.UnixMilli(millis).Sub(t.now()).Round(time.Minute)
+		query.Set(p, fmt.Sprintf("now%+dm", offset/time.Minute))
+	}
+	u.RawQuery = query.Encode()
+
+	hash := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return u.String() + " " + hex.EncodeToString(hash[:])
+}
+
+func (t *CachingTransport) load(key string) (cacheEntry, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	data, err := afero.ReadFile(t.fs, t.path(key))
+	if err != nil {
+		return cacheEntry{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		log.Debug("Ignoring invalid cache entry at %s", t.path(key))
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// store writes the given entry to the cache. Failing to do so is not an error, as the response can still be used.
+func (t *CachingTransport) store(entry cacheEntry) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err := t.write(entry); err != nil {
+		log.Warn("Failed to cache response for %s: %v", entry.URL, err)
+	}
+}
+
+func (t *CachingTransport) write(entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := t.fs.MkdirAll(t.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return afero.WriteFile(t.fs, t.path(entry.Key), data, 0600)
+}
+
+// path returns the path of the cache file of the given key
+func (t *CachingTransport) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(t.dir, hex.EncodeToString(hash[:])+".json")
+}
+
+func (e cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
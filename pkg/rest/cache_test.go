@@ -0,0 +1,141 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"github.com/spf13/afero"
+	"gotest.tools/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCachingTransport(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("ETag", `"v1"`)
+		_, _ = rw.Write([]byte(`{"name": "cached"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := NewCachingTransport(nil, afero.NewMemMapFs(), "/cache", time.Minute)
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	get := func() Response {
+		resp, err := Get(context.TODO(), client, server.URL+"/api/config/v1/something")
+		assert.NilError(t, err)
+		return resp
+	}
+
+	resp := get()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, string(resp.Body), `{"name": "cached"}`)
+	assert.Equal(t, requests, 1)
+
+	// within the TTL, the cached response is used without sending a request
+	resp = get()
+	assert.Equal(t, string(resp.Body), `{"name": "cached"}`)
+	assert.Equal(t, requests, 1)
+
+	// after the TTL, the cached response is revalidated and reused
+	now = now.Add(2 * time.Minute)
+	resp = get()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, string(resp.Body), `{"name": "cached"}`)
+	assert.Equal(t, requests, 2)
+
+	// revalidation restarts the TTL
+	get()
+	assert.Equal(t, requests, 2)
+}
+
+func TestCachingTransport_DoesNotCacheFailedOrNonGetRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.Method == http.MethodGet {
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fs := afero.NewMemMapFs()
+	client := &http.Client{Transport: NewCachingTransport(nil, fs, "/cache", time.Hour)}
+
+	for i := 0; i < 2; i++ {
+		resp, err := Get(context.TODO(), client, server.URL)
+		assert.NilError(t, err)
+		assert.Equal(t, resp.StatusCode, http.StatusNotFound)
+
+		_, err = Post(context.TODO(), client, server.URL, []byte("{}"))
+		assert.NilError(t, err)
+	}
+
+	assert.Equal(t, requests, 4)
+	exists, err := afero.DirExists(fs, "/cache")
+	assert.NilError(t, err)
+	assert.Assert(t, !exists)
+}
+
+func TestCachingTransport_Key(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		_, _ = rw.Write([]byte(`{"totalCount": 0}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := NewCachingTransport(nil, afero.NewMemMapFs(), "/cache", time.Hour)
+	transport.now = func() time.Time { return now }
+	client := &http.Client{Transport: transport}
+
+	get := func(token string) {
+		from := strconv.FormatInt(now.Add(-24*time.Hour).UnixMilli(), 10)
+		to := strconv.FormatInt(now.Add(-10*time.Minute).UnixMilli(), 10)
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v2/entities?from="+from+"&to="+to, nil)
+		assert.NilError(t, err)
+		req.Header.Set("Authorization", "Api-Token "+token)
+		resp, err := client.Do(req)
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	get("token-1")
+	assert.Equal(t, requests, 1)
+
+	// the time frame moves along with the current time, but is the same relative to it
+	now = now.Add(5 * time.Second)
+	get("token-1")
+	assert.Equal(t, requests, 1)
+
+	// responses are not shared between tokens
+	get("token-2")
+	assert.Equal(t, requests, 2)
+}
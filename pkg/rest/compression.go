@@ -0,0 +1,118 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/featureflags"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+)
+
+// acceptedEncodings are the content encodings of responses decoded by decodeBody
+const acceptedEncodings = "gzip, deflate"
+
+// compressionThreshold is the minimum size of payloads compressed if featureflags.RequestCompression is enabled.
+// Compressing smaller payloads does not save enough to make up for the overhead.
+const compressionThreshold = 16 * 1024
+
+// sendPayload sends the given payload, compressing it if featureflags.RequestCompression is enabled and the payload is
+// large enough. If the API rejects the compressed payload, it is sent again uncompressed.
+func sendPayload(ctx context.Context, client *http.Client, method string, url string, data []byte) (Response, error) {
+	if featureflags.RequestCompression().Enabled() && len(data) >= compressionThreshold {
+		compressed, err := compress(data)
+		if err != nil {
+			return Response{}, fmt.Errorf("failed to compress payload: %w", err)
+		}
+
+		req, err := requestWithBody(ctx, method, url, bytes.NewReader(compressed))
+		if err != nil {
+			return Response{}, err
+		}
+		req.Header.Set("Content-Encoding", "gzip")
+
+		resp, err := executeRequest(client, req)
+		if err != nil || resp.StatusCode != http.StatusUnsupportedMediaType {
+			return resp, err
+		}
+		log.WithCtxFields(ctx).Debug("%s %s does not support compressed payloads, sending it uncompressed", method, url)
+	}
+
+	req, err := requestWithBody(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return Response{}, err
+	}
+	return executeRequest(client, req)
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeBody reads the body of the given response, decoding it according to its content encoding. As the response is
+// decoded, the Content-Encoding and Content-Length headers are removed.
+func decodeBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader
+	switch encoding := resp.Header.Get("Content-Encoding"); encoding {
+	case "":
+		return io.ReadAll(resp.Body)
+	case "gzip":
+		r, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode gzip response: %w", err)
+		}
+		defer r.Close()
+		reader = r
+	case "deflate":
+		// 'deflate' is defined as zlib format, yet some servers send raw deflate data
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if r, err := zlib.NewReader(bytes.NewReader(raw)); err == nil {
+			defer r.Close()
+			reader = r
+		} else {
+			reader = flate.NewReader(bytes.NewReader(raw))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	return body, nil
+}
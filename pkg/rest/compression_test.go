@@ -0,0 +1,131 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"gotest.tools/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodesCompressedResponses(t *testing.T) {
+	const payload = `{"name": "compressed"}`
+
+	tests := []struct {
+		encoding string
+		encode   func(w io.Writer) io.WriteCloser
+	}{
+		{"gzip", func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }},
+		{"deflate", func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, req.Header.Get("Accept-Encoding"), "gzip, deflate")
+				rw.Header().Set("Content-Encoding", tt.encoding)
+				w := tt.encode(rw)
+				_, _ = w.Write([]byte(payload))
+				_ = w.Close()
+			}))
+			defer server.Close()
+
+			resp, err := Get(context.TODO(), server.Client(), server.URL)
+
+			assert.NilError(t, err)
+			assert.Equal(t, string(resp.Body), payload)
+			assert.Equal(t, http.Header(resp.Headers).Get("Content-Encoding"), "")
+		})
+	}
+}
+
+func TestCompressesLargePayloads(t *testing.T) {
+	large := []byte(`{"data": "` + strings.Repeat("a", compressionThreshold) + `"}`)
+	small := []byte(`{"data": "a"}`)
+
+	tests := []struct {
+		name           string
+		flagEnabled    string
+		payload        []byte
+		wantCompressed bool
+	}{
+		{"compresses large payload if enabled", "true", large, true},
+		{"does not compress small payload", "true", small, false},
+		{"does not compress if disabled", "false", large, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MONACO_FEAT_REQUEST_COMPRESSION", tt.flagEnabled)
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				assert.NilError(t, err)
+
+				if tt.wantCompressed {
+					assert.Equal(t, req.Header.Get("Content-Encoding"), "gzip")
+					r, err := gzip.NewReader(bytes.NewReader(body))
+					assert.NilError(t, err)
+					body, err = io.ReadAll(r)
+					assert.NilError(t, err)
+				} else {
+					assert.Equal(t, req.Header.Get("Content-Encoding"), "")
+				}
+				assert.DeepEqual(t, body, tt.payload)
+			}))
+			defer server.Close()
+
+			resp, err := Put(context.TODO(), server.Client(), server.URL, tt.payload)
+			assert.NilError(t, err)
+			assert.Equal(t, resp.StatusCode, http.StatusOK)
+		})
+	}
+}
+
+func TestSendsPayloadUncompressedIfCompressionIsNotSupported(t *testing.T) {
+	t.Setenv("MONACO_FEAT_REQUEST_COMPRESSION", "true")
+	payload := []byte(`{"data": "` + strings.Repeat("a", compressionThreshold) + `"}`)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.Header.Get("Content-Encoding") != "" {
+			rw.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, err := io.ReadAll(req.Body)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, body, payload)
+		rw.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	resp, err := Post(context.TODO(), server.Client(), server.URL, payload)
+
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusCreated)
+	assert.Equal(t, requests, 2)
+}
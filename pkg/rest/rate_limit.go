@@ -17,9 +17,13 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
@@ -33,22 +37,134 @@ type rateLimitStrategy interface {
 	executeRequest(timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error)
 }
 
+// RateLimitListener is notified whenever a request is rate limited, with the duration the request is suspended for
+// before it is retried.
+type RateLimitListener func(wait time.Duration)
+
+type rateLimitListenerKey struct{}
+
+// WithRateLimitListener returns a context notifying the given listener whenever a request sent with it is rate limited.
+// This allows callers to slow down, instead of sending further requests that are rate limited as well.
+func WithRateLimitListener(ctx context.Context, l RateLimitListener) context.Context {
+	return context.WithValue(ctx, rateLimitListenerKey{}, l)
+}
+
+// RateLimitStats holds how often requests were rate limited during the run, and how long requests were suspended
+// because of rate limiting in total.
+type RateLimitStats struct {
+	RateLimited int64
+	Waited      time.Duration
+}
+
+var rateLimitedCount, rateLimitWaitedNanos atomic.Int64
+
+// RateLimitStatistics returns the rate limiting statistics of all requests sent since they were last reset.
+func RateLimitStatistics() RateLimitStats {
+	return RateLimitStats{
+		RateLimited: rateLimitedCount.Load(),
+		Waited:      time.Duration(rateLimitWaitedNanos.Load()),
+	}
+}
+
+// ResetRateLimitStatistics discards the rate limiting statistics of all requests sent so far.
+func ResetRateLimitStatistics() {
+	rateLimitedCount.Store(0)
+	rateLimitWaitedNanos.Store(0)
+}
+
+const (
+	// smoothingInterval is the minimal time between requests sent to a host after it rate limited requests
+	smoothingInterval = 100 * time.Millisecond
+	// smoothingPeriod is how long requests are smoothed after the rate limit of a host was reset
+	smoothingPeriod = 10 * time.Second
+)
+
+// rateLimitGate is shared by all requests sent to the same host. When a request is rate limited, all requests to the
+// host are suspended until the rate limit is reset, instead of each of them running into the rate limit on its own.
+// Afterward, requests are released one at a time for a while, so that suspended requests do not burst into the next
+// rate limit at once.
+type rateLimitGate struct {
+	mu          sync.Mutex
+	pausedUntil time.Time
+	nextSlot    time.Time
+}
+
+// gates holds the rateLimitGate per host
+var gates sync.Map
+
+func gateForHost(host string) *rateLimitGate {
+	g, _ := gates.LoadOrStore(host, &rateLimitGate{})
+	return g.(*rateLimitGate)
+}
+
+// pause suspends all requests until the given time
+func (g *rateLimitGate) pause(until time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until.After(g.pausedUntil) {
+		g.pausedUntil = until
+	}
+}
+
+// reserve returns how long a request has to wait before it may be sent.
+func (g *rateLimitGate) reserve(now time.Time) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.pausedUntil.IsZero() || now.After(g.pausedUntil.Add(smoothingPeriod)) {
+		return 0
+	}
+
+	start := now
+	if g.pausedUntil.After(start) {
+		start = g.pausedUntil
+	}
+	if g.nextSlot.After(start) {
+		start = g.nextSlot
+	}
+	g.nextSlot = start.Add(smoothingInterval)
+	return start.Sub(now)
+}
+
 // createRateLimitStrategy creates a rateLimitStrategy. In the future this can be extended to instantiate
 // different rate limiting strategies based on e.g. environment variables. The current implementation
 // always returns the strategy simpleSleepRateLimitStrategy, which suspends the current goroutine until
-// the time in the rate limiting header 'X-RateLimit-Reset' is up.
-func createRateLimitStrategy() rateLimitStrategy {
-	return &simpleSleepRateLimitStrategy{}
+// the time in the rate limiting header 'X-RateLimit-Reset' is up. A RateLimitListener set on the given
+// context is notified about each suspension. If a host is given, requests to it share a rateLimitGate.
+func createRateLimitStrategy(ctx context.Context, host string) rateLimitStrategy {
+	l, _ := ctx.Value(rateLimitListenerKey{}).(RateLimitListener)
+	s := &simpleSleepRateLimitStrategy{onRateLimited: l}
+	if host != "" {
+		s.gate = gateForHost(host)
+	}
+	return s
 }
 
 // simpleSleepRateLimitStrategy, is a rate limiting strategy which suspends the current goroutine until
-// the time in the rate limiting header 'X-RateLimit-Reset' is up.
+// the time in the rate limiting header 'X-RateLimit-Reset' - or the 'Retry-After' header - is up.
 // It has a min sleep duration of 5 seconds and a max sleep duration of one minute and performs maximal 5
 // polling iterations before giving up.
-type simpleSleepRateLimitStrategy struct{}
+type simpleSleepRateLimitStrategy struct {
+	// onRateLimited is optionally notified before each suspension
+	onRateLimited RateLimitListener
+	// gate, if set, suspends the request while other requests to the same host are rate limited
+	gate *rateLimitGate
+}
+
+// waitForGate suspends the current goroutine until the gate lets the request pass
+func (s *simpleSleepRateLimitStrategy) waitForGate(timelineProvider timeutils.TimelineProvider) {
+	if s.gate == nil {
+		return
+	}
+	if d := s.gate.reserve(timelineProvider.Now()); d > 0 {
+		rateLimitWaitedNanos.Add(int64(d))
+		timelineProvider.Sleep(d)
+	}
+}
 
 func (s *simpleSleepRateLimitStrategy) executeRequest(timelineProvider timeutils.TimelineProvider, callback func() (Response, error)) (Response, error) {
 
+	s.waitForGate(timelineProvider)
 	response, err := callback()
 	if err != nil {
 		return Response{}, err
@@ -73,11 +189,21 @@ func (s *simpleSleepRateLimitStrategy) executeRequest(timelineProvider timeutils
 
 		log.Debug("Rate limit reached (iteration: %d/%d). Sleeping until %s (%s)", currentIteration+1, maxIterationCount, humanReadableTimestamp, sleepDuration)
 
+		rateLimitedCount.Add(1)
+		rateLimitWaitedNanos.Add(int64(sleepDuration))
+		if s.gate != nil {
+			s.gate.pause(timelineProvider.Now().Add(sleepDuration))
+		}
+		if s.onRateLimited != nil {
+			s.onRateLimited(sleepDuration)
+		}
+
 		timelineProvider.Sleep(sleepDuration)
 
 		// Checking again:
 		currentIteration++
 
+		s.waitForGate(timelineProvider)
 		response, err = callback()
 		if err != nil {
 			return Response{}, err
@@ -90,6 +216,9 @@ func (s *simpleSleepRateLimitStrategy) executeRequest(timelineProvider timeutils
 func (s *simpleSleepRateLimitStrategy) getSleepDurationFromResponseHeader(response Response, timelineProvider timeutils.TimelineProvider) (sleepDuration time.Duration, humanReadableResetTimestamp string, err error) {
 	_, humanReadableTimestamp, timeInMicroseconds, err := s.extractRateLimitHeaders(response)
 	if err != nil {
+		if d, resetTime, ok := retryAfter(response, timelineProvider); ok {
+			return d, resetTime.Format(time.RFC3339), nil
+		}
 		return 0, "", fmt.Errorf("encountered response code 'STATUS_TOO_MANY_REQUESTS (429)' but failed to extract rate limit header: %w", err)
 	}
 
@@ -125,3 +254,21 @@ func (s *simpleSleepRateLimitStrategy) extractRateLimitHeaders(response Response
 
 	return limit, humanReadableResetTimestamp, resetTimeInMicroseconds, nil
 }
+
+// retryAfter returns the duration to wait and the time to wait until, as defined by the 'Retry-After' header of the
+// response given either in seconds or as HTTP date. False is returned if the header is not set or invalid.
+func retryAfter(response Response, timelineProvider timeutils.TimelineProvider) (time.Duration, time.Time, bool) {
+	values := response.Headers[http.CanonicalHeaderKey("Retry-After")]
+	if len(values) == 0 || values[0] == "" {
+		return 0, time.Time{}, false
+	}
+
+	if seconds, err := strconv.Atoi(values[0]); err == nil && seconds >= 0 {
+		d := time.Duration(seconds) * time.Second
+		return d, timelineProvider.Now().Add(d), true
+	}
+	if date, err := http.ParseTime(values[0]); err == nil {
+		return date.Sub(timelineProvider.Now()), date, true
+	}
+	return 0, time.Time{}, false
+}
@@ -19,6 +19,7 @@
 package rest
 
 import (
+	"context"
 	"errors"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/throttle"
 	"github.com/dynatrace/dynatrace-configuration-as-code/internal/timeutils"
@@ -195,3 +196,116 @@ func TestHandleEmptyResponse(t *testing.T) {
 	_, err := rateLimitStrategy.executeRequest(timelineProvider, callback)
 	assert.ErrorContains(t, err, "foo Error")
 }
+
+func TestSimpleRateLimitStrategyUsesRetryAfterAndNotifiesListener(t *testing.T) {
+
+	var notified []time.Duration
+	rateLimitStrategy := createRateLimitStrategy(WithRateLimitListener(context.TODO(), func(wait time.Duration) {
+		notified = append(notified, wait)
+	}), "")
+	timelineProvider := createTimelineProviderMock(t)
+	invocationCount := 0
+	callback := func() (Response, error) {
+
+		if invocationCount == 0 {
+			invocationCount++
+			return Response{
+				StatusCode: 429,
+				Headers:    map[string][]string{"Retry-After": {"7"}},
+			}, nil
+		}
+		return Response{StatusCode: 200}, nil
+	}
+
+	timelineProvider.EXPECT().Now().AnyTimes().Return(time.Unix(0, 0))
+	timelineProvider.EXPECT().Sleep(7 * time.Second).Times(1)
+
+	response, err := rateLimitStrategy.executeRequest(timelineProvider, callback)
+
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, 200)
+	assert.DeepEqual(t, notified, []time.Duration{7 * time.Second})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header string
+		want   time.Duration
+		wantOk bool
+	}{
+		{header: "", wantOk: false},
+		{header: "30", want: 30 * time.Second, wantOk: true},
+		{header: "Thu, 01 Jun 2023 12:00:10 GMT", want: 10 * time.Second, wantOk: true},
+		{header: "soon", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			timelineProvider := createTimelineProviderMock(t)
+			timelineProvider.EXPECT().Now().AnyTimes().Return(now)
+
+			got, until, ok := retryAfter(Response{Headers: map[string][]string{"Retry-After": {tt.header}}}, timelineProvider)
+			assert.Equal(t, ok, tt.wantOk)
+			assert.Equal(t, got, tt.want)
+			if ok {
+				assert.Equal(t, until, now.Add(tt.want))
+			}
+		})
+	}
+}
+
+func TestRateLimitGate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	g := &rateLimitGate{}
+
+	assert.Equal(t, g.reserve(now), time.Duration(0), "requests must not wait if the host never rate limited")
+
+	g.pause(now.Add(5 * time.Second))
+	assert.Equal(t, g.reserve(now), 5*time.Second, "requests must wait until the pause ends")
+	assert.Equal(t, g.reserve(now), 5*time.Second+smoothingInterval, "requests released after the pause must be spread")
+	assert.Equal(t, g.reserve(now.Add(time.Minute)), time.Duration(0), "requests must not wait after the smoothing period")
+
+	g.pause(now)
+	assert.Equal(t, g.pausedUntil, now.Add(5*time.Second), "pauses must not be shortened")
+}
+
+func TestSimpleRateLimitStrategySuspendsOtherRequestsToHost(t *testing.T) {
+	gate := &rateLimitGate{}
+	limited := &simpleSleepRateLimitStrategy{gate: gate}
+	other := &simpleSleepRateLimitStrategy{gate: gate}
+	before := RateLimitStatistics()
+
+	timelineProvider := createTimelineProviderMock(t)
+	timelineProvider.EXPECT().Now().AnyTimes().Return(time.Unix(0, 0))
+	timelineProvider.EXPECT().Sleep(7 * time.Second).Times(2)
+	timelineProvider.EXPECT().Sleep(7*time.Second + smoothingInterval).Times(1)
+
+	invocationCount := 0
+	_, err := limited.executeRequest(timelineProvider, func() (Response, error) {
+		if invocationCount == 0 {
+			invocationCount++
+			return Response{StatusCode: 429, Headers: map[string][]string{"Retry-After": {"7"}}}, nil
+		}
+		return Response{StatusCode: 200}, nil
+	})
+	assert.NilError(t, err)
+
+	response, err := other.executeRequest(timelineProvider, func() (Response, error) {
+		return Response{StatusCode: 200}, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, response.StatusCode, 200)
+
+	after := RateLimitStatistics()
+	assert.Equal(t, after.RateLimited-before.RateLimited, int64(1))
+}
+
+func TestResetRateLimitStatistics(t *testing.T) {
+	rateLimitedCount.Add(1)
+	rateLimitWaitedNanos.Add(int64(time.Second))
+
+	ResetRateLimitStatistics()
+
+	assert.Equal(t, RateLimitStatistics(), RateLimitStats{})
+}
@@ -18,6 +18,7 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -28,8 +29,19 @@ import (
 	"github.com/google/uuid"
 )
 
-func Get(client *http.Client, url string) (Response, error) {
-	req, err := request(http.MethodGet, url)
+func Get(ctx context.Context, client *http.Client, url string) (Response, error) {
+	req, err := request(ctx, http.MethodGet, url)
+
+	if err != nil {
+		return Response{}, err
+	}
+
+	return executeRequest(client, req)
+}
+
+// Delete sends a DELETE request to the given URL and returns the response without interpreting it.
+func Delete(ctx context.Context, client *http.Client, url string) (Response, error) {
+	req, err := request(ctx, http.MethodDelete, url)
 
 	if err != nil {
 		return Response{}, err
@@ -39,9 +51,9 @@ func Get(client *http.Client, url string) (Response, error) {
 }
 
 // the name delete() would collide with the built-in function
-func DeleteConfig(client *http.Client, url string, id string) error {
+func DeleteConfig(ctx context.Context, client *http.Client, url string, id string) error {
 	fullPath := url + "/" + id
-	req, err := request(http.MethodDelete, fullPath)
+	req, err := request(ctx, http.MethodDelete, fullPath)
 
 	if err != nil {
 		return err
@@ -54,7 +66,7 @@ func DeleteConfig(client *http.Client, url string, id string) error {
 	}
 
 	if resp.StatusCode == 404 {
-		log.Debug("No config with id '%s' found to delete (HTTP 404 response)", id)
+		log.WithCtxFields(ctx).Debug("No config with id '%s' found to delete (HTTP 404 response)", id)
 		return nil
 	}
 
@@ -65,18 +77,12 @@ func DeleteConfig(client *http.Client, url string, id string) error {
 	return nil
 }
 
-func Post(client *http.Client, url string, data []byte) (Response, error) {
-	req, err := requestWithBody(http.MethodPost, url, bytes.NewBuffer(data))
-
-	if err != nil {
-		return Response{}, err
-	}
-
-	return executeRequest(client, req)
+func Post(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
+	return sendPayload(ctx, client, http.MethodPost, url, data)
 }
 
-func PostMultiPartFile(client *http.Client, url string, data *bytes.Buffer, contentType string) (Response, error) {
-	req, err := requestWithBody(http.MethodPost, url, data)
+func PostMultiPartFile(ctx context.Context, client *http.Client, url string, data *bytes.Buffer, contentType string) (Response, error) {
+	req, err := requestWithBody(ctx, http.MethodPost, url, data)
 
 	if err != nil {
 		return Response{}, err
@@ -87,30 +93,25 @@ func PostMultiPartFile(client *http.Client, url string, data *bytes.Buffer, cont
 	return executeRequest(client, req)
 }
 
-func Put(client *http.Client, url string, data []byte) (Response, error) {
-	req, err := requestWithBody(http.MethodPut, url, bytes.NewBuffer(data))
-
-	if err != nil {
-		return Response{}, err
-	}
-
-	return executeRequest(client, req)
+func Put(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
+	return sendPayload(ctx, client, http.MethodPut, url, data)
 }
 
 // function type of Put and Post requests
-type SendingRequest func(client *http.Client, url string, data []byte) (Response, error)
+type SendingRequest func(ctx context.Context, client *http.Client, url string, data []byte) (Response, error)
 
-func request(method string, url string) (*http.Request, error) {
-	return requestWithBody(method, url, nil)
+func request(ctx context.Context, method string, url string) (*http.Request, error) {
+	return requestWithBody(ctx, method, url, nil)
 }
 
-func requestWithBody(method string, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+func requestWithBody(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-type", "application/json")
+	req.Header.Set("Accept-Encoding", acceptedEncodings)
 	return req, nil
 }
 
@@ -125,18 +126,18 @@ func executeRequest(client *http.Client, request *http.Request) (Response, error
 		}
 	}
 
-	rateLimitStrategy := createRateLimitStrategy()
+	rateLimitStrategy := createRateLimitStrategy(request.Context(), request.URL.Host)
 
 	response, err := rateLimitStrategy.executeRequest(timeutils.NewTimelineProvider(), func() (Response, error) {
 		resp, err := client.Do(request)
 		if err != nil {
-			log.Error("HTTP Request failed with Error: " + err.Error())
+			log.WithCtxFields(request.Context()).Error("HTTP Request failed with Error: %s", err)
 			return Response{}, err
 		}
 		defer func() {
 			err = resp.Body.Close()
 		}()
-		body, err := io.ReadAll(resp.Body)
+		body, err := decodeBody(resp)
 
 		if log.IsResponseLoggingActive() {
 			err := log.LogResponse(requestId, resp, string(body))
@@ -0,0 +1,73 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RequestAddition defines headers and query parameters added to requests whose URL path starts with PathPrefix.
+// An empty PathPrefix matches all requests.
+type RequestAddition struct {
+	PathPrefix      string
+	Headers         map[string]string
+	QueryParameters map[string]string
+}
+
+// RequestAdditionsTransport is an http.RoundTripper adding the headers and query parameters of all matching
+// RequestAddition entries to each request. Entries are applied in order, so later entries replace values of
+// earlier ones with the same name.
+type RequestAdditionsTransport struct {
+	base      http.RoundTripper
+	additions []RequestAddition
+}
+
+// NewRequestAdditionsTransport creates a new RequestAdditionsTransport. If base is nil, http.DefaultTransport is used.
+func NewRequestAdditionsTransport(base http.RoundTripper, additions []RequestAddition) *RequestAdditionsTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RequestAdditionsTransport{base: base, additions: additions}
+}
+
+func (t *RequestAdditionsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var matching []RequestAddition
+	for _, a := range t.additions {
+		if strings.HasPrefix(req.URL.Path, a.PathPrefix) {
+			matching = append(matching, a)
+		}
+	}
+	if len(matching) == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the given request, hence the request is cloned
+	r := req.Clone(req.Context())
+	query := r.URL.Query()
+	for _, a := range matching {
+		for k, v := range a.Headers {
+			r.Header.Set(k, v)
+		}
+		for k, v := range a.QueryParameters {
+			query.Set(k, v)
+		}
+	}
+	r.URL.RawQuery = query.Encode()
+
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,72 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"gotest.tools/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestAdditionsTransport(t *testing.T) {
+	var received *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		received = req
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRequestAdditionsTransport(nil, []RequestAddition{
+		{
+			Headers:         map[string]string{"X-Forwarded-User": "monaco", "X-Route": "default"},
+			QueryParameters: map[string]string{"tenant": "abc"},
+		},
+		{
+			PathPrefix: "/api/v2/settings",
+			Headers:    map[string]string{"X-Route": "settings"},
+		},
+	})}
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedRoute string
+	}{
+		{"general additions only", "/api/config/v1/alertingProfiles?pageSize=10", "default"},
+		{"api specific additions replace general ones", "/api/v2/settings/objects?pageSize=10", "settings"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			assert.NilError(t, err)
+
+			resp, err := client.Do(req)
+			assert.NilError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Equal(t, received.Header.Get("X-Forwarded-User"), "monaco")
+			assert.Equal(t, received.Header.Get("X-Route"), tt.expectedRoute)
+			assert.Equal(t, received.URL.Query().Get("tenant"), "abc")
+			assert.Equal(t, received.URL.Query().Get("pageSize"), "10")
+
+			assert.Equal(t, req.Header.Get("X-Route"), "", "original request must not be modified")
+		})
+	}
+}
@@ -19,11 +19,14 @@
 package rest
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"gotest.tools/assert"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func Test_deleteConfig(t *testing.T) {
@@ -76,7 +79,7 @@ func Test_deleteConfig(t *testing.T) {
 			}))
 			defer server.Close()
 
-			if err := DeleteConfig(server.Client(), server.URL, "checked ID does not matter"); (err != nil) != tt.wantErr {
+			if err := DeleteConfig(context.TODO(), server.Client(), server.URL, "checked ID does not matter"); (err != nil) != tt.wantErr {
 				t.Errorf("DeleteConfig() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -85,7 +88,7 @@ func Test_deleteConfig(t *testing.T) {
 
 func Test_sendWithsendWithRetryReturnsFirstSuccessfulResponse(t *testing.T) {
 	i := 0
-	mockCall := SendingRequest(func(client *http.Client, url string, data []byte) (Response, error) {
+	mockCall := SendingRequest(func(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
 		if i < 3 {
 			i++
 			return Response{}, fmt.Errorf("Something wrong")
@@ -96,7 +99,7 @@ func Test_sendWithsendWithRetryReturnsFirstSuccessfulResponse(t *testing.T) {
 		}, nil
 	})
 
-	gotResp, err := SendWithRetry(nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: 5})
+	gotResp, err := SendWithRetry(context.TODO(), nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: 5})
 	assert.NilError(t, err)
 	assert.Equal(t, gotResp.StatusCode, 200)
 	assert.Equal(t, string(gotResp.Body), "Success")
@@ -105,7 +108,7 @@ func Test_sendWithsendWithRetryReturnsFirstSuccessfulResponse(t *testing.T) {
 func Test_sendWithRetryFailsAfterDefinedTries(t *testing.T) {
 	maxRetries := 2
 	i := 0
-	mockCall := SendingRequest(func(client *http.Client, url string, data []byte) (Response, error) {
+	mockCall := SendingRequest(func(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
 		if i < maxRetries+1 {
 			i++
 			return Response{}, fmt.Errorf("Something wrong")
@@ -116,15 +119,30 @@ func Test_sendWithRetryFailsAfterDefinedTries(t *testing.T) {
 		}, nil
 	})
 
-	_, err := SendWithRetry(nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: maxRetries})
+	_, err := SendWithRetry(context.TODO(), nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: maxRetries})
 	assert.Check(t, err != nil)
 	assert.Equal(t, i, 2)
 }
 
+func Test_sendWithRetryStopsOnCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	i := 0
+	mockCall := SendingRequest(func(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
+		i++
+		return Response{}, fmt.Errorf("Something wrong")
+	})
+
+	_, err := SendWithRetry(ctx, nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: 5, WaitTime: time.Minute})
+	assert.Check(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, i, 0)
+}
+
 func Test_sendWithRetryReturnContainsOriginalApiError(t *testing.T) {
 	maxRetries := 2
 	i := 0
-	mockCall := SendingRequest(func(client *http.Client, url string, data []byte) (Response, error) {
+	mockCall := SendingRequest(func(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
 		if i < maxRetries+1 {
 			i++
 			return Response{}, fmt.Errorf("Something wrong")
@@ -135,7 +153,7 @@ func Test_sendWithRetryReturnContainsOriginalApiError(t *testing.T) {
 		}, nil
 	})
 
-	_, err := SendWithRetry(nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: maxRetries})
+	_, err := SendWithRetry(context.TODO(), nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: maxRetries})
 	assert.Check(t, err != nil)
 	assert.ErrorContains(t, err, "Something wrong")
 }
@@ -143,7 +161,7 @@ func Test_sendWithRetryReturnContainsOriginalApiError(t *testing.T) {
 func Test_sendWithRetryReturnContainsHttpErrorIfNotSuccess(t *testing.T) {
 	maxRetries := 2
 	i := 0
-	mockCall := SendingRequest(func(client *http.Client, url string, data []byte) (Response, error) {
+	mockCall := SendingRequest(func(ctx context.Context, client *http.Client, url string, data []byte) (Response, error) {
 		if i < maxRetries+1 {
 			i++
 			return Response{
@@ -157,7 +175,7 @@ func Test_sendWithRetryReturnContainsHttpErrorIfNotSuccess(t *testing.T) {
 		}, nil
 	})
 
-	_, err := SendWithRetry(nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: maxRetries})
+	_, err := SendWithRetry(context.TODO(), nil, mockCall, "dont matter", "some/path", []byte("body"), RetrySetting{MaxRetries: maxRetries})
 	assert.Check(t, err != nil)
 	assert.ErrorContains(t, err, "400")
 	assert.ErrorContains(t, err, "{ err: 'failed to create thing'}")
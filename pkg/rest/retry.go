@@ -17,6 +17,7 @@
 package rest
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -52,17 +53,19 @@ var DefaultRetrySettings = RetrySettings{
 
 // GetWithRetry will retry a GET request for a given number of times, waiting a give duration between calls
 // this method can be used for API calls we know to have occasional timing issues on GET - e.g. paginated queries that are impacted by replication lag, returning unequal amounts of objects/pages per node
-func GetWithRetry(client *http.Client, url string, settings RetrySetting) (resp Response, err error) {
-	resp, err = Get(client, url)
+func GetWithRetry(ctx context.Context, client *http.Client, url string, settings RetrySetting) (resp Response, err error) {
+	resp, err = Get(ctx, client, url)
 
 	if err == nil && resp.IsSuccess() {
 		return resp, nil
 	}
 
 	for i := 0; i < settings.MaxRetries; i++ {
-		log.Warn("Retrying failed GET request %s with error (HTTP %d)", url, resp.StatusCode)
-		time.Sleep(settings.WaitTime)
-		resp, err = Get(client, url)
+		log.WithCtxFields(ctx).Warn("Retrying failed GET request %s with error (HTTP %d)", url, resp.StatusCode)
+		if err := sleep(ctx, settings.WaitTime); err != nil {
+			return resp, err
+		}
+		resp, err = Get(ctx, client, url)
 		if err == nil && resp.IsSuccess() {
 			return resp, err
 		}
@@ -78,32 +81,45 @@ func GetWithRetry(client *http.Client, url string, settings RetrySetting) (resp
 }
 
 // SendWithRetry will retry a SendingRequest(PUT or POST) for a given number of times, waiting a give duration between calls
-func SendWithRetry(client *http.Client, restCall SendingRequest, objectName string, path string, body []byte, setting RetrySetting) (resp Response, err error) {
+func SendWithRetry(ctx context.Context, client *http.Client, restCall SendingRequest, objectName string, path string, body []byte, setting RetrySetting) (resp Response, err error) {
 
 	for i := 0; i < setting.MaxRetries; i++ {
-		log.Warn("Failed to upsert config %q. Waiting for %s before retrying...", objectName, setting.WaitTime)
-		time.Sleep(setting.WaitTime)
-		resp, err = restCall(client, path, body)
+		log.WithCtxFields(ctx).Warn("Failed to upsert config %q. Waiting for %s before retrying...", objectName, setting.WaitTime)
+		if err := sleep(ctx, setting.WaitTime); err != nil {
+			return Response{}, err
+		}
+		resp, err = restCall(ctx, client, path, body)
 		if err == nil && resp.IsSuccess() {
 			return resp, err
 		}
 	}
 
-	var retryErr error
 	if err != nil {
-		retryErr = fmt.Errorf("failed to upsert config %q after %d retries: %w", objectName, setting.MaxRetries, err)
-	} else {
-		retryErr = fmt.Errorf("failed to upsert config %q after %d retries: (HTTP %d)!\n    Response was: %s", objectName, setting.MaxRetries, resp.StatusCode, resp.Body)
+		return Response{}, fmt.Errorf("failed to upsert config %q after %d retries: %w", objectName, setting.MaxRetries, err)
 	}
-	return Response{}, retryErr
+	// the last response is returned with the error, allowing callers to inspect error details in its body
+	return resp, fmt.Errorf("failed to upsert config %q after %d retries: (HTTP %d)!\n    Response was: %s", objectName, setting.MaxRetries, resp.StatusCode, resp.Body)
 }
 
 // SendWithRetryWithInitialTry will try to send a request and later retry a SendingRequest(PUT or POST) for a given number of times, waiting a give duration between calls
-func SendWithRetryWithInitialTry(client *http.Client, restCall SendingRequest, objectName string, path string, body []byte, setting RetrySetting) (resp Response, err error) {
-	resp, err = restCall(client, path, body)
+func SendWithRetryWithInitialTry(ctx context.Context, client *http.Client, restCall SendingRequest, objectName string, path string, body []byte, setting RetrySetting) (resp Response, err error) {
+	resp, err = restCall(ctx, client, path, body)
 	if err == nil && resp.IsSuccess() {
 		return resp, err
 	}
 
-	return SendWithRetry(client, restCall, objectName, path, body, setting)
+	return SendWithRetry(ctx, client, restCall, objectName, path, body, setting)
+}
+
+// sleep waits for the given duration, returning early with the context's error if it is cancelled in the meantime
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
 }
@@ -0,0 +1,186 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package schemacache stores the Settings 2.0 schema definitions of an environment in a local folder, so they can be
+// used without access to the environment, e.g. for validation or by editors.
+//
+// Each schema is stored as '<schemaId>/<version>.json' (with ':' replaced by '_'). The index file of the folder maps
+// each schema ID to its cached version. A cached schema is invalidated and downloaded again once the environment
+// reports a different version of it.
+package schemacache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/log"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IndexFile is the name of the file mapping each cached schema ID to its version.
+const IndexFile = "index.json"
+
+// ErrNotCached is returned when loading a schema that is not cached.
+var ErrNotCached = errors.New("schema not cached")
+
+// Index maps schema IDs to their cached version.
+type Index map[string]string
+
+// Summary holds the number of schemas affected by a Pull.
+type Summary struct {
+	// Downloaded is the number of schemas that were not cached, or whose cached version was outdated
+	Downloaded int
+	// UpToDate is the number of schemas whose cached version matched the version of the environment
+	UpToDate int
+	// Removed is the number of cached schemas the environment does not report anymore
+	Removed int
+}
+
+// Pull updates the cache in the given folder with the schemas of the environment of the given client. Schemas are
+// only downloaded if they are not cached in their latest version, unless force is set.
+func Pull(ctx context.Context, fs afero.Fs, c client.SettingsClient, folder string, force bool) (Summary, error) {
+	index, err := LoadIndex(fs, folder)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	schemas, err := c.ListSchemas(ctx)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	var summary Summary
+	// outdated holds the versions of schemas replaced by a newer version, which are only removed once all schemas are
+	// fetched and the index is written, so the cache stays consistent if fetching a schema fails
+	outdated := make(Index)
+	reported := make(map[string]struct{}, len(schemas))
+	for _, s := range schemas {
+		reported[s.SchemaId] = struct{}{}
+
+		cached, found := index[s.SchemaId]
+		if found && cached == s.LatestSchemaVersion && !force {
+			summary.UpToDate++
+			continue
+		}
+
+		data, err := c.GetSchema(ctx, s.SchemaId)
+		if err != nil {
+			return summary, fmt.Errorf("failed to download schema %q: %w", s.SchemaId, err)
+		}
+		path := schemaPath(folder, s.SchemaId, s.LatestSchemaVersion)
+		if err := fs.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return summary, fmt.Errorf("failed to create folder of schema %q: %w", s.SchemaId, err)
+		}
+		if err := afero.WriteFile(fs, path, data, 0664); err != nil {
+			return summary, fmt.Errorf("failed to write schema %q: %w", s.SchemaId, err)
+		}
+		if found && cached != s.LatestSchemaVersion {
+			outdated[s.SchemaId] = cached
+		}
+		log.Debug("Downloaded schema %q in version %q", s.SchemaId, s.LatestSchemaVersion)
+
+		index[s.SchemaId] = s.LatestSchemaVersion
+		summary.Downloaded++
+	}
+
+	for schemaId, version := range index {
+		if _, found := reported[schemaId]; !found {
+			outdated[schemaId] = version
+			delete(index, schemaId)
+			summary.Removed++
+		}
+	}
+
+	if err := writeIndex(fs, folder, index); err != nil {
+		return summary, err
+	}
+	for schemaId, version := range outdated {
+		removeSchema(fs, folder, schemaId, version)
+	}
+	return summary, nil
+}
+
+// LoadIndex returns the index of the cache in the given folder. An empty index is returned if nothing is cached yet.
+func LoadIndex(fs afero.Fs, folder string) (Index, error) {
+	data, err := afero.ReadFile(fs, filepath.Join(folder, IndexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return Index{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read schema cache index: %w", err)
+	}
+
+	index := Index{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse schema cache index %q: %w", filepath.Join(folder, IndexFile), err)
+	}
+	return index, nil
+}
+
+// Load returns the cached version and definition of the given schema. ErrNotCached is returned if the schema is not
+// cached in the given folder.
+func Load(fs afero.Fs, folder string, schemaId string) (version string, definition []byte, err error) {
+	index, err := LoadIndex(fs, folder)
+	if err != nil {
+		return "", nil, err
+	}
+
+	version, found := index[schemaId]
+	if !found {
+		return "", nil, fmt.Errorf("%w: %q", ErrNotCached, schemaId)
+	}
+
+	definition, err = afero.ReadFile(fs, schemaPath(folder, schemaId, version))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read cached schema %q: %w", schemaId, err)
+	}
+	return version, definition, nil
+}
+
+func writeIndex(fs afero.Fs, folder string, index Index) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize schema cache index: %w", err)
+	}
+	if err := fs.MkdirAll(folder, 0777); err != nil {
+		return fmt.Errorf("failed to create schema cache folder %q: %w", folder, err)
+	}
+	if err := afero.WriteFile(fs, filepath.Join(folder, IndexFile), append(data, '\n'), 0664); err != nil {
+		return fmt.Errorf("failed to write schema cache index: %w", err)
+	}
+	return nil
+}
+
+func removeSchema(fs afero.Fs, folder string, schemaId string, version string) {
+	if err := fs.Remove(schemaPath(folder, schemaId, version)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn("Failed to remove outdated version %q of schema %q: %s", version, schemaId, err)
+	}
+	if empty, err := afero.IsEmpty(fs, filepath.Join(folder, schemaFolder(schemaId))); err == nil && empty {
+		_ = fs.Remove(filepath.Join(folder, schemaFolder(schemaId)))
+	}
+}
+
+func schemaPath(folder string, schemaId string, version string) string {
+	return filepath.Join(folder, schemaFolder(schemaId), version+".json")
+}
+
+func schemaFolder(schemaId string) string {
+	return strings.ReplaceAll(schemaId, ":", "_")
+}
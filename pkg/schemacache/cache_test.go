@@ -0,0 +1,147 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schemacache
+
+import (
+	"context"
+	"errors"
+	"github.com/dynatrace/dynatrace-configuration-as-code/pkg/client"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPull(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{
+		{SchemaId: "builtin:a", LatestSchemaVersion: "1.0"},
+		{SchemaId: "builtin:b", LatestSchemaVersion: "2.0"},
+	}, nil)
+	c.EXPECT().GetSchema(gomock.Any(), "builtin:a").Return([]byte(`{"schemaId": "builtin:a"}`), nil)
+	c.EXPECT().GetSchema(gomock.Any(), "builtin:b").Return([]byte(`{"schemaId": "builtin:b"}`), nil)
+
+	summary, err := Pull(context.TODO(), fs, c, "cache", false)
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{Downloaded: 2}, summary)
+
+	version, definition, err := Load(fs, "cache", "builtin:a")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", version)
+	assert.JSONEq(t, `{"schemaId": "builtin:a"}`, string(definition))
+
+	exists, _ := afero.Exists(fs, "cache/builtin_b/2.0.json")
+	assert.True(t, exists)
+
+	t.Run("only outdated schemas are downloaded again", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{
+			{SchemaId: "builtin:a", LatestSchemaVersion: "1.0"},
+			{SchemaId: "builtin:b", LatestSchemaVersion: "2.1"},
+			{SchemaId: "builtin:c", LatestSchemaVersion: "1.0"},
+		}, nil)
+		c.EXPECT().GetSchema(gomock.Any(), "builtin:b").Return([]byte(`{"schemaId": "builtin:b", "version": "2.1"}`), nil)
+		c.EXPECT().GetSchema(gomock.Any(), "builtin:c").Return([]byte(`{"schemaId": "builtin:c"}`), nil)
+
+		summary, err := Pull(context.TODO(), fs, c, "cache", false)
+		assert.NoError(t, err)
+		assert.Equal(t, Summary{Downloaded: 2, UpToDate: 1}, summary)
+
+		version, _, err := Load(fs, "cache", "builtin:b")
+		assert.NoError(t, err)
+		assert.Equal(t, "2.1", version)
+
+		exists, _ := afero.Exists(fs, "cache/builtin_b/2.0.json")
+		assert.False(t, exists, "outdated version should be removed")
+	})
+
+	t.Run("schemas not reported anymore are removed", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{
+			{SchemaId: "builtin:a", LatestSchemaVersion: "1.0"},
+		}, nil)
+
+		summary, err := Pull(context.TODO(), fs, c, "cache", false)
+		assert.NoError(t, err)
+		assert.Equal(t, Summary{UpToDate: 1, Removed: 2}, summary)
+
+		_, _, err = Load(fs, "cache", "builtin:c")
+		assert.ErrorIs(t, err, ErrNotCached)
+	})
+
+	t.Run("force downloads all schemas", func(t *testing.T) {
+		c := client.NewMockClient(gomock.NewController(t))
+		c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{
+			{SchemaId: "builtin:a", LatestSchemaVersion: "1.0"},
+		}, nil)
+		c.EXPECT().GetSchema(gomock.Any(), "builtin:a").Return([]byte(`{"schemaId": "builtin:a"}`), nil)
+
+		summary, err := Pull(context.TODO(), fs, c, "cache", true)
+		assert.NoError(t, err)
+		assert.Equal(t, Summary{Downloaded: 1}, summary)
+	})
+}
+
+func TestPull_ReturnsClientErrors(t *testing.T) {
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{{SchemaId: "builtin:a", LatestSchemaVersion: "1.0"}}, nil)
+	c.EXPECT().GetSchema(gomock.Any(), "builtin:a").Return(nil, errors.New("failed"))
+
+	_, err := Pull(context.TODO(), afero.NewMemMapFs(), c, "cache", false)
+	assert.ErrorContains(t, err, "failed to download schema \"builtin:a\"")
+}
+
+func TestPull_KeepsCacheConsistentOnErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	assert.NoError(t, writeIndex(fs, "cache", Index{"builtin:a": "1.0", "builtin:b": "1.0"}))
+	assert.NoError(t, afero.WriteFile(fs, "cache/builtin_a/1.0.json", []byte(`{"version": "1.0"}`), 0664))
+	assert.NoError(t, afero.WriteFile(fs, "cache/builtin_b/1.0.json", []byte(`{"version": "1.0"}`), 0664))
+
+	c := client.NewMockClient(gomock.NewController(t))
+	c.EXPECT().ListSchemas(gomock.Any()).Return(client.SchemaList{
+		{SchemaId: "builtin:a", LatestSchemaVersion: "2.0"},
+		{SchemaId: "builtin:b", LatestSchemaVersion: "2.0"},
+	}, nil)
+	c.EXPECT().GetSchema(gomock.Any(), "builtin:a").Return([]byte(`{"version": "2.0"}`), nil)
+	c.EXPECT().GetSchema(gomock.Any(), "builtin:b").Return(nil, errors.New("failed"))
+
+	_, err := Pull(context.TODO(), fs, c, "cache", false)
+	assert.Error(t, err)
+
+	for _, schemaId := range []string{"builtin:a", "builtin:b"} {
+		version, definition, err := Load(fs, "cache", schemaId)
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0", version)
+		assert.JSONEq(t, `{"version": "1.0"}`, string(definition))
+	}
+}
+
+func TestLoadIndex(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	index, err := LoadIndex(fs, "cache")
+	assert.NoError(t, err)
+	assert.Empty(t, index)
+
+	_ = afero.WriteFile(fs, "cache/index.json", []byte("{invalid"), 0644)
+	_, err = LoadIndex(fs, "cache")
+	assert.Error(t, err)
+}
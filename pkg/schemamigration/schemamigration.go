@@ -0,0 +1,123 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package schemamigration migrates settings objects between major versions of their Settings 2.0 schema.
+//
+// Migrations are defined in a YAML file, listing per schema the fields renamed between two major versions:
+//
+//	migrations:
+//	  - schemaId: builtin:alerting.profile
+//	    fromMajorVersion: 7
+//	    toMajorVersion: 8
+//	    renamedFields:
+//	      severityRules.delayInMinutes: delay
+//
+// Each key of renamedFields is the dot-separated path of a field in the old version, the value is its new name.
+// Lists on the path are traversed, so the field is renamed in every element.
+package schemamigration
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"strings"
+)
+
+// Migration defines the fields of settings objects of a schema renamed between two major versions.
+type Migration struct {
+	SchemaId      string            `yaml:"schemaId"`
+	FromMajor     int               `yaml:"fromMajorVersion"`
+	ToMajor       int               `yaml:"toMajorVersion"`
+	RenamedFields map[string]string `yaml:"renamedFields"`
+}
+
+// Table is a list of migrations.
+type Table []Migration
+
+// Load reads the migration table from the given file.
+func Load(fs afero.Fs, path string) (Table, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema migrations %q: %w", path, err)
+	}
+
+	var parsed struct {
+		Migrations Table `yaml:"migrations"`
+	}
+	if err := yaml.UnmarshalStrict(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse schema migrations %q: %w", path, err)
+	}
+
+	for i, m := range parsed.Migrations {
+		if m.SchemaId == "" {
+			return nil, fmt.Errorf("invalid schema migration %d in %q: `schemaId` is required", i, path)
+		}
+		if m.FromMajor == m.ToMajor {
+			return nil, fmt.Errorf("invalid schema migration of %q in %q: `fromMajorVersion` and `toMajorVersion` must differ", m.SchemaId, path)
+		}
+	}
+	return parsed.Migrations, nil
+}
+
+// Find returns the migration of the given schema between the given major versions.
+func (t Table) Find(schemaId string, fromMajor, toMajor int) (Migration, bool) {
+	for _, m := range t {
+		if m.SchemaId == schemaId && m.FromMajor == fromMajor && m.ToMajor == toMajor {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// Apply returns the given JSON settings object with all renamed fields migrated. Fields not present in the object are
+// ignored.
+func (m Migration) Apply(content string) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return "", fmt.Errorf("failed to parse settings object: %w", err)
+	}
+
+	for path, newName := range m.RenamedFields {
+		rename(value, strings.Split(path, "."), newName)
+	}
+
+	migrated, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(migrated), nil
+}
+
+func rename(value interface{}, path []string, newName string) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, e := range v {
+			rename(e, path, newName)
+		}
+	case map[string]interface{}:
+		field, found := v[path[0]]
+		if !found {
+			return
+		}
+		if len(path) > 1 {
+			rename(field, path[1:], newName)
+			return
+		}
+		delete(v, path[0])
+		v[newName] = field
+	}
+}
@@ -0,0 +1,93 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schemamigration
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "migrations.yaml", []byte(`
+migrations:
+  - schemaId: builtin:alerting.profile
+    fromMajorVersion: 7
+    toMajorVersion: 8
+    renamedFields:
+      severityRules.delayInMinutes: delay
+`), 0644)
+	_ = afero.WriteFile(fs, "invalid.yaml", []byte(`
+migrations:
+  - schemaId: builtin:alerting.profile
+    fromMajorVersion: 7
+    toMajorVersion: 7
+`), 0644)
+
+	t.Run("migrations are loaded", func(t *testing.T) {
+		table, err := Load(fs, "migrations.yaml")
+		assert.NoError(t, err)
+		assert.Equal(t, Table{{
+			SchemaId:      "builtin:alerting.profile",
+			FromMajor:     7,
+			ToMajor:       8,
+			RenamedFields: map[string]string{"severityRules.delayInMinutes": "delay"},
+		}}, table)
+
+		_, found := table.Find("builtin:alerting.profile", 7, 8)
+		assert.True(t, found)
+		_, found = table.Find("builtin:alerting.profile", 8, 7)
+		assert.False(t, found)
+	})
+
+	t.Run("invalid migrations fail", func(t *testing.T) {
+		_, err := Load(fs, "invalid.yaml")
+		assert.ErrorContains(t, err, "must differ")
+	})
+
+	t.Run("missing files fail", func(t *testing.T) {
+		_, err := Load(fs, "missing.yaml")
+		assert.Error(t, err)
+	})
+}
+
+func TestMigration_Apply(t *testing.T) {
+	m := Migration{RenamedFields: map[string]string{
+		"name":                         "displayName",
+		"severityRules.delayInMinutes": "delay",
+		"missing.field":                "ignored",
+	}}
+
+	got, err := m.Apply(`{
+  "name": "profile",
+  "severityRules": [
+    {"severityLevel": "AVAILABILITY", "delayInMinutes": 0},
+    {"severityLevel": "ERRORS", "delayInMinutes": 5}
+  ]
+}`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "displayName": "profile",
+  "severityRules": [
+    {"severityLevel": "AVAILABILITY", "delay": 0},
+    {"severityLevel": "ERRORS", "delay": 5}
+  ]
+}`, got)
+}
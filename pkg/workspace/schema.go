@@ -0,0 +1,27 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/jsonschema"
+	"reflect"
+)
+
+// JSONSchema returns the JSON Schema of workspace files.
+func JSONSchema() *jsonschema.Schema {
+	return jsonschema.Generate(reflect.TypeOf(workspaceDefinition{}), "monaco workspace file")
+}
@@ -0,0 +1,165 @@
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package workspace loads workspace files, which combine several manifests - e.g. one per business unit - so that
+// commands can operate on the projects of all of them at once.
+//
+// Project names only need to be unique within a manifest. Hence, projects of a workspace are scoped by the name of
+// their manifest: project 'infrastructure' of manifest 'payment' is referred to as 'payment/infrastructure'.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/files"
+	"github.com/dynatrace/dynatrace-configuration-as-code/internal/slices"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v2"
+	"path/filepath"
+	"strings"
+)
+
+// ScopeSeparator separates the name of a manifest from the name of one of its projects.
+const ScopeSeparator = "/"
+
+// Workspace holds the manifests of a workspace file.
+type Workspace struct {
+	// Manifests holds all manifests of the workspace in the order they are defined in
+	Manifests []Manifest
+}
+
+// Manifest is a manifest of a workspace.
+type Manifest struct {
+	// Name of the manifest, unique within the workspace and used to scope the names of its projects
+	Name string
+	// Path is the absolute path of the manifest file
+	Path string
+}
+
+// Scope returns the given name - e.g. of a project or config - scoped by the manifest's name.
+func (m Manifest) Scope(name string) string {
+	return m.Name + ScopeSeparator + name
+}
+
+type workspaceDefinition struct {
+	Manifests []manifestDefinition `yaml:"manifests" jsonschema:"required"`
+}
+
+type manifestDefinition struct {
+	// Name of the manifest. If not set, the name of the folder containing the manifest is used.
+	Name string `yaml:"name,omitempty"`
+	// Path of the manifest, relative to the workspace file
+	Path string `yaml:"path" jsonschema:"required"`
+}
+
+// Load loads the workspace file at the given path. Paths of manifests are resolved relative to the workspace file.
+func Load(fs afero.Fs, path string) (Workspace, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("error while finding absolute path for `%s`: %w", path, err)
+	}
+
+	data, err := afero.ReadFile(fs, absPath)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	var def workspaceDefinition
+	if err := yaml.UnmarshalStrict(data, &def); err != nil {
+		return Workspace{}, fmt.Errorf("failed to parse workspace file %q: %w", path, err)
+	}
+
+	if len(def.Manifests) == 0 {
+		return Workspace{}, fmt.Errorf("workspace file %q does not define any manifests", path)
+	}
+
+	var errs []error
+	names := make(map[string]struct{}, len(def.Manifests))
+	w := Workspace{Manifests: make([]Manifest, 0, len(def.Manifests))}
+	for i, d := range def.Manifests {
+		if !files.IsYamlFileExtension(d.Path) {
+			errs = append(errs, fmt.Errorf("manifest %d: expected the path of a .yaml file, but got %q", i, d.Path))
+			continue
+		}
+
+		m := Manifest{
+			Name: d.Name,
+			Path: filepath.Join(filepath.Dir(absPath), filepath.FromSlash(d.Path)),
+		}
+		if m.Name == "" {
+			m.Name = filepath.Base(filepath.Dir(m.Path))
+		}
+
+		if strings.Contains(m.Name, ScopeSeparator) {
+			errs = append(errs, fmt.Errorf("manifest %d: name %q must not contain %q", i, m.Name, ScopeSeparator))
+			continue
+		}
+		if _, found := names[m.Name]; found {
+			errs = append(errs, fmt.Errorf("manifest %d: name %q is not unique. Please define a unique name for each manifest", i, m.Name))
+			continue
+		}
+		names[m.Name] = struct{}{}
+
+		w.Manifests = append(w.Manifests, m)
+	}
+
+	if len(errs) > 0 {
+		return Workspace{}, fmt.Errorf("invalid workspace file %q: %w", path, errors.Join(errs...))
+	}
+	return w, nil
+}
+
+// SplitScoped splits a name scoped by Manifest.Scope into the name of the manifest and the unscoped name. False is
+// returned if the name is not scoped.
+func SplitScoped(scoped string) (manifest string, name string, ok bool) {
+	manifest, name, ok = strings.Cut(scoped, ScopeSeparator)
+	return manifest, name, ok && manifest != "" && name != ""
+}
+
+// Select returns the given names that apply to the manifest, e.g. the names of environments to filter on: names scoped
+// by the manifest are returned without the scope, names scoped by another manifest are left out, and unscoped names
+// apply to all manifests.
+//
+// False is returned if names were given, but none of them applies to the manifest - i.e. the manifest is not selected.
+func (m Manifest) Select(names []string) ([]string, bool) {
+	if len(names) == 0 {
+		return nil, true
+	}
+
+	var selected []string
+	for _, n := range names {
+		if manifest, name, ok := SplitScoped(n); !ok {
+			selected = append(selected, n)
+		} else if manifest == m.Name {
+			selected = append(selected, name)
+		}
+	}
+	return selected, len(selected) > 0
+}
+
+// CheckScopes returns an error if any of the given names is scoped by a manifest not part of the workspace.
+func (w Workspace) CheckScopes(names []string) error {
+	for _, n := range names {
+		manifest, _, ok := SplitScoped(n)
+		if !ok {
+			continue
+		}
+		if !slices.AnyMatches(w.Manifests, func(m Manifest) bool { return m.Name == manifest }) {
+			return fmt.Errorf("%q is scoped by unknown manifest %q", n, manifest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+//go:build unit
+
+/*
+ * @license
+ * Copyright 2023 Dynatrace LLC
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package workspace
+
+import (
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	abs, _ := filepath.Abs(".")
+	_ = afero.WriteFile(fs, filepath.Join(abs, "workspace.yaml"), []byte(`manifests:
+- name: payment
+  path: bu-payment/manifest.yaml
+- path: shipping/manifest.yaml
+`), 0644)
+
+	w, err := Load(fs, "workspace.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Manifest{
+		{Name: "payment", Path: filepath.Join(abs, "bu-payment", "manifest.yaml")},
+		{Name: "shipping", Path: filepath.Join(abs, "shipping", "manifest.yaml")},
+	}, w.Manifests)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "no manifests",
+			content: "manifests: []\n",
+			wantErr: "does not define any manifests",
+		},
+		{
+			name:    "unknown key",
+			content: "manifests:\n- path: a/manifest.yaml\n  environments: []\n",
+			wantErr: "failed to parse workspace file",
+		},
+		{
+			name:    "no YAML file",
+			content: "manifests:\n- path: a/manifest.json\n",
+			wantErr: `manifest 0: expected the path of a .yaml file, but got "a/manifest.json"`,
+		},
+		{
+			name:    "duplicate names",
+			content: "manifests:\n- path: a/manifest.yaml\n- path: b/a/manifest.yaml\n",
+			wantErr: `manifest 1: name "a" is not unique`,
+		},
+		{
+			name:    "name with separator",
+			content: "manifests:\n- name: a/b\n  path: a/manifest.yaml\n",
+			wantErr: `manifest 0: name "a/b" must not contain "/"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			path, _ := filepath.Abs("workspace.yaml")
+			_ = afero.WriteFile(fs, path, []byte(tt.content), 0644)
+
+			_, err := Load(fs, "workspace.yaml")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := Load(afero.NewMemMapFs(), "workspace.yaml")
+		assert.Error(t, err)
+	})
+}
+
+func TestSplitScoped(t *testing.T) {
+	m := Manifest{Name: "payment"}
+
+	manifest, name, ok := SplitScoped(m.Scope("infrastructure"))
+	assert.True(t, ok)
+	assert.Equal(t, "payment", manifest)
+	assert.Equal(t, "infrastructure", name)
+
+	_, _, ok = SplitScoped("infrastructure")
+	assert.False(t, ok)
+}
+
+func TestManifest_Select(t *testing.T) {
+	m := Manifest{Name: "payment"}
+
+	selected, ok := m.Select(nil)
+	assert.True(t, ok)
+	assert.Empty(t, selected)
+
+	selected, ok = m.Select([]string{"dev", "payment/prod", "shipping/test"})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"dev", "prod"}, selected)
+
+	_, ok = m.Select([]string{"shipping/test"})
+	assert.False(t, ok)
+}
+
+func TestWorkspace_CheckScopes(t *testing.T) {
+	w := Workspace{Manifests: []Manifest{{Name: "payment"}}}
+
+	assert.NoError(t, w.CheckScopes([]string{"dev", "payment/prod"}))
+	assert.EqualError(t, w.CheckScopes([]string{"shipping/prod"}), `"shipping/prod" is scoped by unknown manifest "shipping"`)
+}